@@ -49,7 +49,7 @@ func extractAccountIDFromARN(arn string) (string, error) {
 
 // NewStsClientE creates a new STS client.
 func NewStsClientE(t testing.TestingT, region string) (*sts.Client, error) {
-	sess, err := NewAuthenticatedSession(region)
+	sess, err := newAuthenticatedSession(t, region)
 	if err != nil {
 		return nil, err
 	}
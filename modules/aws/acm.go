@@ -49,7 +49,7 @@ func NewAcmClient(t testing.TestingT, region string) *acm.Client {
 
 // NewAcmClientE creates a new ACM client.
 func NewAcmClientE(t testing.TestingT, awsRegion string) (*acm.Client, error) {
-	sess, err := NewAuthenticatedSession(awsRegion)
+	sess, err := newAuthenticatedSession(t, awsRegion)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,526 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	apigatewaytypes "github.com/aws/aws-sdk-go-v2/service/apigateway/types"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	apigatewayv2types "github.com/aws/aws-sdk-go-v2/service/apigatewayv2/types"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// ApiGatewayApi is an API Gateway REST API (v1) or HTTP or WebSocket API (v2).
+type ApiGatewayApi struct {
+	Id           string // The ID of the API
+	Name         string // The name of the API
+	Description  string // The description of the API
+	ProtocolType string // REST for REST APIs, HTTP or WEBSOCKET for v2 APIs
+	Endpoint     string // The default endpoint of the API (e.g. https://abc123.execute-api.us-east-1.amazonaws.com)
+}
+
+// ApiGatewayStage is a stage of an API Gateway API.
+type ApiGatewayStage struct {
+	Name         string            // The name of the stage
+	DeploymentId string            // The ID of the deployment the stage points to
+	Variables    map[string]string // The stage variables
+	InvokeUrl    string            // The URL at which the stage is invoked through the default endpoint of the API
+}
+
+// ApiGatewayResource is a resource of an API Gateway REST API.
+type ApiGatewayResource struct {
+	Id      string   // The ID of the resource
+	Path    string   // The full path of the resource (e.g. /users/{id})
+	Methods []string // The HTTP methods of the resource (e.g. GET), sorted
+}
+
+// ApiGatewayRoute is a route of an API Gateway HTTP or WebSocket API.
+type ApiGatewayRoute struct {
+	Id                string // The ID of the route
+	RouteKey          string // The route key (e.g. GET /users/{id} or $default)
+	Target            string // The target of the route (e.g. integrations/abc123)
+	AuthorizationType string // The authorization type of the route (e.g. NONE, AWS_IAM or JWT)
+}
+
+// ApiGatewayIntegration is the integration of a method of a REST API or of a route of an HTTP or WebSocket API with its
+// backend (e.g. a Lambda function).
+type ApiGatewayIntegration struct {
+	Type   string // The type of the integration (e.g. AWS_PROXY)
+	Uri    string // The URI of the backend (e.g. the invocation ARN of a Lambda function)
+	Method string // The HTTP method used to call the backend
+}
+
+// ApiGatewayDomainMapping maps a base path of a custom domain name to a stage of an API.
+type ApiGatewayDomainMapping struct {
+	Id    string // The ID of the mapping
+	ApiId string // The ID of the API
+	Stage string // The name of the stage of the API
+	Key   string // The base path of the mapping (e.g. v1). Empty if the API is mapped to the root of the domain.
+}
+
+// GetApiGatewayRestApi fetches the API Gateway REST API with the given ID in the given region.
+func GetApiGatewayRestApi(t testing.TestingT, region string, apiID string) *ApiGatewayApi {
+	api, err := GetApiGatewayRestApiE(t, region, apiID)
+	require.NoError(t, err)
+	return api
+}
+
+// GetApiGatewayRestApiE fetches the API Gateway REST API with the given ID in the given region. Returns a NotFoundError
+// if the API does not exist.
+func GetApiGatewayRestApiE(t testing.TestingT, region string, apiID string) (*ApiGatewayApi, error) {
+	client, err := NewApiGatewayClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetRestApi(context.Background(), &apigateway.GetRestApiInput{RestApiId: aws.String(apiID)})
+	if err != nil {
+		return nil, notFoundOr[*apigatewaytypes.NotFoundException](err, "API Gateway REST API", apiID, region)
+	}
+
+	return &ApiGatewayApi{
+		Id:           aws.ToString(output.Id),
+		Name:         aws.ToString(output.Name),
+		Description:  aws.ToString(output.Description),
+		ProtocolType: "REST",
+		Endpoint:     formatRestApiEndpoint(client, apiID, region),
+	}, nil
+}
+
+// GetApiGatewayRestApiStage fetches the stage with the given name of the API Gateway REST API with the given ID in the
+// given region.
+func GetApiGatewayRestApiStage(t testing.TestingT, region string, apiID string, stageName string) *ApiGatewayStage {
+	stage, err := GetApiGatewayRestApiStageE(t, region, apiID, stageName)
+	require.NoError(t, err)
+	return stage
+}
+
+// GetApiGatewayRestApiStageE fetches the stage with the given name of the API Gateway REST API with the given ID in the
+// given region. Returns a NotFoundError if the API or the stage does not exist.
+func GetApiGatewayRestApiStageE(t testing.TestingT, region string, apiID string, stageName string) (*ApiGatewayStage, error) {
+	client, err := NewApiGatewayClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetStage(context.Background(), &apigateway.GetStageInput{
+		RestApiId: aws.String(apiID),
+		StageName: aws.String(stageName),
+	})
+	if err != nil {
+		return nil, notFoundOr[*apigatewaytypes.NotFoundException](err, "API Gateway REST API stage", apiID+"/"+stageName, region)
+	}
+
+	return newApiGatewayStage(aws.ToString(output.StageName), aws.ToString(output.DeploymentId), output.Variables, formatRestApiEndpoint(client, apiID, region)), nil
+}
+
+// GetApiGatewayRestApiResources fetches the resources, along with their methods, of the API Gateway REST API with the
+// given ID in the given region, sorted by path.
+func GetApiGatewayRestApiResources(t testing.TestingT, region string, apiID string) []ApiGatewayResource {
+	resources, err := GetApiGatewayRestApiResourcesE(t, region, apiID)
+	require.NoError(t, err)
+	return resources
+}
+
+// GetApiGatewayRestApiResourcesE fetches the resources, along with their methods, of the API Gateway REST API with the
+// given ID in the given region, sorted by path. Returns a NotFoundError if the API does not exist.
+func GetApiGatewayRestApiResourcesE(t testing.TestingT, region string, apiID string) ([]ApiGatewayResource, error) {
+	client, err := NewApiGatewayClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &apigateway.GetResourcesInput{RestApiId: aws.String(apiID)}
+	resources := []ApiGatewayResource{}
+	for {
+		output, err := client.GetResources(context.Background(), input)
+		if err != nil {
+			return nil, notFoundOr[*apigatewaytypes.NotFoundException](err, "API Gateway REST API", apiID, region)
+		}
+
+		for _, item := range output.Items {
+			resources = append(resources, newApiGatewayResource(item))
+		}
+
+		if output.Position == nil {
+			break
+		}
+		input.Position = output.Position
+	}
+
+	sort.Slice(resources, func(i, j int) bool { return resources[i].Path < resources[j].Path })
+	return resources, nil
+}
+
+// newApiGatewayResource converts the given resource returned by the API Gateway API to an ApiGatewayResource.
+func newApiGatewayResource(item apigatewaytypes.Resource) ApiGatewayResource {
+	resource := ApiGatewayResource{
+		Id:      aws.ToString(item.Id),
+		Path:    aws.ToString(item.Path),
+		Methods: []string{},
+	}
+	for method := range item.ResourceMethods {
+		resource.Methods = append(resource.Methods, method)
+	}
+	sort.Strings(resource.Methods)
+	return resource
+}
+
+// GetApiGatewayRestApiIntegration fetches the integration of the given HTTP method of the resource with the given ID of
+// the API Gateway REST API with the given ID in the given region.
+func GetApiGatewayRestApiIntegration(t testing.TestingT, region string, apiID string, resourceID string, httpMethod string) *ApiGatewayIntegration {
+	integration, err := GetApiGatewayRestApiIntegrationE(t, region, apiID, resourceID, httpMethod)
+	require.NoError(t, err)
+	return integration
+}
+
+// GetApiGatewayRestApiIntegrationE fetches the integration of the given HTTP method of the resource with the given ID
+// of the API Gateway REST API with the given ID in the given region. Returns a NotFoundError if the API, the resource or
+// the method does not exist, or if the method has no integration.
+func GetApiGatewayRestApiIntegrationE(t testing.TestingT, region string, apiID string, resourceID string, httpMethod string) (*ApiGatewayIntegration, error) {
+	client, err := NewApiGatewayClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetIntegration(context.Background(), &apigateway.GetIntegrationInput{
+		RestApiId:  aws.String(apiID),
+		ResourceId: aws.String(resourceID),
+		HttpMethod: aws.String(httpMethod),
+	})
+	if err != nil {
+		return nil, notFoundOr[*apigatewaytypes.NotFoundException](err, "API Gateway REST API integration", fmt.Sprintf("%s/%s/%s", apiID, resourceID, httpMethod), region)
+	}
+
+	return &ApiGatewayIntegration{
+		Type:   string(output.Type),
+		Uri:    aws.ToString(output.Uri),
+		Method: aws.ToString(output.HttpMethod),
+	}, nil
+}
+
+// GetApiGatewayHttpApi fetches the API Gateway HTTP or WebSocket API with the given ID in the given region.
+func GetApiGatewayHttpApi(t testing.TestingT, region string, apiID string) *ApiGatewayApi {
+	api, err := GetApiGatewayHttpApiE(t, region, apiID)
+	require.NoError(t, err)
+	return api
+}
+
+// GetApiGatewayHttpApiE fetches the API Gateway HTTP or WebSocket API with the given ID in the given region. Returns a
+// NotFoundError if the API does not exist.
+func GetApiGatewayHttpApiE(t testing.TestingT, region string, apiID string) (*ApiGatewayApi, error) {
+	client, err := NewApiGatewayV2ClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetApi(context.Background(), &apigatewayv2.GetApiInput{ApiId: aws.String(apiID)})
+	if err != nil {
+		return nil, notFoundOr[*apigatewayv2types.NotFoundException](err, "API Gateway HTTP API", apiID, region)
+	}
+
+	return &ApiGatewayApi{
+		Id:           aws.ToString(output.ApiId),
+		Name:         aws.ToString(output.Name),
+		Description:  aws.ToString(output.Description),
+		ProtocolType: string(output.ProtocolType),
+		Endpoint:     aws.ToString(output.ApiEndpoint),
+	}, nil
+}
+
+// GetApiGatewayHttpApiStage fetches the stage with the given name of the API Gateway HTTP or WebSocket API with the
+// given ID in the given region.
+func GetApiGatewayHttpApiStage(t testing.TestingT, region string, apiID string, stageName string) *ApiGatewayStage {
+	stage, err := GetApiGatewayHttpApiStageE(t, region, apiID, stageName)
+	require.NoError(t, err)
+	return stage
+}
+
+// GetApiGatewayHttpApiStageE fetches the stage with the given name of the API Gateway HTTP or WebSocket API with the
+// given ID in the given region. Returns a NotFoundError if the API or the stage does not exist.
+func GetApiGatewayHttpApiStageE(t testing.TestingT, region string, apiID string, stageName string) (*ApiGatewayStage, error) {
+	api, err := GetApiGatewayHttpApiE(t, region, apiID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewApiGatewayV2ClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetStage(context.Background(), &apigatewayv2.GetStageInput{
+		ApiId:     aws.String(apiID),
+		StageName: aws.String(stageName),
+	})
+	if err != nil {
+		return nil, notFoundOr[*apigatewayv2types.NotFoundException](err, "API Gateway HTTP API stage", apiID+"/"+stageName, region)
+	}
+
+	return newApiGatewayStage(aws.ToString(output.StageName), aws.ToString(output.DeploymentId), output.StageVariables, api.Endpoint), nil
+}
+
+// newApiGatewayStage returns an ApiGatewayStage with the given name, deployment and variables of an API with the given
+// default endpoint.
+func newApiGatewayStage(name string, deploymentID string, variables map[string]string, endpoint string) *ApiGatewayStage {
+	stage := &ApiGatewayStage{
+		Name:         name,
+		DeploymentId: deploymentID,
+		Variables:    map[string]string{},
+		InvokeUrl:    endpoint + "/" + name,
+	}
+	// The $default stage of HTTP APIs is invoked at the root of the endpoint
+	if name == "$default" {
+		stage.InvokeUrl = endpoint
+	}
+	if variables != nil {
+		stage.Variables = variables
+	}
+	return stage
+}
+
+// GetApiGatewayHttpApiRoutes fetches the routes of the API Gateway HTTP or WebSocket API with the given ID in the given
+// region, sorted by route key.
+func GetApiGatewayHttpApiRoutes(t testing.TestingT, region string, apiID string) []ApiGatewayRoute {
+	routes, err := GetApiGatewayHttpApiRoutesE(t, region, apiID)
+	require.NoError(t, err)
+	return routes
+}
+
+// GetApiGatewayHttpApiRoutesE fetches the routes of the API Gateway HTTP or WebSocket API with the given ID in the given
+// region, sorted by route key. Returns a NotFoundError if the API does not exist.
+func GetApiGatewayHttpApiRoutesE(t testing.TestingT, region string, apiID string) ([]ApiGatewayRoute, error) {
+	client, err := NewApiGatewayV2ClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &apigatewayv2.GetRoutesInput{ApiId: aws.String(apiID)}
+	routes := []ApiGatewayRoute{}
+	for {
+		output, err := client.GetRoutes(context.Background(), input)
+		if err != nil {
+			return nil, notFoundOr[*apigatewayv2types.NotFoundException](err, "API Gateway HTTP API", apiID, region)
+		}
+
+		for _, route := range output.Items {
+			routes = append(routes, ApiGatewayRoute{
+				Id:                aws.ToString(route.RouteId),
+				RouteKey:          aws.ToString(route.RouteKey),
+				Target:            aws.ToString(route.Target),
+				AuthorizationType: string(route.AuthorizationType),
+			})
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	sort.Slice(routes, func(i, j int) bool { return routes[i].RouteKey < routes[j].RouteKey })
+	return routes, nil
+}
+
+// GetApiGatewayHttpApiIntegration fetches the integration with the given ID of the API Gateway HTTP or WebSocket API
+// with the given ID in the given region. The ID of the integration of a route is the part of its Target after
+// integrations/.
+func GetApiGatewayHttpApiIntegration(t testing.TestingT, region string, apiID string, integrationID string) *ApiGatewayIntegration {
+	integration, err := GetApiGatewayHttpApiIntegrationE(t, region, apiID, integrationID)
+	require.NoError(t, err)
+	return integration
+}
+
+// GetApiGatewayHttpApiIntegrationE fetches the integration with the given ID of the API Gateway HTTP or WebSocket API
+// with the given ID in the given region. The ID of the integration of a route is the part of its Target after
+// integrations/. Returns a NotFoundError if the API or the integration does not exist.
+func GetApiGatewayHttpApiIntegrationE(t testing.TestingT, region string, apiID string, integrationID string) (*ApiGatewayIntegration, error) {
+	client, err := NewApiGatewayV2ClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetIntegration(context.Background(), &apigatewayv2.GetIntegrationInput{
+		ApiId:         aws.String(apiID),
+		IntegrationId: aws.String(integrationID),
+	})
+	if err != nil {
+		return nil, notFoundOr[*apigatewayv2types.NotFoundException](err, "API Gateway HTTP API integration", apiID+"/"+integrationID, region)
+	}
+
+	return &ApiGatewayIntegration{
+		Type:   string(output.IntegrationType),
+		Uri:    aws.ToString(output.IntegrationUri),
+		Method: aws.ToString(output.IntegrationMethod),
+	}, nil
+}
+
+// GetApiGatewayDomainMappings fetches the mappings of the API Gateway custom domain name with the given name in the
+// given region to the stages of REST, HTTP and WebSocket APIs.
+func GetApiGatewayDomainMappings(t testing.TestingT, region string, domainName string) []ApiGatewayDomainMapping {
+	mappings, err := GetApiGatewayDomainMappingsE(t, region, domainName)
+	require.NoError(t, err)
+	return mappings
+}
+
+// GetApiGatewayDomainMappingsE fetches the mappings of the API Gateway custom domain name with the given name in the
+// given region to the stages of REST, HTTP and WebSocket APIs. Returns a NotFoundError if the domain name does not
+// exist.
+func GetApiGatewayDomainMappingsE(t testing.TestingT, region string, domainName string) ([]ApiGatewayDomainMapping, error) {
+	client, err := NewApiGatewayV2ClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &apigatewayv2.GetApiMappingsInput{DomainName: aws.String(domainName)}
+	mappings := []ApiGatewayDomainMapping{}
+	for {
+		output, err := client.GetApiMappings(context.Background(), input)
+		if err != nil {
+			return nil, notFoundOr[*apigatewayv2types.NotFoundException](err, "API Gateway domain name", domainName, region)
+		}
+
+		for _, mapping := range output.Items {
+			mappings = append(mappings, ApiGatewayDomainMapping{
+				Id:    aws.ToString(mapping.ApiMappingId),
+				ApiId: aws.ToString(mapping.ApiId),
+				Stage: aws.ToString(mapping.Stage),
+				Key:   aws.ToString(mapping.ApiMappingKey),
+			})
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	return mappings, nil
+}
+
+// InvokeApiGatewayEndpointWithRetry sends a request with the given method and body to the given URL of an API Gateway
+// API in the given region, signed with SigV4 with the current AWS credentials so that APIs that use IAM authorization
+// can be invoked, until the response has the expected status code or the given number of retries is exhausted (e.g.
+// while a new deployment propagates), and returns the body of the response. This will fail the test if the response
+// never has the expected status code.
+func InvokeApiGatewayEndpointWithRetry(t testing.TestingT, region string, method string, url string, body []byte, expectedStatus int, retries int, sleepBetweenRetries time.Duration) string {
+	responseBody, err := InvokeApiGatewayEndpointWithRetryE(t, region, method, url, body, expectedStatus, retries, sleepBetweenRetries)
+	require.NoError(t, err)
+	return responseBody
+}
+
+// InvokeApiGatewayEndpointWithRetryE sends a request with the given method and body to the given URL of an API Gateway
+// API in the given region, signed with SigV4 with the current AWS credentials so that APIs that use IAM authorization
+// can be invoked, until the response has the expected status code or the given number of retries is exhausted (e.g.
+// while a new deployment propagates), and returns the body of the response.
+func InvokeApiGatewayEndpointWithRetryE(t testing.TestingT, region string, method string, url string, body []byte, expectedStatus int, retries int, sleepBetweenRetries time.Duration) (string, error) {
+	sess, err := newAuthenticatedSession(t, region)
+	if err != nil {
+		return "", err
+	}
+
+	description := fmt.Sprintf("%s %s", method, url)
+	return retry.DoWithRetryE(t, description, retries, sleepBetweenRetries, func() (string, error) {
+		credentials, err := sess.Credentials.Retrieve(context.Background())
+		if err != nil {
+			return "", retry.FatalError{Underlying: err}
+		}
+
+		statusCode, responseBody, err := invokeSignedApiGatewayRequest(credentials, region, method, url, body)
+		if err != nil {
+			return "", err
+		}
+		if statusCode != expectedStatus {
+			return "", fmt.Errorf("expected status code %d from %s, but got %d: %s", expectedStatus, url, statusCode, responseBody)
+		}
+		return responseBody, nil
+	})
+}
+
+// invokeSignedApiGatewayRequest sends a request with the given method and body to the given URL of an API Gateway API
+// in the given region, signed with SigV4 with the given credentials, and returns the status code and body of the
+// response.
+func invokeSignedApiGatewayRequest(credentials aws.Credentials, region string, method string, url string, body []byte) (int, string, error) {
+	request, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+
+	payloadHash := sha256.Sum256(body)
+	signer := v4.NewSigner()
+	if err := signer.SignHTTP(context.Background(), credentials, request, hex.EncodeToString(payloadHash[:]), "execute-api", region, time.Now()); err != nil {
+		return 0, "", err
+	}
+
+	client := http.Client{
+		// By default, Go does not impose a timeout, so an HTTP connection attempt can hang for a LONG time.
+		Timeout: 30 * time.Second,
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, "", err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return 0, "", err
+	}
+	return response.StatusCode, string(responseBody), nil
+}
+
+// formatRestApiEndpoint returns the default endpoint of the REST API with the given ID in the given region. If the
+// given client sends its requests to a custom endpoint (see SetGlobalEndpoint), such as LocalStack, the REST API is
+// invoked through that endpoint as well, at the /_aws/execute-api/<apiID> path that LocalStack serves it at.
+func formatRestApiEndpoint(client *apigateway.Client, apiID string, region string) string {
+	if baseEndpoint := aws.ToString(client.Options().BaseEndpoint); baseEndpoint != "" {
+		return fmt.Sprintf("%s/_aws/execute-api/%s", strings.TrimSuffix(baseEndpoint, "/"), apiID)
+	}
+	return fmt.Sprintf("https://%s.execute-api.%s.amazonaws.com", apiID, region)
+}
+
+// NewApiGatewayClient creates a new API Gateway client, for REST APIs.
+func NewApiGatewayClient(t testing.TestingT, region string) *apigateway.Client {
+	client, err := NewApiGatewayClientE(t, region)
+	require.NoError(t, err)
+	return client
+}
+
+// NewApiGatewayClientE creates a new API Gateway client, for REST APIs.
+func NewApiGatewayClientE(t testing.TestingT, region string) (*apigateway.Client, error) {
+	sess, err := newAuthenticatedSession(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return apigateway.NewFromConfig(*sess), nil
+}
+
+// NewApiGatewayV2Client creates a new API Gateway V2 client, for HTTP and WebSocket APIs.
+func NewApiGatewayV2Client(t testing.TestingT, region string) *apigatewayv2.Client {
+	client, err := NewApiGatewayV2ClientE(t, region)
+	require.NoError(t, err)
+	return client
+}
+
+// NewApiGatewayV2ClientE creates a new API Gateway V2 client, for HTTP and WebSocket APIs.
+func NewApiGatewayV2ClientE(t testing.TestingT, region string) (*apigatewayv2.Client, error) {
+	sess, err := newAuthenticatedSession(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return apigatewayv2.NewFromConfig(*sess), nil
+}
@@ -0,0 +1,66 @@
+package aws
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	apigatewaytypes "github.com/aws/aws-sdk-go-v2/service/apigateway/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewApiGatewayResource(t *testing.T) {
+	t.Parallel()
+
+	resource := newApiGatewayResource(apigatewaytypes.Resource{
+		Id:              aws.String("abc123"),
+		Path:            aws.String("/users/{id}"),
+		ResourceMethods: map[string]apigatewaytypes.Method{"PUT": {}, "GET": {}},
+	})
+	assert.Equal(t, ApiGatewayResource{Id: "abc123", Path: "/users/{id}", Methods: []string{"GET", "PUT"}}, resource)
+}
+
+func TestNewApiGatewayStage(t *testing.T) {
+	t.Parallel()
+
+	endpoint := "https://abc123.execute-api.us-east-1.amazonaws.com"
+	assert.Equal(t, endpoint+"/prod", newApiGatewayStage("prod", "dep123", nil, endpoint).InvokeUrl)
+	assert.Equal(t, endpoint, newApiGatewayStage("$default", "dep123", nil, endpoint).InvokeUrl)
+	assert.Equal(t, map[string]string{}, newApiGatewayStage("prod", "dep123", nil, endpoint).Variables)
+}
+
+func TestFormatRestApiEndpoint(t *testing.T) {
+	t.Parallel()
+
+	client := apigateway.New(apigateway.Options{Region: "us-east-1"})
+	assert.Equal(t, "https://abc123.execute-api.us-east-1.amazonaws.com", formatRestApiEndpoint(client, "abc123", "us-east-1"))
+
+	client = apigateway.New(apigateway.Options{Region: "us-east-1", BaseEndpoint: aws.String("http://localhost:4566/")})
+	assert.Equal(t, "http://localhost:4566/_aws/execute-api/abc123", formatRestApiEndpoint(client, "abc123", "us-east-1"))
+}
+
+func TestInvokeSignedApiGatewayRequest(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		authorization := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") || !strings.Contains(authorization, "/us-east-1/execute-api/aws4_request") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write(append([]byte("echo: "), body...))
+	}))
+	defer server.Close()
+
+	credentials := aws.Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"}
+	statusCode, body, err := invokeSignedApiGatewayRequest(credentials, "us-east-1", http.MethodPost, server.URL+"/prod/users", []byte("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "echo: hello", body)
+}
@@ -136,7 +136,7 @@ func NewAsgClient(t testing.TestingT, region string) *autoscaling.Client {
 
 // NewAsgClientE creates an Auto Scaling Group client.
 func NewAsgClientE(t testing.TestingT, region string) (*autoscaling.Client, error) {
-	sess, err := NewAuthenticatedSession(region)
+	sess, err := newAuthenticatedSession(t, region)
 	if err != nil {
 		return nil, err
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -13,13 +14,221 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	"github.com/aws/aws-sdk-go-v2/service/iam/types"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/require"
 )
 
 const (
 	AuthAssumeRoleEnvVar = "TERRATEST_IAM_ROLE" // OS environment variable name through which Assume Role ARN may be passed for authentication
 )
 
+// AuthOptions configures the credentials of the AWS Config used by the helpers of this package, for tests that check
+// resources in more than one account or that should not use the default credential chain.
+type AuthOptions struct {
+	Region          string        // The region of the config. The helpers that take a region override it.
+	Profile         string        // The named profile of the shared config and credentials files to use. Empty to use the default credential chain.
+	RoleArn         string        // The ARN of an IAM role to assume. Empty to assume the role in AuthAssumeRoleEnvVar, if set.
+	ExternalID      string        // The external ID that the trust policy of the role requires, if any
+	SessionName     string        // The name of the role session. Empty to let the SDK generate one.
+	SessionDuration time.Duration // The duration of the role session. Zero for the default of 15 minutes.
+	Endpoint        string        // The URL to send requests to, such as http://localhost:4566 for LocalStack. Empty for the global endpoint, if any.
+}
+
+// assumedRoleCredentialsKey identifies the assumed role credentials of an AuthOptions, which are the same whatever the
+// region of the config.
+type assumedRoleCredentialsKey struct {
+	profile         string
+	roleArn         string
+	externalID      string
+	sessionName     string
+	sessionDuration time.Duration
+	endpoint        string
+}
+
+// assumedRoleCredentialsEntry is a cache entry of the credentials of an assumed role.
+type assumedRoleCredentialsEntry struct {
+	credentials *aws.CredentialsCache
+	expires     time.Time // When the role session that was assumed for the entry expires. Zero if it doesn't.
+}
+
+var (
+	assumedRoleCredentialsMutex sync.Mutex
+	assumedRoleCredentials      = map[assumedRoleCredentialsKey]assumedRoleCredentialsEntry{}
+)
+
+// authOptionsT is a TestingT that carries the AuthOptions that the helpers of this package use to authenticate.
+type authOptionsT struct {
+	testing.TestingT
+	options AuthOptions
+}
+
+// WithAuthOptions returns a TestingT that wraps the given one so that the helpers of this package that it is passed to
+// authenticate with the given options rather than with the default credential chain. For example, to check a bucket in
+// another account:
+//
+//	prodT := aws.WithAuthOptions(t, aws.AuthOptions{RoleArn: "arn:aws:iam::111111111111:role/terratest"})
+//	aws.AssertS3BucketExists(prodT, region, bucketName)
+//
+// The client constructors, such as NewS3Client, honor the options as well, so the clients they return can be used
+// directly for anything the helpers do not cover.
+func WithAuthOptions(t testing.TestingT, options AuthOptions) testing.TestingT {
+	if wrapped, ok := t.(authOptionsT); ok {
+		t = wrapped.TestingT
+	}
+	return authOptionsT{TestingT: t, options: options}
+}
+
+// Helper marks the calling function as a test helper if the wrapped TestingT supports it, as *testing.T does, so that
+// assertion libraries such as testify report the line of the test rather than their own.
+func (t authOptionsT) Helper() {
+	if helper, ok := t.TestingT.(interface{ Helper() }); ok {
+		helper.Helper()
+	}
+}
+
+// newAuthenticatedSession creates an AWS Config in the given region with the AuthOptions of the given TestingT if it
+// was returned by WithAuthOptions, or following the standard AWS authentication workflow otherwise.
+func newAuthenticatedSession(t testing.TestingT, region string) (*aws.Config, error) {
+	wrapped, ok := t.(authOptionsT)
+	if !ok {
+		return NewAuthenticatedSession(region)
+	}
+
+	options := wrapped.options
+	if region != "" {
+		options.Region = region
+	}
+	return NewAuthenticatedSessionWithOptions(options)
+}
+
+// NewAuthenticatedSessionWithOptions creates an AWS Config with the credentials of the named profile of the given
+// options, or of the default credential chain if there is none, and assumes the role of the options, if any. The
+// credentials of the role are cached for the process, so that the configs created with the same options (e.g. for each
+// helper call) share them instead of each assuming the role again, and they are refreshed automatically before they
+// expire.
+func NewAuthenticatedSessionWithOptions(options AuthOptions) (*aws.Config, error) {
+	loadOptions := []func(*config.LoadOptions) error{config.WithRegion(options.Region)}
+	if options.Profile != "" {
+		loadOptions = append(loadOptions, config.WithSharedConfigProfile(options.Profile))
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background(), loadOptions...)
+	if err != nil {
+		return nil, CredentialsError{UnderlyingErr: err}
+	}
+	if options.Endpoint != "" {
+		cfg.BaseEndpoint = aws.String(options.Endpoint)
+	} else {
+		applyGlobalEndpoint(&cfg)
+	}
+
+	roleArn := options.RoleArn
+	if roleArn == "" {
+		roleArn = os.Getenv(AuthAssumeRoleEnvVar)
+	}
+	if roleArn != "" {
+		roleCredentials, err := getAssumedRoleCredentials(cfg, options, roleArn)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Credentials = roleCredentials
+	}
+
+	applyGlobalRateLimit(&cfg)
+	return &cfg, nil
+}
+
+// getAssumedRoleCredentials returns the cached credentials of the given role for the given options, assuming the role
+// with the credentials of the given config if they are not cached yet or if the role session they were cached for has
+// expired. The lock of the cache is not held while the role is assumed, so that a slow STS call doesn't block the other
+// tests.
+func getAssumedRoleCredentials(cfg aws.Config, options AuthOptions, roleArn string) (*aws.CredentialsCache, error) {
+	key := assumedRoleCredentialsKey{
+		profile:         options.Profile,
+		roleArn:         roleArn,
+		externalID:      options.ExternalID,
+		sessionName:     options.SessionName,
+		sessionDuration: options.SessionDuration,
+		endpoint:        aws.ToString(cfg.BaseEndpoint),
+	}
+
+	if roleCredentials, ok := getCachedAssumedRoleCredentials(key); ok {
+		return roleCredentials, nil
+	}
+
+	roleProvider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), roleArn, func(assumeRoleOptions *stscreds.AssumeRoleOptions) {
+		if options.ExternalID != "" {
+			assumeRoleOptions.ExternalID = aws.String(options.ExternalID)
+		}
+		if options.SessionName != "" {
+			assumeRoleOptions.RoleSessionName = options.SessionName
+		}
+		if options.SessionDuration > 0 {
+			assumeRoleOptions.Duration = options.SessionDuration
+		}
+	})
+	roleCredentials := aws.NewCredentialsCache(roleProvider)
+
+	// Assume the role right away, so that a role that can't be assumed fails here rather than on the first request
+	credentials, err := roleCredentials.Retrieve(context.Background())
+	if err != nil {
+		return nil, CredentialsError{UnderlyingErr: err}
+	}
+
+	entry := assumedRoleCredentialsEntry{credentials: roleCredentials}
+	if credentials.CanExpire {
+		entry.expires = credentials.Expires
+	}
+
+	assumedRoleCredentialsMutex.Lock()
+	defer assumedRoleCredentialsMutex.Unlock()
+	assumedRoleCredentials[key] = entry
+	return roleCredentials, nil
+}
+
+// getCachedAssumedRoleCredentials returns the cached credentials for the given key, if there are any, after evicting
+// the entries of the cache whose role session has expired.
+func getCachedAssumedRoleCredentials(key assumedRoleCredentialsKey) (*aws.CredentialsCache, bool) {
+	assumedRoleCredentialsMutex.Lock()
+	defer assumedRoleCredentialsMutex.Unlock()
+
+	now := time.Now()
+	for cachedKey, entry := range assumedRoleCredentials {
+		if !entry.expires.IsZero() && !now.Before(entry.expires) {
+			delete(assumedRoleCredentials, cachedKey)
+		}
+	}
+
+	entry, ok := assumedRoleCredentials[key]
+	return entry.credentials, ok
+}
+
+// NewClientWithAuthOptions creates a client of any AWS service that authenticates with the given options, by passing
+// the config of NewAuthenticatedSessionWithOptions in the given region to the NewFromConfig function of the service.
+// This is an alternative to passing the TestingT returned by WithAuthOptions to a client constructor, e.g.:
+//
+//	prodS3Client := aws.NewClientWithAuthOptions(t, prodOptions, region, s3.NewFromConfig)
+func NewClientWithAuthOptions[Client any, ClientOptions any](t testing.TestingT, options AuthOptions, region string, newFromConfig func(aws.Config, ...func(*ClientOptions)) Client) Client {
+	client, err := NewClientWithAuthOptionsE(t, options, region, newFromConfig)
+	require.NoError(t, err)
+	return client
+}
+
+// NewClientWithAuthOptionsE creates a client of any AWS service that authenticates with the given options, by passing
+// the config of NewAuthenticatedSessionWithOptions in the given region to the NewFromConfig function of the service.
+func NewClientWithAuthOptionsE[Client any, ClientOptions any](t testing.TestingT, options AuthOptions, region string, newFromConfig func(aws.Config, ...func(*ClientOptions)) Client) (Client, error) {
+	if region != "" {
+		options.Region = region
+	}
+	cfg, err := NewAuthenticatedSessionWithOptions(options)
+	if err != nil {
+		var client Client
+		return client, err
+	}
+	return newFromConfig(*cfg), nil
+}
+
 // NewAuthenticatedSession creates an AWS Config following to standard AWS authentication workflow.
 // If AuthAssumeIamRoleEnvVar environment variable is set, assumes IAM role specified in it.
 func NewAuthenticatedSession(region string) (*aws.Config, error) {
@@ -37,6 +246,8 @@ func NewAuthenticatedSessionFromDefaultCredentials(region string) (*aws.Config,
 		return nil, CredentialsError{UnderlyingErr: err}
 	}
 
+	applyGlobalEndpoint(&cfg)
+	applyGlobalRateLimit(&cfg)
 	return &cfg, nil
 }
 
@@ -57,21 +268,27 @@ func NewAuthenticatedSessionFromRole(region string, roleARN string) (*aws.Config
 		return nil, CredentialsError{UnderlyingErr: err}
 	}
 
-	return &aws.Config{
+	roleCfg := &aws.Config{
 		Region: region,
 		Credentials: aws.NewCredentialsCache(credentials.StaticCredentialsProvider{
 			Value: retrieve,
 		}),
-	}, nil
+	}
+	applyGlobalEndpoint(roleCfg)
+	applyGlobalRateLimit(roleCfg)
+	return roleCfg, nil
 }
 
 // CreateAwsSessionWithCreds creates a new AWS Config using explicit credentials. This is useful if you want to create an IAM User dynamically and
 // create an AWS Config authenticated as the new IAM User.
 func CreateAwsSessionWithCreds(region string, accessKeyID string, secretAccessKey string) (*aws.Config, error) {
-	return &aws.Config{
+	cfg := &aws.Config{
 		Region:      region,
 		Credentials: aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, "")),
-	}, nil
+	}
+	applyGlobalEndpoint(cfg)
+	applyGlobalRateLimit(cfg)
+	return cfg, nil
 }
 
 // CreateAwsSessionWithMfa creates a new AWS Config authenticated using an MFA token retrieved using the given STS client and MFA Device.
@@ -93,10 +310,13 @@ func CreateAwsSessionWithMfa(region string, stsClient *sts.Client, mfaDevice *ty
 	secretAccessKey := *output.Credentials.SecretAccessKey
 	sessionToken := *output.Credentials.SessionToken
 
-	return &aws.Config{
+	cfg := &aws.Config{
 		Region:      region,
 		Credentials: aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, sessionToken)),
-	}, nil
+	}
+	applyGlobalEndpoint(cfg)
+	applyGlobalRateLimit(cfg)
+	return cfg, nil
 }
 
 // GetTimeBasedOneTimePassword gets a One-Time Password from the given mfaDevice. Per the RFC 6238 standard, this value will be different every 30 seconds.
@@ -0,0 +1,131 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestStsServer starts a fake STS endpoint that answers every request as a successful AssumeRole call, and returns
+// its URL along with the number of requests it received.
+func newTestStsServer(t *testing.T) (string, *int32) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprint(w, `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>ASIAASSUMEDROLE</AccessKeyId>
+      <SecretAccessKey>assumed-secret</SecretAccessKey>
+      <SessionToken>assumed-token</SessionToken>
+      <Expiration>2100-01-01T00:00:00Z</Expiration>
+    </Credentials>
+    <AssumedRoleUser>
+      <Arn>arn:aws:sts::111111111111:assumed-role/terratest/session</Arn>
+      <AssumedRoleId>AROAEXAMPLE:session</AssumedRoleId>
+    </AssumedRoleUser>
+  </AssumeRoleResult>
+  <ResponseMetadata><RequestId>test</RequestId></ResponseMetadata>
+</AssumeRoleResponse>`)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, &requests
+}
+
+func TestWithAuthOptions(t *testing.T) {
+	t.Parallel()
+
+	wrapped := WithAuthOptions(t, AuthOptions{Profile: "dev"})
+	rewrapped := WithAuthOptions(wrapped, AuthOptions{Profile: "prod"})
+
+	require.IsType(t, authOptionsT{}, rewrapped)
+	assert.Equal(t, AuthOptions{Profile: "prod"}, rewrapped.(authOptionsT).options)
+	assert.Equal(t, t, rewrapped.(authOptionsT).TestingT)
+	assert.Equal(t, t.Name(), rewrapped.Name())
+	assert.Implements(t, (*interface{ Helper() })(nil), rewrapped)
+}
+
+func TestNewAuthenticatedSessionUsesRegionOfHelper(t *testing.T) {
+	t.Setenv(AuthAssumeRoleEnvVar, "")
+
+	cfg, err := newAuthenticatedSession(WithAuthOptions(t, AuthOptions{Region: "us-east-1"}), "eu-west-1")
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-1", cfg.Region)
+
+	cfg, err = newAuthenticatedSession(WithAuthOptions(t, AuthOptions{Region: "us-east-1"}), "")
+	require.NoError(t, err)
+	assert.Equal(t, "us-east-1", cfg.Region)
+}
+
+func TestNewAuthenticatedSessionWithOptionsUnknownProfile(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewAuthenticatedSessionWithOptions(AuthOptions{Region: "us-east-1", Profile: "terratest-profile-that-does-not-exist"})
+	assert.IsType(t, CredentialsError{}, err)
+}
+
+// This test sets the credentials in the environment, so it can't run in parallel with other tests that create configs.
+func TestNewAuthenticatedSessionWithOptionsCachesRoleCredentials(t *testing.T) {
+	t.Setenv(AuthAssumeRoleEnvVar, "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIAEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	endpoint, requests := newTestStsServer(t)
+	options := AuthOptions{Region: "us-east-1", RoleArn: "arn:aws:iam::111111111111:role/terratest", Endpoint: endpoint}
+
+	first, err := NewAuthenticatedSessionWithOptions(options)
+	require.NoError(t, err)
+	second, err := newAuthenticatedSession(WithAuthOptions(t, options), "eu-west-1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+	assert.Same(t, first.Credentials, second.Credentials)
+
+	credentials, err := second.Credentials.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ASIAASSUMEDROLE", credentials.AccessKeyID)
+
+	// Other options assume the role again, with their own session.
+	options.SessionName = "other-session"
+	_, err = NewAuthenticatedSessionWithOptions(options)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(requests))
+}
+
+func TestGetCachedAssumedRoleCredentialsEvictsExpiredEntries(t *testing.T) {
+	t.Parallel()
+
+	expiredKey := assumedRoleCredentialsKey{roleArn: "arn:aws:iam::111111111111:role/expired-" + t.Name()}
+	validKey := assumedRoleCredentialsKey{roleArn: "arn:aws:iam::111111111111:role/valid-" + t.Name()}
+	assumedRoleCredentialsMutex.Lock()
+	assumedRoleCredentials[expiredKey] = assumedRoleCredentialsEntry{credentials: &aws.CredentialsCache{}, expires: time.Now().Add(-time.Minute)}
+	assumedRoleCredentials[validKey] = assumedRoleCredentialsEntry{credentials: &aws.CredentialsCache{}, expires: time.Now().Add(time.Hour)}
+	assumedRoleCredentialsMutex.Unlock()
+
+	_, found := getCachedAssumedRoleCredentials(expiredKey)
+	assert.False(t, found)
+	_, found = getCachedAssumedRoleCredentials(validKey)
+	assert.True(t, found)
+
+	assumedRoleCredentialsMutex.Lock()
+	defer assumedRoleCredentialsMutex.Unlock()
+	assert.NotContains(t, assumedRoleCredentials, expiredKey)
+	delete(assumedRoleCredentials, validKey)
+}
+
+func TestNewClientWithAuthOptions(t *testing.T) {
+	t.Parallel()
+
+	client := NewClientWithAuthOptions(t, AuthOptions{Region: "us-east-1", Endpoint: "http://localhost:4566"}, "eu-west-1", s3.NewFromConfig)
+	assert.Equal(t, "eu-west-1", client.Options().Region)
+	assert.Equal(t, "http://localhost:4566", *client.Options().BaseEndpoint)
+}
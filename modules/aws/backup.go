@@ -0,0 +1,178 @@
+package aws
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	"github.com/aws/aws-sdk-go-v2/service/backup/types"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// BackupRecoveryPoint is a backup of a resource stored in an AWS Backup vault.
+type BackupRecoveryPoint struct {
+	Arn            string    // The ARN of the recovery point
+	ResourceArn    string    // The ARN of the backed up resource
+	ResourceType   string    // The type of the backed up resource (e.g. EBS, RDS, DynamoDB)
+	Status         string    // The status of the recovery point (e.g. COMPLETED, PARTIAL, EXPIRED)
+	CreationDate   time.Time // When the backup job that created the recovery point started
+	CompletionDate time.Time // When the backup job that created the recovery point completed. Zero if it is still running.
+}
+
+// BackupPlan is an AWS Backup plan.
+type BackupPlan struct {
+	Id        string       // The ID of the backup plan
+	Arn       string       // The ARN of the backup plan
+	Name      string       // The name of the backup plan
+	VersionId string       // The ID of the current version of the backup plan
+	Rules     []BackupRule // The rules of the backup plan
+}
+
+// BackupRule is a rule of an AWS Backup plan, which defines when backups are taken and how long they are kept.
+type BackupRule struct {
+	Name                       string // The name of the rule
+	TargetVaultName            string // The name of the vault the backups are stored in
+	ScheduleExpression         string // The cron expression of the backup schedule. Empty if backups are only taken on demand.
+	StartWindowMinutes         int64  // The time after the scheduled time within which a backup must start
+	CompletionWindowMinutes    int64  // The time after the start within which a backup must complete
+	MoveToColdStorageAfterDays int64  // The days after which backups are moved to cold storage. Zero if never.
+	DeleteAfterDays            int64  // The days after which backups are deleted. Zero if never.
+	EnableContinuousBackup     bool   // Whether continuous backups (point-in-time restore) are enabled
+}
+
+// GetBackupVaultRecoveryPoints fetches all the recovery points stored in the AWS Backup vault with the given name.
+func GetBackupVaultRecoveryPoints(t testing.TestingT, region string, vaultName string) []BackupRecoveryPoint {
+	recoveryPoints, err := GetBackupVaultRecoveryPointsE(t, region, vaultName)
+	require.NoError(t, err)
+	return recoveryPoints
+}
+
+// GetBackupVaultRecoveryPointsE fetches all the recovery points stored in the AWS Backup vault with the given name.
+// Returns a NotFoundError if the vault does not exist.
+func GetBackupVaultRecoveryPointsE(t testing.TestingT, region string, vaultName string) ([]BackupRecoveryPoint, error) {
+	return listBackupVaultRecoveryPointsE(t, region, &backup.ListRecoveryPointsByBackupVaultInput{
+		BackupVaultName: aws.String(vaultName),
+	})
+}
+
+// GetBackupVaultRecoveryPointsForResource fetches the recovery points of the resource with the given ARN stored in the
+// AWS Backup vault with the given name.
+func GetBackupVaultRecoveryPointsForResource(t testing.TestingT, region string, vaultName string, resourceArn string) []BackupRecoveryPoint {
+	recoveryPoints, err := GetBackupVaultRecoveryPointsForResourceE(t, region, vaultName, resourceArn)
+	require.NoError(t, err)
+	return recoveryPoints
+}
+
+// GetBackupVaultRecoveryPointsForResourceE fetches the recovery points of the resource with the given ARN stored in the
+// AWS Backup vault with the given name. Returns a NotFoundError if the vault does not exist.
+func GetBackupVaultRecoveryPointsForResourceE(t testing.TestingT, region string, vaultName string, resourceArn string) ([]BackupRecoveryPoint, error) {
+	return listBackupVaultRecoveryPointsE(t, region, &backup.ListRecoveryPointsByBackupVaultInput{
+		BackupVaultName: aws.String(vaultName),
+		ByResourceArn:   aws.String(resourceArn),
+	})
+}
+
+func listBackupVaultRecoveryPointsE(t testing.TestingT, region string, input *backup.ListRecoveryPointsByBackupVaultInput) ([]BackupRecoveryPoint, error) {
+	client, err := NewBackupClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryPoints := []BackupRecoveryPoint{}
+	paginator := backup.NewListRecoveryPointsByBackupVaultPaginator(client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, notFoundOr[*types.ResourceNotFoundException](err, "AWS Backup vault", aws.ToString(input.BackupVaultName), region)
+		}
+		for _, recoveryPoint := range page.RecoveryPoints {
+			recoveryPoints = append(recoveryPoints, newBackupRecoveryPoint(recoveryPoint))
+		}
+	}
+
+	return recoveryPoints, nil
+}
+
+// GetBackupPlan fetches the current version of the AWS Backup plan with the given ID, including its rules.
+func GetBackupPlan(t testing.TestingT, region string, planID string) *BackupPlan {
+	plan, err := GetBackupPlanE(t, region, planID)
+	require.NoError(t, err)
+	return plan
+}
+
+// GetBackupPlanE fetches the current version of the AWS Backup plan with the given ID, including its rules. Returns a
+// NotFoundError if the plan does not exist.
+func GetBackupPlanE(t testing.TestingT, region string, planID string) (*BackupPlan, error) {
+	client, err := NewBackupClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetBackupPlan(context.Background(), &backup.GetBackupPlanInput{BackupPlanId: aws.String(planID)})
+	if err != nil {
+		return nil, notFoundOr[*types.ResourceNotFoundException](err, "AWS Backup plan", planID, region)
+	}
+	if output.BackupPlan == nil {
+		return nil, NewNotFoundError("AWS Backup plan", planID, region)
+	}
+
+	return newBackupPlan(output), nil
+}
+
+func newBackupRecoveryPoint(recoveryPoint types.RecoveryPointByBackupVault) BackupRecoveryPoint {
+	return BackupRecoveryPoint{
+		Arn:            aws.ToString(recoveryPoint.RecoveryPointArn),
+		ResourceArn:    aws.ToString(recoveryPoint.ResourceArn),
+		ResourceType:   aws.ToString(recoveryPoint.ResourceType),
+		Status:         string(recoveryPoint.Status),
+		CreationDate:   aws.ToTime(recoveryPoint.CreationDate),
+		CompletionDate: aws.ToTime(recoveryPoint.CompletionDate),
+	}
+}
+
+func newBackupPlan(output *backup.GetBackupPlanOutput) *BackupPlan {
+	plan := &BackupPlan{
+		Id:        aws.ToString(output.BackupPlanId),
+		Arn:       aws.ToString(output.BackupPlanArn),
+		Name:      aws.ToString(output.BackupPlan.BackupPlanName),
+		VersionId: aws.ToString(output.VersionId),
+		Rules:     []BackupRule{},
+	}
+
+	for _, rule := range output.BackupPlan.Rules {
+		backupRule := BackupRule{
+			Name:                    aws.ToString(rule.RuleName),
+			TargetVaultName:         aws.ToString(rule.TargetBackupVaultName),
+			ScheduleExpression:      aws.ToString(rule.ScheduleExpression),
+			StartWindowMinutes:      aws.ToInt64(rule.StartWindowMinutes),
+			CompletionWindowMinutes: aws.ToInt64(rule.CompletionWindowMinutes),
+			EnableContinuousBackup:  aws.ToBool(rule.EnableContinuousBackup),
+		}
+		if lifecycle := rule.Lifecycle; lifecycle != nil {
+			backupRule.MoveToColdStorageAfterDays = aws.ToInt64(lifecycle.MoveToColdStorageAfterDays)
+			backupRule.DeleteAfterDays = aws.ToInt64(lifecycle.DeleteAfterDays)
+		}
+		plan.Rules = append(plan.Rules, backupRule)
+	}
+
+	return plan
+}
+
+// NewBackupClient creates a new AWS Backup client.
+func NewBackupClient(t testing.TestingT, region string) *backup.Client {
+	client, err := NewBackupClientE(t, region)
+	require.NoError(t, err)
+	return client
+}
+
+// NewBackupClientE creates a new AWS Backup client.
+func NewBackupClientE(t testing.TestingT, region string) (*backup.Client, error) {
+	sess, err := newAuthenticatedSession(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return backup.NewFromConfig(*sess), nil
+}
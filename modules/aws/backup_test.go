@@ -0,0 +1,80 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	"github.com/aws/aws-sdk-go-v2/service/backup/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBackupRecoveryPoint(t *testing.T) {
+	t.Parallel()
+
+	creationDate := time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC)
+	recoveryPoint := newBackupRecoveryPoint(types.RecoveryPointByBackupVault{
+		RecoveryPointArn: aws.String("arn:aws:ec2:us-east-1::snapshot/snap-0123456789abcdef0"),
+		ResourceArn:      aws.String("arn:aws:ec2:us-east-1:123456789012:volume/vol-0123456789abcdef0"),
+		ResourceType:     aws.String("EBS"),
+		Status:           types.RecoveryPointStatusCompleted,
+		CreationDate:     aws.Time(creationDate),
+	})
+
+	assert.Equal(t, BackupRecoveryPoint{
+		Arn:          "arn:aws:ec2:us-east-1::snapshot/snap-0123456789abcdef0",
+		ResourceArn:  "arn:aws:ec2:us-east-1:123456789012:volume/vol-0123456789abcdef0",
+		ResourceType: "EBS",
+		Status:       "COMPLETED",
+		CreationDate: creationDate,
+	}, recoveryPoint)
+}
+
+func TestNewBackupPlan(t *testing.T) {
+	t.Parallel()
+
+	plan := newBackupPlan(&backup.GetBackupPlanOutput{
+		BackupPlanId:  aws.String("plan-id"),
+		BackupPlanArn: aws.String("arn:aws:backup:us-east-1:123456789012:backup-plan:plan-id"),
+		VersionId:     aws.String("version-id"),
+		BackupPlan: &types.BackupPlan{
+			BackupPlanName: aws.String("daily"),
+			Rules: []types.BackupRule{
+				{
+					RuleName:              aws.String("daily-rule"),
+					TargetBackupVaultName: aws.String("vault"),
+					ScheduleExpression:    aws.String("cron(0 5 ? * * *)"),
+					StartWindowMinutes:    aws.Int64(60),
+					Lifecycle:             &types.Lifecycle{DeleteAfterDays: aws.Int64(35)},
+				},
+				{
+					RuleName:               aws.String("continuous-rule"),
+					TargetBackupVaultName:  aws.String("vault"),
+					EnableContinuousBackup: aws.Bool(true),
+				},
+			},
+		},
+	})
+
+	assert.Equal(t, &BackupPlan{
+		Id:        "plan-id",
+		Arn:       "arn:aws:backup:us-east-1:123456789012:backup-plan:plan-id",
+		Name:      "daily",
+		VersionId: "version-id",
+		Rules: []BackupRule{
+			{
+				Name:               "daily-rule",
+				TargetVaultName:    "vault",
+				ScheduleExpression: "cron(0 5 ? * * *)",
+				StartWindowMinutes: 60,
+				DeleteAfterDays:    35,
+			},
+			{
+				Name:                   "continuous-rule",
+				TargetVaultName:        "vault",
+				EnableContinuousBackup: true,
+			},
+		},
+	}, plan)
+}
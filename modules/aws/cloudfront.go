@@ -0,0 +1,437 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/google/uuid"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// cloudFrontDistributionPollInterval is the time to wait between checks of the status of a distribution while it is
+// being deployed, which usually takes several minutes.
+const cloudFrontDistributionPollInterval = 30 * time.Second
+
+// cloudFrontInvalidationPollInterval is the time to wait between checks of the status of an invalidation.
+const cloudFrontInvalidationPollInterval = 10 * time.Second
+
+// CloudFrontDistribution is a CloudFront distribution.
+type CloudFrontDistribution struct {
+	Id                   string                    // The ID of the distribution
+	Arn                  string                    // The ARN of the distribution
+	DomainName           string                    // The CloudFront domain name of the distribution (e.g. d111111abcdef8.cloudfront.net)
+	Status               string                    // The status of the distribution (Deployed or InProgress)
+	Enabled              bool                      // Whether the distribution serves requests
+	Aliases              []string                  // The alternate domain names (CNAMEs) of the distribution
+	Comment              string                    // The comment of the distribution
+	PriceClass           string                    // The price class of the distribution (e.g. PriceClass_100)
+	WebAclId             string                    // The ID or ARN of the WAF web ACL of the distribution. Empty if there is none.
+	Origins              []CloudFrontOrigin        // The origins of the distribution
+	DefaultCacheBehavior CloudFrontCacheBehavior   // The cache behavior of the requests that match no other cache behavior
+	CacheBehaviors       []CloudFrontCacheBehavior // The other cache behaviors of the distribution, in order of precedence
+}
+
+// CloudFrontOrigin is an origin of a CloudFront distribution.
+type CloudFrontOrigin struct {
+	Id                    string // The ID of the origin, which cache behaviors refer to
+	DomainName            string // The domain name of the origin (e.g. my-bucket.s3.us-east-1.amazonaws.com)
+	OriginPath            string // The path requests are forwarded to on the origin. Empty for the root.
+	OriginAccessControlId string // The ID of the origin access control of the origin. Empty if there is none.
+	ProtocolPolicy        string // The protocol CloudFront uses to connect to a custom origin (http-only, https-only or match-viewer). Empty for S3 origins.
+}
+
+// CloudFrontCacheBehavior is a cache behavior of a CloudFront distribution.
+type CloudFrontCacheBehavior struct {
+	PathPattern          string   // The path pattern of the requests the behavior applies to, or "*" for the default cache behavior
+	TargetOriginId       string   // The ID of the origin requests are forwarded to
+	ViewerProtocolPolicy string   // The protocol viewers can use (allow-all, https-only or redirect-to-https)
+	CachePolicyId        string   // The ID of the cache policy. Empty if the behavior uses legacy cache settings.
+	AllowedMethods       []string // The HTTP methods CloudFront forwards to the origin
+	Compress             bool     // Whether CloudFront compresses the responses
+}
+
+// CloudFrontResponse is a response to a request sent through a CloudFront distribution.
+type CloudFrontResponse struct {
+	StatusCode int         // The HTTP status code of the response
+	Headers    http.Header // The headers of the response, such as X-Cache and Age
+	Body       string      // The body of the response
+}
+
+// GetCloudFrontDistribution fetches the CloudFront distribution with the given ID.
+func GetCloudFrontDistribution(t testing.TestingT, distributionID string) *CloudFrontDistribution {
+	distribution, err := GetCloudFrontDistributionE(t, distributionID)
+	require.NoError(t, err)
+	return distribution
+}
+
+// GetCloudFrontDistributionE fetches the CloudFront distribution with the given ID. Returns a NotFoundError if the
+// distribution does not exist.
+func GetCloudFrontDistributionE(t testing.TestingT, distributionID string) (*CloudFrontDistribution, error) {
+	client, err := NewCloudFrontClientE(t, defaultRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetDistribution(context.Background(), &cloudfront.GetDistributionInput{Id: aws.String(distributionID)})
+	if err != nil {
+		var notFoundErr *types.NoSuchDistribution
+		if errors.As(err, &notFoundErr) {
+			return nil, NewNotFoundError("CloudFront distribution", distributionID, defaultRegion)
+		}
+		return nil, err
+	}
+	return newCloudFrontDistribution(output.Distribution), nil
+}
+
+// newCloudFrontDistribution converts the given distribution returned by the CloudFront API to a
+// CloudFrontDistribution.
+func newCloudFrontDistribution(description *types.Distribution) *CloudFrontDistribution {
+	distribution := &CloudFrontDistribution{
+		Id:             aws.ToString(description.Id),
+		Arn:            aws.ToString(description.ARN),
+		DomainName:     aws.ToString(description.DomainName),
+		Status:         aws.ToString(description.Status),
+		Aliases:        []string{},
+		Origins:        []CloudFrontOrigin{},
+		CacheBehaviors: []CloudFrontCacheBehavior{},
+	}
+
+	config := description.DistributionConfig
+	if config == nil {
+		return distribution
+	}
+	distribution.Enabled = aws.ToBool(config.Enabled)
+	distribution.Comment = aws.ToString(config.Comment)
+	distribution.PriceClass = string(config.PriceClass)
+	distribution.WebAclId = aws.ToString(config.WebACLId)
+	if config.Aliases != nil {
+		distribution.Aliases = append(distribution.Aliases, config.Aliases.Items...)
+	}
+	if config.Origins != nil {
+		for _, origin := range config.Origins.Items {
+			distribution.Origins = append(distribution.Origins, newCloudFrontOrigin(origin))
+		}
+	}
+	if config.DefaultCacheBehavior != nil {
+		behavior := config.DefaultCacheBehavior
+		distribution.DefaultCacheBehavior = CloudFrontCacheBehavior{
+			PathPattern:          "*",
+			TargetOriginId:       aws.ToString(behavior.TargetOriginId),
+			ViewerProtocolPolicy: string(behavior.ViewerProtocolPolicy),
+			CachePolicyId:        aws.ToString(behavior.CachePolicyId),
+			AllowedMethods:       newCloudFrontAllowedMethods(behavior.AllowedMethods),
+			Compress:             aws.ToBool(behavior.Compress),
+		}
+	}
+	if config.CacheBehaviors != nil {
+		for _, behavior := range config.CacheBehaviors.Items {
+			distribution.CacheBehaviors = append(distribution.CacheBehaviors, CloudFrontCacheBehavior{
+				PathPattern:          aws.ToString(behavior.PathPattern),
+				TargetOriginId:       aws.ToString(behavior.TargetOriginId),
+				ViewerProtocolPolicy: string(behavior.ViewerProtocolPolicy),
+				CachePolicyId:        aws.ToString(behavior.CachePolicyId),
+				AllowedMethods:       newCloudFrontAllowedMethods(behavior.AllowedMethods),
+				Compress:             aws.ToBool(behavior.Compress),
+			})
+		}
+	}
+	return distribution
+}
+
+// newCloudFrontOrigin converts the given origin returned by the CloudFront API to a CloudFrontOrigin.
+func newCloudFrontOrigin(origin types.Origin) CloudFrontOrigin {
+	cloudFrontOrigin := CloudFrontOrigin{
+		Id:                    aws.ToString(origin.Id),
+		DomainName:            aws.ToString(origin.DomainName),
+		OriginPath:            aws.ToString(origin.OriginPath),
+		OriginAccessControlId: aws.ToString(origin.OriginAccessControlId),
+	}
+	if origin.CustomOriginConfig != nil {
+		cloudFrontOrigin.ProtocolPolicy = string(origin.CustomOriginConfig.OriginProtocolPolicy)
+	}
+	return cloudFrontOrigin
+}
+
+// newCloudFrontAllowedMethods returns the given allowed methods returned by the CloudFront API as strings.
+func newCloudFrontAllowedMethods(allowedMethods *types.AllowedMethods) []string {
+	methods := []string{}
+	if allowedMethods == nil {
+		return methods
+	}
+	for _, method := range allowedMethods.Items {
+		methods = append(methods, string(method))
+	}
+	return methods
+}
+
+// WaitForCloudFrontDistributionDeployed waits up to the given timeout for the CloudFront distribution with the given
+// ID to be deployed to all edge locations and returns it.
+func WaitForCloudFrontDistributionDeployed(t testing.TestingT, distributionID string, timeout time.Duration) *CloudFrontDistribution {
+	distribution, err := WaitForCloudFrontDistributionDeployedE(t, distributionID, timeout)
+	require.NoError(t, err)
+	return distribution
+}
+
+// WaitForCloudFrontDistributionDeployedE waits up to the given timeout for the CloudFront distribution with the given
+// ID to be deployed to all edge locations and returns it. Creating or updating a distribution usually takes several
+// minutes to deploy, so the timeout should be generous.
+func WaitForCloudFrontDistributionDeployedE(t testing.TestingT, distributionID string, timeout time.Duration) (*CloudFrontDistribution, error) {
+	retries := int(timeout / cloudFrontDistributionPollInterval)
+	if retries < 1 {
+		retries = 1
+	}
+
+	description := fmt.Sprintf("Waiting for CloudFront distribution %s to be deployed", distributionID)
+	out, err := retry.DoWithRetryInterfaceE(t, description, retries, cloudFrontDistributionPollInterval, func() (interface{}, error) {
+		distribution, err := GetCloudFrontDistributionE(t, distributionID)
+		if err != nil {
+			return nil, err
+		}
+		if distribution.Status != "Deployed" {
+			return nil, fmt.Errorf("CloudFront distribution %s is still %s", distributionID, distribution.Status)
+		}
+		return distribution, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*CloudFrontDistribution), nil
+}
+
+// CreateCloudFrontInvalidationAndWait invalidates the given paths, such as "/index.html" or "/*", in the cache of the
+// CloudFront distribution with the given ID, waits up to the given timeout for the invalidation to complete and returns
+// the ID of the invalidation.
+func CreateCloudFrontInvalidationAndWait(t testing.TestingT, distributionID string, paths []string, timeout time.Duration) string {
+	invalidationID, err := CreateCloudFrontInvalidationAndWaitE(t, distributionID, paths, timeout)
+	require.NoError(t, err)
+	return invalidationID
+}
+
+// CreateCloudFrontInvalidationAndWaitE invalidates the given paths, such as "/index.html" or "/*", in the cache of the
+// CloudFront distribution with the given ID, waits up to the given timeout for the invalidation to complete and returns
+// the ID of the invalidation.
+func CreateCloudFrontInvalidationAndWaitE(t testing.TestingT, distributionID string, paths []string, timeout time.Duration) (string, error) {
+	client, err := NewCloudFrontClientE(t, defaultRegion)
+	if err != nil {
+		return "", err
+	}
+
+	logger.Default.Logf(t, "Creating invalidation of %v in CloudFront distribution %s", paths, distributionID)
+	output, err := client.CreateInvalidation(context.Background(), &cloudfront.CreateInvalidationInput{
+		DistributionId: aws.String(distributionID),
+		InvalidationBatch: &types.InvalidationBatch{
+			CallerReference: aws.String(uuid.NewString()),
+			Paths: &types.Paths{
+				Quantity: aws.Int32(int32(len(paths))),
+				Items:    paths,
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	invalidationID := aws.ToString(output.Invalidation.Id)
+
+	retries := int(timeout / cloudFrontInvalidationPollInterval)
+	if retries < 1 {
+		retries = 1
+	}
+
+	description := fmt.Sprintf("Waiting for invalidation %s of CloudFront distribution %s to complete", invalidationID, distributionID)
+	_, err = retry.DoWithRetryE(t, description, retries, cloudFrontInvalidationPollInterval, func() (string, error) {
+		invalidation, err := client.GetInvalidation(context.Background(), &cloudfront.GetInvalidationInput{
+			DistributionId: aws.String(distributionID),
+			Id:             aws.String(invalidationID),
+		})
+		if err != nil {
+			return "", err
+		}
+		status := aws.ToString(invalidation.Invalidation.Status)
+		if status != "Completed" {
+			return "", fmt.Errorf("invalidation %s is still %s", invalidationID, status)
+		}
+		return "", nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return invalidationID, nil
+}
+
+// AssertCloudFrontOriginDomainName checks that the origin with the given ID of the CloudFront distribution with the
+// given ID has the given domain name, failing the test if it does not.
+func AssertCloudFrontOriginDomainName(t testing.TestingT, distributionID string, originID string, expectedDomainName string) {
+	err := AssertCloudFrontOriginDomainNameE(t, distributionID, originID, expectedDomainName)
+	require.NoError(t, err)
+}
+
+// AssertCloudFrontOriginDomainNameE checks that the origin with the given ID of the CloudFront distribution with the
+// given ID has the given domain name. Returns an UnexpectedCloudFrontConfiguration error if it does not, and a
+// NotFoundError if the distribution has no origin with that ID.
+func AssertCloudFrontOriginDomainNameE(t testing.TestingT, distributionID string, originID string, expectedDomainName string) error {
+	distribution, err := GetCloudFrontDistributionE(t, distributionID)
+	if err != nil {
+		return err
+	}
+	return checkCloudFrontOriginDomainName(distribution, originID, expectedDomainName)
+}
+
+// checkCloudFrontOriginDomainName returns an error unless the origin with the given ID of the given distribution has
+// the given domain name.
+func checkCloudFrontOriginDomainName(distribution *CloudFrontDistribution, originID string, expectedDomainName string) error {
+	for _, origin := range distribution.Origins {
+		if origin.Id != originID {
+			continue
+		}
+		if origin.DomainName != expectedDomainName {
+			return UnexpectedCloudFrontConfiguration{
+				DistributionID: distribution.Id,
+				Setting:        fmt.Sprintf("domain name of origin %s", originID),
+				Expected:       expectedDomainName,
+				Actual:         origin.DomainName,
+			}
+		}
+		return nil
+	}
+	return NewNotFoundError("CloudFront origin", originID, defaultRegion)
+}
+
+// AssertCloudFrontCacheBehavior checks that the cache behavior with the given path pattern, or "*" for the default
+// cache behavior, of the CloudFront distribution with the given ID forwards requests to the origin with the given ID
+// with the given viewer protocol policy, failing the test if it does not.
+func AssertCloudFrontCacheBehavior(t testing.TestingT, distributionID string, pathPattern string, expectedTargetOriginID string, expectedViewerProtocolPolicy types.ViewerProtocolPolicy) {
+	err := AssertCloudFrontCacheBehaviorE(t, distributionID, pathPattern, expectedTargetOriginID, expectedViewerProtocolPolicy)
+	require.NoError(t, err)
+}
+
+// AssertCloudFrontCacheBehaviorE checks that the cache behavior with the given path pattern, or "*" for the default
+// cache behavior, of the CloudFront distribution with the given ID forwards requests to the origin with the given ID
+// with the given viewer protocol policy. Returns an UnexpectedCloudFrontConfiguration error if it does not, and a
+// NotFoundError if the distribution has no cache behavior with that path pattern.
+func AssertCloudFrontCacheBehaviorE(t testing.TestingT, distributionID string, pathPattern string, expectedTargetOriginID string, expectedViewerProtocolPolicy types.ViewerProtocolPolicy) error {
+	distribution, err := GetCloudFrontDistributionE(t, distributionID)
+	if err != nil {
+		return err
+	}
+	return checkCloudFrontCacheBehavior(distribution, pathPattern, expectedTargetOriginID, expectedViewerProtocolPolicy)
+}
+
+// checkCloudFrontCacheBehavior returns an error unless the cache behavior with the given path pattern of the given
+// distribution forwards requests to the given origin with the given viewer protocol policy.
+func checkCloudFrontCacheBehavior(distribution *CloudFrontDistribution, pathPattern string, expectedTargetOriginID string, expectedViewerProtocolPolicy types.ViewerProtocolPolicy) error {
+	behaviors := append([]CloudFrontCacheBehavior{distribution.DefaultCacheBehavior}, distribution.CacheBehaviors...)
+	for _, behavior := range behaviors {
+		if behavior.PathPattern != pathPattern {
+			continue
+		}
+		if behavior.TargetOriginId != expectedTargetOriginID {
+			return UnexpectedCloudFrontConfiguration{
+				DistributionID: distribution.Id,
+				Setting:        fmt.Sprintf("target origin of cache behavior %s", pathPattern),
+				Expected:       expectedTargetOriginID,
+				Actual:         behavior.TargetOriginId,
+			}
+		}
+		if behavior.ViewerProtocolPolicy != string(expectedViewerProtocolPolicy) {
+			return UnexpectedCloudFrontConfiguration{
+				DistributionID: distribution.Id,
+				Setting:        fmt.Sprintf("viewer protocol policy of cache behavior %s", pathPattern),
+				Expected:       string(expectedViewerProtocolPolicy),
+				Actual:         behavior.ViewerProtocolPolicy,
+			}
+		}
+		return nil
+	}
+	return NewNotFoundError("CloudFront cache behavior", pathPattern, defaultRegion)
+}
+
+// HttpGetThroughCloudFrontWithRetry sends GET requests for the given path to the given domain name of a CloudFront
+// distribution until the response has all the given headers, and returns the response.
+func HttpGetThroughCloudFrontWithRetry(t testing.TestingT, domainName string, path string, expectedHeaders map[string]string, retries int, sleepBetweenRetries time.Duration) *CloudFrontResponse {
+	response, err := HttpGetThroughCloudFrontWithRetryE(t, domainName, path, expectedHeaders, retries, sleepBetweenRetries)
+	require.NoError(t, err)
+	return response
+}
+
+// HttpGetThroughCloudFrontWithRetryE sends GET requests over HTTPS for the given path to the given domain name of a
+// CloudFront distribution, which can be its CloudFront domain name or one of its aliases, until the response has all
+// the given headers with the given values or the given number of retries is exhausted, and returns the response. For
+// example, pass {"X-Cache": "Hit from cloudfront"} to wait until responses are served from the cache. Header values are
+// compared case insensitively.
+func HttpGetThroughCloudFrontWithRetryE(t testing.TestingT, domainName string, path string, expectedHeaders map[string]string, retries int, sleepBetweenRetries time.Duration) (*CloudFrontResponse, error) {
+	url := fmt.Sprintf("https://%s/%s", domainName, strings.TrimPrefix(path, "/"))
+
+	description := fmt.Sprintf("GET %s until the response has headers %v", url, expectedHeaders)
+	out, err := retry.DoWithRetryInterfaceE(t, description, retries, sleepBetweenRetries, func() (interface{}, error) {
+		response, err := httpGetThroughCloudFront(url)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkCloudFrontResponseHeaders(response.Headers, expectedHeaders); err != nil {
+			return nil, err
+		}
+		return response, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*CloudFrontResponse), nil
+}
+
+// httpGetThroughCloudFront sends a GET request to the given URL and returns the response.
+func httpGetThroughCloudFront(url string) (*CloudFrontResponse, error) {
+	client := http.Client{
+		// By default, Go does not impose a timeout, so an HTTP connection attempt can hang for a LONG time.
+		Timeout: 30 * time.Second,
+	}
+	response, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &CloudFrontResponse{StatusCode: response.StatusCode, Headers: response.Header, Body: string(body)}, nil
+}
+
+// checkCloudFrontResponseHeaders returns an error unless the given headers have all the given expected values.
+func checkCloudFrontResponseHeaders(headers http.Header, expectedHeaders map[string]string) error {
+	for name, expectedValue := range expectedHeaders {
+		actualValue := headers.Get(name)
+		if !strings.EqualFold(actualValue, expectedValue) {
+			return fmt.Errorf("expected header %s to be %q, but got %q", name, expectedValue, actualValue)
+		}
+	}
+	return nil
+}
+
+// NewCloudFrontClient creates a new CloudFront client.
+func NewCloudFrontClient(t testing.TestingT, region string) *cloudfront.Client {
+	client, err := NewCloudFrontClientE(t, region)
+	require.NoError(t, err)
+	return client
+}
+
+// NewCloudFrontClientE creates a new CloudFront client.
+func NewCloudFrontClientE(t testing.TestingT, region string) (*cloudfront.Client, error) {
+	sess, err := newAuthenticatedSession(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return cloudfront.NewFromConfig(*sess), nil
+}
@@ -0,0 +1,65 @@
+package aws
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCloudFrontDistribution(t *testing.T) {
+	t.Parallel()
+
+	distribution := newCloudFrontDistribution(&types.Distribution{
+		Id:         aws.String("E123"),
+		DomainName: aws.String("d111111abcdef8.cloudfront.net"),
+		Status:     aws.String("Deployed"),
+		DistributionConfig: &types.DistributionConfig{
+			Enabled: aws.Bool(true),
+			Aliases: &types.Aliases{Quantity: aws.Int32(1), Items: []string{"www.example.com"}},
+			Origins: &types.Origins{Quantity: aws.Int32(2), Items: []types.Origin{
+				{Id: aws.String("s3"), DomainName: aws.String("my-bucket.s3.us-east-1.amazonaws.com"), OriginAccessControlId: aws.String("OAC1")},
+				{Id: aws.String("api"), DomainName: aws.String("api.example.com"), CustomOriginConfig: &types.CustomOriginConfig{OriginProtocolPolicy: types.OriginProtocolPolicyHttpsOnly}},
+			}},
+			DefaultCacheBehavior: &types.DefaultCacheBehavior{
+				TargetOriginId:       aws.String("s3"),
+				ViewerProtocolPolicy: types.ViewerProtocolPolicyRedirectToHttps,
+				AllowedMethods:       &types.AllowedMethods{Quantity: aws.Int32(2), Items: []types.Method{types.MethodGet, types.MethodHead}},
+			},
+			CacheBehaviors: &types.CacheBehaviors{Quantity: aws.Int32(1), Items: []types.CacheBehavior{
+				{PathPattern: aws.String("/api/*"), TargetOriginId: aws.String("api"), ViewerProtocolPolicy: types.ViewerProtocolPolicyHttpsOnly},
+			}},
+		},
+	})
+
+	assert.Equal(t, "E123", distribution.Id)
+	assert.True(t, distribution.Enabled)
+	assert.Equal(t, []string{"www.example.com"}, distribution.Aliases)
+	require.Len(t, distribution.Origins, 2)
+	assert.Equal(t, "OAC1", distribution.Origins[0].OriginAccessControlId)
+	assert.Equal(t, "https-only", distribution.Origins[1].ProtocolPolicy)
+	assert.Equal(t, CloudFrontCacheBehavior{PathPattern: "*", TargetOriginId: "s3", ViewerProtocolPolicy: "redirect-to-https", AllowedMethods: []string{"GET", "HEAD"}}, distribution.DefaultCacheBehavior)
+
+	assert.NoError(t, checkCloudFrontOriginDomainName(distribution, "api", "api.example.com"))
+	assert.IsType(t, UnexpectedCloudFrontConfiguration{}, checkCloudFrontOriginDomainName(distribution, "api", "other.example.com"))
+	assert.IsType(t, NotFoundError{}, checkCloudFrontOriginDomainName(distribution, "missing", "api.example.com"))
+
+	assert.NoError(t, checkCloudFrontCacheBehavior(distribution, "*", "s3", types.ViewerProtocolPolicyRedirectToHttps))
+	assert.NoError(t, checkCloudFrontCacheBehavior(distribution, "/api/*", "api", types.ViewerProtocolPolicyHttpsOnly))
+	assert.IsType(t, UnexpectedCloudFrontConfiguration{}, checkCloudFrontCacheBehavior(distribution, "/api/*", "api", types.ViewerProtocolPolicyAllowAll))
+	assert.IsType(t, NotFoundError{}, checkCloudFrontCacheBehavior(distribution, "/static/*", "s3", types.ViewerProtocolPolicyAllowAll))
+}
+
+func TestCheckCloudFrontResponseHeaders(t *testing.T) {
+	t.Parallel()
+
+	headers := http.Header{}
+	headers.Set("X-Cache", "Hit from cloudfront")
+
+	assert.NoError(t, checkCloudFrontResponseHeaders(headers, map[string]string{"x-cache": "hit from cloudfront"}))
+	assert.Error(t, checkCloudFrontResponseHeaders(headers, map[string]string{"X-Cache": "Miss from cloudfront"}))
+	assert.Error(t, checkCloudFrontResponseHeaders(headers, map[string]string{"Age": "10"}))
+}
@@ -0,0 +1,118 @@
+package aws
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// CloudTrail contains the configuration of an AWS CloudTrail trail.
+type CloudTrail struct {
+	Name                       string // The name of the trail
+	Arn                        string // The ARN of the trail
+	HomeRegion                 string // The region the trail was created in
+	S3BucketName               string // The S3 bucket the log files are delivered to
+	S3KeyPrefix                string // The prefix of the log files in the S3 bucket. Empty if not set.
+	KmsKeyId                   string // The KMS key used to encrypt the log files. Empty if the log files use SSE-S3.
+	CloudWatchLogsLogGroupArn  string // The CloudWatch Logs log group the events are delivered to. Empty if not set.
+	IsMultiRegionTrail         bool   // Whether the trail logs events from all regions
+	IsOrganizationTrail        bool   // Whether the trail logs events from all the accounts of the organization
+	IncludeGlobalServiceEvents bool   // Whether the trail logs events from global services such as IAM
+	LogFileValidationEnabled   bool   // Whether log file integrity validation is enabled
+}
+
+// CloudTrailStatus contains the logging status of an AWS CloudTrail trail.
+type CloudTrailStatus struct {
+	IsLogging           bool      // Whether the trail is currently logging events
+	LatestDeliveryTime  time.Time // When the trail last delivered log files to S3. Zero if it never did.
+	LatestDeliveryError string    // The error of the last failed delivery of log files to S3. Empty if the last delivery succeeded.
+}
+
+// GetCloudTrail fetches the configuration of the CloudTrail trail with the given name or ARN.
+func GetCloudTrail(t testing.TestingT, region string, trailName string) *CloudTrail {
+	trail, err := GetCloudTrailE(t, region, trailName)
+	require.NoError(t, err)
+	return trail
+}
+
+// GetCloudTrailE fetches the configuration of the CloudTrail trail with the given name or ARN. Returns a NotFoundError
+// if the trail does not exist.
+func GetCloudTrailE(t testing.TestingT, region string, trailName string) (*CloudTrail, error) {
+	client, err := NewCloudTrailClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetTrail(context.Background(), &cloudtrail.GetTrailInput{Name: aws.String(trailName)})
+	if err != nil {
+		return nil, notFoundOr[*types.TrailNotFoundException](err, "CloudTrail trail", trailName, region)
+	}
+	if output.Trail == nil {
+		return nil, NewNotFoundError("CloudTrail trail", trailName, region)
+	}
+
+	trail := output.Trail
+	return &CloudTrail{
+		Name:                       aws.ToString(trail.Name),
+		Arn:                        aws.ToString(trail.TrailARN),
+		HomeRegion:                 aws.ToString(trail.HomeRegion),
+		S3BucketName:               aws.ToString(trail.S3BucketName),
+		S3KeyPrefix:                aws.ToString(trail.S3KeyPrefix),
+		KmsKeyId:                   aws.ToString(trail.KmsKeyId),
+		CloudWatchLogsLogGroupArn:  aws.ToString(trail.CloudWatchLogsLogGroupArn),
+		IsMultiRegionTrail:         aws.ToBool(trail.IsMultiRegionTrail),
+		IsOrganizationTrail:        aws.ToBool(trail.IsOrganizationTrail),
+		IncludeGlobalServiceEvents: aws.ToBool(trail.IncludeGlobalServiceEvents),
+		LogFileValidationEnabled:   aws.ToBool(trail.LogFileValidationEnabled),
+	}, nil
+}
+
+// GetCloudTrailStatus fetches whether the CloudTrail trail with the given name or ARN is logging, and when it last
+// delivered log files.
+func GetCloudTrailStatus(t testing.TestingT, region string, trailName string) *CloudTrailStatus {
+	status, err := GetCloudTrailStatusE(t, region, trailName)
+	require.NoError(t, err)
+	return status
+}
+
+// GetCloudTrailStatusE fetches whether the CloudTrail trail with the given name or ARN is logging, and when it last
+// delivered log files. Returns a NotFoundError if the trail does not exist.
+func GetCloudTrailStatusE(t testing.TestingT, region string, trailName string) (*CloudTrailStatus, error) {
+	client, err := NewCloudTrailClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetTrailStatus(context.Background(), &cloudtrail.GetTrailStatusInput{Name: aws.String(trailName)})
+	if err != nil {
+		return nil, notFoundOr[*types.TrailNotFoundException](err, "CloudTrail trail", trailName, region)
+	}
+
+	return &CloudTrailStatus{
+		IsLogging:           aws.ToBool(output.IsLogging),
+		LatestDeliveryTime:  aws.ToTime(output.LatestDeliveryTime),
+		LatestDeliveryError: aws.ToString(output.LatestDeliveryError),
+	}, nil
+}
+
+// NewCloudTrailClient creates a new CloudTrail client.
+func NewCloudTrailClient(t testing.TestingT, region string) *cloudtrail.Client {
+	client, err := NewCloudTrailClientE(t, region)
+	require.NoError(t, err)
+	return client
+}
+
+// NewCloudTrailClientE creates a new CloudTrail client.
+func NewCloudTrailClientE(t testing.TestingT, region string) (*cloudtrail.Client, error) {
+	sess, err := newAuthenticatedSession(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return cloudtrail.NewFromConfig(*sess), nil
+}
@@ -2,12 +2,74 @@ package aws
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	cloudwatchlogstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
 
+// CloudWatchDatapoint contains the statistics of a CloudWatch metric for one period.
+type CloudWatchDatapoint struct {
+	Timestamp   time.Time // The start of the period
+	SampleCount float64   // The number of data points published in the period
+	Average     float64   // The average of the values published in the period
+	Sum         float64   // The sum of the values published in the period
+	Minimum     float64   // The minimum value published in the period
+	Maximum     float64   // The maximum value published in the period
+	Unit        string    // The unit of the values (e.g. Count or Seconds)
+}
+
+// CloudWatchMetricDataResult contains the values returned for one query of a GetMetricData request.
+type CloudWatchMetricDataResult struct {
+	Id         string      // The id of the query
+	Label      string      // The label of the query, or the name of the metric if the query has no label
+	Timestamps []time.Time // The timestamps of the values, newest first
+	Values     []float64   // The values of the query, in the same order as Timestamps
+}
+
+// CloudWatchAlarm contains the configuration and state of a CloudWatch metric alarm.
+type CloudWatchAlarm struct {
+	Name               string            // The name of the alarm
+	Arn                string            // The ARN of the alarm
+	State              types.StateValue  // The current state of the alarm (OK, ALARM or INSUFFICIENT_DATA)
+	StateReason        string            // The explanation for the current state of the alarm
+	Namespace          string            // The namespace of the metric of the alarm. Empty for alarms on metric math expressions.
+	MetricName         string            // The name of the metric of the alarm. Empty for alarms on metric math expressions.
+	Dimensions         map[string]string // The dimensions of the metric of the alarm
+	Statistic          string            // The statistic (e.g. Average) or extended statistic (e.g. p99) of the metric
+	Period             int32             // The length in seconds of the periods over which the statistic is applied
+	EvaluationPeriods  int32             // The number of most recent periods compared to the threshold
+	DatapointsToAlarm  int32             // The number of data points that must be breaching to trigger the alarm
+	Threshold          float64           // The value the statistic is compared to
+	ComparisonOperator string            // How the statistic is compared to the threshold (e.g. GreaterThanThreshold)
+	TreatMissingData   string            // How missing data points are treated (e.g. missing or breaching)
+	ActionsEnabled     bool              // Whether actions are run when the alarm changes state
+	AlarmActions       []string          // The ARNs of the actions run when the alarm enters the ALARM state
+	OkActions          []string          // The ARNs of the actions run when the alarm enters the OK state
+}
+
+// CloudWatchLogsInsightsRow is a row of the results of a CloudWatch Logs Insights query, mapping the names of the
+// fields of the query (e.g. @timestamp or @message) to their values.
+type CloudWatchLogsInsightsRow map[string]string
+
+// cloudWatchAlarmPollInterval is the time to wait between checks of the state of an alarm.
+const cloudWatchAlarmPollInterval = 10 * time.Second
+
+// logsInsightsQueryTimeout is the maximum time to wait for a CloudWatch Logs Insights query to complete, and
+// logsInsightsPollInterval the time to wait between checks of its status.
+const (
+	logsInsightsQueryTimeout = 5 * time.Minute
+	logsInsightsPollInterval = time.Second
+)
+
 // GetCloudWatchLogEntries returns the CloudWatch log messages in the given region for the given log stream and log group.
 func GetCloudWatchLogEntries(t testing.TestingT, awsRegion string, logStreamName string, logGroupName string) []string {
 	out, err := GetCloudWatchLogEntriesE(t, awsRegion, logStreamName, logGroupName)
@@ -41,6 +103,502 @@ func GetCloudWatchLogEntriesE(t testing.TestingT, awsRegion string, logStreamNam
 	return entries, nil
 }
 
+// GetCloudWatchMetricStatistics returns the statistics of the CloudWatch metric with the given namespace, name and
+// dimensions between the given start and end time, aggregated over periods of the given length, oldest first.
+func GetCloudWatchMetricStatistics(t testing.TestingT, awsRegion string, namespace string, metricName string, dimensions map[string]string, startTime time.Time, endTime time.Time, period time.Duration) []CloudWatchDatapoint {
+	out, err := GetCloudWatchMetricStatisticsE(t, awsRegion, namespace, metricName, dimensions, startTime, endTime, period)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// GetCloudWatchMetricStatisticsE returns the statistics of the CloudWatch metric with the given namespace, name and
+// dimensions between the given start and end time, aggregated over periods of the given length, oldest first. The
+// period must be a multiple of 60 seconds, unless the metric is a high resolution metric. Periods in which no data was
+// published are not returned, so an empty list means the metric was not published at all in that time.
+func GetCloudWatchMetricStatisticsE(t testing.TestingT, awsRegion string, namespace string, metricName string, dimensions map[string]string, startTime time.Time, endTime time.Time, period time.Duration) ([]CloudWatchDatapoint, error) {
+	client, err := NewCloudWatchClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetMetricStatistics(context.Background(), &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String(namespace),
+		MetricName: aws.String(metricName),
+		Dimensions: newCloudWatchDimensions(dimensions),
+		StartTime:  aws.Time(startTime),
+		EndTime:    aws.Time(endTime),
+		Period:     aws.Int32(int32(period / time.Second)),
+		Statistics: types.Statistic("").Values(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newCloudWatchDatapoints(output.Datapoints), nil
+}
+
+// GetCloudWatchMetricData runs the given CloudWatch metric data queries between the given start and end time and
+// returns the results of the queries that return data.
+func GetCloudWatchMetricData(t testing.TestingT, awsRegion string, queries []types.MetricDataQuery, startTime time.Time, endTime time.Time) []CloudWatchMetricDataResult {
+	out, err := GetCloudWatchMetricDataE(t, awsRegion, queries, startTime, endTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// GetCloudWatchMetricDataE runs the given CloudWatch metric data queries between the given start and end time and
+// returns the results of the queries that return data. Unlike GetCloudWatchMetricStatisticsE, this supports metric
+// math expressions and querying several metrics at once. The values of each query are gathered from all the pages of
+// the response.
+func GetCloudWatchMetricDataE(t testing.TestingT, awsRegion string, queries []types.MetricDataQuery, startTime time.Time, endTime time.Time) ([]CloudWatchMetricDataResult, error) {
+	client, err := NewCloudWatchClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []types.MetricDataResult
+
+	paginator := cloudwatch.NewGetMetricDataPaginator(client, &cloudwatch.GetMetricDataInput{
+		MetricDataQueries: queries,
+		StartTime:         aws.Time(startTime),
+		EndTime:           aws.Time(endTime),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, page.MetricDataResults...)
+	}
+
+	return mergeCloudWatchMetricDataResults(results), nil
+}
+
+// GetCloudWatchAlarm returns the CloudWatch metric alarm with the given name.
+func GetCloudWatchAlarm(t testing.TestingT, awsRegion string, alarmName string) *CloudWatchAlarm {
+	out, err := GetCloudWatchAlarmE(t, awsRegion, alarmName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// GetCloudWatchAlarmE returns the CloudWatch metric alarm with the given name, or a NotFoundError if there is no such
+// alarm.
+func GetCloudWatchAlarmE(t testing.TestingT, awsRegion string, alarmName string) (*CloudWatchAlarm, error) {
+	client, err := NewCloudWatchClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.DescribeAlarms(context.Background(), &cloudwatch.DescribeAlarmsInput{
+		AlarmNames: []string{alarmName},
+		AlarmTypes: []types.AlarmType{types.AlarmTypeMetricAlarm},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(output.MetricAlarms) == 0 {
+		return nil, NewNotFoundError("CloudWatch alarm", alarmName, awsRegion)
+	}
+
+	return newCloudWatchAlarm(&output.MetricAlarms[0]), nil
+}
+
+// WaitForCloudWatchAlarmState waits up to the given timeout for the CloudWatch metric alarm with the given name to be
+// in the given state and returns the alarm.
+func WaitForCloudWatchAlarmState(t testing.TestingT, awsRegion string, alarmName string, state types.StateValue, timeout time.Duration) *CloudWatchAlarm {
+	out, err := WaitForCloudWatchAlarmStateE(t, awsRegion, alarmName, state, timeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// WaitForCloudWatchAlarmStateE waits up to the given timeout for the CloudWatch metric alarm with the given name to be
+// in the given state and returns the alarm. The state of the alarm is checked every few seconds. Note that CloudWatch
+// only evaluates an alarm once per period of its metric, so the timeout should be at least a few periods long.
+func WaitForCloudWatchAlarmStateE(t testing.TestingT, awsRegion string, alarmName string, state types.StateValue, timeout time.Duration) (*CloudWatchAlarm, error) {
+	retries := int(timeout / cloudWatchAlarmPollInterval)
+	if retries < 1 {
+		retries = 1
+	}
+
+	description := fmt.Sprintf("Waiting for CloudWatch alarm %s to be in state %s", alarmName, state)
+	out, err := retry.DoWithRetryInterfaceE(t, description, retries, cloudWatchAlarmPollInterval, func() (interface{}, error) {
+		alarm, err := GetCloudWatchAlarmE(t, awsRegion, alarmName)
+		if err != nil {
+			return nil, err
+		}
+		if alarm.State != state {
+			return nil, fmt.Errorf("CloudWatch alarm %s is in state %s: %s", alarmName, alarm.State, alarm.StateReason)
+		}
+		return alarm, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Default.Logf(t, "CloudWatch alarm %s is in state %s", alarmName, state)
+	return out.(*CloudWatchAlarm), nil
+}
+
+// GetCloudWatchAlarmsForResource returns the CloudWatch metric alarms on metrics in the given namespace that have a
+// dimension with the given name and value, such as the alarms with the dimension InstanceId=i-0123456789abcdef0 in the
+// AWS/EC2 namespace.
+func GetCloudWatchAlarmsForResource(t testing.TestingT, awsRegion string, namespace string, dimensionName string, dimensionValue string) []CloudWatchAlarm {
+	out, err := GetCloudWatchAlarmsForResourceE(t, awsRegion, namespace, dimensionName, dimensionValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// GetCloudWatchAlarmsForResourceE returns the CloudWatch metric alarms on metrics in the given namespace that have a
+// dimension with the given name and value, such as the alarms with the dimension InstanceId=i-0123456789abcdef0 in the
+// AWS/EC2 namespace. Alarms on metric math expressions are included if any of the metrics in the expression match.
+func GetCloudWatchAlarmsForResourceE(t testing.TestingT, awsRegion string, namespace string, dimensionName string, dimensionValue string) ([]CloudWatchAlarm, error) {
+	client, err := NewCloudWatchClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	var alarms []CloudWatchAlarm
+
+	paginator := cloudwatch.NewDescribeAlarmsPaginator(client, &cloudwatch.DescribeAlarmsInput{
+		AlarmTypes: []types.AlarmType{types.AlarmTypeMetricAlarm},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for i := range page.MetricAlarms {
+			if cloudWatchAlarmMatchesResource(&page.MetricAlarms[i], namespace, dimensionName, dimensionValue) {
+				alarms = append(alarms, *newCloudWatchAlarm(&page.MetricAlarms[i]))
+			}
+		}
+	}
+
+	return alarms, nil
+}
+
+// AssertCloudWatchAlarmExistsForResource checks that there is at least one CloudWatch metric alarm on a metric in the
+// given namespace with a dimension with the given name and value, and fails the test if there is not.
+func AssertCloudWatchAlarmExistsForResource(t testing.TestingT, awsRegion string, namespace string, dimensionName string, dimensionValue string) {
+	err := AssertCloudWatchAlarmExistsForResourceE(t, awsRegion, namespace, dimensionName, dimensionValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// AssertCloudWatchAlarmExistsForResourceE checks that there is at least one CloudWatch metric alarm on a metric in the
+// given namespace with a dimension with the given name and value, and returns a NoCloudWatchAlarmForResourceError if
+// there is not.
+func AssertCloudWatchAlarmExistsForResourceE(t testing.TestingT, awsRegion string, namespace string, dimensionName string, dimensionValue string) error {
+	alarms, err := GetCloudWatchAlarmsForResourceE(t, awsRegion, namespace, dimensionName, dimensionValue)
+	if err != nil {
+		return err
+	}
+	if len(alarms) == 0 {
+		return NoCloudWatchAlarmForResourceError{
+			Namespace:      namespace,
+			DimensionName:  dimensionName,
+			DimensionValue: dimensionValue,
+			Region:         awsRegion,
+		}
+	}
+	return nil
+}
+
+// newCloudWatchDimensions converts the given map of dimension names to values into CloudWatch dimensions, sorted by
+// name.
+func newCloudWatchDimensions(dimensions map[string]string) []types.Dimension {
+	result := make([]types.Dimension, 0, len(dimensions))
+	for name, value := range dimensions {
+		result = append(result, types.Dimension{Name: aws.String(name), Value: aws.String(value)})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return aws.ToString(result[i].Name) < aws.ToString(result[j].Name)
+	})
+	return result
+}
+
+// newCloudWatchDatapoints converts the given datapoints returned by the CloudWatch API, which are not in any
+// particular order, into CloudWatchDatapoints sorted by timestamp, oldest first.
+func newCloudWatchDatapoints(datapoints []types.Datapoint) []CloudWatchDatapoint {
+	result := make([]CloudWatchDatapoint, 0, len(datapoints))
+	for _, datapoint := range datapoints {
+		result = append(result, CloudWatchDatapoint{
+			Timestamp:   aws.ToTime(datapoint.Timestamp),
+			SampleCount: aws.ToFloat64(datapoint.SampleCount),
+			Average:     aws.ToFloat64(datapoint.Average),
+			Sum:         aws.ToFloat64(datapoint.Sum),
+			Minimum:     aws.ToFloat64(datapoint.Minimum),
+			Maximum:     aws.ToFloat64(datapoint.Maximum),
+			Unit:        string(datapoint.Unit),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+	return result
+}
+
+// mergeCloudWatchMetricDataResults converts the given metric data results, which may contain several partial results
+// for the same query when the response spans several pages, into one CloudWatchMetricDataResult per query, in the
+// order the queries first appear.
+func mergeCloudWatchMetricDataResults(results []types.MetricDataResult) []CloudWatchMetricDataResult {
+	var merged []CloudWatchMetricDataResult
+	indexes := map[string]int{}
+
+	for _, result := range results {
+		id := aws.ToString(result.Id)
+		index, ok := indexes[id]
+		if !ok {
+			index = len(merged)
+			indexes[id] = index
+			merged = append(merged, CloudWatchMetricDataResult{Id: id, Label: aws.ToString(result.Label)})
+		}
+		merged[index].Timestamps = append(merged[index].Timestamps, result.Timestamps...)
+		merged[index].Values = append(merged[index].Values, result.Values...)
+	}
+
+	return merged
+}
+
+// newCloudWatchAlarm converts the given metric alarm returned by the CloudWatch API into a CloudWatchAlarm.
+func newCloudWatchAlarm(alarm *types.MetricAlarm) *CloudWatchAlarm {
+	statistic := string(alarm.Statistic)
+	if statistic == "" {
+		statistic = aws.ToString(alarm.ExtendedStatistic)
+	}
+
+	dimensions := map[string]string{}
+	for _, dimension := range alarm.Dimensions {
+		dimensions[aws.ToString(dimension.Name)] = aws.ToString(dimension.Value)
+	}
+
+	return &CloudWatchAlarm{
+		Name:               aws.ToString(alarm.AlarmName),
+		Arn:                aws.ToString(alarm.AlarmArn),
+		State:              alarm.StateValue,
+		StateReason:        aws.ToString(alarm.StateReason),
+		Namespace:          aws.ToString(alarm.Namespace),
+		MetricName:         aws.ToString(alarm.MetricName),
+		Dimensions:         dimensions,
+		Statistic:          statistic,
+		Period:             aws.ToInt32(alarm.Period),
+		EvaluationPeriods:  aws.ToInt32(alarm.EvaluationPeriods),
+		DatapointsToAlarm:  aws.ToInt32(alarm.DatapointsToAlarm),
+		Threshold:          aws.ToFloat64(alarm.Threshold),
+		ComparisonOperator: string(alarm.ComparisonOperator),
+		TreatMissingData:   aws.ToString(alarm.TreatMissingData),
+		ActionsEnabled:     aws.ToBool(alarm.ActionsEnabled),
+		AlarmActions:       alarm.AlarmActions,
+		OkActions:          alarm.OKActions,
+	}
+}
+
+// cloudWatchAlarmMatchesResource returns true if the given alarm, or any of the metrics in its metric math
+// expression, is on a metric in the given namespace with a dimension with the given name and value.
+func cloudWatchAlarmMatchesResource(alarm *types.MetricAlarm, namespace string, dimensionName string, dimensionValue string) bool {
+	if aws.ToString(alarm.Namespace) == namespace && hasCloudWatchDimension(alarm.Dimensions, dimensionName, dimensionValue) {
+		return true
+	}
+	for _, query := range alarm.Metrics {
+		if query.MetricStat == nil || query.MetricStat.Metric == nil {
+			continue
+		}
+		metric := query.MetricStat.Metric
+		if aws.ToString(metric.Namespace) == namespace && hasCloudWatchDimension(metric.Dimensions, dimensionName, dimensionValue) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCloudWatchDimension returns true if the given dimensions contain a dimension with the given name and value.
+func hasCloudWatchDimension(dimensions []types.Dimension, name string, value string) bool {
+	for _, dimension := range dimensions {
+		if aws.ToString(dimension.Name) == name && aws.ToString(dimension.Value) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// NewCloudWatchClient creates a new CloudWatch client.
+func NewCloudWatchClient(t testing.TestingT, region string) *cloudwatch.Client {
+	client, err := NewCloudWatchClientE(t, region)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// NewCloudWatchClientE creates a new CloudWatch client.
+func NewCloudWatchClientE(t testing.TestingT, region string) (*cloudwatch.Client, error) {
+	sess, err := newAuthenticatedSession(t, region)
+	if err != nil {
+		return nil, err
+	}
+	return cloudwatch.NewFromConfig(*sess), nil
+}
+
+// QueryCloudWatchLogsInsights runs the given CloudWatch Logs Insights query over the events logged in the given log
+// groups in the given window of time before now, waits for it to complete and returns the rows of its results.
+func QueryCloudWatchLogsInsights(t testing.TestingT, awsRegion string, logGroupNames []string, query string, window time.Duration) []CloudWatchLogsInsightsRow {
+	out, err := QueryCloudWatchLogsInsightsE(t, awsRegion, logGroupNames, query, window)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// QueryCloudWatchLogsInsightsE runs the given CloudWatch Logs Insights query over the events logged in the given log
+// groups in the given window of time before now, waits for it to complete and returns the rows of its results. The
+// query runs on the CloudWatch side, so unlike GetCloudWatchLogEntriesE this works for busy log groups and log groups
+// with many streams, e.g.:
+//
+//	fields @timestamp, @message | filter @message like /ERROR/ | sort @timestamp desc | limit 20
+//
+// Returns a LogsInsightsQueryFailed error if the query fails or does not complete within a few minutes, in which case
+// the query is stopped.
+func QueryCloudWatchLogsInsightsE(t testing.TestingT, awsRegion string, logGroupNames []string, query string, window time.Duration) ([]CloudWatchLogsInsightsRow, error) {
+	client, err := NewCloudWatchLogsClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	endTime := time.Now()
+	startTime := endTime.Add(-window)
+	output, err := client.StartQuery(context.Background(), &cloudwatchlogs.StartQueryInput{
+		LogGroupNames: logGroupNames,
+		QueryString:   aws.String(query),
+		StartTime:     aws.Int64(startTime.Unix()),
+		EndTime:       aws.Int64(endTime.Unix()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	queryID := aws.ToString(output.QueryId)
+
+	description := fmt.Sprintf("Waiting for CloudWatch Logs Insights query %s to complete", queryID)
+	maxRetries := int(logsInsightsQueryTimeout / logsInsightsPollInterval)
+	var lastStatus cloudwatchlogstypes.QueryStatus
+	out, err := retry.DoWithRetryInterfaceE(t, description, maxRetries, logsInsightsPollInterval, func() (interface{}, error) {
+		results, err := client.GetQueryResults(context.Background(), &cloudwatchlogs.GetQueryResultsInput{
+			QueryId: aws.String(queryID),
+		})
+		if err != nil {
+			return nil, err
+		}
+		lastStatus = results.Status
+		if err := checkLogsInsightsQueryStatus(queryID, results.Status); err != nil {
+			return nil, err
+		}
+		return results.Results, nil
+	})
+	if err != nil {
+		if _, timedOut := err.(retry.MaxRetriesExceeded); timedOut && lastStatus != "" {
+			// Stop the query, so that it no longer counts against the concurrent query quota of the account
+			client.StopQuery(context.Background(), &cloudwatchlogs.StopQueryInput{QueryId: aws.String(queryID)})
+		}
+		return nil, logsInsightsQueryError(queryID, lastStatus, err)
+	}
+
+	return newCloudWatchLogsInsightsRows(out.([][]cloudwatchlogstypes.ResultField)), nil
+}
+
+// checkLogsInsightsQueryStatus returns nil if the query with the given id and status has completed, a retryable error
+// if it is still running, and a fatal LogsInsightsQueryFailed error if it will never complete.
+func checkLogsInsightsQueryStatus(queryID string, status cloudwatchlogstypes.QueryStatus) error {
+	switch status {
+	case cloudwatchlogstypes.QueryStatusComplete:
+		return nil
+	case cloudwatchlogstypes.QueryStatusScheduled, cloudwatchlogstypes.QueryStatusRunning:
+		return fmt.Errorf("CloudWatch Logs Insights query %s is %s", queryID, status)
+	default:
+		return retry.FatalError{Underlying: LogsInsightsQueryFailed{QueryID: queryID, Status: string(status)}}
+	}
+}
+
+// logsInsightsQueryError converts the given error from waiting for the query with the given id, whose last known status
+// is given, to complete into the error to return: the LogsInsightsQueryFailed error wrapped in a FatalError if the query
+// failed, and a LogsInsightsQueryFailed error with the last status if it did not complete in time. Errors from calling
+// CloudWatch are returned as is.
+func logsInsightsQueryError(queryID string, lastStatus cloudwatchlogstypes.QueryStatus, err error) error {
+	if fatalErr, isFatal := err.(retry.FatalError); isFatal {
+		return fatalErr.Underlying
+	}
+	if _, timedOut := err.(retry.MaxRetriesExceeded); timedOut && lastStatus != "" {
+		return LogsInsightsQueryFailed{QueryID: queryID, Status: string(lastStatus)}
+	}
+	return err
+}
+
+// newCloudWatchLogsInsightsRows converts the given results of a CloudWatch Logs Insights query into rows. The @ptr
+// field, which CloudWatch adds to every row to identify the log event, is left out.
+func newCloudWatchLogsInsightsRows(results [][]cloudwatchlogstypes.ResultField) []CloudWatchLogsInsightsRow {
+	rows := make([]CloudWatchLogsInsightsRow, 0, len(results))
+	for _, fields := range results {
+		row := CloudWatchLogsInsightsRow{}
+		for _, field := range fields {
+			name := aws.ToString(field.Field)
+			if name == "@ptr" {
+				continue
+			}
+			row[name] = aws.ToString(field.Value)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// WaitForCloudWatchLogMessage waits until an event matching the given filter pattern is logged in the given log group
+// at or after the given time, and returns the message of the first such event.
+func WaitForCloudWatchLogMessage(t testing.TestingT, awsRegion string, logGroupName string, filterPattern string, since time.Time, maxRetries int, sleepBetweenRetries time.Duration) string {
+	out, err := WaitForCloudWatchLogMessageE(t, awsRegion, logGroupName, filterPattern, since, maxRetries, sleepBetweenRetries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// WaitForCloudWatchLogMessageE waits until an event matching the given filter pattern is logged in the given log group
+// at or after the given time, and returns the message of the first such event. The filter pattern uses the CloudWatch
+// Logs filter pattern syntax (e.g. "ERROR" or { $.level = "error" }) and is applied by CloudWatch across all the
+// streams of the log group, so only matching events are transferred.
+func WaitForCloudWatchLogMessageE(t testing.TestingT, awsRegion string, logGroupName string, filterPattern string, since time.Time, maxRetries int, sleepBetweenRetries time.Duration) (string, error) {
+	client, err := NewCloudWatchLogsClientE(t, awsRegion)
+	if err != nil {
+		return "", err
+	}
+
+	description := fmt.Sprintf("Waiting for a message matching %s in CloudWatch log group %s", filterPattern, logGroupName)
+	return retry.DoWithRetryE(t, description, maxRetries, sleepBetweenRetries, func() (string, error) {
+		paginator := cloudwatchlogs.NewFilterLogEventsPaginator(client, &cloudwatchlogs.FilterLogEventsInput{
+			LogGroupName:  aws.String(logGroupName),
+			FilterPattern: aws.String(filterPattern),
+			StartTime:     aws.Int64(since.UnixMilli()),
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(context.Background())
+			if err != nil {
+				return "", err
+			}
+			if len(page.Events) > 0 {
+				return aws.ToString(page.Events[0].Message), nil
+			}
+		}
+		return "", fmt.Errorf("no message matching %s found in CloudWatch log group %s", filterPattern, logGroupName)
+	})
+}
+
 // NewCloudWatchLogsClient creates a new CloudWatch Logs client.
 func NewCloudWatchLogsClient(t testing.TestingT, region string) *cloudwatchlogs.Client {
 	client, err := NewCloudWatchLogsClientE(t, region)
@@ -52,7 +610,7 @@ func NewCloudWatchLogsClient(t testing.TestingT, region string) *cloudwatchlogs.
 
 // NewCloudWatchLogsClientE creates a new CloudWatch Logs client.
 func NewCloudWatchLogsClientE(t testing.TestingT, region string) (*cloudwatchlogs.Client, error) {
-	sess, err := NewAuthenticatedSession(region)
+	sess, err := newAuthenticatedSession(t, region)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,149 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	cloudwatchlogstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCloudWatchDimensions(t *testing.T) {
+	t.Parallel()
+
+	dimensions := newCloudWatchDimensions(map[string]string{"InstanceId": "i-123", "AutoScalingGroupName": "asg"})
+
+	assert.Equal(t, []types.Dimension{
+		{Name: aws.String("AutoScalingGroupName"), Value: aws.String("asg")},
+		{Name: aws.String("InstanceId"), Value: aws.String("i-123")},
+	}, dimensions)
+}
+
+func TestNewCloudWatchDatapointsSortsByTimestamp(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	datapoints := newCloudWatchDatapoints([]types.Datapoint{
+		{Timestamp: aws.Time(start.Add(time.Minute)), Sum: aws.Float64(2), Unit: types.StandardUnitCount},
+		{Timestamp: aws.Time(start), Sum: aws.Float64(1), Unit: types.StandardUnitCount},
+	})
+
+	assert.Len(t, datapoints, 2)
+	assert.Equal(t, start, datapoints[0].Timestamp)
+	assert.Equal(t, float64(1), datapoints[0].Sum)
+	assert.Equal(t, float64(2), datapoints[1].Sum)
+	assert.Equal(t, "Count", datapoints[1].Unit)
+}
+
+func TestMergeCloudWatchMetricDataResults(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	results := mergeCloudWatchMetricDataResults([]types.MetricDataResult{
+		{Id: aws.String("errors"), Label: aws.String("Errors"), Timestamps: []time.Time{start.Add(time.Minute)}, Values: []float64{3}},
+		{Id: aws.String("invocations"), Label: aws.String("Invocations"), Timestamps: []time.Time{start}, Values: []float64{10}},
+		{Id: aws.String("errors"), Timestamps: []time.Time{start}, Values: []float64{1}},
+	})
+
+	assert.Equal(t, []CloudWatchMetricDataResult{
+		{Id: "errors", Label: "Errors", Timestamps: []time.Time{start.Add(time.Minute), start}, Values: []float64{3, 1}},
+		{Id: "invocations", Label: "Invocations", Timestamps: []time.Time{start}, Values: []float64{10}},
+	}, results)
+}
+
+func TestNewCloudWatchAlarm(t *testing.T) {
+	t.Parallel()
+
+	alarm := newCloudWatchAlarm(&types.MetricAlarm{
+		AlarmName:          aws.String("high-latency"),
+		StateValue:         types.StateValueAlarm,
+		Namespace:          aws.String("AWS/ApplicationELB"),
+		MetricName:         aws.String("TargetResponseTime"),
+		Dimensions:         []types.Dimension{{Name: aws.String("LoadBalancer"), Value: aws.String("app/my-lb/123")}},
+		ExtendedStatistic:  aws.String("p99"),
+		Threshold:          aws.Float64(1.5),
+		ComparisonOperator: types.ComparisonOperatorGreaterThanThreshold,
+		EvaluationPeriods:  aws.Int32(3),
+	})
+
+	assert.Equal(t, "high-latency", alarm.Name)
+	assert.Equal(t, types.StateValueAlarm, alarm.State)
+	assert.Equal(t, map[string]string{"LoadBalancer": "app/my-lb/123"}, alarm.Dimensions)
+	assert.Equal(t, "p99", alarm.Statistic)
+	assert.Equal(t, 1.5, alarm.Threshold)
+	assert.Equal(t, "GreaterThanThreshold", alarm.ComparisonOperator)
+	assert.Equal(t, int32(3), alarm.EvaluationPeriods)
+}
+
+func TestCloudWatchAlarmMatchesResource(t *testing.T) {
+	t.Parallel()
+
+	metricAlarm := &types.MetricAlarm{
+		Namespace:  aws.String("AWS/EC2"),
+		Dimensions: []types.Dimension{{Name: aws.String("InstanceId"), Value: aws.String("i-123")}},
+	}
+	mathAlarm := &types.MetricAlarm{
+		Metrics: []types.MetricDataQuery{
+			{Id: aws.String("e1"), Expression: aws.String("m1 * 100")},
+			{Id: aws.String("m1"), MetricStat: &types.MetricStat{Metric: &types.Metric{
+				Namespace:  aws.String("AWS/EC2"),
+				Dimensions: []types.Dimension{{Name: aws.String("InstanceId"), Value: aws.String("i-123")}},
+			}}},
+		},
+	}
+
+	assert.True(t, cloudWatchAlarmMatchesResource(metricAlarm, "AWS/EC2", "InstanceId", "i-123"))
+	assert.False(t, cloudWatchAlarmMatchesResource(metricAlarm, "AWS/EC2", "InstanceId", "i-456"))
+	assert.False(t, cloudWatchAlarmMatchesResource(metricAlarm, "AWS/RDS", "InstanceId", "i-123"))
+	assert.True(t, cloudWatchAlarmMatchesResource(mathAlarm, "AWS/EC2", "InstanceId", "i-123"))
+}
+
+func TestNewCloudWatchLogsInsightsRows(t *testing.T) {
+	t.Parallel()
+
+	rows := newCloudWatchLogsInsightsRows([][]cloudwatchlogstypes.ResultField{
+		{
+			{Field: aws.String("@timestamp"), Value: aws.String("2024-01-01 00:00:00.000")},
+			{Field: aws.String("@message"), Value: aws.String("ERROR something broke")},
+			{Field: aws.String("@ptr"), Value: aws.String("CmAKJwoj")},
+		},
+		{
+			{Field: aws.String("count()"), Value: aws.String("42")},
+		},
+	})
+
+	assert.Equal(t, []CloudWatchLogsInsightsRow{
+		{"@timestamp": "2024-01-01 00:00:00.000", "@message": "ERROR something broke"},
+		{"count()": "42"},
+	}, rows)
+}
+
+func TestCheckLogsInsightsQueryStatus(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, checkLogsInsightsQueryStatus("query", cloudwatchlogstypes.QueryStatusComplete))
+
+	err := checkLogsInsightsQueryStatus("query", cloudwatchlogstypes.QueryStatusRunning)
+	assert.Error(t, err)
+	_, isFatal := err.(retry.FatalError)
+	assert.False(t, isFatal)
+
+	err = checkLogsInsightsQueryStatus("query", cloudwatchlogstypes.QueryStatusFailed)
+	assert.Equal(t, retry.FatalError{Underlying: LogsInsightsQueryFailed{QueryID: "query", Status: "Failed"}}, err)
+}
+
+func TestLogsInsightsQueryError(t *testing.T) {
+	t.Parallel()
+
+	failed := LogsInsightsQueryFailed{QueryID: "query", Status: "Failed"}
+	assert.Equal(t, failed, logsInsightsQueryError("query", cloudwatchlogstypes.QueryStatusFailed, retry.FatalError{Underlying: failed}))
+
+	timedOut := retry.MaxRetriesExceeded{Description: "Waiting for query", MaxRetries: 300}
+	assert.Equal(t, LogsInsightsQueryFailed{QueryID: "query", Status: "Running"}, logsInsightsQueryError("query", cloudwatchlogstypes.QueryStatusRunning, timedOut))
+
+	// Without a status, the query results could never be fetched, so there is no status of the query to report
+	assert.Equal(t, timedOut, logsInsightsQueryError("query", "", timedOut))
+}
@@ -0,0 +1,198 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider/types"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// CognitoUserPool contains the sign-in, password and MFA settings of an Amazon Cognito user pool.
+type CognitoUserPool struct {
+	Id                            string   // The ID of the user pool
+	Arn                           string   // The ARN of the user pool
+	Name                          string   // The name of the user pool
+	MfaConfiguration              string   // Whether MFA is required (OFF, ON or OPTIONAL)
+	AutoVerifiedAttributes        []string // The attributes that are verified automatically (e.g. email, phone_number)
+	UsernameAttributes            []string // The attributes that can be used as username at sign-in. Empty if users sign in with a username.
+	DeletionProtection            string   // Whether deletion protection is enabled (ACTIVE or INACTIVE)
+	PasswordMinimumLength         int32    // The minimum length of passwords
+	PasswordRequireUppercase      bool     // Whether passwords must contain an uppercase letter
+	PasswordRequireLowercase      bool     // Whether passwords must contain a lowercase letter
+	PasswordRequireNumbers        bool     // Whether passwords must contain a number
+	PasswordRequireSymbols        bool     // Whether passwords must contain a symbol
+	TemporaryPasswordValidityDays int32    // The number of days a temporary password set by an administrator is valid
+}
+
+// CognitoUserPoolClient contains the authentication flow settings of an Amazon Cognito user pool app client. The client
+// secret is intentionally left out; use GetCognitoUserPoolClientSecret to fetch it.
+type CognitoUserPoolClient struct {
+	Id                         string   // The ID of the app client
+	Name                       string   // The name of the app client
+	UserPoolId                 string   // The ID of the user pool the app client belongs to
+	HasClientSecret            bool     // Whether the app client has a client secret
+	ExplicitAuthFlows          []string // The authentication flows the app client supports (e.g. ALLOW_USER_SRP_AUTH)
+	AllowedOAuthFlowsEnabled   bool     // Whether the app client can use the OAuth flows in AllowedOAuthFlows
+	AllowedOAuthFlows          []string // The OAuth flows the app client can use (code, implicit or client_credentials)
+	AllowedOAuthScopes         []string // The OAuth scopes the app client can request
+	CallbackURLs               []string // The URLs users can be redirected to after signing in
+	LogoutURLs                 []string // The URLs users can be redirected to after signing out
+	SupportedIdentityProviders []string // The identity providers the app client supports (e.g. COGNITO)
+}
+
+// GetCognitoUserPool fetches the sign-in, password and MFA settings of the Cognito user pool with the given ID.
+func GetCognitoUserPool(t testing.TestingT, region string, poolID string) *CognitoUserPool {
+	pool, err := GetCognitoUserPoolE(t, region, poolID)
+	require.NoError(t, err)
+	return pool
+}
+
+// GetCognitoUserPoolE fetches the sign-in, password and MFA settings of the Cognito user pool with the given ID.
+// Returns a NotFoundError if the user pool does not exist.
+func GetCognitoUserPoolE(t testing.TestingT, region string, poolID string) (*CognitoUserPool, error) {
+	client, err := NewCognitoIdentityProviderClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.DescribeUserPool(context.Background(), &cognitoidentityprovider.DescribeUserPoolInput{
+		UserPoolId: aws.String(poolID),
+	})
+	if err != nil {
+		return nil, notFoundOr[*types.ResourceNotFoundException](err, "Cognito user pool", poolID, region)
+	}
+	if output.UserPool == nil {
+		return nil, NewNotFoundError("Cognito user pool", poolID, region)
+	}
+
+	return newCognitoUserPool(*output.UserPool), nil
+}
+
+// GetCognitoUserPoolClient fetches the authentication flow settings of the given app client of the given Cognito user
+// pool.
+func GetCognitoUserPoolClient(t testing.TestingT, region string, poolID string, clientID string) *CognitoUserPoolClient {
+	poolClient, err := GetCognitoUserPoolClientE(t, region, poolID, clientID)
+	require.NoError(t, err)
+	return poolClient
+}
+
+// GetCognitoUserPoolClientE fetches the authentication flow settings of the given app client of the given Cognito user
+// pool. Returns a NotFoundError if the user pool or the app client does not exist.
+func GetCognitoUserPoolClientE(t testing.TestingT, region string, poolID string, clientID string) (*CognitoUserPoolClient, error) {
+	poolClient, err := describeCognitoUserPoolClientE(t, region, poolID, clientID)
+	if err != nil {
+		return nil, err
+	}
+	return newCognitoUserPoolClient(*poolClient), nil
+}
+
+// GetCognitoUserPoolClientSecret fetches the client secret of the given app client of the given Cognito user pool.
+// Neither the secret nor the response it is read from is logged.
+func GetCognitoUserPoolClientSecret(t testing.TestingT, region string, poolID string, clientID string) string {
+	secret, err := GetCognitoUserPoolClientSecretE(t, region, poolID, clientID)
+	require.NoError(t, err)
+	return secret
+}
+
+// GetCognitoUserPoolClientSecretE fetches the client secret of the given app client of the given Cognito user pool.
+// Neither the secret nor the response it is read from is logged. Returns an empty string if the app client has no
+// secret.
+func GetCognitoUserPoolClientSecretE(t testing.TestingT, region string, poolID string, clientID string) (string, error) {
+	poolClient, err := describeCognitoUserPoolClientE(t, region, poolID, clientID)
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(poolClient.ClientSecret), nil
+}
+
+func describeCognitoUserPoolClientE(t testing.TestingT, region string, poolID string, clientID string) (*types.UserPoolClientType, error) {
+	client, err := NewCognitoIdentityProviderClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.DescribeUserPoolClient(context.Background(), &cognitoidentityprovider.DescribeUserPoolClientInput{
+		UserPoolId: aws.String(poolID),
+		ClientId:   aws.String(clientID),
+	})
+	if err != nil {
+		return nil, notFoundOr[*types.ResourceNotFoundException](err, "Cognito user pool client", clientID, region)
+	}
+	if output.UserPoolClient == nil {
+		return nil, NewNotFoundError("Cognito user pool client", clientID, region)
+	}
+
+	return output.UserPoolClient, nil
+}
+
+func newCognitoUserPool(pool types.UserPoolType) *CognitoUserPool {
+	result := &CognitoUserPool{
+		Id:                 aws.ToString(pool.Id),
+		Arn:                aws.ToString(pool.Arn),
+		Name:               aws.ToString(pool.Name),
+		MfaConfiguration:   string(pool.MfaConfiguration),
+		DeletionProtection: string(pool.DeletionProtection),
+	}
+
+	for _, attribute := range pool.AutoVerifiedAttributes {
+		result.AutoVerifiedAttributes = append(result.AutoVerifiedAttributes, string(attribute))
+	}
+	for _, attribute := range pool.UsernameAttributes {
+		result.UsernameAttributes = append(result.UsernameAttributes, string(attribute))
+	}
+
+	if pool.Policies != nil && pool.Policies.PasswordPolicy != nil {
+		passwordPolicy := pool.Policies.PasswordPolicy
+		result.PasswordMinimumLength = aws.ToInt32(passwordPolicy.MinimumLength)
+		result.PasswordRequireUppercase = passwordPolicy.RequireUppercase
+		result.PasswordRequireLowercase = passwordPolicy.RequireLowercase
+		result.PasswordRequireNumbers = passwordPolicy.RequireNumbers
+		result.PasswordRequireSymbols = passwordPolicy.RequireSymbols
+		result.TemporaryPasswordValidityDays = passwordPolicy.TemporaryPasswordValidityDays
+	}
+
+	return result
+}
+
+func newCognitoUserPoolClient(poolClient types.UserPoolClientType) *CognitoUserPoolClient {
+	result := &CognitoUserPoolClient{
+		Id:                         aws.ToString(poolClient.ClientId),
+		Name:                       aws.ToString(poolClient.ClientName),
+		UserPoolId:                 aws.ToString(poolClient.UserPoolId),
+		HasClientSecret:            aws.ToString(poolClient.ClientSecret) != "",
+		AllowedOAuthFlowsEnabled:   aws.ToBool(poolClient.AllowedOAuthFlowsUserPoolClient),
+		AllowedOAuthScopes:         poolClient.AllowedOAuthScopes,
+		CallbackURLs:               poolClient.CallbackURLs,
+		LogoutURLs:                 poolClient.LogoutURLs,
+		SupportedIdentityProviders: poolClient.SupportedIdentityProviders,
+	}
+
+	for _, flow := range poolClient.ExplicitAuthFlows {
+		result.ExplicitAuthFlows = append(result.ExplicitAuthFlows, string(flow))
+	}
+	for _, flow := range poolClient.AllowedOAuthFlows {
+		result.AllowedOAuthFlows = append(result.AllowedOAuthFlows, string(flow))
+	}
+
+	return result
+}
+
+// NewCognitoIdentityProviderClient creates a new Cognito user pools client.
+func NewCognitoIdentityProviderClient(t testing.TestingT, region string) *cognitoidentityprovider.Client {
+	client, err := NewCognitoIdentityProviderClientE(t, region)
+	require.NoError(t, err)
+	return client
+}
+
+// NewCognitoIdentityProviderClientE creates a new Cognito user pools client.
+func NewCognitoIdentityProviderClientE(t testing.TestingT, region string) (*cognitoidentityprovider.Client, error) {
+	sess, err := newAuthenticatedSession(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return cognitoidentityprovider.NewFromConfig(*sess), nil
+}
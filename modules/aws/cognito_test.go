@@ -0,0 +1,56 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCognitoUserPool(t *testing.T) {
+	t.Parallel()
+
+	pool := newCognitoUserPool(types.UserPoolType{
+		Id:                     aws.String("us-east-1_abc123"),
+		MfaConfiguration:       types.UserPoolMfaTypeOptional,
+		AutoVerifiedAttributes: []types.VerifiedAttributeType{types.VerifiedAttributeTypeEmail},
+		Policies: &types.UserPoolPolicyType{
+			PasswordPolicy: &types.PasswordPolicyType{
+				MinimumLength:    aws.Int32(12),
+				RequireSymbols:   true,
+				RequireUppercase: true,
+			},
+		},
+	})
+
+	assert.Equal(t, "us-east-1_abc123", pool.Id)
+	assert.Equal(t, "OPTIONAL", pool.MfaConfiguration)
+	assert.Equal(t, []string{"email"}, pool.AutoVerifiedAttributes)
+	assert.Equal(t, int32(12), pool.PasswordMinimumLength)
+	assert.True(t, pool.PasswordRequireSymbols)
+	assert.True(t, pool.PasswordRequireUppercase)
+	assert.False(t, pool.PasswordRequireNumbers)
+}
+
+func TestNewCognitoUserPoolClient(t *testing.T) {
+	t.Parallel()
+
+	poolClient := newCognitoUserPoolClient(types.UserPoolClientType{
+		ClientId:                        aws.String("client123"),
+		ClientSecret:                    aws.String("super-secret"),
+		ExplicitAuthFlows:               []types.ExplicitAuthFlowsType{types.ExplicitAuthFlowsTypeAllowUserSrpAuth},
+		AllowedOAuthFlowsUserPoolClient: aws.Bool(true),
+		AllowedOAuthFlows:               []types.OAuthFlowType{types.OAuthFlowTypeCode},
+		CallbackURLs:                    []string{"https://example.com/callback"},
+	})
+
+	assert.Equal(t, "client123", poolClient.Id)
+	assert.True(t, poolClient.HasClientSecret)
+	assert.NotContains(t, fmt.Sprintf("%+v", poolClient), "super-secret")
+	assert.Equal(t, []string{"ALLOW_USER_SRP_AUTH"}, poolClient.ExplicitAuthFlows)
+	assert.True(t, poolClient.AllowedOAuthFlowsEnabled)
+	assert.Equal(t, []string{"code"}, poolClient.AllowedOAuthFlows)
+	assert.Equal(t, []string{"https://example.com/callback"}, poolClient.CallbackURLs)
+}
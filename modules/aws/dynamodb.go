@@ -2,10 +2,13 @@ package aws
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/require"
 )
@@ -80,6 +83,312 @@ func GetDynamoDBTableE(t testing.TestingT, region string, tableName string) (*ty
 	return out.Table, err
 }
 
+// PutDynamoDbItem writes the given item to the given dynamoDB table, replacing any existing item with the same key. This
+// will fail the test if there are any errors.
+func PutDynamoDbItem(t testing.TestingT, region string, tableName string, item map[string]types.AttributeValue) {
+	err := PutDynamoDbItemE(t, region, tableName, item)
+	require.NoError(t, err)
+}
+
+// PutDynamoDbItemE writes the given item to the given dynamoDB table, replacing any existing item with the same key.
+func PutDynamoDbItemE(t testing.TestingT, region string, tableName string, item map[string]types.AttributeValue) error {
+	client, err := NewDynamoDBClientE(t, region)
+	if err != nil {
+		return err
+	}
+	_, err = client.PutItem(context.Background(), &dynamodb.PutItemInput{
+		TableName: aws.String(tableName),
+		Item:      item,
+	})
+	return err
+}
+
+// GetDynamoDbItem reads the item with the given key from the given dynamoDB table. This will fail the test if there
+// are any errors or there is no such item.
+func GetDynamoDbItem(t testing.TestingT, region string, tableName string, key map[string]types.AttributeValue) map[string]types.AttributeValue {
+	item, err := GetDynamoDbItemE(t, region, tableName, key)
+	require.NoError(t, err)
+	return item
+}
+
+// GetDynamoDbItemE reads the item with the given key from the given dynamoDB table, using a strongly consistent read
+// so that items written earlier in the test are always returned. Returns a NotFoundError if there is no such item.
+func GetDynamoDbItemE(t testing.TestingT, region string, tableName string, key map[string]types.AttributeValue) (map[string]types.AttributeValue, error) {
+	client, err := NewDynamoDBClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+	out, err := client.GetItem(context.Background(), &dynamodb.GetItemInput{
+		TableName:      aws.String(tableName),
+		Key:            key,
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out.Item == nil {
+		return nil, NewNotFoundError("DynamoDB item", fmt.Sprintf("%v in table %s", key, tableName), region)
+	}
+	return out.Item, nil
+}
+
+// DeleteDynamoDbItem deletes the item with the given key from the given dynamoDB table. This will fail the test if
+// there are any errors.
+func DeleteDynamoDbItem(t testing.TestingT, region string, tableName string, key map[string]types.AttributeValue) {
+	err := DeleteDynamoDbItemE(t, region, tableName, key)
+	require.NoError(t, err)
+}
+
+// DeleteDynamoDbItemE deletes the item with the given key from the given dynamoDB table. Deleting an item that does not
+// exist is not an error.
+func DeleteDynamoDbItemE(t testing.TestingT, region string, tableName string, key map[string]types.AttributeValue) error {
+	client, err := NewDynamoDBClientE(t, region)
+	if err != nil {
+		return err
+	}
+	_, err = client.DeleteItem(context.Background(), &dynamodb.DeleteItemInput{
+		TableName: aws.String(tableName),
+		Key:       key,
+	})
+	return err
+}
+
+// QueryDynamoDbTable returns all the items of the given dynamoDB table that match the given key condition expression.
+// This will fail the test if there are any errors.
+func QueryDynamoDbTable(t testing.TestingT, region string, tableName string, keyConditionExpression string, expressionAttributeValues map[string]types.AttributeValue) []map[string]types.AttributeValue {
+	items, err := QueryDynamoDbTableE(t, region, tableName, keyConditionExpression, expressionAttributeValues)
+	require.NoError(t, err)
+	return items
+}
+
+// QueryDynamoDbTableE returns all the items of the given dynamoDB table that match the given key condition expression,
+// e.g. "pk = :pk AND begins_with(sk, :prefix)" with the values of :pk and :prefix in expressionAttributeValues. The
+// items are read with strongly consistent reads and gathered from all the pages of the results.
+func QueryDynamoDbTableE(t testing.TestingT, region string, tableName string, keyConditionExpression string, expressionAttributeValues map[string]types.AttributeValue) ([]map[string]types.AttributeValue, error) {
+	return queryDynamoDbE(t, region, &dynamodb.QueryInput{
+		TableName:                 aws.String(tableName),
+		KeyConditionExpression:    aws.String(keyConditionExpression),
+		ExpressionAttributeValues: expressionAttributeValues,
+		ConsistentRead:            aws.Bool(true),
+	})
+}
+
+// QueryDynamoDbIndex returns all the items of the given index of the given dynamoDB table that match the given key
+// condition expression. This will fail the test if there are any errors.
+func QueryDynamoDbIndex(t testing.TestingT, region string, tableName string, indexName string, keyConditionExpression string, expressionAttributeValues map[string]types.AttributeValue) []map[string]types.AttributeValue {
+	items, err := QueryDynamoDbIndexE(t, region, tableName, indexName, keyConditionExpression, expressionAttributeValues)
+	require.NoError(t, err)
+	return items
+}
+
+// QueryDynamoDbIndexE returns all the items of the given index of the given dynamoDB table that match the given key
+// condition expression, gathered from all the pages of the results. Note that global secondary indexes are eventually
+// consistent, so items written just before the query may be missing; retry the query if needed.
+func QueryDynamoDbIndexE(t testing.TestingT, region string, tableName string, indexName string, keyConditionExpression string, expressionAttributeValues map[string]types.AttributeValue) ([]map[string]types.AttributeValue, error) {
+	return queryDynamoDbE(t, region, &dynamodb.QueryInput{
+		TableName:                 aws.String(tableName),
+		IndexName:                 aws.String(indexName),
+		KeyConditionExpression:    aws.String(keyConditionExpression),
+		ExpressionAttributeValues: expressionAttributeValues,
+	})
+}
+
+// queryDynamoDbE runs the given query and returns the items of all the pages of the results.
+func queryDynamoDbE(t testing.TestingT, region string, input *dynamodb.QueryInput) ([]map[string]types.AttributeValue, error) {
+	client, err := NewDynamoDBClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []map[string]types.AttributeValue{}
+	paginator := dynamodb.NewQueryPaginator(client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page.Items...)
+	}
+	return items, nil
+}
+
+// WaitForDynamoDbTableActive waits until the given dynamoDB table and all its global secondary indexes are active,
+// retrying the check for the specified amount of times, sleeping for the provided duration between each try. This
+// will fail the test if the table does not become active in time.
+func WaitForDynamoDbTableActive(t testing.TestingT, region string, tableName string, maxRetries int, sleepBetweenRetries time.Duration) {
+	err := WaitForDynamoDbTableActiveE(t, region, tableName, maxRetries, sleepBetweenRetries)
+	require.NoError(t, err)
+}
+
+// WaitForDynamoDbTableActiveE waits until the given dynamoDB table and all its global secondary indexes are active,
+// retrying the check for the specified amount of times, sleeping for the provided duration between each try. Indexes
+// that are still being created or backfilled cannot be queried yet, even when the table itself is active.
+func WaitForDynamoDbTableActiveE(t testing.TestingT, region string, tableName string, maxRetries int, sleepBetweenRetries time.Duration) error {
+	description := fmt.Sprintf("Waiting for DynamoDB table %s to be active", tableName)
+	_, err := retry.DoWithRetryE(t, description, maxRetries, sleepBetweenRetries, func() (string, error) {
+		table, err := GetDynamoDBTableE(t, region, tableName)
+		if err != nil {
+			return "", err
+		}
+		return "", checkDynamoDbTableActive(table)
+	})
+	return err
+}
+
+// checkDynamoDbTableActive returns an error if the given table or any of its global secondary indexes is not active.
+func checkDynamoDbTableActive(table *types.TableDescription) error {
+	tableName := aws.ToString(table.TableName)
+	if table.TableStatus != types.TableStatusActive {
+		return fmt.Errorf("DynamoDB table %s is in status %s", tableName, table.TableStatus)
+	}
+	for _, index := range table.GlobalSecondaryIndexes {
+		if index.IndexStatus != types.IndexStatusActive || aws.ToBool(index.Backfilling) {
+			return fmt.Errorf("global secondary index %s of DynamoDB table %s is in status %s", aws.ToString(index.IndexName), tableName, index.IndexStatus)
+		}
+	}
+	return nil
+}
+
+// AssertDynamoDbTableKeySchema checks that the given dynamoDB table has the given partition key and sort key, and fails
+// the test if it does not. Use an empty sort key for tables without a sort key.
+func AssertDynamoDbTableKeySchema(t testing.TestingT, region string, tableName string, partitionKey string, sortKey string) {
+	err := AssertDynamoDbTableKeySchemaE(t, region, tableName, partitionKey, sortKey)
+	require.NoError(t, err)
+}
+
+// AssertDynamoDbTableKeySchemaE checks that the given dynamoDB table has the given partition key and sort key, and
+// returns an UnexpectedDynamoDbTableConfiguration error if it does not. Use an empty sort key for tables without a sort
+// key.
+func AssertDynamoDbTableKeySchemaE(t testing.TestingT, region string, tableName string, partitionKey string, sortKey string) error {
+	table, err := GetDynamoDBTableE(t, region, tableName)
+	if err != nil {
+		return err
+	}
+	return checkDynamoDbKeySchema(tableName, "key schema", table.KeySchema, partitionKey, sortKey)
+}
+
+// AssertDynamoDbGlobalSecondaryIndex checks that the given dynamoDB table has an active global secondary index with
+// the given name, partition key and sort key, and fails the test if it does not.
+func AssertDynamoDbGlobalSecondaryIndex(t testing.TestingT, region string, tableName string, indexName string, partitionKey string, sortKey string) {
+	err := AssertDynamoDbGlobalSecondaryIndexE(t, region, tableName, indexName, partitionKey, sortKey)
+	require.NoError(t, err)
+}
+
+// AssertDynamoDbGlobalSecondaryIndexE checks that the given dynamoDB table has an active global secondary index with
+// the given name, partition key and sort key. Returns a NotFoundError if there is no such index, and an
+// UnexpectedDynamoDbTableConfiguration error if it is not active or has a different key schema.
+func AssertDynamoDbGlobalSecondaryIndexE(t testing.TestingT, region string, tableName string, indexName string, partitionKey string, sortKey string) error {
+	table, err := GetDynamoDBTableE(t, region, tableName)
+	if err != nil {
+		return err
+	}
+
+	for _, index := range table.GlobalSecondaryIndexes {
+		if aws.ToString(index.IndexName) != indexName {
+			continue
+		}
+		if index.IndexStatus != types.IndexStatusActive {
+			return UnexpectedDynamoDbTableConfiguration{TableName: tableName, Setting: fmt.Sprintf("status of index %s", indexName), Expected: string(types.IndexStatusActive), Actual: string(index.IndexStatus)}
+		}
+		return checkDynamoDbKeySchema(tableName, fmt.Sprintf("key schema of index %s", indexName), index.KeySchema, partitionKey, sortKey)
+	}
+	return NewNotFoundError("DynamoDB global secondary index", fmt.Sprintf("%s of table %s", indexName, tableName), region)
+}
+
+// AssertDynamoDbTableTimeToLiveEnabled checks that time to live is enabled on the given attribute of the given dynamoDB
+// table, and fails the test if it is not.
+func AssertDynamoDbTableTimeToLiveEnabled(t testing.TestingT, region string, tableName string, attributeName string) {
+	err := AssertDynamoDbTableTimeToLiveEnabledE(t, region, tableName, attributeName)
+	require.NoError(t, err)
+}
+
+// AssertDynamoDbTableTimeToLiveEnabledE checks that time to live is enabled on the given attribute of the given
+// dynamoDB table, and returns an UnexpectedDynamoDbTableConfiguration error if it is not.
+func AssertDynamoDbTableTimeToLiveEnabledE(t testing.TestingT, region string, tableName string, attributeName string) error {
+	ttl, err := GetDynamoDBTableTimeToLiveE(t, region, tableName)
+	if err != nil {
+		return err
+	}
+	return checkDynamoDbTimeToLive(tableName, ttl, attributeName)
+}
+
+// AssertDynamoDbTableStreamEnabled checks that the given dynamoDB table has a stream with the given view type, and
+// fails the test if it does not.
+func AssertDynamoDbTableStreamEnabled(t testing.TestingT, region string, tableName string, viewType types.StreamViewType) {
+	err := AssertDynamoDbTableStreamEnabledE(t, region, tableName, viewType)
+	require.NoError(t, err)
+}
+
+// AssertDynamoDbTableStreamEnabledE checks that the given dynamoDB table has a stream with the given view type (e.g.
+// NEW_AND_OLD_IMAGES), and returns an UnexpectedDynamoDbTableConfiguration error if it does not.
+func AssertDynamoDbTableStreamEnabledE(t testing.TestingT, region string, tableName string, viewType types.StreamViewType) error {
+	table, err := GetDynamoDBTableE(t, region, tableName)
+	if err != nil {
+		return err
+	}
+	return checkDynamoDbStream(tableName, table.StreamSpecification, viewType)
+}
+
+// checkDynamoDbKeySchema returns an UnexpectedDynamoDbTableConfiguration error if the given key schema, described by
+// the given setting name, does not have the given partition and sort keys.
+func checkDynamoDbKeySchema(tableName string, setting string, keySchema []types.KeySchemaElement, partitionKey string, sortKey string) error {
+	actualPartitionKey, actualSortKey := "", ""
+	for _, element := range keySchema {
+		switch element.KeyType {
+		case types.KeyTypeHash:
+			actualPartitionKey = aws.ToString(element.AttributeName)
+		case types.KeyTypeRange:
+			actualSortKey = aws.ToString(element.AttributeName)
+		}
+	}
+
+	if actualPartitionKey == partitionKey && actualSortKey == sortKey {
+		return nil
+	}
+	return UnexpectedDynamoDbTableConfiguration{
+		TableName: tableName,
+		Setting:   setting,
+		Expected:  fmt.Sprintf("partition key %q, sort key %q", partitionKey, sortKey),
+		Actual:    fmt.Sprintf("partition key %q, sort key %q", actualPartitionKey, actualSortKey),
+	}
+}
+
+// checkDynamoDbTimeToLive returns an UnexpectedDynamoDbTableConfiguration error if the given time to live
+// configuration is not enabled on the given attribute.
+func checkDynamoDbTimeToLive(tableName string, ttl *types.TimeToLiveDescription, attributeName string) error {
+	actual := string(types.TimeToLiveStatusDisabled)
+	if ttl != nil {
+		actual = fmt.Sprintf("%s on %q", ttl.TimeToLiveStatus, aws.ToString(ttl.AttributeName))
+		if ttl.TimeToLiveStatus == types.TimeToLiveStatusEnabled && aws.ToString(ttl.AttributeName) == attributeName {
+			return nil
+		}
+	}
+	return UnexpectedDynamoDbTableConfiguration{
+		TableName: tableName,
+		Setting:   "time to live",
+		Expected:  fmt.Sprintf("%s on %q", types.TimeToLiveStatusEnabled, attributeName),
+		Actual:    actual,
+	}
+}
+
+// checkDynamoDbStream returns an UnexpectedDynamoDbTableConfiguration error if the given stream specification is not
+// enabled with the given view type.
+func checkDynamoDbStream(tableName string, stream *types.StreamSpecification, viewType types.StreamViewType) error {
+	actual := "disabled"
+	if stream != nil && aws.ToBool(stream.StreamEnabled) {
+		if stream.StreamViewType == viewType {
+			return nil
+		}
+		actual = string(stream.StreamViewType)
+	}
+	return UnexpectedDynamoDbTableConfiguration{
+		TableName: tableName,
+		Setting:   "stream",
+		Expected:  string(viewType),
+		Actual:    actual,
+	}
+}
+
 // NewDynamoDBClient creates a DynamoDB client.
 func NewDynamoDBClient(t testing.TestingT, region string) *dynamodb.Client {
 	client, err := NewDynamoDBClientE(t, region)
@@ -89,7 +398,7 @@ func NewDynamoDBClient(t testing.TestingT, region string) *dynamodb.Client {
 
 // NewDynamoDBClientE creates a DynamoDB client.
 func NewDynamoDBClientE(t testing.TestingT, region string) (*dynamodb.Client, error) {
-	sess, err := NewAuthenticatedSession(region)
+	sess, err := newAuthenticatedSession(t, region)
 	if err != nil {
 		return nil, err
 	}
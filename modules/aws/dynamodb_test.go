@@ -0,0 +1,70 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckDynamoDbTableActive(t *testing.T) {
+	t.Parallel()
+
+	table := &types.TableDescription{
+		TableName:   aws.String("orders"),
+		TableStatus: types.TableStatusActive,
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{
+			{IndexName: aws.String("by-customer"), IndexStatus: types.IndexStatusActive},
+		},
+	}
+	assert.NoError(t, checkDynamoDbTableActive(table))
+
+	table.GlobalSecondaryIndexes[0].Backfilling = aws.Bool(true)
+	assert.Error(t, checkDynamoDbTableActive(table))
+
+	table.GlobalSecondaryIndexes[0].Backfilling = nil
+	table.TableStatus = types.TableStatusUpdating
+	assert.Error(t, checkDynamoDbTableActive(table))
+}
+
+func TestCheckDynamoDbKeySchema(t *testing.T) {
+	t.Parallel()
+
+	keySchema := []types.KeySchemaElement{
+		{AttributeName: aws.String("pk"), KeyType: types.KeyTypeHash},
+		{AttributeName: aws.String("sk"), KeyType: types.KeyTypeRange},
+	}
+	assert.NoError(t, checkDynamoDbKeySchema("orders", "key schema", keySchema, "pk", "sk"))
+
+	err := checkDynamoDbKeySchema("orders", "key schema", keySchema, "pk", "")
+	assert.Equal(t, UnexpectedDynamoDbTableConfiguration{
+		TableName: "orders",
+		Setting:   "key schema",
+		Expected:  `partition key "pk", sort key ""`,
+		Actual:    `partition key "pk", sort key "sk"`,
+	}, err)
+}
+
+func TestCheckDynamoDbTimeToLive(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, checkDynamoDbTimeToLive("orders", &types.TimeToLiveDescription{
+		AttributeName:    aws.String("expires_at"),
+		TimeToLiveStatus: types.TimeToLiveStatusEnabled,
+	}, "expires_at"))
+	assert.Error(t, checkDynamoDbTimeToLive("orders", &types.TimeToLiveDescription{
+		AttributeName:    aws.String("ttl"),
+		TimeToLiveStatus: types.TimeToLiveStatusEnabled,
+	}, "expires_at"))
+	assert.Error(t, checkDynamoDbTimeToLive("orders", nil, "expires_at"))
+}
+
+func TestCheckDynamoDbStream(t *testing.T) {
+	t.Parallel()
+
+	stream := &types.StreamSpecification{StreamEnabled: aws.Bool(true), StreamViewType: types.StreamViewTypeNewAndOldImages}
+	assert.NoError(t, checkDynamoDbStream("orders", stream, types.StreamViewTypeNewAndOldImages))
+	assert.Error(t, checkDynamoDbStream("orders", stream, types.StreamViewTypeKeysOnly))
+	assert.Error(t, checkDynamoDbStream("orders", nil, types.StreamViewTypeKeysOnly))
+}
@@ -263,6 +263,47 @@ func GetTagsForEc2InstanceE(t testing.TestingT, region string, instanceID string
 	return tags, nil
 }
 
+// GetInstanceMetadataOptions returns the instance metadata service (IMDS) options, such as HttpTokens,
+// HttpPutResponseHopLimit and HttpEndpoint, of the given EC2 Instance. This is useful to check that IMDSv2 is enforced
+// (i.e. HttpTokens is "required").
+func GetInstanceMetadataOptions(t testing.TestingT, region string, instanceID string) *types.InstanceMetadataOptionsResponse {
+	options, err := GetInstanceMetadataOptionsE(t, region, instanceID)
+	require.NoError(t, err)
+	return options
+}
+
+// GetInstanceMetadataOptionsE returns the instance metadata service (IMDS) options, such as HttpTokens,
+// HttpPutResponseHopLimit and HttpEndpoint, of the given EC2 Instance. Returns a NotFoundError if the instance does not
+// exist and an Ec2InstanceTerminated error if it has been terminated.
+func GetInstanceMetadataOptionsE(t testing.TestingT, region string, instanceID string) (*types.InstanceMetadataOptionsResponse, error) {
+	client, err := NewEc2ClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.DescribeInstances(context.Background(), &ec2.DescribeInstancesInput{InstanceIds: []string{instanceID}})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reservation := range output.Reservations {
+		for _, instance := range reservation.Instances {
+			if aws.ToString(instance.InstanceId) != instanceID {
+				continue
+			}
+			if instance.State != nil && instance.State.Name == types.InstanceStateNameTerminated {
+				return nil, Ec2InstanceTerminated{InstanceId: instanceID, AwsRegion: region}
+			}
+			if instance.MetadataOptions == nil {
+				return nil, NewNotFoundError("EC2 instance metadata options", instanceID, region)
+			}
+			return instance.MetadataOptions, nil
+		}
+	}
+
+	return nil, NewNotFoundError("EC2 instance", instanceID, region)
+}
+
 // DeleteAmi deletes the given AMI in the given region.
 func DeleteAmi(t testing.TestingT, region string, imageID string) {
 	require.NoError(t, DeleteAmiE(t, region, imageID))
@@ -535,7 +576,7 @@ func NewEc2Client(t testing.TestingT, region string) *ec2.Client {
 
 // NewEc2ClientE creates an EC2 client.
 func NewEc2ClientE(t testing.TestingT, region string) (*ec2.Client, error) {
-	sess, err := NewAuthenticatedSession(region)
+	sess, err := newAuthenticatedSession(t, region)
 	if err != nil {
 		return nil, err
 	}
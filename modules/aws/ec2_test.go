@@ -35,6 +35,14 @@ func TestGetEc2InstanceIdsByFilters(t *testing.T) {
 	assert.Equal(t, 0, len(ids))
 }
 
+func TestGetInstanceMetadataOptionsNonexistentInstance(t *testing.T) {
+	t.Parallel()
+
+	region := GetRandomStableRegion(t, nil, nil)
+	_, err := GetInstanceMetadataOptionsE(t, region, "i-00000000000000000")
+	require.Error(t, err)
+}
+
 func TestGetRecommendedInstanceType(t *testing.T) {
 	t.Parallel()
 
@@ -94,7 +94,7 @@ func NewECRClient(t testing.TestingT, region string) *ecr.Client {
 
 // NewECRClientE returns a client for the Elastic Container Registry.
 func NewECRClientE(t testing.TestingT, region string) (*ecr.Client, error) {
-	sess, err := NewAuthenticatedSession(region)
+	sess, err := newAuthenticatedSession(t, region)
 	if err != nil {
 		return nil, err
 	}
@@ -157,7 +157,7 @@ func NewEcsClient(t testing.TestingT, region string) *ecs.Client {
 
 // NewEcsClientE creates an ECS client.
 func NewEcsClientE(t testing.TestingT, region string) (*ecs.Client, error) {
-	sess, err := NewAuthenticatedSession(region)
+	sess, err := newAuthenticatedSession(t, region)
 	if err != nil {
 		return nil, err
 	}
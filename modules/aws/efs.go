@@ -0,0 +1,169 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/efs"
+	"github.com/aws/aws-sdk-go-v2/service/efs/types"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// EfsFileSystem is an Amazon Elastic File System.
+type EfsFileSystem struct {
+	Id                           string  // The ID of the file system
+	Arn                          string  // The ARN of the file system
+	Name                         string  // The value of the Name tag of the file system
+	LifeCycleState               string  // The state of the file system (e.g. available)
+	Encrypted                    bool    // Whether the file system is encrypted at rest
+	KmsKeyId                     string  // The ID of the KMS key used to encrypt the file system
+	PerformanceMode              string  // The performance mode of the file system (generalPurpose or maxIO)
+	ThroughputMode               string  // The throughput mode of the file system (bursting, provisioned or elastic)
+	ProvisionedThroughputInMibps float64 // The provisioned throughput. Only set if ThroughputMode is provisioned.
+	NumberOfMountTargets         int32   // The number of mount targets of the file system
+	TransitionToIA               string  // The lifecycle policy that moves files to Infrequent Access (e.g. AFTER_30_DAYS). Empty if not set.
+	TransitionToPrimaryStorage   string  // The lifecycle policy that moves files back to primary storage (e.g. AFTER_1_ACCESS). Empty if not set.
+	TransitionToArchive          string  // The lifecycle policy that moves files to Archive storage (e.g. AFTER_90_DAYS). Empty if not set.
+}
+
+// EfsMountTarget is a mount target through which an EFS file system can be reached from a subnet.
+type EfsMountTarget struct {
+	Id                 string // The ID of the mount target
+	FileSystemId       string // The ID of the file system the mount target belongs to
+	SubnetId           string // The ID of the subnet the mount target is in
+	VpcId              string // The ID of the VPC the mount target is in
+	AvailabilityZone   string // The name of the availability zone the mount target is in
+	IpAddress          string // The IPv4 address of the mount target
+	NetworkInterfaceId string // The ID of the network interface of the mount target
+	LifeCycleState     string // The state of the mount target (e.g. available)
+}
+
+// GetEfsFileSystem fetches the encryption, throughput and lifecycle settings of the EFS file system with the given ID
+// in the given region.
+func GetEfsFileSystem(t testing.TestingT, region string, fileSystemID string) *EfsFileSystem {
+	fileSystem, err := GetEfsFileSystemE(t, region, fileSystemID)
+	require.NoError(t, err)
+	return fileSystem
+}
+
+// GetEfsFileSystemE fetches the encryption, throughput and lifecycle settings of the EFS file system with the given ID
+// in the given region. Returns a NotFoundError if the file system does not exist.
+func GetEfsFileSystemE(t testing.TestingT, region string, fileSystemID string) (*EfsFileSystem, error) {
+	client, err := NewEfsClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.DescribeFileSystems(context.Background(), &efs.DescribeFileSystemsInput{
+		FileSystemId: aws.String(fileSystemID),
+	})
+	if err != nil {
+		return nil, notFoundOr[*types.FileSystemNotFound](err, "EFS file system", fileSystemID, region)
+	}
+	if len(output.FileSystems) != 1 {
+		return nil, NewNotFoundError("EFS file system", fileSystemID, region)
+	}
+
+	description := output.FileSystems[0]
+	fileSystem := &EfsFileSystem{
+		Id:                           aws.ToString(description.FileSystemId),
+		Arn:                          aws.ToString(description.FileSystemArn),
+		Name:                         aws.ToString(description.Name),
+		LifeCycleState:               string(description.LifeCycleState),
+		Encrypted:                    aws.ToBool(description.Encrypted),
+		KmsKeyId:                     aws.ToString(description.KmsKeyId),
+		PerformanceMode:              string(description.PerformanceMode),
+		ThroughputMode:               string(description.ThroughputMode),
+		ProvisionedThroughputInMibps: aws.ToFloat64(description.ProvisionedThroughputInMibps),
+		NumberOfMountTargets:         description.NumberOfMountTargets,
+	}
+
+	lifecycle, err := client.DescribeLifecycleConfiguration(context.Background(), &efs.DescribeLifecycleConfigurationInput{
+		FileSystemId: aws.String(fileSystemID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	setEfsLifecyclePolicies(fileSystem, lifecycle.LifecyclePolicies)
+
+	return fileSystem, nil
+}
+
+// setEfsLifecyclePolicies copies the transitions of the given lifecycle policies to the given file system. The EFS API
+// returns each transition as a separate policy, so at most one transition is set on each policy.
+func setEfsLifecyclePolicies(fileSystem *EfsFileSystem, policies []types.LifecyclePolicy) {
+	for _, policy := range policies {
+		if policy.TransitionToIA != "" {
+			fileSystem.TransitionToIA = string(policy.TransitionToIA)
+		}
+		if policy.TransitionToPrimaryStorageClass != "" {
+			fileSystem.TransitionToPrimaryStorage = string(policy.TransitionToPrimaryStorageClass)
+		}
+		if policy.TransitionToArchive != "" {
+			fileSystem.TransitionToArchive = string(policy.TransitionToArchive)
+		}
+	}
+}
+
+// GetEfsMountTargets fetches the mount targets of the EFS file system with the given ID in the given region.
+func GetEfsMountTargets(t testing.TestingT, region string, fileSystemID string) []EfsMountTarget {
+	mountTargets, err := GetEfsMountTargetsE(t, region, fileSystemID)
+	require.NoError(t, err)
+	return mountTargets
+}
+
+// GetEfsMountTargetsE fetches the mount targets of the EFS file system with the given ID in the given region. Returns
+// a NotFoundError if the file system does not exist.
+func GetEfsMountTargetsE(t testing.TestingT, region string, fileSystemID string) ([]EfsMountTarget, error) {
+	client, err := NewEfsClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &efs.DescribeMountTargetsInput{FileSystemId: aws.String(fileSystemID)}
+	mountTargets := []EfsMountTarget{}
+	for {
+		output, err := client.DescribeMountTargets(context.Background(), input)
+		if err != nil {
+			return nil, notFoundOr[*types.FileSystemNotFound](err, "EFS file system", fileSystemID, region)
+		}
+
+		for _, mountTarget := range output.MountTargets {
+			mountTargets = append(mountTargets, EfsMountTarget{
+				Id:                 aws.ToString(mountTarget.MountTargetId),
+				FileSystemId:       aws.ToString(mountTarget.FileSystemId),
+				SubnetId:           aws.ToString(mountTarget.SubnetId),
+				VpcId:              aws.ToString(mountTarget.VpcId),
+				AvailabilityZone:   aws.ToString(mountTarget.AvailabilityZoneName),
+				IpAddress:          aws.ToString(mountTarget.IpAddress),
+				NetworkInterfaceId: aws.ToString(mountTarget.NetworkInterfaceId),
+				LifeCycleState:     string(mountTarget.LifeCycleState),
+			})
+		}
+
+		if output.NextMarker == nil {
+			break
+		}
+		input.Marker = output.NextMarker
+	}
+
+	return mountTargets, nil
+}
+
+// NewEfsClient creates a new EFS client.
+func NewEfsClient(t testing.TestingT, region string) *efs.Client {
+	client, err := NewEfsClientE(t, region)
+	require.NoError(t, err)
+	return client
+}
+
+// NewEfsClientE creates a new EFS client.
+func NewEfsClientE(t testing.TestingT, region string) (*efs.Client, error) {
+	sess, err := newAuthenticatedSession(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return efs.NewFromConfig(*sess), nil
+}
@@ -0,0 +1,22 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/efs/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetEfsLifecyclePolicies(t *testing.T) {
+	t.Parallel()
+
+	fileSystem := &EfsFileSystem{}
+	setEfsLifecyclePolicies(fileSystem, []types.LifecyclePolicy{
+		{TransitionToIA: types.TransitionToIARulesAfter30Days},
+		{TransitionToPrimaryStorageClass: types.TransitionToPrimaryStorageClassRulesAfter1Access},
+	})
+
+	assert.Equal(t, "AFTER_30_DAYS", fileSystem.TransitionToIA)
+	assert.Equal(t, "AFTER_1_ACCESS", fileSystem.TransitionToPrimaryStorage)
+	assert.Empty(t, fileSystem.TransitionToArchive)
+}
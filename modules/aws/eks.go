@@ -0,0 +1,338 @@
+package aws
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// EksCluster is an Amazon Elastic Kubernetes Service cluster.
+type EksCluster struct {
+	Name                     string   // The name of the cluster
+	Arn                      string   // The ARN of the cluster
+	Status                   string   // The status of the cluster (e.g. ACTIVE)
+	Version                  string   // The Kubernetes version of the cluster (e.g. 1.31)
+	PlatformVersion          string   // The EKS platform version of the cluster (e.g. eks.12)
+	Endpoint                 string   // The URL of the Kubernetes API server of the cluster
+	CertificateAuthorityData string   // The base64 encoded certificate of the certificate authority of the cluster
+	RoleArn                  string   // The ARN of the IAM role of the control plane
+	VpcId                    string   // The ID of the VPC of the cluster
+	SubnetIds                []string // The IDs of the subnets of the cluster
+	SecurityGroupIds         []string // The IDs of the security groups of the control plane, without the cluster security group
+	EndpointPublicAccess     bool     // Whether the Kubernetes API server can be reached from the internet
+	EndpointPrivateAccess    bool     // Whether the Kubernetes API server can be reached from the VPC
+}
+
+// EksNodeGroup is a managed node group of an EKS cluster.
+type EksNodeGroup struct {
+	Name           string            // The name of the node group
+	Arn            string            // The ARN of the node group
+	Status         string            // The status of the node group (e.g. ACTIVE)
+	Version        string            // The Kubernetes version of the nodes
+	ReleaseVersion string            // The version of the AMI of the nodes
+	AmiType        string            // The type of the AMI of the nodes (e.g. AL2023_x86_64_STANDARD)
+	CapacityType   string            // The capacity type of the nodes (ON_DEMAND or SPOT)
+	InstanceTypes  []string          // The instance types of the nodes
+	MinSize        int32             // The minimum number of nodes
+	MaxSize        int32             // The maximum number of nodes
+	DesiredSize    int32             // The desired number of nodes
+	SubnetIds      []string          // The IDs of the subnets the nodes are launched in
+	NodeRoleArn    string            // The ARN of the IAM role of the nodes
+	Labels         map[string]string // The Kubernetes labels of the nodes
+}
+
+// EksFargateProfile is a Fargate profile of an EKS cluster, which runs the pods matching its selectors on Fargate.
+type EksFargateProfile struct {
+	Name                string                      // The name of the Fargate profile
+	Arn                 string                      // The ARN of the Fargate profile
+	Status              string                      // The status of the Fargate profile (e.g. ACTIVE)
+	PodExecutionRoleArn string                      // The ARN of the IAM role of the pods
+	SubnetIds           []string                    // The IDs of the subnets the pods are launched in
+	Selectors           []EksFargateProfileSelector // The selectors of the pods that run on Fargate
+}
+
+// EksFargateProfileSelector selects the pods that run on the Fargate profile.
+type EksFargateProfileSelector struct {
+	Namespace string            // The namespace of the pods
+	Labels    map[string]string // The labels the pods must have. Empty to select all the pods of the namespace.
+}
+
+// GetEksCluster fetches the EKS cluster with the given name in the given region.
+func GetEksCluster(t testing.TestingT, region string, name string) *EksCluster {
+	cluster, err := GetEksClusterE(t, region, name)
+	require.NoError(t, err)
+	return cluster
+}
+
+// GetEksClusterE fetches the EKS cluster with the given name in the given region. Returns a NotFoundError if the
+// cluster does not exist.
+func GetEksClusterE(t testing.TestingT, region string, name string) (*EksCluster, error) {
+	client, err := NewEksClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.DescribeCluster(context.Background(), &eks.DescribeClusterInput{Name: aws.String(name)})
+	if err != nil {
+		return nil, notFoundOr[*types.ResourceNotFoundException](err, "EKS cluster", name, region)
+	}
+	if output.Cluster == nil {
+		return nil, NewNotFoundError("EKS cluster", name, region)
+	}
+	return newEksCluster(output.Cluster), nil
+}
+
+// newEksCluster converts the given cluster returned by the EKS API to an EksCluster.
+func newEksCluster(description *types.Cluster) *EksCluster {
+	cluster := &EksCluster{
+		Name:            aws.ToString(description.Name),
+		Arn:             aws.ToString(description.Arn),
+		Status:          string(description.Status),
+		Version:         aws.ToString(description.Version),
+		PlatformVersion: aws.ToString(description.PlatformVersion),
+		Endpoint:        aws.ToString(description.Endpoint),
+		RoleArn:         aws.ToString(description.RoleArn),
+	}
+	if description.CertificateAuthority != nil {
+		cluster.CertificateAuthorityData = aws.ToString(description.CertificateAuthority.Data)
+	}
+	if description.ResourcesVpcConfig != nil {
+		cluster.VpcId = aws.ToString(description.ResourcesVpcConfig.VpcId)
+		cluster.SubnetIds = description.ResourcesVpcConfig.SubnetIds
+		cluster.SecurityGroupIds = description.ResourcesVpcConfig.SecurityGroupIds
+		cluster.EndpointPublicAccess = description.ResourcesVpcConfig.EndpointPublicAccess
+		cluster.EndpointPrivateAccess = description.ResourcesVpcConfig.EndpointPrivateAccess
+	}
+	return cluster
+}
+
+// GetEksNodeGroups fetches the managed node groups of the EKS cluster with the given name in the given region.
+func GetEksNodeGroups(t testing.TestingT, region string, clusterName string) []EksNodeGroup {
+	nodeGroups, err := GetEksNodeGroupsE(t, region, clusterName)
+	require.NoError(t, err)
+	return nodeGroups
+}
+
+// GetEksNodeGroupsE fetches the managed node groups of the EKS cluster with the given name in the given region.
+// Returns a NotFoundError if the cluster does not exist.
+func GetEksNodeGroupsE(t testing.TestingT, region string, clusterName string) ([]EksNodeGroup, error) {
+	client, err := NewEksClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &eks.ListNodegroupsInput{ClusterName: aws.String(clusterName)}
+	nodeGroups := []EksNodeGroup{}
+	for {
+		output, err := client.ListNodegroups(context.Background(), input)
+		if err != nil {
+			return nil, notFoundOr[*types.ResourceNotFoundException](err, "EKS cluster", clusterName, region)
+		}
+
+		for _, name := range output.Nodegroups {
+			description, err := client.DescribeNodegroup(context.Background(), &eks.DescribeNodegroupInput{
+				ClusterName:   aws.String(clusterName),
+				NodegroupName: aws.String(name),
+			})
+			if err != nil {
+				return nil, notFoundOr[*types.ResourceNotFoundException](err, "EKS node group", name, region)
+			}
+			nodeGroups = append(nodeGroups, newEksNodeGroup(description.Nodegroup))
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	return nodeGroups, nil
+}
+
+// newEksNodeGroup converts the given node group returned by the EKS API to an EksNodeGroup.
+func newEksNodeGroup(description *types.Nodegroup) EksNodeGroup {
+	nodeGroup := EksNodeGroup{
+		Name:           aws.ToString(description.NodegroupName),
+		Arn:            aws.ToString(description.NodegroupArn),
+		Status:         string(description.Status),
+		Version:        aws.ToString(description.Version),
+		ReleaseVersion: aws.ToString(description.ReleaseVersion),
+		AmiType:        string(description.AmiType),
+		CapacityType:   string(description.CapacityType),
+		InstanceTypes:  description.InstanceTypes,
+		SubnetIds:      description.Subnets,
+		NodeRoleArn:    aws.ToString(description.NodeRole),
+		Labels:         description.Labels,
+	}
+	if description.ScalingConfig != nil {
+		nodeGroup.MinSize = aws.ToInt32(description.ScalingConfig.MinSize)
+		nodeGroup.MaxSize = aws.ToInt32(description.ScalingConfig.MaxSize)
+		nodeGroup.DesiredSize = aws.ToInt32(description.ScalingConfig.DesiredSize)
+	}
+	return nodeGroup
+}
+
+// GetEksFargateProfiles fetches the Fargate profiles of the EKS cluster with the given name in the given region.
+func GetEksFargateProfiles(t testing.TestingT, region string, clusterName string) []EksFargateProfile {
+	profiles, err := GetEksFargateProfilesE(t, region, clusterName)
+	require.NoError(t, err)
+	return profiles
+}
+
+// GetEksFargateProfilesE fetches the Fargate profiles of the EKS cluster with the given name in the given region.
+// Returns a NotFoundError if the cluster does not exist.
+func GetEksFargateProfilesE(t testing.TestingT, region string, clusterName string) ([]EksFargateProfile, error) {
+	client, err := NewEksClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &eks.ListFargateProfilesInput{ClusterName: aws.String(clusterName)}
+	profiles := []EksFargateProfile{}
+	for {
+		output, err := client.ListFargateProfiles(context.Background(), input)
+		if err != nil {
+			return nil, notFoundOr[*types.ResourceNotFoundException](err, "EKS cluster", clusterName, region)
+		}
+
+		for _, name := range output.FargateProfileNames {
+			description, err := client.DescribeFargateProfile(context.Background(), &eks.DescribeFargateProfileInput{
+				ClusterName:        aws.String(clusterName),
+				FargateProfileName: aws.String(name),
+			})
+			if err != nil {
+				return nil, notFoundOr[*types.ResourceNotFoundException](err, "EKS Fargate profile", name, region)
+			}
+			profiles = append(profiles, newEksFargateProfile(description.FargateProfile))
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	return profiles, nil
+}
+
+// newEksFargateProfile converts the given Fargate profile returned by the EKS API to an EksFargateProfile.
+func newEksFargateProfile(description *types.FargateProfile) EksFargateProfile {
+	profile := EksFargateProfile{
+		Name:                aws.ToString(description.FargateProfileName),
+		Arn:                 aws.ToString(description.FargateProfileArn),
+		Status:              string(description.Status),
+		PodExecutionRoleArn: aws.ToString(description.PodExecutionRoleArn),
+		SubnetIds:           description.Subnets,
+		Selectors:           []EksFargateProfileSelector{},
+	}
+	for _, selector := range description.Selectors {
+		profile.Selectors = append(profile.Selectors, EksFargateProfileSelector{
+			Namespace: aws.ToString(selector.Namespace),
+			Labels:    selector.Labels,
+		})
+	}
+	return profile
+}
+
+// WaitForEksClusterActive waits until the EKS cluster with the given name in the given region is active and returns
+// it. This will fail the test if the cluster is not active after the given number of retries, or if it failed.
+func WaitForEksClusterActive(t testing.TestingT, region string, name string, retries int, sleepBetweenRetries time.Duration) *EksCluster {
+	cluster, err := WaitForEksClusterActiveE(t, region, name, retries, sleepBetweenRetries)
+	require.NoError(t, err)
+	return cluster
+}
+
+// WaitForEksClusterActiveE waits until the EKS cluster with the given name in the given region is active and returns
+// it. Stops waiting early if the cluster failed or is being deleted, since it will never become active.
+func WaitForEksClusterActiveE(t testing.TestingT, region string, name string, retries int, sleepBetweenRetries time.Duration) (*EksCluster, error) {
+	description := fmt.Sprintf("Waiting for EKS cluster %s to be active", name)
+	out, err := retry.DoWithRetryInterfaceE(t, description, retries, sleepBetweenRetries, func() (interface{}, error) {
+		cluster, err := GetEksClusterE(t, region, name)
+		if err != nil {
+			return nil, err
+		}
+		switch types.ClusterStatus(cluster.Status) {
+		case types.ClusterStatusActive:
+			return cluster, nil
+		case types.ClusterStatusFailed, types.ClusterStatusDeleting:
+			return nil, retry.FatalError{Underlying: fmt.Errorf("EKS cluster %s is %s, so it will never be active", name, cluster.Status)}
+		default:
+			return nil, fmt.Errorf("EKS cluster %s is still %s", name, cluster.Status)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*EksCluster), nil
+}
+
+// eksTokenPrefix is the prefix of the bearer tokens that the EKS authenticator accepts.
+const eksTokenPrefix = "k8s-aws-v1."
+
+// eksClusterIDHeader is the header with the name of the cluster that the EKS authenticator expects to be signed, so that
+// a token for one cluster can't be used with another.
+const eksClusterIDHeader = "x-k8s-aws-id"
+
+// GetEksToken returns a bearer token to authenticate to the Kubernetes API server of the EKS cluster with the given
+// name in the given region as the current AWS identity, like `aws eks get-token` and aws-iam-authenticator do. The token
+// expires after 15 minutes.
+func GetEksToken(t testing.TestingT, region string, clusterName string) string {
+	token, err := GetEksTokenE(t, region, clusterName)
+	require.NoError(t, err)
+	return token
+}
+
+// GetEksTokenE returns a bearer token to authenticate to the Kubernetes API server of the EKS cluster with the given
+// name in the given region as the current AWS identity, like `aws eks get-token` and aws-iam-authenticator do. The token
+// expires after 15 minutes.
+func GetEksTokenE(t testing.TestingT, region string, clusterName string) (string, error) {
+	client, err := NewStsClientE(t, region)
+	if err != nil {
+		return "", err
+	}
+	return getEksToken(client, clusterName)
+}
+
+// getEksToken returns a bearer token for the EKS cluster with the given name, which is a presigned URL of the STS
+// GetCallerIdentity call that the EKS authenticator makes to find out the AWS identity of the bearer.
+func getEksToken(client *sts.Client, clusterName string) (string, error) {
+	request, err := sts.NewPresignClient(client).PresignGetCallerIdentity(context.Background(), &sts.GetCallerIdentityInput{}, func(options *sts.PresignOptions) {
+		options.ClientOptions = append(options.ClientOptions, func(options *sts.Options) {
+			options.APIOptions = append(options.APIOptions,
+				smithyhttp.AddHeaderValue(eksClusterIDHeader, clusterName),
+				smithyhttp.AddHeaderValue("X-Amz-Expires", "60"),
+			)
+		})
+	})
+	if err != nil {
+		return "", err
+	}
+	return eksTokenPrefix + base64.RawURLEncoding.EncodeToString([]byte(request.URL)), nil
+}
+
+// NewEksClient creates a new EKS client.
+func NewEksClient(t testing.TestingT, region string) *eks.Client {
+	client, err := NewEksClientE(t, region)
+	require.NoError(t, err)
+	return client
+}
+
+// NewEksClientE creates a new EKS client.
+func NewEksClientE(t testing.TestingT, region string) (*eks.Client, error) {
+	sess, err := newAuthenticatedSession(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return eks.NewFromConfig(*sess), nil
+}
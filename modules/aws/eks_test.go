@@ -0,0 +1,65 @@
+package aws
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEksNodeGroup(t *testing.T) {
+	t.Parallel()
+
+	nodeGroup := newEksNodeGroup(&types.Nodegroup{
+		NodegroupName: aws.String("default"),
+		Status:        types.NodegroupStatusActive,
+		CapacityType:  types.CapacityTypesSpot,
+		InstanceTypes: []string{"t3.medium"},
+		ScalingConfig: &types.NodegroupScalingConfig{MinSize: aws.Int32(1), MaxSize: aws.Int32(3), DesiredSize: aws.Int32(2)},
+	})
+
+	assert.Equal(t, "default", nodeGroup.Name)
+	assert.Equal(t, "ACTIVE", nodeGroup.Status)
+	assert.Equal(t, "SPOT", nodeGroup.CapacityType)
+	assert.Equal(t, []string{"t3.medium"}, nodeGroup.InstanceTypes)
+	assert.Equal(t, int32(2), nodeGroup.DesiredSize)
+}
+
+func TestNewEksFargateProfile(t *testing.T) {
+	t.Parallel()
+
+	profile := newEksFargateProfile(&types.FargateProfile{
+		FargateProfileName: aws.String("kube-system"),
+		Selectors:          []types.FargateProfileSelector{{Namespace: aws.String("kube-system"), Labels: map[string]string{"k8s-app": "kube-dns"}}},
+	})
+
+	assert.Equal(t, "kube-system", profile.Name)
+	assert.Equal(t, []EksFargateProfileSelector{{Namespace: "kube-system", Labels: map[string]string{"k8s-app": "kube-dns"}}}, profile.Selectors)
+}
+
+func TestGetEksToken(t *testing.T) {
+	t.Parallel()
+
+	client := sts.NewFromConfig(aws.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+	})
+	token, err := getEksToken(client, "my-cluster")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(token, "k8s-aws-v1."))
+
+	presignedURL, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token, "k8s-aws-v1."))
+	require.NoError(t, err)
+	parsedURL, err := url.Parse(string(presignedURL))
+	require.NoError(t, err)
+	assert.Equal(t, "GetCallerIdentity", parsedURL.Query().Get("Action"))
+	assert.Equal(t, "60", parsedURL.Query().Get("X-Amz-Expires"))
+	assert.Contains(t, parsedURL.Query().Get("X-Amz-SignedHeaders"), "x-k8s-aws-id")
+}
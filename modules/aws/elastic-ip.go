@@ -0,0 +1,116 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// ElasticIp is an Elastic IP address.
+type ElasticIp struct {
+	PublicIp           string            // The Elastic IP address
+	AllocationId       string            // The ID of the allocation of the address
+	AssociationId      string            // The ID of the association with an instance or network interface. Empty if not associated.
+	InstanceId         string            // The ID of the instance the address is associated with. Empty if not associated with an instance.
+	NetworkInterfaceId string            // The ID of the network interface the address is associated with. Empty if not associated.
+	PrivateIpAddress   string            // The private IP address the address is associated with. Empty if not associated.
+	Domain             string            // Whether the address is for use in a VPC (vpc) or in EC2-Classic (standard)
+	Tags               map[string]string // The tags associated with the address
+}
+
+// GetElasticIps fetches the Elastic IP addresses in the given region that match all of the given EC2 filter names and
+// values (e.g. {"tag:Name": "my-eip"}), as per
+// https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_DescribeAddresses.html.
+func GetElasticIps(t testing.TestingT, region string, filters map[string]string) []ElasticIp {
+	elasticIps, err := GetElasticIpsE(t, region, filters)
+	require.NoError(t, err)
+	return elasticIps
+}
+
+// GetElasticIpsE fetches the Elastic IP addresses in the given region that match all of the given EC2 filter names and
+// values (e.g. {"tag:Name": "my-eip"}), as per
+// https://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_DescribeAddresses.html.
+func GetElasticIpsE(t testing.TestingT, region string, filters map[string]string) ([]ElasticIp, error) {
+	client, err := NewEc2ClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	var ec2FilterList []types.Filter
+	for name, value := range filters {
+		ec2FilterList = append(ec2FilterList, types.Filter{Name: aws.String(name), Values: []string{value}})
+	}
+
+	output, err := client.DescribeAddresses(context.Background(), &ec2.DescribeAddressesInput{Filters: ec2FilterList})
+	if err != nil {
+		return nil, err
+	}
+
+	elasticIps := []ElasticIp{}
+	for _, address := range output.Addresses {
+		elasticIps = append(elasticIps, newElasticIp(address))
+	}
+	return elasticIps, nil
+}
+
+// GetElasticIpForInstance returns the Elastic IP address associated with the EC2 instance with the given ID.
+func GetElasticIpForInstance(t testing.TestingT, region string, instanceID string) string {
+	publicIp, err := GetElasticIpForInstanceE(t, region, instanceID)
+	require.NoError(t, err)
+	return publicIp
+}
+
+// GetElasticIpForInstanceE returns the Elastic IP address associated with the EC2 instance with the given ID. If more
+// than one address is associated with the instance, the first one returned by the API is used. Returns a NotFoundError
+// if no Elastic IP address is associated with the instance.
+func GetElasticIpForInstanceE(t testing.TestingT, region string, instanceID string) (string, error) {
+	return getAssociatedElasticIpE(t, region, "instance-id", "Elastic IP for EC2 instance", instanceID)
+}
+
+// GetElasticIpForNetworkInterface returns the Elastic IP address associated with the network interface with the given
+// ID.
+func GetElasticIpForNetworkInterface(t testing.TestingT, region string, networkInterfaceID string) string {
+	publicIp, err := GetElasticIpForNetworkInterfaceE(t, region, networkInterfaceID)
+	require.NoError(t, err)
+	return publicIp
+}
+
+// GetElasticIpForNetworkInterfaceE returns the Elastic IP address associated with the network interface with the given
+// ID. If more than one address is associated with the network interface, the first one returned by the API is used.
+// Returns a NotFoundError if no Elastic IP address is associated with the network interface.
+func GetElasticIpForNetworkInterfaceE(t testing.TestingT, region string, networkInterfaceID string) (string, error) {
+	return getAssociatedElasticIpE(t, region, "network-interface-id", "Elastic IP for network interface", networkInterfaceID)
+}
+
+func getAssociatedElasticIpE(t testing.TestingT, region string, filterName string, objectType string, objectID string) (string, error) {
+	elasticIps, err := GetElasticIpsE(t, region, map[string]string{filterName: objectID})
+	if err != nil {
+		return "", err
+	}
+	if len(elasticIps) == 0 {
+		return "", NewNotFoundError(objectType, objectID, region)
+	}
+	return elasticIps[0].PublicIp, nil
+}
+
+func newElasticIp(address types.Address) ElasticIp {
+	tags := map[string]string{}
+	for _, tag := range address.Tags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+
+	return ElasticIp{
+		PublicIp:           aws.ToString(address.PublicIp),
+		AllocationId:       aws.ToString(address.AllocationId),
+		AssociationId:      aws.ToString(address.AssociationId),
+		InstanceId:         aws.ToString(address.InstanceId),
+		NetworkInterfaceId: aws.ToString(address.NetworkInterfaceId),
+		PrivateIpAddress:   aws.ToString(address.PrivateIpAddress),
+		Domain:             string(address.Domain),
+		Tags:               tags,
+	}
+}
@@ -0,0 +1,49 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewElasticIpAssociated(t *testing.T) {
+	t.Parallel()
+
+	elasticIp := newElasticIp(types.Address{
+		PublicIp:           aws.String("203.0.113.10"),
+		AllocationId:       aws.String("eipalloc-0123456789abcdef0"),
+		AssociationId:      aws.String("eipassoc-0123456789abcdef0"),
+		InstanceId:         aws.String("i-0123456789abcdef0"),
+		NetworkInterfaceId: aws.String("eni-0123456789abcdef0"),
+		PrivateIpAddress:   aws.String("10.0.1.10"),
+		Domain:             types.DomainTypeVpc,
+		Tags:               []types.Tag{{Key: aws.String("Name"), Value: aws.String("web")}},
+	})
+
+	assert.Equal(t, ElasticIp{
+		PublicIp:           "203.0.113.10",
+		AllocationId:       "eipalloc-0123456789abcdef0",
+		AssociationId:      "eipassoc-0123456789abcdef0",
+		InstanceId:         "i-0123456789abcdef0",
+		NetworkInterfaceId: "eni-0123456789abcdef0",
+		PrivateIpAddress:   "10.0.1.10",
+		Domain:             "vpc",
+		Tags:               map[string]string{"Name": "web"},
+	}, elasticIp)
+}
+
+func TestNewElasticIpNotAssociated(t *testing.T) {
+	t.Parallel()
+
+	elasticIp := newElasticIp(types.Address{
+		PublicIp:     aws.String("203.0.113.11"),
+		AllocationId: aws.String("eipalloc-0123456789abcdef1"),
+		Domain:       types.DomainTypeVpc,
+	})
+
+	assert.Equal(t, "", elasticIp.AssociationId)
+	assert.Equal(t, "", elasticIp.InstanceId)
+	assert.Equal(t, map[string]string{}, elasticIp.Tags)
+}
@@ -0,0 +1,51 @@
+package aws
+
+import (
+	"os"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// AuthEndpointEnvVar is the environment variable through which the AWS SDK reads the endpoint URL of all AWS services,
+// such as http://localhost:4566 for LocalStack.
+const AuthEndpointEnvVar = "AWS_ENDPOINT_URL"
+
+var (
+	globalEndpointMutex sync.RWMutex
+	globalEndpoint      string
+)
+
+// SetGlobalEndpoint sends the requests of all the clients created after this call, across all the tests of the
+// process, to the given endpoint URL rather than to the AWS endpoints, e.g. http://localhost:4566 to run tests against
+// LocalStack or another emulator. This takes precedence over AuthEndpointEnvVar, while the endpoint of AuthOptions and
+// the service specific AWS_ENDPOINT_URL_<SERVICE> environment variables take precedence over this. Pass an empty URL
+// to send requests to the AWS endpoints again, which is the default.
+func SetGlobalEndpoint(endpointURL string) {
+	globalEndpointMutex.Lock()
+	defer globalEndpointMutex.Unlock()
+
+	globalEndpoint = endpointURL
+}
+
+// applyGlobalEndpoint points the given config at the global endpoint, if one is set with SetGlobalEndpoint, or at the
+// endpoint in AuthEndpointEnvVar if the config does not have an endpoint yet, as is the case for configs that are not
+// loaded from the environment.
+func applyGlobalEndpoint(cfg *aws.Config) {
+	globalEndpointMutex.RLock()
+	endpointURL := globalEndpoint
+	globalEndpointMutex.RUnlock()
+
+	if endpointURL == "" && cfg.BaseEndpoint == nil {
+		endpointURL = os.Getenv(AuthEndpointEnvVar)
+	}
+	if endpointURL != "" {
+		cfg.BaseEndpoint = aws.String(endpointURL)
+	}
+}
+
+// hasCustomEndpoint returns true if the given config sends requests to a custom endpoint rather than to the AWS
+// endpoints.
+func hasCustomEndpoint(cfg *aws.Config) bool {
+	return aws.ToString(cfg.BaseEndpoint) != ""
+}
@@ -0,0 +1,46 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// This test changes the global endpoint, so it can't run in parallel with other tests that create configs.
+func TestApplyGlobalEndpoint(t *testing.T) {
+	defer SetGlobalEndpoint("")
+	t.Setenv(AuthEndpointEnvVar, "")
+
+	cfg := &aws.Config{}
+	applyGlobalEndpoint(cfg)
+	assert.Nil(t, cfg.BaseEndpoint)
+	assert.False(t, newS3ClientFromConfig(cfg).Options().UsePathStyle)
+
+	t.Setenv(AuthEndpointEnvVar, "http://emulator:4566")
+	applyGlobalEndpoint(cfg)
+	assert.Equal(t, "http://emulator:4566", aws.ToString(cfg.BaseEndpoint))
+
+	SetGlobalEndpoint("http://localhost:4566")
+	applyGlobalEndpoint(cfg)
+	assert.Equal(t, "http://localhost:4566", aws.ToString(cfg.BaseEndpoint))
+	assert.True(t, newS3ClientFromConfig(cfg).Options().UsePathStyle)
+}
+
+// This test changes the global endpoint, so it can't run in parallel with other tests that create configs.
+func TestNewAuthenticatedSessionWithOptionsEndpoint(t *testing.T) {
+	defer SetGlobalEndpoint("")
+	t.Setenv(AuthAssumeRoleEnvVar, "")
+	t.Setenv(AuthEndpointEnvVar, "")
+
+	SetGlobalEndpoint("http://localhost:4566")
+
+	cfg, err := NewAuthenticatedSessionWithOptions(AuthOptions{Region: "us-east-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:4566", aws.ToString(cfg.BaseEndpoint))
+
+	cfg, err = NewAuthenticatedSessionWithOptions(AuthOptions{Region: "us-east-1", Endpoint: "http://other:4566"})
+	require.NoError(t, err)
+	assert.Equal(t, "http://other:4566", aws.ToString(cfg.BaseEndpoint))
+}
@@ -1,7 +1,10 @@
 package aws
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 )
 
 // IpForEc2InstanceNotFound is an error that occurs when the IP for an EC2 instance is not found.
@@ -26,6 +29,16 @@ func (err HostnameForEc2InstanceNotFound) Error() string {
 	return fmt.Sprintf("Could not find a %s hostname for EC2 Instance %s in %s", err.Type, err.InstanceId, err.AwsRegion)
 }
 
+// Ec2InstanceTerminated is an error that occurs when an EC2 instance has been terminated.
+type Ec2InstanceTerminated struct {
+	InstanceId string
+	AwsRegion  string
+}
+
+func (err Ec2InstanceTerminated) Error() string {
+	return fmt.Sprintf("EC2 Instance %s in %s has been terminated", err.InstanceId, err.AwsRegion)
+}
+
 // NotFoundError is returned when an expected object is not found
 type NotFoundError struct {
 	objectType string
@@ -41,6 +54,17 @@ func NewNotFoundError(objectType string, objectID string, region string) NotFoun
 	return NotFoundError{objectType, objectID, region}
 }
 
+// notFoundOr converts the given error to a NotFoundError for the object of the given type and id if it is (or wraps) an
+// error of type E, which is the error the AWS API returns when the object doesn't exist (e.g.
+// *types.ResourceNotFoundException), and returns any other error as is.
+func notFoundOr[E error](err error, objectType string, objectID string, region string) error {
+	var notFoundErr E
+	if errors.As(err, &notFoundErr) {
+		return NewNotFoundError(objectType, objectID, region)
+	}
+	return err
+}
+
 // AsgCapacityNotMetError is returned when the ASG capacity is not yet at the desired capacity.
 type AsgCapacityNotMetError struct {
 	asgName         string
@@ -129,3 +153,271 @@ func (err NoRdsInstanceTypeError) Error() string {
 		err.DatabaseEngineVersion,
 	)
 }
+
+// StepFunctionExecutionFailedError is returned when a Step Functions execution finishes with a status other than
+// SUCCEEDED
+type StepFunctionExecutionFailedError struct {
+	ExecutionArn string
+	Status       string
+	ErrorName    string
+	Cause        string
+}
+
+func (err StepFunctionExecutionFailedError) Error() string {
+	return fmt.Sprintf(
+		"Step Functions execution %s finished with status %s: %s: %s",
+		err.ExecutionArn,
+		err.Status,
+		err.ErrorName,
+		err.Cause,
+	)
+}
+
+// UnexpectedStepFunctionExecutionOutput is returned when the output of a Step Functions execution is not the expected
+// output
+type UnexpectedStepFunctionExecutionOutput struct {
+	ExecutionArn   string
+	ExpectedOutput string
+	ActualOutput   string
+}
+
+func (err UnexpectedStepFunctionExecutionOutput) Error() string {
+	return fmt.Sprintf(
+		"Step Functions execution %s returned output %s, expected %s",
+		err.ExecutionArn,
+		err.ActualOutput,
+		err.ExpectedOutput,
+	)
+}
+
+// NoCloudWatchAlarmForResourceError is returned when there is no CloudWatch alarm on a metric of a resource
+type NoCloudWatchAlarmForResourceError struct {
+	Namespace      string
+	DimensionName  string
+	DimensionValue string
+	Region         string
+}
+
+func (err NoCloudWatchAlarmForResourceError) Error() string {
+	return fmt.Sprintf(
+		"No CloudWatch alarm found in region %s on a metric in namespace %s with dimension %s=%s",
+		err.Region,
+		err.Namespace,
+		err.DimensionName,
+		err.DimensionValue,
+	)
+}
+
+// LogsInsightsQueryFailed is returned when a CloudWatch Logs Insights query finishes with a status other than Complete,
+// or does not finish in time
+type LogsInsightsQueryFailed struct {
+	QueryID string
+	Status  string
+}
+
+func (err LogsInsightsQueryFailed) Error() string {
+	return fmt.Sprintf("CloudWatch Logs Insights query %s did not complete, its status is %s", err.QueryID, err.Status)
+}
+
+// SsmCommandFailed is returned when an SSM command could not be run to completion on an instance
+type SsmCommandFailed struct {
+	CommandID     string
+	InstanceID    string
+	Status        string
+	StatusDetails string
+}
+
+func (err SsmCommandFailed) Error() string {
+	return fmt.Sprintf(
+		"SSM command %s on %s finished with status %s: %s",
+		err.CommandID,
+		err.InstanceID,
+		err.Status,
+		err.StatusDetails,
+	)
+}
+
+// UnexpectedCloudFrontConfiguration is returned when a setting of a CloudFront distribution does not have the expected
+// value
+type UnexpectedCloudFrontConfiguration struct {
+	DistributionID string
+	Setting        string
+	Expected       string
+	Actual         string
+}
+
+func (err UnexpectedCloudFrontConfiguration) Error() string {
+	return fmt.Sprintf(
+		"CloudFront distribution %s has %s %s, expected %s",
+		err.DistributionID,
+		err.Setting,
+		err.Actual,
+		err.Expected,
+	)
+}
+
+// UnexpectedDynamoDbTableConfiguration is returned when a setting of a DynamoDB table does not have the expected value
+type UnexpectedDynamoDbTableConfiguration struct {
+	TableName string
+	Setting   string
+	Expected  string
+	Actual    string
+}
+
+func (err UnexpectedDynamoDbTableConfiguration) Error() string {
+	return fmt.Sprintf(
+		"DynamoDB table %s has %s %s, expected %s",
+		err.TableName,
+		err.Setting,
+		err.Actual,
+		err.Expected,
+	)
+}
+
+// SnsMessageNotDelivered is returned when a message published to an SNS topic is not delivered to an SQS queue in time
+type SnsMessageNotDelivered struct {
+	TopicArn string
+	QueueUrl string
+	Timeout  time.Duration
+}
+
+func (err SnsMessageNotDelivered) Error() string {
+	return fmt.Sprintf("Message published to SNS topic %s was not delivered to queue %s within %s", err.TopicArn, err.QueueUrl, err.Timeout)
+}
+
+// UnexpectedSnsFilterPolicy is returned when the filter policy of an SNS subscription is not the expected policy
+type UnexpectedSnsFilterPolicy struct {
+	SubscriptionArn string
+	ExpectedPolicy  string
+	ActualPolicy    string
+}
+
+func (err UnexpectedSnsFilterPolicy) Error() string {
+	return fmt.Sprintf("SNS subscription %s has filter policy %q, expected %q", err.SubscriptionArn, err.ActualPolicy, err.ExpectedPolicy)
+}
+
+// UnexpectedIamPolicyDecision is returned when the IAM policy simulator allows actions that a principal should not be
+// allowed to perform, or denies actions it should be allowed to perform
+type UnexpectedIamPolicyDecision struct {
+	PrincipalArn  string
+	ExpectAllowed bool
+	Decisions     string
+}
+
+func (err UnexpectedIamPolicyDecision) Error() string {
+	expected := "denied"
+	if err.ExpectAllowed {
+		expected = "allowed"
+	}
+	return fmt.Sprintf("Expected %s to be %s, but %s", err.PrincipalArn, expected, err.Decisions)
+}
+
+// KmsEncryptionRoundTripFailed is returned when decrypting a value encrypted with a KMS key does not return the
+// original value
+type KmsEncryptionRoundTripFailed struct {
+	KeyID string
+}
+
+func (err KmsEncryptionRoundTripFailed) Error() string {
+	return fmt.Sprintf("Decrypting a value encrypted with KMS key %s did not return the original value", err.KeyID)
+}
+
+// KmsKeyPolicyDoesNotAllow is returned when the key policy of a KMS key does not allow a principal to perform an action
+type KmsKeyPolicyDoesNotAllow struct {
+	KeyID     string
+	Principal string
+	Action    string
+	// The conditional statements that match the principal and action, which were not evaluated
+	NotEvaluatedStatements []string
+}
+
+func (err KmsKeyPolicyDoesNotAllow) Error() string {
+	if len(err.NotEvaluatedStatements) > 0 {
+		return fmt.Sprintf("Key policy of KMS key %s does not allow %s to perform %s (conditional statements %s were not evaluated)", err.KeyID, err.Principal, err.Action, strings.Join(err.NotEvaluatedStatements, ", "))
+	}
+	return fmt.Sprintf("Key policy of KMS key %s does not allow %s to perform %s", err.KeyID, err.Principal, err.Action)
+}
+
+// Route53HealthCheckNotHealthy is returned when too few health checkers report a Route 53 health check as healthy
+type Route53HealthCheckNotHealthy struct {
+	HealthCheckID   string
+	HealthyCheckers int
+	TotalCheckers   int
+}
+
+func (err Route53HealthCheckNotHealthy) Error() string {
+	return fmt.Sprintf("Route 53 health check %s is not healthy: %d of %d health checkers report it as healthy", err.HealthCheckID, err.HealthyCheckers, err.TotalCheckers)
+}
+
+// SecretRotationNotEnabled is returned when rotation is not enabled for a Secrets Manager secret
+type SecretRotationNotEnabled struct {
+	SecretID  string
+	AwsRegion string
+}
+
+func (err SecretRotationNotEnabled) Error() string {
+	return fmt.Sprintf("Rotation is not enabled for secret %s in %s", err.SecretID, err.AwsRegion)
+}
+
+// GlueJobRunFailed is returned when a Glue job run finishes in a state other than SUCCEEDED
+type GlueJobRunFailed struct {
+	JobName      string
+	RunID        string
+	State        string
+	ErrorMessage string
+}
+
+func (err GlueJobRunFailed) Error() string {
+	return fmt.Sprintf(
+		"Run %s of Glue job %s finished in state %s: %s",
+		err.RunID,
+		err.JobName,
+		err.State,
+		err.ErrorMessage,
+	)
+}
+
+// EventBridgePutEventFailed is returned when EventBridge does not accept an event
+type EventBridgePutEventFailed struct {
+	BusName          string
+	FailedEntryCount int32
+	ErrorCode        string
+	ErrorMessage     string
+}
+
+func (err EventBridgePutEventFailed) Error() string {
+	return fmt.Sprintf(
+		"Failed to put %d event(s) on EventBridge bus %s: %s: %s",
+		err.FailedEntryCount,
+		err.BusName,
+		err.ErrorCode,
+		err.ErrorMessage,
+	)
+}
+
+// NotEnoughAvailabilityZones is returned when a region has fewer available Availability Zones than requested
+type NotEnoughAvailabilityZones struct {
+	Region    string
+	Requested int
+	Available []string
+}
+
+func (err NotEnoughAvailabilityZones) Error() string {
+	return fmt.Sprintf(
+		"Requested %d availability zones in region %s, but only %d are available: %v",
+		err.Requested,
+		err.Region,
+		len(err.Available),
+		err.Available,
+	)
+}
+
+// NetworkPathAnalysisFailed is returned when VPC Reachability Analyzer fails to analyze a network path
+type NetworkPathAnalysisFailed struct {
+	AnalysisId    string
+	StatusMessage string
+}
+
+func (err NetworkPathAnalysisFailed) Error() string {
+	return fmt.Sprintf("Network insights analysis %s failed: %s", err.AnalysisId, err.StatusMessage)
+}
@@ -0,0 +1,26 @@
+package aws
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotFoundOr(t *testing.T) {
+	t.Parallel()
+
+	notFoundErr := &types.ResourceNotFoundException{Message: aws.String("Function not found")}
+	err := notFoundOr[*types.ResourceNotFoundException](notFoundErr, "Lambda function", "my-function", "us-east-1")
+	assert.Equal(t, NewNotFoundError("Lambda function", "my-function", "us-east-1"), err)
+
+	wrappedErr := fmt.Errorf("operation error Lambda: GetFunction, %w", notFoundErr)
+	err = notFoundOr[*types.ResourceNotFoundException](wrappedErr, "Lambda function", "my-function", "us-east-1")
+	assert.Equal(t, NewNotFoundError("Lambda function", "my-function", "us-east-1"), err)
+
+	otherErr := errors.New("AccessDeniedException")
+	assert.Equal(t, otherErr, notFoundOr[*types.ResourceNotFoundException](otherErr, "Lambda function", "my-function", "us-east-1"))
+}
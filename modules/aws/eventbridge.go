@@ -0,0 +1,139 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// EventBridgeRuleTarget is a target that events matching an EventBridge rule are delivered to.
+type EventBridgeRuleTarget struct {
+	Id        string // The ID of the target within the rule
+	Arn       string // The ARN of the target (e.g. a Lambda function, SQS queue or SNS topic)
+	RoleArn   string // The ARN of the IAM role EventBridge uses to invoke the target. Empty if not set.
+	Input     string // The constant JSON passed to the target instead of the event. Empty if not set.
+	InputPath string // The JSONPath of the part of the event passed to the target. Empty if not set.
+}
+
+// PutEventBridgeEvent publishes an event with the given source, detail type and JSON detail to the given event bus
+// and returns the ID of the event. Use "default" or an empty string as the bus name for the default event bus. This
+// will fail the test if the event was not accepted.
+func PutEventBridgeEvent(t testing.TestingT, region string, busName string, source string, detailType string, detailJSON string) string {
+	eventID, err := PutEventBridgeEventE(t, region, busName, source, detailType, detailJSON)
+	require.NoError(t, err)
+	return eventID
+}
+
+// PutEventBridgeEventE publishes an event with the given source, detail type and JSON detail to the given event bus
+// and returns the ID of the event. Use "default" or an empty string as the bus name for the default event bus. Returns
+// an EventBridgePutEventFailed error with the error code reported by EventBridge if the event was not accepted.
+func PutEventBridgeEventE(t testing.TestingT, region string, busName string, source string, detailType string, detailJSON string) (string, error) {
+	logger.Default.Logf(t, "Putting %s event from %s on EventBridge bus %s in %s", detailType, source, busName, region)
+
+	client, err := NewEventBridgeClientE(t, region)
+	if err != nil {
+		return "", err
+	}
+
+	entry := types.PutEventsRequestEntry{
+		Source:     aws.String(source),
+		DetailType: aws.String(detailType),
+		Detail:     aws.String(detailJSON),
+	}
+	if busName != "" {
+		entry.EventBusName = aws.String(busName)
+	}
+
+	output, err := client.PutEvents(context.Background(), &eventbridge.PutEventsInput{
+		Entries: []types.PutEventsRequestEntry{entry},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return eventIDFromPutEventsOutput(busName, output)
+}
+
+// eventIDFromPutEventsOutput returns the ID of the single event put by PutEventBridgeEventE, or an
+// EventBridgePutEventFailed error if EventBridge reported the event as failed.
+func eventIDFromPutEventsOutput(busName string, output *eventbridge.PutEventsOutput) (string, error) {
+	if output.FailedEntryCount > 0 || len(output.Entries) != 1 {
+		err := EventBridgePutEventFailed{BusName: busName, FailedEntryCount: output.FailedEntryCount}
+		if len(output.Entries) == 1 {
+			err.ErrorCode = aws.ToString(output.Entries[0].ErrorCode)
+			err.ErrorMessage = aws.ToString(output.Entries[0].ErrorMessage)
+		}
+		return "", err
+	}
+
+	return aws.ToString(output.Entries[0].EventId), nil
+}
+
+// GetEventBridgeRuleTargets fetches the targets of the given rule on the given event bus. Use "default" or an empty
+// string as the bus name for the default event bus.
+func GetEventBridgeRuleTargets(t testing.TestingT, region string, busName string, ruleName string) []EventBridgeRuleTarget {
+	targets, err := GetEventBridgeRuleTargetsE(t, region, busName, ruleName)
+	require.NoError(t, err)
+	return targets
+}
+
+// GetEventBridgeRuleTargetsE fetches the targets of the given rule on the given event bus. Use "default" or an empty
+// string as the bus name for the default event bus.
+func GetEventBridgeRuleTargetsE(t testing.TestingT, region string, busName string, ruleName string) ([]EventBridgeRuleTarget, error) {
+	client, err := NewEventBridgeClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &eventbridge.ListTargetsByRuleInput{Rule: aws.String(ruleName)}
+	if busName != "" {
+		input.EventBusName = aws.String(busName)
+	}
+
+	targets := []EventBridgeRuleTarget{}
+	for {
+		output, err := client.ListTargetsByRule(context.Background(), input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, target := range output.Targets {
+			targets = append(targets, EventBridgeRuleTarget{
+				Id:        aws.ToString(target.Id),
+				Arn:       aws.ToString(target.Arn),
+				RoleArn:   aws.ToString(target.RoleArn),
+				Input:     aws.ToString(target.Input),
+				InputPath: aws.ToString(target.InputPath),
+			})
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	return targets, nil
+}
+
+// NewEventBridgeClient creates a new EventBridge client.
+func NewEventBridgeClient(t testing.TestingT, region string) *eventbridge.Client {
+	client, err := NewEventBridgeClientE(t, region)
+	require.NoError(t, err)
+	return client
+}
+
+// NewEventBridgeClientE creates a new EventBridge client.
+func NewEventBridgeClientE(t testing.TestingT, region string) (*eventbridge.Client, error) {
+	sess, err := newAuthenticatedSession(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return eventbridge.NewFromConfig(*sess), nil
+}
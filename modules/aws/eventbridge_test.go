@@ -0,0 +1,40 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventIDFromPutEventsOutput(t *testing.T) {
+	t.Parallel()
+
+	eventID, err := eventIDFromPutEventsOutput("default", &eventbridge.PutEventsOutput{
+		Entries: []types.PutEventsResultEntry{{EventId: aws.String("11710aed-b79e-4468-a20b-bb3c0c3b4860")}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "11710aed-b79e-4468-a20b-bb3c0c3b4860", eventID)
+}
+
+func TestEventIDFromPutEventsOutputFailedEntry(t *testing.T) {
+	t.Parallel()
+
+	_, err := eventIDFromPutEventsOutput("orders", &eventbridge.PutEventsOutput{
+		FailedEntryCount: 1,
+		Entries: []types.PutEventsResultEntry{{
+			ErrorCode:    aws.String("MalformedDetail"),
+			ErrorMessage: aws.String("Detail is malformed."),
+		}},
+	})
+	require.Error(t, err)
+	assert.Equal(t, EventBridgePutEventFailed{
+		BusName:          "orders",
+		FailedEntryCount: 1,
+		ErrorCode:        "MalformedDetail",
+		ErrorMessage:     "Detail is malformed.",
+	}, err)
+}
@@ -0,0 +1,192 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/glue"
+	"github.com/aws/aws-sdk-go-v2/service/glue/types"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// GlueJob contains the capacity and runtime settings of an AWS Glue job.
+type GlueJob struct {
+	Name            string  // The name of the job
+	Role            string  // The IAM role the job runs as
+	GlueVersion     string  // The Glue version of the job (e.g. 4.0)
+	CommandName     string  // The type of the job (e.g. glueetl, gluestreaming or pythonshell)
+	ScriptLocation  string  // The S3 path of the script the job runs
+	WorkerType      string  // The type of the workers allocated to the job (e.g. G.1X). Empty if MaxCapacity is used.
+	NumberOfWorkers int32   // The number of workers allocated to the job. Zero if MaxCapacity is used.
+	MaxCapacity     float64 // The number of data processing units allocated to the job. Zero if WorkerType is used.
+	Timeout         int32   // The timeout of the job, in minutes
+}
+
+// GlueJobRunResult contains the final state of an AWS Glue job run.
+type GlueJobRunResult struct {
+	State         types.JobRunState // The final state of the run (e.g. SUCCEEDED, FAILED, TIMEOUT or STOPPED)
+	ErrorMessage  string            // The error message of the run. Only set if the run did not succeed.
+	ExecutionTime int32             // The number of seconds the run consumed resources
+}
+
+// GetGlueJob fetches the capacity and runtime settings of the Glue job with the given name.
+func GetGlueJob(t testing.TestingT, region string, jobName string) *GlueJob {
+	job, err := GetGlueJobE(t, region, jobName)
+	require.NoError(t, err)
+	return job
+}
+
+// GetGlueJobE fetches the capacity and runtime settings of the Glue job with the given name.
+func GetGlueJobE(t testing.TestingT, region string, jobName string) (*GlueJob, error) {
+	client, err := NewGlueClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetJob(context.Background(), &glue.GetJobInput{JobName: aws.String(jobName)})
+	if err != nil {
+		return nil, err
+	}
+	if output.Job == nil {
+		return nil, NewNotFoundError("Glue job", jobName, region)
+	}
+
+	return newGlueJob(*output.Job), nil
+}
+
+// StartGlueJobRun starts a run of the given Glue job with the given arguments and returns the ID of the run.
+func StartGlueJobRun(t testing.TestingT, region string, jobName string, args map[string]string) string {
+	runID, err := StartGlueJobRunE(t, region, jobName, args)
+	require.NoError(t, err)
+	return runID
+}
+
+// StartGlueJobRunE starts a run of the given Glue job with the given arguments and returns the ID of the run.
+// Argument names must include the leading "--" (e.g. "--input_path").
+func StartGlueJobRunE(t testing.TestingT, region string, jobName string, args map[string]string) (string, error) {
+	logger.Default.Logf(t, "Starting run of Glue job %s in %s", jobName, region)
+
+	client, err := NewGlueClientE(t, region)
+	if err != nil {
+		return "", err
+	}
+
+	output, err := client.StartJobRun(context.Background(), &glue.StartJobRunInput{
+		JobName:   aws.String(jobName),
+		Arguments: args,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return aws.ToString(output.JobRunId), nil
+}
+
+// WaitForGlueJobRun waits until the given Glue job run has finished and returns its final state. This will fail the
+// test if the run did not succeed.
+func WaitForGlueJobRun(t testing.TestingT, region string, jobName string, runID string, retries int, sleepBetweenRetries time.Duration) *GlueJobRunResult {
+	result, err := WaitForGlueJobRunE(t, region, jobName, runID, retries, sleepBetweenRetries)
+	require.NoError(t, err)
+	return result
+}
+
+// WaitForGlueJobRunE waits until the given Glue job run has finished and returns its final state. If the run did not
+// succeed, the result is returned along with a GlueJobRunFailed error that includes the error message of the run.
+func WaitForGlueJobRunE(t testing.TestingT, region string, jobName string, runID string, retries int, sleepBetweenRetries time.Duration) (*GlueJobRunResult, error) {
+	client, err := NewGlueClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	description := fmt.Sprintf("Waiting for run %s of Glue job %s to finish", runID, jobName)
+	out, err := retry.DoWithRetryInterfaceE(t, description, retries, sleepBetweenRetries, func() (interface{}, error) {
+		output, err := client.GetJobRun(context.Background(), &glue.GetJobRunInput{
+			JobName: aws.String(jobName),
+			RunId:   aws.String(runID),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if output.JobRun == nil {
+			return nil, retry.FatalError{Underlying: NewNotFoundError("Glue job run", runID, region)}
+		}
+		if !isGlueJobRunFinished(output.JobRun.JobRunState) {
+			return nil, fmt.Errorf("run %s of Glue job %s is still %s", runID, jobName, output.JobRun.JobRunState)
+		}
+		return &GlueJobRunResult{
+			State:         output.JobRun.JobRunState,
+			ErrorMessage:  aws.ToString(output.JobRun.ErrorMessage),
+			ExecutionTime: output.JobRun.ExecutionTime,
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := out.(*GlueJobRunResult)
+	if result.State == types.JobRunStateSucceeded {
+		return result, nil
+	}
+
+	return result, GlueJobRunFailed{
+		JobName:      jobName,
+		RunID:        runID,
+		State:        string(result.State),
+		ErrorMessage: result.ErrorMessage,
+	}
+}
+
+// isGlueJobRunFinished returns true if a Glue job run in the given state will not change state anymore.
+func isGlueJobRunFinished(state types.JobRunState) bool {
+	switch state {
+	case types.JobRunStateSucceeded,
+		types.JobRunStateFailed,
+		types.JobRunStateTimeout,
+		types.JobRunStateStopped,
+		types.JobRunStateError,
+		types.JobRunStateExpired:
+		return true
+	}
+	return false
+}
+
+func newGlueJob(job types.Job) *GlueJob {
+	result := &GlueJob{
+		Name:            aws.ToString(job.Name),
+		Role:            aws.ToString(job.Role),
+		GlueVersion:     aws.ToString(job.GlueVersion),
+		WorkerType:      string(job.WorkerType),
+		NumberOfWorkers: aws.ToInt32(job.NumberOfWorkers),
+		MaxCapacity:     aws.ToFloat64(job.MaxCapacity),
+		Timeout:         aws.ToInt32(job.Timeout),
+	}
+
+	if command := job.Command; command != nil {
+		result.CommandName = aws.ToString(command.Name)
+		result.ScriptLocation = aws.ToString(command.ScriptLocation)
+	}
+
+	return result
+}
+
+// NewGlueClient creates a new Glue client.
+func NewGlueClient(t testing.TestingT, region string) *glue.Client {
+	client, err := NewGlueClientE(t, region)
+	require.NoError(t, err)
+	return client
+}
+
+// NewGlueClientE creates a new Glue client.
+func NewGlueClientE(t testing.TestingT, region string) (*glue.Client, error) {
+	sess, err := newAuthenticatedSession(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return glue.NewFromConfig(*sess), nil
+}
@@ -0,0 +1,41 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/glue/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsGlueJobRunFinished(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, isGlueJobRunFinished(types.JobRunStateStarting))
+	assert.False(t, isGlueJobRunFinished(types.JobRunStateRunning))
+	assert.False(t, isGlueJobRunFinished(types.JobRunStateStopping))
+	assert.True(t, isGlueJobRunFinished(types.JobRunStateSucceeded))
+	assert.True(t, isGlueJobRunFinished(types.JobRunStateFailed))
+	assert.True(t, isGlueJobRunFinished(types.JobRunStateTimeout))
+}
+
+func TestNewGlueJob(t *testing.T) {
+	t.Parallel()
+
+	job := newGlueJob(types.Job{
+		Name:            aws.String("etl"),
+		GlueVersion:     aws.String("4.0"),
+		WorkerType:      types.WorkerTypeG1x,
+		NumberOfWorkers: aws.Int32(10),
+		Command: &types.JobCommand{
+			Name:           aws.String("glueetl"),
+			ScriptLocation: aws.String("s3://bucket/etl.py"),
+		},
+	})
+
+	assert.Equal(t, "etl", job.Name)
+	assert.Equal(t, "G.1X", job.WorkerType)
+	assert.Equal(t, int32(10), job.NumberOfWorkers)
+	assert.Equal(t, "glueetl", job.CommandName)
+	assert.Equal(t, "s3://bucket/etl.py", job.ScriptLocation)
+}
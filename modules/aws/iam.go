@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -13,6 +14,16 @@ import (
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
 
+// IamPolicySimulationResult is the result of simulating whether an IAM principal is allowed to perform an action on a
+// resource.
+type IamPolicySimulationResult struct {
+	Action               string   // The action that was simulated (e.g. s3:GetObject)
+	Resource             string   // The ARN of the resource the action was simulated on, or "*"
+	Decision             string   // The decision of the simulation (allowed, explicitDeny or implicitDeny)
+	MatchedPolicyIds     []string // The IDs of the policies with statements that determined the decision
+	MissingContextValues []string // The context keys the policies use in conditions that were not provided to the simulation
+}
+
 // GetIamCurrentUserName gets the username for the current IAM user.
 func GetIamCurrentUserName(t testing.TestingT) string {
 	out, err := GetIamCurrentUserNameE(t)
@@ -112,6 +123,128 @@ func GetIamPolicyDocumentE(t testing.TestingT, region string, policyARN string)
 	return escapedDocument, nil
 }
 
+// SimulateIamPrincipalPolicy simulates whether the IAM user, group or role with the given ARN is allowed to perform each
+// of the given actions on each of the given resources, based on the policies attached to it.
+func SimulateIamPrincipalPolicy(t testing.TestingT, region string, principalArn string, actions []string, resources []string) []IamPolicySimulationResult {
+	out, err := SimulateIamPrincipalPolicyE(t, region, principalArn, actions, resources)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// SimulateIamPrincipalPolicyE simulates whether the IAM user, group or role with the given ARN is allowed to perform
+// each of the given actions, such as "s3:GetObject", on each of the given resource ARNs, based on the identity policies
+// and permissions boundary attached to it. Pass no resources to simulate the actions on all resources ("*"). Note that
+// resource policies, such as S3 bucket policies, and service control policies are not taken into account.
+func SimulateIamPrincipalPolicyE(t testing.TestingT, region string, principalArn string, actions []string, resources []string) ([]IamPolicySimulationResult, error) {
+	iamClient, err := NewIamClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principalArn),
+		ActionNames:     actions,
+	}
+	if len(resources) > 0 {
+		input.ResourceArns = resources
+	}
+
+	results := []IamPolicySimulationResult{}
+	paginator := iam.NewSimulatePrincipalPolicyPaginator(iamClient, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range page.EvaluationResults {
+			results = append(results, newIamPolicySimulationResult(result))
+		}
+	}
+	return results, nil
+}
+
+// AssertIamRoleCan checks that the IAM role (or other principal) with the given ARN is allowed to perform all the given
+// actions on all the given resources, failing the test if it is not.
+func AssertIamRoleCan(t testing.TestingT, region string, roleArn string, actions []string, resources []string) {
+	err := AssertIamRoleCanE(t, region, roleArn, actions, resources)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// AssertIamRoleCanE checks that the IAM role (or other principal) with the given ARN is allowed to perform all the
+// given actions on all the given resources, according to SimulateIamPrincipalPolicyE. Returns an
+// UnexpectedIamPolicyDecision error listing the actions that are denied, or whose decision depends on condition keys
+// the simulation has no values for.
+func AssertIamRoleCanE(t testing.TestingT, region string, roleArn string, actions []string, resources []string) error {
+	results, err := SimulateIamPrincipalPolicyE(t, region, roleArn, actions, resources)
+	if err != nil {
+		return err
+	}
+	return checkIamPolicySimulationResults(roleArn, results, true)
+}
+
+// AssertIamRoleCannot checks that the IAM role (or other principal) with the given ARN is denied all the given actions
+// on all the given resources, failing the test if it is not.
+func AssertIamRoleCannot(t testing.TestingT, region string, roleArn string, actions []string, resources []string) {
+	err := AssertIamRoleCannotE(t, region, roleArn, actions, resources)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// AssertIamRoleCannotE checks that the IAM role (or other principal) with the given ARN is denied all the given actions
+// on all the given resources, either explicitly or because no policy allows them, according to
+// SimulateIamPrincipalPolicyE. Returns an UnexpectedIamPolicyDecision error listing the actions that are allowed, or
+// whose decision depends on condition keys the simulation has no values for.
+func AssertIamRoleCannotE(t testing.TestingT, region string, roleArn string, actions []string, resources []string) error {
+	results, err := SimulateIamPrincipalPolicyE(t, region, roleArn, actions, resources)
+	if err != nil {
+		return err
+	}
+	return checkIamPolicySimulationResults(roleArn, results, false)
+}
+
+// newIamPolicySimulationResult converts the given evaluation result returned by the IAM API to an
+// IamPolicySimulationResult.
+func newIamPolicySimulationResult(result types.EvaluationResult) IamPolicySimulationResult {
+	policyIds := []string{}
+	for _, statement := range result.MatchedStatements {
+		policyIds = append(policyIds, aws.ToString(statement.SourcePolicyId))
+	}
+	return IamPolicySimulationResult{
+		Action:               aws.ToString(result.EvalActionName),
+		Resource:             aws.ToString(result.EvalResourceName),
+		Decision:             string(result.EvalDecision),
+		MatchedPolicyIds:     policyIds,
+		MissingContextValues: result.MissingContextValues,
+	}
+}
+
+// checkIamPolicySimulationResults returns an UnexpectedIamPolicyDecision error if any of the given results is not
+// allowed when expectAllowed is true, or is allowed when expectAllowed is false. Results with missing context values
+// are inconclusive, as the decision may change once the condition keys are known, so they are reported whatever their
+// decision is.
+func checkIamPolicySimulationResults(principalArn string, results []IamPolicySimulationResult, expectAllowed bool) error {
+	unexpected := []string{}
+	for _, result := range results {
+		if len(result.MissingContextValues) > 0 {
+			unexpected = append(unexpected, fmt.Sprintf("%s on %s is inconclusive, as it depends on the context keys %s", result.Action, result.Resource, strings.Join(result.MissingContextValues, ", ")))
+			continue
+		}
+		allowed := types.PolicyEvaluationDecisionType(result.Decision) == types.PolicyEvaluationDecisionTypeAllowed
+		if allowed != expectAllowed {
+			unexpected = append(unexpected, fmt.Sprintf("%s on %s is %s", result.Action, result.Resource, result.Decision))
+		}
+	}
+	if len(unexpected) > 0 {
+		return UnexpectedIamPolicyDecision{PrincipalArn: principalArn, ExpectAllowed: expectAllowed, Decisions: strings.Join(unexpected, ", ")}
+	}
+	return nil
+}
+
 // CreateMfaDevice creates an MFA device using the given IAM client.
 func CreateMfaDevice(t testing.TestingT, iamClient *iam.Client, deviceName string) *types.VirtualMFADevice {
 	mfaDevice, err := CreateMfaDeviceE(t, iamClient, deviceName)
@@ -199,7 +332,7 @@ func NewIamClient(t testing.TestingT, region string) *iam.Client {
 
 // NewIamClientE creates a new IAM client.
 func NewIamClientE(t testing.TestingT, region string) (*iam.Client, error) {
-	sess, err := NewAuthenticatedSession(region)
+	sess, err := newAuthenticatedSession(t, region)
 	if err != nil {
 		return nil, err
 	}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
 	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -71,3 +72,43 @@ func TestGetIAMPolicyDocument(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestCheckIamPolicySimulationResults(t *testing.T) {
+	t.Parallel()
+
+	results := []IamPolicySimulationResult{
+		newIamPolicySimulationResult(types.EvaluationResult{
+			EvalActionName:    aws.String("s3:GetObject"),
+			EvalResourceName:  aws.String("arn:aws:s3:::my-bucket/*"),
+			EvalDecision:      types.PolicyEvaluationDecisionTypeAllowed,
+			MatchedStatements: []types.Statement{{SourcePolicyId: aws.String("read-only")}},
+		}),
+		newIamPolicySimulationResult(types.EvaluationResult{
+			EvalActionName:   aws.String("s3:DeleteObject"),
+			EvalResourceName: aws.String("arn:aws:s3:::my-bucket/*"),
+			EvalDecision:     types.PolicyEvaluationDecisionTypeImplicitDeny,
+		}),
+	}
+	assert.Equal(t, []string{"read-only"}, results[0].MatchedPolicyIds)
+
+	roleArn := "arn:aws:iam::123456789012:role/reader"
+	assert.NoError(t, checkIamPolicySimulationResults(roleArn, results[:1], true))
+	assert.NoError(t, checkIamPolicySimulationResults(roleArn, results[1:], false))
+
+	err := checkIamPolicySimulationResults(roleArn, results, true)
+	assert.Equal(t, UnexpectedIamPolicyDecision{PrincipalArn: roleArn, ExpectAllowed: true, Decisions: "s3:DeleteObject on arn:aws:s3:::my-bucket/* is implicitDeny"}, err)
+
+	err = checkIamPolicySimulationResults(roleArn, results, false)
+	assert.Equal(t, UnexpectedIamPolicyDecision{PrincipalArn: roleArn, ExpectAllowed: false, Decisions: "s3:GetObject on arn:aws:s3:::my-bucket/* is allowed"}, err)
+
+	conditional := []IamPolicySimulationResult{
+		newIamPolicySimulationResult(types.EvaluationResult{
+			EvalActionName:       aws.String("s3:PutObject"),
+			EvalResourceName:     aws.String("arn:aws:s3:::my-bucket/*"),
+			EvalDecision:         types.PolicyEvaluationDecisionTypeImplicitDeny,
+			MissingContextValues: []string{"aws:SourceIp"},
+		}),
+	}
+	err = checkIamPolicySimulationResults(roleArn, conditional, false)
+	assert.Equal(t, UnexpectedIamPolicyDecision{PrincipalArn: roleArn, ExpectAllowed: false, Decisions: "s3:PutObject on arn:aws:s3:::my-bucket/* is inconclusive, as it depends on the context keys aws:SourceIp"}, err)
+}
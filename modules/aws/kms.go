@@ -1,13 +1,48 @@
 package aws
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
 
+// KmsGrant contains the details of a grant on a KMS key.
+type KmsGrant struct {
+	GrantID           string    // The ID of the grant
+	Name              string    // The friendly name of the grant. Empty if the grant has no name.
+	GranteePrincipal  string    // The principal that receives the permissions of the grant
+	RetiringPrincipal string    // The principal that can retire the grant. Empty if there is none.
+	Operations        []string  // The operations the grant permits, such as "Encrypt" or "Decrypt"
+	CreationDate      time.Time // The date the grant was created
+}
+
+// kmsKeyPolicyDocument is the subset of a KMS key policy that is needed to check which principals it allows.
+type kmsKeyPolicyDocument struct {
+	Statement []kmsKeyPolicyStatement
+}
+
+// kmsKeyPolicyStatement is a single statement of a KMS key policy. Principal and Action can be a string or a list in
+// the policy JSON, so they are decoded lazily.
+type kmsKeyPolicyStatement struct {
+	Sid       string
+	Effect    string
+	Principal json.RawMessage
+	Action    json.RawMessage
+	Condition json.RawMessage
+}
+
 // GetCmkArn gets the ARN of a KMS Customer Master Key (CMK) in the given region with the given ID. The ID can be an alias, such
 // as "alias/my-cmk".
 func GetCmkArn(t testing.TestingT, region string, cmkID string) string {
@@ -37,6 +72,407 @@ func GetCmkArnE(t testing.TestingT, region string, cmkID string) (string, error)
 	return *result.KeyMetadata.Arn, nil
 }
 
+// KmsEncrypt encrypts the given plaintext with the KMS key with the given ID, which can be a key ID, key ARN or alias
+// such as "alias/my-cmk", and returns the ciphertext blob.
+func KmsEncrypt(t testing.TestingT, region string, keyID string, plaintext []byte) []byte {
+	out, err := KmsEncryptE(t, region, keyID, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// KmsEncryptE encrypts the given plaintext with the KMS key with the given ID, which can be a key ID, key ARN or alias
+// such as "alias/my-cmk", and returns the ciphertext blob.
+func KmsEncryptE(t testing.TestingT, region string, keyID string, plaintext []byte) ([]byte, error) {
+	kmsClient, err := NewKmsClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := kmsClient.Encrypt(context.Background(), &kms.EncryptInput{
+		KeyId:     aws.String(keyID),
+		Plaintext: plaintext,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.CiphertextBlob, nil
+}
+
+// KmsDecrypt decrypts the given ciphertext blob, which was encrypted with a symmetric KMS key, and returns the plaintext.
+func KmsDecrypt(t testing.TestingT, region string, ciphertext []byte) []byte {
+	out, err := KmsDecryptE(t, region, ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// KmsDecryptE decrypts the given ciphertext blob, which was encrypted with a symmetric KMS key, and returns the plaintext.
+// The KMS key is identified from the metadata in the ciphertext blob.
+func KmsDecryptE(t testing.TestingT, region string, ciphertext []byte) ([]byte, error) {
+	kmsClient, err := NewKmsClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := kmsClient.Decrypt(context.Background(), &kms.DecryptInput{
+		CiphertextBlob: ciphertext,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Plaintext, nil
+}
+
+// AssertKmsEncryptionRoundTrip encrypts a random value with the KMS key with the given ID and checks that decrypting
+// the ciphertext returns the same value, failing the test if it does not.
+func AssertKmsEncryptionRoundTrip(t testing.TestingT, region string, keyID string) {
+	err := AssertKmsEncryptionRoundTripE(t, region, keyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// AssertKmsEncryptionRoundTripE encrypts a random value with the KMS key with the given ID and checks that decrypting
+// the ciphertext returns the same value. This proves that the current AWS identity can both encrypt and decrypt with
+// the key.
+func AssertKmsEncryptionRoundTripE(t testing.TestingT, region string, keyID string) error {
+	plaintext := []byte(random.UniqueId())
+
+	logger.Default.Logf(t, "Encrypting and decrypting a random value with KMS key %s in %s", keyID, region)
+
+	ciphertext, err := KmsEncryptE(t, region, keyID, plaintext)
+	if err != nil {
+		return err
+	}
+	decrypted, err := KmsDecryptE(t, region, ciphertext)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(plaintext, decrypted) {
+		return KmsEncryptionRoundTripFailed{KeyID: keyID}
+	}
+	return nil
+}
+
+// GetKmsKeyPolicy gets the JSON document of the default key policy of the KMS key with the given ID.
+func GetKmsKeyPolicy(t testing.TestingT, region string, keyID string) string {
+	out, err := GetKmsKeyPolicyE(t, region, keyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// GetKmsKeyPolicyE gets the JSON document of the default key policy of the KMS key with the given ID.
+func GetKmsKeyPolicyE(t testing.TestingT, region string, keyID string) (string, error) {
+	kmsClient, err := NewKmsClientE(t, region)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := kmsClient.GetKeyPolicy(context.Background(), &kms.GetKeyPolicyInput{
+		KeyId:      aws.String(keyID),
+		PolicyName: aws.String("default"),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return aws.ToString(result.Policy), nil
+}
+
+// AssertKmsKeyPolicyAllows checks that the key policy of the KMS key with the given ID allows the given principal to
+// perform the given action, failing the test if it does not.
+func AssertKmsKeyPolicyAllows(t testing.TestingT, region string, keyID string, principal string, action string) {
+	err := AssertKmsKeyPolicyAllowsE(t, region, keyID, principal, action)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// AssertKmsKeyPolicyAllowsE checks that the key policy of the KMS key with the given ID allows the given principal,
+// such as an IAM role ARN, to perform the given action, such as "kms:Decrypt". A statement matches if it names the
+// principal or "*" (a bare account ID, such as "123456789012", names the root of the account), and names the action or
+// a wildcard pattern matching it, such as "kms:*". Explicit denies take precedence. Statements with a Condition block
+// (e.g. denying requests without aws:SecureTransport) depend on the request, so they are not evaluated: they neither
+// allow nor deny, and they are listed in the error (or logged, if the action is allowed). NotPrincipal and NotAction
+// are not evaluated either, and neither are IAM policies that the key policy delegates to by allowing the account root.
+func AssertKmsKeyPolicyAllowsE(t testing.TestingT, region string, keyID string, principal string, action string) error {
+	policy, err := GetKmsKeyPolicyE(t, region, keyID)
+	if err != nil {
+		return err
+	}
+
+	allowed, notEvaluated, err := kmsKeyPolicyAllows(policy, principal, action)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return KmsKeyPolicyDoesNotAllow{KeyID: keyID, Principal: principal, Action: action, NotEvaluatedStatements: notEvaluated}
+	}
+	if len(notEvaluated) > 0 {
+		logger.Default.Logf(t, "Key policy of KMS key %s allows %s to perform %s, but the conditional statements %s that also match were not evaluated", keyID, principal, action, strings.Join(notEvaluated, ", "))
+	}
+	return nil
+}
+
+// GetKmsKeyGrants gets all the grants on the KMS key with the given ID.
+func GetKmsKeyGrants(t testing.TestingT, region string, keyID string) []KmsGrant {
+	out, err := GetKmsKeyGrantsE(t, region, keyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// GetKmsKeyGrantsE gets all the grants on the KMS key with the given ID, which must be a key ID or key ARN.
+func GetKmsKeyGrantsE(t testing.TestingT, region string, keyID string) ([]KmsGrant, error) {
+	kmsClient, err := NewKmsClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	grants := []KmsGrant{}
+	paginator := kms.NewListGrantsPaginator(kmsClient, &kms.ListGrantsInput{KeyId: aws.String(keyID)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for _, grant := range page.Grants {
+			grants = append(grants, newKmsGrant(grant))
+		}
+	}
+	return grants, nil
+}
+
+// GetKmsKeyAliases gets the names of all the aliases, such as "alias/my-cmk", of the KMS key with the given ID.
+func GetKmsKeyAliases(t testing.TestingT, region string, keyID string) []string {
+	out, err := GetKmsKeyAliasesE(t, region, keyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// GetKmsKeyAliasesE gets the names of all the aliases, such as "alias/my-cmk", of the KMS key with the given ID, which
+// must be a key ID or key ARN.
+func GetKmsKeyAliasesE(t testing.TestingT, region string, keyID string) ([]string, error) {
+	kmsClient, err := NewKmsClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := []string{}
+	paginator := kms.NewListAliasesPaginator(kmsClient, &kms.ListAliasesInput{KeyId: aws.String(keyID)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for _, alias := range page.Aliases {
+			aliases = append(aliases, aws.ToString(alias.AliasName))
+		}
+	}
+	return aliases, nil
+}
+
+// GetKmsKeyIdForAlias gets the ID of the KMS key that the alias with the given name, such as "alias/my-cmk", points to.
+func GetKmsKeyIdForAlias(t testing.TestingT, region string, aliasName string) string {
+	out, err := GetKmsKeyIdForAliasE(t, region, aliasName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// GetKmsKeyIdForAliasE gets the ID of the KMS key that the alias with the given name, such as "alias/my-cmk", points to.
+// Returns a NotFoundError if there is no such alias.
+func GetKmsKeyIdForAliasE(t testing.TestingT, region string, aliasName string) (string, error) {
+	kmsClient, err := NewKmsClientE(t, region)
+	if err != nil {
+		return "", err
+	}
+
+	paginator := kms.NewListAliasesPaginator(kmsClient, &kms.ListAliasesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return "", err
+		}
+		for _, alias := range page.Aliases {
+			if aws.ToString(alias.AliasName) == aliasName && alias.TargetKeyId != nil {
+				return aws.ToString(alias.TargetKeyId), nil
+			}
+		}
+	}
+	return "", NewNotFoundError("KMS alias", aliasName, region)
+}
+
+// newKmsGrant converts the given grant returned by the KMS API into a KmsGrant.
+func newKmsGrant(grant types.GrantListEntry) KmsGrant {
+	operations := []string{}
+	for _, operation := range grant.Operations {
+		operations = append(operations, string(operation))
+	}
+	return KmsGrant{
+		GrantID:           aws.ToString(grant.GrantId),
+		Name:              aws.ToString(grant.Name),
+		GranteePrincipal:  aws.ToString(grant.GranteePrincipal),
+		RetiringPrincipal: aws.ToString(grant.RetiringPrincipal),
+		Operations:        operations,
+		CreationDate:      aws.ToTime(grant.CreationDate),
+	}
+}
+
+// kmsKeyPolicyAllows returns true if the given key policy has an unconditional Allow statement, and no unconditional
+// Deny statement, that matches the given principal and action. It also returns the Sids (or positions, for statements
+// without a Sid) of the statements that match but were not evaluated because they have a Condition block.
+func kmsKeyPolicyAllows(policy string, principal string, action string) (bool, []string, error) {
+	var document kmsKeyPolicyDocument
+	if err := json.Unmarshal([]byte(policy), &document); err != nil {
+		return false, nil, err
+	}
+
+	allowed := false
+	notEvaluated := []string{}
+	for i, statement := range document.Statement {
+		principalMatches, err := kmsKeyPolicyPrincipalMatches(statement.Principal, principal)
+		if err != nil {
+			return false, nil, err
+		}
+		actionMatches, err := kmsKeyPolicyActionMatches(statement.Action, action)
+		if err != nil {
+			return false, nil, err
+		}
+		if !principalMatches || !actionMatches {
+			continue
+		}
+
+		if kmsKeyPolicyHasCondition(statement.Condition) {
+			name := statement.Sid
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			notEvaluated = append(notEvaluated, fmt.Sprintf("%s (%s)", name, statement.Effect))
+			continue
+		}
+
+		switch statement.Effect {
+		case "Deny":
+			return false, notEvaluated, nil
+		case "Allow":
+			allowed = true
+		}
+	}
+	return allowed, notEvaluated, nil
+}
+
+// kmsKeyPolicyHasCondition returns true if the given Condition element of a key policy statement is set and not empty.
+func kmsKeyPolicyHasCondition(rawCondition json.RawMessage) bool {
+	condition := strings.TrimSpace(string(rawCondition))
+	return condition != "" && condition != "null" && condition != "{}"
+}
+
+// kmsKeyPolicyPrincipalMatches returns true if the given Principal element of a key policy statement, which is either
+// "*" or a map from principal type ("AWS", "Service", ...) to one or more principals, matches the given principal.
+func kmsKeyPolicyPrincipalMatches(rawPrincipal json.RawMessage, principal string) (bool, error) {
+	if len(rawPrincipal) == 0 {
+		return false, nil
+	}
+
+	var wildcard string
+	if err := json.Unmarshal(rawPrincipal, &wildcard); err == nil {
+		return wildcard == "*", nil
+	}
+
+	var principalsByType map[string]json.RawMessage
+	if err := json.Unmarshal(rawPrincipal, &principalsByType); err != nil {
+		return false, err
+	}
+	partition := getArnPartition(principal)
+	principal = normalizeKmsKeyPolicyPrincipal(principal, partition)
+	for principalType, rawPrincipals := range principalsByType {
+		principals, err := decodeKmsKeyPolicyStringOrList(rawPrincipals)
+		if err != nil {
+			return false, err
+		}
+		for _, policyPrincipal := range principals {
+			if principalType == "AWS" {
+				policyPrincipal = normalizeKmsKeyPolicyPrincipal(policyPrincipal, partition)
+			}
+			if policyPrincipal == "*" || policyPrincipal == principal {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// accountIDRegexp matches a bare AWS account ID.
+var accountIDRegexp = regexp.MustCompile(`^[0-9]{12}$`)
+
+// normalizeKmsKeyPolicyPrincipal returns the ARN of the root user of the account if the given AWS principal is a bare
+// account ID, which policies accept as a shorthand for it (e.g. "AWS": "123456789012"), and the principal as is
+// otherwise.
+func normalizeKmsKeyPolicyPrincipal(principal string, partition string) string {
+	if accountIDRegexp.MatchString(principal) {
+		return fmt.Sprintf("arn:%s:iam::%s:root", partition, principal)
+	}
+	return principal
+}
+
+// getArnPartition returns the partition of the given ARN, such as aws or aws-cn, or aws if it is not an ARN.
+func getArnPartition(arn string) string {
+	parts := strings.SplitN(arn, ":", 3)
+	if len(parts) == 3 && parts[0] == "arn" && parts[1] != "" {
+		return parts[1]
+	}
+	return "aws"
+}
+
+// kmsKeyPolicyActionMatches returns true if the given Action element of a key policy statement contains the given
+// action or a wildcard pattern matching it. Actions are case insensitive.
+func kmsKeyPolicyActionMatches(rawAction json.RawMessage, action string) (bool, error) {
+	if len(rawAction) == 0 {
+		return false, nil
+	}
+
+	actions, err := decodeKmsKeyPolicyStringOrList(rawAction)
+	if err != nil {
+		return false, err
+	}
+	for _, policyAction := range actions {
+		matches, err := path.Match(strings.ToLower(policyAction), strings.ToLower(action))
+		if err != nil {
+			return false, err
+		}
+		if matches {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// decodeKmsKeyPolicyStringOrList decodes a key policy element that can be either a single string or a list of strings.
+func decodeKmsKeyPolicyStringOrList(raw json.RawMessage) ([]string, error) {
+	var value string
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return []string{value}, nil
+	}
+
+	var values []string
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
 // NewKmsClient creates a KMS client.
 func NewKmsClient(t testing.TestingT, region string) *kms.Client {
 	client, err := NewKmsClientE(t, region)
@@ -48,7 +484,7 @@ func NewKmsClient(t testing.TestingT, region string) *kms.Client {
 
 // NewKmsClientE creates a KMS client.
 func NewKmsClientE(t testing.TestingT, region string) (*kms.Client, error) {
-	sess, err := NewAuthenticatedSession(region)
+	sess, err := newAuthenticatedSession(t, region)
 	if err != nil {
 		return nil, err
 	}
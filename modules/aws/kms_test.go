@@ -0,0 +1,145 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKmsKeyPolicy = `{
+	"Version": "2012-10-17",
+	"Statement": [
+		{
+			"Sid": "Enable IAM User Permissions",
+			"Effect": "Allow",
+			"Principal": {"AWS": "arn:aws:iam::123456789012:root"},
+			"Action": "kms:*",
+			"Resource": "*"
+		},
+		{
+			"Sid": "Allow use of the key",
+			"Effect": "Allow",
+			"Principal": {"AWS": ["arn:aws:iam::123456789012:role/app", "arn:aws:iam::123456789012:role/ops"]},
+			"Action": ["kms:Encrypt", "kms:Decrypt", "kms:GenerateDataKey*"],
+			"Resource": "*"
+		},
+		{
+			"Sid": "Deny decryption to ops",
+			"Effect": "Deny",
+			"Principal": {"AWS": "arn:aws:iam::123456789012:role/ops"},
+			"Action": "kms:Decrypt",
+			"Resource": "*"
+		}
+	]
+}`
+
+func TestKmsKeyPolicyAllows(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		principal string
+		action    string
+		expected  bool
+	}{
+		{"account root with wildcard action", "arn:aws:iam::123456789012:root", "kms:ScheduleKeyDeletion", true},
+		{"role in principal list", "arn:aws:iam::123456789012:role/app", "kms:Decrypt", true},
+		{"action wildcard suffix", "arn:aws:iam::123456789012:role/app", "kms:GenerateDataKeyWithoutPlaintext", true},
+		{"case insensitive action", "arn:aws:iam::123456789012:role/app", "KMS:encrypt", true},
+		{"action not allowed", "arn:aws:iam::123456789012:role/app", "kms:PutKeyPolicy", false},
+		{"explicit deny", "arn:aws:iam::123456789012:role/ops", "kms:Decrypt", false},
+		{"unknown principal", "arn:aws:iam::123456789012:role/other", "kms:Encrypt", false},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			allowed, notEvaluated, err := kmsKeyPolicyAllows(testKmsKeyPolicy, testCase.principal, testCase.action)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, allowed)
+			assert.Empty(t, notEvaluated)
+		})
+	}
+}
+
+func TestKmsKeyPolicyAllowsWildcardPrincipal(t *testing.T) {
+	t.Parallel()
+
+	policy := `{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "kms:Decrypt"}]}`
+	allowed, _, err := kmsKeyPolicyAllows(policy, "arn:aws:iam::123456789012:role/any", "kms:Decrypt")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	_, _, err = kmsKeyPolicyAllows("not json", "arn:aws:iam::123456789012:role/any", "kms:Decrypt")
+	assert.Error(t, err)
+}
+
+func TestKmsKeyPolicyAllowsBareAccountIDPrincipal(t *testing.T) {
+	t.Parallel()
+
+	policy := `{"Statement": [{"Effect": "Allow", "Principal": {"AWS": "123456789012"}, "Action": "kms:*", "Resource": "*"}]}`
+
+	allowed, _, err := kmsKeyPolicyAllows(policy, "arn:aws:iam::123456789012:root", "kms:Decrypt")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, _, err = kmsKeyPolicyAllows(policy, "123456789012", "kms:Decrypt")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, _, err = kmsKeyPolicyAllows(policy, "arn:aws:iam::210987654321:root", "kms:Decrypt")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestKmsKeyPolicyAllowsSkipsConditionalStatements(t *testing.T) {
+	t.Parallel()
+
+	policy := `{"Statement": [
+		{"Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::123456789012:role/app"}, "Action": "kms:Decrypt"},
+		{
+			"Sid": "DenyInsecureTransport",
+			"Effect": "Deny",
+			"Principal": "*",
+			"Action": "kms:*",
+			"Condition": {"Bool": {"aws:SecureTransport": "false"}}
+		},
+		{
+			"Effect": "Allow",
+			"Principal": {"AWS": "arn:aws:iam::123456789012:role/ci"},
+			"Action": "kms:Decrypt",
+			"Condition": {"StringEquals": {"kms:ViaService": "s3.us-east-1.amazonaws.com"}}
+		}
+	]}`
+
+	// A conditional deny doesn't make an unconditional allow fail.
+	allowed, notEvaluated, err := kmsKeyPolicyAllows(policy, "arn:aws:iam::123456789012:role/app", "kms:Decrypt")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, []string{"DenyInsecureTransport (Deny)"}, notEvaluated)
+
+	// A conditional allow doesn't count as allowing the principal.
+	allowed, notEvaluated, err = kmsKeyPolicyAllows(policy, "arn:aws:iam::123456789012:role/ci", "kms:Decrypt")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, []string{"DenyInsecureTransport (Deny)", "#3 (Allow)"}, notEvaluated)
+}
+
+func TestNewKmsGrant(t *testing.T) {
+	t.Parallel()
+
+	grant := newKmsGrant(types.GrantListEntry{
+		GrantId:          aws.String("abc123"),
+		GranteePrincipal: aws.String("arn:aws:iam::123456789012:role/app"),
+		Operations:       []types.GrantOperation{types.GrantOperationEncrypt, types.GrantOperationDecrypt},
+	})
+
+	assert.Equal(t, "abc123", grant.GrantID)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/app", grant.GranteePrincipal)
+	assert.Empty(t, grant.RetiringPrincipal)
+	assert.Equal(t, []string{"Encrypt", "Decrypt"}, grant.Operations)
+}
@@ -2,12 +2,20 @@ package aws
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	cloudwatchlogstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
 	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/gruntwork-io/terratest/modules/retry"
 	"github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/require"
 )
@@ -175,6 +183,372 @@ func (err *FunctionError) Error() string {
 	return fmt.Sprintf("%q error with status code %d invoking lambda function: %q", err.Message, err.StatusCode, err.Payload)
 }
 
+// LambdaInvocation is the response of a synchronous invocation of a Lambda function.
+type LambdaInvocation struct {
+	StatusCode      int32  // The HTTP status code of the invocation (200 if the function was invoked)
+	Payload         []byte // The response of the function, or the error object if it failed
+	FunctionError   string // Set (to Unhandled) if the function failed
+	ExecutedVersion string // The version of the function that was invoked (e.g. $LATEST or the version an alias points to)
+	LogTail         string // The last 4 KB of the logs of the invocation
+}
+
+// LambdaFunctionConfiguration is the configuration of a Lambda function.
+type LambdaFunctionConfiguration struct {
+	FunctionName     string            // The name of the function
+	FunctionArn      string            // The ARN of the function
+	Version          string            // The version of the function
+	Description      string            // The description of the function
+	Runtime          string            // The runtime of the function (e.g. python3.12). Empty for container image functions.
+	Handler          string            // The handler of the function (e.g. main.handler)
+	PackageType      string            // How the function is deployed (Zip or Image)
+	Architectures    []string          // The instruction set architectures of the function (e.g. arm64)
+	MemorySize       int32             // The memory of the function, in MB
+	Timeout          int32             // The timeout of the function, in seconds
+	Role             string            // The ARN of the execution role of the function
+	Environment      map[string]string // The environment variables of the function
+	Layers           []string          // The ARNs of the layers of the function
+	KmsKeyArn        string            // The ARN of the KMS key that encrypts the environment variables. Empty if the AWS managed key is used.
+	TracingMode      string            // The X-Ray tracing mode of the function (Active or PassThrough)
+	SubnetIds        []string          // The IDs of the subnets of the function, if it's connected to a VPC
+	SecurityGroupIds []string          // The IDs of the security groups of the function, if it's connected to a VPC
+	DeadLetterArn    string            // The ARN of the SQS queue or SNS topic to which failed asynchronous invocations are sent
+	LogGroup         string            // The CloudWatch log group of the function
+	State            string            // The state of the function (e.g. Active)
+	StateReason      string            // Why the function is in its state
+	LastUpdateStatus string            // The status of the last update of the function (e.g. Successful)
+}
+
+// LambdaAlias is an alias of a Lambda function, which points to one (or, when shifting traffic, two) of its versions.
+type LambdaAlias struct {
+	Name                     string             // The name of the alias
+	Arn                      string             // The ARN of the alias
+	FunctionVersion          string             // The version the alias points to
+	Description              string             // The description of the alias
+	AdditionalVersionWeights map[string]float64 // The weights of the other versions the alias routes traffic to, keyed by version
+}
+
+// InvokeLambdaWithPayload invokes the Lambda function with the given name (or ARN) in the given region synchronously,
+// with the given payload converted to JSON, and returns its response along with the tail of its logs. Add a version or
+// alias to the function name (e.g. my-function:live) to invoke it. This will fail the test if the function fails.
+func InvokeLambdaWithPayload(t testing.TestingT, region string, functionName string, payload interface{}) *LambdaInvocation {
+	invocation, err := InvokeLambdaWithPayloadE(t, region, functionName, payload)
+	require.NoError(t, err)
+	return invocation
+}
+
+// InvokeLambdaWithPayloadE invokes the Lambda function with the given name (or ARN) in the given region synchronously,
+// with the given payload converted to JSON, and returns its response along with the tail of its logs. Add a version or
+// alias to the function name (e.g. my-function:live) to invoke it. If the function fails, the response is returned
+// along with a FunctionError with the error (message, type and stack trace) the function returned as payload.
+func InvokeLambdaWithPayloadE(t testing.TestingT, region string, functionName string, payload interface{}) (*LambdaInvocation, error) {
+	client, err := NewLambdaClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &lambda.InvokeInput{
+		FunctionName:   aws.String(functionName),
+		InvocationType: types.InvocationTypeRequestResponse,
+		LogType:        types.LogTypeTail,
+	}
+	if payload != nil {
+		payloadJson, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		input.Payload = payloadJson
+	}
+
+	output, err := client.Invoke(context.Background(), input)
+	if err != nil {
+		return nil, err
+	}
+
+	invocation := &LambdaInvocation{
+		StatusCode:      output.StatusCode,
+		Payload:         output.Payload,
+		FunctionError:   aws.ToString(output.FunctionError),
+		ExecutedVersion: aws.ToString(output.ExecutedVersion),
+	}
+	if output.LogResult != nil {
+		logTail, err := base64.StdEncoding.DecodeString(*output.LogResult)
+		if err != nil {
+			return nil, err
+		}
+		invocation.LogTail = string(logTail)
+	}
+
+	if invocation.FunctionError != "" {
+		return invocation, &FunctionError{Message: invocation.FunctionError, StatusCode: invocation.StatusCode, Payload: invocation.Payload}
+	}
+	return invocation, nil
+}
+
+// GetLambdaFunctionConfiguration fetches the configuration of the Lambda function with the given name (or ARN) in the
+// given region. Add a version or alias to the function name (e.g. my-function:live) to fetch the configuration of that
+// version.
+func GetLambdaFunctionConfiguration(t testing.TestingT, region string, functionName string) *LambdaFunctionConfiguration {
+	configuration, err := GetLambdaFunctionConfigurationE(t, region, functionName)
+	require.NoError(t, err)
+	return configuration
+}
+
+// GetLambdaFunctionConfigurationE fetches the configuration of the Lambda function with the given name (or ARN) in the
+// given region. Add a version or alias to the function name (e.g. my-function:live) to fetch the configuration of that
+// version. Returns a NotFoundError if the function does not exist.
+func GetLambdaFunctionConfigurationE(t testing.TestingT, region string, functionName string) (*LambdaFunctionConfiguration, error) {
+	client, err := NewLambdaClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetFunctionConfiguration(context.Background(), &lambda.GetFunctionConfigurationInput{
+		FunctionName: aws.String(functionName),
+	})
+	if err != nil {
+		return nil, notFoundOr[*types.ResourceNotFoundException](err, "Lambda function", functionName, region)
+	}
+
+	configuration := &LambdaFunctionConfiguration{
+		FunctionName:     aws.ToString(output.FunctionName),
+		FunctionArn:      aws.ToString(output.FunctionArn),
+		Version:          aws.ToString(output.Version),
+		Description:      aws.ToString(output.Description),
+		Runtime:          string(output.Runtime),
+		Handler:          aws.ToString(output.Handler),
+		PackageType:      string(output.PackageType),
+		Architectures:    []string{},
+		MemorySize:       aws.ToInt32(output.MemorySize),
+		Timeout:          aws.ToInt32(output.Timeout),
+		Role:             aws.ToString(output.Role),
+		Environment:      map[string]string{},
+		Layers:           []string{},
+		KmsKeyArn:        aws.ToString(output.KMSKeyArn),
+		LogGroup:         "/aws/lambda/" + aws.ToString(output.FunctionName),
+		State:            string(output.State),
+		StateReason:      aws.ToString(output.StateReason),
+		LastUpdateStatus: string(output.LastUpdateStatus),
+	}
+	for _, architecture := range output.Architectures {
+		configuration.Architectures = append(configuration.Architectures, string(architecture))
+	}
+	if output.Environment != nil && output.Environment.Variables != nil {
+		configuration.Environment = output.Environment.Variables
+	}
+	for _, layer := range output.Layers {
+		configuration.Layers = append(configuration.Layers, aws.ToString(layer.Arn))
+	}
+	if output.TracingConfig != nil {
+		configuration.TracingMode = string(output.TracingConfig.Mode)
+	}
+	if output.VpcConfig != nil {
+		configuration.SubnetIds = output.VpcConfig.SubnetIds
+		configuration.SecurityGroupIds = output.VpcConfig.SecurityGroupIds
+	}
+	if output.DeadLetterConfig != nil {
+		configuration.DeadLetterArn = aws.ToString(output.DeadLetterConfig.TargetArn)
+	}
+	if output.LoggingConfig != nil && output.LoggingConfig.LogGroup != nil {
+		configuration.LogGroup = aws.ToString(output.LoggingConfig.LogGroup)
+	}
+
+	return configuration, nil
+}
+
+// WaitForLambdaActive waits until the Lambda function with the given name (or ARN) in the given region is active and
+// its last update is done, so that it can be invoked, and returns its configuration. This will fail the test if the
+// function is not active after the given number of retries, or if it failed.
+func WaitForLambdaActive(t testing.TestingT, region string, functionName string, retries int, sleepBetweenRetries time.Duration) *LambdaFunctionConfiguration {
+	configuration, err := WaitForLambdaActiveE(t, region, functionName, retries, sleepBetweenRetries)
+	require.NoError(t, err)
+	return configuration
+}
+
+// WaitForLambdaActiveE waits until the Lambda function with the given name (or ARN) in the given region is active and
+// its last update is done, so that it can be invoked, and returns its configuration. Stops waiting early if the function
+// or its last update failed.
+func WaitForLambdaActiveE(t testing.TestingT, region string, functionName string, retries int, sleepBetweenRetries time.Duration) (*LambdaFunctionConfiguration, error) {
+	description := fmt.Sprintf("Waiting for Lambda function %s to be active", functionName)
+	out, err := retry.DoWithRetryInterfaceE(t, description, retries, sleepBetweenRetries, func() (interface{}, error) {
+		configuration, err := GetLambdaFunctionConfigurationE(t, region, functionName)
+		if err != nil {
+			return nil, err
+		}
+		return configuration, checkLambdaActive(configuration)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.(*LambdaFunctionConfiguration), nil
+}
+
+// checkLambdaActive returns nil if the function with the given configuration is active and its last update is done, a
+// retry.FatalError if the function or its last update failed, and an error to retry otherwise.
+func checkLambdaActive(configuration *LambdaFunctionConfiguration) error {
+	name := configuration.FunctionName
+	switch {
+	case configuration.State == string(types.StateFailed):
+		return retry.FatalError{Underlying: fmt.Errorf("Lambda function %s failed: %s", name, configuration.StateReason)}
+	case configuration.LastUpdateStatus == string(types.LastUpdateStatusFailed):
+		return retry.FatalError{Underlying: fmt.Errorf("the last update of Lambda function %s failed", name)}
+	case configuration.State != string(types.StateActive):
+		return fmt.Errorf("Lambda function %s is still %s", name, configuration.State)
+	case configuration.LastUpdateStatus == string(types.LastUpdateStatusInProgress):
+		return fmt.Errorf("Lambda function %s is still being updated", name)
+	default:
+		return nil
+	}
+}
+
+// GetLambdaAlias fetches the alias with the given name of the Lambda function with the given name in the given region.
+func GetLambdaAlias(t testing.TestingT, region string, functionName string, aliasName string) *LambdaAlias {
+	alias, err := GetLambdaAliasE(t, region, functionName, aliasName)
+	require.NoError(t, err)
+	return alias
+}
+
+// GetLambdaAliasE fetches the alias with the given name of the Lambda function with the given name in the given region.
+// Returns a NotFoundError if the function or the alias does not exist.
+func GetLambdaAliasE(t testing.TestingT, region string, functionName string, aliasName string) (*LambdaAlias, error) {
+	client, err := NewLambdaClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetAlias(context.Background(), &lambda.GetAliasInput{
+		FunctionName: aws.String(functionName),
+		Name:         aws.String(aliasName),
+	})
+	if err != nil {
+		return nil, notFoundOr[*types.ResourceNotFoundException](err, "Lambda alias", functionName+":"+aliasName, region)
+	}
+
+	alias := &LambdaAlias{
+		Name:                     aws.ToString(output.Name),
+		Arn:                      aws.ToString(output.AliasArn),
+		FunctionVersion:          aws.ToString(output.FunctionVersion),
+		Description:              aws.ToString(output.Description),
+		AdditionalVersionWeights: map[string]float64{},
+	}
+	if output.RoutingConfig != nil && output.RoutingConfig.AdditionalVersionWeights != nil {
+		alias.AdditionalVersionWeights = output.RoutingConfig.AdditionalVersionWeights
+	}
+	return alias, nil
+}
+
+// GetLambdaFunctionVersions fetches the published versions (e.g. 1, 2) of the Lambda function with the given name in
+// the given region, in the order they were published, without $LATEST.
+func GetLambdaFunctionVersions(t testing.TestingT, region string, functionName string) []string {
+	versions, err := GetLambdaFunctionVersionsE(t, region, functionName)
+	require.NoError(t, err)
+	return versions
+}
+
+// GetLambdaFunctionVersionsE fetches the published versions (e.g. 1, 2) of the Lambda function with the given name in
+// the given region, in the order they were published, without $LATEST. Returns a NotFoundError if the function does not
+// exist.
+func GetLambdaFunctionVersionsE(t testing.TestingT, region string, functionName string) ([]string, error) {
+	client, err := NewLambdaClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &lambda.ListVersionsByFunctionInput{FunctionName: aws.String(functionName)}
+	versions := []string{}
+	for {
+		output, err := client.ListVersionsByFunction(context.Background(), input)
+		if err != nil {
+			return nil, notFoundOr[*types.ResourceNotFoundException](err, "Lambda function", functionName, region)
+		}
+
+		for _, version := range output.Versions {
+			if aws.ToString(version.Version) != "$LATEST" {
+				versions = append(versions, aws.ToString(version.Version))
+			}
+		}
+
+		if output.NextMarker == nil {
+			break
+		}
+		input.Marker = output.NextMarker
+	}
+
+	return versions, nil
+}
+
+// lambdaLogStreamsToRead is the max number of the most recent log streams of a Lambda function GetLambdaLogLines reads.
+const lambdaLogStreamsToRead = 10
+
+// GetLambdaLogLines fetches the last (up to) the given number of lines the Lambda function with the given name in the
+// given region logged to CloudWatch, oldest first.
+func GetLambdaLogLines(t testing.TestingT, region string, functionName string, numLines int) []string {
+	lines, err := GetLambdaLogLinesE(t, region, functionName, numLines)
+	require.NoError(t, err)
+	return lines
+}
+
+// GetLambdaLogLinesE fetches the last (up to) the given number of lines the Lambda function with the given name in the
+// given region logged to CloudWatch, oldest first. The lines are read from the most recent log streams of the log group
+// of the function, since each instance of the function logs to its own stream. Note that it can take a few seconds for
+// the logs of an invocation to show up in CloudWatch.
+func GetLambdaLogLinesE(t testing.TestingT, region string, functionName string, numLines int) ([]string, error) {
+	if numLines <= 0 {
+		return []string{}, nil
+	}
+
+	configuration, err := GetLambdaFunctionConfigurationE(t, region, functionName)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewCloudWatchLogsClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	streams, err := client.DescribeLogStreams(context.Background(), &cloudwatchlogs.DescribeLogStreamsInput{
+		LogGroupName: aws.String(configuration.LogGroup),
+		OrderBy:      cloudwatchlogstypes.OrderByLastEventTime,
+		Descending:   aws.Bool(true),
+		Limit:        aws.Int32(lambdaLogStreamsToRead),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := []cloudwatchlogstypes.OutputLogEvent{}
+	for _, stream := range streams.LogStreams {
+		output, err := client.GetLogEvents(context.Background(), &cloudwatchlogs.GetLogEventsInput{
+			LogGroupName:  aws.String(configuration.LogGroup),
+			LogStreamName: stream.LogStreamName,
+			StartFromHead: aws.Bool(false),
+			Limit:         aws.Int32(int32(numLines)),
+		})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, output.Events...)
+	}
+
+	return lastLogLines(events, numLines), nil
+}
+
+// lastLogLines returns the messages of the given number of most recent log events, oldest first, without their trailing
+// newlines.
+func lastLogLines(events []cloudwatchlogstypes.OutputLogEvent, numLines int) []string {
+	sort.SliceStable(events, func(i, j int) bool {
+		return aws.ToInt64(events[i].Timestamp) < aws.ToInt64(events[j].Timestamp)
+	})
+	if len(events) > numLines {
+		events = events[len(events)-numLines:]
+	}
+
+	lines := []string{}
+	for _, event := range events {
+		lines = append(lines, strings.TrimRight(aws.ToString(event.Message), "\n"))
+	}
+	return lines
+}
+
 // NewLambdaClient creates a new Lambda client.
 func NewLambdaClient(t testing.TestingT, region string) *lambda.Client {
 	client, err := NewLambdaClientE(t, region)
@@ -184,7 +558,7 @@ func NewLambdaClient(t testing.TestingT, region string) *lambda.Client {
 
 // NewLambdaClientE creates a new Lambda client.
 func NewLambdaClientE(t testing.TestingT, region string) (*lambda.Client, error) {
-	sess, err := NewAuthenticatedSession(region)
+	sess, err := newAuthenticatedSession(t, region)
 	if err != nil {
 		return nil, err
 	}
@@ -3,6 +3,10 @@ package aws
 import (
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cloudwatchlogstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -15,3 +19,25 @@ func TestFunctionError(t *testing.T) {
 	require.Contains(t, err.Error(), "123")
 	require.Contains(t, err.Error(), "payload")
 }
+
+func TestCheckLambdaActive(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, checkLambdaActive(&LambdaFunctionConfiguration{State: "Active", LastUpdateStatus: "Successful"}))
+	assert.Error(t, checkLambdaActive(&LambdaFunctionConfiguration{State: "Pending"}))
+	assert.Error(t, checkLambdaActive(&LambdaFunctionConfiguration{State: "Active", LastUpdateStatus: "InProgress"}))
+	assert.IsType(t, retry.FatalError{}, checkLambdaActive(&LambdaFunctionConfiguration{State: "Failed"}))
+	assert.IsType(t, retry.FatalError{}, checkLambdaActive(&LambdaFunctionConfiguration{State: "Active", LastUpdateStatus: "Failed"}))
+}
+
+func TestLastLogLines(t *testing.T) {
+	t.Parallel()
+
+	events := []cloudwatchlogstypes.OutputLogEvent{
+		{Timestamp: aws.Int64(3), Message: aws.String("third\n")},
+		{Timestamp: aws.Int64(1), Message: aws.String("first\n")},
+		{Timestamp: aws.Int64(2), Message: aws.String("second\n")},
+	}
+	assert.Equal(t, []string{"second", "third"}, lastLogLines(events, 2))
+	assert.Equal(t, []string{"first", "second", "third"}, lastLogLines(events, 5))
+}
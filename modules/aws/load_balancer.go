@@ -0,0 +1,428 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// targetHealthPollInterval is the time to wait between checks of the health of the targets of a target group.
+const targetHealthPollInterval = 10 * time.Second
+
+// LoadBalancer is an Application, Network or Gateway Load Balancer.
+type LoadBalancer struct {
+	Name                  string   // The name of the load balancer
+	Arn                   string   // The ARN of the load balancer
+	Type                  string   // The type of the load balancer (application, network or gateway)
+	Scheme                string   // The scheme of the load balancer (internet-facing or internal)
+	State                 string   // The state of the load balancer (e.g. active or provisioning)
+	DNSName               string   // The public DNS name of the load balancer
+	CanonicalHostedZoneId string   // The ID of the Route 53 hosted zone of the load balancer, for alias records
+	VpcId                 string   // The ID of the VPC of the load balancer
+	SubnetIds             []string // The IDs of the subnets of the load balancer
+	SecurityGroupIds      []string // The IDs of the security groups of the load balancer. Empty for most Network Load Balancers.
+}
+
+// TargetHealth is the health of a target registered with a target group.
+type TargetHealth struct {
+	TargetId    string // The ID of the target: an instance ID, IP address, Lambda function ARN or ALB ARN
+	Port        int32  // The port the target receives traffic on
+	State       string // The health state of the target (e.g. healthy, unhealthy, initial or draining)
+	Reason      string // The reason code for the state, if the target is not healthy
+	Description string // A description of the reason for the state, if the target is not healthy
+}
+
+// LoadBalancerListener is a listener of a load balancer.
+type LoadBalancerListener struct {
+	Arn      string // The ARN of the listener
+	Port     int32  // The port the listener listens on
+	Protocol string // The protocol of the listener (e.g. HTTP, HTTPS or TCP)
+}
+
+// ListenerRule is a rule of a listener of an Application Load Balancer.
+type ListenerRule struct {
+	Arn        string                  // The ARN of the rule
+	Priority   string                  // The priority of the rule, or "default" for the default rule
+	IsDefault  bool                    // Whether the rule is the default rule of the listener
+	Conditions []ListenerRuleCondition // The conditions a request must match for the rule to apply. Empty for the default rule.
+	Actions    []ListenerRuleAction    // The actions of the rule, in order
+}
+
+// ListenerRuleCondition is a condition of a listener rule.
+type ListenerRuleCondition struct {
+	Field      string   // The field of the request (host-header, path-pattern, http-header, http-request-method, query-string or source-ip)
+	HeaderName string   // The name of the HTTP header, for http-header conditions
+	Values     []string // The values the field is matched against. Query strings are formatted as key=value, or value without a key.
+}
+
+// ListenerRuleAction is an action of a listener rule.
+type ListenerRuleAction struct {
+	Type            string   // The type of the action (forward, redirect, fixed-response, authenticate-oidc or authenticate-cognito)
+	Order           int32    // The order of the action in the rule
+	TargetGroupArns []string // The ARNs of the target groups that requests are forwarded to, for forward actions
+	StatusCode      string   // The HTTP status code of the response, for redirect and fixed-response actions
+	RedirectUrl     string   // The URL requests are redirected to, for redirect actions. May contain placeholders such as #{host}.
+}
+
+// GetLoadBalancer fetches the Application, Network or Gateway Load Balancer with the given name in the given region.
+func GetLoadBalancer(t testing.TestingT, region string, name string) *LoadBalancer {
+	loadBalancer, err := GetLoadBalancerE(t, region, name)
+	require.NoError(t, err)
+	return loadBalancer
+}
+
+// GetLoadBalancerE fetches the Application, Network or Gateway Load Balancer with the given name in the given region.
+// Returns a NotFoundError if the load balancer does not exist.
+func GetLoadBalancerE(t testing.TestingT, region string, name string) (*LoadBalancer, error) {
+	client, err := NewElbV2ClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.DescribeLoadBalancers(context.Background(), &elbv2.DescribeLoadBalancersInput{Names: []string{name}})
+	if err != nil {
+		var notFoundErr *types.LoadBalancerNotFoundException
+		if errors.As(err, &notFoundErr) {
+			return nil, NewNotFoundError("Load balancer", name, region)
+		}
+		return nil, err
+	}
+	if len(output.LoadBalancers) == 0 {
+		return nil, NewNotFoundError("Load balancer", name, region)
+	}
+	return newLoadBalancer(&output.LoadBalancers[0]), nil
+}
+
+// newLoadBalancer converts the given load balancer returned by the ELB API to a LoadBalancer.
+func newLoadBalancer(description *types.LoadBalancer) *LoadBalancer {
+	loadBalancer := &LoadBalancer{
+		Name:                  aws.ToString(description.LoadBalancerName),
+		Arn:                   aws.ToString(description.LoadBalancerArn),
+		Type:                  string(description.Type),
+		Scheme:                string(description.Scheme),
+		DNSName:               aws.ToString(description.DNSName),
+		CanonicalHostedZoneId: aws.ToString(description.CanonicalHostedZoneId),
+		VpcId:                 aws.ToString(description.VpcId),
+		SubnetIds:             []string{},
+		SecurityGroupIds:      description.SecurityGroups,
+	}
+	if description.State != nil {
+		loadBalancer.State = string(description.State.Code)
+	}
+	for _, zone := range description.AvailabilityZones {
+		loadBalancer.SubnetIds = append(loadBalancer.SubnetIds, aws.ToString(zone.SubnetId))
+	}
+	return loadBalancer
+}
+
+// GetTargetGroupHealth fetches the health of the targets registered with the target group with the given ARN.
+func GetTargetGroupHealth(t testing.TestingT, region string, targetGroupArn string) []TargetHealth {
+	targets, err := GetTargetGroupHealthE(t, region, targetGroupArn)
+	require.NoError(t, err)
+	return targets
+}
+
+// GetTargetGroupHealthE fetches the health of the targets registered with the target group with the given ARN.
+// Returns a NotFoundError if the target group does not exist.
+func GetTargetGroupHealthE(t testing.TestingT, region string, targetGroupArn string) ([]TargetHealth, error) {
+	client, err := NewElbV2ClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.DescribeTargetHealth(context.Background(), &elbv2.DescribeTargetHealthInput{TargetGroupArn: aws.String(targetGroupArn)})
+	if err != nil {
+		var notFoundErr *types.TargetGroupNotFoundException
+		if errors.As(err, &notFoundErr) {
+			return nil, NewNotFoundError("Target group", targetGroupArn, region)
+		}
+		return nil, err
+	}
+	return newTargetHealths(output.TargetHealthDescriptions), nil
+}
+
+// newTargetHealths converts the given target health descriptions returned by the ELB API to TargetHealths.
+func newTargetHealths(descriptions []types.TargetHealthDescription) []TargetHealth {
+	targets := []TargetHealth{}
+	for _, description := range descriptions {
+		target := TargetHealth{}
+		if description.Target != nil {
+			target.TargetId = aws.ToString(description.Target.Id)
+			target.Port = aws.ToInt32(description.Target.Port)
+		}
+		if description.TargetHealth != nil {
+			target.State = string(description.TargetHealth.State)
+			target.Reason = string(description.TargetHealth.Reason)
+			target.Description = aws.ToString(description.TargetHealth.Description)
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// WaitForAllTargetsHealthy waits up to the given timeout for all the targets registered with the target group with the
+// given ARN to be healthy and returns their health.
+func WaitForAllTargetsHealthy(t testing.TestingT, region string, targetGroupArn string, timeout time.Duration) []TargetHealth {
+	targets, err := WaitForAllTargetsHealthyE(t, region, targetGroupArn, timeout)
+	require.NoError(t, err)
+	return targets
+}
+
+// WaitForAllTargetsHealthyE waits up to the given timeout for all the targets registered with the target group with the
+// given ARN to be healthy and returns their health. The health is checked every few seconds, and the target group must
+// have at least one target. Note that targets only become healthy after passing the healthy threshold of consecutive
+// health checks, so the timeout should be at least a few health check intervals long.
+func WaitForAllTargetsHealthyE(t testing.TestingT, region string, targetGroupArn string, timeout time.Duration) ([]TargetHealth, error) {
+	retries := int(timeout / targetHealthPollInterval)
+	if retries < 1 {
+		retries = 1
+	}
+
+	description := fmt.Sprintf("Waiting for all targets of target group %s to be healthy", targetGroupArn)
+	out, err := retry.DoWithRetryInterfaceE(t, description, retries, targetHealthPollInterval, func() (interface{}, error) {
+		targets, err := GetTargetGroupHealthE(t, region, targetGroupArn)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkAllTargetsHealthy(targetGroupArn, targets); err != nil {
+			return nil, err
+		}
+		return targets, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	targets := out.([]TargetHealth)
+	logger.Default.Logf(t, "All %d targets of target group %s are healthy", len(targets), targetGroupArn)
+	return targets, nil
+}
+
+// checkAllTargetsHealthy returns an error listing the targets that are not healthy, or if there are no targets at all.
+func checkAllTargetsHealthy(targetGroupArn string, targets []TargetHealth) error {
+	if len(targets) == 0 {
+		return fmt.Errorf("target group %s has no registered targets", targetGroupArn)
+	}
+
+	unhealthy := []string{}
+	for _, target := range targets {
+		if types.TargetHealthStateEnum(target.State) != types.TargetHealthStateEnumHealthy {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s:%d is %s (%s)", target.TargetId, target.Port, target.State, target.Reason))
+		}
+	}
+	if len(unhealthy) > 0 {
+		return fmt.Errorf("%d of %d targets of target group %s are not healthy: %s", len(unhealthy), len(targets), targetGroupArn, strings.Join(unhealthy, ", "))
+	}
+	return nil
+}
+
+// GetLoadBalancerListeners fetches the listeners of the load balancer with the given ARN.
+func GetLoadBalancerListeners(t testing.TestingT, region string, loadBalancerArn string) []LoadBalancerListener {
+	listeners, err := GetLoadBalancerListenersE(t, region, loadBalancerArn)
+	require.NoError(t, err)
+	return listeners
+}
+
+// GetLoadBalancerListenersE fetches the listeners of the load balancer with the given ARN.
+func GetLoadBalancerListenersE(t testing.TestingT, region string, loadBalancerArn string) ([]LoadBalancerListener, error) {
+	client, err := NewElbV2ClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	listeners := []LoadBalancerListener{}
+	paginator := elbv2.NewDescribeListenersPaginator(client, &elbv2.DescribeListenersInput{LoadBalancerArn: aws.String(loadBalancerArn)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for _, listener := range page.Listeners {
+			listeners = append(listeners, LoadBalancerListener{
+				Arn:      aws.ToString(listener.ListenerArn),
+				Port:     aws.ToInt32(listener.Port),
+				Protocol: string(listener.Protocol),
+			})
+		}
+	}
+	return listeners, nil
+}
+
+// GetListenerRules fetches the rules of the listener with the given ARN, sorted by priority with the default rule
+// last.
+func GetListenerRules(t testing.TestingT, region string, listenerArn string) []ListenerRule {
+	rules, err := GetListenerRulesE(t, region, listenerArn)
+	require.NoError(t, err)
+	return rules
+}
+
+// GetListenerRulesE fetches the rules of the listener with the given ARN, sorted by priority with the default rule
+// last.
+func GetListenerRulesE(t testing.TestingT, region string, listenerArn string) ([]ListenerRule, error) {
+	client, err := NewElbV2ClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &elbv2.DescribeRulesInput{ListenerArn: aws.String(listenerArn)}
+	rules := []ListenerRule{}
+	for {
+		output, err := client.DescribeRules(context.Background(), input)
+		if err != nil {
+			return nil, err
+		}
+		for i := range output.Rules {
+			rules = append(rules, newListenerRule(&output.Rules[i]))
+		}
+		if aws.ToString(output.NextMarker) == "" {
+			break
+		}
+		input.Marker = output.NextMarker
+	}
+
+	sortListenerRules(rules)
+	return rules, nil
+}
+
+// GetListenerRuleForPriority fetches the rule with the given priority, or "default" for the default rule, of the
+// listener with the given ARN.
+func GetListenerRuleForPriority(t testing.TestingT, region string, listenerArn string, priority string) *ListenerRule {
+	rule, err := GetListenerRuleForPriorityE(t, region, listenerArn, priority)
+	require.NoError(t, err)
+	return rule
+}
+
+// GetListenerRuleForPriorityE fetches the rule with the given priority, or "default" for the default rule, of the
+// listener with the given ARN. Returns a NotFoundError if the listener has no rule with that priority.
+func GetListenerRuleForPriorityE(t testing.TestingT, region string, listenerArn string, priority string) (*ListenerRule, error) {
+	rules, err := GetListenerRulesE(t, region, listenerArn)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rules {
+		if rules[i].Priority == priority {
+			return &rules[i], nil
+		}
+	}
+	return nil, NewNotFoundError("Listener rule", fmt.Sprintf("%s with priority %s", listenerArn, priority), region)
+}
+
+// newListenerRule converts the given rule returned by the ELB API to a ListenerRule.
+func newListenerRule(rule *types.Rule) ListenerRule {
+	listenerRule := ListenerRule{
+		Arn:        aws.ToString(rule.RuleArn),
+		Priority:   aws.ToString(rule.Priority),
+		IsDefault:  aws.ToBool(rule.IsDefault),
+		Conditions: []ListenerRuleCondition{},
+		Actions:    []ListenerRuleAction{},
+	}
+	for i := range rule.Conditions {
+		listenerRule.Conditions = append(listenerRule.Conditions, newListenerRuleCondition(&rule.Conditions[i]))
+	}
+	for i := range rule.Actions {
+		listenerRule.Actions = append(listenerRule.Actions, newListenerRuleAction(&rule.Actions[i]))
+	}
+	sort.SliceStable(listenerRule.Actions, func(i, j int) bool { return listenerRule.Actions[i].Order < listenerRule.Actions[j].Order })
+	return listenerRule
+}
+
+// newListenerRuleCondition converts the given rule condition returned by the ELB API to a ListenerRuleCondition. The
+// values are read from the config of the field if it is set, as the legacy Values field is only set for some fields.
+func newListenerRuleCondition(condition *types.RuleCondition) ListenerRuleCondition {
+	listenerRuleCondition := ListenerRuleCondition{
+		Field:  aws.ToString(condition.Field),
+		Values: condition.Values,
+	}
+	switch {
+	case condition.HostHeaderConfig != nil:
+		listenerRuleCondition.Values = condition.HostHeaderConfig.Values
+	case condition.PathPatternConfig != nil:
+		listenerRuleCondition.Values = condition.PathPatternConfig.Values
+	case condition.HttpHeaderConfig != nil:
+		listenerRuleCondition.HeaderName = aws.ToString(condition.HttpHeaderConfig.HttpHeaderName)
+		listenerRuleCondition.Values = condition.HttpHeaderConfig.Values
+	case condition.HttpRequestMethodConfig != nil:
+		listenerRuleCondition.Values = condition.HttpRequestMethodConfig.Values
+	case condition.SourceIpConfig != nil:
+		listenerRuleCondition.Values = condition.SourceIpConfig.Values
+	case condition.QueryStringConfig != nil:
+		listenerRuleCondition.Values = []string{}
+		for _, pair := range condition.QueryStringConfig.Values {
+			if pair.Key == nil {
+				listenerRuleCondition.Values = append(listenerRuleCondition.Values, aws.ToString(pair.Value))
+			} else {
+				listenerRuleCondition.Values = append(listenerRuleCondition.Values, aws.ToString(pair.Key)+"="+aws.ToString(pair.Value))
+			}
+		}
+	}
+	if listenerRuleCondition.Values == nil {
+		listenerRuleCondition.Values = []string{}
+	}
+	return listenerRuleCondition
+}
+
+// newListenerRuleAction converts the given rule action returned by the ELB API to a ListenerRuleAction.
+func newListenerRuleAction(action *types.Action) ListenerRuleAction {
+	listenerRuleAction := ListenerRuleAction{
+		Type:            string(action.Type),
+		Order:           aws.ToInt32(action.Order),
+		TargetGroupArns: []string{},
+	}
+	if action.ForwardConfig != nil {
+		for _, targetGroup := range action.ForwardConfig.TargetGroups {
+			listenerRuleAction.TargetGroupArns = append(listenerRuleAction.TargetGroupArns, aws.ToString(targetGroup.TargetGroupArn))
+		}
+	} else if action.TargetGroupArn != nil {
+		listenerRuleAction.TargetGroupArns = append(listenerRuleAction.TargetGroupArns, aws.ToString(action.TargetGroupArn))
+	}
+	if action.RedirectConfig != nil {
+		redirect := action.RedirectConfig
+		listenerRuleAction.StatusCode = strings.TrimPrefix(string(redirect.StatusCode), "HTTP_")
+		listenerRuleAction.RedirectUrl = fmt.Sprintf("%s://%s:%s%s?%s", strings.ToLower(aws.ToString(redirect.Protocol)), aws.ToString(redirect.Host), aws.ToString(redirect.Port), aws.ToString(redirect.Path), aws.ToString(redirect.Query))
+	}
+	if action.FixedResponseConfig != nil {
+		listenerRuleAction.StatusCode = aws.ToString(action.FixedResponseConfig.StatusCode)
+	}
+	return listenerRuleAction
+}
+
+// sortListenerRules sorts the given rules by priority, with the default rule last.
+func sortListenerRules(rules []ListenerRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].IsDefault || rules[j].IsDefault {
+			return !rules[i].IsDefault && rules[j].IsDefault
+		}
+		priorityI, _ := strconv.Atoi(rules[i].Priority)
+		priorityJ, _ := strconv.Atoi(rules[j].Priority)
+		return priorityI < priorityJ
+	})
+}
+
+// NewElbV2Client creates a new Elastic Load Balancing v2 client, for Application, Network and Gateway Load Balancers.
+func NewElbV2Client(t testing.TestingT, region string) *elbv2.Client {
+	client, err := NewElbV2ClientE(t, region)
+	require.NoError(t, err)
+	return client
+}
+
+// NewElbV2ClientE creates a new Elastic Load Balancing v2 client, for Application, Network and Gateway Load Balancers.
+func NewElbV2ClientE(t testing.TestingT, region string) (*elbv2.Client, error) {
+	sess, err := newAuthenticatedSession(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return elbv2.NewFromConfig(*sess), nil
+}
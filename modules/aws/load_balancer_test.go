@@ -0,0 +1,80 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLoadBalancer(t *testing.T) {
+	t.Parallel()
+
+	loadBalancer := newLoadBalancer(&types.LoadBalancer{
+		LoadBalancerName:  aws.String("my-alb"),
+		Type:              types.LoadBalancerTypeEnumApplication,
+		Scheme:            types.LoadBalancerSchemeEnumInternetFacing,
+		State:             &types.LoadBalancerState{Code: types.LoadBalancerStateEnumActive},
+		DNSName:           aws.String("my-alb-123.us-east-1.elb.amazonaws.com"),
+		AvailabilityZones: []types.AvailabilityZone{{ZoneName: aws.String("us-east-1a"), SubnetId: aws.String("subnet-1")}, {ZoneName: aws.String("us-east-1b"), SubnetId: aws.String("subnet-2")}},
+	})
+
+	assert.Equal(t, "my-alb", loadBalancer.Name)
+	assert.Equal(t, "application", loadBalancer.Type)
+	assert.Equal(t, "internet-facing", loadBalancer.Scheme)
+	assert.Equal(t, "active", loadBalancer.State)
+	assert.Equal(t, []string{"subnet-1", "subnet-2"}, loadBalancer.SubnetIds)
+}
+
+func TestCheckAllTargetsHealthy(t *testing.T) {
+	t.Parallel()
+
+	targets := newTargetHealths([]types.TargetHealthDescription{
+		{Target: &types.TargetDescription{Id: aws.String("i-1"), Port: aws.Int32(80)}, TargetHealth: &types.TargetHealth{State: types.TargetHealthStateEnumHealthy}},
+		{Target: &types.TargetDescription{Id: aws.String("i-2"), Port: aws.Int32(80)}, TargetHealth: &types.TargetHealth{State: types.TargetHealthStateEnumInitial, Reason: types.TargetHealthReasonEnumRegistrationInProgress}},
+	})
+	require.Len(t, targets, 2)
+	assert.Equal(t, TargetHealth{TargetId: "i-2", Port: 80, State: "initial", Reason: "Elb.RegistrationInProgress"}, targets[1])
+
+	err := checkAllTargetsHealthy("my-tg", targets)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "i-2:80 is initial")
+
+	require.NoError(t, checkAllTargetsHealthy("my-tg", targets[:1]))
+	assert.Error(t, checkAllTargetsHealthy("my-tg", []TargetHealth{}))
+}
+
+func TestNewListenerRule(t *testing.T) {
+	t.Parallel()
+
+	rule := newListenerRule(&types.Rule{
+		Priority: aws.String("10"),
+		Conditions: []types.RuleCondition{
+			{Field: aws.String("path-pattern"), PathPatternConfig: &types.PathPatternConditionConfig{Values: []string{"/api/*"}}},
+			{Field: aws.String("query-string"), QueryStringConfig: &types.QueryStringConditionConfig{Values: []types.QueryStringKeyValuePair{{Key: aws.String("version"), Value: aws.String("v2")}}}},
+		},
+		Actions: []types.Action{
+			{Type: types.ActionTypeEnumForward, Order: aws.Int32(2), TargetGroupArn: aws.String("arn:tg")},
+			{Type: types.ActionTypeEnumRedirect, Order: aws.Int32(1), RedirectConfig: &types.RedirectActionConfig{Protocol: aws.String("HTTPS"), Host: aws.String("#{host}"), Port: aws.String("443"), Path: aws.String("/#{path}"), Query: aws.String("#{query}"), StatusCode: types.RedirectActionStatusCodeEnumHttp301}},
+		},
+	})
+
+	assert.Equal(t, "10", rule.Priority)
+	assert.Equal(t, []ListenerRuleCondition{{Field: "path-pattern", Values: []string{"/api/*"}}, {Field: "query-string", Values: []string{"version=v2"}}}, rule.Conditions)
+	require.Len(t, rule.Actions, 2)
+	assert.Equal(t, "redirect", rule.Actions[0].Type)
+	assert.Equal(t, "301", rule.Actions[0].StatusCode)
+	assert.Equal(t, "https://#{host}:443/#{path}?#{query}", rule.Actions[0].RedirectUrl)
+	assert.Equal(t, []string{"arn:tg"}, rule.Actions[1].TargetGroupArns)
+}
+
+func TestSortListenerRules(t *testing.T) {
+	t.Parallel()
+
+	rules := []ListenerRule{{Priority: "default", IsDefault: true}, {Priority: "100"}, {Priority: "9"}}
+	sortListenerRules(rules)
+
+	assert.Equal(t, []string{"9", "100", "default"}, []string{rules[0].Priority, rules[1].Priority, rules[2].Priority})
+}
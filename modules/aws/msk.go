@@ -0,0 +1,125 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kafka"
+	"github.com/aws/aws-sdk-go-v2/service/kafka/types"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// MskCluster is an Amazon Managed Streaming for Apache Kafka (MSK) provisioned cluster.
+type MskCluster struct {
+	Arn                 string // The ARN of the cluster
+	Name                string // The name of the cluster
+	State               string // The state of the cluster (e.g. CREATING, ACTIVE or UPDATING)
+	KafkaVersion        string // The Apache Kafka version of the brokers (e.g. 3.5.1)
+	NumberOfBrokerNodes int32  // The number of broker nodes in the cluster
+	BrokerInstanceType  string // The instance type of the broker nodes (e.g. kafka.m5.large)
+	CurrentVersion      string // The current version of the cluster configuration, which is needed to update the cluster
+}
+
+// MskBootstrapBrokers contains the connection strings clients use to connect to the brokers of an MSK cluster. Each
+// field is a comma-separated list of host:port pairs, or empty if the corresponding authentication method is disabled.
+type MskBootstrapBrokers struct {
+	Plaintext string // The brokers accepting unauthenticated plaintext connections
+	Tls       string // The brokers accepting TLS connections
+	SaslScram string // The brokers accepting SASL/SCRAM connections
+	SaslIam   string // The brokers accepting IAM-authenticated connections
+}
+
+// GetMskCluster fetches the state, Kafka version and broker count of the MSK cluster with the given ARN.
+func GetMskCluster(t testing.TestingT, region string, clusterArn string) *MskCluster {
+	cluster, err := GetMskClusterE(t, region, clusterArn)
+	require.NoError(t, err)
+	return cluster
+}
+
+// GetMskClusterE fetches the state, Kafka version and broker count of the MSK cluster with the given ARN. Returns a
+// NotFoundError if the cluster does not exist.
+func GetMskClusterE(t testing.TestingT, region string, clusterArn string) (*MskCluster, error) {
+	client, err := NewMskClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.DescribeCluster(context.Background(), &kafka.DescribeClusterInput{
+		ClusterArn: aws.String(clusterArn),
+	})
+	if err != nil {
+		return nil, notFoundOr[*types.NotFoundException](err, "MSK cluster", clusterArn, region)
+	}
+	if output.ClusterInfo == nil {
+		return nil, NewNotFoundError("MSK cluster", clusterArn, region)
+	}
+
+	return newMskCluster(*output.ClusterInfo), nil
+}
+
+// GetMskBootstrapBrokers fetches the connection strings of the brokers of the MSK cluster with the given ARN.
+func GetMskBootstrapBrokers(t testing.TestingT, region string, clusterArn string) *MskBootstrapBrokers {
+	brokers, err := GetMskBootstrapBrokersE(t, region, clusterArn)
+	require.NoError(t, err)
+	return brokers
+}
+
+// GetMskBootstrapBrokersE fetches the connection strings of the brokers of the MSK cluster with the given ARN. Returns
+// a NotFoundError if the cluster does not exist. The brokers are only available once the cluster is ACTIVE.
+func GetMskBootstrapBrokersE(t testing.TestingT, region string, clusterArn string) (*MskBootstrapBrokers, error) {
+	client, err := NewMskClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.GetBootstrapBrokers(context.Background(), &kafka.GetBootstrapBrokersInput{
+		ClusterArn: aws.String(clusterArn),
+	})
+	if err != nil {
+		return nil, notFoundOr[*types.NotFoundException](err, "MSK cluster", clusterArn, region)
+	}
+
+	return &MskBootstrapBrokers{
+		Plaintext: aws.ToString(output.BootstrapBrokerString),
+		Tls:       aws.ToString(output.BootstrapBrokerStringTls),
+		SaslScram: aws.ToString(output.BootstrapBrokerStringSaslScram),
+		SaslIam:   aws.ToString(output.BootstrapBrokerStringSaslIam),
+	}, nil
+}
+
+func newMskCluster(cluster types.ClusterInfo) *MskCluster {
+	result := &MskCluster{
+		Arn:                 aws.ToString(cluster.ClusterArn),
+		Name:                aws.ToString(cluster.ClusterName),
+		State:               string(cluster.State),
+		NumberOfBrokerNodes: aws.ToInt32(cluster.NumberOfBrokerNodes),
+		CurrentVersion:      aws.ToString(cluster.CurrentVersion),
+	}
+
+	if softwareInfo := cluster.CurrentBrokerSoftwareInfo; softwareInfo != nil {
+		result.KafkaVersion = aws.ToString(softwareInfo.KafkaVersion)
+	}
+	if nodeGroupInfo := cluster.BrokerNodeGroupInfo; nodeGroupInfo != nil {
+		result.BrokerInstanceType = aws.ToString(nodeGroupInfo.InstanceType)
+	}
+
+	return result
+}
+
+// NewMskClient creates a new MSK client.
+func NewMskClient(t testing.TestingT, region string) *kafka.Client {
+	client, err := NewMskClientE(t, region)
+	require.NoError(t, err)
+	return client
+}
+
+// NewMskClientE creates a new MSK client.
+func NewMskClientE(t testing.TestingT, region string) (*kafka.Client, error) {
+	sess, err := newAuthenticatedSession(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return kafka.NewFromConfig(*sess), nil
+}
@@ -0,0 +1,28 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kafka/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMskCluster(t *testing.T) {
+	t.Parallel()
+
+	cluster := newMskCluster(types.ClusterInfo{
+		ClusterArn:                aws.String("arn:aws:kafka:us-east-1:123456789012:cluster/demo/abc"),
+		ClusterName:               aws.String("demo"),
+		State:                     types.ClusterStateActive,
+		NumberOfBrokerNodes:       aws.Int32(3),
+		CurrentBrokerSoftwareInfo: &types.BrokerSoftwareInfo{KafkaVersion: aws.String("3.5.1")},
+		BrokerNodeGroupInfo:       &types.BrokerNodeGroupInfo{InstanceType: aws.String("kafka.m5.large")},
+	})
+
+	assert.Equal(t, "demo", cluster.Name)
+	assert.Equal(t, "ACTIVE", cluster.State)
+	assert.Equal(t, "3.5.1", cluster.KafkaVersion)
+	assert.Equal(t, int32(3), cluster.NumberOfBrokerNodes)
+	assert.Equal(t, "kafka.m5.large", cluster.BrokerInstanceType)
+}
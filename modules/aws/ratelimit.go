@@ -0,0 +1,61 @@
+package aws
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"golang.org/x/time/rate"
+)
+
+var (
+	globalRateLimiterMutex sync.RWMutex
+	globalRateLimiter      *rate.Limiter
+)
+
+// SetGlobalRateLimit limits the rate of the requests that are sent to the AWS APIs by all the clients created after
+// this call, across all the tests of the process, to the given number of requests per second. This helps avoid
+// Throttling and RequestLimitExceeded errors when running a large number of tests in parallel. Retries of throttled
+// requests made by the SDK are limited as well. Pass 0 to disable rate limiting again, which is the default.
+func SetGlobalRateLimit(requestsPerSecond float64) {
+	globalRateLimiterMutex.Lock()
+	defer globalRateLimiterMutex.Unlock()
+
+	if requestsPerSecond <= 0 {
+		globalRateLimiter = nil
+		return
+	}
+	globalRateLimiter = rate.NewLimiter(rate.Limit(requestsPerSecond), 1)
+}
+
+// applyGlobalRateLimit wraps the HTTP client of the given config so that its requests are limited by the global rate
+// limit, if one is set with SetGlobalRateLimit.
+func applyGlobalRateLimit(cfg *aws.Config) {
+	globalRateLimiterMutex.RLock()
+	limiter := globalRateLimiter
+	globalRateLimiterMutex.RUnlock()
+
+	if limiter == nil {
+		return
+	}
+
+	client := cfg.HTTPClient
+	if client == nil {
+		client = awshttp.NewBuildableClient()
+	}
+	cfg.HTTPClient = &rateLimitedHTTPClient{client: client, limiter: limiter}
+}
+
+// rateLimitedHTTPClient is an HTTP client for the AWS SDK that waits for the given limiter before sending each request.
+type rateLimitedHTTPClient struct {
+	client  aws.HTTPClient
+	limiter *rate.Limiter
+}
+
+func (c *rateLimitedHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if err := c.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return c.client.Do(req)
+}
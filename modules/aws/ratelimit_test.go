@@ -0,0 +1,69 @@
+package aws
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+type countingHTTPClient struct {
+	mutex    sync.Mutex
+	requests int
+}
+
+func (c *countingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.requests++
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+func TestRateLimitedHTTPClientDelaysBurst(t *testing.T) {
+	t.Parallel()
+
+	counter := &countingHTTPClient{}
+	client := &rateLimitedHTTPClient{client: counter, limiter: rate.NewLimiter(rate.Limit(20), 1)}
+
+	start := time.Now()
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			req, err := http.NewRequest(http.MethodGet, "https://example.com", nil)
+			require.NoError(t, err)
+			_, err = client.Do(req)
+			require.NoError(t, err)
+		}()
+	}
+	waitGroup.Wait()
+
+	// The first request goes through right away, and each of the 4 others has to wait for 1/20th of a second
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	assert.Equal(t, 5, counter.requests)
+}
+
+// This test changes the global rate limit, so it can't run in parallel with other tests that create configs.
+func TestApplyGlobalRateLimit(t *testing.T) {
+	defer SetGlobalRateLimit(0)
+
+	cfg := &aws.Config{}
+	applyGlobalRateLimit(cfg)
+	assert.Nil(t, cfg.HTTPClient)
+
+	SetGlobalRateLimit(10)
+	applyGlobalRateLimit(cfg)
+	require.IsType(t, &rateLimitedHTTPClient{}, cfg.HTTPClient)
+	assert.Equal(t, rate.Limit(10), cfg.HTTPClient.(*rateLimitedHTTPClient).limiter.Limit())
+
+	SetGlobalRateLimit(0)
+	otherCfg := &aws.Config{}
+	applyGlobalRateLimit(otherCfg)
+	assert.Nil(t, otherCfg.HTTPClient)
+}
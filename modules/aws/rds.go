@@ -3,12 +3,20 @@ package aws
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 	"github.com/aws/aws-sdk-go-v2/service/rds/types"
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/ssh"
 	"github.com/gruntwork-io/terratest/modules/testing"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/stretchr/testify/require"
@@ -253,7 +261,7 @@ func NewRdsClient(t testing.TestingT, region string) *rds.Client {
 
 // NewRdsClientE creates an RDS client.
 func NewRdsClientE(t testing.TestingT, region string) (*rds.Client, error) {
-	sess, err := NewAuthenticatedSession(region)
+	sess, err := newAuthenticatedSession(t, region)
 	if err != nil {
 		return nil, err
 	}
@@ -345,6 +353,812 @@ func GetValidEngineVersionE(t testing.TestingT, region string, engine string, ma
 	return *out.DBEngineVersions[0].EngineVersion, nil
 }
 
+const (
+	// RdsSnapshotTypeAll matches both automated and manual RDS snapshots.
+	RdsSnapshotTypeAll = ""
+	// RdsSnapshotTypeAutomated matches only the snapshots RDS takes automatically during the backup window.
+	RdsSnapshotTypeAutomated = "automated"
+	// RdsSnapshotTypeManual matches only the snapshots that were explicitly created.
+	RdsSnapshotTypeManual = "manual"
+)
+
+// GetRdsSnapshotsForInstance gets all the automated and manual snapshots of the given RDS instance in the given region.
+func GetRdsSnapshotsForInstance(t testing.TestingT, region string, dbInstanceID string) []types.DBSnapshot {
+	snapshots, err := GetRdsSnapshotsForInstanceE(t, region, dbInstanceID)
+	require.NoError(t, err)
+	return snapshots
+}
+
+// GetRdsSnapshotsForInstanceE gets all the automated and manual snapshots of the given RDS instance in the given region.
+func GetRdsSnapshotsForInstanceE(t testing.TestingT, region string, dbInstanceID string) ([]types.DBSnapshot, error) {
+	return GetRdsSnapshotsOfTypeForInstanceE(t, region, dbInstanceID, RdsSnapshotTypeAll)
+}
+
+// GetRdsSnapshotsOfTypeForInstance gets the snapshots of the given RDS instance in the given region, filtered by
+// snapshotType, which should be one of RdsSnapshotTypeAll, RdsSnapshotTypeAutomated or RdsSnapshotTypeManual.
+func GetRdsSnapshotsOfTypeForInstance(t testing.TestingT, region string, dbInstanceID string, snapshotType string) []types.DBSnapshot {
+	snapshots, err := GetRdsSnapshotsOfTypeForInstanceE(t, region, dbInstanceID, snapshotType)
+	require.NoError(t, err)
+	return snapshots
+}
+
+// GetRdsSnapshotsOfTypeForInstanceE gets the snapshots of the given RDS instance in the given region, filtered by
+// snapshotType, which should be one of RdsSnapshotTypeAll, RdsSnapshotTypeAutomated or RdsSnapshotTypeManual.
+func GetRdsSnapshotsOfTypeForInstanceE(t testing.TestingT, region string, dbInstanceID string, snapshotType string) ([]types.DBSnapshot, error) {
+	rdsClient, err := NewRdsClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := rds.DescribeDBSnapshotsInput{DBInstanceIdentifier: aws.String(dbInstanceID)}
+	if snapshotType != RdsSnapshotTypeAll {
+		input.SnapshotType = aws.String(snapshotType)
+	}
+
+	var allSnapshots []types.DBSnapshot
+	for {
+		output, err := rdsClient.DescribeDBSnapshots(context.Background(), &input)
+		if err != nil {
+			return nil, err
+		}
+
+		allSnapshots = append(allSnapshots, output.DBSnapshots...)
+		if output.Marker == nil {
+			break
+		}
+
+		input.Marker = output.Marker
+	}
+	return allSnapshots, nil
+}
+
+// GetLatestRdsSnapshot gets the most recently created snapshot, automated or manual, of the given RDS instance in the
+// given region.
+func GetLatestRdsSnapshot(t testing.TestingT, region string, dbInstanceID string) *types.DBSnapshot {
+	snapshot, err := GetLatestRdsSnapshotE(t, region, dbInstanceID)
+	require.NoError(t, err)
+	return snapshot
+}
+
+// GetLatestRdsSnapshotE gets the most recently created snapshot, automated or manual, of the given RDS instance in the
+// given region. Returns a NotFoundError if the instance has no snapshots.
+func GetLatestRdsSnapshotE(t testing.TestingT, region string, dbInstanceID string) (*types.DBSnapshot, error) {
+	snapshots, err := GetRdsSnapshotsForInstanceE(t, region, dbInstanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := latestRdsSnapshot(snapshots)
+	if latest == nil {
+		return nil, NewNotFoundError("RDS snapshot for instance", dbInstanceID, region)
+	}
+	return latest, nil
+}
+
+// latestRdsSnapshot returns the snapshot with the most recent creation time, or nil if there are no snapshots with a
+// creation time (e.g. because they are all still being created).
+func latestRdsSnapshot(snapshots []types.DBSnapshot) *types.DBSnapshot {
+	var latest *types.DBSnapshot
+	for i := range snapshots {
+		snapshot := snapshots[i]
+		if snapshot.SnapshotCreateTime == nil {
+			continue
+		}
+		if latest == nil || snapshot.SnapshotCreateTime.After(*latest.SnapshotCreateTime) {
+			latest = &snapshot
+		}
+	}
+	return latest
+}
+
+// WaitForRdsSnapshotAvailable waits until the RDS snapshot with the given identifier is in the available state,
+// retrying the check for the specified amount of times, sleeping for the provided duration between each try. This will
+// fail the test if the snapshot does not become available in time.
+func WaitForRdsSnapshotAvailable(t testing.TestingT, region string, snapshotID string, maxRetries int, sleepBetweenRetries time.Duration) {
+	err := WaitForRdsSnapshotAvailableE(t, region, snapshotID, maxRetries, sleepBetweenRetries)
+	require.NoError(t, err)
+}
+
+// WaitForRdsSnapshotAvailableE waits until the RDS snapshot with the given identifier is in the available state,
+// retrying the check for the specified amount of times, sleeping for the provided duration between each try.
+func WaitForRdsSnapshotAvailableE(t testing.TestingT, region string, snapshotID string, maxRetries int, sleepBetweenRetries time.Duration) error {
+	rdsClient, err := NewRdsClientE(t, region)
+	if err != nil {
+		return err
+	}
+
+	description := fmt.Sprintf("Waiting for RDS snapshot %s to be available", snapshotID)
+	_, err = retry.DoWithRetryE(t, description, maxRetries, sleepBetweenRetries, func() (string, error) {
+		output, err := rdsClient.DescribeDBSnapshots(context.Background(), &rds.DescribeDBSnapshotsInput{
+			DBSnapshotIdentifier: aws.String(snapshotID),
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(output.DBSnapshots) == 0 {
+			return "", NewNotFoundError("RDS snapshot", snapshotID, region)
+		}
+
+		status := aws.ToString(output.DBSnapshots[0].Status)
+		if status != "available" {
+			return "", fmt.Errorf("RDS snapshot %s is in status %s", snapshotID, status)
+		}
+		return "", nil
+	})
+	return err
+}
+
+// GetRdsClusterDetails gets the details of the RDS cluster, such as an Aurora cluster, with the given identifier in the
+// given region.
+func GetRdsClusterDetails(t testing.TestingT, dbClusterID string, awsRegion string) *types.DBCluster {
+	dbCluster, err := GetRdsClusterDetailsE(t, dbClusterID, awsRegion)
+	require.NoError(t, err)
+	return dbCluster
+}
+
+// GetRdsClusterDetailsE gets the details of the RDS cluster, such as an Aurora cluster, with the given identifier in the
+// given region. Returns a NotFoundError if there is no such cluster.
+func GetRdsClusterDetailsE(t testing.TestingT, dbClusterID string, awsRegion string) (*types.DBCluster, error) {
+	rdsClient, err := NewRdsClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := rdsClient.DescribeDBClusters(context.Background(), &rds.DescribeDBClustersInput{
+		DBClusterIdentifier: aws.String(dbClusterID),
+	})
+	if err != nil {
+		var notFoundErr *types.DBClusterNotFoundFault
+		if errors.As(err, &notFoundErr) {
+			return nil, NewNotFoundError("RDS cluster", dbClusterID, awsRegion)
+		}
+		return nil, err
+	}
+	if len(output.DBClusters) == 0 {
+		return nil, NewNotFoundError("RDS cluster", dbClusterID, awsRegion)
+	}
+	return &output.DBClusters[0], nil
+}
+
+// GetAddressOfRdsCluster gets the address of the writer endpoint of the given RDS cluster in the given region.
+func GetAddressOfRdsCluster(t testing.TestingT, dbClusterID string, awsRegion string) string {
+	address, err := GetAddressOfRdsClusterE(t, dbClusterID, awsRegion)
+	require.NoError(t, err)
+	return address
+}
+
+// GetAddressOfRdsClusterE gets the address of the writer endpoint of the given RDS cluster in the given region.
+func GetAddressOfRdsClusterE(t testing.TestingT, dbClusterID string, awsRegion string) (string, error) {
+	dbCluster, err := GetRdsClusterDetailsE(t, dbClusterID, awsRegion)
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(dbCluster.Endpoint), nil
+}
+
+// GetReaderAddressOfRdsCluster gets the address of the reader endpoint of the given RDS cluster in the given region.
+func GetReaderAddressOfRdsCluster(t testing.TestingT, dbClusterID string, awsRegion string) string {
+	address, err := GetReaderAddressOfRdsClusterE(t, dbClusterID, awsRegion)
+	require.NoError(t, err)
+	return address
+}
+
+// GetReaderAddressOfRdsClusterE gets the address of the reader endpoint of the given RDS cluster in the given region.
+func GetReaderAddressOfRdsClusterE(t testing.TestingT, dbClusterID string, awsRegion string) (string, error) {
+	dbCluster, err := GetRdsClusterDetailsE(t, dbClusterID, awsRegion)
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(dbCluster.ReaderEndpoint), nil
+}
+
+// GetPortOfRdsCluster gets the port of the given RDS cluster in the given region.
+func GetPortOfRdsCluster(t testing.TestingT, dbClusterID string, awsRegion string) int32 {
+	port, err := GetPortOfRdsClusterE(t, dbClusterID, awsRegion)
+	require.NoError(t, err)
+	return port
+}
+
+// GetPortOfRdsClusterE gets the port of the given RDS cluster in the given region.
+func GetPortOfRdsClusterE(t testing.TestingT, dbClusterID string, awsRegion string) (int32, error) {
+	dbCluster, err := GetRdsClusterDetailsE(t, dbClusterID, awsRegion)
+	if err != nil {
+		return -1, err
+	}
+	return aws.ToInt32(dbCluster.Port), nil
+}
+
+// GetRdsClusterMemberIDs gets the identifiers of the writer and reader DB instances of the given RDS cluster in the
+// given region. The writer is empty if the cluster has no writer.
+func GetRdsClusterMemberIDs(t testing.TestingT, dbClusterID string, awsRegion string) (string, []string) {
+	writer, readers, err := GetRdsClusterMemberIDsE(t, dbClusterID, awsRegion)
+	require.NoError(t, err)
+	return writer, readers
+}
+
+// GetRdsClusterMemberIDsE gets the identifiers of the writer and reader DB instances of the given RDS cluster in the
+// given region. The writer is empty if the cluster has no writer.
+func GetRdsClusterMemberIDsE(t testing.TestingT, dbClusterID string, awsRegion string) (string, []string, error) {
+	dbCluster, err := GetRdsClusterDetailsE(t, dbClusterID, awsRegion)
+	if err != nil {
+		return "", nil, err
+	}
+
+	writer, readers := rdsClusterMemberIDs(dbCluster.DBClusterMembers)
+	return writer, readers, nil
+}
+
+// rdsClusterMemberIDs splits the given members of an RDS cluster into the identifier of the writer and the identifiers
+// of the readers.
+func rdsClusterMemberIDs(members []types.DBClusterMember) (string, []string) {
+	writer := ""
+	readers := []string{}
+	for _, member := range members {
+		if aws.ToBool(member.IsClusterWriter) {
+			writer = aws.ToString(member.DBInstanceIdentifier)
+		} else {
+			readers = append(readers, aws.ToString(member.DBInstanceIdentifier))
+		}
+	}
+	return writer, readers
+}
+
+// WaitForRdsInstanceAvailable waits until the RDS instance with the given identifier is in the available state,
+// retrying the check for the specified amount of times, sleeping for the provided duration between each try. This will
+// fail the test if the instance does not become available in time.
+func WaitForRdsInstanceAvailable(t testing.TestingT, region string, dbInstanceID string, maxRetries int, sleepBetweenRetries time.Duration) {
+	err := WaitForRdsInstanceAvailableE(t, region, dbInstanceID, maxRetries, sleepBetweenRetries)
+	require.NoError(t, err)
+}
+
+// WaitForRdsInstanceAvailableE waits until the RDS instance with the given identifier is in the available state,
+// retrying the check for the specified amount of times, sleeping for the provided duration between each try.
+func WaitForRdsInstanceAvailableE(t testing.TestingT, region string, dbInstanceID string, maxRetries int, sleepBetweenRetries time.Duration) error {
+	description := fmt.Sprintf("Waiting for RDS instance %s to be available", dbInstanceID)
+	_, err := retry.DoWithRetryE(t, description, maxRetries, sleepBetweenRetries, func() (string, error) {
+		dbInstance, err := GetRdsInstanceDetailsE(t, dbInstanceID, region)
+		if err != nil {
+			return "", err
+		}
+
+		status := aws.ToString(dbInstance.DBInstanceStatus)
+		if status != "available" {
+			return "", fmt.Errorf("RDS instance %s is in status %s", dbInstanceID, status)
+		}
+		return "", nil
+	})
+	return err
+}
+
+// WaitForRdsClusterAvailable waits until the RDS cluster with the given identifier is in the available state, retrying
+// the check for the specified amount of times, sleeping for the provided duration between each try. This will fail the
+// test if the cluster does not become available in time.
+func WaitForRdsClusterAvailable(t testing.TestingT, region string, dbClusterID string, maxRetries int, sleepBetweenRetries time.Duration) {
+	err := WaitForRdsClusterAvailableE(t, region, dbClusterID, maxRetries, sleepBetweenRetries)
+	require.NoError(t, err)
+}
+
+// WaitForRdsClusterAvailableE waits until the RDS cluster with the given identifier is in the available state, retrying
+// the check for the specified amount of times, sleeping for the provided duration between each try. Note that the DB
+// instances of the cluster may still be starting when the cluster is available, so also wait for them with
+// WaitForRdsInstanceAvailableE before connecting to the cluster.
+func WaitForRdsClusterAvailableE(t testing.TestingT, region string, dbClusterID string, maxRetries int, sleepBetweenRetries time.Duration) error {
+	description := fmt.Sprintf("Waiting for RDS cluster %s to be available", dbClusterID)
+	_, err := retry.DoWithRetryE(t, description, maxRetries, sleepBetweenRetries, func() (string, error) {
+		dbCluster, err := GetRdsClusterDetailsE(t, dbClusterID, region)
+		if err != nil {
+			return "", err
+		}
+
+		status := aws.ToString(dbCluster.Status)
+		if status != "available" {
+			return "", fmt.Errorf("RDS cluster %s is in status %s", dbClusterID, status)
+		}
+		return "", nil
+	})
+	return err
+}
+
+// CreateRdsSnapshot creates a manual snapshot with the given identifier of the given RDS instance in the given region
+// and returns the ARN of the snapshot. Use WaitForRdsSnapshotAvailable to wait for the snapshot to be created.
+func CreateRdsSnapshot(t testing.TestingT, region string, dbInstanceID string, snapshotID string) string {
+	snapshotArn, err := CreateRdsSnapshotE(t, region, dbInstanceID, snapshotID)
+	require.NoError(t, err)
+	return snapshotArn
+}
+
+// CreateRdsSnapshotE creates a manual snapshot with the given identifier of the given RDS instance in the given region
+// and returns the ARN of the snapshot. Use WaitForRdsSnapshotAvailableE to wait for the snapshot to be created.
+func CreateRdsSnapshotE(t testing.TestingT, region string, dbInstanceID string, snapshotID string) (string, error) {
+	rdsClient, err := NewRdsClientE(t, region)
+	if err != nil {
+		return "", err
+	}
+
+	output, err := rdsClient.CreateDBSnapshot(context.Background(), &rds.CreateDBSnapshotInput{
+		DBInstanceIdentifier: aws.String(dbInstanceID),
+		DBSnapshotIdentifier: aws.String(snapshotID),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(output.DBSnapshot.DBSnapshotArn), nil
+}
+
+// DeleteRdsSnapshot deletes the manual RDS snapshot with the given identifier in the given region.
+func DeleteRdsSnapshot(t testing.TestingT, region string, snapshotID string) {
+	err := DeleteRdsSnapshotE(t, region, snapshotID)
+	require.NoError(t, err)
+}
+
+// DeleteRdsSnapshotE deletes the manual RDS snapshot with the given identifier in the given region.
+func DeleteRdsSnapshotE(t testing.TestingT, region string, snapshotID string) error {
+	rdsClient, err := NewRdsClientE(t, region)
+	if err != nil {
+		return err
+	}
+
+	_, err = rdsClient.DeleteDBSnapshot(context.Background(), &rds.DeleteDBSnapshotInput{
+		DBSnapshotIdentifier: aws.String(snapshotID),
+	})
+	return err
+}
+
+// CreateRdsClusterSnapshot creates a manual snapshot with the given identifier of the given RDS cluster in the given
+// region and returns the ARN of the snapshot. Use WaitForRdsClusterSnapshotAvailable to wait for the snapshot to be
+// created.
+func CreateRdsClusterSnapshot(t testing.TestingT, region string, dbClusterID string, snapshotID string) string {
+	snapshotArn, err := CreateRdsClusterSnapshotE(t, region, dbClusterID, snapshotID)
+	require.NoError(t, err)
+	return snapshotArn
+}
+
+// CreateRdsClusterSnapshotE creates a manual snapshot with the given identifier of the given RDS cluster in the given
+// region and returns the ARN of the snapshot. Use WaitForRdsClusterSnapshotAvailableE to wait for the snapshot to be
+// created.
+func CreateRdsClusterSnapshotE(t testing.TestingT, region string, dbClusterID string, snapshotID string) (string, error) {
+	rdsClient, err := NewRdsClientE(t, region)
+	if err != nil {
+		return "", err
+	}
+
+	output, err := rdsClient.CreateDBClusterSnapshot(context.Background(), &rds.CreateDBClusterSnapshotInput{
+		DBClusterIdentifier:         aws.String(dbClusterID),
+		DBClusterSnapshotIdentifier: aws.String(snapshotID),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(output.DBClusterSnapshot.DBClusterSnapshotArn), nil
+}
+
+// WaitForRdsClusterSnapshotAvailable waits until the RDS cluster snapshot with the given identifier is in the available
+// state, retrying the check for the specified amount of times, sleeping for the provided duration between each try.
+// This will fail the test if the snapshot does not become available in time.
+func WaitForRdsClusterSnapshotAvailable(t testing.TestingT, region string, snapshotID string, maxRetries int, sleepBetweenRetries time.Duration) {
+	err := WaitForRdsClusterSnapshotAvailableE(t, region, snapshotID, maxRetries, sleepBetweenRetries)
+	require.NoError(t, err)
+}
+
+// WaitForRdsClusterSnapshotAvailableE waits until the RDS cluster snapshot with the given identifier is in the
+// available state, retrying the check for the specified amount of times, sleeping for the provided duration between
+// each try.
+func WaitForRdsClusterSnapshotAvailableE(t testing.TestingT, region string, snapshotID string, maxRetries int, sleepBetweenRetries time.Duration) error {
+	rdsClient, err := NewRdsClientE(t, region)
+	if err != nil {
+		return err
+	}
+
+	description := fmt.Sprintf("Waiting for RDS cluster snapshot %s to be available", snapshotID)
+	_, err = retry.DoWithRetryE(t, description, maxRetries, sleepBetweenRetries, func() (string, error) {
+		output, err := rdsClient.DescribeDBClusterSnapshots(context.Background(), &rds.DescribeDBClusterSnapshotsInput{
+			DBClusterSnapshotIdentifier: aws.String(snapshotID),
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(output.DBClusterSnapshots) == 0 {
+			return "", NewNotFoundError("RDS cluster snapshot", snapshotID, region)
+		}
+
+		status := aws.ToString(output.DBClusterSnapshots[0].Status)
+		if status != "available" {
+			return "", fmt.Errorf("RDS cluster snapshot %s is in status %s", snapshotID, status)
+		}
+		return "", nil
+	})
+	return err
+}
+
+// DeleteRdsClusterSnapshot deletes the manual RDS cluster snapshot with the given identifier in the given region.
+func DeleteRdsClusterSnapshot(t testing.TestingT, region string, snapshotID string) {
+	err := DeleteRdsClusterSnapshotE(t, region, snapshotID)
+	require.NoError(t, err)
+}
+
+// DeleteRdsClusterSnapshotE deletes the manual RDS cluster snapshot with the given identifier in the given region.
+func DeleteRdsClusterSnapshotE(t testing.TestingT, region string, snapshotID string) error {
+	rdsClient, err := NewRdsClientE(t, region)
+	if err != nil {
+		return err
+	}
+
+	_, err = rdsClient.DeleteDBClusterSnapshot(context.Background(), &rds.DeleteDBClusterSnapshotInput{
+		DBClusterSnapshotIdentifier: aws.String(snapshotID),
+	})
+	return err
+}
+
+// RestoreRdsInstanceFromSnapshot restores the RDS snapshot with the given identifier into a new RDS instance with the
+// given identifier in the given region. See RestoreRdsInstanceFromSnapshotE for details.
+func RestoreRdsInstanceFromSnapshot(t testing.TestingT, region string, snapshotID string, dbInstanceID string, dbSubnetGroupName string, vpcSecurityGroupIDs []string) {
+	err := RestoreRdsInstanceFromSnapshotE(t, region, snapshotID, dbInstanceID, dbSubnetGroupName, vpcSecurityGroupIDs)
+	require.NoError(t, err)
+}
+
+// RestoreRdsInstanceFromSnapshotE restores the RDS snapshot with the given identifier into a new RDS instance with the
+// given identifier in the given region, in the given DB subnet group and VPC security groups. If the subnet group is
+// empty, the instance is created in the default VPC, and if the security groups are empty, the default security group
+// of the VPC is used. The other settings of the instance are taken from the snapshot. Use WaitForRdsInstanceAvailableE
+// to wait for the instance to be restored and DeleteRdsInstanceE to delete it at the end of the test.
+func RestoreRdsInstanceFromSnapshotE(t testing.TestingT, region string, snapshotID string, dbInstanceID string, dbSubnetGroupName string, vpcSecurityGroupIDs []string) error {
+	rdsClient, err := NewRdsClientE(t, region)
+	if err != nil {
+		return err
+	}
+
+	input := &rds.RestoreDBInstanceFromDBSnapshotInput{
+		DBSnapshotIdentifier: aws.String(snapshotID),
+		DBInstanceIdentifier: aws.String(dbInstanceID),
+		VpcSecurityGroupIds:  vpcSecurityGroupIDs,
+	}
+	if dbSubnetGroupName != "" {
+		input.DBSubnetGroupName = aws.String(dbSubnetGroupName)
+	}
+
+	_, err = rdsClient.RestoreDBInstanceFromDBSnapshot(context.Background(), input)
+	return err
+}
+
+// RestoreRdsClusterFromSnapshot restores the RDS cluster snapshot with the given identifier into a new RDS cluster with
+// the given identifier in the given region. See RestoreRdsClusterFromSnapshotE for details.
+func RestoreRdsClusterFromSnapshot(t testing.TestingT, region string, snapshotID string, dbClusterID string, dbSubnetGroupName string, vpcSecurityGroupIDs []string) {
+	err := RestoreRdsClusterFromSnapshotE(t, region, snapshotID, dbClusterID, dbSubnetGroupName, vpcSecurityGroupIDs)
+	require.NoError(t, err)
+}
+
+// RestoreRdsClusterFromSnapshotE restores the RDS cluster snapshot with the given identifier into a new RDS cluster with
+// the given identifier in the given region, in the given DB subnet group and VPC security groups. If the subnet group
+// is empty, the cluster is created in the default VPC, and if the security groups are empty, the default security group
+// of the VPC is used. The engine and the other settings of the cluster are taken from the snapshot. Note that, unlike
+// an instance, a restored cluster has no DB instances, so create them before connecting to the cluster. Use
+// WaitForRdsClusterAvailableE to wait for the cluster to be restored.
+func RestoreRdsClusterFromSnapshotE(t testing.TestingT, region string, snapshotID string, dbClusterID string, dbSubnetGroupName string, vpcSecurityGroupIDs []string) error {
+	rdsClient, err := NewRdsClientE(t, region)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := rdsClient.DescribeDBClusterSnapshots(context.Background(), &rds.DescribeDBClusterSnapshotsInput{
+		DBClusterSnapshotIdentifier: aws.String(snapshotID),
+	})
+	if err != nil {
+		return err
+	}
+	if len(snapshots.DBClusterSnapshots) == 0 {
+		return NewNotFoundError("RDS cluster snapshot", snapshotID, region)
+	}
+
+	input := &rds.RestoreDBClusterFromSnapshotInput{
+		SnapshotIdentifier:  aws.String(snapshotID),
+		DBClusterIdentifier: aws.String(dbClusterID),
+		Engine:              snapshots.DBClusterSnapshots[0].Engine,
+		VpcSecurityGroupIds: vpcSecurityGroupIDs,
+	}
+	if dbSubnetGroupName != "" {
+		input.DBSubnetGroupName = aws.String(dbSubnetGroupName)
+	}
+
+	_, err = rdsClient.RestoreDBClusterFromSnapshot(context.Background(), input)
+	return err
+}
+
+// DeleteRdsInstance deletes the RDS instance with the given identifier in the given region, without taking a final
+// snapshot and along with its automated backups.
+func DeleteRdsInstance(t testing.TestingT, region string, dbInstanceID string) {
+	err := DeleteRdsInstanceE(t, region, dbInstanceID)
+	require.NoError(t, err)
+}
+
+// DeleteRdsInstanceE deletes the RDS instance with the given identifier in the given region, without taking a final
+// snapshot and along with its automated backups.
+func DeleteRdsInstanceE(t testing.TestingT, region string, dbInstanceID string) error {
+	rdsClient, err := NewRdsClientE(t, region)
+	if err != nil {
+		return err
+	}
+
+	_, err = rdsClient.DeleteDBInstance(context.Background(), &rds.DeleteDBInstanceInput{
+		DBInstanceIdentifier:   aws.String(dbInstanceID),
+		SkipFinalSnapshot:      aws.Bool(true),
+		DeleteAutomatedBackups: aws.Bool(true),
+	})
+	return err
+}
+
+// GetAllParametersOfRdsCluster gets all the parameters defined in the cluster parameter group of the RDS cluster in
+// the given region.
+func GetAllParametersOfRdsCluster(t testing.TestingT, dbClusterID string, awsRegion string) []types.Parameter {
+	parameters, err := GetAllParametersOfRdsClusterE(t, dbClusterID, awsRegion)
+	require.NoError(t, err)
+	return parameters
+}
+
+// GetAllParametersOfRdsClusterE gets all the parameters defined in the cluster parameter group of the RDS cluster in
+// the given region.
+func GetAllParametersOfRdsClusterE(t testing.TestingT, dbClusterID string, awsRegion string) ([]types.Parameter, error) {
+	dbCluster, err := GetRdsClusterDetailsE(t, dbClusterID, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+	rdsClient, err := NewRdsClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	input := rds.DescribeDBClusterParametersInput{DBClusterParameterGroupName: dbCluster.DBClusterParameterGroup}
+
+	var allParameters []types.Parameter
+	for {
+		output, err := rdsClient.DescribeDBClusterParameters(context.Background(), &input)
+		if err != nil {
+			return nil, err
+		}
+
+		allParameters = append(allParameters, output.Parameters...)
+		if output.Marker == nil {
+			break
+		}
+
+		input.Marker = output.Marker
+	}
+	return allParameters, nil
+}
+
+// GetParameterValueForParameterOfRdsCluster gets the value of the parameter name specified for the RDS cluster in the
+// given region.
+func GetParameterValueForParameterOfRdsCluster(t testing.TestingT, parameterName string, dbClusterID string, awsRegion string) string {
+	parameterValue, err := GetParameterValueForParameterOfRdsClusterE(t, parameterName, dbClusterID, awsRegion)
+	require.NoError(t, err)
+	return parameterValue
+}
+
+// GetParameterValueForParameterOfRdsClusterE gets the value of the parameter name specified for the RDS cluster in the
+// given region.
+func GetParameterValueForParameterOfRdsClusterE(t testing.TestingT, parameterName string, dbClusterID string, awsRegion string) (string, error) {
+	parameters, err := GetAllParametersOfRdsClusterE(t, dbClusterID, awsRegion)
+	if err != nil {
+		return "", err
+	}
+
+	value, found := findRdsParameterValue(parameters, parameterName)
+	if !found {
+		return "", ParameterForDbClusterNotFound{ParameterName: parameterName, DbClusterID: dbClusterID, AwsRegion: awsRegion}
+	}
+	return value, nil
+}
+
+// AssertRdsInstanceParameterValue checks that the parameter with the given name in the parameter group of the given RDS
+// instance has the given value, and fails the test if it does not.
+func AssertRdsInstanceParameterValue(t testing.TestingT, parameterName string, expectedValue string, dbInstanceID string, awsRegion string) {
+	err := AssertRdsInstanceParameterValueE(t, parameterName, expectedValue, dbInstanceID, awsRegion)
+	require.NoError(t, err)
+}
+
+// AssertRdsInstanceParameterValueE checks that the parameter with the given name in the parameter group of the given
+// RDS instance has the given value, and returns an UnexpectedRdsParameterValue error if it does not.
+func AssertRdsInstanceParameterValueE(t testing.TestingT, parameterName string, expectedValue string, dbInstanceID string, awsRegion string) error {
+	parameters, err := GetAllParametersOfRdsInstanceE(t, dbInstanceID, awsRegion)
+	if err != nil {
+		return err
+	}
+
+	value, found := findRdsParameterValue(parameters, parameterName)
+	if !found {
+		return ParameterForDbInstanceNotFound{ParameterName: parameterName, DbInstanceID: dbInstanceID, AwsRegion: awsRegion}
+	}
+	return checkRdsParameterValue(dbInstanceID, parameterName, expectedValue, value)
+}
+
+// AssertRdsClusterParameterValue checks that the parameter with the given name in the cluster parameter group of the
+// given RDS cluster has the given value, and fails the test if it does not.
+func AssertRdsClusterParameterValue(t testing.TestingT, parameterName string, expectedValue string, dbClusterID string, awsRegion string) {
+	err := AssertRdsClusterParameterValueE(t, parameterName, expectedValue, dbClusterID, awsRegion)
+	require.NoError(t, err)
+}
+
+// AssertRdsClusterParameterValueE checks that the parameter with the given name in the cluster parameter group of the
+// given RDS cluster has the given value, and returns an UnexpectedRdsParameterValue error if it does not.
+func AssertRdsClusterParameterValueE(t testing.TestingT, parameterName string, expectedValue string, dbClusterID string, awsRegion string) error {
+	value, err := GetParameterValueForParameterOfRdsClusterE(t, parameterName, dbClusterID, awsRegion)
+	if err != nil {
+		return err
+	}
+	return checkRdsParameterValue(dbClusterID, parameterName, expectedValue, value)
+}
+
+// findRdsParameterValue returns the value of the parameter with the given name in the given parameters, and whether
+// there is such a parameter.
+func findRdsParameterValue(parameters []types.Parameter, parameterName string) (string, bool) {
+	for _, parameter := range parameters {
+		if aws.ToString(parameter.ParameterName) == parameterName {
+			return aws.ToString(parameter.ParameterValue), true
+		}
+	}
+	return "", false
+}
+
+// checkRdsParameterValue returns an UnexpectedRdsParameterValue error if the given actual value of the given parameter
+// of the given RDS instance or cluster is not the expected value.
+func checkRdsParameterValue(dbID string, parameterName string, expectedValue string, actualValue string) error {
+	if actualValue == expectedValue {
+		return nil
+	}
+	return UnexpectedRdsParameterValue{
+		ParameterName: parameterName,
+		DbID:          dbID,
+		ExpectedValue: expectedValue,
+		ActualValue:   actualValue,
+	}
+}
+
+// GetRdsConnection opens a connection to the database with the given name on the given RDS instance, using the driver
+// for the engine of the instance. See GetRdsConnectionE for details.
+func GetRdsConnection(t testing.TestingT, region string, dbInstanceID string, dbUsername string, dbPassword string, databaseName string) *sql.DB {
+	db, err := GetRdsConnectionE(t, region, dbInstanceID, dbUsername, dbPassword, databaseName)
+	require.NoError(t, err)
+	return db
+}
+
+// GetRdsConnectionE opens a connection to the database with the given name on the given RDS instance, using the driver
+// for the engine of the instance, and checks that the database can be reached. MySQL, MariaDB and PostgreSQL engines,
+// including Aurora, are supported. The instance must be reachable from where the test runs; for instances in private
+// subnets use GetRdsConnectionThroughSsmTunnelE or GetRdsConnectionThroughSshTunnelE. Close the connection at the end of the test.
+func GetRdsConnectionE(t testing.TestingT, region string, dbInstanceID string, dbUsername string, dbPassword string, databaseName string) (*sql.DB, error) {
+	dbInstance, err := GetRdsInstanceDetailsE(t, dbInstanceID, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return openRdsConnectionE(aws.ToString(dbInstance.Engine), aws.ToString(dbInstance.Endpoint.Address), aws.ToInt32(dbInstance.Endpoint.Port), dbUsername, dbPassword, databaseName)
+}
+
+// GetRdsClusterConnection opens a connection to the database with the given name on the writer endpoint of the given
+// RDS cluster, using the driver for the engine of the cluster. See GetRdsConnectionE for details.
+func GetRdsClusterConnection(t testing.TestingT, region string, dbClusterID string, dbUsername string, dbPassword string, databaseName string) *sql.DB {
+	db, err := GetRdsClusterConnectionE(t, region, dbClusterID, dbUsername, dbPassword, databaseName)
+	require.NoError(t, err)
+	return db
+}
+
+// GetRdsClusterConnectionE opens a connection to the database with the given name on the writer endpoint of the given
+// RDS cluster, using the driver for the engine of the cluster. See GetRdsConnectionE for details.
+func GetRdsClusterConnectionE(t testing.TestingT, region string, dbClusterID string, dbUsername string, dbPassword string, databaseName string) (*sql.DB, error) {
+	dbCluster, err := GetRdsClusterDetailsE(t, dbClusterID, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return openRdsConnectionE(aws.ToString(dbCluster.Engine), aws.ToString(dbCluster.Endpoint), aws.ToInt32(dbCluster.Port), dbUsername, dbPassword, databaseName)
+}
+
+// GetRdsConnectionThroughSsmTunnel opens a connection to the database with the given name on the given RDS instance
+// through an SSM port forwarding tunnel via the given EC2 instance. See GetRdsConnectionThroughSsmTunnelE for details.
+func GetRdsConnectionThroughSsmTunnel(t testing.TestingT, region string, dbInstanceID string, bastionInstanceID string, dbUsername string, dbPassword string, databaseName string) (*sql.DB, *SsmTunnel) {
+	db, tunnel, err := GetRdsConnectionThroughSsmTunnelE(t, region, dbInstanceID, bastionInstanceID, dbUsername, dbPassword, databaseName)
+	require.NoError(t, err)
+	return db, tunnel
+}
+
+// GetRdsConnectionThroughSsmTunnelE opens a connection to the database with the given name on the given RDS instance
+// through an SSM port forwarding tunnel via the given EC2 instance, so that instances in private subnets can be
+// reached. The EC2 instance must be managed by SSM and be able to reach the RDS instance. Close both the connection and
+// the tunnel at the end of the test.
+func GetRdsConnectionThroughSsmTunnelE(t testing.TestingT, region string, dbInstanceID string, bastionInstanceID string, dbUsername string, dbPassword string, databaseName string) (*sql.DB, *SsmTunnel, error) {
+	dbInstance, err := GetRdsInstanceDetailsE(t, dbInstanceID, region)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tunnel := NewSsmTunnelToRemoteHost(region, bastionInstanceID, aws.ToString(dbInstance.Endpoint.Address), 0, int(aws.ToInt32(dbInstance.Endpoint.Port)))
+	if err := tunnel.ForwardPortE(t); err != nil {
+		return nil, nil, err
+	}
+
+	db, err := openRdsConnectionE(aws.ToString(dbInstance.Engine), "localhost", int32(tunnel.localPort), dbUsername, dbPassword, databaseName)
+	if err != nil {
+		tunnel.Close()
+		return nil, nil, err
+	}
+	return db, tunnel, nil
+}
+
+// GetRdsConnectionThroughSshTunnel opens a connection to the database with the given name on the given RDS instance
+// through an SSH tunnel via the given bastion host. See GetRdsConnectionThroughSshTunnelE for details.
+func GetRdsConnectionThroughSshTunnel(t testing.TestingT, region string, dbInstanceID string, bastionHost ssh.Host, dbUsername string, dbPassword string, databaseName string) (*sql.DB, *ssh.SshTunnel) {
+	db, tunnel, err := GetRdsConnectionThroughSshTunnelE(t, region, dbInstanceID, bastionHost, dbUsername, dbPassword, databaseName)
+	require.NoError(t, err)
+	return db, tunnel
+}
+
+// GetRdsConnectionThroughSshTunnelE opens a connection to the database with the given name on the given RDS instance
+// through an SSH tunnel via the given bastion host, so that instances in private subnets can be reached. The bastion
+// host must be able to reach the RDS instance. Close both the connection and the tunnel at the end of the test.
+func GetRdsConnectionThroughSshTunnelE(t testing.TestingT, region string, dbInstanceID string, bastionHost ssh.Host, dbUsername string, dbPassword string, databaseName string) (*sql.DB, *ssh.SshTunnel, error) {
+	dbInstance, err := GetRdsInstanceDetailsE(t, dbInstanceID, region)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tunnel := ssh.NewSshTunnel(bastionHost, aws.ToString(dbInstance.Endpoint.Address), 0, int(aws.ToInt32(dbInstance.Endpoint.Port)))
+	if err := tunnel.ForwardPortE(t); err != nil {
+		return nil, nil, err
+	}
+
+	db, err := openRdsConnectionE(aws.ToString(dbInstance.Engine), "localhost", int32(tunnel.LocalPort()), dbUsername, dbPassword, databaseName)
+	if err != nil {
+		tunnel.Close()
+		return nil, nil, err
+	}
+	return db, tunnel, nil
+}
+
+// openRdsConnectionE opens a connection to the database with the given name on the given host and port, using the
+// driver for the given RDS engine, and checks that the database can be reached.
+func openRdsConnectionE(engine string, host string, port int32, dbUsername string, dbPassword string, databaseName string) (*sql.DB, error) {
+	driverName, dataSourceName, err := rdsDataSource(engine, host, port, dbUsername, dbPassword, databaseName)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// rdsDataSource returns the name of the database/sql driver and the data source name to connect to the database with
+// the given name on the given host and port with the given RDS engine (e.g. mysql, aurora-postgresql). The credentials
+// are escaped, so they may contain any characters.
+func rdsDataSource(engine string, host string, port int32, dbUsername string, dbPassword string, databaseName string) (string, string, error) {
+	address := net.JoinHostPort(host, strconv.Itoa(int(port)))
+	switch {
+	case strings.Contains(engine, "mysql") || strings.Contains(engine, "mariadb"):
+		config := mysql.NewConfig()
+		config.User = dbUsername
+		config.Passwd = dbPassword
+		config.Net = "tcp"
+		config.Addr = address
+		config.DBName = databaseName
+		return "mysql", config.FormatDSN(), nil
+	case strings.Contains(engine, "postgres"):
+		dataSourceName := url.URL{
+			Scheme: "postgres",
+			User:   url.UserPassword(dbUsername, dbPassword),
+			Host:   address,
+			Path:   "/" + databaseName,
+		}
+		return "pgx", dataSourceName.String(), nil
+	default:
+		return "", "", UnsupportedRdsEngine{Engine: engine}
+	}
+}
+
 // ParameterForDbInstanceNotFound is an error that occurs when the parameter group specified is not found for the DB instance
 type ParameterForDbInstanceNotFound struct {
 	ParameterName string
@@ -367,3 +1181,35 @@ type OptionGroupOptionSettingForDbInstanceNotFound struct {
 func (err OptionGroupOptionSettingForDbInstanceNotFound) Error() string {
 	return fmt.Sprintf("Could not find a option setting %s in option name %s of database %s in %s", err.OptionName, err.OptionSettingName, err.DbInstanceID, err.AwsRegion)
 }
+
+// ParameterForDbClusterNotFound is an error that occurs when the parameter specified is not found in the cluster parameter group of the DB cluster
+type ParameterForDbClusterNotFound struct {
+	ParameterName string
+	DbClusterID   string
+	AwsRegion     string
+}
+
+func (err ParameterForDbClusterNotFound) Error() string {
+	return fmt.Sprintf("Could not find a parameter %s in cluster parameter group of database cluster %s in %s", err.ParameterName, err.DbClusterID, err.AwsRegion)
+}
+
+// UnexpectedRdsParameterValue is an error that occurs when a parameter of a DB instance or cluster does not have the expected value
+type UnexpectedRdsParameterValue struct {
+	ParameterName string
+	DbID          string
+	ExpectedValue string
+	ActualValue   string
+}
+
+func (err UnexpectedRdsParameterValue) Error() string {
+	return fmt.Sprintf("Parameter %s of database %s has value %q, expected %q", err.ParameterName, err.DbID, err.ActualValue, err.ExpectedValue)
+}
+
+// UnsupportedRdsEngine is an error that occurs when connecting to a database with an engine that has no supported database/sql driver
+type UnsupportedRdsEngine struct {
+	Engine string
+}
+
+func (err UnsupportedRdsEngine) Error() string {
+	return fmt.Sprintf("Connecting to databases with RDS engine %s is not supported", err.Engine)
+}
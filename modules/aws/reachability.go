@@ -0,0 +1,162 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// An analysis usually takes around a minute, so this waits for up to 5 minutes.
+const networkPathAnalysisMaxRetries = 60
+const networkPathAnalysisTimeBetweenRetries = 5 * time.Second
+
+// NetworkPathAnalysis is the result of a VPC Reachability Analyzer analysis of the network path between two resources.
+type NetworkPathAnalysis struct {
+	Reachable    bool     // Whether the destination is reachable from the source
+	Explanations []string // Why the destination is not reachable, as an explanation code followed by the IDs of the components involved (e.g. ENI_SG_RULES_MISMATCH sg-0123456789abcdef0). Empty if reachable.
+}
+
+// CanAccessNetworkPort returns whether the given TCP port of the destination resource (e.g. an instance ID or network
+// interface ID) can be reached from the source resource, according to VPC Reachability Analyzer.
+func CanAccessNetworkPort(t testing.TestingT, region string, source string, destination string, port int32) bool {
+	reachable, err := CanAccessNetworkPortE(t, region, source, destination, port)
+	require.NoError(t, err)
+	return reachable
+}
+
+// CanAccessNetworkPortE returns whether the given TCP port of the destination resource (e.g. an instance ID or network
+// interface ID) can be reached from the source resource, according to VPC Reachability Analyzer.
+func CanAccessNetworkPortE(t testing.TestingT, region string, source string, destination string, port int32) (bool, error) {
+	analysis, err := AnalyzeNetworkPathE(t, region, source, destination, port, string(types.ProtocolTcp))
+	if err != nil {
+		return false, err
+	}
+	return analysis.Reachable, nil
+}
+
+// AnalyzeNetworkPath uses VPC Reachability Analyzer to check whether the given port of the destination resource can be
+// reached from the source resource over the given protocol (tcp or udp). The source and destination can be the IDs or
+// ARNs of instances, network interfaces, gateways, etc. This creates a network insights path, runs an analysis on it
+// and waits for the result, and deletes both the analysis and the path afterwards.
+func AnalyzeNetworkPath(t testing.TestingT, region string, source string, destination string, port int32, protocol string) *NetworkPathAnalysis {
+	analysis, err := AnalyzeNetworkPathE(t, region, source, destination, port, protocol)
+	require.NoError(t, err)
+	return analysis
+}
+
+// AnalyzeNetworkPathE uses VPC Reachability Analyzer to check whether the given port of the destination resource can be
+// reached from the source resource over the given protocol (tcp or udp). The source and destination can be the IDs or
+// ARNs of instances, network interfaces, gateways, etc. This creates a network insights path, runs an analysis on it
+// and waits for the result, and deletes both the analysis and the path afterwards.
+func AnalyzeNetworkPathE(t testing.TestingT, region string, source string, destination string, port int32, protocol string) (*NetworkPathAnalysis, error) {
+	client, err := NewEc2ClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Default.Logf(t, "Analyzing the network path from %s to %s on port %d/%s", source, destination, port, protocol)
+
+	path, err := client.CreateNetworkInsightsPath(context.Background(), &ec2.CreateNetworkInsightsPathInput{
+		Source:          aws.String(source),
+		Destination:     aws.String(destination),
+		DestinationPort: aws.Int32(port),
+		Protocol:        types.Protocol(strings.ToLower(protocol)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	pathID := aws.ToString(path.NetworkInsightsPath.NetworkInsightsPathId)
+	defer deleteNetworkInsightsPath(t, client, pathID)
+
+	started, err := client.StartNetworkInsightsAnalysis(context.Background(), &ec2.StartNetworkInsightsAnalysisInput{
+		NetworkInsightsPathId: aws.String(pathID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	analysisID := aws.ToString(started.NetworkInsightsAnalysis.NetworkInsightsAnalysisId)
+	defer deleteNetworkInsightsAnalysis(t, client, analysisID)
+
+	description := fmt.Sprintf("Waiting for network insights analysis %s to finish", analysisID)
+	out, err := retry.DoWithRetryInterfaceE(t, description, networkPathAnalysisMaxRetries, networkPathAnalysisTimeBetweenRetries, func() (interface{}, error) {
+		output, err := client.DescribeNetworkInsightsAnalyses(context.Background(), &ec2.DescribeNetworkInsightsAnalysesInput{
+			NetworkInsightsAnalysisIds: []string{analysisID},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(output.NetworkInsightsAnalyses) != 1 {
+			return nil, retry.FatalError{Underlying: NewNotFoundError("Network insights analysis", analysisID, region)}
+		}
+
+		analysis := output.NetworkInsightsAnalyses[0]
+		switch analysis.Status {
+		case types.AnalysisStatusSucceeded:
+			return newNetworkPathAnalysis(analysis), nil
+		case types.AnalysisStatusFailed:
+			return nil, retry.FatalError{Underlying: NetworkPathAnalysisFailed{AnalysisId: analysisID, StatusMessage: aws.ToString(analysis.StatusMessage)}}
+		default:
+			return nil, fmt.Errorf("network insights analysis %s is still %s", analysisID, analysis.Status)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.(*NetworkPathAnalysis), nil
+}
+
+func newNetworkPathAnalysis(analysis types.NetworkInsightsAnalysis) *NetworkPathAnalysis {
+	result := &NetworkPathAnalysis{
+		Reachable:    aws.ToBool(analysis.NetworkPathFound),
+		Explanations: []string{},
+	}
+
+	for _, explanation := range analysis.Explanations {
+		parts := []string{aws.ToString(explanation.ExplanationCode)}
+		for _, component := range []*types.AnalysisComponent{
+			explanation.Component,
+			explanation.SecurityGroup,
+			explanation.Acl,
+			explanation.RouteTable,
+			explanation.Subnet,
+			explanation.Vpc,
+		} {
+			if component != nil && component.Id != nil {
+				parts = append(parts, aws.ToString(component.Id))
+			}
+		}
+		result.Explanations = append(result.Explanations, strings.Join(parts, " "))
+	}
+
+	return result
+}
+
+// deleteNetworkInsightsAnalysis deletes the given analysis, only logging on failure since this is used for cleanup.
+func deleteNetworkInsightsAnalysis(t testing.TestingT, client *ec2.Client, analysisID string) {
+	_, err := client.DeleteNetworkInsightsAnalysis(context.Background(), &ec2.DeleteNetworkInsightsAnalysisInput{
+		NetworkInsightsAnalysisId: aws.String(analysisID),
+	})
+	if err != nil {
+		logger.Default.Logf(t, "Failed to delete network insights analysis %s: %v", analysisID, err)
+	}
+}
+
+// deleteNetworkInsightsPath deletes the given path, only logging on failure since this is used for cleanup.
+func deleteNetworkInsightsPath(t testing.TestingT, client *ec2.Client, pathID string) {
+	_, err := client.DeleteNetworkInsightsPath(context.Background(), &ec2.DeleteNetworkInsightsPathInput{
+		NetworkInsightsPathId: aws.String(pathID),
+	})
+	if err != nil {
+		logger.Default.Logf(t, "Failed to delete network insights path %s: %v", pathID, err)
+	}
+}
@@ -0,0 +1,46 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNetworkPathAnalysisReachable(t *testing.T) {
+	t.Parallel()
+
+	analysis := newNetworkPathAnalysis(types.NetworkInsightsAnalysis{
+		Status:           types.AnalysisStatusSucceeded,
+		NetworkPathFound: aws.Bool(true),
+	})
+
+	assert.Equal(t, &NetworkPathAnalysis{Reachable: true, Explanations: []string{}}, analysis)
+}
+
+func TestNewNetworkPathAnalysisNotReachable(t *testing.T) {
+	t.Parallel()
+
+	analysis := newNetworkPathAnalysis(types.NetworkInsightsAnalysis{
+		Status:           types.AnalysisStatusSucceeded,
+		NetworkPathFound: aws.Bool(false),
+		Explanations: []types.Explanation{
+			{
+				ExplanationCode: aws.String("ENI_SG_RULES_MISMATCH"),
+				Component:       &types.AnalysisComponent{Id: aws.String("eni-0123456789abcdef0")},
+				SecurityGroup:   &types.AnalysisComponent{Id: aws.String("sg-0123456789abcdef0")},
+			},
+			{
+				ExplanationCode: aws.String("NO_ROUTE_TO_DESTINATION"),
+				RouteTable:      &types.AnalysisComponent{Id: aws.String("rtb-0123456789abcdef0")},
+			},
+		},
+	})
+
+	assert.False(t, analysis.Reachable)
+	assert.Equal(t, []string{
+		"ENI_SG_RULES_MISMATCH eni-0123456789abcdef0 sg-0123456789abcdef0",
+		"NO_ROUTE_TO_DESTINATION rtb-0123456789abcdef0",
+	}, analysis.Explanations)
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/gruntwork-io/terratest/modules/collections"
 	"github.com/gruntwork-io/terratest/modules/logger"
@@ -160,6 +162,72 @@ func GetAvailabilityZonesE(t testing.TestingT, region string) ([]string, error)
 	return out, nil
 }
 
+// GetAvailabilityZonesForRegion gets the names of the regular Availability Zones that are currently available in the
+// given AWS region, sorted by name. Unlike GetAvailabilityZones, this leaves out Local Zones, Wavelength Zones and
+// zones that are impaired or unavailable, which can't be used for regular subnets.
+func GetAvailabilityZonesForRegion(t testing.TestingT, region string) []string {
+	out, err := GetAvailabilityZonesForRegionE(t, region)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// GetAvailabilityZonesForRegionE gets the names of the regular Availability Zones that are currently available in the
+// given AWS region, sorted by name. Unlike GetAvailabilityZonesE, this leaves out Local Zones, Wavelength Zones and
+// zones that are impaired or unavailable, which can't be used for regular subnets.
+func GetAvailabilityZonesForRegionE(t testing.TestingT, region string) ([]string, error) {
+	logger.Default.Logf(t, "Looking up the available regular availability zones in this account for region %s", region)
+
+	ec2Client, err := NewEc2ClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ec2Client.DescribeAvailabilityZones(context.Background(), &ec2.DescribeAvailabilityZonesInput{
+		Filters: []types.Filter{
+			{Name: aws.String("zone-type"), Values: []string{"availability-zone"}},
+			{Name: aws.String("state"), Values: []string{"available"}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var out []string
+	for _, availabilityZone := range resp.AvailabilityZones {
+		out = append(out, aws.ToString(availabilityZone.ZoneName))
+	}
+	sort.Strings(out)
+
+	return out, nil
+}
+
+// GetFirstAvailabilityZonesForRegion gets the names of the first count regular Availability Zones, sorted by name, that
+// are currently available in the given AWS region. This is useful to pick a consistent set of AZs for multi-AZ tests.
+// This will fail the test if fewer AZs are available.
+func GetFirstAvailabilityZonesForRegion(t testing.TestingT, region string, count int) []string {
+	out, err := GetFirstAvailabilityZonesForRegionE(t, region, count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+// GetFirstAvailabilityZonesForRegionE gets the names of the first count regular Availability Zones, sorted by name,
+// that are currently available in the given AWS region. This is useful to pick a consistent set of AZs for multi-AZ
+// tests. Returns a NotEnoughAvailabilityZones error if fewer AZs are available.
+func GetFirstAvailabilityZonesForRegionE(t testing.TestingT, region string, count int) ([]string, error) {
+	azs, err := GetAvailabilityZonesForRegionE(t, region)
+	if err != nil {
+		return nil, err
+	}
+	if len(azs) < count {
+		return nil, NotEnoughAvailabilityZones{Region: region, Requested: count, Available: azs}
+	}
+	return azs[:count], nil
+}
+
 // GetRegionsForService gets all AWS regions in which a service is available.
 func GetRegionsForService(t testing.TestingT, serviceName string) []string {
 	out, err := GetRegionsForServiceE(t, serviceName)
@@ -55,6 +55,25 @@ func TestGetAvailabilityZones(t *testing.T) {
 	}
 }
 
+func TestGetAvailabilityZonesForRegion(t *testing.T) {
+	t.Parallel()
+
+	randomRegion := GetRandomStableRegion(t, nil, nil)
+	azs := GetAvailabilityZonesForRegion(t, randomRegion)
+
+	assert.True(t, len(azs) > 1)
+	assert.IsNonDecreasing(t, azs)
+	for _, az := range azs {
+		assert.Regexp(t, fmt.Sprintf("^%s[a-z]$", randomRegion), az)
+	}
+
+	firstAzs := GetFirstAvailabilityZonesForRegion(t, randomRegion, 2)
+	assert.Equal(t, azs[:2], firstAzs)
+
+	_, err := GetFirstAvailabilityZonesForRegionE(t, randomRegion, len(azs)+1)
+	assert.IsType(t, NotEnoughAvailabilityZones{}, err)
+}
+
 func TestGetRandomRegionForService(t *testing.T) {
 	t.Parallel()
 
@@ -4,16 +4,45 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/route53"
 	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	dns_helper "github.com/gruntwork-io/terratest/modules/dns-helper"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/require"
 )
 
+// route53PollInterval is the time to wait between checks when waiting for DNS records to propagate or health checks
+// to become healthy.
+const route53PollInterval = 10 * time.Second
+
+// route53HealthyThreshold is the fraction of Route 53 health checkers that must report a health check as healthy for
+// Route 53 to consider it healthy.
+const route53HealthyThreshold = 0.18
+
+// Route53HostedZone is a Route 53 hosted zone.
+type Route53HostedZone struct {
+	Id          string // The ID of the hosted zone, without the /hostedzone/ prefix
+	Name        string // The domain name of the hosted zone, with a trailing dot
+	PrivateZone bool   // Whether the hosted zone is private to one or more VPCs
+	RecordCount int64  // The number of record sets in the hosted zone
+	Comment     string // The comment of the hosted zone
+}
+
+// Route53HealthCheckObservation is the status of a Route 53 health check as reported by one of the health checkers.
+type Route53HealthCheckObservation struct {
+	Region      string    // The region of the health checker
+	IPAddress   string    // The IP address of the health checker
+	Status      string    // The status reported by the health checker, starting with Success or Failure
+	CheckedTime time.Time // The time of the check
+}
+
 // GetRoute53Record returns a Route 53 Record
-func GetRoute53Record(t *testing.T, hostedZoneID, recordName, recordType, awsRegion string) *types.ResourceRecordSet {
+func GetRoute53Record(t testing.TestingT, hostedZoneID, recordName, recordType, awsRegion string) *types.ResourceRecordSet {
 	r, err := GetRoute53RecordE(t, hostedZoneID, recordName, recordType, awsRegion)
 	require.NoError(t, err)
 
@@ -21,7 +50,7 @@ func GetRoute53Record(t *testing.T, hostedZoneID, recordName, recordType, awsReg
 }
 
 // GetRoute53RecordE returns a Route 53 Record
-func GetRoute53RecordE(t *testing.T, hostedZoneID, recordName, recordType, awsRegion string) (*types.ResourceRecordSet, error) {
+func GetRoute53RecordE(t testing.TestingT, hostedZoneID, recordName, recordType, awsRegion string) (*types.ResourceRecordSet, error) {
 	route53Client, err := NewRoute53ClientE(t, awsRegion)
 	if err != nil {
 		return nil, err
@@ -46,8 +75,358 @@ func GetRoute53RecordE(t *testing.T, hostedZoneID, recordName, recordType, awsRe
 	return nil, fmt.Errorf("record not found")
 }
 
+// GetRoute53HostedZone returns the Route 53 hosted zone with the given domain name
+func GetRoute53HostedZone(t testing.TestingT, zoneName string, awsRegion string) *Route53HostedZone {
+	zone, err := GetRoute53HostedZoneE(t, zoneName, awsRegion)
+	require.NoError(t, err)
+
+	return zone
+}
+
+// GetRoute53HostedZoneE returns the Route 53 hosted zone with the given domain name. If there is both a public and a
+// private hosted zone with that name, the public one is returned. Returns a NotFoundError if there is no such zone.
+func GetRoute53HostedZoneE(t testing.TestingT, zoneName string, awsRegion string) (*Route53HostedZone, error) {
+	zones, err := getRoute53HostedZonesE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	zone := findRoute53HostedZone(zones, zoneName, false)
+	if zone == nil {
+		return nil, NewNotFoundError("Route 53 hosted zone", zoneName, awsRegion)
+	}
+	return zone, nil
+}
+
+// GetRoute53HostedZoneForDomain returns the Route 53 hosted zone that the given domain name belongs to
+func GetRoute53HostedZoneForDomain(t testing.TestingT, fqdn string, awsRegion string) *Route53HostedZone {
+	zone, err := GetRoute53HostedZoneForDomainE(t, fqdn, awsRegion)
+	require.NoError(t, err)
+
+	return zone
+}
+
+// GetRoute53HostedZoneForDomainE returns the Route 53 hosted zone that the given domain name belongs to, which is the
+// zone with the longest name that is a suffix of the domain name. Public zones are preferred over private zones with
+// the same name. Returns a NotFoundError if there is no such zone.
+func GetRoute53HostedZoneForDomainE(t testing.TestingT, fqdn string, awsRegion string) (*Route53HostedZone, error) {
+	zones, err := getRoute53HostedZonesE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	zone := findRoute53HostedZone(zones, fqdn, true)
+	if zone == nil {
+		return nil, NewNotFoundError("Route 53 hosted zone for domain", fqdn, awsRegion)
+	}
+	return zone, nil
+}
+
+// getRoute53HostedZonesE returns all the Route 53 hosted zones of the account.
+func getRoute53HostedZonesE(t testing.TestingT, awsRegion string) ([]Route53HostedZone, error) {
+	route53Client, err := NewRoute53ClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	zones := []Route53HostedZone{}
+	paginator := route53.NewListHostedZonesPaginator(route53Client, &route53.ListHostedZonesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for i := range page.HostedZones {
+			zones = append(zones, newRoute53HostedZone(&page.HostedZones[i]))
+		}
+	}
+	return zones, nil
+}
+
+// newRoute53HostedZone converts the given hosted zone returned by the Route 53 API to a Route53HostedZone.
+func newRoute53HostedZone(zone *types.HostedZone) Route53HostedZone {
+	hostedZone := Route53HostedZone{
+		Id:          strings.TrimPrefix(aws.ToString(zone.Id), "/hostedzone/"),
+		Name:        aws.ToString(zone.Name),
+		RecordCount: aws.ToInt64(zone.ResourceRecordSetCount),
+	}
+	if zone.Config != nil {
+		hostedZone.PrivateZone = zone.Config.PrivateZone
+		hostedZone.Comment = aws.ToString(zone.Config.Comment)
+	}
+	return hostedZone
+}
+
+// findRoute53HostedZone returns the zone with the given name, or if matchParents is true, the zone with the longest
+// name that is the given name or one of its parent domains. Public zones are preferred over private zones with the
+// same name. Returns nil if no zone matches.
+func findRoute53HostedZone(zones []Route53HostedZone, name string, matchParents bool) *Route53HostedZone {
+	name = normalizeRoute53Name(name)
+
+	var found *Route53HostedZone
+	for i := range zones {
+		zone := &zones[i]
+		zoneName := normalizeRoute53Name(zone.Name)
+		matches := zoneName == name || (matchParents && strings.HasSuffix(name, "."+zoneName))
+		if !matches {
+			continue
+		}
+		if found == nil || len(zoneName) > len(normalizeRoute53Name(found.Name)) || (len(zoneName) == len(normalizeRoute53Name(found.Name)) && found.PrivateZone && !zone.PrivateZone) {
+			found = zone
+		}
+	}
+	return found
+}
+
+// GetRoute53RecordSets returns all the Route 53 record sets with the given name in the given hosted zone
+func GetRoute53RecordSets(t testing.TestingT, hostedZoneID, recordName, awsRegion string) []types.ResourceRecordSet {
+	r, err := GetRoute53RecordSetsE(t, hostedZoneID, recordName, awsRegion)
+	require.NoError(t, err)
+
+	return r
+}
+
+// GetRoute53RecordSetsE returns all the Route 53 record sets with the given name in the given hosted zone, of every
+// type. This includes every record set of weighted, failover, latency and other routing policies, which share a name
+// and type and differ by SetIdentifier.
+func GetRoute53RecordSetsE(t testing.TestingT, hostedZoneID, recordName, awsRegion string) ([]types.ResourceRecordSet, error) {
+	route53Client, err := NewRoute53ClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	recordSets := []types.ResourceRecordSet{}
+	paginator := route53.NewListResourceRecordSetsPaginator(route53Client, &route53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(hostedZoneID),
+		StartRecordName: aws.String(recordName),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range page.ResourceRecordSets {
+			// Record sets are sorted by name, so there are no more matches after the first record set with another name
+			if normalizeRoute53Name(aws.ToString(record.Name)) != normalizeRoute53Name(recordName) {
+				return recordSets, nil
+			}
+			recordSets = append(recordSets, record)
+		}
+	}
+	return recordSets, nil
+}
+
+// WaitForDnsPropagation waits until the given domain name has a record with the given value in Route 53 and the given
+// DNS resolvers return that value.
+func WaitForDnsPropagation(t testing.TestingT, fqdn string, expectedValue string, resolvers []string, timeout time.Duration, awsRegion string) {
+	err := WaitForDnsPropagationE(t, fqdn, expectedValue, resolvers, timeout, awsRegion)
+	require.NoError(t, err)
+}
+
+// WaitForDnsPropagationE waits up to the given timeout until the given domain name has a record with the given value in
+// the Route 53 hosted zone it belongs to, and every one of the given DNS resolvers (e.g. "8.8.8.8:53") returns that
+// value for the type of that record. The value is either the value of a record, such as an IP address or CNAME target,
+// or the target DNS name of an alias record, such as the DNS name of a load balancer. As resolvers answer alias records
+// with the records of their target, for alias records every resolver must return one of the addresses it returns for
+// the target instead. Values are compared case insensitively, ignoring trailing dots and the quotes around TXT values.
+func WaitForDnsPropagationE(t testing.TestingT, fqdn string, expectedValue string, resolvers []string, timeout time.Duration, awsRegion string) error {
+	zone, err := GetRoute53HostedZoneForDomainE(t, fqdn, awsRegion)
+	if err != nil {
+		return err
+	}
+
+	retries := int(timeout / route53PollInterval)
+	if retries < 1 {
+		retries = 1
+	}
+
+	description := fmt.Sprintf("Waiting for DNS record %s with value %s to propagate", fqdn, expectedValue)
+	_, err = retry.DoWithRetryE(t, description, retries, route53PollInterval, func() (string, error) {
+		recordSets, err := GetRoute53RecordSetsE(t, zone.Id, fqdn, awsRegion)
+		if err != nil {
+			return "", err
+		}
+		recordSet := findRoute53RecordSetWithValue(recordSets, expectedValue)
+		if recordSet == nil {
+			return "", fmt.Errorf("Route 53 hosted zone %s has no record %s with value %s", zone.Name, fqdn, expectedValue)
+		}
+
+		query := dns_helper.DNSQuery{Type: string(recordSet.Type), Name: fqdn}
+		for _, resolver := range resolvers {
+			answers, err := dns_helper.DNSLookupE(t, query, []string{resolver})
+			if err != nil {
+				return "", err
+			}
+			if recordSet.AliasTarget == nil {
+				if !dnsAnswersHaveValue(answers, expectedValue) {
+					return "", fmt.Errorf("resolver %s returned %v for %s %s instead of %s", resolver, answers, query.Type, fqdn, expectedValue)
+				}
+				continue
+			}
+
+			// Resolvers answer alias records with the records of the alias target, so compare with those
+			targetQuery := dns_helper.DNSQuery{Type: query.Type, Name: aws.ToString(recordSet.AliasTarget.DNSName)}
+			targetAnswers, err := dns_helper.DNSLookupE(t, targetQuery, []string{resolver})
+			if err != nil {
+				return "", err
+			}
+			if !dnsAnswersShareValue(answers, targetAnswers) {
+				return "", fmt.Errorf("resolver %s returned %v for %s %s instead of %v, the records of alias target %s", resolver, answers, query.Type, fqdn, targetAnswers, targetQuery.Name)
+			}
+		}
+		return "", nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Default.Logf(t, "DNS record %s with value %s has propagated to %d resolvers", fqdn, expectedValue, len(resolvers))
+	return nil
+}
+
+// findRoute53RecordSetWithValue returns the first of the given record sets that has the given value, or that is an
+// alias to the given DNS name, or nil if there is none.
+func findRoute53RecordSetWithValue(recordSets []types.ResourceRecordSet, value string) *types.ResourceRecordSet {
+	for i, recordSet := range recordSets {
+		for _, record := range recordSet.ResourceRecords {
+			if normalizeDnsValue(aws.ToString(record.Value)) == normalizeDnsValue(value) {
+				return &recordSets[i]
+			}
+		}
+		if recordSet.AliasTarget != nil && normalizeDnsValue(aws.ToString(recordSet.AliasTarget.DNSName)) == normalizeDnsValue(value) {
+			return &recordSets[i]
+		}
+	}
+	return nil
+}
+
+// dnsAnswersHaveValue returns true if one of the given DNS answers has the given value.
+func dnsAnswersHaveValue(answers dns_helper.DNSAnswers, value string) bool {
+	for _, answer := range answers {
+		if normalizeDnsValue(answer.Value) == normalizeDnsValue(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// dnsAnswersShareValue returns true if one of the given DNS answers has the value of one of the other given answers.
+// Answers are not compared as a whole, as resolvers may return a different subset or rotation of the addresses of
+// load balancers and CDNs on every query.
+func dnsAnswersShareValue(answers dns_helper.DNSAnswers, otherAnswers dns_helper.DNSAnswers) bool {
+	for _, otherAnswer := range otherAnswers {
+		if dnsAnswersHaveValue(answers, otherAnswer.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeDnsValue returns the given record value in lower case without a trailing dot or surrounding quotes, so that
+// values from Route 53 and from DNS answers can be compared.
+func normalizeDnsValue(value string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.Trim(value, `"`), "."))
+}
+
+// normalizeRoute53Name returns the given domain name in lower case without a trailing dot.
+func normalizeRoute53Name(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// GetRoute53HealthCheckObservations returns the latest status of the Route 53 health check with the given ID as
+// reported by each of the health checkers
+func GetRoute53HealthCheckObservations(t testing.TestingT, healthCheckID string, awsRegion string) []Route53HealthCheckObservation {
+	observations, err := GetRoute53HealthCheckObservationsE(t, healthCheckID, awsRegion)
+	require.NoError(t, err)
+
+	return observations
+}
+
+// GetRoute53HealthCheckObservationsE returns the latest status of the Route 53 health check with the given ID as
+// reported by each of the health checkers
+func GetRoute53HealthCheckObservationsE(t testing.TestingT, healthCheckID string, awsRegion string) ([]Route53HealthCheckObservation, error) {
+	route53Client, err := NewRoute53ClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := route53Client.GetHealthCheckStatus(context.Background(), &route53.GetHealthCheckStatusInput{
+		HealthCheckId: aws.String(healthCheckID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	observations := []Route53HealthCheckObservation{}
+	for _, observation := range o.HealthCheckObservations {
+		result := Route53HealthCheckObservation{
+			Region:    string(observation.Region),
+			IPAddress: aws.ToString(observation.IPAddress),
+		}
+		if observation.StatusReport != nil {
+			result.Status = aws.ToString(observation.StatusReport.Status)
+			result.CheckedTime = aws.ToTime(observation.StatusReport.CheckedTime)
+		}
+		observations = append(observations, result)
+	}
+	return observations, nil
+}
+
+// AssertRoute53HealthCheckHealthy checks that the Route 53 health check with the given ID is healthy
+func AssertRoute53HealthCheckHealthy(t testing.TestingT, healthCheckID string, awsRegion string) {
+	err := AssertRoute53HealthCheckHealthyE(t, healthCheckID, awsRegion)
+	require.NoError(t, err)
+}
+
+// AssertRoute53HealthCheckHealthyE checks that the Route 53 health check with the given ID is healthy, which is the
+// case when more than 18% of the health checkers report it as healthy, like Route 53 does. Returns a
+// Route53HealthCheckNotHealthy error if it is not.
+func AssertRoute53HealthCheckHealthyE(t testing.TestingT, healthCheckID string, awsRegion string) error {
+	observations, err := GetRoute53HealthCheckObservationsE(t, healthCheckID, awsRegion)
+	if err != nil {
+		return err
+	}
+	return checkRoute53HealthCheckHealthy(healthCheckID, observations)
+}
+
+// WaitForRoute53HealthCheckHealthy waits until the Route 53 health check with the given ID is healthy
+func WaitForRoute53HealthCheckHealthy(t testing.TestingT, healthCheckID string, timeout time.Duration, awsRegion string) {
+	err := WaitForRoute53HealthCheckHealthyE(t, healthCheckID, timeout, awsRegion)
+	require.NoError(t, err)
+}
+
+// WaitForRoute53HealthCheckHealthyE waits up to the given timeout until the Route 53 health check with the given ID is
+// healthy. See AssertRoute53HealthCheckHealthyE for when a health check is healthy.
+func WaitForRoute53HealthCheckHealthyE(t testing.TestingT, healthCheckID string, timeout time.Duration, awsRegion string) error {
+	retries := int(timeout / route53PollInterval)
+	if retries < 1 {
+		retries = 1
+	}
+
+	description := fmt.Sprintf("Waiting for Route 53 health check %s to be healthy", healthCheckID)
+	_, err := retry.DoWithRetryE(t, description, retries, route53PollInterval, func() (string, error) {
+		return "", AssertRoute53HealthCheckHealthyE(t, healthCheckID, awsRegion)
+	})
+	return err
+}
+
+// checkRoute53HealthCheckHealthy returns a Route53HealthCheckNotHealthy error unless more than the healthy threshold
+// of the given observations report success.
+func checkRoute53HealthCheckHealthy(healthCheckID string, observations []Route53HealthCheckObservation) error {
+	healthy := 0
+	for _, observation := range observations {
+		if strings.HasPrefix(observation.Status, "Success") {
+			healthy++
+		}
+	}
+	if len(observations) == 0 || float64(healthy)/float64(len(observations)) <= route53HealthyThreshold {
+		return Route53HealthCheckNotHealthy{HealthCheckID: healthCheckID, HealthyCheckers: healthy, TotalCheckers: len(observations)}
+	}
+	return nil
+}
+
 // NewRoute53Client creates a route 53 client.
-func NewRoute53Client(t *testing.T, region string) *route53.Client {
+func NewRoute53Client(t testing.TestingT, region string) *route53.Client {
 	c, err := NewRoute53ClientE(t, region)
 	require.NoError(t, err)
 
@@ -55,8 +434,8 @@ func NewRoute53Client(t *testing.T, region string) *route53.Client {
 }
 
 // NewRoute53ClientE creates a route 53 client.
-func NewRoute53ClientE(t *testing.T, region string) (*route53.Client, error) {
-	sess, err := NewAuthenticatedSession(region)
+func NewRoute53ClientE(t testing.TestingT, region string) (*route53.Client, error) {
+	sess, err := newAuthenticatedSession(t, region)
 	if err != nil {
 		return nil, err
 	}
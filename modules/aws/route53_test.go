@@ -9,6 +9,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/route53"
 	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	dns_helper "github.com/gruntwork-io/terratest/modules/dns-helper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -85,3 +86,72 @@ func TestRoute53Record(t *testing.T) {
 	})
 
 }
+
+func TestFindRoute53HostedZone(t *testing.T) {
+	t.Parallel()
+
+	zones := []Route53HostedZone{
+		{Id: "Z1", Name: "example.com."},
+		{Id: "Z2", Name: "dev.example.com.", PrivateZone: true},
+		{Id: "Z3", Name: "dev.example.com."},
+	}
+
+	assert.Equal(t, "Z1", findRoute53HostedZone(zones, "example.com", false).Id)
+	assert.Equal(t, "Z3", findRoute53HostedZone(zones, "dev.example.com.", false).Id)
+	assert.Nil(t, findRoute53HostedZone(zones, "api.dev.example.com", false))
+	assert.Equal(t, "Z3", findRoute53HostedZone(zones, "API.dev.example.com", true).Id)
+	assert.Equal(t, "Z1", findRoute53HostedZone(zones, "www.example.com.", true).Id)
+	assert.Nil(t, findRoute53HostedZone(zones, "notexample.com", true))
+}
+
+func TestFindRoute53RecordSetWithValue(t *testing.T) {
+	t.Parallel()
+
+	recordSets := []types.ResourceRecordSet{
+		{Name: aws.String("www.example.com."), Type: types.RRTypeTxt, ResourceRecords: []types.ResourceRecord{{Value: aws.String(`"v=spf1 -all"`)}}},
+		{Name: aws.String("www.example.com."), Type: types.RRTypeCname, SetIdentifier: aws.String("blue"), Weight: aws.Int64(90), ResourceRecords: []types.ResourceRecord{{Value: aws.String("blue.example.com")}}},
+		{Name: aws.String("www.example.com."), Type: types.RRTypeCname, SetIdentifier: aws.String("green"), Weight: aws.Int64(10), ResourceRecords: []types.ResourceRecord{{Value: aws.String("green.example.com")}}},
+		{Name: aws.String("api.example.com."), Type: types.RRTypeA, AliasTarget: &types.AliasTarget{DNSName: aws.String("dualstack.my-lb-123.us-east-1.elb.amazonaws.com.")}},
+	}
+
+	assert.Equal(t, &recordSets[2], findRoute53RecordSetWithValue(recordSets, "Green.example.com."))
+	assert.Equal(t, &recordSets[0], findRoute53RecordSetWithValue(recordSets, "v=spf1 -all"))
+	assert.Equal(t, &recordSets[3], findRoute53RecordSetWithValue(recordSets, "dualstack.my-lb-123.us-east-1.elb.amazonaws.com"))
+	assert.Nil(t, findRoute53RecordSetWithValue(recordSets, "red.example.com"))
+}
+
+func TestDnsAnswersHaveValue(t *testing.T) {
+	t.Parallel()
+
+	answers := dns_helper.DNSAnswers{{Type: "CNAME", Value: "blue.example.com."}, {Type: "TXT", Value: `"hello"`}}
+
+	assert.True(t, dnsAnswersHaveValue(answers, "blue.example.com"))
+	assert.True(t, dnsAnswersHaveValue(answers, "hello"))
+	assert.False(t, dnsAnswersHaveValue(answers, "green.example.com"))
+}
+
+func TestDnsAnswersShareValue(t *testing.T) {
+	t.Parallel()
+
+	answers := dns_helper.DNSAnswers{{Type: "A", Value: "192.0.2.1"}, {Type: "A", Value: "192.0.2.2"}}
+
+	assert.True(t, dnsAnswersShareValue(answers, dns_helper.DNSAnswers{{Type: "A", Value: "192.0.2.2"}, {Type: "A", Value: "192.0.2.3"}}))
+	assert.False(t, dnsAnswersShareValue(answers, dns_helper.DNSAnswers{{Type: "A", Value: "192.0.2.3"}}))
+	assert.False(t, dnsAnswersShareValue(answers, dns_helper.DNSAnswers{}))
+}
+
+func TestCheckRoute53HealthCheckHealthy(t *testing.T) {
+	t.Parallel()
+
+	observations := []Route53HealthCheckObservation{
+		{Status: "Success: HTTP Status Code 200, OK"},
+		{Status: "Failure: Connection timed out."},
+		{Status: "Failure: Connection timed out."},
+		{Status: "Failure: Connection timed out."},
+	}
+	assert.NoError(t, checkRoute53HealthCheckHealthy("abc", observations))
+
+	err := checkRoute53HealthCheckHealthy("abc", observations[1:])
+	assert.Equal(t, Route53HealthCheckNotHealthy{HealthCheckID: "abc", HealthyCheckers: 0, TotalCheckers: 3}, err)
+	assert.Error(t, checkRoute53HealthCheckHealthy("abc", []Route53HealthCheckObservation{}))
+}
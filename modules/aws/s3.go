@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
@@ -130,6 +131,155 @@ func GetS3ObjectContentsE(t testing.TestingT, awsRegion string, bucket string, k
 	return contents, nil
 }
 
+// DeleteS3Object deletes the object in the given bucket with the given key.
+func DeleteS3Object(t testing.TestingT, awsRegion string, bucket string, key string) {
+	err := DeleteS3ObjectE(t, awsRegion, bucket, key)
+	require.NoError(t, err)
+}
+
+// DeleteS3ObjectE deletes the object in the given bucket with the given key. Deleting an object that doesn't exist is
+// not an error.
+func DeleteS3ObjectE(t testing.TestingT, awsRegion string, bucket string, key string) error {
+	logger.Default.Logf(t, "Deleting s3://%s/%s", bucket, key)
+
+	s3Client, err := NewS3ClientE(t, awsRegion)
+	if err != nil {
+		return err
+	}
+
+	_, err = s3Client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	})
+	return err
+}
+
+// GetS3ObjectLastModified returns when the object with the given key in the given bucket was last modified.
+func GetS3ObjectLastModified(t testing.TestingT, awsRegion string, bucket string, key string) time.Time {
+	lastModified, err := GetS3ObjectLastModifiedE(t, awsRegion, bucket, key)
+	require.NoError(t, err)
+	return lastModified
+}
+
+// GetS3ObjectLastModifiedE returns when the object with the given key in the given bucket was last modified.
+func GetS3ObjectLastModifiedE(t testing.TestingT, awsRegion string, bucket string, key string) (time.Time, error) {
+	s3Client, err := NewS3ClientE(t, awsRegion)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	res, err := s3Client.HeadObject(context.Background(), &s3.HeadObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return aws.ToTime(res.LastModified), nil
+}
+
+// PutS3ObjectContents writes the given contents to the object in the given bucket with the given key, overwriting it
+// if it exists.
+func PutS3ObjectContents(t testing.TestingT, awsRegion string, bucket string, key string, contents string) {
+	err := PutS3ObjectContentsE(t, awsRegion, bucket, key, contents)
+	require.NoError(t, err)
+}
+
+// PutS3ObjectContentsE writes the given contents to the object in the given bucket with the given key, overwriting it
+// if it exists.
+func PutS3ObjectContentsE(t testing.TestingT, awsRegion string, bucket string, key string, contents string) error {
+	logger.Default.Logf(t, "Writing contents to s3://%s/%s", bucket, key)
+
+	s3Client, err := NewS3ClientE(t, awsRegion)
+	if err != nil {
+		return err
+	}
+
+	_, err = s3Client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+		Body:   strings.NewReader(contents),
+	})
+	return err
+}
+
+// DeleteS3ObjectsWithPrefix deletes all the objects in the given bucket whose keys start with the given prefix.
+func DeleteS3ObjectsWithPrefix(t testing.TestingT, awsRegion string, bucket string, prefix string) {
+	err := DeleteS3ObjectsWithPrefixE(t, awsRegion, bucket, prefix)
+	require.NoError(t, err)
+}
+
+// DeleteS3ObjectsWithPrefixE deletes all the objects in the given bucket whose keys start with the given prefix. Only
+// the latest version of the objects is deleted in versioned buckets.
+func DeleteS3ObjectsWithPrefixE(t testing.TestingT, awsRegion string, bucket string, prefix string) error {
+	logger.Default.Logf(t, "Deleting s3://%s/%s*", bucket, prefix)
+
+	s3Client, err := NewS3ClientE(t, awsRegion)
+	if err != nil {
+		return err
+	}
+
+	paginator := s3.NewListObjectsV2Paginator(s3Client, &s3.ListObjectsV2Input{
+		Bucket: &bucket,
+		Prefix: &prefix,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return err
+		}
+		if len(page.Contents) == 0 {
+			continue
+		}
+
+		objectsToDelete := make([]types.ObjectIdentifier, 0, len(page.Contents))
+		for _, object := range page.Contents {
+			objectsToDelete = append(objectsToDelete, types.ObjectIdentifier{Key: object.Key})
+		}
+		_, err = s3Client.DeleteObjects(context.Background(), &s3.DeleteObjectsInput{
+			Bucket: &bucket,
+			Delete: &types.Delete{Objects: objectsToDelete},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListS3ObjectKeysWithPrefix returns the keys of all the objects in the given bucket whose keys start with the given
+// prefix.
+func ListS3ObjectKeysWithPrefix(t testing.TestingT, awsRegion string, bucket string, prefix string) []string {
+	keys, err := ListS3ObjectKeysWithPrefixE(t, awsRegion, bucket, prefix)
+	require.NoError(t, err)
+	return keys
+}
+
+// ListS3ObjectKeysWithPrefixE returns the keys of all the objects in the given bucket whose keys start with the given
+// prefix.
+func ListS3ObjectKeysWithPrefixE(t testing.TestingT, awsRegion string, bucket string, prefix string) ([]string, error) {
+	s3Client, err := NewS3ClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+	paginator := s3.NewListObjectsV2Paginator(s3Client, &s3.ListObjectsV2Input{
+		Bucket: &bucket,
+		Prefix: &prefix,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range page.Contents {
+			keys = append(keys, aws.ToString(object.Key))
+		}
+	}
+	return keys, nil
+}
+
 // CreateS3Bucket creates an S3 bucket in the given region with the given name. Note that S3 bucket names must be globally unique.
 func CreateS3Bucket(t testing.TestingT, region string, name string) {
 	err := CreateS3BucketE(t, region, name)
@@ -513,14 +663,15 @@ func NewS3Client(t testing.TestingT, region string) *s3.Client {
 	return client
 }
 
-// NewS3ClientE creates an S3 client.
+// NewS3ClientE creates an S3 client. The client uses path-style addressing when requests are sent to a custom endpoint,
+// as emulators such as LocalStack don't resolve the DNS names of virtual-hosted-style addressing.
 func NewS3ClientE(t testing.TestingT, region string) (*s3.Client, error) {
-	sess, err := NewAuthenticatedSession(region)
+	sess, err := newAuthenticatedSession(t, region)
 	if err != nil {
 		return nil, err
 	}
 
-	return s3.NewFromConfig(*sess), nil
+	return newS3ClientFromConfig(sess), nil
 }
 
 // NewS3Uploader creates an S3 Uploader.
@@ -532,12 +683,22 @@ func NewS3Uploader(t testing.TestingT, region string) *manager.Uploader {
 
 // NewS3UploaderE creates an S3 Uploader.
 func NewS3UploaderE(t testing.TestingT, region string) (*manager.Uploader, error) {
-	sess, err := NewAuthenticatedSession(region)
+	sess, err := newAuthenticatedSession(t, region)
 	if err != nil {
 		return nil, err
 	}
 
-	return manager.NewUploader(s3.NewFromConfig(*sess)), nil
+	return manager.NewUploader(newS3ClientFromConfig(sess)), nil
+}
+
+// newS3ClientFromConfig creates an S3 client from the given config, using path-style addressing if the config sends
+// requests to a custom endpoint.
+func newS3ClientFromConfig(cfg *aws.Config) *s3.Client {
+	return s3.NewFromConfig(*cfg, func(options *s3.Options) {
+		if hasCustomEndpoint(cfg) {
+			options.UsePathStyle = true
+		}
+	})
 }
 
 // S3AccessLoggingNotEnabledErr is a custom error that occurs when acess logging hasn't been enabled on the S3 Bucket
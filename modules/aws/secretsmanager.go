@@ -2,6 +2,9 @@ package aws
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
@@ -10,6 +13,16 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// SecretRotation contains the rotation configuration of a Secrets Manager secret.
+type SecretRotation struct {
+	Enabled                bool      // Whether rotation is enabled
+	LambdaArn              string    // The ARN of the Lambda function that rotates the secret. Empty for managed rotation.
+	AutomaticallyAfterDays int64     // The number of days between rotations. Zero if the rotation uses a schedule expression.
+	ScheduleExpression     string    // The cron() or rate() expression of the rotation schedule. Empty if the rotation uses a number of days.
+	LastRotatedDate        time.Time // The last time the secret was rotated. Zero if it was never rotated.
+	NextRotationDate       time.Time // The next time the secret is scheduled to be rotated. Zero if rotation is disabled.
+}
+
 // CreateSecretStringWithDefaultKey creates a new secret in Secrets Manager using the default "aws/secretsmanager" KMS key and returns the secret ARN
 func CreateSecretStringWithDefaultKey(t testing.TestingT, awsRegion, description, name, secretString string) string {
 	arn, err := CreateSecretStringWithDefaultKeyE(t, awsRegion, description, name, secretString)
@@ -20,6 +33,7 @@ func CreateSecretStringWithDefaultKey(t testing.TestingT, awsRegion, description
 // CreateSecretStringWithDefaultKeyE creates a new secret in Secrets Manager using the default "aws/secretsmanager" KMS key and returns the secret ARN
 func CreateSecretStringWithDefaultKeyE(t testing.TestingT, awsRegion, description, name, secretString string) (string, error) {
 	logger.Default.Logf(t, "Creating new secret in secrets manager named %s", name)
+	registerSecretValue(secretString)
 
 	client := NewSecretsManagerClient(t, awsRegion)
 
@@ -36,14 +50,17 @@ func CreateSecretStringWithDefaultKeyE(t testing.TestingT, awsRegion, descriptio
 	return aws.ToString(secret.ARN), nil
 }
 
-// GetSecretValue takes the friendly name or ARN of a secret and returns the plaintext value
+// GetSecretValue takes the friendly name or ARN of a secret and returns the plaintext value. The value is registered
+// with logger.RegisterSecret so that it is redacted from the logs.
 func GetSecretValue(t testing.TestingT, awsRegion, id string) string {
 	secret, err := GetSecretValueE(t, awsRegion, id)
 	require.NoError(t, err)
 	return secret
 }
 
-// GetSecretValueE takes the friendly name or ARN of a secret and returns the plaintext value
+// GetSecretValueE takes the friendly name or ARN of a secret and returns the plaintext value. The value, and the
+// password, secret, token and key fields in it if it is a JSON object, are registered with logger.RegisterSecret so
+// that they are redacted from the logs.
 func GetSecretValueE(t testing.TestingT, awsRegion, id string) (string, error) {
 	logger.Default.Logf(t, "Getting value of secret with ID %s", id)
 
@@ -56,7 +73,9 @@ func GetSecretValueE(t testing.TestingT, awsRegion, id string) (string, error) {
 		return "", err
 	}
 
-	return aws.ToString(secret.SecretString), nil
+	value := aws.ToString(secret.SecretString)
+	registerSecretValue(value)
+	return value, nil
 }
 
 // PutSecretString updates a secret in Secrets Manager to a new string value
@@ -68,6 +87,7 @@ func PutSecretString(t testing.TestingT, awsRegion, id string, secretString stri
 // PutSecretStringE updates a secret in Secrets Manager to a new string value
 func PutSecretStringE(t testing.TestingT, awsRegion, id string, secretString string) error {
 	logger.Default.Logf(t, "Updating secret with ID %s", id)
+	registerSecretValue(secretString)
 
 	client := NewSecretsManagerClient(t, awsRegion)
 
@@ -99,6 +119,130 @@ func DeleteSecretE(t testing.TestingT, awsRegion, id string, forceDelete bool) e
 	return err
 }
 
+// CreateSecretStringWithKmsKey creates a new secret in Secrets Manager encrypted with the given KMS key and returns the
+// secret ARN
+func CreateSecretStringWithKmsKey(t testing.TestingT, awsRegion, description, name, secretString, kmsKeyID string) string {
+	arn, err := CreateSecretStringWithKmsKeyE(t, awsRegion, description, name, secretString, kmsKeyID)
+	require.NoError(t, err)
+	return arn
+}
+
+// CreateSecretStringWithKmsKeyE creates a new secret in Secrets Manager encrypted with the given KMS key and returns the
+// secret ARN. The key can be given as a key ID, key ARN, alias name or alias ARN.
+func CreateSecretStringWithKmsKeyE(t testing.TestingT, awsRegion, description, name, secretString, kmsKeyID string) (string, error) {
+	logger.Default.Logf(t, "Creating new secret in secrets manager named %s encrypted with KMS key %s", name, kmsKeyID)
+	registerSecretValue(secretString)
+
+	client, err := NewSecretsManagerClientE(t, awsRegion)
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := client.CreateSecret(context.Background(), &secretsmanager.CreateSecretInput{
+		Description:  aws.String(description),
+		Name:         aws.String(name),
+		SecretString: aws.String(secretString),
+		KmsKeyId:     aws.String(kmsKeyID),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return aws.ToString(secret.ARN), nil
+}
+
+// GetSecretRotation takes the friendly name or ARN of a secret and returns its rotation configuration
+func GetSecretRotation(t testing.TestingT, awsRegion, id string) *SecretRotation {
+	rotation, err := GetSecretRotationE(t, awsRegion, id)
+	require.NoError(t, err)
+	return rotation
+}
+
+// GetSecretRotationE takes the friendly name or ARN of a secret and returns its rotation configuration
+func GetSecretRotationE(t testing.TestingT, awsRegion, id string) (*SecretRotation, error) {
+	client, err := NewSecretsManagerClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := client.DescribeSecret(context.Background(), &secretsmanager.DescribeSecretInput{
+		SecretId: aws.String(id),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newSecretRotation(secret), nil
+}
+
+// AssertSecretRotationEnabled takes the friendly name or ARN of a secret and checks that rotation is enabled for it,
+// failing the test if it is not
+func AssertSecretRotationEnabled(t testing.TestingT, awsRegion, id string) {
+	err := AssertSecretRotationEnabledE(t, awsRegion, id)
+	require.NoError(t, err)
+}
+
+// AssertSecretRotationEnabledE takes the friendly name or ARN of a secret and checks that rotation is enabled for it.
+// Returns a SecretRotationNotEnabled error if it is not.
+func AssertSecretRotationEnabledE(t testing.TestingT, awsRegion, id string) error {
+	rotation, err := GetSecretRotationE(t, awsRegion, id)
+	if err != nil {
+		return err
+	}
+	if !rotation.Enabled {
+		return SecretRotationNotEnabled{SecretID: id, AwsRegion: awsRegion}
+	}
+	return nil
+}
+
+// newSecretRotation returns the rotation configuration of the given secret.
+func newSecretRotation(secret *secretsmanager.DescribeSecretOutput) *SecretRotation {
+	rotation := &SecretRotation{
+		Enabled:          aws.ToBool(secret.RotationEnabled),
+		LambdaArn:        aws.ToString(secret.RotationLambdaARN),
+		LastRotatedDate:  aws.ToTime(secret.LastRotatedDate),
+		NextRotationDate: aws.ToTime(secret.NextRotationDate),
+	}
+	if secret.RotationRules != nil {
+		rotation.AutomaticallyAfterDays = aws.ToInt64(secret.RotationRules.AutomaticallyAfterDays)
+		rotation.ScheduleExpression = aws.ToString(secret.RotationRules.ScheduleExpression)
+	}
+	return rotation
+}
+
+// secretFieldNames are the words that the names of the fields of a JSON secret value contain if the fields hold
+// credentials, such as password, db_password, api_key or access_token.
+var secretFieldNames = []string{"password", "secret", "token", "key"}
+
+// registerSecretValue registers the given secret value with logger.RegisterSecret, along with the string values of the
+// fields in it that hold credentials (see secretFieldNames) if it is a JSON object, as Secrets Manager secrets often
+// hold credentials as JSON key/value pairs. The other fields, such as the username, host or database name, are not
+// redacted, as they are often short common values that would otherwise be redacted from unrelated log lines.
+func registerSecretValue(value string) {
+	logger.RegisterSecret(value)
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return
+	}
+	for name, field := range fields {
+		if fieldValue, isString := field.(string); isString && isSecretFieldName(name) {
+			logger.RegisterSecret(fieldValue)
+		}
+	}
+}
+
+// isSecretFieldName returns true if the JSON field with the given name holds credentials (see secretFieldNames).
+func isSecretFieldName(name string) bool {
+	name = strings.ToLower(name)
+	for _, secretFieldName := range secretFieldNames {
+		if strings.Contains(name, secretFieldName) {
+			return true
+		}
+	}
+	return false
+}
+
 // NewSecretsManagerClient creates a new SecretsManager client.
 func NewSecretsManagerClient(t testing.TestingT, region string) *secretsmanager.Client {
 	client, err := NewSecretsManagerClientE(t, region)
@@ -108,7 +252,7 @@ func NewSecretsManagerClient(t testing.TestingT, region string) *secretsmanager.
 
 // NewSecretsManagerClientE creates a new SecretsManager client.
 func NewSecretsManagerClientE(t testing.TestingT, region string) (*secretsmanager.Client, error) {
-	sess, err := NewAuthenticatedSession(region)
+	sess, err := newAuthenticatedSession(t, region)
 	if err != nil {
 		return nil, err
 	}
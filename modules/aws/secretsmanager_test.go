@@ -2,7 +2,12 @@ package aws
 
 import (
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -29,6 +34,42 @@ func TestSecretsManagerMethods(t *testing.T) {
 	assert.Equal(t, secretUpdatedValue, storedValueAfterUpdate)
 }
 
+func TestNewSecretRotation(t *testing.T) {
+	t.Parallel()
+
+	lastRotated := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rotation := newSecretRotation(&secretsmanager.DescribeSecretOutput{
+		RotationEnabled:   aws.Bool(true),
+		RotationLambdaARN: aws.String("arn:aws:lambda:us-east-1:123456789012:function:rotate"),
+		RotationRules:     &types.RotationRulesType{AutomaticallyAfterDays: aws.Int64(30)},
+		LastRotatedDate:   aws.Time(lastRotated),
+	})
+
+	assert.True(t, rotation.Enabled)
+	assert.Equal(t, "arn:aws:lambda:us-east-1:123456789012:function:rotate", rotation.LambdaArn)
+	assert.Equal(t, int64(30), rotation.AutomaticallyAfterDays)
+	assert.Equal(t, lastRotated, rotation.LastRotatedDate)
+	assert.True(t, rotation.NextRotationDate.IsZero())
+
+	assert.False(t, newSecretRotation(&secretsmanager.DescribeSecretOutput{}).Enabled)
+}
+
+func TestRegisterSecretValue(t *testing.T) {
+	t.Parallel()
+
+	password := random.UniqueId()
+	apiKey := random.UniqueId()
+	username := random.UniqueId()
+	registerSecretValue(`{"username": "` + username + `", "password": "` + password + `", "API_KEY": "` + apiKey + `", "port": 5432}`)
+
+	assert.Equal(t, "user "+username+" with password "+logger.RedactedValue, logger.Redact("user "+username+" with password "+password))
+	assert.Equal(t, logger.RedactedValue, logger.Redact(apiKey))
+
+	plain := random.UniqueId()
+	registerSecretValue(plain)
+	assert.Equal(t, logger.RedactedValue, logger.Redact(plain))
+}
+
 func deleteSecret(t *testing.T, region, id string) {
 	DeleteSecret(t, region, id, true)
 
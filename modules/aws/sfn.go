@@ -0,0 +1,423 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sfn"
+	"github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// StepFunctionExecutionResult contains the final state of a Step Functions execution.
+type StepFunctionExecutionResult struct {
+	Status types.ExecutionStatus // The final status of the execution (e.g. SUCCEEDED, FAILED, TIMED_OUT or ABORTED)
+	Output string                // The JSON output of the execution. Only set if the execution succeeded.
+	Error  string                // The error name reported in the execution history. Only set if the execution did not succeed.
+	Cause  string                // The cause of the error reported in the execution history. Only set if the execution did not succeed.
+}
+
+// StepFunctionHistoryEvent is an event in the history of a Step Functions execution. Only the fields that apply to the
+// type of the event are set.
+type StepFunctionHistoryEvent struct {
+	Id              int64                  // The id of the event. Events are numbered from 1 in the order they happened.
+	PreviousEventId int64                  // The id of the event that led to this event
+	Timestamp       time.Time              // The date and time the event occurred
+	Type            types.HistoryEventType // The type of the event (e.g. TaskStateEntered or ExecutionSucceeded)
+	StateName       string                 // The name of the state that was entered or exited
+	Resource        string                 // The ARN of the Lambda function, activity or service integration that was called
+	Input           string                 // The JSON input of the execution, state or task
+	Output          string                 // The JSON output of the execution, state or task
+	Error           string                 // The error name of a failed, aborted or timed out event
+	Cause           string                 // The cause of the error of a failed, aborted or timed out event
+}
+
+// stepFunctionPollInterval is the time to wait between checks of the status of an execution when waiting with a
+// timeout.
+const stepFunctionPollInterval = 2 * time.Second
+
+// StartStepFunctionExecution starts an execution of the given state machine with the given JSON input and returns the
+// ARN of the execution.
+func StartStepFunctionExecution(t testing.TestingT, region string, stateMachineArn string, input string) string {
+	executionArn, err := StartStepFunctionExecutionE(t, region, stateMachineArn, input)
+	require.NoError(t, err)
+	return executionArn
+}
+
+// StartStepFunctionExecutionE starts an execution of the given state machine with the given JSON input and returns the
+// ARN of the execution.
+func StartStepFunctionExecutionE(t testing.TestingT, region string, stateMachineArn string, input string) (string, error) {
+	logger.Default.Logf(t, "Starting execution of state machine %s in %s", stateMachineArn, region)
+
+	client, err := NewSfnClientE(t, region)
+	if err != nil {
+		return "", err
+	}
+
+	startInput := &sfn.StartExecutionInput{
+		StateMachineArn: aws.String(stateMachineArn),
+	}
+	if input != "" {
+		startInput.Input = aws.String(input)
+	}
+
+	output, err := client.StartExecution(context.Background(), startInput)
+	if err != nil {
+		return "", err
+	}
+
+	return aws.ToString(output.ExecutionArn), nil
+}
+
+// WaitForStepFunctionExecution waits until the given Step Functions execution is no longer running and returns its
+// final status and output. This will fail the test if the execution did not succeed.
+func WaitForStepFunctionExecution(t testing.TestingT, region string, executionArn string, retries int, sleepBetweenRetries time.Duration) *StepFunctionExecutionResult {
+	result, err := WaitForStepFunctionExecutionE(t, region, executionArn, retries, sleepBetweenRetries)
+	require.NoError(t, err)
+	return result
+}
+
+// WaitForStepFunctionExecutionE waits until the given Step Functions execution is no longer running and returns its
+// final status and output. If the execution did not succeed, the result is returned along with a
+// StepFunctionExecutionFailedError that includes the error and cause found in the execution history.
+func WaitForStepFunctionExecutionE(t testing.TestingT, region string, executionArn string, retries int, sleepBetweenRetries time.Duration) (*StepFunctionExecutionResult, error) {
+	client, err := NewSfnClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	description := fmt.Sprintf("Waiting for Step Functions execution %s to finish", executionArn)
+	out, err := retry.DoWithRetryInterfaceE(t, description, retries, sleepBetweenRetries, func() (interface{}, error) {
+		output, err := client.DescribeExecution(context.Background(), &sfn.DescribeExecutionInput{
+			ExecutionArn: aws.String(executionArn),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if output.Status == types.ExecutionStatusRunning {
+			return nil, fmt.Errorf("execution %s is still %s", executionArn, output.Status)
+		}
+		return &StepFunctionExecutionResult{
+			Status: output.Status,
+			Output: aws.ToString(output.Output),
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := out.(*StepFunctionExecutionResult)
+	if result.Status == types.ExecutionStatusSucceeded {
+		return result, nil
+	}
+
+	result.Error, result.Cause, err = getStepFunctionExecutionFailureE(client, executionArn)
+	if err != nil {
+		return result, err
+	}
+
+	return result, checkStepFunctionExecutionSucceeded(executionArn, result)
+}
+
+// WaitForStepFunctionExecutionWithTimeout waits up to the given timeout for the given Step Functions execution to
+// finish and returns its final status and output. This will fail the test if the execution did not succeed.
+func WaitForStepFunctionExecutionWithTimeout(t testing.TestingT, region string, executionArn string, timeout time.Duration) *StepFunctionExecutionResult {
+	result, err := WaitForStepFunctionExecutionWithTimeoutE(t, region, executionArn, timeout)
+	require.NoError(t, err)
+	return result
+}
+
+// WaitForStepFunctionExecutionWithTimeoutE waits up to the given timeout for the given Step Functions execution to
+// finish and returns its final status and output. The status of the execution is checked every few seconds. See
+// WaitForStepFunctionExecutionE for the errors returned when the execution did not succeed.
+func WaitForStepFunctionExecutionWithTimeoutE(t testing.TestingT, region string, executionArn string, timeout time.Duration) (*StepFunctionExecutionResult, error) {
+	return WaitForStepFunctionExecutionE(t, region, executionArn, stepFunctionRetriesForTimeout(timeout), stepFunctionPollInterval)
+}
+
+// stepFunctionRetriesForTimeout returns the number of times to check the status of an execution, stepFunctionPollInterval
+// apart, to wait for about the given timeout.
+func stepFunctionRetriesForTimeout(timeout time.Duration) int {
+	retries := int(timeout / stepFunctionPollInterval)
+	if retries < 1 {
+		return 1
+	}
+	return retries
+}
+
+// GetStepFunctionExecutionResult returns the current status and output of the given Step Functions execution without
+// waiting for it to finish.
+func GetStepFunctionExecutionResult(t testing.TestingT, region string, executionArn string) *StepFunctionExecutionResult {
+	result, err := GetStepFunctionExecutionResultE(t, region, executionArn)
+	require.NoError(t, err)
+	return result
+}
+
+// GetStepFunctionExecutionResultE returns the current status and output of the given Step Functions execution without
+// waiting for it to finish. If the execution finished without succeeding, the error and cause found in the execution
+// history are included in the result.
+func GetStepFunctionExecutionResultE(t testing.TestingT, region string, executionArn string) (*StepFunctionExecutionResult, error) {
+	client, err := NewSfnClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.DescribeExecution(context.Background(), &sfn.DescribeExecutionInput{
+		ExecutionArn: aws.String(executionArn),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StepFunctionExecutionResult{
+		Status: output.Status,
+		Output: aws.ToString(output.Output),
+	}
+	if result.Status == types.ExecutionStatusRunning || result.Status == types.ExecutionStatusSucceeded {
+		return result, nil
+	}
+
+	result.Error, result.Cause, err = getStepFunctionExecutionFailureE(client, executionArn)
+	return result, err
+}
+
+// AssertStepFunctionExecutionSucceeded checks that the given Step Functions execution finished with status SUCCEEDED
+// and fails the test if it did not.
+func AssertStepFunctionExecutionSucceeded(t testing.TestingT, region string, executionArn string) {
+	err := AssertStepFunctionExecutionSucceededE(t, region, executionArn)
+	require.NoError(t, err)
+}
+
+// AssertStepFunctionExecutionSucceededE checks that the given Step Functions execution finished with status SUCCEEDED
+// and returns a StepFunctionExecutionFailedError if it did not.
+func AssertStepFunctionExecutionSucceededE(t testing.TestingT, region string, executionArn string) error {
+	result, err := GetStepFunctionExecutionResultE(t, region, executionArn)
+	if err != nil {
+		return err
+	}
+	return checkStepFunctionExecutionSucceeded(executionArn, result)
+}
+
+// AssertStepFunctionExecutionOutput checks that the given Step Functions execution succeeded with an output that is
+// equal, as JSON, to the given expected output and fails the test if it did not.
+func AssertStepFunctionExecutionOutput(t testing.TestingT, region string, executionArn string, expectedOutput string) {
+	err := AssertStepFunctionExecutionOutputE(t, region, executionArn, expectedOutput)
+	require.NoError(t, err)
+}
+
+// AssertStepFunctionExecutionOutputE checks that the given Step Functions execution succeeded with an output that is
+// equal, as JSON, to the given expected output. The formatting of the JSON and the order of object keys are ignored.
+// Returns a StepFunctionExecutionFailedError if the execution did not succeed, or an
+// UnexpectedStepFunctionExecutionOutput error if the output is different.
+func AssertStepFunctionExecutionOutputE(t testing.TestingT, region string, executionArn string, expectedOutput string) error {
+	result, err := GetStepFunctionExecutionResultE(t, region, executionArn)
+	if err != nil {
+		return err
+	}
+	if err := checkStepFunctionExecutionSucceeded(executionArn, result); err != nil {
+		return err
+	}
+	return checkStepFunctionExecutionOutput(executionArn, result.Output, expectedOutput)
+}
+
+// checkStepFunctionExecutionSucceeded returns a StepFunctionExecutionFailedError if the given result of the given
+// execution does not have status SUCCEEDED.
+func checkStepFunctionExecutionSucceeded(executionArn string, result *StepFunctionExecutionResult) error {
+	if result.Status == types.ExecutionStatusSucceeded {
+		return nil
+	}
+	return StepFunctionExecutionFailedError{
+		ExecutionArn: executionArn,
+		Status:       string(result.Status),
+		ErrorName:    result.Error,
+		Cause:        result.Cause,
+	}
+}
+
+// checkStepFunctionExecutionOutput returns an UnexpectedStepFunctionExecutionOutput error if the given actual and
+// expected outputs of the given execution are not equal as JSON.
+func checkStepFunctionExecutionOutput(executionArn string, actualOutput string, expectedOutput string) error {
+	var actual, expected interface{}
+	if err := json.Unmarshal([]byte(actualOutput), &actual); err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(expectedOutput), &expected); err != nil {
+		return err
+	}
+	if reflect.DeepEqual(actual, expected) {
+		return nil
+	}
+	return UnexpectedStepFunctionExecutionOutput{
+		ExecutionArn:   executionArn,
+		ExpectedOutput: expectedOutput,
+		ActualOutput:   actualOutput,
+	}
+}
+
+// GetStepFunctionExecutionHistory returns all the events in the history of the given Step Functions execution, oldest
+// first.
+func GetStepFunctionExecutionHistory(t testing.TestingT, region string, executionArn string) []StepFunctionHistoryEvent {
+	events, err := GetStepFunctionExecutionHistoryE(t, region, executionArn)
+	require.NoError(t, err)
+	return events
+}
+
+// GetStepFunctionExecutionHistoryE returns all the events in the history of the given Step Functions execution, oldest
+// first.
+func GetStepFunctionExecutionHistoryE(t testing.TestingT, region string, executionArn string) ([]StepFunctionHistoryEvent, error) {
+	client, err := NewSfnClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := getStepFunctionExecutionEventsE(client, executionArn)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]StepFunctionHistoryEvent, 0, len(events))
+	for _, event := range events {
+		history = append(history, newStepFunctionHistoryEvent(event))
+	}
+	return history, nil
+}
+
+// newStepFunctionHistoryEvent converts the given history event returned by the Step Functions API into a
+// StepFunctionHistoryEvent.
+func newStepFunctionHistoryEvent(event types.HistoryEvent) StepFunctionHistoryEvent {
+	result := StepFunctionHistoryEvent{
+		Id:              event.Id,
+		PreviousEventId: event.PreviousEventId,
+		Timestamp:       aws.ToTime(event.Timestamp),
+		Type:            event.Type,
+	}
+
+	switch {
+	case event.ExecutionStartedEventDetails != nil:
+		result.Input = aws.ToString(event.ExecutionStartedEventDetails.Input)
+	case event.ExecutionSucceededEventDetails != nil:
+		result.Output = aws.ToString(event.ExecutionSucceededEventDetails.Output)
+	case event.ExecutionFailedEventDetails != nil:
+		result.Error = aws.ToString(event.ExecutionFailedEventDetails.Error)
+		result.Cause = aws.ToString(event.ExecutionFailedEventDetails.Cause)
+	case event.ExecutionTimedOutEventDetails != nil:
+		result.Error = aws.ToString(event.ExecutionTimedOutEventDetails.Error)
+		result.Cause = aws.ToString(event.ExecutionTimedOutEventDetails.Cause)
+	case event.ExecutionAbortedEventDetails != nil:
+		result.Error = aws.ToString(event.ExecutionAbortedEventDetails.Error)
+		result.Cause = aws.ToString(event.ExecutionAbortedEventDetails.Cause)
+	case event.StateEnteredEventDetails != nil:
+		result.StateName = aws.ToString(event.StateEnteredEventDetails.Name)
+		result.Input = aws.ToString(event.StateEnteredEventDetails.Input)
+	case event.StateExitedEventDetails != nil:
+		result.StateName = aws.ToString(event.StateExitedEventDetails.Name)
+		result.Output = aws.ToString(event.StateExitedEventDetails.Output)
+	case event.TaskScheduledEventDetails != nil:
+		result.Resource = aws.ToString(event.TaskScheduledEventDetails.Resource)
+		result.Input = aws.ToString(event.TaskScheduledEventDetails.Parameters)
+	case event.TaskSucceededEventDetails != nil:
+		result.Resource = aws.ToString(event.TaskSucceededEventDetails.Resource)
+		result.Output = aws.ToString(event.TaskSucceededEventDetails.Output)
+	case event.TaskFailedEventDetails != nil:
+		result.Resource = aws.ToString(event.TaskFailedEventDetails.Resource)
+		result.Error = aws.ToString(event.TaskFailedEventDetails.Error)
+		result.Cause = aws.ToString(event.TaskFailedEventDetails.Cause)
+	case event.TaskTimedOutEventDetails != nil:
+		result.Resource = aws.ToString(event.TaskTimedOutEventDetails.Resource)
+		result.Error = aws.ToString(event.TaskTimedOutEventDetails.Error)
+		result.Cause = aws.ToString(event.TaskTimedOutEventDetails.Cause)
+	case event.LambdaFunctionScheduledEventDetails != nil:
+		result.Resource = aws.ToString(event.LambdaFunctionScheduledEventDetails.Resource)
+		result.Input = aws.ToString(event.LambdaFunctionScheduledEventDetails.Input)
+	case event.LambdaFunctionSucceededEventDetails != nil:
+		result.Output = aws.ToString(event.LambdaFunctionSucceededEventDetails.Output)
+	case event.LambdaFunctionFailedEventDetails != nil:
+		result.Error = aws.ToString(event.LambdaFunctionFailedEventDetails.Error)
+		result.Cause = aws.ToString(event.LambdaFunctionFailedEventDetails.Cause)
+	case event.LambdaFunctionTimedOutEventDetails != nil:
+		result.Error = aws.ToString(event.LambdaFunctionTimedOutEventDetails.Error)
+		result.Cause = aws.ToString(event.LambdaFunctionTimedOutEventDetails.Cause)
+	case event.ActivityScheduledEventDetails != nil:
+		result.Resource = aws.ToString(event.ActivityScheduledEventDetails.Resource)
+		result.Input = aws.ToString(event.ActivityScheduledEventDetails.Input)
+	case event.ActivitySucceededEventDetails != nil:
+		result.Output = aws.ToString(event.ActivitySucceededEventDetails.Output)
+	case event.ActivityFailedEventDetails != nil:
+		result.Error = aws.ToString(event.ActivityFailedEventDetails.Error)
+		result.Cause = aws.ToString(event.ActivityFailedEventDetails.Cause)
+	case event.ActivityTimedOutEventDetails != nil:
+		result.Error = aws.ToString(event.ActivityTimedOutEventDetails.Error)
+		result.Cause = aws.ToString(event.ActivityTimedOutEventDetails.Cause)
+	}
+
+	return result
+}
+
+// getStepFunctionExecutionFailureE reads the history of the given execution and returns the error and cause of the
+// event that ended it.
+func getStepFunctionExecutionFailureE(client *sfn.Client, executionArn string) (string, string, error) {
+	events, err := getStepFunctionExecutionEventsE(client, executionArn)
+	if err != nil {
+		return "", "", err
+	}
+
+	errorName, cause := findStepFunctionExecutionFailure(events)
+	return errorName, cause, nil
+}
+
+// getStepFunctionExecutionEventsE returns all the events in the history of the given execution, oldest first.
+func getStepFunctionExecutionEventsE(client *sfn.Client, executionArn string) ([]types.HistoryEvent, error) {
+	var events []types.HistoryEvent
+
+	paginator := sfn.NewGetExecutionHistoryPaginator(client, &sfn.GetExecutionHistoryInput{
+		ExecutionArn: aws.String(executionArn),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, page.Events...)
+	}
+	return events, nil
+}
+
+// findStepFunctionExecutionFailure returns the error and cause of the last event in the given history that ended the
+// execution unsuccessfully.
+func findStepFunctionExecutionFailure(events []types.HistoryEvent) (string, string) {
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		switch {
+		case event.ExecutionFailedEventDetails != nil:
+			return aws.ToString(event.ExecutionFailedEventDetails.Error), aws.ToString(event.ExecutionFailedEventDetails.Cause)
+		case event.ExecutionTimedOutEventDetails != nil:
+			return aws.ToString(event.ExecutionTimedOutEventDetails.Error), aws.ToString(event.ExecutionTimedOutEventDetails.Cause)
+		case event.ExecutionAbortedEventDetails != nil:
+			return aws.ToString(event.ExecutionAbortedEventDetails.Error), aws.ToString(event.ExecutionAbortedEventDetails.Cause)
+		}
+	}
+	return "", ""
+}
+
+// NewSfnClient creates a new Step Functions client.
+func NewSfnClient(t testing.TestingT, region string) *sfn.Client {
+	client, err := NewSfnClientE(t, region)
+	require.NoError(t, err)
+	return client
+}
+
+// NewSfnClientE creates a new Step Functions client.
+func NewSfnClientE(t testing.TestingT, region string) (*sfn.Client, error) {
+	sess, err := newAuthenticatedSession(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return sfn.NewFromConfig(*sess), nil
+}
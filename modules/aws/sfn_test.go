@@ -0,0 +1,123 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindStepFunctionExecutionFailure(t *testing.T) {
+	t.Parallel()
+
+	events := []types.HistoryEvent{
+		{Type: types.HistoryEventTypeExecutionStarted},
+		{Type: types.HistoryEventTypeTaskStateEntered},
+		{
+			Type: types.HistoryEventTypeExecutionFailed,
+			ExecutionFailedEventDetails: &types.ExecutionFailedEventDetails{
+				Error: aws.String("States.TaskFailed"),
+				Cause: aws.String("Lambda returned an error"),
+			},
+		},
+	}
+
+	errorName, cause := findStepFunctionExecutionFailure(events)
+	require.Equal(t, "States.TaskFailed", errorName)
+	require.Equal(t, "Lambda returned an error", cause)
+}
+
+func TestFindStepFunctionExecutionFailureTimedOut(t *testing.T) {
+	t.Parallel()
+
+	events := []types.HistoryEvent{
+		{Type: types.HistoryEventTypeExecutionStarted},
+		{
+			Type: types.HistoryEventTypeExecutionTimedOut,
+			ExecutionTimedOutEventDetails: &types.ExecutionTimedOutEventDetails{
+				Error: aws.String("States.Timeout"),
+			},
+		},
+	}
+
+	errorName, cause := findStepFunctionExecutionFailure(events)
+	require.Equal(t, "States.Timeout", errorName)
+	require.Empty(t, cause)
+}
+
+func TestNewStepFunctionHistoryEvent(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	event := newStepFunctionHistoryEvent(types.HistoryEvent{
+		Id:              3,
+		PreviousEventId: 2,
+		Timestamp:       aws.Time(timestamp),
+		Type:            types.HistoryEventTypeTaskStateExited,
+		StateExitedEventDetails: &types.StateExitedEventDetails{
+			Name:   aws.String("ProcessOrder"),
+			Output: aws.String(`{"status":"done"}`),
+		},
+	})
+
+	require.Equal(t, StepFunctionHistoryEvent{
+		Id:              3,
+		PreviousEventId: 2,
+		Timestamp:       timestamp,
+		Type:            types.HistoryEventTypeTaskStateExited,
+		StateName:       "ProcessOrder",
+		Output:          `{"status":"done"}`,
+	}, event)
+}
+
+func TestNewStepFunctionHistoryEventTaskFailed(t *testing.T) {
+	t.Parallel()
+
+	event := newStepFunctionHistoryEvent(types.HistoryEvent{
+		Id:   5,
+		Type: types.HistoryEventTypeTaskFailed,
+		TaskFailedEventDetails: &types.TaskFailedEventDetails{
+			Resource: aws.String("invoke"),
+			Error:    aws.String("Lambda.Unknown"),
+			Cause:    aws.String("out of memory"),
+		},
+	})
+
+	require.Equal(t, "invoke", event.Resource)
+	require.Equal(t, "Lambda.Unknown", event.Error)
+	require.Equal(t, "out of memory", event.Cause)
+	require.Empty(t, event.StateName)
+}
+
+func TestCheckStepFunctionExecutionSucceeded(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, checkStepFunctionExecutionSucceeded("arn", &StepFunctionExecutionResult{Status: types.ExecutionStatusSucceeded}))
+
+	err := checkStepFunctionExecutionSucceeded("arn", &StepFunctionExecutionResult{
+		Status: types.ExecutionStatusFailed,
+		Error:  "States.TaskFailed",
+		Cause:  "boom",
+	})
+	require.Equal(t, StepFunctionExecutionFailedError{ExecutionArn: "arn", Status: "FAILED", ErrorName: "States.TaskFailed", Cause: "boom"}, err)
+}
+
+func TestCheckStepFunctionExecutionOutput(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, checkStepFunctionExecutionOutput("arn", `{"a": 1, "b": [true, "x"]}`, `{"b":[true,"x"],"a":1}`))
+
+	err := checkStepFunctionExecutionOutput("arn", `{"a": 1}`, `{"a": 2}`)
+	require.IsType(t, UnexpectedStepFunctionExecutionOutput{}, err)
+
+	require.Error(t, checkStepFunctionExecutionOutput("arn", `{"a": 1}`, `not json`))
+}
+
+func TestStepFunctionRetriesForTimeout(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, 1, stepFunctionRetriesForTimeout(time.Second))
+	require.Equal(t, 30, stepFunctionRetriesForTimeout(time.Minute))
+}
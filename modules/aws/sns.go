@@ -2,9 +2,16 @@ package aws
 
 import (
 	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/google/uuid"
 	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
@@ -64,6 +71,255 @@ func DeleteSNSTopicE(t testing.TestingT, region string, snsTopicArn string) erro
 	return err
 }
 
+// PublishSnsMessage publishes the given message with the given string message attributes to the given SNS topic and
+// returns the ID of the message.
+func PublishSnsMessage(t testing.TestingT, region string, snsTopicArn string, message string, attributes map[string]string) string {
+	messageID, err := PublishSnsMessageE(t, region, snsTopicArn, message, attributes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return messageID
+}
+
+// PublishSnsMessageE publishes the given message with the given string message attributes to the given SNS topic and
+// returns the ID of the message. Message attributes are what subscription filter policies match on by default. If the
+// topic is a FIFO topic, the message is published in a message group named terratest with a random deduplication ID.
+func PublishSnsMessageE(t testing.TestingT, region string, snsTopicArn string, message string, attributes map[string]string) (string, error) {
+	logger.Default.Logf(t, "Publishing message %s to SNS topic %s", message, snsTopicArn)
+
+	snsClient, err := NewSnsClientE(t, region)
+	if err != nil {
+		return "", err
+	}
+
+	output, err := snsClient.Publish(context.Background(), newSnsPublishInput(snsTopicArn, message, attributes))
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(output.MessageId), nil
+}
+
+// newSnsPublishInput returns the input to publish the given message with the given string message attributes to the
+// given SNS topic, with a message group and deduplication ID if the topic is a FIFO topic.
+func newSnsPublishInput(snsTopicArn string, message string, attributes map[string]string) *sns.PublishInput {
+	input := &sns.PublishInput{
+		TopicArn: aws.String(snsTopicArn),
+		Message:  aws.String(message),
+	}
+	if len(attributes) > 0 {
+		input.MessageAttributes = map[string]snstypes.MessageAttributeValue{}
+		for name, value := range attributes {
+			input.MessageAttributes[name] = snstypes.MessageAttributeValue{
+				DataType:    aws.String("String"),
+				StringValue: aws.String(value),
+			}
+		}
+	}
+	if strings.HasSuffix(snsTopicArn, ".fifo") {
+		input.MessageGroupId = aws.String("terratest")
+		input.MessageDeduplicationId = aws.String(uuid.NewString())
+	}
+	return input
+}
+
+// AssertSnsToSqsDelivery publishes the given message to the given SNS topic and checks that it is delivered to the SQS
+// queue with the given URL within the given timeout, failing the test if it is not.
+func AssertSnsToSqsDelivery(t testing.TestingT, region string, snsTopicArn string, queueURL string, message string, timeout time.Duration) {
+	err := AssertSnsToSqsDeliveryE(t, region, snsTopicArn, queueURL, message, timeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// AssertSnsToSqsDeliveryE publishes the given message to the given SNS topic and checks that it is delivered to the
+// SQS queue with the given URL within the given timeout. Both raw and JSON-wrapped deliveries are recognized. The
+// delivered message is deleted from the queue. Any other messages received while waiting are kept invisible until the
+// search is over, so that they are not received (and counted towards the maxReceiveCount of the queue) again and
+// again, and are then made visible again for their consumers. Returns an SnsMessageNotDelivered error if the message
+// does not arrive in time, which usually means the subscription, its filter policy or the queue policy is not set up
+// correctly.
+func AssertSnsToSqsDeliveryE(t testing.TestingT, region string, snsTopicArn string, queueURL string, message string, timeout time.Duration) error {
+	sqsClient, err := NewSqsClientE(t, region)
+	if err != nil {
+		return err
+	}
+	if _, err := PublishSnsMessageE(t, region, snsTopicArn, message, nil); err != nil {
+		return err
+	}
+
+	delivered, others, err := receiveSnsMessageFromQueueE(t, sqsClient, queueURL, message, time.Now().Add(timeout))
+	releaseErr := releaseQueueMessagesE(sqsClient, queueURL, others)
+	if err != nil {
+		return err
+	}
+	if releaseErr != nil {
+		return releaseErr
+	}
+	if delivered == nil {
+		return SnsMessageNotDelivered{TopicArn: snsTopicArn, QueueUrl: queueURL, Timeout: timeout}
+	}
+
+	logger.Default.Logf(t, "Message published to SNS topic %s was delivered to queue %s", snsTopicArn, queueURL)
+	return DeleteMessageFromQueueE(t, region, queueURL, delivered.ReceiptHandle)
+}
+
+// receiveSnsMessageFromQueueE receives messages from the SQS queue with the given URL until the given message
+// published to SNS is received or the given deadline passes, and returns the message, if it was received, and the
+// other messages that were received. The other messages are kept invisible until the deadline, so that they are not
+// received again while searching; the caller has to release them.
+func receiveSnsMessageFromQueueE(t testing.TestingT, sqsClient *sqs.Client, queueURL string, message string, deadline time.Time) (*QueueMessage, []QueueMessage, error) {
+	var others []QueueMessage
+	for time.Now().Before(deadline) {
+		messages, err := waitForQueueMessagesE(t, sqsClient, queueURL, 10, time.Until(deadline))
+		if err != nil {
+			if _, isTimeout := err.(ReceiveMessageTimeout); isTimeout {
+				break
+			}
+			return nil, others, err
+		}
+
+		var delivered *QueueMessage
+		var received []QueueMessage
+		for i := range messages {
+			if delivered == nil && snsMessageMatches(messages[i].Body, message) {
+				delivered = &messages[i]
+			} else {
+				received = append(received, messages[i])
+			}
+		}
+		others = append(others, received...)
+		if delivered != nil {
+			return delivered, others, nil
+		}
+		if err := changeQueueMessagesVisibilityE(sqsClient, queueURL, received, time.Until(deadline)); err != nil {
+			return nil, others, err
+		}
+	}
+	return nil, others, nil
+}
+
+// snsMessageMatches returns true if the given body of an SQS message is the given message published to SNS, either
+// delivered raw or wrapped in the JSON envelope SNS uses when raw message delivery is disabled.
+func snsMessageMatches(body string, message string) bool {
+	if body == message {
+		return true
+	}
+
+	var envelope struct {
+		Type    string
+		Message string
+	}
+	if err := json.Unmarshal([]byte(body), &envelope); err != nil {
+		return false
+	}
+	return envelope.Type == "Notification" && envelope.Message == message
+}
+
+// GetSnsSubscriptionArn returns the ARN of the subscription of the given endpoint, such as the ARN of an SQS queue or
+// Lambda function, to the given SNS topic.
+func GetSnsSubscriptionArn(t testing.TestingT, region string, snsTopicArn string, endpoint string) string {
+	subscriptionArn, err := GetSnsSubscriptionArnE(t, region, snsTopicArn, endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return subscriptionArn
+}
+
+// GetSnsSubscriptionArnE returns the ARN of the subscription of the given endpoint, such as the ARN of an SQS queue or
+// Lambda function, to the given SNS topic. Returns a NotFoundError if the endpoint is not subscribed to the topic.
+func GetSnsSubscriptionArnE(t testing.TestingT, region string, snsTopicArn string, endpoint string) (string, error) {
+	snsClient, err := NewSnsClientE(t, region)
+	if err != nil {
+		return "", err
+	}
+
+	paginator := sns.NewListSubscriptionsByTopicPaginator(snsClient, &sns.ListSubscriptionsByTopicInput{
+		TopicArn: aws.String(snsTopicArn),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return "", err
+		}
+		for _, subscription := range page.Subscriptions {
+			if aws.ToString(subscription.Endpoint) == endpoint {
+				return aws.ToString(subscription.SubscriptionArn), nil
+			}
+		}
+	}
+	return "", NewNotFoundError("SNS subscription", endpoint+" to "+snsTopicArn, region)
+}
+
+// GetSnsSubscriptionFilterPolicy returns the filter policy of the given SNS subscription.
+func GetSnsSubscriptionFilterPolicy(t testing.TestingT, region string, subscriptionArn string) string {
+	filterPolicy, err := GetSnsSubscriptionFilterPolicyE(t, region, subscriptionArn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filterPolicy
+}
+
+// GetSnsSubscriptionFilterPolicyE returns the filter policy of the given SNS subscription, or an empty string if the
+// subscription has no filter policy.
+func GetSnsSubscriptionFilterPolicyE(t testing.TestingT, region string, subscriptionArn string) (string, error) {
+	snsClient, err := NewSnsClientE(t, region)
+	if err != nil {
+		return "", err
+	}
+
+	output, err := snsClient.GetSubscriptionAttributes(context.Background(), &sns.GetSubscriptionAttributesInput{
+		SubscriptionArn: aws.String(subscriptionArn),
+	})
+	if err != nil {
+		return "", err
+	}
+	return output.Attributes["FilterPolicy"], nil
+}
+
+// AssertSnsSubscriptionFilterPolicy checks that the filter policy of the given SNS subscription is equal, as JSON, to
+// the given expected policy, and fails the test if it is not.
+func AssertSnsSubscriptionFilterPolicy(t testing.TestingT, region string, subscriptionArn string, expectedPolicy string) {
+	err := AssertSnsSubscriptionFilterPolicyE(t, region, subscriptionArn, expectedPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// AssertSnsSubscriptionFilterPolicyE checks that the filter policy of the given SNS subscription is equal, as JSON, to
+// the given expected policy. The formatting of the JSON and the order of object keys are ignored. Returns an
+// UnexpectedSnsFilterPolicy error if the policy is different.
+func AssertSnsSubscriptionFilterPolicyE(t testing.TestingT, region string, subscriptionArn string, expectedPolicy string) error {
+	filterPolicy, err := GetSnsSubscriptionFilterPolicyE(t, region, subscriptionArn)
+	if err != nil {
+		return err
+	}
+	return checkSnsFilterPolicy(subscriptionArn, filterPolicy, expectedPolicy)
+}
+
+// checkSnsFilterPolicy returns an UnexpectedSnsFilterPolicy error if the given actual and expected filter policies of
+// the given subscription are not equal as JSON.
+func checkSnsFilterPolicy(subscriptionArn string, actualPolicy string, expectedPolicy string) error {
+	var actual, expected interface{}
+	if actualPolicy != "" {
+		if err := json.Unmarshal([]byte(actualPolicy), &actual); err != nil {
+			return err
+		}
+	}
+	if expectedPolicy != "" {
+		if err := json.Unmarshal([]byte(expectedPolicy), &expected); err != nil {
+			return err
+		}
+	}
+	if reflect.DeepEqual(actual, expected) {
+		return nil
+	}
+	return UnexpectedSnsFilterPolicy{
+		SubscriptionArn: subscriptionArn,
+		ExpectedPolicy:  expectedPolicy,
+		ActualPolicy:    actualPolicy,
+	}
+}
+
 // NewSnsClient creates a new SNS client.
 func NewSnsClient(t testing.TestingT, region string) *sns.Client {
 	client, err := NewSnsClientE(t, region)
@@ -75,7 +331,7 @@ func NewSnsClient(t testing.TestingT, region string) *sns.Client {
 
 // NewSnsClientE creates a new SNS client.
 func NewSnsClientE(t testing.TestingT, region string) (*sns.Client, error) {
-	sess, err := NewAuthenticatedSession(region)
+	sess, err := newAuthenticatedSession(t, region)
 	if err != nil {
 		return nil, err
 	}
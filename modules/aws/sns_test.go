@@ -44,3 +44,36 @@ func deleteTopic(t *testing.T, region string, arn string) {
 	DeleteSNSTopic(t, region, arn)
 	assert.False(t, snsTopicExists(t, region, arn))
 }
+
+func TestNewSnsPublishInput(t *testing.T) {
+	t.Parallel()
+
+	input := newSnsPublishInput("arn:aws:sns:us-east-1:123456789012:orders", "hello", map[string]string{"event": "created"})
+	assert.Equal(t, "hello", aws.ToString(input.Message))
+	assert.Equal(t, "created", aws.ToString(input.MessageAttributes["event"].StringValue))
+	assert.Nil(t, input.MessageGroupId)
+
+	input = newSnsPublishInput("arn:aws:sns:us-east-1:123456789012:orders.fifo", "hello", nil)
+	assert.Nil(t, input.MessageAttributes)
+	assert.Equal(t, "terratest", aws.ToString(input.MessageGroupId))
+	assert.NotEmpty(t, aws.ToString(input.MessageDeduplicationId))
+}
+
+func TestSnsMessageMatches(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, snsMessageMatches("hello", "hello"))
+	assert.True(t, snsMessageMatches(`{"Type": "Notification", "MessageId": "1", "Message": "hello"}`, "hello"))
+	assert.False(t, snsMessageMatches(`{"Type": "Notification", "Message": "goodbye"}`, "hello"))
+	assert.False(t, snsMessageMatches("goodbye", "hello"))
+}
+
+func TestCheckSnsFilterPolicy(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, checkSnsFilterPolicy("sub", `{"event": ["created", "updated"], "store": ["a"]}`, `{"store":["a"],"event":["created","updated"]}`))
+	assert.NoError(t, checkSnsFilterPolicy("sub", "", ""))
+
+	err := checkSnsFilterPolicy("sub", "", `{"event": ["created"]}`)
+	assert.Equal(t, UnexpectedSnsFilterPolicy{SubscriptionArn: "sub", ExpectedPolicy: `{"event": ["created"]}`}, err)
+}
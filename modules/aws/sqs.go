@@ -2,11 +2,14 @@ package aws
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	"github.com/google/uuid"
@@ -255,6 +258,302 @@ func WaitForQueueMessage(t testing.TestingT, awsRegion string, queueURL string,
 	return QueueMessageResponse{Error: ReceiveMessageTimeout{QueueUrl: queueURL, TimeoutSec: timeout}}
 }
 
+// SendMessageToFifoQueueWithDeduplicationID sends the given message to the FIFO SQS queue with the given URL, in the
+// given message group and with the given deduplication ID.
+func SendMessageToFifoQueueWithDeduplicationID(t testing.TestingT, awsRegion string, queueURL string, message string, messageGroupID string, deduplicationID string) string {
+	messageID, err := SendMessageToFifoQueueWithDeduplicationIDE(t, awsRegion, queueURL, message, messageGroupID, deduplicationID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return messageID
+}
+
+// SendMessageToFifoQueueWithDeduplicationIDE sends the given message to the FIFO SQS queue with the given URL, in the
+// given message group and with the given deduplication ID, and returns the ID of the message. SQS drops messages with
+// the same deduplication ID sent within five minutes of each other, which is what tests of deduplication rely on. If
+// the deduplication ID is empty, the queue must have content-based deduplication enabled.
+func SendMessageToFifoQueueWithDeduplicationIDE(t testing.TestingT, awsRegion string, queueURL string, message string, messageGroupID string, deduplicationID string) (string, error) {
+	logger.Default.Logf(t, "Sending message %s to queue %s in message group %s", message, queueURL, messageGroupID)
+
+	sqsClient, err := NewSqsClientE(t, awsRegion)
+	if err != nil {
+		return "", err
+	}
+
+	input := &sqs.SendMessageInput{
+		MessageBody:    aws.String(message),
+		QueueUrl:       aws.String(queueURL),
+		MessageGroupId: aws.String(messageGroupID),
+	}
+	if deduplicationID != "" {
+		input.MessageDeduplicationId = aws.String(deduplicationID)
+	}
+
+	res, err := sqsClient.SendMessage(context.Background(), input)
+	if err != nil {
+		return "", err
+	}
+
+	logger.Default.Logf(t, "Message id %s sent to queue %s", aws.ToString(res.MessageId), queueURL)
+	return aws.ToString(res.MessageId), nil
+}
+
+// QueueMessage is a message received from an SQS queue.
+type QueueMessage struct {
+	MessageID               string            // The ID of the message
+	ReceiptHandle           string            // The handle to delete the message with
+	Body                    string            // The body of the message
+	Attributes              map[string]string // The string and number message attributes of the message
+	ApproximateReceiveCount int               // The number of times the message has been received, including this time
+	MessageGroupID          string            // The message group of the message. Only set for FIFO queues.
+	MessageDeduplicationID  string            // The deduplication ID of the message. Only set for FIFO queues.
+	SequenceNumber          string            // The sequence number of the message in its group. Only set for FIFO queues.
+}
+
+// WaitForQueueMessages waits up to the given timeout for messages on the SQS queue with the given URL and returns up to
+// the given maximum number of messages (at most 10).
+func WaitForQueueMessages(t testing.TestingT, awsRegion string, queueURL string, maxMessages int, timeout time.Duration) []QueueMessage {
+	messages, err := WaitForQueueMessagesE(t, awsRegion, queueURL, maxMessages, timeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return messages
+}
+
+// WaitForQueueMessagesE waits up to the given timeout for messages on the SQS queue with the given URL and returns up
+// to the given maximum number of messages (at most 10), along with their FIFO attributes and receive count. The
+// messages are hidden from other consumers for the visibility timeout of the queue, so delete them with
+// DeleteMessageFromQueueE once processed. Returns a ReceiveMessageTimeout error if no message arrives in time.
+func WaitForQueueMessagesE(t testing.TestingT, awsRegion string, queueURL string, maxMessages int, timeout time.Duration) ([]QueueMessage, error) {
+	sqsClient, err := NewSqsClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+	return waitForQueueMessagesE(t, sqsClient, queueURL, maxMessages, timeout)
+}
+
+// PeekSqsDeadLetterQueueMessages waits up to the given timeout for messages on the dead-letter queue of the SQS queue
+// with the given URL and returns up to the given maximum number of messages (at most 10), without removing them from
+// the dead-letter queue.
+func PeekSqsDeadLetterQueueMessages(t testing.TestingT, awsRegion string, sourceQueueURL string, maxMessages int, timeout time.Duration) []QueueMessage {
+	messages, err := PeekSqsDeadLetterQueueMessagesE(t, awsRegion, sourceQueueURL, maxMessages, timeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return messages
+}
+
+// PeekSqsDeadLetterQueueMessagesE waits up to the given timeout for messages on the dead-letter queue of the SQS queue
+// with the given URL and returns up to the given maximum number of messages (at most 10). The messages are made
+// visible again right after they are received, so they stay available on the dead-letter queue for other consumers and
+// later checks. Returns a SqsRedrivePolicyNotFound error if the queue has no dead-letter queue, and a ReceiveMessageTimeout
+// error if no message arrives in time.
+func PeekSqsDeadLetterQueueMessagesE(t testing.TestingT, awsRegion string, sourceQueueURL string, maxMessages int, timeout time.Duration) ([]QueueMessage, error) {
+	deadLetterQueueURL, err := GetSqsDeadLetterQueueUrlE(t, awsRegion, sourceQueueURL)
+	if err != nil {
+		return nil, err
+	}
+	sqsClient, err := NewSqsClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := waitForQueueMessagesE(t, sqsClient, deadLetterQueueURL, maxMessages, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return messages, releaseQueueMessagesE(sqsClient, deadLetterQueueURL, messages)
+}
+
+// GetSqsQueueApproximateMessageCount returns the approximate number of messages available on the SQS queue with the
+// given URL.
+func GetSqsQueueApproximateMessageCount(t testing.TestingT, awsRegion string, queueURL string) int {
+	count, err := GetSqsQueueApproximateMessageCountE(t, awsRegion, queueURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+// GetSqsQueueApproximateMessageCountE returns the approximate number of messages available on the SQS queue with the
+// given URL. Messages that are in flight (received but not yet deleted) or delayed are not counted.
+func GetSqsQueueApproximateMessageCountE(t testing.TestingT, awsRegion string, queueURL string) (int, error) {
+	sqsClient, err := NewSqsClientE(t, awsRegion)
+	if err != nil {
+		return 0, err
+	}
+
+	attributes, err := sqsClient.GetQueueAttributes(context.Background(), &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(queueURL),
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameApproximateNumberOfMessages},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(attributes.Attributes[string(types.QueueAttributeNameApproximateNumberOfMessages)])
+}
+
+// waitForQueueMessagesE long polls the queue with the given URL until at least one message is received or the given
+// timeout expires, and returns the received messages.
+func waitForQueueMessagesE(t testing.TestingT, sqsClient *sqs.Client, queueURL string, maxMessages int, timeout time.Duration) ([]QueueMessage, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		waitTime := time.Until(deadline)
+		if waitTime > 20*time.Second {
+			waitTime = 20 * time.Second
+		}
+		if waitTime < time.Second {
+			waitTime = time.Second
+		}
+
+		logger.Default.Logf(t, "Waiting for messages on %s", queueURL)
+		result, err := sqsClient.ReceiveMessage(context.Background(), &sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(queueURL),
+			MaxNumberOfMessages: int32(maxMessages),
+			WaitTimeSeconds:     int32(waitTime / time.Second),
+			MessageSystemAttributeNames: []types.MessageSystemAttributeName{
+				types.MessageSystemAttributeNameApproximateReceiveCount,
+				types.MessageSystemAttributeNameMessageGroupId,
+				types.MessageSystemAttributeNameMessageDeduplicationId,
+				types.MessageSystemAttributeNameSequenceNumber,
+			},
+			MessageAttributeNames: []string{"All"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(result.Messages) > 0 {
+			logger.Default.Logf(t, "Received %d messages on %s", len(result.Messages), queueURL)
+			return newQueueMessages(result.Messages), nil
+		}
+
+		if !time.Now().Before(deadline) {
+			return nil, ReceiveMessageTimeout{QueueUrl: queueURL, TimeoutSec: int(timeout / time.Second)}
+		}
+	}
+}
+
+// releaseQueueMessagesE makes the given received messages visible again on the queue with the given URL, so that they
+// can be received again right away instead of after the visibility timeout of the queue.
+func releaseQueueMessagesE(sqsClient *sqs.Client, queueURL string, messages []QueueMessage) error {
+	return changeQueueMessagesVisibilityE(sqsClient, queueURL, messages, 0)
+}
+
+// maxSqsVisibilityTimeout is the longest that SQS allows a received message to stay invisible.
+const maxSqsVisibilityTimeout = 12 * time.Hour
+
+// changeQueueMessagesVisibilityE keeps the given received messages invisible on the queue with the given URL for the
+// given duration from now (rounded up to a second), or makes them visible again right away if the duration is 0.
+func changeQueueMessagesVisibilityE(sqsClient *sqs.Client, queueURL string, messages []QueueMessage, visibilityTimeout time.Duration) error {
+	if visibilityTimeout > maxSqsVisibilityTimeout {
+		visibilityTimeout = maxSqsVisibilityTimeout
+	}
+	for _, message := range messages {
+		_, err := sqsClient.ChangeMessageVisibility(context.Background(), &sqs.ChangeMessageVisibilityInput{
+			QueueUrl:          aws.String(queueURL),
+			ReceiptHandle:     aws.String(message.ReceiptHandle),
+			VisibilityTimeout: int32((visibilityTimeout + time.Second - 1) / time.Second),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newQueueMessages converts the given messages returned by the SQS API into QueueMessages.
+func newQueueMessages(messages []types.Message) []QueueMessage {
+	result := make([]QueueMessage, 0, len(messages))
+	for _, message := range messages {
+		attributes := map[string]string{}
+		for name, value := range message.MessageAttributes {
+			if value.StringValue != nil {
+				attributes[name] = aws.ToString(value.StringValue)
+			}
+		}
+
+		receiveCount, _ := strconv.Atoi(message.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)])
+		result = append(result, QueueMessage{
+			MessageID:               aws.ToString(message.MessageId),
+			ReceiptHandle:           aws.ToString(message.ReceiptHandle),
+			Body:                    aws.ToString(message.Body),
+			Attributes:              attributes,
+			ApproximateReceiveCount: receiveCount,
+			MessageGroupID:          message.Attributes[string(types.MessageSystemAttributeNameMessageGroupId)],
+			MessageDeduplicationID:  message.Attributes[string(types.MessageSystemAttributeNameMessageDeduplicationId)],
+			SequenceNumber:          message.Attributes[string(types.MessageSystemAttributeNameSequenceNumber)],
+		})
+	}
+	return result
+}
+
+// GetSqsDeadLetterQueueUrl returns the URL of the dead-letter queue that the SQS queue with the given URL redrives its
+// failed messages to.
+func GetSqsDeadLetterQueueUrl(t testing.TestingT, awsRegion string, sourceQueueURL string) string {
+	url, err := GetSqsDeadLetterQueueUrlE(t, awsRegion, sourceQueueURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return url
+}
+
+// GetSqsDeadLetterQueueUrlE returns the URL of the dead-letter queue that the SQS queue with the given URL redrives its
+// failed messages to. Returns a SqsRedrivePolicyNotFound error if the queue has no redrive policy.
+func GetSqsDeadLetterQueueUrlE(t testing.TestingT, awsRegion string, sourceQueueURL string) (string, error) {
+	sqsClient, err := NewSqsClientE(t, awsRegion)
+	if err != nil {
+		return "", err
+	}
+
+	attributes, err := sqsClient.GetQueueAttributes(context.Background(), &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(sourceQueueURL),
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameRedrivePolicy},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	deadLetterQueueArn, err := parseSqsRedrivePolicy(sourceQueueURL, attributes.Attributes[string(types.QueueAttributeNameRedrivePolicy)])
+	if err != nil {
+		return "", err
+	}
+
+	// The queue name is the last part of the ARN (arn:aws:sqs:<region>:<account-id>:<queue-name>)
+	result, err := sqsClient.GetQueueUrl(context.Background(), &sqs.GetQueueUrlInput{
+		QueueName:              aws.String(deadLetterQueueArn.Resource),
+		QueueOwnerAWSAccountId: aws.String(deadLetterQueueArn.AccountID),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	url := aws.ToString(result.QueueUrl)
+	logger.Default.Logf(t, "Queue %s redrives failed messages to dead-letter queue %s", sourceQueueURL, url)
+	return url, nil
+}
+
+// sqsRedrivePolicy is the JSON document stored in the RedrivePolicy attribute of an SQS queue.
+type sqsRedrivePolicy struct {
+	DeadLetterTargetArn string `json:"deadLetterTargetArn"`
+}
+
+// parseSqsRedrivePolicy extracts the ARN of the dead-letter queue from the given RedrivePolicy attribute of the SQS
+// queue with the given URL.
+func parseSqsRedrivePolicy(queueURL string, redrivePolicy string) (arn.ARN, error) {
+	if redrivePolicy == "" {
+		return arn.ARN{}, SqsRedrivePolicyNotFound{QueueUrl: queueURL}
+	}
+
+	var policy sqsRedrivePolicy
+	if err := json.Unmarshal([]byte(redrivePolicy), &policy); err != nil {
+		return arn.ARN{}, fmt.Errorf("failed to parse redrive policy of queue %s: %w", queueURL, err)
+	}
+	if policy.DeadLetterTargetArn == "" {
+		return arn.ARN{}, SqsRedrivePolicyNotFound{QueueUrl: queueURL}
+	}
+
+	return arn.Parse(policy.DeadLetterTargetArn)
+}
+
 // NewSqsClient creates a new SQS client.
 func NewSqsClient(t testing.TestingT, region string) *sqs.Client {
 	client, err := NewSqsClientE(t, region)
@@ -266,7 +565,7 @@ func NewSqsClient(t testing.TestingT, region string) *sqs.Client {
 
 // NewSqsClientE creates a new SQS client.
 func NewSqsClientE(t testing.TestingT, region string) (*sqs.Client, error) {
-	sess, err := NewAuthenticatedSession(region)
+	sess, err := newAuthenticatedSession(t, region)
 	if err != nil {
 		return nil, err
 	}
@@ -283,3 +582,12 @@ type ReceiveMessageTimeout struct {
 func (err ReceiveMessageTimeout) Error() string {
 	return fmt.Sprintf("Failed to receive messages on %s within %s seconds", err.QueueUrl, strconv.Itoa(err.TimeoutSec))
 }
+
+// SqsRedrivePolicyNotFound is an error that occurs if an SQS queue has no dead-letter queue configured.
+type SqsRedrivePolicyNotFound struct {
+	QueueUrl string
+}
+
+func (err SqsRedrivePolicyNotFound) Error() string {
+	return fmt.Sprintf("Queue %s has no redrive policy, so it has no dead-letter queue", err.QueueUrl)
+}
@@ -8,8 +8,10 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSqsQueueMethods(t *testing.T) {
@@ -67,6 +69,60 @@ func TestFifoSqsQueueMethods(t *testing.T) {
 	assert.Error(t, secondResponse.Error, ReceiveMessageTimeout{QueueUrl: url, TimeoutSec: timeoutSec})
 }
 
+func TestGetSqsDeadLetterQueueUrl(t *testing.T) {
+	t.Parallel()
+
+	region := GetRandomStableRegion(t, nil, nil)
+	namePrefix := fmt.Sprintf("sqs-dlq-test-%s", random.UniqueId())
+
+	deadLetterQueueURL := CreateRandomQueue(t, region, namePrefix+"-dlq")
+	defer deleteQueue(t, region, deadLetterQueueURL)
+	sourceQueueURL := CreateRandomQueue(t, region, namePrefix)
+	defer deleteQueue(t, region, sourceQueueURL)
+
+	_, err := GetSqsDeadLetterQueueUrlE(t, region, sourceQueueURL)
+	require.Error(t, err)
+	assert.Equal(t, SqsRedrivePolicyNotFound{QueueUrl: sourceQueueURL}, err)
+
+	sqsClient := NewSqsClient(t, region)
+	deadLetterQueueAttributes, err := sqsClient.GetQueueAttributes(context.Background(), &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(deadLetterQueueURL),
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameQueueArn},
+	})
+	require.NoError(t, err)
+	deadLetterQueueArn := deadLetterQueueAttributes.Attributes[string(types.QueueAttributeNameQueueArn)]
+
+	_, err = sqsClient.SetQueueAttributes(context.Background(), &sqs.SetQueueAttributesInput{
+		QueueUrl: aws.String(sourceQueueURL),
+		Attributes: map[string]string{
+			string(types.QueueAttributeNameRedrivePolicy): fmt.Sprintf(`{"deadLetterTargetArn":"%s","maxReceiveCount":"1"}`, deadLetterQueueArn),
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, deadLetterQueueURL, GetSqsDeadLetterQueueUrl(t, region, sourceQueueURL))
+}
+
+func TestParseSqsRedrivePolicy(t *testing.T) {
+	t.Parallel()
+
+	queueURL := "https://sqs.us-east-1.amazonaws.com/123456789012/source"
+
+	deadLetterQueueArn, err := parseSqsRedrivePolicy(queueURL, `{"deadLetterTargetArn":"arn:aws:sqs:us-east-1:123456789012:source-dlq","maxReceiveCount":5}`)
+	require.NoError(t, err)
+	assert.Equal(t, "123456789012", deadLetterQueueArn.AccountID)
+	assert.Equal(t, "source-dlq", deadLetterQueueArn.Resource)
+
+	_, err = parseSqsRedrivePolicy(queueURL, "")
+	assert.Equal(t, SqsRedrivePolicyNotFound{QueueUrl: queueURL}, err)
+
+	_, err = parseSqsRedrivePolicy(queueURL, `{"maxReceiveCount":5}`)
+	assert.Equal(t, SqsRedrivePolicyNotFound{QueueUrl: queueURL}, err)
+
+	_, err = parseSqsRedrivePolicy(queueURL, "not json")
+	assert.Error(t, err)
+}
+
 func queueExists(t *testing.T, region string, url string) bool {
 	sqsClient := NewSqsClient(t, region)
 
@@ -86,3 +142,37 @@ func deleteQueue(t *testing.T, region string, url string) {
 	DeleteQueue(t, region, url)
 	assert.False(t, queueExists(t, region, url))
 }
+
+func TestNewQueueMessages(t *testing.T) {
+	t.Parallel()
+
+	messages := newQueueMessages([]types.Message{
+		{
+			MessageId:     aws.String("1"),
+			ReceiptHandle: aws.String("receipt"),
+			Body:          aws.String("hello"),
+			Attributes: map[string]string{
+				"ApproximateReceiveCount": "3",
+				"MessageGroupId":          "orders",
+				"MessageDeduplicationId":  "dedup",
+				"SequenceNumber":          "18849496460467696128",
+			},
+			MessageAttributes: map[string]types.MessageAttributeValue{
+				"event": {DataType: aws.String("String"), StringValue: aws.String("created")},
+				"blob":  {DataType: aws.String("Binary"), BinaryValue: []byte("data")},
+			},
+		},
+	})
+
+	require.Len(t, messages, 1)
+	assert.Equal(t, QueueMessage{
+		MessageID:               "1",
+		ReceiptHandle:           "receipt",
+		Body:                    "hello",
+		Attributes:              map[string]string{"event": "created"},
+		ApproximateReceiveCount: 3,
+		MessageGroupID:          "orders",
+		MessageDeduplicationID:  "dedup",
+		SequenceNumber:          "18849496460467696128",
+	}, messages[0])
+}
@@ -2,8 +2,12 @@ package aws
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -15,6 +19,15 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// SsmParameter contains the value and metadata of an SSM parameter.
+type SsmParameter struct {
+	Name             string              // The name of the parameter
+	Type             types.ParameterType // The type of the parameter (String, StringList or SecureString)
+	Value            string              // The value of the parameter, decrypted if it is a SecureString
+	Version          int64               // The version of the parameter
+	LastModifiedDate time.Time           // The date the parameter was last changed
+}
+
 // GetParameter retrieves the latest version of SSM Parameter at keyName with decryption.
 func GetParameter(t testing.TestingT, awsRegion string, keyName string) string {
 	keyValue, err := GetParameterE(t, awsRegion, keyName)
@@ -33,14 +46,55 @@ func GetParameterE(t testing.TestingT, awsRegion string, keyName string) (string
 }
 
 // GetParameterWithClientE retrieves the latest version of SSM Parameter at keyName with decryption with the ability to provide the SSM client.
+// The values of SecureString parameters are registered with logger.RegisterSecret so that they are redacted from the logs.
 func GetParameterWithClientE(t testing.TestingT, client *ssm.Client, keyName string) (string, error) {
-	resp, err := client.GetParameter(context.Background(), &ssm.GetParameterInput{Name: aws.String(keyName), WithDecryption: aws.Bool(true)})
+	parameter, err := GetParameterDetailsWithClientE(t, client, keyName)
 	if err != nil {
 		return "", err
 	}
+	return parameter.Value, nil
+}
+
+// GetParameterDetails retrieves the latest version of SSM Parameter at keyName with decryption, along with its type and version.
+func GetParameterDetails(t testing.TestingT, awsRegion string, keyName string) *SsmParameter {
+	parameter, err := GetParameterDetailsE(t, awsRegion, keyName)
+	require.NoError(t, err)
+	return parameter
+}
+
+// GetParameterDetailsE retrieves the latest version of SSM Parameter at keyName with decryption, along with its type and version.
+func GetParameterDetailsE(t testing.TestingT, awsRegion string, keyName string) (*SsmParameter, error) {
+	ssmClient, err := NewSsmClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+	return GetParameterDetailsWithClientE(t, ssmClient, keyName)
+}
+
+// GetParameterDetailsWithClientE retrieves the latest version of SSM Parameter at keyName with decryption, along with its type and version, with the ability to provide the SSM client.
+// The values of SecureString parameters are registered with logger.RegisterSecret so that they are redacted from the logs.
+func GetParameterDetailsWithClientE(t testing.TestingT, client *ssm.Client, keyName string) (*SsmParameter, error) {
+	resp, err := client.GetParameter(context.Background(), &ssm.GetParameterInput{Name: aws.String(keyName), WithDecryption: aws.Bool(true)})
+	if err != nil {
+		return nil, err
+	}
+
+	parameter := newSsmParameter(resp.Parameter)
+	if parameter.Type == types.ParameterTypeSecureString {
+		logger.RegisterSecret(parameter.Value)
+	}
+	return parameter, nil
+}
 
-	parameter := *resp.Parameter
-	return *parameter.Value, nil
+// newSsmParameter converts the given parameter returned by the SSM API into an SsmParameter.
+func newSsmParameter(parameter *types.Parameter) *SsmParameter {
+	return &SsmParameter{
+		Name:             aws.ToString(parameter.Name),
+		Type:             parameter.Type,
+		Value:            aws.ToString(parameter.Value),
+		Version:          parameter.Version,
+		LastModifiedDate: aws.ToTime(parameter.LastModifiedDate),
+	}
 }
 
 // PutParameter creates new version of SSM Parameter at keyName with keyValue as SecureString.
@@ -61,11 +115,37 @@ func PutParameterE(t testing.TestingT, awsRegion string, keyName string, keyDesc
 
 // PutParameterWithClientE creates new version of SSM Parameter at keyName with keyValue as SecureString with the ability to provide the SSM client.
 func PutParameterWithClientE(t testing.TestingT, client *ssm.Client, keyName string, keyDescription string, keyValue string) (int64, error) {
+	return PutParameterWithTypeWithClientE(t, client, keyName, keyDescription, keyValue, types.ParameterTypeSecureString)
+}
+
+// PutParameterWithType creates new version of SSM Parameter at keyName with keyValue as the given type (String, StringList or SecureString).
+func PutParameterWithType(t testing.TestingT, awsRegion string, keyName string, keyDescription string, keyValue string, parameterType types.ParameterType) int64 {
+	version, err := PutParameterWithTypeE(t, awsRegion, keyName, keyDescription, keyValue, parameterType)
+	require.NoError(t, err)
+	return version
+}
+
+// PutParameterWithTypeE creates new version of SSM Parameter at keyName with keyValue as the given type (String, StringList or SecureString).
+func PutParameterWithTypeE(t testing.TestingT, awsRegion string, keyName string, keyDescription string, keyValue string, parameterType types.ParameterType) (int64, error) {
+	ssmClient, err := NewSsmClientE(t, awsRegion)
+	if err != nil {
+		return 0, err
+	}
+	return PutParameterWithTypeWithClientE(t, ssmClient, keyName, keyDescription, keyValue, parameterType)
+}
+
+// PutParameterWithTypeWithClientE creates new version of SSM Parameter at keyName with keyValue as the given type with the ability to provide the SSM client.
+// The values of SecureString parameters are registered with logger.RegisterSecret so that they are redacted from the logs.
+func PutParameterWithTypeWithClientE(t testing.TestingT, client *ssm.Client, keyName string, keyDescription string, keyValue string, parameterType types.ParameterType) (int64, error) {
+	if parameterType == types.ParameterTypeSecureString {
+		logger.RegisterSecret(keyValue)
+	}
+
 	resp, err := client.PutParameter(context.Background(), &ssm.PutParameterInput{
 		Name:        aws.String(keyName),
 		Description: aws.String(keyDescription),
 		Value:       aws.String(keyValue),
-		Type:        types.ParameterTypeSecureString,
+		Type:        parameterType,
 	})
 	if err != nil {
 		return 0, err
@@ -108,7 +188,7 @@ func NewSsmClient(t testing.TestingT, region string) *ssm.Client {
 
 // NewSsmClientE creates an SSM client.
 func NewSsmClientE(t testing.TestingT, region string) (*ssm.Client, error) {
-	sess, err := NewAuthenticatedSession(region)
+	sess, err := newAuthenticatedSession(t, region)
 	if err != nil {
 		return nil, err
 	}
@@ -206,10 +286,34 @@ func CheckSsmCommandWithDocumentE(t testing.TestingT, awsRegion, instanceID, com
 
 // CheckSSMCommandWithClientWithDocumentE checks that you can run the given command on the given instance through AWS SSM with the ability to provide the SSM client with specified Command Doc type. Returns the result and an error if one occurs.
 func CheckSSMCommandWithClientWithDocumentE(t testing.TestingT, client *ssm.Client, instanceID, command string, commandDocName string, timeout time.Duration) (*CommandOutput, error) {
+	commandID, err := sendSsmCommandE(client, instanceID, command, commandDocName)
+	if err != nil {
+		return nil, err
+	}
 
-	timeBetweenRetries := 2 * time.Second
-	maxRetries := int(timeout.Seconds() / timeBetweenRetries.Seconds())
+	result := &CommandOutput{}
+	invocation, err := waitForSsmCommandInvocationE(t, client, commandID, instanceID, timeout)
+	if invocation != nil {
+		result.Stderr = aws.ToString(invocation.StandardErrorContent)
+		result.Stdout = aws.ToString(invocation.StandardOutputContent)
+		result.ExitCode = int64(invocation.ResponseCode)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	switch invocation.Status {
+	case types.CommandInvocationStatusSuccess:
+		return result, nil
+	case types.CommandInvocationStatusFailed:
+		return result, errors.New(aws.ToString(invocation.StatusDetails))
+	}
+	return result, fmt.Errorf("bad status: %s", invocation.Status)
+}
 
+// sendSsmCommandE sends the given command to the given instance with the given SSM document and returns the id of the
+// command.
+func sendSsmCommandE(client *ssm.Client, instanceID string, command string, commandDocName string) (string, error) {
 	resp, err := client.SendCommand(
 		context.Background(),
 		&ssm.SendCommandInput{
@@ -222,10 +326,18 @@ func CheckSSMCommandWithClientWithDocumentE(t testing.TestingT, client *ssm.Clie
 		},
 	)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
+	return aws.ToString(resp.Command.CommandId), nil
+}
+
+// waitForSsmCommandInvocationE waits up to the given timeout for the command with the given id to finish on the given
+// instance and returns its invocation. If the command is still running when the timeout expires, the last invocation
+// that was fetched is returned along with the error.
+func waitForSsmCommandInvocationE(t testing.TestingT, client *ssm.Client, commandID string, instanceID string, timeout time.Duration) (*ssm.GetCommandInvocationOutput, error) {
+	timeBetweenRetries := 2 * time.Second
+	maxRetries := int(timeout.Seconds() / timeBetweenRetries.Seconds())
 
-	// Wait for the result
 	description := "Waiting for the result of the command"
 	retryableErrors := map[string]string{
 		"InvocationDoesNotExist": "InvocationDoesNotExist",
@@ -234,41 +346,311 @@ func CheckSSMCommandWithClientWithDocumentE(t testing.TestingT, client *ssm.Clie
 		"bad status: Delayed":    "bad status: Delayed",
 	}
 
-	result := &CommandOutput{}
-	_, err = retry.DoWithRetryableErrorsE(t, description, retryableErrors, maxRetries, timeBetweenRetries, func() (string, error) {
+	var invocation *ssm.GetCommandInvocationOutput
+	_, err := retry.DoWithRetryableErrorsE(t, description, retryableErrors, maxRetries, timeBetweenRetries, func() (string, error) {
 		resp, err := client.GetCommandInvocation(context.Background(), &ssm.GetCommandInvocationInput{
-			CommandId:  resp.Command.CommandId,
-			InstanceId: &instanceID,
+			CommandId:  aws.String(commandID),
+			InstanceId: aws.String(instanceID),
 		})
-
 		if err != nil {
 			return "", err
 		}
+		invocation = resp
+
+		switch resp.Status {
+		case types.CommandInvocationStatusPending, types.CommandInvocationStatusInProgress, types.CommandInvocationStatusDelayed:
+			return "", fmt.Errorf("bad status: %s", resp.Status)
+		}
+		return "", nil
+	})
+
+	if err != nil {
+		var actualErr retry.FatalError
+		if errors.As(err, &actualErr) {
+			return invocation, actualErr.Underlying
+		}
+		return invocation, fmt.Errorf("unexpected error: %v", err)
+	}
 
-		result.Stderr = aws.ToString(resp.StandardErrorContent)
-		result.Stdout = aws.ToString(resp.StandardOutputContent)
-		result.ExitCode = int64(resp.ResponseCode)
+	return invocation, nil
+}
 
-		status := resp.Status
+// SsmSessionManagerPluginPath is the path of the Session Manager plugin used to open SSM port forwarding sessions. See
+// https://docs.aws.amazon.com/systems-manager/latest/userguide/session-manager-working-with-install-plugin.html
+const SsmSessionManagerPluginPath = "session-manager-plugin"
 
-		if status == types.CommandInvocationStatusSuccess {
-			return "", nil
+// RunSsmCommand runs the given shell script on the given instance through AWS SSM, waits for it to finish and returns
+// its output and exit code. Unlike CheckSsmCommand, this does not fail the test if the script exits with a non-zero
+// exit code, so the exit code can be checked by the test.
+func RunSsmCommand(t testing.TestingT, awsRegion string, instanceID string, script string, timeout time.Duration) *CommandOutput {
+	result, err := RunSsmCommandE(t, awsRegion, instanceID, script, timeout)
+	require.NoError(t, err)
+	return result
+}
+
+// RunSsmCommandE runs the given shell script on the given instance through AWS SSM, waits for it to finish and returns
+// its output and exit code. Unlike CheckSsmCommandE, this does not return an error if the script exits with a non-zero
+// exit code, but only if the script could not be run to completion, e.g. because it was cancelled or timed out.
+func RunSsmCommandE(t testing.TestingT, awsRegion string, instanceID string, script string, timeout time.Duration) (*CommandOutput, error) {
+	client, err := NewSsmClientE(t, awsRegion)
+	if err != nil {
+		return nil, err
+	}
+	return RunSsmCommandWithClientE(t, client, instanceID, script, timeout)
+}
+
+// RunSsmCommandWithClientE runs the given shell script on the given instance through AWS SSM with the ability to
+// provide the SSM client, waits for it to finish and returns its output and exit code. Note that SSM truncates the
+// stdout and stderr of a command to 24,000 characters each. Returns an SsmCommandFailed error if the script could not
+// be run to completion.
+func RunSsmCommandWithClientE(t testing.TestingT, client *ssm.Client, instanceID string, script string, timeout time.Duration) (*CommandOutput, error) {
+	logger.Default.Logf(t, "Running script on EC2 instance with ID '%s' through SSM", instanceID)
+
+	commandID, err := sendSsmCommandE(client, instanceID, script, "AWS-RunShellScript")
+	if err != nil {
+		return nil, err
+	}
+	invocation, err := waitForSsmCommandInvocationE(t, client, commandID, instanceID, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return checkSsmCommandInvocation(commandID, instanceID, invocation)
+}
+
+// checkSsmCommandInvocation returns the output of the given finished invocation of the command with the given id on
+// the given instance if the command ran to completion, or an SsmCommandFailed error if it was not run to completion.
+func checkSsmCommandInvocation(commandID string, instanceID string, invocation *ssm.GetCommandInvocationOutput) (*CommandOutput, error) {
+	result := &CommandOutput{
+		Stdout:   aws.ToString(invocation.StandardOutputContent),
+		Stderr:   aws.ToString(invocation.StandardErrorContent),
+		ExitCode: int64(invocation.ResponseCode),
+	}
+
+	switch invocation.Status {
+	case types.CommandInvocationStatusSuccess:
+		return result, nil
+	case types.CommandInvocationStatusFailed:
+		// A response code of -1 means the script did not run at all, e.g. because the SSM agent could not start it
+		if invocation.ResponseCode >= 0 {
+			return result, nil
 		}
+	}
 
-		if status == types.CommandInvocationStatusFailed {
-			return "", fmt.Errorf(aws.ToString(resp.StatusDetails))
+	return nil, SsmCommandFailed{
+		CommandID:     commandID,
+		InstanceID:    instanceID,
+		Status:        string(invocation.Status),
+		StatusDetails: aws.ToString(invocation.StatusDetails),
+	}
+}
+
+// SsmTunnel forwards a local port to a port on an EC2 instance, or to a port on a remote host reachable from the
+// instance, through an SSM port forwarding session. This allows tests to reach instances and databases in private
+// subnets without public IPs, SSH keys or bastion hosts. The Session Manager plugin must be installed.
+type SsmTunnel struct {
+	region     string
+	instanceID string
+	remoteHost string
+	localPort  int
+	remotePort int
+	t          testing.TestingT
+	client     *ssm.Client
+	sessionID  string
+	cmd        *exec.Cmd
+	done       chan error
+}
+
+// NewSsmTunnel creates a new tunnel from the given local port to the given port on the given EC2 instance. If the
+// local port is 0, an open port on the host system is selected when the tunnel is opened with ForwardPort.
+func NewSsmTunnel(region string, instanceID string, localPort int, remotePort int) *SsmTunnel {
+	return &SsmTunnel{
+		region:     region,
+		instanceID: instanceID,
+		localPort:  localPort,
+		remotePort: remotePort,
+	}
+}
+
+// NewSsmTunnelToRemoteHost creates a new tunnel from the given local port to the given port on the given remote host,
+// such as the endpoint of an RDS instance, through the given EC2 instance. If the local port is 0, an open port on the
+// host system is selected when the tunnel is opened with ForwardPort.
+func NewSsmTunnelToRemoteHost(region string, instanceID string, remoteHost string, localPort int, remotePort int) *SsmTunnel {
+	tunnel := NewSsmTunnel(region, instanceID, localPort, remotePort)
+	tunnel.remoteHost = remoteHost
+	return tunnel
+}
+
+// Endpoint returns the local endpoint of the tunnel.
+func (tunnel *SsmTunnel) Endpoint() string {
+	return fmt.Sprintf("localhost:%d", tunnel.localPort)
+}
+
+// ForwardPort opens the tunnel and waits until it accepts connections. This will fail the test if the tunnel could not
+// be opened.
+func (tunnel *SsmTunnel) ForwardPort(t testing.TestingT) {
+	require.NoError(t, tunnel.ForwardPortE(t))
+}
+
+// ForwardPortE opens the tunnel and waits until it accepts connections, by starting an SSM session and running the
+// Session Manager plugin in the background to connect it to the local port. Call Close to close the tunnel.
+func (tunnel *SsmTunnel) ForwardPortE(t testing.TestingT) error {
+	if tunnel.localPort == 0 {
+		port, err := getAvailableLocalPort()
+		if err != nil {
+			return err
 		}
+		tunnel.localPort = port
+	}
 
-		return "", fmt.Errorf("bad status: %s", status)
-	})
+	logger.Default.Logf(t, "Creating an SSM port forwarding tunnel through %s routing local port %d to remote port %d", tunnel.instanceID, tunnel.localPort, tunnel.remotePort)
 
+	client, err := NewSsmClientE(t, tunnel.region)
 	if err != nil {
-		var actualErr retry.FatalError
-		if errors.As(err, &actualErr) {
-			return result, actualErr.Underlying
+		return err
+	}
+	tunnel.t = t
+	tunnel.client = client
+
+	input := newSsmPortForwardingSessionInput(tunnel.instanceID, tunnel.remoteHost, tunnel.localPort, tunnel.remotePort)
+	session, err := client.StartSession(context.Background(), input)
+	if err != nil {
+		return err
+	}
+	tunnel.sessionID = aws.ToString(session.SessionId)
+
+	endpoint, err := getSsmEndpointE(client)
+	if err != nil {
+		tunnel.Close()
+		return err
+	}
+	args, err := newSessionManagerPluginArgs(tunnel.region, endpoint, input, session)
+	if err != nil {
+		tunnel.Close()
+		return err
+	}
+	tunnel.cmd = exec.Command(SsmSessionManagerPluginPath, args...)
+	if err := tunnel.cmd.Start(); err != nil {
+		tunnel.Close()
+		return err
+	}
+	tunnel.done = make(chan error, 1)
+	go func() {
+		tunnel.done <- tunnel.cmd.Wait()
+	}()
+
+	if err := tunnel.waitUntilReady(30 * time.Second); err != nil {
+		tunnel.Close()
+		return err
+	}
+	logger.Default.Logf(t, "Successfully created SSM port forwarding tunnel with session %s", tunnel.sessionID)
+	return nil
+}
+
+// waitUntilReady waits up to the given timeout for the local port of the tunnel to accept connections.
+func (tunnel *SsmTunnel) waitUntilReady(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := net.DialTimeout("tcp", tunnel.Endpoint(), time.Second)
+		if err == nil {
+			return conn.Close()
+		}
+
+		select {
+		case err := <-tunnel.done:
+			tunnel.done <- err
+			return fmt.Errorf("%s exited before the SSM tunnel was ready: %v", SsmSessionManagerPluginPath, err)
+		case <-time.After(500 * time.Millisecond):
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("SSM tunnel on %s was not ready after %s", tunnel.Endpoint(), timeout)
 		}
-		return result, fmt.Errorf("unexpected error: %v", err)
 	}
+}
+
+// Close closes the tunnel by terminating the SSM session and stopping the Session Manager plugin. A failure to terminate
+// the session is logged, as the session is eventually terminated by SSM once it is idle.
+func (tunnel *SsmTunnel) Close() {
+	if tunnel.client != nil && tunnel.sessionID != "" {
+		_, err := tunnel.client.TerminateSession(context.Background(), &ssm.TerminateSessionInput{
+			SessionId: aws.String(tunnel.sessionID),
+		})
+		if err != nil {
+			logger.Default.Logf(tunnel.t, "Failed to terminate SSM session %s: %v", tunnel.sessionID, err)
+		}
+		tunnel.sessionID = ""
+	}
+	if tunnel.cmd != nil && tunnel.cmd.Process != nil {
+		tunnel.cmd.Process.Kill()
+		<-tunnel.done
+		tunnel.cmd = nil
+	}
+}
+
+// newSsmPortForwardingSessionInput returns the input to start an SSM session that forwards the given local port to
+// the given remote port on the given instance or, if remoteHost is set, on the given remote host.
+func newSsmPortForwardingSessionInput(instanceID string, remoteHost string, localPort int, remotePort int) *ssm.StartSessionInput {
+	parameters := map[string][]string{
+		"portNumber":      {strconv.Itoa(remotePort)},
+		"localPortNumber": {strconv.Itoa(localPort)},
+	}
+	documentName := "AWS-StartPortForwardingSession"
+	if remoteHost != "" {
+		parameters["host"] = []string{remoteHost}
+		documentName = "AWS-StartPortForwardingSessionToRemoteHost"
+	}
+
+	return &ssm.StartSessionInput{
+		Target:       aws.String(instanceID),
+		DocumentName: aws.String(documentName),
+		Parameters:   parameters,
+	}
+}
 
-	return result, nil
+// getSsmEndpointE returns the endpoint of the SSM API that the given client sends its requests to, which the Session
+// Manager plugin uses to manage the session: the custom endpoint of the client, if it has one (see SetGlobalEndpoint),
+// or else the endpoint of its region, in the partition of the region.
+func getSsmEndpointE(client *ssm.Client) (string, error) {
+	options := client.Options()
+	endpoint, err := options.EndpointResolverV2.ResolveEndpoint(context.Background(), ssm.EndpointParameters{
+		Region:   aws.String(options.Region),
+		Endpoint: options.BaseEndpoint,
+	})
+	if err != nil {
+		return "", err
+	}
+	return endpoint.URI.String(), nil
+}
+
+// newSessionManagerPluginArgs returns the arguments to run the Session Manager plugin for the given session, started
+// through the given endpoint of the SSM API, the same way the AWS CLI does for `aws ssm start-session`.
+func newSessionManagerPluginArgs(region string, endpoint string, input *ssm.StartSessionInput, session *ssm.StartSessionOutput) ([]string, error) {
+	sessionJSON, err := json.Marshal(map[string]string{
+		"SessionId":  aws.ToString(session.SessionId),
+		"StreamUrl":  aws.ToString(session.StreamUrl),
+		"TokenValue": aws.ToString(session.TokenValue),
+	})
+	if err != nil {
+		return nil, err
+	}
+	inputJSON, err := json.Marshal(map[string]interface{}{
+		"Target":       aws.ToString(input.Target),
+		"DocumentName": aws.ToString(input.DocumentName),
+		"Parameters":   input.Parameters,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{string(sessionJSON), region, "StartSession", "", string(inputJSON), endpoint}, nil
+}
+
+// getAvailableLocalPort returns a port on the host system that is not in use.
+func getAvailableLocalPort() (int, error) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
 }
@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
 	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/random"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParameterIsFound(t *testing.T) {
@@ -38,3 +42,81 @@ func TestParameterIsDeleted(t *testing.T) {
 	assert.Equal(t, actualValue, "")
 	assert.Error(t, err)
 }
+
+func TestNewSsmParameter(t *testing.T) {
+	t.Parallel()
+
+	parameter := newSsmParameter(&types.Parameter{
+		Name:    aws.String("/app/password"),
+		Type:    types.ParameterTypeSecureString,
+		Value:   aws.String("hunter2"),
+		Version: 3,
+	})
+
+	assert.Equal(t, &SsmParameter{Name: "/app/password", Type: types.ParameterTypeSecureString, Value: "hunter2", Version: 3}, parameter)
+}
+
+func TestCheckSsmCommandInvocation(t *testing.T) {
+	t.Parallel()
+
+	result, err := checkSsmCommandInvocation("command", "i-123", &ssm.GetCommandInvocationOutput{
+		Status:                types.CommandInvocationStatusFailed,
+		ResponseCode:          2,
+		StandardOutputContent: aws.String("out"),
+		StandardErrorContent:  aws.String("not found"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &CommandOutput{Stdout: "out", Stderr: "not found", ExitCode: 2}, result)
+
+	_, err = checkSsmCommandInvocation("command", "i-123", &ssm.GetCommandInvocationOutput{
+		Status:        types.CommandInvocationStatusTimedOut,
+		ResponseCode:  -1,
+		StatusDetails: aws.String("DeliveryTimedOut"),
+	})
+	assert.Equal(t, SsmCommandFailed{CommandID: "command", InstanceID: "i-123", Status: "TimedOut", StatusDetails: "DeliveryTimedOut"}, err)
+}
+
+func TestNewSsmPortForwardingSessionInput(t *testing.T) {
+	t.Parallel()
+
+	input := newSsmPortForwardingSessionInput("i-123", "", 8080, 80)
+	assert.Equal(t, "AWS-StartPortForwardingSession", aws.ToString(input.DocumentName))
+	assert.Equal(t, map[string][]string{"portNumber": {"80"}, "localPortNumber": {"8080"}}, input.Parameters)
+
+	input = newSsmPortForwardingSessionInput("i-123", "db.example.com", 15432, 5432)
+	assert.Equal(t, "AWS-StartPortForwardingSessionToRemoteHost", aws.ToString(input.DocumentName))
+	assert.Equal(t, []string{"db.example.com"}, input.Parameters["host"])
+}
+
+func TestNewSessionManagerPluginArgs(t *testing.T) {
+	t.Parallel()
+
+	input := newSsmPortForwardingSessionInput("i-123", "", 8080, 80)
+	args, err := newSessionManagerPluginArgs("us-east-1", "https://ssm.us-east-1.amazonaws.com", input, &ssm.StartSessionOutput{
+		SessionId:  aws.String("session"),
+		StreamUrl:  aws.String("wss://ssmmessages.us-east-1.amazonaws.com/v1/data-channel/session"),
+		TokenValue: aws.String("token"),
+	})
+	require.NoError(t, err)
+	require.Len(t, args, 6)
+	assert.JSONEq(t, `{"SessionId": "session", "StreamUrl": "wss://ssmmessages.us-east-1.amazonaws.com/v1/data-channel/session", "TokenValue": "token"}`, args[0])
+	assert.Equal(t, []string{"us-east-1", "StartSession", ""}, args[1:4])
+	assert.JSONEq(t, `{"Target": "i-123", "DocumentName": "AWS-StartPortForwardingSession", "Parameters": {"portNumber": ["80"], "localPortNumber": ["8080"]}}`, args[4])
+	assert.Equal(t, "https://ssm.us-east-1.amazonaws.com", args[5])
+}
+
+func TestGetSsmEndpoint(t *testing.T) {
+	t.Parallel()
+
+	endpoint, err := getSsmEndpointE(ssm.New(ssm.Options{Region: "us-east-1"}))
+	require.NoError(t, err)
+	assert.Equal(t, "https://ssm.us-east-1.amazonaws.com", endpoint)
+
+	endpoint, err = getSsmEndpointE(ssm.New(ssm.Options{Region: "cn-north-1"}))
+	require.NoError(t, err)
+	assert.Equal(t, "https://ssm.cn-north-1.amazonaws.com.cn", endpoint)
+
+	endpoint, err = getSsmEndpointE(ssm.New(ssm.Options{Region: "us-east-1", BaseEndpoint: aws.String("http://localhost:4566")}))
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:4566", endpoint)
+}
@@ -0,0 +1,174 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// TransitGateway is an AWS Transit Gateway.
+type TransitGateway struct {
+	Id                             string            // The ID of the transit gateway
+	Arn                            string            // The ARN of the transit gateway
+	State                          string            // The state of the transit gateway (e.g. available)
+	OwnerId                        string            // The ID of the AWS account that owns the transit gateway
+	AmazonSideAsn                  int64             // The private ASN for the Amazon side of a BGP session
+	AutoAcceptSharedAttachments    string            // Whether attachment requests are automatically accepted (enable or disable)
+	DefaultRouteTableAssociation   string            // Whether attachments are automatically associated with the default route table (enable or disable)
+	DefaultRouteTablePropagation   string            // Whether attachments automatically propagate routes to the default route table (enable or disable)
+	AssociationDefaultRouteTableId string            // The ID of the default association route table
+	PropagationDefaultRouteTableId string            // The ID of the default propagation route table
+	DnsSupport                     string            // Whether DNS support is enabled (enable or disable)
+	Tags                           map[string]string // The tags associated with the transit gateway
+}
+
+// TransitGatewayAttachment is an attachment between a resource (e.g. a VPC or a VPN) and a transit gateway.
+type TransitGatewayAttachment struct {
+	Id                    string // The ID of the attachment
+	TransitGatewayId      string // The ID of the transit gateway
+	ResourceType          string // The type of the attached resource (e.g. vpc, vpn, peering)
+	ResourceId            string // The ID of the attached resource
+	ResourceOwnerId       string // The ID of the AWS account that owns the attached resource
+	State                 string // The state of the attachment (e.g. available, pendingAcceptance)
+	AssociationState      string // The state of the association with a transit gateway route table. Empty if not associated.
+	AssociationRouteTable string // The ID of the transit gateway route table the attachment is associated with. Empty if not associated.
+}
+
+const transitGatewayIdFilterName = "transit-gateway-id"
+const transitGatewayVpcResourceType = "vpc"
+
+// GetTransitGateway fetches information about the transit gateway with the given ID in the given region.
+func GetTransitGateway(t testing.TestingT, region string, transitGatewayID string) *TransitGateway {
+	transitGateway, err := GetTransitGatewayE(t, region, transitGatewayID)
+	require.NoError(t, err)
+	return transitGateway
+}
+
+// GetTransitGatewayE fetches information about the transit gateway with the given ID in the given region.
+func GetTransitGatewayE(t testing.TestingT, region string, transitGatewayID string) (*TransitGateway, error) {
+	client, err := NewEc2ClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.DescribeTransitGateways(context.Background(), &ec2.DescribeTransitGatewaysInput{
+		TransitGatewayIds: []string{transitGatewayID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(output.TransitGateways) != 1 {
+		return nil, NewNotFoundError("Transit Gateway", transitGatewayID, region)
+	}
+
+	return newTransitGateway(output.TransitGateways[0]), nil
+}
+
+// GetTransitGatewayAttachments fetches all the attachments of the transit gateway with the given ID in the given region.
+func GetTransitGatewayAttachments(t testing.TestingT, region string, transitGatewayID string) []TransitGatewayAttachment {
+	attachments, err := GetTransitGatewayAttachmentsE(t, region, transitGatewayID)
+	require.NoError(t, err)
+	return attachments
+}
+
+// GetTransitGatewayAttachmentsE fetches all the attachments of the transit gateway with the given ID in the given region.
+func GetTransitGatewayAttachmentsE(t testing.TestingT, region string, transitGatewayID string) ([]TransitGatewayAttachment, error) {
+	client, err := NewEc2ClientE(t, region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &ec2.DescribeTransitGatewayAttachmentsInput{
+		Filters: []types.Filter{
+			{Name: aws.String(transitGatewayIdFilterName), Values: []string{transitGatewayID}},
+		},
+	}
+
+	attachments := []TransitGatewayAttachment{}
+	paginator := ec2.NewDescribeTransitGatewayAttachmentsPaginator(client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for _, attachment := range page.TransitGatewayAttachments {
+			attachments = append(attachments, newTransitGatewayAttachment(attachment))
+		}
+	}
+
+	return attachments, nil
+}
+
+// GetTransitGatewayVpcAttachment fetches the attachment between the given VPC and the transit gateway with the given
+// ID in the given region.
+func GetTransitGatewayVpcAttachment(t testing.TestingT, region string, transitGatewayID string, vpcID string) *TransitGatewayAttachment {
+	attachment, err := GetTransitGatewayVpcAttachmentE(t, region, transitGatewayID, vpcID)
+	require.NoError(t, err)
+	return attachment
+}
+
+// GetTransitGatewayVpcAttachmentE fetches the attachment between the given VPC and the transit gateway with the given
+// ID in the given region. Returns a NotFoundError if the VPC is not attached to the transit gateway.
+func GetTransitGatewayVpcAttachmentE(t testing.TestingT, region string, transitGatewayID string, vpcID string) (*TransitGatewayAttachment, error) {
+	attachments, err := GetTransitGatewayAttachmentsE(t, region, transitGatewayID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range attachments {
+		if attachment.ResourceType == transitGatewayVpcResourceType && attachment.ResourceId == vpcID {
+			return &attachment, nil
+		}
+	}
+
+	return nil, NewNotFoundError("Transit Gateway VPC attachment", vpcID, region)
+}
+
+func newTransitGateway(transitGateway types.TransitGateway) *TransitGateway {
+	tags := map[string]string{}
+	for _, tag := range transitGateway.Tags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+
+	result := &TransitGateway{
+		Id:      aws.ToString(transitGateway.TransitGatewayId),
+		Arn:     aws.ToString(transitGateway.TransitGatewayArn),
+		State:   string(transitGateway.State),
+		OwnerId: aws.ToString(transitGateway.OwnerId),
+		Tags:    tags,
+	}
+
+	if options := transitGateway.Options; options != nil {
+		result.AmazonSideAsn = aws.ToInt64(options.AmazonSideAsn)
+		result.AutoAcceptSharedAttachments = string(options.AutoAcceptSharedAttachments)
+		result.DefaultRouteTableAssociation = string(options.DefaultRouteTableAssociation)
+		result.DefaultRouteTablePropagation = string(options.DefaultRouteTablePropagation)
+		result.AssociationDefaultRouteTableId = aws.ToString(options.AssociationDefaultRouteTableId)
+		result.PropagationDefaultRouteTableId = aws.ToString(options.PropagationDefaultRouteTableId)
+		result.DnsSupport = string(options.DnsSupport)
+	}
+
+	return result
+}
+
+func newTransitGatewayAttachment(attachment types.TransitGatewayAttachment) TransitGatewayAttachment {
+	result := TransitGatewayAttachment{
+		Id:               aws.ToString(attachment.TransitGatewayAttachmentId),
+		TransitGatewayId: aws.ToString(attachment.TransitGatewayId),
+		ResourceType:     string(attachment.ResourceType),
+		ResourceId:       aws.ToString(attachment.ResourceId),
+		ResourceOwnerId:  aws.ToString(attachment.ResourceOwnerId),
+		State:            string(attachment.State),
+	}
+
+	if association := attachment.Association; association != nil {
+		result.AssociationState = string(association.State)
+		result.AssociationRouteTable = aws.ToString(association.TransitGatewayRouteTableId)
+	}
+
+	return result
+}
@@ -0,0 +1,46 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTransitGatewayAttachment(t *testing.T) {
+	t.Parallel()
+
+	attachment := newTransitGatewayAttachment(types.TransitGatewayAttachment{
+		TransitGatewayAttachmentId: aws.String("tgw-attach-123"),
+		TransitGatewayId:           aws.String("tgw-123"),
+		ResourceType:               types.TransitGatewayAttachmentResourceTypeVpc,
+		ResourceId:                 aws.String("vpc-123"),
+		State:                      types.TransitGatewayAttachmentStateAvailable,
+		Association: &types.TransitGatewayAttachmentAssociation{
+			State:                      types.TransitGatewayAssociationStateAssociated,
+			TransitGatewayRouteTableId: aws.String("tgw-rtb-123"),
+		},
+	})
+
+	assert.Equal(t, "tgw-attach-123", attachment.Id)
+	assert.Equal(t, "vpc", attachment.ResourceType)
+	assert.Equal(t, "vpc-123", attachment.ResourceId)
+	assert.Equal(t, "available", attachment.State)
+	assert.Equal(t, "associated", attachment.AssociationState)
+	assert.Equal(t, "tgw-rtb-123", attachment.AssociationRouteTable)
+}
+
+func TestNewTransitGatewayAttachmentNotAssociated(t *testing.T) {
+	t.Parallel()
+
+	attachment := newTransitGatewayAttachment(types.TransitGatewayAttachment{
+		TransitGatewayAttachmentId: aws.String("tgw-attach-123"),
+		ResourceType:               types.TransitGatewayAttachmentResourceTypeVpn,
+		State:                      types.TransitGatewayAttachmentStatePendingAcceptance,
+	})
+
+	assert.Equal(t, "vpn", attachment.ResourceType)
+	assert.Equal(t, "pendingAcceptance", attachment.State)
+	assert.Empty(t, attachment.AssociationState)
+}
@@ -12,6 +12,7 @@ package azure
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"reflect"
 	"strings"
@@ -30,6 +31,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-07-01/compute"
 	"github.com/Azure/azure-sdk-for-go/services/containerinstance/mgmt/2018-10-01/containerinstance"
 	"github.com/Azure/azure-sdk-for-go/services/containerregistry/mgmt/2019-05-01/containerregistry"
@@ -1088,6 +1090,38 @@ func getArmAppContainersClientFactory(subscriptionID string) (*armappcontainers.
 	})
 }
 
+// CreateStorageBlobContainerDataClientE creates a client for the blobs of the storage container with the given URL
+// (e.g. https://myaccount.blob.core.windows.net/mycontainer). If the URL carries a SAS token, the requests are
+// authorized with it; otherwise they are authorized with the default Azure credential (environment, managed identity or
+// Azure CLI).
+func CreateStorageBlobContainerDataClientE(containerURL string) (*container.Client, error) {
+	parsedURL, err := url.Parse(containerURL)
+	if err != nil {
+		return nil, err
+	}
+	clientCloudConfig, err := getClientCloudConfig()
+	if err != nil {
+		return nil, err
+	}
+	options := &container.ClientOptions{
+		ClientOptions: azcore.ClientOptions{
+			Cloud: clientCloudConfig,
+		},
+	}
+	if parsedURL.Query().Has("sig") {
+		return container.NewClientWithNoCredential(containerURL, options)
+	}
+	cred, err := azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{
+		ClientOptions: azcore.ClientOptions{
+			Cloud: clientCloudConfig,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return container.NewClient(containerURL, cred, options)
+}
+
 func getClientCloudConfig() (cloud.Configuration, error) {
 	envName := getDefaultEnvironmentName()
 	switch strings.ToUpper(envName) {
@@ -0,0 +1,141 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/stretchr/testify/require"
+)
+
+// ReadStorageBlob returns the contents of the blob with the given name in the storage container with the given URL.
+// This function would fail the test if there is an error.
+func ReadStorageBlob(t *testing.T, containerURL string, blobName string) []byte {
+	contents, err := ReadStorageBlobE(containerURL, blobName)
+	require.NoError(t, err)
+	return contents
+}
+
+// ReadStorageBlobE returns the contents of the blob with the given name in the storage container with the given URL.
+// See CreateStorageBlobContainerDataClientE for how the requests are authorized.
+func ReadStorageBlobE(containerURL string, blobName string) ([]byte, error) {
+	client, err := CreateStorageBlobContainerDataClientE(containerURL)
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.NewBlobClient(blobName).DownloadStream(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	return io.ReadAll(response.Body)
+}
+
+// WriteStorageBlob writes the given contents, with the given content type, to a block blob with the given name in the
+// storage container with the given URL, replacing the blob if it already exists.
+// This function would fail the test if there is an error.
+func WriteStorageBlob(t *testing.T, containerURL string, blobName string, contents []byte, contentType string) {
+	err := WriteStorageBlobE(containerURL, blobName, contents, contentType)
+	require.NoError(t, err)
+}
+
+// WriteStorageBlobE writes the given contents, with the given content type, to a block blob with the given name in the
+// storage container with the given URL, replacing the blob if it already exists.
+func WriteStorageBlobE(containerURL string, blobName string, contents []byte, contentType string) error {
+	client, err := CreateStorageBlobContainerDataClientE(containerURL)
+	if err != nil {
+		return err
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	_, err = client.NewBlockBlobClient(blobName).Upload(context.Background(), streaming.NopCloser(bytes.NewReader(contents)), &blockblob.UploadOptions{
+		HTTPHeaders: &blob.HTTPHeaders{BlobContentType: &contentType},
+	})
+	return err
+}
+
+// DeleteStorageBlob deletes the blob with the given name in the storage container with the given URL.
+// This function would fail the test if there is an error.
+func DeleteStorageBlob(t *testing.T, containerURL string, blobName string) {
+	err := DeleteStorageBlobE(containerURL, blobName)
+	require.NoError(t, err)
+}
+
+// DeleteStorageBlobE deletes the blob with the given name in the storage container with the given URL. Deleting a blob
+// that doesn't exist is not an error.
+func DeleteStorageBlobE(containerURL string, blobName string) error {
+	client, err := CreateStorageBlobContainerDataClientE(containerURL)
+	if err != nil {
+		return err
+	}
+	_, err = client.NewBlobClient(blobName).Delete(context.Background(), nil)
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return nil
+	}
+	return err
+}
+
+// ListStorageBlobNamesWithPrefix returns the names of all the blobs in the storage container with the given URL that
+// start with the given prefix.
+// This function would fail the test if there is an error.
+func ListStorageBlobNamesWithPrefix(t *testing.T, containerURL string, prefix string) []string {
+	blobNames, err := ListStorageBlobNamesWithPrefixE(containerURL, prefix)
+	require.NoError(t, err)
+	return blobNames
+}
+
+// ListStorageBlobNamesWithPrefixE returns the names of all the blobs in the storage container with the given URL that
+// start with the given prefix.
+func ListStorageBlobNamesWithPrefixE(containerURL string, prefix string) ([]string, error) {
+	client, err := CreateStorageBlobContainerDataClientE(containerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	blobNames := []string{}
+	pager := client.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{Prefix: &prefix})
+	for pager.More() {
+		page, err := pager.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Segment.BlobItems {
+			blobNames = append(blobNames, *item.Name)
+		}
+	}
+	return blobNames, nil
+}
+
+// GetStorageBlobLastModified returns when the blob with the given name in the storage container with the given URL was
+// last modified.
+// This function would fail the test if there is an error.
+func GetStorageBlobLastModified(t *testing.T, containerURL string, blobName string) time.Time {
+	lastModified, err := GetStorageBlobLastModifiedE(containerURL, blobName)
+	require.NoError(t, err)
+	return lastModified
+}
+
+// GetStorageBlobLastModifiedE returns when the blob with the given name in the storage container with the given URL was
+// last modified.
+func GetStorageBlobLastModifiedE(containerURL string, blobName string) (time.Time, error) {
+	client, err := CreateStorageBlobContainerDataClientE(containerURL)
+	if err != nil {
+		return time.Time{}, err
+	}
+	properties, err := client.NewBlobClient(blobName).GetProperties(context.Background(), nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if properties.LastModified == nil {
+		return time.Time{}, nil
+	}
+	return *properties.LastModified, nil
+}
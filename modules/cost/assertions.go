@@ -0,0 +1,38 @@
+package cost
+
+import (
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// AssertMonthlyCostBelow checks that the estimated monthly cost of all the resources of the breakdown is below the
+// given maximum, in the currency of the breakdown, failing the test if it is not.
+func AssertMonthlyCostBelow(t testing.TestingT, breakdown *Breakdown, max float64) {
+	assert.Lessf(t, breakdown.TotalMonthlyCost, max, "Estimated monthly cost is above the maximum of %.2f %s", max, breakdown.Currency)
+}
+
+// RequireMonthlyCostBelow checks that the estimated monthly cost of all the resources of the breakdown is below the
+// given maximum, in the currency of the breakdown, failing and halting the test if it is not.
+func RequireMonthlyCostBelow(t testing.TestingT, breakdown *Breakdown, max float64) {
+	require.Lessf(t, breakdown.TotalMonthlyCost, max, "Estimated monthly cost is above the maximum of %.2f %s", max, breakdown.Currency)
+}
+
+// AssertResourceMonthlyCostBelow checks that the estimated monthly cost of the resource with the given address (e.g.
+// aws_instance.web) is below the given maximum, in the currency of the breakdown, failing the test if it is not or if
+// the breakdown has no such resource.
+func AssertResourceMonthlyCostBelow(t testing.TestingT, breakdown *Breakdown, name string, max float64) {
+	resource, exists := breakdown.GetResource(name)
+	if assert.Truef(t, exists, "Resource %s not found in the cost breakdown", name) {
+		assert.Lessf(t, resource.MonthlyCost, max, "Estimated monthly cost of %s is above the maximum of %.2f %s", name, max, breakdown.Currency)
+	}
+}
+
+// RequireResourceMonthlyCostBelow checks that the estimated monthly cost of the resource with the given address (e.g.
+// aws_instance.web) is below the given maximum, in the currency of the breakdown, failing and halting the test if it
+// is not or if the breakdown has no such resource.
+func RequireResourceMonthlyCostBelow(t testing.TestingT, breakdown *Breakdown, name string, max float64) {
+	resource, exists := breakdown.GetResource(name)
+	require.Truef(t, exists, "Resource %s not found in the cost breakdown", name)
+	require.Lessf(t, resource.MonthlyCost, max, "Estimated monthly cost of %s is above the maximum of %.2f %s", name, max, breakdown.Currency)
+}
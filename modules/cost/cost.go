@@ -0,0 +1,230 @@
+// Package cost allows to estimate the cost of the resources of a Terraform plan with Infracost, so that tests can fail
+// when a change makes the infrastructure more expensive than expected.
+package cost
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/shell"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// DefaultInfracostBinary is the Infracost binary that is used if Options.InfracostBinary is not set.
+const DefaultInfracostBinary = "infracost"
+
+// Options are the options to run Infracost with.
+type Options struct {
+	InfracostBinary string            // The Infracost binary to use. Defaults to infracost.
+	UsageFile       string            // The path of an Infracost usage file with the usage of the resources (e.g. the requests per month of a Lambda function)
+	EnvVars         map[string]string // Environment variables to set when running Infracost (e.g. INFRACOST_API_KEY or INFRACOST_CURRENCY)
+	Logger          *logger.Logger    // Set a non-default logger that should be used. See the logger package for more info.
+}
+
+// Breakdown is the estimated cost of the resources of a Terraform plan.
+type Breakdown struct {
+	Currency         string         // The currency of the costs (e.g. USD)
+	TotalMonthlyCost float64        // The estimated monthly cost of all the resources
+	TotalHourlyCost  float64        // The estimated hourly cost of all the resources
+	Resources        []ResourceCost // The resources that have a cost, in the order Infracost returned them
+}
+
+// ResourceCost is the estimated cost of one resource.
+type ResourceCost struct {
+	Name           string          // The address of the resource (e.g. aws_instance.web or module.db.aws_db_instance.this)
+	ResourceType   string          // The type of the resource (e.g. aws_instance)
+	MonthlyCost    float64         // The estimated monthly cost of the resource, including its subresources
+	HourlyCost     float64         // The estimated hourly cost of the resource, including its subresources
+	CostComponents []CostComponent // The parts of the cost of the resource (e.g. instance usage and storage)
+	Subresources   []ResourceCost  // The subresources of the resource (e.g. the root block device of an instance)
+}
+
+// CostComponent is one part of the cost of a resource, such as the usage or the storage of an instance.
+type CostComponent struct {
+	Name            string  // The name of the component (e.g. Instance usage (Linux/UNIX, on-demand, t3.micro))
+	Unit            string  // The unit of the price (e.g. hours or GB)
+	MonthlyQuantity float64 // The quantity used in a month, in the unit
+	Price           float64 // The price of one unit
+	MonthlyCost     float64 // The estimated monthly cost of the component
+}
+
+// GetResource returns the cost of the resource with the given address, and whether the breakdown has that resource.
+func (breakdown *Breakdown) GetResource(name string) (ResourceCost, bool) {
+	for _, resource := range breakdown.Resources {
+		if resource.Name == name {
+			return resource, true
+		}
+	}
+	return ResourceCost{}, false
+}
+
+// Estimate runs infracost breakdown on the given Terraform plan, in the JSON format returned by
+// terraform.InitAndPlanAndShow or terraform show -json, and returns the estimated cost of its resources. This will fail
+// the test if Infracost fails.
+func Estimate(t testing.TestingT, options *Options, planJson string) *Breakdown {
+	breakdown, err := EstimateE(t, options, planJson)
+	require.NoError(t, err)
+	return breakdown
+}
+
+// EstimateE runs infracost breakdown on the given Terraform plan, in the JSON format returned by
+// terraform.InitAndPlanAndShow or terraform show -json, and returns the estimated cost of its resources.
+func EstimateE(t testing.TestingT, options *Options, planJson string) (*Breakdown, error) {
+	planFile, err := os.CreateTemp("", "*-terratest-plan.json")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(planFile.Name())
+
+	_, err = planFile.WriteString(planJson)
+	planFile.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return EstimatePlanFileE(t, options, planFile.Name())
+}
+
+// EstimatePlanFile runs infracost breakdown on the Terraform plan at the given path, which can be a JSON plan (e.g. from
+// terraform show -json) or a Terraform directory, and returns the estimated cost of its resources. This will fail the
+// test if Infracost fails.
+func EstimatePlanFile(t testing.TestingT, options *Options, path string) *Breakdown {
+	breakdown, err := EstimatePlanFileE(t, options, path)
+	require.NoError(t, err)
+	return breakdown
+}
+
+// EstimatePlanFileE runs infracost breakdown on the Terraform plan at the given path, which can be a JSON plan (e.g.
+// from terraform show -json) or a Terraform directory, and returns the estimated cost of its resources.
+func EstimatePlanFileE(t testing.TestingT, options *Options, path string) (*Breakdown, error) {
+	cmd := shell.Command{
+		Command: options.InfracostBinary,
+		Args:    formatBreakdownArgs(options, path),
+		Env:     options.EnvVars,
+		Logger:  options.Logger,
+	}
+	if cmd.Command == "" {
+		cmd.Command = DefaultInfracostBinary
+	}
+
+	out, err := shell.RunCommandAndGetStdOutE(t, cmd)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBreakdown(out)
+}
+
+// formatBreakdownArgs formats the arguments of the infracost breakdown command for the given plan path.
+func formatBreakdownArgs(options *Options, path string) []string {
+	args := []string{"breakdown", "--path", path, "--format", "json", "--no-color"}
+	if options.UsageFile != "" {
+		args = append(args, "--usage-file", options.UsageFile)
+	}
+	return args
+}
+
+// ParseBreakdown parses the output of infracost breakdown --format json into a Breakdown. The resources of all the
+// projects of the output are returned together.
+func ParseBreakdown(out string) (*Breakdown, error) {
+	var output infracostOutput
+	if err := json.Unmarshal([]byte(out), &output); err != nil {
+		return nil, err
+	}
+
+	breakdown := &Breakdown{
+		Currency:         output.Currency,
+		TotalMonthlyCost: float64(output.TotalMonthlyCost),
+		TotalHourlyCost:  float64(output.TotalHourlyCost),
+		Resources:        []ResourceCost{},
+	}
+	for _, project := range output.Projects {
+		if project.Breakdown == nil {
+			continue
+		}
+		for _, resource := range project.Breakdown.Resources {
+			breakdown.Resources = append(breakdown.Resources, resource.toResourceCost())
+		}
+	}
+	return breakdown, nil
+}
+
+// infracostOutput is the output of infracost breakdown --format json.
+type infracostOutput struct {
+	Currency         string             `json:"currency"`
+	TotalMonthlyCost infracostCost      `json:"totalMonthlyCost"`
+	TotalHourlyCost  infracostCost      `json:"totalHourlyCost"`
+	Projects         []infracostProject `json:"projects"`
+}
+
+type infracostProject struct {
+	Name      string `json:"name"`
+	Breakdown *struct {
+		Resources []infracostResource `json:"resources"`
+	} `json:"breakdown"`
+}
+
+type infracostResource struct {
+	Name           string                   `json:"name"`
+	ResourceType   string                   `json:"resourceType"`
+	MonthlyCost    infracostCost            `json:"monthlyCost"`
+	HourlyCost     infracostCost            `json:"hourlyCost"`
+	CostComponents []infracostCostComponent `json:"costComponents"`
+	Subresources   []infracostResource      `json:"subresources"`
+}
+
+type infracostCostComponent struct {
+	Name            string        `json:"name"`
+	Unit            string        `json:"unit"`
+	MonthlyQuantity infracostCost `json:"monthlyQuantity"`
+	Price           infracostCost `json:"price"`
+	MonthlyCost     infracostCost `json:"monthlyCost"`
+}
+
+func (resource infracostResource) toResourceCost() ResourceCost {
+	resourceCost := ResourceCost{
+		Name:           resource.Name,
+		ResourceType:   resource.ResourceType,
+		MonthlyCost:    float64(resource.MonthlyCost),
+		HourlyCost:     float64(resource.HourlyCost),
+		CostComponents: []CostComponent{},
+		Subresources:   []ResourceCost{},
+	}
+	for _, component := range resource.CostComponents {
+		resourceCost.CostComponents = append(resourceCost.CostComponents, CostComponent{
+			Name:            component.Name,
+			Unit:            component.Unit,
+			MonthlyQuantity: float64(component.MonthlyQuantity),
+			Price:           float64(component.Price),
+			MonthlyCost:     float64(component.MonthlyCost),
+		})
+	}
+	for _, subresource := range resource.Subresources {
+		resourceCost.Subresources = append(resourceCost.Subresources, subresource.toResourceCost())
+	}
+	return resourceCost
+}
+
+// infracostCost is a cost or quantity in the output of Infracost, which is a decimal string, or null if it can't be
+// estimated (e.g. usage-based costs without a usage file), in which case it is 0.
+type infracostCost float64
+
+func (cost *infracostCost) UnmarshalJSON(data []byte) error {
+	var value *string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if value == nil || *value == "" {
+		*cost = 0
+		return nil
+	}
+
+	parsed, err := strconv.ParseFloat(*value, 64)
+	if err != nil {
+		return err
+	}
+	*cost = infracostCost(parsed)
+	return nil
+}
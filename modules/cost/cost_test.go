@@ -0,0 +1,107 @@
+package cost
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const breakdownJson = `{
+  "version": "0.2",
+  "currency": "USD",
+  "projects": [
+    {
+      "name": "plan.json",
+      "breakdown": {
+        "resources": [
+          {
+            "name": "aws_instance.web",
+            "resourceType": "aws_instance",
+            "hourlyCost": "0.0132",
+            "monthlyCost": "9.636",
+            "costComponents": [
+              {"name": "Instance usage (Linux/UNIX, on-demand, t3.micro)", "unit": "hours", "hourlyQuantity": "1", "monthlyQuantity": "730", "price": "0.0104", "hourlyCost": "0.0104", "monthlyCost": "7.592"}
+            ],
+            "subresources": [
+              {
+                "name": "root_block_device",
+                "resourceType": "",
+                "hourlyCost": "0.0028",
+                "monthlyCost": "2.044",
+                "costComponents": [
+                  {"name": "Storage (general purpose SSD, gp2)", "unit": "GB", "monthlyQuantity": "20", "price": "0.1022", "monthlyCost": "2.044"}
+                ]
+              }
+            ]
+          },
+          {
+            "name": "aws_lambda_function.api",
+            "resourceType": "aws_lambda_function",
+            "hourlyCost": null,
+            "monthlyCost": null,
+            "costComponents": [
+              {"name": "Requests", "unit": "1M requests", "monthlyQuantity": null, "price": "0.2", "monthlyCost": null}
+            ]
+          }
+        ]
+      }
+    }
+  ],
+  "totalHourlyCost": "0.0132",
+  "totalMonthlyCost": "9.636"
+}`
+
+func TestParseBreakdown(t *testing.T) {
+	t.Parallel()
+
+	breakdown, err := ParseBreakdown(breakdownJson)
+	require.NoError(t, err)
+
+	assert.Equal(t, "USD", breakdown.Currency)
+	assert.Equal(t, 9.636, breakdown.TotalMonthlyCost)
+	assert.Equal(t, 0.0132, breakdown.TotalHourlyCost)
+	require.Len(t, breakdown.Resources, 2)
+
+	instance, exists := breakdown.GetResource("aws_instance.web")
+	require.True(t, exists)
+	assert.Equal(t, "aws_instance", instance.ResourceType)
+	assert.Equal(t, 9.636, instance.MonthlyCost)
+	assert.Equal(t, []CostComponent{{Name: "Instance usage (Linux/UNIX, on-demand, t3.micro)", Unit: "hours", MonthlyQuantity: 730, Price: 0.0104, MonthlyCost: 7.592}}, instance.CostComponents)
+	require.Len(t, instance.Subresources, 1)
+	assert.Equal(t, 2.044, instance.Subresources[0].MonthlyCost)
+
+	lambda, exists := breakdown.GetResource("aws_lambda_function.api")
+	require.True(t, exists)
+	assert.Equal(t, 0.0, lambda.MonthlyCost)
+
+	_, exists = breakdown.GetResource("aws_s3_bucket.missing")
+	assert.False(t, exists)
+
+	AssertMonthlyCostBelow(t, breakdown, 10)
+	RequireMonthlyCostBelow(t, breakdown, 10)
+	AssertResourceMonthlyCostBelow(t, breakdown, "aws_instance.web", 10)
+	RequireResourceMonthlyCostBelow(t, breakdown, "aws_lambda_function.api", 1)
+}
+
+func TestEstimateE(t *testing.T) {
+	t.Parallel()
+
+	// the stub checks that the plan is passed with --path and prints the breakdown
+	tmpDir := t.TempDir()
+	breakdownPath := filepath.Join(tmpDir, "breakdown.json")
+	require.NoError(t, os.WriteFile(breakdownPath, []byte(breakdownJson), 0644))
+	stubPath := filepath.Join(tmpDir, "infracost-stub")
+	script := fmt.Sprintf("#!/bin/sh\n[ \"$1\" = \"breakdown\" ] && [ \"$2\" = \"--path\" ] && grep -q resource_changes \"$3\" && cat %q\n", breakdownPath)
+	require.NoError(t, os.WriteFile(stubPath, []byte(script), 0755))
+
+	breakdown, err := EstimateE(t, &Options{InfracostBinary: stubPath}, `{"format_version": "1.2", "resource_changes": []}`)
+	require.NoError(t, err)
+	assert.Equal(t, 9.636, breakdown.TotalMonthlyCost)
+
+	_, err = EstimateE(t, &Options{InfracostBinary: stubPath}, `{"format_version": "1.2"}`)
+	assert.Error(t, err)
+}
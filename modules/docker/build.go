@@ -1,6 +1,7 @@
 package docker
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -57,6 +58,10 @@ type BuildOptions struct {
 
 	// Set a logger that should be used. See the logger package for more info.
 	Logger *logger.Logger
+
+	// If set, the docker commands are interrupted when the context is done (e.g. the context passed to the stage by
+	// test_structure.RunTestStageWithContext).
+	Context context.Context
 }
 
 // Build runs the 'docker build' command at the given path with the given options and fails the test if there are any
@@ -83,6 +88,7 @@ func BuildE(t testing.TestingT, path string, options *BuildOptions) error {
 		Args:    formatDockerBuildArgs(path, options),
 		Logger:  options.Logger,
 		Env:     env,
+		Context: options.Context,
 	}
 
 	if err := shell.RunCommandE(t, cmd); err != nil {
@@ -108,6 +114,7 @@ func BuildE(t testing.TestingT, path string, options *BuildOptions) error {
 			Command: "docker",
 			Args:    formatDockerBuildxLoadArgs(path, options),
 			Logger:  options.Logger,
+			Context: options.Context,
 		}
 		return shell.RunCommandE(t, loadCmd)
 	}
@@ -147,6 +154,7 @@ func GitCloneAndBuildE(
 	cloneCmd := shell.Command{
 		Command: "git",
 		Args:    []string{"clone", repo, workingDir},
+		Context: dockerBuildOpts.Context,
 	}
 	if err := shell.RunCommandE(t, cloneCmd); err != nil {
 		return err
@@ -156,6 +164,7 @@ func GitCloneAndBuildE(
 		Command:    "git",
 		Args:       []string{"checkout", ref},
 		WorkingDir: workingDir,
+		Context:    dockerBuildOpts.Context,
 	}
 	if err := shell.RunCommandE(t, checkoutCmd); err != nil {
 		return err
@@ -1,6 +1,7 @@
 package docker
 
 import (
+	"context"
 	"regexp"
 	"strings"
 
@@ -22,6 +23,10 @@ type Options struct {
 	// Set a logger that should be used. See the logger package for more info.
 	Logger      *logger.Logger
 	ProjectName string
+
+	// If set, the docker commands are interrupted when the context is done (e.g. the context passed to the stage by
+	// test_structure.RunTestStageWithContext).
+	Context context.Context
 }
 
 // RunDockerCompose runs docker compose with the given arguments and options and return stdout/stderr.
@@ -72,6 +77,7 @@ func runDockerComposeE(t testing.TestingT, stdout bool, options *Options, args .
 			WorkingDir: options.WorkingDir,
 			Env:        options.EnvVars,
 			Logger:     options.Logger,
+			Context:    options.Context,
 		}
 	} else {
 		cmd = shell.Command{
@@ -82,6 +88,7 @@ func runDockerComposeE(t testing.TestingT, stdout bool, options *Options, args .
 			WorkingDir: options.WorkingDir,
 			Env:        options.EnvVars,
 			Logger:     options.Logger,
+			Context:    options.Context,
 		}
 	}
 
@@ -1,6 +1,8 @@
 package docker
 
 import (
+	"context"
+
 	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/shell"
 	"github.com/gruntwork-io/terratest/modules/testing"
@@ -53,6 +55,10 @@ type RunOptions struct {
 
 	// Set a logger that should be used. See the logger package for more info.
 	Logger *logger.Logger
+
+	// If set, the docker commands are interrupted when the context is done (e.g. the context passed to the stage by
+	// test_structure.RunTestStageWithContext).
+	Context context.Context
 }
 
 // Run runs the 'docker run' command on the given image with the given options and return stdout/stderr. This method
@@ -76,6 +82,7 @@ func RunE(t testing.TestingT, image string, options *RunOptions) (string, error)
 		Command: "docker",
 		Args:    args,
 		Logger:  options.Logger,
+		Context: options.Context,
 	}
 
 	return shell.RunCommandAndGetOutputE(t, cmd)
@@ -103,6 +110,7 @@ func RunAndGetIDE(t testing.TestingT, image string, options *RunOptions) (string
 		Command: "docker",
 		Args:    args,
 		Logger:  options.Logger,
+		Context: options.Context,
 	}
 
 	return shell.RunCommandAndGetStdOutE(t, cmd)
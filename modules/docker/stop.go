@@ -1,6 +1,7 @@
 package docker
 
 import (
+	"context"
 	"strconv"
 
 	"github.com/gruntwork-io/terratest/modules/logger"
@@ -16,6 +17,10 @@ type StopOptions struct {
 
 	// Set a logger that should be used. See the logger package for more info.
 	Logger *logger.Logger
+
+	// If set, the docker commands are interrupted when the context is done (e.g. the context passed to the stage by
+	// test_structure.RunTestStageWithContext).
+	Context context.Context
 }
 
 // Stop runs the 'docker stop' command for the given containers and return the stdout/stderr. This method fails
@@ -39,6 +44,7 @@ func StopE(t testing.TestingT, containers []string, options *StopOptions) (strin
 		Command: "docker",
 		Args:    args,
 		Logger:  options.Logger,
+		Context: options.Context,
 	}
 
 	return shell.RunCommandAndGetOutputE(t, cmd)
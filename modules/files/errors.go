@@ -10,3 +10,13 @@ type DirNotFoundError struct {
 func (err DirNotFoundError) Error() string {
 	return fmt.Sprintf("Directory was not found: \"%s\"", err.Directory)
 }
+
+// SymlinkCycleError is an error that occurs if a symlink points to a folder that contains it when following symlinks
+type SymlinkCycleError struct {
+	Path   string
+	Target string
+}
+
+func (err SymlinkCycleError) Error() string {
+	return fmt.Sprintf("Symlink \"%s\" points to \"%s\", which contains it", err.Path, err.Target)
+}
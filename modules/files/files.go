@@ -46,7 +46,18 @@ func IsExistingDir(path string) bool {
 // files, and terraform.tfvars files are not copied to this temp folder, as you typically don't want them interfering with your tests.
 // This method is useful when running through a build tool so the files are copied to a destination that is cleaned on each run of the pipeline.
 func CopyTerraformFolderToDest(folderPath string, destRootFolder string, tempFolderPrefix string) (string, error) {
-	filter := func(path string) bool {
+	return CopyTerraformFolderToDestWithOptions(folderPath, destRootFolder, tempFolderPrefix, CopyFolderOptions{})
+}
+
+// CopyTerraformFolderToDestWithOptions works like CopyTerraformFolderToDest, but only copies the files and folders
+// selected by the given options (e.g. to skip large folders in a monorepo, or to follow symlinks), in addition to
+// skipping the files that CopyTerraformFolderToDest skips.
+func CopyTerraformFolderToDestWithOptions(folderPath string, destRootFolder string, tempFolderPrefix string, options CopyFolderOptions) (string, error) {
+	optionsFilter := options.Filter
+	options.Filter = func(path string) bool {
+		if optionsFilter != nil && !optionsFilter(path) {
+			return false
+		}
 		if PathIsTerraformVersionFile(path) || PathIsTerraformLockFile(path) {
 			return true
 		}
@@ -56,7 +67,7 @@ func CopyTerraformFolderToDest(folderPath string, destRootFolder string, tempFol
 		return true
 	}
 
-	destFolder, err := CopyFolderToDest(folderPath, destRootFolder, tempFolderPrefix, filter)
+	destFolder, err := CopyFolderToDestWithOptions(folderPath, destRootFolder, tempFolderPrefix, options)
 	if err != nil {
 		return "", err
 	}
@@ -90,9 +101,40 @@ func CopyTerragruntFolderToTemp(folderPath string, tempFolderPrefix string) (str
 	return CopyTerragruntFolderToDest(folderPath, os.TempDir(), tempFolderPrefix)
 }
 
+// DefaultSkipDirs are the folders that are typically not worth copying to a temp folder: the folders in which Terraform
+// and Terragrunt download providers and modules, which can contain gigabytes of provider binaries, and git metadata.
+var DefaultSkipDirs = []string{".terraform", ".terragrunt-cache", ".git"}
+
+// CopyFolderOptions are the options for copying a folder with CopyFolderContentsWithOptions and
+// CopyFolderToDestWithOptions.
+type CopyFolderOptions struct {
+	// If set, only the files and folders for which Filter returns true are copied.
+	Filter func(path string) bool
+
+	// Glob patterns (relative to the folder being copied, e.g. "*.tf" or "modules/**/*.tf") of the files to copy. If set,
+	// only the files that match one of the patterns are copied, along with the folders that contain them.
+	Include []string
+
+	// Glob patterns (relative to the folder being copied) of the files and folders not to copy.
+	Exclude []string
+
+	// The names of the folders not to copy, wherever they are in the folder being copied (e.g. DefaultSkipDirs).
+	SkipDirs []string
+
+	// If true, symlinks are replaced with copies of the files and folders they point to. Otherwise, the symlinks
+	// themselves are copied. Broken symlinks are always copied as symlinks.
+	FollowSymlinks bool
+}
+
 // CopyFolderToDest creates a copy of the given folder and all its filtered contents in a temp folder
 // with a unique name and the given prefix.
 func CopyFolderToDest(folderPath string, destRootFolder string, tempFolderPrefix string, filter func(path string) bool) (string, error) {
+	return CopyFolderToDestWithOptions(folderPath, destRootFolder, tempFolderPrefix, CopyFolderOptions{Filter: filter})
+}
+
+// CopyFolderToDestWithOptions creates a copy of the given folder and its contents, as selected by the given options, in
+// a temp folder with a unique name and the given prefix.
+func CopyFolderToDestWithOptions(folderPath string, destRootFolder string, tempFolderPrefix string, options CopyFolderOptions) (string, error) {
 	destRootExists, err := FileExistsE(destRootFolder)
 	if err != nil {
 		return "", err
@@ -126,7 +168,7 @@ func CopyFolderToDest(folderPath string, destRootFolder string, tempFolderPrefix
 		return "", err
 	}
 
-	if err := CopyFolderContentsWithFilter(folderPath, destFolder, filter); err != nil {
+	if err := CopyFolderContentsWithOptions(folderPath, destFolder, options); err != nil {
 		return "", err
 	}
 
@@ -148,6 +190,27 @@ func CopyFolderContents(source string, destination string) error {
 // CopyFolderContentsWithFilter copies the files and folders within the given source folder that pass the given filter (return true) to the
 // destination folder.
 func CopyFolderContentsWithFilter(source string, destination string, filter func(path string) bool) error {
+	return CopyFolderContentsWithOptions(source, destination, CopyFolderOptions{Filter: filter})
+}
+
+// CopyFolderContentsWithOptions copies the files and folders within the given source folder, as selected by the given
+// options, to the destination folder.
+func CopyFolderContentsWithOptions(source string, destination string, options CopyFolderOptions) error {
+	copying := map[string]bool{}
+	if options.FollowSymlinks {
+		realSource, err := filepath.EvalSymlinks(source)
+		if err != nil {
+			return err
+		}
+		copying[realSource] = true
+	}
+	return copyFolderContents(source, source, destination, options, copying)
+}
+
+// copyFolderContents copies the files and folders within the given source folder, which is in the given root folder
+// being copied, to the destination folder. When following symlinks, copying contains the real paths of the folders
+// being copied, to detect symlinks that point to one of them.
+func copyFolderContents(root string, source string, destination string, options CopyFolderOptions, copying map[string]bool) error {
 	files, err := os.ReadDir(source)
 	if err != nil {
 		return err
@@ -156,19 +219,57 @@ func CopyFolderContentsWithFilter(source string, destination string, filter func
 	for _, file := range files {
 		src := filepath.Join(source, file.Name())
 		dest := filepath.Join(destination, file.Name())
-		f, _ := file.Info()
-		if !filter(src) {
+		f, err := file.Info()
+		if err != nil {
+			return err
+		}
+		if options.Filter != nil && !options.Filter(src) {
+			continue
+		}
+
+		relPath, err := filepath.Rel(root, src)
+		if err != nil {
+			return err
+		}
+		excluded, err := matchesAnyGlob(options.Exclude, relPath)
+		if err != nil {
+			return err
+		}
+		if excluded {
 			continue
-		} else if file.IsDir() {
-			if err := os.MkdirAll(dest, f.Mode()); err != nil {
+		}
+
+		if isSymLink(f) && options.FollowSymlinks {
+			target, err := os.Stat(src)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			if err == nil {
+				f = target
+			}
+		}
+
+		if f.IsDir() {
+			if containsString(options.SkipDirs, file.Name()) {
+				continue
+			}
+			if err := copyFolder(root, src, dest, f.Mode(), options, copying); err != nil {
 				return err
 			}
+			continue
+		}
 
-			if err := CopyFolderContentsWithFilter(src, dest, filter); err != nil {
+		if len(options.Include) > 0 {
+			included, err := matchesAnyGlob(options.Include, relPath)
+			if err != nil {
 				return err
 			}
+			if !included {
+				continue
+			}
+		}
 
-		} else if isSymLink(f) {
+		if isSymLink(f) {
 			if err := copySymLink(src, dest); err != nil {
 				return err
 			}
@@ -182,6 +283,67 @@ func CopyFolderContentsWithFilter(source string, destination string, filter func
 	return nil
 }
 
+// copyFolder copies the given source folder, which is in the given root folder being copied, to the destination
+// folder, which is created with the given mode. If the options include only some files, the destination folder is
+// removed if none of them were in it.
+func copyFolder(root string, source string, destination string, mode os.FileMode, options CopyFolderOptions, copying map[string]bool) error {
+	if options.FollowSymlinks {
+		realSource, err := filepath.EvalSymlinks(source)
+		if err != nil {
+			return err
+		}
+		if copying[realSource] {
+			return SymlinkCycleError{Path: source, Target: realSource}
+		}
+		copying[realSource] = true
+		defer delete(copying, realSource)
+	}
+
+	if err := os.MkdirAll(destination, mode); err != nil {
+		return err
+	}
+
+	if err := copyFolderContents(root, source, destination, options, copying); err != nil {
+		return err
+	}
+
+	if len(options.Include) > 0 {
+		entries, err := os.ReadDir(destination)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return os.Remove(destination)
+		}
+	}
+	return nil
+}
+
+// matchesAnyGlob returns true if the given path matches any of the given glob patterns, which can contain ** to match
+// any number of folders.
+func matchesAnyGlob(patterns []string, path string) (bool, error) {
+	for _, pattern := range patterns {
+		matched, err := zglob.Match(filepath.ToSlash(pattern), filepath.ToSlash(path))
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// containsString returns true if the given list contains the given string.
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
 // PathContainsTerraformStateOrVars returns true if the path corresponds to a Terraform state file or .tfvars/.tfvars.json file.
 func PathContainsTerraformStateOrVars(path string) bool {
 	filename := filepath.Base(path)
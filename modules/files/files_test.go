@@ -144,6 +144,63 @@ func TestCopyFolderContentsWithBrokenSymLinks(t *testing.T) {
 	fmt.Println("Test completed without error, however due to a limitation in GNU diff < 3.3.0, directories have not been compared for equivalency.")
 }
 
+func TestCopyFolderContentsWithOptions(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	originalDir := filepath.Join(rootDir, "original")
+	for path, contents := range map[string]string{
+		"original/main.tf":                               "main",
+		"original/README.md":                             "readme",
+		"original/modules/vpc/main.tf":                   "vpc",
+		"original/modules/vpc/.terraform/providers/aws":  "provider",
+		"original/node_modules/left-pad/index.js":        "js",
+		"original/node_modules/left-pad/terraform/io.tf": "tf",
+		"shared/variables.tf":                            "variables",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(rootDir, path)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(rootDir, path), []byte(contents), 0644))
+	}
+	require.NoError(t, os.Symlink(filepath.Join(rootDir, "shared"), filepath.Join(originalDir, "shared")))
+
+	options := CopyFolderOptions{
+		Exclude:  []string{"node_modules"},
+		SkipDirs: DefaultSkipDirs,
+	}
+
+	tmpDir := t.TempDir()
+	require.NoError(t, CopyFolderContentsWithOptions(originalDir, tmpDir, options))
+	assert.Equal(t, []string{"README.md", "main.tf", "modules/vpc/main.tf", "shared"}, listFiles(t, tmpDir))
+	sharedInfo, err := os.Lstat(filepath.Join(tmpDir, "shared"))
+	require.NoError(t, err)
+	assert.NotZero(t, sharedInfo.Mode()&os.ModeSymlink)
+
+	options.Include = []string{"**/*.tf"}
+	options.FollowSymlinks = true
+	tmpDir = t.TempDir()
+	require.NoError(t, CopyFolderContentsWithOptions(originalDir, tmpDir, options))
+	assert.Equal(t, []string{"main.tf", "modules/vpc/main.tf", "shared/variables.tf"}, listFiles(t, tmpDir))
+
+	require.NoError(t, os.Symlink(originalDir, filepath.Join(originalDir, "modules", "loop")))
+	err = CopyFolderContentsWithOptions(originalDir, t.TempDir(), options)
+	assert.ErrorAs(t, err, &SymlinkCycleError{})
+}
+
+// listFiles returns the paths, relative to the given folder, of the files and symlinks in it, sorted.
+func listFiles(t *testing.T, dir string) []string {
+	paths := []string{}
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		relPath, err := filepath.Rel(dir, path)
+		paths = append(paths, filepath.ToSlash(relPath))
+		return err
+	})
+	require.NoError(t, err)
+	return paths
+}
+
 func TestCopyTerraformFolderToTemp(t *testing.T) {
 	t.Parallel()
 
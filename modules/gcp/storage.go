@@ -2,8 +2,10 @@ package gcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	"cloud.google.com/go/storage"
 	"github.com/gruntwork-io/terratest/modules/logger"
@@ -89,6 +91,130 @@ func ReadBucketObjectE(t testing.TestingT, bucketName string, filePath string) (
 	return r, nil
 }
 
+// GetBucketObjectUpdatedTime returns when the object with the given path in the given Storage Bucket was last updated.
+func GetBucketObjectUpdatedTime(t testing.TestingT, bucketName string, filePath string) time.Time {
+	updated, err := GetBucketObjectUpdatedTimeE(t, bucketName, filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return updated
+}
+
+// GetBucketObjectUpdatedTimeE returns when the object with the given path in the given Storage Bucket was last updated.
+func GetBucketObjectUpdatedTimeE(t testing.TestingT, bucketName string, filePath string) (time.Time, error) {
+	ctx := context.Background()
+
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	attrs, err := client.Bucket(bucketName).Object(filePath).Attrs(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return attrs.Updated, nil
+}
+
+// DeleteBucketObject deletes an object from the given Storage Bucket.
+func DeleteBucketObject(t testing.TestingT, bucketName string, filePath string) {
+	err := DeleteBucketObjectE(t, bucketName, filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// DeleteBucketObjectE deletes an object from the given Storage Bucket. Deleting an object that doesn't exist is not an
+// error.
+func DeleteBucketObjectE(t testing.TestingT, bucketName string, filePath string) error {
+	logger.Default.Logf(t, "Deleting object from bucket %s using path %s", bucketName, filePath)
+
+	ctx := context.Background()
+
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = client.Bucket(bucketName).Object(filePath).Delete(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil
+	}
+	return err
+}
+
+// DeleteBucketObjectsWithPrefix deletes all the objects from the given Storage Bucket whose paths start with the given
+// prefix.
+func DeleteBucketObjectsWithPrefix(t testing.TestingT, bucketName string, prefix string) {
+	err := DeleteBucketObjectsWithPrefixE(t, bucketName, prefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// DeleteBucketObjectsWithPrefixE deletes all the objects from the given Storage Bucket whose paths start with the given
+// prefix.
+func DeleteBucketObjectsWithPrefixE(t testing.TestingT, bucketName string, prefix string) error {
+	logger.Default.Logf(t, "Deleting objects from bucket %s with prefix %s", bucketName, prefix)
+
+	ctx := context.Background()
+
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	bucket := client.Bucket(bucketName)
+	it := bucket.Objects(ctx, &storage.Query{Prefix: prefix})
+	for {
+		objectAttrs, err := it.Next()
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := bucket.Object(objectAttrs.Name).Delete(ctx); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			return err
+		}
+	}
+}
+
+// ListBucketObjectsWithPrefix returns the paths of all the objects in the given Storage Bucket that start with the
+// given prefix.
+func ListBucketObjectsWithPrefix(t testing.TestingT, bucketName string, prefix string) []string {
+	paths, err := ListBucketObjectsWithPrefixE(t, bucketName, prefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return paths
+}
+
+// ListBucketObjectsWithPrefixE returns the paths of all the objects in the given Storage Bucket that start with the
+// given prefix.
+func ListBucketObjectsWithPrefixE(t testing.TestingT, bucketName string, prefix string) ([]string, error) {
+	ctx := context.Background()
+
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	it := client.Bucket(bucketName).Objects(ctx, &storage.Query{Prefix: prefix})
+	for {
+		objectAttrs, err := it.Next()
+		if err == iterator.Done {
+			return paths, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, objectAttrs.Name)
+	}
+}
+
 // WriteBucketObject writes an object to the given Storage Bucket and returns its URL.
 func WriteBucketObject(t testing.TestingT, bucketName string, filePath string, body io.Reader, contentType string) string {
 	out, err := WriteBucketObjectE(t, bucketName, filePath, body, contentType)
@@ -1,6 +1,7 @@
 package helm
 
 import (
+	"context"
 	"testing"
 
 	"github.com/gruntwork-io/terratest/modules/k8s"
@@ -17,6 +18,7 @@ func TestPrepareHelmCommand(t *testing.T) {
 		},
 		EnvVars: map[string]string{"SampleEnv": "test_value"},
 		Logger:  logger.Default,
+		Context: context.Background(),
 	}
 	t.Run("command without additional args", func(t *testing.T) {
 		cmd := prepareHelmCommand(t, options, "install")
@@ -27,6 +29,7 @@ func TestPrepareHelmCommand(t *testing.T) {
 		assert.Equal(t, ".", cmd.WorkingDir)
 		assert.Equal(t, options.EnvVars, cmd.Env)
 		assert.Equal(t, options.Logger, cmd.Logger)
+		assert.Equal(t, options.Context, cmd.Context)
 	})
 	t.Run("Command with additional args", func(t *testing.T) {
 		cmd := prepareHelmCommand(t, options, "upgrade", "--install", "my-release", "my-chart")
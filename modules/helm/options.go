@@ -1,6 +1,8 @@
 package helm
 
 import (
+	"context"
+
 	"github.com/gruntwork-io/terratest/modules/k8s"
 	"github.com/gruntwork-io/terratest/modules/logger"
 )
@@ -19,4 +21,5 @@ type Options struct {
 	ExtraArgs         map[string][]string // Extra arguments to pass to the helm install/upgrade/rollback/delete and helm repo add commands. The key signals the command (e.g., install) while the values are the extra arguments to pass through.
 	BuildDependencies bool                // If true, helm dependencies will be built before rendering template, installing or upgrade the chart.
 	SnapshotPath      string              // The path to the snapshot directory when using snapshot based testing. Empty string means use default ($PWD/__snapshot__).
+	Context           context.Context     // If set, the helm commands are interrupted when the context is done (e.g. the context passed to the stage by test_structure.RunTestStageWithContext).
 }
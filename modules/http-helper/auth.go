@@ -0,0 +1,54 @@
+package http_helper
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Auth contains the credentials to send with an HTTP request. Use BasicAuth or BearerAuth to create one. The
+// credentials are never included in log output: formatting an Auth, e.g. with %v, only shows the type of
+// authentication and, for basic auth, the username.
+type Auth struct {
+	username string
+	password string
+	token    string
+}
+
+// BasicAuth returns an Auth that sends the given username and password using HTTP basic authentication.
+func BasicAuth(username string, password string) *Auth {
+	return &Auth{username: username, password: password}
+}
+
+// BearerAuth returns an Auth that sends the given token in an "Authorization: Bearer" header.
+func BearerAuth(token string) *Auth {
+	return &Auth{token: token}
+}
+
+// String returns a description of the Auth with the secret values redacted, so it is safe to log.
+func (auth *Auth) String() string {
+	if auth == nil {
+		return "no auth"
+	}
+	if auth.token != "" {
+		return "bearer auth (token redacted)"
+	}
+	return fmt.Sprintf("basic auth for user %s (password redacted)", auth.username)
+}
+
+// GoString returns the same redacted description as String, so that the secret values are not printed with %#v
+// either.
+func (auth *Auth) GoString() string {
+	return auth.String()
+}
+
+// apply sets the credentials on the given request. This is a no-op if auth is nil.
+func (auth *Auth) apply(req *http.Request) {
+	if auth == nil || req == nil {
+		return
+	}
+	if auth.token != "" {
+		req.Header.Set("Authorization", "Bearer "+auth.token)
+		return
+	}
+	req.SetBasicAuth(auth.username, auth.password)
+}
@@ -0,0 +1,66 @@
+package http_helper
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func authCheckHandler(w http.ResponseWriter, r *http.Request) {
+	if username, password, ok := r.BasicAuth(); ok && username == "admin" && password == "s3cr3t" {
+		w.Write([]byte("basic ok"))
+		return
+	}
+	if r.Header.Get("Authorization") == "Bearer t0k3n" {
+		w.Write([]byte("bearer ok"))
+		return
+	}
+	w.WriteHeader(http.StatusUnauthorized)
+}
+
+func TestHttpGetWithRetryAndBasicAuth(t *testing.T) {
+	t.Parallel()
+	ts := getTestServerForFunction(authCheckHandler)
+	defer ts.Close()
+
+	HttpGetWithRetryAndAuth(t, ts.URL, nil, BasicAuth("admin", "s3cr3t"), 200, "basic ok", 3, time.Second)
+}
+
+func TestHttpGetWithRetryAndBearerAuth(t *testing.T) {
+	t.Parallel()
+	ts := getTestServerForFunction(authCheckHandler)
+	defer ts.Close()
+
+	HttpGetWithRetryAndAuth(t, ts.URL, nil, BearerAuth("t0k3n"), 200, "bearer ok", 3, time.Second)
+}
+
+func TestHTTPDoWithBadAuth(t *testing.T) {
+	t.Parallel()
+	ts := getTestServerForFunction(authCheckHandler)
+	defer ts.Close()
+
+	statusCode, _, err := HTTPDoWithOptionsE(t, HttpDoOptions{
+		Method:  "GET",
+		Url:     ts.URL,
+		Timeout: 10,
+		Auth:    BasicAuth("admin", "wrong"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+}
+
+func TestAuthIsRedactedWhenFormatted(t *testing.T) {
+	t.Parallel()
+
+	for _, auth := range []*Auth{BasicAuth("admin", "s3cr3t"), BearerAuth("t0k3n")} {
+		for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+			formatted := fmt.Sprintf(format, auth)
+			assert.NotContains(t, formatted, "s3cr3t")
+			assert.NotContains(t, formatted, "t0k3n")
+		}
+	}
+}
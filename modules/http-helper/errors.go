@@ -1,6 +1,9 @@
 package http_helper
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // ValidationFunctionFailed is an error that occurs if a validation function fails.
 type ValidationFunctionFailed struct {
@@ -12,3 +15,14 @@ type ValidationFunctionFailed struct {
 func (err ValidationFunctionFailed) Error() string {
 	return fmt.Sprintf("Validation failed for URL %s. Response status: %d. Response body:\n%s", err.Url, err.Status, err.Body)
 }
+
+// LatencyThresholdExceeded is an error that occurs if a response takes longer than the maximum latency.
+type LatencyThresholdExceeded struct {
+	Url        string
+	Latency    time.Duration
+	MaxLatency time.Duration
+}
+
+func (err LatencyThresholdExceeded) Error() string {
+	return fmt.Sprintf("Response from URL %s took %s, which is longer than the maximum latency of %s", err.Url, err.Latency, err.MaxLatency)
+}
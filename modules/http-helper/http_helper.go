@@ -19,6 +19,7 @@ type HttpGetOptions struct {
 	Url       string
 	TlsConfig *tls.Config
 	Timeout   int
+	Auth      *Auth // Optional credentials to send with the request. See BasicAuth and BearerAuth.
 }
 
 type HttpDoOptions struct {
@@ -28,6 +29,7 @@ type HttpDoOptions struct {
 	Headers   map[string]string
 	TlsConfig *tls.Config
 	Timeout   int
+	Auth      *Auth // Optional credentials to send with the request. See BasicAuth and BearerAuth.
 }
 
 // HttpGet performs an HTTP GET, with an optional pointer to a custom TLS configuration, on the given URL and
@@ -68,7 +70,13 @@ func HttpGetWithOptionsE(t testing.TestingT, options HttpGetOptions) (int, strin
 		Transport: tr,
 	}
 
-	resp, err := client.Get(options.Url)
+	req, err := http.NewRequest(http.MethodGet, options.Url, nil)
+	if err != nil {
+		return -1, "", err
+	}
+	options.Auth.apply(req)
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return -1, "", err
 	}
@@ -180,6 +188,20 @@ func HttpGetWithRetryWithOptionsE(t testing.TestingT, options HttpGetOptions, ex
 	return err
 }
 
+// HttpGetWithRetryAndAuth repeatedly performs an HTTP GET on the given URL, authenticating with the given credentials,
+// until the given status code and body are returned or until max retries has been exceeded.
+func HttpGetWithRetryAndAuth(t testing.TestingT, url string, tlsConfig *tls.Config, auth *Auth, expectedStatus int, expectedBody string, retries int, sleepBetweenRetries time.Duration) {
+	options := HttpGetOptions{Url: url, TlsConfig: tlsConfig, Timeout: 10, Auth: auth}
+	HttpGetWithRetryWithOptions(t, options, expectedStatus, expectedBody, retries, sleepBetweenRetries)
+}
+
+// HttpGetWithRetryAndAuthE repeatedly performs an HTTP GET on the given URL, authenticating with the given credentials,
+// until the given status code and body are returned or until max retries has been exceeded.
+func HttpGetWithRetryAndAuthE(t testing.TestingT, url string, tlsConfig *tls.Config, auth *Auth, expectedStatus int, expectedBody string, retries int, sleepBetweenRetries time.Duration) error {
+	options := HttpGetOptions{Url: url, TlsConfig: tlsConfig, Timeout: 10, Auth: auth}
+	return HttpGetWithRetryWithOptionsE(t, options, expectedStatus, expectedBody, retries, sleepBetweenRetries)
+}
+
 // HttpGetWithRetryWithCustomValidation repeatedly performs an HTTP GET on the given URL until the given validation function returns true or max retries
 // has been exceeded.
 func HttpGetWithRetryWithCustomValidation(t testing.TestingT, url string, tlsConfig *tls.Config, retries int, sleepBetweenRetries time.Duration, validateResponse func(int, string) bool) {
@@ -272,6 +294,7 @@ func HTTPDoWithOptionsE(
 	}
 
 	req := newRequest(options.Method, options.Url, options.Body, options.Headers)
+	options.Auth.apply(req)
 	resp, err := client.Do(req)
 	if err != nil {
 		return -1, "", err
@@ -536,6 +559,127 @@ func HTTPDoWithCustomValidationWithOptionsE(t testing.TestingT, options HttpDoOp
 	return nil
 }
 
+// HTTPDoWithLatencyCheck performs the given HTTP method on the given URL and returns the time it took to get the full
+// response back, from sending the request to reading the whole body. If there's any error or the response takes longer
+// than maxLatency, fail the test.
+func HTTPDoWithLatencyCheck(
+	t testing.TestingT, method string, url string, body io.Reader,
+	headers map[string]string, maxLatency time.Duration, tlsConfig *tls.Config,
+) time.Duration {
+	options := HttpDoOptions{
+		Method:    method,
+		Url:       url,
+		Body:      body,
+		Headers:   headers,
+		TlsConfig: tlsConfig,
+		Timeout:   10}
+	return HTTPDoWithLatencyCheckWithOptions(t, options, maxLatency)
+}
+
+// HTTPDoWithLatencyCheckWithOptions performs the given HTTP method on the given URL and returns the time it took to get
+// the full response back, from sending the request to reading the whole body. If there's any error or the response
+// takes longer than maxLatency, fail the test.
+func HTTPDoWithLatencyCheckWithOptions(t testing.TestingT, options HttpDoOptions, maxLatency time.Duration) time.Duration {
+	latency, err := HTTPDoWithLatencyCheckWithOptionsE(t, options, maxLatency)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return latency
+}
+
+// HTTPDoWithLatencyCheckE performs the given HTTP method on the given URL and returns the time it took to get the full
+// response back, from sending the request to reading the whole body. Returns a LatencyThresholdExceeded error, along
+// with the measured latency, if the response takes longer than maxLatency.
+func HTTPDoWithLatencyCheckE(
+	t testing.TestingT, method string, url string, body io.Reader,
+	headers map[string]string, maxLatency time.Duration, tlsConfig *tls.Config,
+) (time.Duration, error) {
+	options := HttpDoOptions{
+		Method:    method,
+		Url:       url,
+		Body:      body,
+		Headers:   headers,
+		TlsConfig: tlsConfig,
+		Timeout:   10}
+	return HTTPDoWithLatencyCheckWithOptionsE(t, options, maxLatency)
+}
+
+// HTTPDoWithLatencyCheckWithOptionsE performs the given HTTP method on the given URL and returns the time it took to
+// get the full response back, from sending the request to reading the whole body. Returns a LatencyThresholdExceeded
+// error, along with the measured latency, if the response takes longer than maxLatency.
+func HTTPDoWithLatencyCheckWithOptionsE(t testing.TestingT, options HttpDoOptions, maxLatency time.Duration) (time.Duration, error) {
+	start := time.Now()
+	if _, _, err := HTTPDoWithOptionsE(t, options); err != nil {
+		return 0, err
+	}
+	latency := time.Since(start)
+
+	logger.Default.Logf(t, "HTTP %s to URL %s took %s", options.Method, options.Url, latency)
+	if latency > maxLatency {
+		return latency, LatencyThresholdExceeded{Url: options.Url, Latency: latency, MaxLatency: maxLatency}
+	}
+	return latency, nil
+}
+
+// HTTPDoWithLatencyCheckRetryWithOptions repeatedly performs the given HTTP method on the given URL until
+// consecutiveFastResponses responses in a row came back within maxLatency, or until max retries has been exceeded, and
+// returns the latency of the last response. Requiring several fast responses in a row avoids failing on a single slow
+// response, e.g. due to a cold start, without letting a single fast response hide a slow endpoint. If the responses
+// never get fast enough, fail the test.
+func HTTPDoWithLatencyCheckRetryWithOptions(
+	t testing.TestingT, options HttpDoOptions, maxLatency time.Duration,
+	consecutiveFastResponses int, retries int, sleepBetweenRetries time.Duration,
+) time.Duration {
+	latency, err := HTTPDoWithLatencyCheckRetryWithOptionsE(t, options, maxLatency, consecutiveFastResponses, retries, sleepBetweenRetries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return latency
+}
+
+// HTTPDoWithLatencyCheckRetryWithOptionsE repeatedly performs the given HTTP method on the given URL until
+// consecutiveFastResponses responses in a row came back within maxLatency, or until max retries has been exceeded, and
+// returns the latency of the last response. Requiring several fast responses in a row avoids failing on a single slow
+// response, e.g. due to a cold start, without letting a single fast response hide a slow endpoint.
+func HTTPDoWithLatencyCheckRetryWithOptionsE(
+	t testing.TestingT, options HttpDoOptions, maxLatency time.Duration,
+	consecutiveFastResponses int, retries int, sleepBetweenRetries time.Duration,
+) (time.Duration, error) {
+	var data []byte
+	if options.Body != nil {
+		// The request body is closed after a request is complete.
+		// Read the underlying data and cache it, so we can reuse for retried requests.
+		b, err := io.ReadAll(options.Body)
+		if err != nil {
+			return 0, err
+		}
+		data = b
+	}
+
+	fastResponses := 0
+	out, err := retry.DoWithRetryInterfaceE(
+		t, fmt.Sprintf("HTTP %s to URL %s within %s", options.Method, options.Url, maxLatency), retries,
+		sleepBetweenRetries, func() (interface{}, error) {
+			options.Body = bytes.NewReader(data)
+			latency, err := HTTPDoWithLatencyCheckWithOptionsE(t, options, maxLatency)
+			if err != nil {
+				fastResponses = 0
+				return nil, err
+			}
+
+			fastResponses++
+			if fastResponses < consecutiveFastResponses {
+				return nil, fmt.Errorf("got %d of %d consecutive responses within %s", fastResponses, consecutiveFastResponses, maxLatency)
+			}
+			return latency, nil
+		})
+	if err != nil {
+		return 0, err
+	}
+
+	return out.(time.Duration), nil
+}
+
 func newRequest(method string, url string, body io.Reader, headers map[string]string) *http.Request {
 	req, err := http.NewRequest(method, url, body)
 	if err != nil {
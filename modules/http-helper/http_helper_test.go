@@ -155,12 +155,66 @@ func TestEmptyRequestBodyWithRetryWithOptions(t *testing.T) {
 	require.Equal(t, "", response)
 }
 
+func TestHTTPDoWithLatencyCheck(t *testing.T) {
+	t.Parallel()
+	ts := getTestServerForFunction(bodyCopyHandler)
+	defer ts.Close()
+
+	latency := HTTPDoWithLatencyCheck(t, "GET", ts.URL, nil, nil, 5*time.Second, nil)
+	assert.Less(t, latency, 5*time.Second)
+}
+
+func TestHTTPDoWithLatencyCheckEMeasuresBodyRead(t *testing.T) {
+	t.Parallel()
+	ts := getTestServerForFunction(slowBodyHandler)
+	defer ts.Close()
+
+	latency, err := HTTPDoWithLatencyCheckE(t, "GET", ts.URL, nil, nil, 50*time.Millisecond, nil)
+	require.Error(t, err)
+	assert.GreaterOrEqual(t, latency, 200*time.Millisecond)
+	assert.Equal(t, LatencyThresholdExceeded{Url: ts.URL, Latency: latency, MaxLatency: 50 * time.Millisecond}, err)
+}
+
+func TestHTTPDoWithLatencyCheckRetryWithOptionsIgnoresColdStart(t *testing.T) {
+	t.Parallel()
+	requests := 0
+	ts := getTestServerForFunction(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			time.Sleep(300 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	defer ts.Close()
+
+	options := HttpDoOptions{Method: "GET", Url: ts.URL, Timeout: 10}
+	HTTPDoWithLatencyCheckRetryWithOptions(t, options, 200*time.Millisecond, 3, 5, 10*time.Millisecond)
+	assert.Equal(t, 4, requests)
+}
+
+func TestHTTPDoWithLatencyCheckRetryWithOptionsEFailsWhenNeverFastEnough(t *testing.T) {
+	t.Parallel()
+	ts := getTestServerForFunction(slowBodyHandler)
+	defer ts.Close()
+
+	options := HttpDoOptions{Method: "GET", Url: ts.URL, Timeout: 10}
+	_, err := HTTPDoWithLatencyCheckRetryWithOptionsE(t, options, 50*time.Millisecond, 2, 2, 10*time.Millisecond)
+	require.Error(t, err)
+}
+
 func bodyCopyHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	body, _ := io.ReadAll(r.Body)
 	w.Write(body)
 }
 
+func slowBodyHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+	time.Sleep(200 * time.Millisecond)
+	w.Write([]byte("slow body"))
+}
+
 func headersCopyHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	var buffer bytes.Buffer
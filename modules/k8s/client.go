@@ -3,6 +3,7 @@ package k8s
 import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
 
 	// The following line loads the gcp plugin which is required to authenticate against GKE clusters.
 	// See: https://github.com/kubernetes/client-go/issues/242
@@ -24,6 +25,33 @@ func GetKubernetesClientE(t testing.TestingT) (*kubernetes.Clientset, error) {
 
 // GetKubernetesClientFromOptionsE returns a Kubernetes API client given a configured KubectlOptions object.
 func GetKubernetesClientFromOptionsE(t testing.TestingT, options *KubectlOptions) (*kubernetes.Clientset, error) {
+	config, err := getRestConfigFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return clientset, nil
+}
+
+// GetMetricsClientFromOptionsE returns a client for the resource metrics API (metrics.k8s.io), which is served by the
+// metrics-server, given a configured KubectlOptions object.
+func GetMetricsClientFromOptionsE(t testing.TestingT, options *KubectlOptions) (*metricsclientset.Clientset, error) {
+	config, err := getRestConfigFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return metricsclientset.NewForConfig(config)
+}
+
+// getRestConfigFromOptionsE returns the config to create Kubernetes API clients with given a configured KubectlOptions
+// object.
+func getRestConfigFromOptionsE(t testing.TestingT, options *KubectlOptions) (*rest.Config, error) {
 	var err error
 	var config *rest.Config
 
@@ -54,10 +82,5 @@ func GetKubernetesClientFromOptionsE(t testing.TestingT, options *KubectlOptions
 		}
 	}
 
-	clientset, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		return nil, err
-	}
-
-	return clientset, nil
+	return config, nil
 }
@@ -32,10 +32,17 @@ func GetConfigMapE(t testing.TestingT, options *KubectlOptions, configMapName st
 }
 
 // WaitUntilConfigMapAvailable waits until the configmap is present on the cluster in cases where it is not immediately
-// available (for example, when using ClusterIssuer to request a certificate).
+// available (for example, when using ClusterIssuer to request a certificate). This will fail the test if the check
+// times out.
 func WaitUntilConfigMapAvailable(t testing.TestingT, options *KubectlOptions, configMapName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilConfigMapAvailableE(t, options, configMapName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilConfigMapAvailableE waits until the configmap is present on the cluster in cases where it is not immediately
+// available (for example, when using ClusterIssuer to request a certificate).
+func WaitUntilConfigMapAvailableE(t testing.TestingT, options *KubectlOptions, configMapName string, retries int, sleepBetweenRetries time.Duration) error {
 	statusMsg := fmt.Sprintf("Wait for configmap %s to be provisioned.", configMapName)
-	message := retry.DoWithRetry(
+	message, err := retry.DoWithRetryE(
 		t,
 		statusMsg,
 		retries,
@@ -49,5 +56,10 @@ func WaitUntilConfigMapAvailable(t testing.TestingT, options *KubectlOptions, co
 			return "configmap is now available", nil
 		},
 	)
+	if err != nil {
+		options.Logger.Logf(t, "Timedout waiting for configmap to be provisioned: %s", err)
+		return err
+	}
 	options.Logger.Logf(t, message)
+	return nil
 }
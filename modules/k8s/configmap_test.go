@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/gruntwork-io/terratest/modules/random"
 )
@@ -26,6 +27,7 @@ func TestGetConfigMapEReturnsErrorForNonExistantConfigMap(t *testing.T) {
 	options := NewKubectlOptions("", "", "default")
 	_, err := GetConfigMapE(t, options, "test-config-map")
 	require.Error(t, err)
+	require.True(t, errors.IsNotFound(err))
 }
 
 func TestGetConfigMapEReturnsCorrectConfigMapInCorrectNamespace(t *testing.T) {
@@ -54,6 +56,14 @@ func TestWaitUntilConfigMapAvailableReturnsSuccessfully(t *testing.T) {
 	WaitUntilConfigMapAvailable(t, options, "test-config-map", 10, 1*time.Second)
 }
 
+func TestWaitUntilConfigMapAvailableEReturnsErrorForNonExistantConfigMap(t *testing.T) {
+	t.Parallel()
+
+	options := NewKubectlOptions("", "", "default")
+	err := WaitUntilConfigMapAvailableE(t, options, "test-config-map-never-created", 2, 1*time.Second)
+	require.Error(t, err)
+}
+
 const EXAMPLE_CONFIGMAP_YAML_TEMPLATE = `---
 apiVersion: v1
 kind: Namespace
@@ -0,0 +1,287 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// mirrorPodAnnotation is set by the kubelet on the API server representation of static pods. These can't be evicted.
+const mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+// DefaultDrainTimeout is how long DrainNode retries the evictions that a PodDisruptionBudget refuses if
+// DrainOptions.Timeout is not set.
+const DefaultDrainTimeout = 5 * time.Minute
+
+// drainRetryInterval is how long DrainNode waits before retrying the evictions that a PodDisruptionBudget refused.
+const drainRetryInterval = 5 * time.Second
+
+// DrainOptions controls how the pods of a node are evicted when draining it, similar to the flags of `kubectl drain`.
+type DrainOptions struct {
+	// Skip pods managed by a DaemonSet instead of refusing to drain the node. DaemonSet pods would be recreated on
+	// the node right away, so they can't be evicted.
+	IgnoreDaemonSets bool
+
+	// Evict pods that use emptyDir volumes, deleting their local data, instead of refusing to drain the node.
+	DeleteLocalData bool
+
+	// The grace period to give each evicted pod to terminate. If nil, the grace period of each pod is used.
+	GracePeriodSeconds *int64
+
+	// How long DrainNode retries the evictions that a PodDisruptionBudget refuses (with HTTP 429) before giving up, e.g.
+	// while the pods of another node that the budget covers are being rescheduled. Defaults to DefaultDrainTimeout.
+	Timeout time.Duration
+}
+
+// CordonNode marks the given node as unschedulable, so that no new pods are scheduled on it. This will fail the test
+// if there is an error.
+func CordonNode(t testing.TestingT, options *KubectlOptions, nodeName string) {
+	require.NoError(t, CordonNodeE(t, options, nodeName))
+}
+
+// CordonNodeE marks the given node as unschedulable, so that no new pods are scheduled on it.
+func CordonNodeE(t testing.TestingT, options *KubectlOptions, nodeName string) error {
+	return setNodeUnschedulableE(t, options, nodeName, true)
+}
+
+// UncordonNode marks the given node as schedulable again. This will fail the test if there is an error.
+func UncordonNode(t testing.TestingT, options *KubectlOptions, nodeName string) {
+	require.NoError(t, UncordonNodeE(t, options, nodeName))
+}
+
+// UncordonNodeE marks the given node as schedulable again.
+func UncordonNodeE(t testing.TestingT, options *KubectlOptions, nodeName string) error {
+	return setNodeUnschedulableE(t, options, nodeName, false)
+}
+
+func setNodeUnschedulableE(t testing.TestingT, options *KubectlOptions, nodeName string, unschedulable bool) error {
+	options.Logger.Logf(t, "Setting unschedulable=%t on node %s", unschedulable, nodeName)
+
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return err
+	}
+
+	node, err := clientset.CoreV1().Nodes().Get(context.Background(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if node.Spec.Unschedulable == unschedulable {
+		return nil
+	}
+
+	node.Spec.Unschedulable = unschedulable
+	_, err = clientset.CoreV1().Nodes().Update(context.Background(), node, metav1.UpdateOptions{})
+	return err
+}
+
+// DrainNode cordons the given node and requests the eviction of all of its pods through the eviction API, which
+// respects PodDisruptionBudgets. Evictions that a PodDisruptionBudget refuses are retried until DrainOptions.Timeout.
+// This does not wait for the evicted pods to terminate; use WaitUntilNodeDrained for that. This will fail the test if
+// there is an error or if the eviction of any pod is still blocked by a PodDisruptionBudget after the timeout.
+func DrainNode(t testing.TestingT, options *KubectlOptions, nodeName string, drainOptions DrainOptions) {
+	require.NoError(t, DrainNodeE(t, options, nodeName, drainOptions))
+}
+
+// DrainNodeE cordons the given node and requests the eviction of all of its pods through the eviction API, which
+// respects PodDisruptionBudgets. Evictions that a PodDisruptionBudget refuses are retried until DrainOptions.Timeout.
+// This does not wait for the evicted pods to terminate; use WaitUntilNodeDrained for that. Returns a PodNotEvictable
+// error if a pod can't be evicted with the given drain options, and a NodeNotDrained error listing the blocked pods if
+// the eviction of any pod is still blocked by a PodDisruptionBudget after the timeout.
+func DrainNodeE(t testing.TestingT, options *KubectlOptions, nodeName string, drainOptions DrainOptions) error {
+	options.Logger.Logf(t, "Draining node %s", nodeName)
+
+	if err := CordonNodeE(t, options, nodeName); err != nil {
+		return err
+	}
+
+	timeout := drainOptions.Timeout
+	if timeout <= 0 {
+		timeout = DefaultDrainTimeout
+	}
+
+	statusMsg := fmt.Sprintf("Evict the pods of node %s.", nodeName)
+	var lastErr error
+	_, err := retry.DoWithRetryE(
+		t,
+		statusMsg,
+		int(timeout/drainRetryInterval),
+		drainRetryInterval,
+		func() (string, error) {
+			result, err := evictPodsFromNodeE(t, options, nodeName, drainOptions)
+			if err != nil {
+				return "", err
+			}
+			if len(result.blockedPods) > 0 {
+				lastErr = NodeNotDrained{NodeName: nodeName, RemainingPods: result.remainingPods, BlockedPods: result.blockedPods}
+				return "", lastErr
+			}
+			return "All the pods of the node were evicted", nil
+		},
+	)
+	return getDrainError(err, lastErr)
+}
+
+// WaitUntilNodeDrained waits until all the pods that DrainNode evicts have left the given node, retrying evictions
+// that were blocked by a PodDisruptionBudget on each try. This will retry the check for the specified amount of
+// times, sleeping for the provided duration between each try. This will fail the test if the retry times out.
+func WaitUntilNodeDrained(t testing.TestingT, options *KubectlOptions, nodeName string, drainOptions DrainOptions, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilNodeDrainedE(t, options, nodeName, drainOptions, retries, sleepBetweenRetries))
+}
+
+// WaitUntilNodeDrainedE waits until all the pods that DrainNode evicts have left the given node, retrying evictions
+// that were blocked by a PodDisruptionBudget on each try. This will retry the check for the specified amount of
+// times, sleeping for the provided duration between each try. On timeout, the returned NodeNotDrained error lists the
+// pods that were still on the node and the pods whose eviction was blocked by a PodDisruptionBudget.
+func WaitUntilNodeDrainedE(t testing.TestingT, options *KubectlOptions, nodeName string, drainOptions DrainOptions, retries int, sleepBetweenRetries time.Duration) error {
+	statusMsg := fmt.Sprintf("Wait for node %s to be drained.", nodeName)
+	var lastErr error
+	message, err := retry.DoWithRetryE(
+		t,
+		statusMsg,
+		retries,
+		sleepBetweenRetries,
+		func() (string, error) {
+			result, err := evictPodsFromNodeE(t, options, nodeName, drainOptions)
+			if err != nil {
+				return "", err
+			}
+			if len(result.remainingPods) > 0 {
+				lastErr = NodeNotDrained{NodeName: nodeName, RemainingPods: result.remainingPods, BlockedPods: result.blockedPods}
+				return "", lastErr
+			}
+			return "Node is now drained", nil
+		},
+	)
+	if err != nil {
+		options.Logger.Logf(t, "Timedout waiting for node to be drained: %s", err)
+		return getDrainError(err, lastErr)
+	}
+	options.Logger.Logf(t, message)
+	return nil
+}
+
+// getDrainError returns the error to report for the given error of a drain retry loop: the underlying error of a
+// FatalError (e.g. PodNotEvictable), or the last NodeNotDrained error if the retries ran out.
+func getDrainError(err error, lastErr error) error {
+	if err == nil {
+		return nil
+	}
+	if fatalErr, isFatalErr := err.(retry.FatalError); isFatalErr {
+		return fatalErr.Underlying
+	}
+	if lastErr != nil {
+		return lastErr
+	}
+	return err
+}
+
+// evictionResult contains the names (namespace/name) of the pods that were still on a node during an eviction pass.
+type evictionResult struct {
+	remainingPods []string
+	blockedPods   []string
+}
+
+// evictPodsFromNodeE requests the eviction of all the evictable pods on the given node that are not already
+// terminating. Pods whose eviction is blocked by a PodDisruptionBudget are reported in the result rather than as an
+// error, so that the eviction can be retried.
+func evictPodsFromNodeE(t testing.TestingT, options *KubectlOptions, nodeName string, drainOptions DrainOptions) (evictionResult, error) {
+	result := evictionResult{}
+
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return result, err
+	}
+
+	pods, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(
+		context.Background(),
+		metav1.ListOptions{FieldSelector: fmt.Sprintf("spec.nodeName=%s", nodeName)},
+	)
+	if err != nil {
+		return result, err
+	}
+
+	evictablePods, err := filterPodsToEvict(pods.Items, drainOptions)
+	if err != nil {
+		return result, retry.FatalError{Underlying: err}
+	}
+
+	for _, pod := range evictablePods {
+		podName := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+		result.remainingPods = append(result.remainingPods, podName)
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		eviction := &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      pod.Name,
+				Namespace: pod.Namespace,
+			},
+			DeleteOptions: &metav1.DeleteOptions{
+				GracePeriodSeconds: drainOptions.GracePeriodSeconds,
+			},
+		}
+		err := clientset.PolicyV1().Evictions(pod.Namespace).Evict(context.Background(), eviction)
+		switch {
+		case err == nil, apierrors.IsNotFound(err):
+			options.Logger.Logf(t, "Evicted pod %s from node %s", podName, nodeName)
+		case apierrors.IsTooManyRequests(err):
+			// The eviction API returns 429 when evicting the pod would violate a PodDisruptionBudget.
+			result.blockedPods = append(result.blockedPods, podName)
+		default:
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// filterPodsToEvict returns the pods that have to be evicted to drain a node. Mirror pods and pods that have already
+// completed are skipped. Returns a PodNotEvictable error if there is a pod that the given DrainOptions don't allow to
+// be evicted or skipped.
+func filterPodsToEvict(pods []corev1.Pod, drainOptions DrainOptions) ([]corev1.Pod, error) {
+	evictablePods := []corev1.Pod{}
+	for _, pod := range pods {
+		if _, isMirrorPod := pod.Annotations[mirrorPodAnnotation]; isMirrorPod {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if isDaemonSetPod(pod) {
+			if drainOptions.IgnoreDaemonSets {
+				continue
+			}
+			return nil, PodNotEvictable{Namespace: pod.Namespace, Name: pod.Name, Reason: "it is managed by a DaemonSet (set IgnoreDaemonSets to skip it)"}
+		}
+		if hasLocalData(pod) && !drainOptions.DeleteLocalData {
+			return nil, PodNotEvictable{Namespace: pod.Namespace, Name: pod.Name, Reason: "it uses an emptyDir volume (set DeleteLocalData to evict it anyway)"}
+		}
+		evictablePods = append(evictablePods, pod)
+	}
+	return evictablePods, nil
+}
+
+func isDaemonSetPod(pod corev1.Pod) bool {
+	controller := metav1.GetControllerOf(&pod)
+	return controller != nil && controller.Kind == "DaemonSet"
+}
+
+func hasLocalData(pod corev1.Pod) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.EmptyDir != nil {
+			return true
+		}
+	}
+	return false
+}
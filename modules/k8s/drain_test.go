@@ -0,0 +1,74 @@
+package k8s
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gruntwork-io/terratest/modules/retry"
+)
+
+func newDrainTestPod(name string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestFilterPodsToEvict(t *testing.T) {
+	t.Parallel()
+
+	isController := true
+	daemonSetPod := newDrainTestPod("daemonset-pod")
+	daemonSetPod.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "agent", Controller: &isController}}
+
+	mirrorPod := newDrainTestPod("mirror-pod")
+	mirrorPod.Annotations = map[string]string{mirrorPodAnnotation: "hash"}
+
+	completedPod := newDrainTestPod("completed-pod")
+	completedPod.Status.Phase = corev1.PodSucceeded
+
+	localDataPod := newDrainTestPod("local-data-pod")
+	localDataPod.Spec.Volumes = []corev1.Volume{{Name: "cache", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+
+	pods := []corev1.Pod{newDrainTestPod("web"), daemonSetPod, mirrorPod, completedPod, localDataPod}
+
+	evictablePods, err := filterPodsToEvict(pods, DrainOptions{IgnoreDaemonSets: true, DeleteLocalData: true})
+	require.NoError(t, err)
+	require.Len(t, evictablePods, 2)
+	assert.Equal(t, "web", evictablePods[0].Name)
+	assert.Equal(t, "local-data-pod", evictablePods[1].Name)
+
+	_, err = filterPodsToEvict(pods, DrainOptions{DeleteLocalData: true})
+	assert.Equal(t, PodNotEvictable{Namespace: "default", Name: "daemonset-pod", Reason: "it is managed by a DaemonSet (set IgnoreDaemonSets to skip it)"}, err)
+
+	_, err = filterPodsToEvict(pods, DrainOptions{IgnoreDaemonSets: true})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "local-data-pod")
+}
+
+func TestErrorNodeNotDrained(t *testing.T) {
+	t.Parallel()
+
+	err := NodeNotDrained{NodeName: "node-1", RemainingPods: []string{"default/web-0", "default/web-1"}, BlockedPods: []string{"default/web-1"}}
+	assert.EqualError(t, err, "Node node-1 is not drained, remaining pods: [default/web-0, default/web-1], eviction blocked by a PodDisruptionBudget for pods: [default/web-1]")
+}
+
+func TestGetDrainError(t *testing.T) {
+	t.Parallel()
+
+	notEvictable := PodNotEvictable{Namespace: "default", Name: "web-0", Reason: "it is managed by a DaemonSet"}
+	notDrained := NodeNotDrained{NodeName: "node-1", RemainingPods: []string{"default/web-1"}, BlockedPods: []string{"default/web-1"}}
+	maxRetriesExceeded := retry.MaxRetriesExceeded{Description: "Evict the pods of node node-1.", MaxRetries: 1}
+
+	assert.NoError(t, getDrainError(nil, notDrained))
+	assert.Equal(t, notEvictable, getDrainError(retry.FatalError{Underlying: notEvictable}, nil))
+	assert.Equal(t, notDrained, getDrainError(maxRetriesExceeded, notDrained))
+
+	otherErr := errors.New("connection refused")
+	assert.Equal(t, otherErr, getDrainError(otherErr, nil))
+}
@@ -0,0 +1,89 @@
+package k8s
+
+import (
+	"encoding/base64"
+	"os"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// NewKubectlOptionsForEksCluster returns KubectlOptions to talk to the EKS cluster with the given name in the given
+// region as the current AWS identity, in the given namespace. See NewKubectlOptionsForEksClusterE for details. This will
+// fail the test if there are any errors.
+func NewKubectlOptionsForEksCluster(t testing.TestingT, region string, clusterName string, namespace string) *KubectlOptions {
+	options, err := NewKubectlOptionsForEksClusterE(t, region, clusterName, namespace)
+	require.NoError(t, err)
+	return options
+}
+
+// NewKubectlOptionsForEksClusterE returns KubectlOptions to talk to the EKS cluster with the given name in the given
+// region as the current AWS identity, in the given namespace. The endpoint and certificate authority of the cluster are
+// written, along with a token from aws.GetEksToken, to a new kubeconfig file in the temp dir, like `aws eks
+// update-kubeconfig` does, so that both kubectl and the Kubernetes API clients can use the options. The context is named
+// after the ARN of the cluster. Note that the token expires after 15 minutes, so call this function again to refresh
+// it in longer tests. The kubeconfig file (ConfigPath) is deleted when the test finishes, if t supports Cleanup (as
+// *testing.T does), and should otherwise be deleted by the caller.
+func NewKubectlOptionsForEksClusterE(t testing.TestingT, region string, clusterName string, namespace string) (*KubectlOptions, error) {
+	cluster, err := aws.GetEksClusterE(t, region, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	token, err := aws.GetEksTokenE(t, region, clusterName)
+	if err != nil {
+		return nil, err
+	}
+	config, err := newEksKubeConfig(cluster, token)
+	if err != nil {
+		return nil, err
+	}
+
+	configPath, err := writeTempKubeConfigE(t, config)
+	if err != nil {
+		return nil, err
+	}
+	return NewKubectlOptions(cluster.Arn, configPath, namespace), nil
+}
+
+// writeTempKubeConfigE writes the given kubeconfig to a new file in the temp dir, which is deleted when the given test
+// finishes if it supports Cleanup, and returns the path of the file.
+func writeTempKubeConfigE(t testing.TestingT, config *api.Config) (string, error) {
+	configFile, err := os.CreateTemp("", "eks-kubeconfig-")
+	if err != nil {
+		return "", err
+	}
+	if err := configFile.Close(); err != nil {
+		return "", err
+	}
+	if cleaner, ok := t.(interface{ Cleanup(func()) }); ok {
+		cleaner.Cleanup(func() { os.Remove(configFile.Name()) })
+	}
+	if err := clientcmd.WriteToFile(*config, configFile.Name()); err != nil {
+		os.Remove(configFile.Name())
+		return "", err
+	}
+	return configFile.Name(), nil
+}
+
+// newEksKubeConfig returns a kubeconfig with a context, named after the ARN of the given EKS cluster, that
+// authenticates to the cluster with the given token.
+func newEksKubeConfig(cluster *aws.EksCluster, token string) (*api.Config, error) {
+	caData, err := base64.StdEncoding.DecodeString(cluster.CertificateAuthorityData)
+	if err != nil {
+		return nil, err
+	}
+
+	config := api.NewConfig()
+	config.Clusters[cluster.Arn] = &api.Cluster{
+		Server:                   cluster.Endpoint,
+		CertificateAuthorityData: caData,
+	}
+	config.AuthInfos[cluster.Arn] = &api.AuthInfo{Token: token}
+	UpsertConfigContext(config, cluster.Arn, cluster.Arn, cluster.Arn)
+	config.CurrentContext = cluster.Arn
+	return config, nil
+}
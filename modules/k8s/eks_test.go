@@ -0,0 +1,48 @@
+package k8s
+
+import (
+	"encoding/base64"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/gruntwork-io/terratest/modules/aws"
+)
+
+func TestNewEksKubeConfig(t *testing.T) {
+	t.Parallel()
+
+	cluster := &aws.EksCluster{
+		Arn:                      "arn:aws:eks:us-east-1:123456789012:cluster/my-cluster",
+		Endpoint:                 "https://ABCDEF.gr7.us-east-1.eks.amazonaws.com",
+		CertificateAuthorityData: base64.StdEncoding.EncodeToString([]byte("certificate")),
+	}
+	config, err := newEksKubeConfig(cluster, "k8s-aws-v1.token")
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, clientcmd.WriteToFile(*config, path))
+
+	restConfig, err := LoadApiClientConfigE(path, cluster.Arn)
+	require.NoError(t, err)
+	assert.Equal(t, cluster.Endpoint, restConfig.Host)
+	assert.Equal(t, "k8s-aws-v1.token", restConfig.BearerToken)
+	assert.Equal(t, []byte("certificate"), restConfig.TLSClientConfig.CAData)
+}
+
+func TestWriteTempKubeConfigEIsDeletedWhenTheTestFinishes(t *testing.T) {
+	t.Parallel()
+
+	var path string
+	t.Run("write", func(t *testing.T) {
+		var err error
+		path, err = writeTempKubeConfigE(t, api.NewConfig())
+		require.NoError(t, err)
+		assert.FileExists(t, path)
+	})
+	assert.NoFileExists(t, path)
+}
@@ -2,6 +2,8 @@ package k8s
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
@@ -52,6 +54,37 @@ func (err DesiredNumberOfPodsNotCreated) Error() string {
 	return fmt.Sprintf("Desired number of pods (%d) matching filter %v not yet created", err.DesiredCount, err.Filter)
 }
 
+// DesiredNumberOfPodsNotInPhase is returned when the number of pods matching a filter condition that are in the given
+// phase does not match the desired number of Pods.
+type DesiredNumberOfPodsNotInPhase struct {
+	Filter       metav1.ListOptions
+	DesiredCount int
+	Phase        corev1.PodPhase
+	PhaseCounts  map[corev1.PodPhase]int
+}
+
+// Error is a simple function to return a formatted error message as a string
+func (err DesiredNumberOfPodsNotInPhase) Error() string {
+	phases := []string{}
+	for phase := range err.PhaseCounts {
+		phases = append(phases, string(phase))
+	}
+	sort.Strings(phases)
+
+	distribution := []string{}
+	for _, phase := range phases {
+		distribution = append(distribution, fmt.Sprintf("%s=%d", phase, err.PhaseCounts[corev1.PodPhase(phase)]))
+	}
+
+	return fmt.Sprintf(
+		"Desired number of pods (%d) matching filter %v not yet in phase %s (actual phases: [%s])",
+		err.DesiredCount,
+		err.Filter,
+		err.Phase,
+		strings.Join(distribution, ", "),
+	)
+}
+
 // ServiceAccountTokenNotAvailable is returned when a Kubernetes ServiceAccount does not have a token provisioned yet.
 type ServiceAccountTokenNotAvailable struct {
 	Name string
@@ -92,6 +125,29 @@ func NewDeploymentNotAvailableError(deploy *appsv1.Deployment) DeploymentNotAvai
 	return DeploymentNotAvailable{deploy}
 }
 
+// StatefulSetNotReady is returned when not all the replicas of a Kubernetes StatefulSet are ready, or its rollout is
+// not complete.
+type StatefulSetNotReady struct {
+	statefulSet *appsv1.StatefulSet
+}
+
+// Error is a simple function to return a formatted error message as a string
+func (err StatefulSetNotReady) Error() string {
+	return fmt.Sprintf(
+		"StatefulSet %s is not ready: %d/%d replicas are ready, current revision: %s, update revision: %s",
+		err.statefulSet.Name,
+		err.statefulSet.Status.ReadyReplicas,
+		getStatefulSetDesiredReplicas(err.statefulSet),
+		err.statefulSet.Status.CurrentRevision,
+		err.statefulSet.Status.UpdateRevision,
+	)
+}
+
+// NewStatefulSetNotReadyError returns a StatefulSetNotReady struct when Kubernetes deems a StatefulSet is not ready
+func NewStatefulSetNotReadyError(statefulSet *appsv1.StatefulSet) StatefulSetNotReady {
+	return StatefulSetNotReady{statefulSet}
+}
+
 // PodNotAvailable is returned when a Kubernetes service is not yet available to accept traffic.
 type PodNotAvailable struct {
 	pod *corev1.Pod
@@ -213,6 +269,39 @@ func NewNoNodesInKubernetesError() NoNodesInKubernetes {
 	return NoNodesInKubernetes{}
 }
 
+// NodeNotDrained is returned when there are still pods on a node that is being drained.
+type NodeNotDrained struct {
+	NodeName      string
+	RemainingPods []string
+	BlockedPods   []string
+}
+
+// Error is a simple function to return a formatted error message as a string
+func (err NodeNotDrained) Error() string {
+	if len(err.BlockedPods) > 0 {
+		return fmt.Sprintf(
+			"Node %s is not drained, remaining pods: [%s], eviction blocked by a PodDisruptionBudget for pods: [%s]",
+			err.NodeName,
+			strings.Join(err.RemainingPods, ", "),
+			strings.Join(err.BlockedPods, ", "),
+		)
+	}
+	return fmt.Sprintf("Node %s is not drained, remaining pods: [%s]", err.NodeName, strings.Join(err.RemainingPods, ", "))
+}
+
+// PodNotEvictable is returned when a node can't be drained because one of its pods can't be evicted with the given
+// drain options.
+type PodNotEvictable struct {
+	Namespace string
+	Name      string
+	Reason    string
+}
+
+// Error is a simple function to return a formatted error message as a string
+func (err PodNotEvictable) Error() string {
+	return fmt.Sprintf("Pod %s/%s can't be evicted because %s", err.Namespace, err.Name, err.Reason)
+}
+
 // NodeHasNoHostname is returned when a Kubernetes node has no discernible hostname
 type NodeHasNoHostname struct {
 	node *corev1.Node
@@ -281,3 +370,27 @@ type JSONPathMalformedJSONPathResultErr struct {
 func (err JSONPathMalformedJSONPathResultErr) Error() string {
 	return fmt.Sprintf("Error unmarshaling json path output: %s", err.underlyingErr)
 }
+
+// MetricsAPINotAvailable is returned when the resource metrics API (metrics.k8s.io) is not served by the cluster,
+// usually because the metrics-server is not installed.
+type MetricsAPINotAvailable struct {
+	underlyingErr error
+}
+
+// Error is a simple function to return a formatted error message as a string
+func (err MetricsAPINotAvailable) Error() string {
+	return fmt.Sprintf("The metrics.k8s.io API is not available. Is the metrics-server installed? %s", err.underlyingErr)
+}
+
+// PodExecFailed is returned when a command run in a pod through the exec API fails.
+type PodExecFailed struct {
+	PodName    string
+	Command    []string
+	Stderr     string
+	Underlying error
+}
+
+// Error is a simple function to return a formatted error message as a string
+func (err PodExecFailed) Error() string {
+	return fmt.Sprintf("Command %q failed in pod %s: %s. Stderr: %s", strings.Join(err.Command, " "), err.PodName, err.Underlying, err.Stderr)
+}
@@ -58,3 +58,16 @@ func TestErrorDeploymentNotAvailable(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorDesiredNumberOfPodsNotInPhase(t *testing.T) {
+	t.Parallel()
+
+	err := DesiredNumberOfPodsNotInPhase{
+		Filter:       metav1.ListOptions{LabelSelector: "app=nginx"},
+		DesiredCount: 3,
+		Phase:        v1.PodRunning,
+		PhaseCounts:  map[v1.PodPhase]int{v1.PodRunning: 2, v1.PodPending: 1},
+	}
+	assert.Contains(t, err.Error(), "not yet in phase Running")
+	assert.Contains(t, err.Error(), "actual phases: [Pending=1, Running=2]")
+}
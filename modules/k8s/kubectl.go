@@ -24,6 +24,12 @@ func RunKubectlE(t testing.TestingT, options *KubectlOptions, args ...string) er
 // RunKubectlAndGetOutputE will call kubectl using the provided options and args, returning the output of stdout and
 // stderr.
 func RunKubectlAndGetOutputE(t testing.TestingT, options *KubectlOptions, args ...string) (string, error) {
+	return shell.RunCommandAndGetOutputE(t, kubectlCommand(options, args...))
+}
+
+// kubectlCommand returns the kubectl command to run with the given args, using the context, config, namespace and
+// request timeout of the provided options.
+func kubectlCommand(options *KubectlOptions, args ...string) shell.Command {
 	cmdArgs := []string{}
 	if options.ContextName != "" {
 		cmdArgs = append(cmdArgs, "--context", options.ContextName)
@@ -38,13 +44,13 @@ func RunKubectlAndGetOutputE(t testing.TestingT, options *KubectlOptions, args .
 		cmdArgs = append(cmdArgs, "--request-timeout", options.RequestTimeout.String())
 	}
 	cmdArgs = append(cmdArgs, args...)
-	command := shell.Command{
+	return shell.Command{
 		Command: "kubectl",
 		Args:    cmdArgs,
 		Env:     options.Env,
 		Logger:  options.Logger,
+		Context: options.Context,
 	}
-	return shell.RunCommandAndGetOutputE(t, command)
 }
 
 // KubectlDelete will take in a file path and delete it from the cluster targeted by KubectlOptions. If there are any
@@ -125,6 +131,44 @@ func KubectlApplyFromStringE(t testing.TestingT, options *KubectlOptions, config
 	return KubectlApplyE(t, options, tmpfile)
 }
 
+// KubectlDiff will take in a kubernetes resource config as a string and compare it with the live objects on the
+// cluster specified by the provided kubectl options, using a server-side dry-run. Returns the diff, and whether there
+// are any differences. If there are any errors, fail the test immediately.
+func KubectlDiff(t testing.TestingT, options *KubectlOptions, configData string) (string, bool) {
+	diff, hasDiff, err := KubectlDiffE(t, options, configData)
+	require.NoError(t, err)
+	return diff, hasDiff
+}
+
+// KubectlDiffE will take in a kubernetes resource config as a string and compare it with the live objects on the
+// cluster specified by the provided kubectl options, using a server-side dry-run. Returns the diff, and whether there
+// are any differences. Note that kubectl diff exits with status 1 when there are differences; this is not treated as an
+// error.
+func KubectlDiffE(t testing.TestingT, options *KubectlOptions, configData string) (string, bool, error) {
+	tmpfile, err := StoreConfigToTempFileE(t, configData)
+	if err != nil {
+		return "", false, err
+	}
+	defer os.Remove(tmpfile)
+
+	diff, err := shell.RunCommandAndGetStdOutE(t, kubectlCommand(options, "diff", "-f", tmpfile))
+	return parseKubectlDiffResult(diff, err)
+}
+
+// parseKubectlDiffResult interprets the result of running kubectl diff, which exits with status 0 when there are no
+// differences, 1 when there are differences and greater than 1 when kubectl or the diff program failed.
+func parseKubectlDiffResult(diff string, err error) (string, bool, error) {
+	if err == nil {
+		return diff, false, nil
+	}
+
+	exitCode, exitCodeErr := shell.GetExitCodeForRunCommandError(err)
+	if exitCodeErr == nil && exitCode == 1 {
+		return diff, true, nil
+	}
+	return diff, false, err
+}
+
 // StoreConfigToTempFile will store the provided config data to a temporary file created on the os and return the
 // filename.
 func StoreConfigToTempFile(t testing.TestingT, configData string) string {
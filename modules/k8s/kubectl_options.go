@@ -1,6 +1,7 @@
 package k8s
 
 import (
+	"context"
 	"time"
 
 	"github.com/gruntwork-io/terratest/modules/logger"
@@ -18,6 +19,9 @@ type KubectlOptions struct {
 	RestConfig     *rest.Config
 	Logger         *logger.Logger
 	RequestTimeout time.Duration
+	// If set, the kubectl commands are interrupted when the context is done (e.g. the context passed to the stage by
+	// test_structure.RunTestStageWithContext). Not to be confused with ContextName, the context of the kubeconfig.
+	Context context.Context
 }
 
 // NewKubectlOptions will return a pointer to new instance of KubectlOptions with the configured options
@@ -89,3 +89,36 @@ current-context: dummy-context
 	})
 
 }
+
+func TestKubectlDiffReportsChanges(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(exampleDiffConfigMapYamlTemplate, uniqueID, uniqueID, "bar")
+	defer KubectlDeleteFromString(t, options, configData)
+	KubectlApplyFromString(t, options, configData)
+
+	diff, hasDiff := KubectlDiff(t, options, configData)
+	assert.False(t, hasDiff)
+	assert.Empty(t, diff)
+
+	diff, hasDiff = KubectlDiff(t, options, fmt.Sprintf(exampleDiffConfigMapYamlTemplate, uniqueID, uniqueID, "baz"))
+	assert.True(t, hasDiff)
+	assert.Contains(t, diff, "+  foo: baz")
+}
+
+const exampleDiffConfigMapYamlTemplate = `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: %s
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-diff-config-map
+  namespace: %s
+data:
+  foo: %s
+`
@@ -0,0 +1,69 @@
+package k8s
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// GetNodeMetrics returns the current CPU and memory usage of all the nodes in the cluster, as reported by the
+// metrics-server. This will fail the test if there is an error.
+func GetNodeMetrics(t testing.TestingT, options *KubectlOptions) []metricsv1beta1.NodeMetrics {
+	nodeMetrics, err := GetNodeMetricsE(t, options)
+	require.NoError(t, err)
+	return nodeMetrics
+}
+
+// GetNodeMetricsE returns the current CPU and memory usage of all the nodes in the cluster, as reported by the
+// metrics-server. Returns a MetricsAPINotAvailable error if the metrics-server is not installed, so that callers can
+// tell it apart from a transient error.
+func GetNodeMetricsE(t testing.TestingT, options *KubectlOptions) ([]metricsv1beta1.NodeMetrics, error) {
+	clientset, err := GetMetricsClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeMetrics, err := clientset.MetricsV1beta1().NodeMetricses().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, metricsAPINotAvailableOr(err)
+	}
+	return nodeMetrics.Items, nil
+}
+
+// GetPodMetrics returns the current CPU and memory usage of the containers of all the pods in the given namespace, as
+// reported by the metrics-server. This will fail the test if there is an error.
+func GetPodMetrics(t testing.TestingT, options *KubectlOptions, namespace string) []metricsv1beta1.PodMetrics {
+	podMetrics, err := GetPodMetricsE(t, options, namespace)
+	require.NoError(t, err)
+	return podMetrics
+}
+
+// GetPodMetricsE returns the current CPU and memory usage of the containers of all the pods in the given namespace, as
+// reported by the metrics-server. Returns a MetricsAPINotAvailable error if the metrics-server is not installed, so
+// that callers can tell it apart from a transient error.
+func GetPodMetricsE(t testing.TestingT, options *KubectlOptions, namespace string) ([]metricsv1beta1.PodMetrics, error) {
+	clientset, err := GetMetricsClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+
+	podMetrics, err := clientset.MetricsV1beta1().PodMetricses(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, metricsAPINotAvailableOr(err)
+	}
+	return podMetrics.Items, nil
+}
+
+// metricsAPINotAvailableOr converts the NotFound error the API server returns when the metrics.k8s.io API is not
+// registered to a MetricsAPINotAvailable error, and returns any other error as is.
+func metricsAPINotAvailableOr(err error) error {
+	if apierrors.IsNotFound(err) {
+		return MetricsAPINotAvailable{underlyingErr: err}
+	}
+	return err
+}
@@ -0,0 +1,19 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestMetricsAPINotAvailableOr(t *testing.T) {
+	t.Parallel()
+
+	notFoundErr := apierrors.NewNotFound(schema.GroupResource{Group: "metrics.k8s.io", Resource: "nodes"}, "")
+	assert.IsType(t, MetricsAPINotAvailable{}, metricsAPINotAvailableOr(notFoundErr))
+
+	unavailableErr := apierrors.NewServiceUnavailable("the server is currently unable to handle the request")
+	assert.Equal(t, unavailableErr, metricsAPINotAvailableOr(unavailableErr))
+}
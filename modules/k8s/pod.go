@@ -101,6 +101,75 @@ func WaitUntilNumPodsCreatedE(
 	return nil
 }
 
+// WaitUntilNumPodsInPhase waits until exactly the desired number of pods that match the provided filter are in the
+// given phase. Pods matching the filter that are in any other phase are not counted. This will retry the check for the
+// specified amount of times, sleeping for the provided duration between each try. This will fail the test if the retry
+// times out.
+func WaitUntilNumPodsInPhase(
+	t testing.TestingT,
+	options *KubectlOptions,
+	filters metav1.ListOptions,
+	desiredCount int,
+	phase corev1.PodPhase,
+	retries int,
+	sleepBetweenRetries time.Duration,
+) {
+	require.NoError(t, WaitUntilNumPodsInPhaseE(t, options, filters, desiredCount, phase, retries, sleepBetweenRetries))
+}
+
+// WaitUntilNumPodsInPhaseE waits until exactly the desired number of pods that match the provided filter are in the
+// given phase. Pods matching the filter that are in any other phase are not counted. This will retry the check for the
+// specified amount of times, sleeping for the provided duration between each try. On timeout, the returned error
+// includes the distribution of phases of the matching pods from the last try.
+func WaitUntilNumPodsInPhaseE(
+	t testing.TestingT,
+	options *KubectlOptions,
+	filters metav1.ListOptions,
+	desiredCount int,
+	phase corev1.PodPhase,
+	retries int,
+	sleepBetweenRetries time.Duration,
+) error {
+	statusMsg := fmt.Sprintf("Wait for num pods in phase %s to match desired count %d.", phase, desiredCount)
+	var lastErr error
+	message, err := retry.DoWithRetryE(
+		t,
+		statusMsg,
+		retries,
+		sleepBetweenRetries,
+		func() (string, error) {
+			pods, err := ListPodsE(t, options, filters)
+			if err != nil {
+				return "", err
+			}
+			phaseCounts := countPodsByPhase(pods)
+			if phaseCounts[phase] != desiredCount {
+				lastErr = DesiredNumberOfPodsNotInPhase{Filter: filters, DesiredCount: desiredCount, Phase: phase, PhaseCounts: phaseCounts}
+				return "", lastErr
+			}
+			return fmt.Sprintf("Desired number of Pods in phase %s", phase), nil
+		},
+	)
+	if err != nil {
+		options.Logger.Logf(t, "Timedout waiting for the desired number of Pods to be in phase %s: %s", phase, err)
+		if lastErr != nil {
+			return lastErr
+		}
+		return err
+	}
+	options.Logger.Logf(t, message)
+	return nil
+}
+
+// countPodsByPhase returns the number of pods in each phase.
+func countPodsByPhase(pods []corev1.Pod) map[corev1.PodPhase]int {
+	phaseCounts := map[corev1.PodPhase]int{}
+	for _, pod := range pods {
+		phaseCounts[pod.Status.Phase]++
+	}
+	return phaseCounts
+}
+
 // WaitUntilPodAvailable waits until all of the containers within the pod are ready and started, retrying the check for the specified amount of times, sleeping
 // for the provided duration between each try. This will fail the test if there is an error or if the check times out.
 func WaitUntilPodAvailable(t testing.TestingT, options *KubectlOptions, podName string, retries int, sleepBetweenRetries time.Duration) {
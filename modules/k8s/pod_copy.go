@@ -0,0 +1,250 @@
+package k8s
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// CopyToPod copies the file or directory at the given local path to the given path in a container of the pod with the
+// given name, similar to `kubectl cp`. The files are streamed as a tar archive through the exec API, so the container
+// must have a tar binary, but kubectl is not needed. File modes are preserved. If containerName is empty, the default
+// container of the pod is used. This will fail the test if there is an error.
+func CopyToPod(t testing.TestingT, options *KubectlOptions, podName string, containerName string, srcLocalPath string, destPath string) {
+	require.NoError(t, CopyToPodE(t, options, podName, containerName, srcLocalPath, destPath))
+}
+
+// CopyToPodE copies the file or directory at the given local path to the given path in a container of the pod with the
+// given name, similar to `kubectl cp`. The files are streamed as a tar archive through the exec API, so the container
+// must have a tar binary, but kubectl is not needed. File modes are preserved. If containerName is empty, the default
+// container of the pod is used.
+func CopyToPodE(t testing.TestingT, options *KubectlOptions, podName string, containerName string, srcLocalPath string, destPath string) error {
+	options.Logger.Logf(t, "Copying %s to %s in pod %s", srcLocalPath, destPath, podName)
+
+	if _, err := os.Stat(srcLocalPath); err != nil {
+		return err
+	}
+
+	// The archive is extracted in the parent directory of the destination, with its entries named after the
+	// destination, so that the copy can be renamed like with `cp`.
+	destDir, destName := path.Split(path.Clean(destPath))
+	if destDir == "" {
+		destDir = "."
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(writeTarArchive(srcLocalPath, destName, writer))
+	}()
+	defer reader.Close()
+
+	command := []string{"tar", "-xmf", "-", "-C", destDir}
+	return execInPodE(t, options, podName, containerName, command, reader, io.Discard)
+}
+
+// CopyFromPod copies the file or directory at the given path in a container of the pod with the given name to the given
+// local path, similar to `kubectl cp`. The files are streamed as a tar archive through the exec API, so the container
+// must have a tar binary, but kubectl is not needed. File modes are preserved. If containerName is empty, the default
+// container of the pod is used. This will fail the test if there is an error.
+func CopyFromPod(t testing.TestingT, options *KubectlOptions, podName string, containerName string, srcPath string, destLocalPath string) {
+	require.NoError(t, CopyFromPodE(t, options, podName, containerName, srcPath, destLocalPath))
+}
+
+// CopyFromPodE copies the file or directory at the given path in a container of the pod with the given name to the
+// given local path, similar to `kubectl cp`. The files are streamed as a tar archive through the exec API, so the
+// container must have a tar binary, but kubectl is not needed. File modes are preserved. If containerName is empty, the
+// default container of the pod is used.
+func CopyFromPodE(t testing.TestingT, options *KubectlOptions, podName string, containerName string, srcPath string, destLocalPath string) error {
+	options.Logger.Logf(t, "Copying %s in pod %s to %s", srcPath, podName, destLocalPath)
+
+	srcDir, srcName := path.Split(path.Clean(srcPath))
+	if srcDir == "" {
+		srcDir = "."
+	}
+
+	reader, writer := io.Pipe()
+	command := []string{"tar", "-cf", "-", "-C", srcDir, srcName}
+	go func() {
+		writer.CloseWithError(execInPodE(t, options, podName, containerName, command, nil, writer))
+	}()
+	defer reader.Close()
+
+	return extractTarArchive(reader, srcName, destLocalPath)
+}
+
+// execInPodE runs the given command in a container of the pod with the given name through the exec API, streaming
+// stdin to the command and the output of the command to stdout. Returns a PodExecFailed error with the stderr of the
+// command if it fails.
+func execInPodE(t testing.TestingT, options *KubectlOptions, podName string, containerName string, command []string, stdin io.Reader, stdout io.Writer) error {
+	config, err := getRestConfigFromOptionsE(t, options)
+	if err != nil {
+		return err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	request := clientset.CoreV1().RESTClient().
+		Post().
+		Resource("pods").
+		Namespace(options.Namespace).
+		Name(podName).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: containerName,
+			Command:   command,
+			Stdin:     stdin != nil,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(config, "POST", request.URL())
+	if err != nil {
+		return err
+	}
+
+	var stderr bytes.Buffer
+	err = executor.StreamWithContext(context.Background(), remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		return PodExecFailed{PodName: podName, Command: command, Stderr: stderr.String(), Underlying: err}
+	}
+	return nil
+}
+
+// writeTarArchive writes the file or directory at the given local path to the given writer as a tar archive, with the
+// entries named after archiveName (i.e. archiveName for a file, and archiveName/<relative path> for the contents of a
+// directory). Symlinks and other special files are skipped.
+func writeTarArchive(srcLocalPath string, archiveName string, writer io.Writer) error {
+	tarWriter := tar.NewWriter(writer)
+
+	err := filepath.Walk(srcLocalPath, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() && !info.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(srcLocalPath, filePath)
+		if err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = path.Join(archiveName, filepath.ToSlash(relPath))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		file, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tarWriter, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return tarWriter.Close()
+}
+
+// extractTarArchive extracts the entries of the tar archive read from the given reader that are named after
+// archiveName to the given local path, preserving their modes. Entries that would be extracted outside of the given
+// local path are rejected, and symlinks and other special files are skipped.
+func extractTarArchive(reader io.Reader, archiveName string, destLocalPath string) error {
+	tarReader := tar.NewReader(reader)
+	destLocalPath = filepath.Clean(destLocalPath)
+
+	// The modes of the directories are only set once all the files are extracted, so that files can still be
+	// extracted in read-only directories.
+	dirModes := map[string]os.FileMode{}
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Clean(header.Name)
+		if name != archiveName && !strings.HasPrefix(name, archiveName+"/") {
+			continue
+		}
+		targetPath := filepath.Join(destLocalPath, filepath.FromSlash(strings.TrimPrefix(name, archiveName)))
+		if targetPath != destLocalPath && !strings.HasPrefix(targetPath, destLocalPath+string(os.PathSeparator)) {
+			return fmt.Errorf("tar entry %s would be extracted outside of %s", header.Name, destLocalPath)
+		}
+
+		mode := header.FileInfo().Mode().Perm()
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(targetPath, 0755); err != nil {
+				return err
+			}
+			dirModes[targetPath] = mode
+		case tar.TypeReg:
+			if err := extractTarFile(tarReader, targetPath, mode); err != nil {
+				return err
+			}
+		}
+	}
+
+	for dirPath, mode := range dirModes {
+		if err := os.Chmod(dirPath, mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractTarFile(reader io.Reader, targetPath string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, reader); err != nil {
+		return err
+	}
+	// The mode given to OpenFile is subject to the umask and isn't applied to existing files
+	return file.Chmod(mode)
+}
@@ -0,0 +1,87 @@
+package k8s
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTarArchiveRoundTripCopiesDirectoryWithModes(t *testing.T) {
+	t.Parallel()
+
+	srcDir := filepath.Join(t.TempDir(), "src")
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "nested"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "script.sh"), []byte("#!/bin/sh\necho hello\n"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "nested", "data.txt"), []byte("data"), 0600))
+	require.NoError(t, os.Chmod(filepath.Join(srcDir, "nested"), 0500))
+	defer os.Chmod(filepath.Join(srcDir, "nested"), 0755)
+
+	var archive bytes.Buffer
+	require.NoError(t, writeTarArchive(srcDir, "output", &archive))
+
+	destDir := filepath.Join(t.TempDir(), "dest")
+	require.NoError(t, extractTarArchive(&archive, "output", destDir))
+	defer os.Chmod(filepath.Join(destDir, "nested"), 0755)
+
+	script, err := os.ReadFile(filepath.Join(destDir, "script.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh\necho hello\n", string(script))
+	assertFileMode(t, filepath.Join(destDir, "script.sh"), 0755)
+
+	data, err := os.ReadFile(filepath.Join(destDir, "nested", "data.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+	assertFileMode(t, filepath.Join(destDir, "nested", "data.txt"), 0600)
+	assertFileMode(t, filepath.Join(destDir, "nested"), 0500)
+}
+
+func TestTarArchiveRoundTripCopiesSingleFile(t *testing.T) {
+	t.Parallel()
+
+	srcFile := filepath.Join(t.TempDir(), "result.json")
+	require.NoError(t, os.WriteFile(srcFile, []byte(`{"ok":true}`), 0640))
+
+	var archive bytes.Buffer
+	require.NoError(t, writeTarArchive(srcFile, "copy.json", &archive))
+
+	destFile := filepath.Join(t.TempDir(), "local.json")
+	require.NoError(t, extractTarArchive(&archive, "copy.json", destFile))
+
+	contents, err := os.ReadFile(destFile)
+	require.NoError(t, err)
+	assert.Equal(t, `{"ok":true}`, string(contents))
+	assertFileMode(t, destFile, 0640)
+}
+
+func TestExtractTarArchiveIgnoresEntriesOutsideArchiveName(t *testing.T) {
+	t.Parallel()
+
+	var archive bytes.Buffer
+	tarWriter := tar.NewWriter(&archive)
+	for _, name := range []string{"output/../../escape.txt", "other.txt", "output/kept.txt"} {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: 4, Typeflag: tar.TypeReg}))
+		_, err := tarWriter.Write([]byte("data"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+
+	rootDir := t.TempDir()
+	destDir := filepath.Join(rootDir, "nested", "dest")
+	require.NoError(t, extractTarArchive(&archive, "output", destDir))
+
+	assert.FileExists(t, filepath.Join(destDir, "kept.txt"))
+	assert.NoFileExists(t, filepath.Join(rootDir, "escape.txt"))
+	assert.NoFileExists(t, filepath.Join(rootDir, "nested", "escape.txt"))
+	assert.NoFileExists(t, filepath.Join(destDir, "other.txt"))
+}
+
+func assertFileMode(t *testing.T, path string, expected os.FileMode) {
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, info.Mode().Perm(), path)
+}
@@ -11,6 +11,8 @@ package k8s
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -72,6 +74,18 @@ func TestWaitUntilNumPodsCreatedReturnsSuccessfully(t *testing.T) {
 	WaitUntilNumPodsCreated(t, options, metav1.ListOptions{}, 1, 60, 1*time.Second)
 }
 
+func TestWaitUntilNumPodsInPhaseReturnsSuccessfully(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(EXAMPLE_POD_YAML_TEMPLATE, uniqueID, uniqueID)
+	defer KubectlDeleteFromString(t, options, configData)
+	KubectlApplyFromString(t, options, configData)
+
+	WaitUntilNumPodsInPhase(t, options, metav1.ListOptions{}, 1, corev1.PodRunning, 60, 1*time.Second)
+}
+
 func TestWaitUntilPodAvailableReturnsSuccessfully(t *testing.T) {
 	t.Parallel()
 
@@ -121,6 +135,33 @@ func TestWaitUntilPodAvailableWithFailingReadinessProbe(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestCopyToPodAndFromPodRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(EXAMPLE_POD_YAML_TEMPLATE, uniqueID, uniqueID)
+	defer KubectlDeleteFromString(t, options, configData)
+	KubectlApplyFromString(t, options, configData)
+	WaitUntilPodAvailable(t, options, "nginx-pod", 60, 1*time.Second)
+
+	srcDir := filepath.Join(t.TempDir(), "fixture")
+	require.NoError(t, os.MkdirAll(srcDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "run.sh"), []byte("echo hello"), 0755))
+
+	CopyToPod(t, options, "nginx-pod", "nginx", srcDir, "/tmp/fixture")
+
+	destDir := filepath.Join(t.TempDir(), "result")
+	CopyFromPod(t, options, "nginx-pod", "nginx", "/tmp/fixture", destDir)
+
+	contents, err := os.ReadFile(filepath.Join(destDir, "run.sh"))
+	require.NoError(t, err)
+	require.Equal(t, "echo hello", string(contents))
+	info, err := os.Stat(filepath.Join(destDir, "run.sh"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0755), info.Mode().Perm())
+}
+
 const EXAMPLE_POD_YAML_TEMPLATE = `---
 apiVersion: v1
 kind: Namespace
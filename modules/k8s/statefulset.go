@@ -0,0 +1,116 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// GetStatefulSet returns a Kubernetes StatefulSet resource in the provided namespace with the given name. This will
+// fail the test if there is an error.
+func GetStatefulSet(t testing.TestingT, options *KubectlOptions, statefulSetName string) *appsv1.StatefulSet {
+	statefulSet, err := GetStatefulSetE(t, options, statefulSetName)
+	require.NoError(t, err)
+	return statefulSet
+}
+
+// GetStatefulSetE returns a Kubernetes StatefulSet resource in the provided namespace with the given name.
+func GetStatefulSetE(t testing.TestingT, options *KubectlOptions, statefulSetName string) (*appsv1.StatefulSet, error) {
+	clientset, err := GetKubernetesClientFromOptionsE(t, options)
+	if err != nil {
+		return nil, err
+	}
+	return clientset.AppsV1().StatefulSets(options.Namespace).Get(context.Background(), statefulSetName, metav1.GetOptions{})
+}
+
+// WaitUntilStatefulSetReady waits until all the replicas of the StatefulSet are ready and its rollout is complete,
+// retrying the check for the specified amount of times, sleeping for the provided duration between each try. This will
+// fail the test if there is an error.
+func WaitUntilStatefulSetReady(t testing.TestingT, options *KubectlOptions, statefulSetName string, retries int, sleepBetweenRetries time.Duration) {
+	require.NoError(t, WaitUntilStatefulSetReadyE(t, options, statefulSetName, retries, sleepBetweenRetries))
+}
+
+// WaitUntilStatefulSetReadyE waits until all the replicas of the StatefulSet are ready and its rollout is complete,
+// retrying the check for the specified amount of times, sleeping for the provided duration between each try. On
+// timeout, the readiness of each pod of the StatefulSet is logged.
+func WaitUntilStatefulSetReadyE(
+	t testing.TestingT,
+	options *KubectlOptions,
+	statefulSetName string,
+	retries int,
+	sleepBetweenRetries time.Duration,
+) error {
+	statusMsg := fmt.Sprintf("Wait for StatefulSet %s to be ready.", statefulSetName)
+	var lastStatefulSet *appsv1.StatefulSet
+	message, err := retry.DoWithRetryE(
+		t,
+		statusMsg,
+		retries,
+		sleepBetweenRetries,
+		func() (string, error) {
+			statefulSet, err := GetStatefulSetE(t, options, statefulSetName)
+			if err != nil {
+				return "", err
+			}
+			lastStatefulSet = statefulSet
+			if !IsStatefulSetReady(statefulSet) {
+				return "", NewStatefulSetNotReadyError(statefulSet)
+			}
+			return "StatefulSet is now ready", nil
+		},
+	)
+	if err != nil {
+		options.Logger.Logf(t, "Timedout waiting for StatefulSet to be ready: %s", err)
+		if lastStatefulSet != nil {
+			logStatefulSetPodsReadiness(t, options, lastStatefulSet)
+		}
+		return err
+	}
+	options.Logger.Logf(t, message)
+	return nil
+}
+
+// IsStatefulSetReady returns true if all the replicas of the StatefulSet are ready and all of them run the latest
+// revision of the StatefulSet, i.e. its rollout is complete.
+func IsStatefulSetReady(statefulSet *appsv1.StatefulSet) bool {
+	status := statefulSet.Status
+	return status.ObservedGeneration >= statefulSet.Generation &&
+		status.ReadyReplicas == getStatefulSetDesiredReplicas(statefulSet) &&
+		status.CurrentRevision == status.UpdateRevision
+}
+
+// getStatefulSetDesiredReplicas returns the number of replicas of the StatefulSet, which defaults to 1 when not set.
+func getStatefulSetDesiredReplicas(statefulSet *appsv1.StatefulSet) int32 {
+	if statefulSet.Spec.Replicas == nil {
+		return 1
+	}
+	return *statefulSet.Spec.Replicas
+}
+
+// logStatefulSetPodsReadiness logs whether each pod of the StatefulSet is ready, to help debug why it isn't.
+func logStatefulSetPodsReadiness(t testing.TestingT, options *KubectlOptions, statefulSet *appsv1.StatefulSet) {
+	pods, err := ListPodsE(t, options, metav1.ListOptions{LabelSelector: metav1.FormatLabelSelector(statefulSet.Spec.Selector)})
+	if err != nil {
+		options.Logger.Logf(t, "Failed to list the pods of StatefulSet %s: %s", statefulSet.Name, err)
+		return
+	}
+	for idx := range pods {
+		pod := &pods[idx]
+		options.Logger.Logf(
+			t,
+			"Pod %s of StatefulSet %s: phase %s, ready %t, revision %s",
+			pod.Name,
+			statefulSet.Name,
+			pod.Status.Phase,
+			IsPodAvailable(pod),
+			pod.Labels[appsv1.StatefulSetRevisionLabel],
+		)
+	}
+}
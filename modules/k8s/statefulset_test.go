@@ -0,0 +1,177 @@
+//go:build kubeall || kubernetes
+// +build kubeall kubernetes
+
+// NOTE: we have build tags to differentiate kubernetes tests from non-kubernetes tests. This is done because minikube
+// is heavy and can interfere with docker related tests in terratest. Specifically, many of the tests start to fail with
+// `connection refused` errors from `minikube`. To avoid overloading the system, we run the kubernetes tests and helm
+// tests separately from the others. This may not be necessary if you have a sufficiently powerful machine.  We
+// recommend at least 4 cores and 16GB of RAM if you want to run all the tests together.
+
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetStatefulSetEReturnsError(t *testing.T) {
+	t.Parallel()
+
+	options := NewKubectlOptions("", "", "")
+	_, err := GetStatefulSetE(t, options, "nginx-statefulset")
+	require.Error(t, err)
+}
+
+func TestGetStatefulSet(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExampleStatefulSetYAMLTemplate, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+
+	statefulSet := GetStatefulSet(t, options, "nginx-statefulset")
+	require.Equal(t, statefulSet.Name, "nginx-statefulset")
+	require.Equal(t, statefulSet.Namespace, uniqueID)
+}
+
+func TestWaitUntilStatefulSetReady(t *testing.T) {
+	t.Parallel()
+
+	uniqueID := strings.ToLower(random.UniqueId())
+	options := NewKubectlOptions("", "", uniqueID)
+	configData := fmt.Sprintf(ExampleStatefulSetYAMLTemplate, uniqueID)
+	KubectlApplyFromString(t, options, configData)
+	defer KubectlDeleteFromString(t, options, configData)
+
+	WaitUntilStatefulSetReady(t, options, "nginx-statefulset", 60, 1*time.Second)
+}
+
+func TestIsStatefulSetReady(t *testing.T) {
+	replicas := int32(2)
+
+	testCases := []struct {
+		title          string
+		statefulSet    *appsv1.StatefulSet
+		expectedResult bool
+	}{
+		{
+			title: "TestIsStatefulSetReadyWithAllReplicasReadyAndRolloutComplete",
+			statefulSet: &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+				Status: appsv1.StatefulSetStatus{
+					ObservedGeneration: 2,
+					ReadyReplicas:      2,
+					CurrentRevision:    "nginx-statefulset-6d4cf56db6",
+					UpdateRevision:     "nginx-statefulset-6d4cf56db6",
+				},
+			},
+			expectedResult: true,
+		},
+		{
+			title: "TestIsStatefulSetReadyWithReplicasNotReady",
+			statefulSet: &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Generation: 1},
+				Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+				Status: appsv1.StatefulSetStatus{
+					ObservedGeneration: 1,
+					ReadyReplicas:      1,
+					CurrentRevision:    "nginx-statefulset-6d4cf56db6",
+					UpdateRevision:     "nginx-statefulset-6d4cf56db6",
+				},
+			},
+			expectedResult: false,
+		},
+		{
+			title: "TestIsStatefulSetReadyWithRolloutInProgress",
+			statefulSet: &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+				Status: appsv1.StatefulSetStatus{
+					ObservedGeneration: 2,
+					ReadyReplicas:      2,
+					CurrentRevision:    "nginx-statefulset-6d4cf56db6",
+					UpdateRevision:     "nginx-statefulset-7f9b8c6d5d",
+				},
+			},
+			expectedResult: false,
+		},
+		{
+			title: "TestIsStatefulSetReadyWithNewGenerationNotObserved",
+			statefulSet: &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Generation: 3},
+				Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+				Status: appsv1.StatefulSetStatus{
+					ObservedGeneration: 2,
+					ReadyReplicas:      2,
+					CurrentRevision:    "nginx-statefulset-6d4cf56db6",
+					UpdateRevision:     "nginx-statefulset-6d4cf56db6",
+				},
+			},
+			expectedResult: false,
+		},
+		{
+			title: "TestIsStatefulSetReadyWithDefaultReplicas",
+			statefulSet: &appsv1.StatefulSet{
+				Status: appsv1.StatefulSetStatus{
+					ReadyReplicas:   1,
+					CurrentRevision: "nginx-statefulset-6d4cf56db6",
+					UpdateRevision:  "nginx-statefulset-6d4cf56db6",
+				},
+			},
+			expectedResult: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			t.Parallel()
+			actualResult := IsStatefulSetReady(tc.statefulSet)
+			require.Equal(t, tc.expectedResult, actualResult)
+		})
+	}
+}
+
+const ExampleStatefulSetYAMLTemplate = `---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: %s
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: nginx-statefulset
+  labels:
+    app: nginx-statefulset
+spec:
+  serviceName: nginx-statefulset
+  replicas: 2
+  selector:
+    matchLabels:
+      app: nginx-statefulset
+  template:
+    metadata:
+      labels:
+        app: nginx-statefulset
+    spec:
+      containers:
+      - name: nginx
+        image: nginx:1.15.7
+        ports:
+        - containerPort: 80
+        readinessProbe:
+          httpGet:
+            path: /
+            port: 80
+`
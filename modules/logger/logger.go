@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	gotesting "testing"
@@ -68,9 +69,62 @@ func (l *Logger) Logf(t testing.TestingT, format string, args ...interface{}) {
 		return
 	}
 
+	// redact secrets before they reach custom loggers too
+	if hasSecrets() {
+		l.l.Logf(t, "%s", Redact(fmt.Sprintf(format, args...)))
+		return
+	}
+
 	l.l.Logf(t, format, args...)
 }
 
+// RedactedValue replaces the secrets in the logs.
+const RedactedValue = "[REDACTED]"
+
+var (
+	secrets      = []string{}
+	secretsMutex sync.RWMutex
+)
+
+// RegisterSecret registers the given value (e.g. a password read from a sensitive Terraform output) as a secret, so
+// that it is replaced by RedactedValue in everything logged through this package from now on. Empty values are
+// ignored.
+func RegisterSecret(secret string) {
+	if secret == "" {
+		return
+	}
+
+	secretsMutex.Lock()
+	defer secretsMutex.Unlock()
+
+	for _, existingSecret := range secrets {
+		if existingSecret == secret {
+			return
+		}
+	}
+	secrets = append(secrets, secret)
+	// replace the longest secrets first, so that secrets that contain other secrets are fully redacted
+	sort.SliceStable(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+}
+
+// Redact returns the given text with every secret registered with RegisterSecret replaced by RedactedValue.
+func Redact(text string) string {
+	secretsMutex.RLock()
+	defer secretsMutex.RUnlock()
+
+	for _, secret := range secrets {
+		text = strings.ReplaceAll(text, secret, RedactedValue)
+	}
+	return text
+}
+
+// hasSecrets returns true if any secret was registered with RegisterSecret.
+func hasSecrets() bool {
+	secretsMutex.RLock()
+	defer secretsMutex.RUnlock()
+	return len(secrets) > 0
+}
+
 // helper is used to mark this library as a "helper", and thus not appearing in the line numbers. testing.T implements
 // this interface, for example.
 type helper interface {
@@ -147,12 +201,12 @@ func Log(t testing.TestingT, args ...interface{}) {
 var mutexStdout sync.Mutex
 
 // DoLog logs the given arguments to the given writer, along with a timestamp and information about what test and file is
-// doing the logging.
+// doing the logging. Secrets registered with RegisterSecret are redacted.
 func DoLog(t testing.TestingT, callDepth int, writer io.Writer, args ...interface{}) {
 	date := time.Now()
 	prefix := fmt.Sprintf("%s %s %s:", t.Name(), date.Format(time.RFC3339), CallerPrefix(callDepth+1))
 	allArgs := append([]interface{}{prefix}, args...)
-	fmt.Fprintln(writer, allArgs...)
+	fmt.Fprint(writer, Redact(fmt.Sprintln(allArgs...)))
 }
 
 // CallerPrefix returns the file and line number information about the methods that called this method, based on the current
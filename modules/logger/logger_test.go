@@ -106,3 +106,22 @@ func TestLockedLog(t *testing.T) {
 	}
 
 }
+
+func TestRegisterSecretRedactsLogs(t *testing.T) {
+	t.Parallel()
+
+	RegisterSecret("hunter2-test-register-secret")
+	RegisterSecret("hunter2-test-register-secret-longer")
+	RegisterSecret("")
+
+	assert.Equal(t, "password: [REDACTED], other: [REDACTED]", Redact("password: hunter2-test-register-secret-longer, other: hunter2-test-register-secret"))
+
+	var buffer bytes.Buffer
+	DoLog(t, 1, &buffer, "password:", "hunter2-test-register-secret")
+	assert.NotContains(t, buffer.String(), "hunter2")
+	assert.Contains(t, buffer.String(), "password: [REDACTED]")
+
+	c := &customLogger{}
+	New(c).Logf(t, "password: %s", "hunter2-test-register-secret")
+	assert.Equal(t, []string{"password: [REDACTED]"}, c.logs)
+}
@@ -0,0 +1,196 @@
+package opa
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/shell"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// violationRules are the names of the rules that report violations, following the conventions of conftest.
+var violationRules = []string{"deny", "violation"}
+
+// Violation is a violation of a policy reported by a deny or violation rule.
+type Violation struct {
+	// The package and rule that reported the violation (e.g. terraform.s3.deny).
+	Rule string
+
+	// The message of the violation.
+	Message string
+
+	// The address of the resource that violates the policy (e.g. aws_s3_bucket.logs), if known. This is either the
+	// address field of the violation (for rules that return objects such as {"msg": ..., "address": ...}), or the
+	// address of the resource of the Terraform plan that the message mentions.
+	Address string
+}
+
+func (violation Violation) String() string {
+	if violation.Address == "" {
+		return fmt.Sprintf("%s: %s", violation.Rule, violation.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", violation.Rule, violation.Address, violation.Message)
+}
+
+// EvalPolicies evaluates all the Rego policies of the RulePath of the options (a file or a directory) against the
+// given JSON file (e.g. a Terraform plan from terraform show -json), and returns the violations reported by their deny
+// and violation rules, in any package. Rules can return strings, or objects with a msg field and an optional address
+// field. This will fail the test if the policies can't be evaluated, but not if there are violations.
+func EvalPolicies(t testing.TestingT, options *EvalOptions, jsonFilePath string) []Violation {
+	violations, err := EvalPoliciesE(t, options, jsonFilePath)
+	require.NoError(t, err)
+	return violations
+}
+
+// EvalPoliciesE evaluates all the Rego policies of the RulePath of the options (a file or a directory) against the
+// given JSON file (e.g. a Terraform plan from terraform show -json), and returns the violations reported by their deny
+// and violation rules, in any package. Rules can return strings, or objects with a msg field and an optional address
+// field. Violations are not reported as an error.
+func EvalPoliciesE(t testing.TestingT, options *EvalOptions, jsonFilePath string) ([]Violation, error) {
+	downloadedPolicyPath, err := DownloadPolicyE(t, options.RulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := shell.Command{
+		Command: "opa",
+		Args:    []string{"eval", "--format", "json", "-i", jsonFilePath, "-d", downloadedPolicyPath, "data"},
+		Logger:  logger.Discard,
+	}
+	out, err := shell.RunCommandAndGetStdOutE(t, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := os.ReadFile(jsonFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	violations, err := parseViolations(out, getPlanAddresses(input))
+	if err != nil {
+		return nil, err
+	}
+	options.Logger.Logf(t, "Found %d violations of the policies %s in %s", len(violations), options.RulePath, jsonFilePath)
+	for _, violation := range violations {
+		options.Logger.Logf(t, "Violation %s", violation)
+	}
+	return violations, nil
+}
+
+// parseViolations returns the violations in the output of opa eval --format json data, sorted by rule, address and
+// message. Violations without an address get the longest of the given addresses that their message contains.
+func parseViolations(out string, addresses []string) ([]Violation, error) {
+	var output struct {
+		Result []struct {
+			Expressions []struct {
+				Value interface{} `json:"value"`
+			} `json:"expressions"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(out), &output); err != nil {
+		return nil, err
+	}
+
+	violations := []Violation{}
+	for _, result := range output.Result {
+		for _, expression := range result.Expressions {
+			violations = append(violations, findViolations(nil, expression.Value)...)
+		}
+	}
+
+	// match the longest addresses first, so that aws_s3_bucket.logs_2 is not reported as aws_s3_bucket.logs
+	sort.SliceStable(addresses, func(i, j int) bool { return len(addresses[i]) > len(addresses[j]) })
+	for i := range violations {
+		if violations[i].Address != "" {
+			continue
+		}
+		for _, address := range addresses {
+			if strings.Contains(violations[i].Message, address) {
+				violations[i].Address = address
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Rule != violations[j].Rule {
+			return violations[i].Rule < violations[j].Rule
+		}
+		if violations[i].Address != violations[j].Address {
+			return violations[i].Address < violations[j].Address
+		}
+		return violations[i].Message < violations[j].Message
+	})
+	return violations, nil
+}
+
+// findViolations returns the violations of the violation rules in the given document, which is at the given path of
+// the data document (e.g. the values of the rules of a package).
+func findViolations(path []string, document interface{}) []Violation {
+	values, isMap := document.(map[string]interface{})
+	if !isMap {
+		return nil
+	}
+
+	violations := []Violation{}
+	for key, value := range values {
+		rulePath := append(append([]string{}, path...), key)
+		results, isList := value.([]interface{})
+		if !isList || !isViolationRule(key) {
+			violations = append(violations, findViolations(rulePath, value)...)
+			continue
+		}
+
+		for _, result := range results {
+			violation := Violation{Rule: strings.Join(rulePath, ".")}
+			switch result := result.(type) {
+			case string:
+				violation.Message = result
+			case map[string]interface{}:
+				violation.Message = fmt.Sprintf("%v", result["msg"])
+				if address, hasAddress := result["address"].(string); hasAddress {
+					violation.Address = address
+				}
+			default:
+				violation.Message = fmt.Sprintf("%v", result)
+			}
+			violations = append(violations, violation)
+		}
+	}
+	return violations
+}
+
+// isViolationRule returns true if the rule with the given name reports violations (e.g. deny or deny_public_buckets).
+func isViolationRule(name string) bool {
+	for _, rule := range violationRules {
+		if name == rule || strings.HasPrefix(name, rule+"_") {
+			return true
+		}
+	}
+	return false
+}
+
+// getPlanAddresses returns the addresses of the resources of the given Terraform plan in the JSON format, or nothing if
+// the input is not a plan.
+func getPlanAddresses(input []byte) []string {
+	var plan struct {
+		ResourceChanges []struct {
+			Address string `json:"address"`
+		} `json:"resource_changes"`
+	}
+	if err := json.Unmarshal(input, &plan); err != nil {
+		return nil
+	}
+
+	addresses := []string{}
+	for _, resourceChange := range plan.ResourceChanges {
+		addresses = append(addresses, resourceChange.Address)
+	}
+	return addresses
+}
@@ -0,0 +1,76 @@
+package opa
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const planJson = `{
+  "format_version": "1.2",
+  "resource_changes": [
+    {"address": "aws_s3_bucket.logs", "type": "aws_s3_bucket", "change": {"actions": ["create"], "after": {"acl": "public-read"}}},
+    {"address": "aws_s3_bucket.logs_archive", "type": "aws_s3_bucket", "change": {"actions": ["create"], "after": {"acl": "public-read"}}},
+    {"address": "aws_s3_bucket.private", "type": "aws_s3_bucket", "change": {"actions": ["create"], "after": {"acl": "private"}}}
+  ]
+}`
+
+func TestParseViolations(t *testing.T) {
+	t.Parallel()
+
+	out := `{
+	  "result": [{
+	    "expressions": [{
+	      "value": {
+	        "terraform": {
+	          "s3": {
+	            "deny": ["aws_s3_bucket.logs_archive must not be public", "aws_s3_bucket.logs must not be public"],
+	            "deny_tags": [{"msg": "missing tags", "address": "aws_s3_bucket.private"}],
+	            "allow": ["not a violation"],
+	            "public_buckets": ["aws_s3_bucket.logs"]
+	          }
+	        },
+	        "violation": ["global violation"]
+	      },
+	      "text": "data"
+	    }]
+	  }]
+	}`
+
+	violations, err := parseViolations(out, getPlanAddresses([]byte(planJson)))
+	require.NoError(t, err)
+	assert.Equal(t, []Violation{
+		{Rule: "terraform.s3.deny", Message: "aws_s3_bucket.logs must not be public", Address: "aws_s3_bucket.logs"},
+		{Rule: "terraform.s3.deny", Message: "aws_s3_bucket.logs_archive must not be public", Address: "aws_s3_bucket.logs_archive"},
+		{Rule: "terraform.s3.deny_tags", Message: "missing tags", Address: "aws_s3_bucket.private"},
+		{Rule: "violation", Message: "global violation"},
+	}, violations)
+}
+
+func TestEvalPolicies(t *testing.T) {
+	t.Parallel()
+
+	policyDir := t.TempDir()
+	policy := `
+		package terraform.s3
+
+		deny[msg] {
+			resource := input.resource_changes[_]
+			resource.type == "aws_s3_bucket"
+			resource.change.after.acl == "public-read"
+			msg := sprintf("%s must not be public", [resource.address])
+		}
+	`
+	require.NoError(t, os.WriteFile(filepath.Join(policyDir, "s3.rego"), []byte(policy), 0644))
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	require.NoError(t, os.WriteFile(planPath, []byte(planJson), 0644))
+
+	violations := EvalPolicies(t, &EvalOptions{RulePath: policyDir}, planPath)
+	assert.Equal(t, []Violation{
+		{Rule: "terraform.s3.deny", Message: "aws_s3_bucket.logs must not be public", Address: "aws_s3_bucket.logs"},
+		{Rule: "terraform.s3.deny", Message: "aws_s3_bucket.logs_archive must not be public", Address: "aws_s3_bucket.logs_archive"},
+	}, violations)
+}
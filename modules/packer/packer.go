@@ -2,11 +2,14 @@
 package packer
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -34,6 +37,9 @@ type Options struct {
 	WorkingDir                 string            // The directory to run packer in
 	Logger                     *logger.Logger    // If set, use a non-default logger
 	DisableTemporaryPluginPath bool              // If set, do not use a temporary directory for Packer plugins.
+	// If set, the Packer commands are interrupted when the context is done (e.g. the context passed to the stage by
+	// test_structure.RunTestStageWithContext), so that Packer stops gracefully and cleans up the instances it launched.
+	Context context.Context `json:"-"`
 }
 
 // BuildArtifacts can take a map of identifierName <-> Options and then parallelize
@@ -93,9 +99,30 @@ func BuildArtifact(t testing.TestingT, options *Options) string {
 	return artifactID
 }
 
-// BuildArtifactE builds the given Packer template and return the generated Artifact ID.
+// BuildArtifactE builds the given Packer template and return the generated Artifact ID. Returns an error if the
+// template has more than one builder that produced an artifact; use BuildMultiBuilderArtifactsE for those templates.
 func BuildArtifactE(t testing.TestingT, options *Options) (string, error) {
-	options.Logger.Logf(t, "Running Packer to generate a custom artifact for template %s", options.Template)
+	artifactIDs, err := BuildMultiBuilderArtifactsE(t, options)
+	if err != nil {
+		return "", err
+	}
+	return singleArtifactID(artifactIDs)
+}
+
+// BuildMultiBuilderArtifacts builds the given Packer template and returns the generated Artifact IDs keyed by the
+// name of the builder that produced them. Use this instead of BuildArtifact for templates with several builders.
+func BuildMultiBuilderArtifacts(t testing.TestingT, options *Options) map[string]string {
+	artifactIDs, err := BuildMultiBuilderArtifactsE(t, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return artifactIDs
+}
+
+// BuildMultiBuilderArtifactsE builds the given Packer template and returns the generated Artifact IDs keyed by the name
+// of the builder that produced them. Use this instead of BuildArtifactE for templates with several builders.
+func BuildMultiBuilderArtifactsE(t testing.TestingT, options *Options) (map[string]string, error) {
+	options.Logger.Logf(t, "Running Packer to generate custom artifacts for template %s", options.Template)
 
 	// By default, we download packer plugins to a temporary directory rather than use the global plugin path.
 	// This prevents race conditions when multiple tests are running in parallel and each of them attempt
@@ -116,7 +143,7 @@ func BuildArtifactE(t testing.TestingT, options *Options) (string, error) {
 
 	err := packerInit(t, options)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	cmd := shell.Command{
@@ -124,6 +151,7 @@ func BuildArtifactE(t testing.TestingT, options *Options) (string, error) {
 		Args:       formatPackerArgs(options),
 		Env:        options.Env,
 		WorkingDir: options.WorkingDir,
+		Context:    options.Context,
 	}
 
 	description := fmt.Sprintf("%s %v", cmd.Command, cmd.Args)
@@ -132,10 +160,10 @@ func BuildArtifactE(t testing.TestingT, options *Options) (string, error) {
 	})
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return extractArtifactID(output)
+	return extractArtifactIDs(output)
 }
 
 // BuildAmi builds the given Packer template and return the generated AMI ID.
@@ -152,7 +180,7 @@ func BuildAmiE(t testing.TestingT, options *Options) (string, error) {
 	return BuildArtifactE(t, options)
 }
 
-// The Packer machine-readable log output should contain an entry of this format:
+// The Packer machine-readable log output should contain an entry of this format for each artifact:
 //
 // AWS: <timestamp>,<builder>,artifact,<index>,id,<region>:<image_id>
 // GCP: <timestamp>,<builder>,artifact,<index>,id,<image_id>
@@ -161,14 +189,51 @@ func BuildAmiE(t testing.TestingT, options *Options) (string, error) {
 //
 // 1456332887,amazon-ebs,artifact,0,id,us-east-1:ami-b481b3de
 // 1533742764,googlecompute,artifact,0,id,terratest-packer-example-2018-08-08t15-35-19z
+var artifactIDRegexp = regexp.MustCompile(`(?m)^\s*\d+,([^,]+),artifact,\d+,id,(?:.+?:|)(.+?)\s*$`)
+
+// extractArtifactIDs returns the ID of the first artifact of each builder in the given Packer machine-readable log
+// output, keyed by builder name.
+func extractArtifactIDs(packerLogOutput string) (map[string]string, error) {
+	artifactIDs := map[string]string{}
+	for _, matches := range artifactIDRegexp.FindAllStringSubmatch(packerLogOutput, -1) {
+		builder, artifactID := matches[1], matches[2]
+		if _, hasArtifact := artifactIDs[builder]; !hasArtifact {
+			artifactIDs[builder] = artifactID
+		}
+	}
+
+	if len(artifactIDs) == 0 {
+		return nil, errors.New("Could not find Artifact ID pattern in Packer output")
+	}
+	return artifactIDs, nil
+}
+
+// extractArtifactID returns the ID of the artifact in the given Packer machine-readable log output. Returns an error if
+// more than one builder produced an artifact.
 func extractArtifactID(packerLogOutput string) (string, error) {
-	re := regexp.MustCompile(`.+artifact,\d+?,id,(?:.+?:|)(.+)`)
-	matches := re.FindStringSubmatch(packerLogOutput)
+	artifactIDs, err := extractArtifactIDs(packerLogOutput)
+	if err != nil {
+		return "", err
+	}
+	return singleArtifactID(artifactIDs)
+}
+
+// singleArtifactID returns the only artifact ID in the given map of builder name to artifact ID, or an error naming
+// the builders if there is more than one.
+func singleArtifactID(artifactIDs map[string]string) (string, error) {
+	if len(artifactIDs) != 1 {
+		builders := make([]string, 0, len(artifactIDs))
+		for builder := range artifactIDs {
+			builders = append(builders, builder)
+		}
+		sort.Strings(builders)
+		return "", fmt.Errorf("Packer produced artifacts for %d builders (%s): use BuildMultiBuilderArtifacts to get all of them", len(builders), strings.Join(builders, ", "))
+	}
 
-	if len(matches) == 2 {
-		return matches[1], nil
+	for _, artifactID := range artifactIDs {
+		return artifactID, nil
 	}
-	return "", errors.New("Could not find Artifact ID pattern in Packer output")
+	return "", nil
 }
 
 // Check if the local version of Packer has init
@@ -185,6 +250,7 @@ func hasPackerInit(t testing.TestingT, options *Options) (bool, error) {
 		Args:       []string{"-version"},
 		Env:        options.Env,
 		WorkingDir: options.WorkingDir,
+		Context:    options.Context,
 	}
 	versionCmdOutput, err := shell.RunCommandAndGetOutputE(t, cmd)
 	if err != nil {
@@ -225,6 +291,7 @@ func packerInit(t testing.TestingT, options *Options) error {
 		Args:       []string{"init", options.Template},
 		Env:        options.Env,
 		WorkingDir: options.WorkingDir,
+		Context:    options.Context,
 	}
 
 	description := "Running Packer init"
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExtractAmiIdFromOneLine(t *testing.T) {
@@ -121,6 +122,28 @@ func TestExtractArtifactINoIdPresent(t *testing.T) {
 	}
 }
 
+func TestExtractArtifactIDsFromMultipleBuilders(t *testing.T) {
+	t.Parallel()
+
+	text := `
+	1456332887,amazon-ebs.ubuntu,artifact-count,1
+	1456332887,amazon-ebs.ubuntu,artifact,0,id,us-east-1:ami-b481b3de
+	1456332888,googlecompute.ubuntu,artifact,0,id,terratest-packer-example-2018-08-09t12-02-58z
+	1456332889,googlecompute.ubuntu,artifact,1,id,terratest-packer-example-post-processed
+	`
+
+	artifactIDs, err := extractArtifactIDs(text)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"amazon-ebs.ubuntu":    "ami-b481b3de",
+		"googlecompute.ubuntu": "terratest-packer-example-2018-08-09t12-02-58z",
+	}, artifactIDs)
+
+	_, err = extractArtifactID(text)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "amazon-ebs.ubuntu, googlecompute.ubuntu")
+}
+
 func TestFormatPackerArgs(t *testing.T) {
 	t.Parallel()
 
@@ -2,14 +2,17 @@ package shell
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"runtime"
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/testing"
@@ -24,8 +27,20 @@ type Command struct {
 	Env        map[string]string // Additional environment variables to set
 	// Use the specified logger for the command's output. Use logger.Discard to not print the output while executing the command.
 	Logger *logger.Logger
+	// If set, called with each line of stdout and stderr as soon as the command writes it, e.g. to report progress or
+	// detect stalls of long running commands. Calls are never concurrent.
+	OutputCallback func(line string)
+	// If set, the command reads its stdin from this reader instead of the stdin of this Go program.
+	Stdin io.Reader
+	// If set, the command is interrupted (with SIGINT, so that e.g. terraform can stop gracefully and release the state
+	// lock) when the context is done, and killed if it hasn't exited CancelWaitDelay later.
+	Context context.Context
 }
 
+// CancelWaitDelay is how long a command that was interrupted because its Context is done has to exit before it is
+// killed.
+const CancelWaitDelay = 30 * time.Second
+
 // RunCommand runs a shell command and redirects its stdout and stderr to the stdout of the atomic script itself. If
 // there are any errors, fail the test.
 func RunCommand(t testing.TestingT, command Command) {
@@ -114,6 +129,10 @@ func (e *ErrWithCmdOutput) Error() string {
 	return fmt.Sprintf("error while running command: %v; %s", e.Underlying, e.Output.Stderr())
 }
 
+func (e *ErrWithCmdOutput) Unwrap() error {
+	return e.Underlying
+}
+
 // runCommand runs a shell command and stores each line from stdout and stderr in Output. Depending on the logger, the
 // stdout and stderr of that command will also be printed to the stdout and stderr of this Go program to make debugging
 // easier.
@@ -121,8 +140,22 @@ func runCommand(t testing.TestingT, command Command) (*output, error) {
 	command.Logger.Logf(t, "Running command %s with args %s", command.Command, command.Args)
 
 	cmd := exec.Command(command.Command, command.Args...)
+	if command.Context != nil {
+		cmd = exec.CommandContext(command.Context, command.Command, command.Args...)
+		cmd.Cancel = func() error {
+			// Windows doesn't support sending interrupts to processes
+			if runtime.GOOS == "windows" {
+				return cmd.Process.Kill()
+			}
+			return cmd.Process.Signal(os.Interrupt)
+		}
+		cmd.WaitDelay = CancelWaitDelay
+	}
 	cmd.Dir = command.WorkingDir
 	cmd.Stdin = os.Stdin
+	if command.Stdin != nil {
+		cmd.Stdin = command.Stdin
+	}
 	cmd.Env = formatEnvVars(command)
 
 	stdout, err := cmd.StdoutPipe()
@@ -140,7 +173,7 @@ func runCommand(t testing.TestingT, command Command) (*output, error) {
 		return nil, err
 	}
 
-	output, err := readStdoutAndStderr(t, command.Logger, stdout, stderr)
+	output, err := readStdoutAndStderr(t, command.Logger, command.OutputCallback, stdout, stderr)
 	if err != nil {
 		return output, err
 	}
@@ -150,8 +183,18 @@ func runCommand(t testing.TestingT, command Command) (*output, error) {
 
 // This function captures stdout and stderr into the given variables while still printing it to the stdout and stderr
 // of this Go program
-func readStdoutAndStderr(t testing.TestingT, log *logger.Logger, stdout, stderr io.ReadCloser) (*output, error) {
+func readStdoutAndStderr(t testing.TestingT, log *logger.Logger, callback func(line string), stdout, stderr io.ReadCloser) (*output, error) {
 	out := newOutput()
+	if callback != nil {
+		// stdout and stderr are read concurrently, so serialize the calls to the callback
+		var callbackMutex sync.Mutex
+		unsafeCallback := callback
+		callback = func(line string) {
+			callbackMutex.Lock()
+			defer callbackMutex.Unlock()
+			unsafeCallback(line)
+		}
+	}
 	stdoutReader := bufio.NewReader(stdout)
 	stderrReader := bufio.NewReader(stderr)
 
@@ -161,11 +204,11 @@ func readStdoutAndStderr(t testing.TestingT, log *logger.Logger, stdout, stderr
 	var stdoutErr, stderrErr error
 	go func() {
 		defer wg.Done()
-		stdoutErr = readData(t, log, stdoutReader, out.stdout)
+		stdoutErr = readData(t, log, callback, stdoutReader, out.stdout)
 	}()
 	go func() {
 		defer wg.Done()
-		stderrErr = readData(t, log, stderrReader, out.stderr)
+		stderrErr = readData(t, log, callback, stderrReader, out.stderr)
 	}()
 	wg.Wait()
 
@@ -179,7 +222,7 @@ func readStdoutAndStderr(t testing.TestingT, log *logger.Logger, stdout, stderr
 	return out, nil
 }
 
-func readData(t testing.TestingT, log *logger.Logger, reader *bufio.Reader, writer io.StringWriter) error {
+func readData(t testing.TestingT, log *logger.Logger, callback func(line string), reader *bufio.Reader, writer io.StringWriter) error {
 	var line string
 	var readErr error
 	for {
@@ -204,6 +247,9 @@ func readData(t testing.TestingT, log *logger.Logger, reader *bufio.Reader, writ
 		//
 		// See https://github.com/gruntwork-io/terratest/issues/982.
 		log.Logf(t, "%s", line)
+		if callback != nil {
+			callback(line)
+		}
 
 		if _, err := writer.WriteString(line); err != nil {
 			return err
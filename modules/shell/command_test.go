@@ -2,10 +2,12 @@ package shell
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -210,3 +212,62 @@ func TestCommandWithStdoutAndStdErr(t *testing.T) {
 	})
 
 }
+
+func TestRunCommandWithOutputCallback(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{}
+	command := Command{
+		Command: "sh",
+		Args:    []string{"-c", `echo "first" && sleep .01 && echo "second" >&2 && sleep .01 && echo "third"`},
+		Logger:  logger.Discard,
+		OutputCallback: func(line string) {
+			lines = append(lines, line)
+		},
+	}
+
+	RunCommand(t, command)
+	assert.Equal(t, []string{"first", "second", "third"}, lines)
+}
+
+func TestRunCommandWithStdin(t *testing.T) {
+	t.Parallel()
+
+	command := Command{
+		Command: "cat",
+		Stdin:   strings.NewReader("hello from stdin\n"),
+		Logger:  logger.Discard,
+	}
+
+	assert.Equal(t, "hello from stdin", RunCommandAndGetStdOut(t, command))
+}
+
+func TestRunCommandWithContextInterruptsCommand(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	command := Command{
+		Command: "bash",
+		Args:    []string{"-c", `trap 'echo interrupted; kill $!; exit 1' INT; sleep 30 & wait`},
+		Context: ctx,
+		Logger:  logger.Discard,
+	}
+
+	start := time.Now()
+	out, err := RunCommandAndGetOutputE(t, command)
+	require.Error(t, err)
+	assert.Equal(t, "interrupted", out)
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+func TestRunCommandWithCancelledContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := RunCommandE(t, Command{Command: "echo", Args: []string{"hello"}, Context: ctx, Logger: logger.Discard})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -201,7 +201,7 @@ func CheckSshConnectionE(t testing.TestingT, host Host) error {
 }
 
 // CheckSshConnectionWithRetry attempts to connect via SSH until max retries has been exceeded and fails the test
-// if the connection fails
+// if the connection fails. Only connection errors (e.g. dial or handshake failures) are retried.
 func CheckSshConnectionWithRetry(t testing.TestingT, host Host, retries int, sleepBetweenRetries time.Duration, f ...func(testing.TestingT, Host) error) {
 	handler := CheckSshConnectionE
 	if f != nil {
@@ -214,14 +214,14 @@ func CheckSshConnectionWithRetry(t testing.TestingT, host Host, retries int, sle
 }
 
 // CheckSshConnectionWithRetryE attempts to connect via SSH until max retries has been exceeded and returns an error if
-// the connection fails
+// the connection fails. Only connection errors (e.g. dial or handshake failures) are retried.
 func CheckSshConnectionWithRetryE(t testing.TestingT, host Host, retries int, sleepBetweenRetries time.Duration, f ...func(testing.TestingT, Host) error) error {
 	handler := CheckSshConnectionE
 	if f != nil {
 		handler = f[0]
 	}
 	_, err := retry.DoWithRetryE(t, fmt.Sprintf("Checking SSH connection to %s", host.Hostname), retries, sleepBetweenRetries, func() (string, error) {
-		return "", handler(t, host)
+		return "", failOnSshCommandError(handler(t, host))
 	})
 
 	return err
@@ -262,6 +262,7 @@ func CheckSshCommandE(t testing.TestingT, host Host, command string) (string, er
 }
 
 // CheckSshCommandWithRetry checks that you can connect via SSH to the given host and run the given command until max retries have been exceeded. Returns the stdout/stderr.
+// Only connection errors (e.g. dial or handshake failures) are retried: if the command runs but exits with a non-zero status, the test fails right away.
 func CheckSshCommandWithRetry(t testing.TestingT, host Host, command string, retries int, sleepBetweenRetries time.Duration, f ...func(testing.TestingT, Host, string) (string, error)) string {
 	handler := CheckSshCommandE
 	if f != nil {
@@ -275,18 +276,29 @@ func CheckSshCommandWithRetry(t testing.TestingT, host Host, command string, ret
 }
 
 // CheckSshCommandWithRetryE checks that you can connect via SSH to the given host and run the given command until max retries has been exceeded.
-// It return an error if the command fails after max retries has been exceeded.
-
+// It return an error if the command fails after max retries has been exceeded. Only connection errors (e.g. dial or handshake failures) are retried:
+// if the command runs but exits with a non-zero status, the *ssh.ExitError is returned right away, wrapped in a retry.FatalError.
 func CheckSshCommandWithRetryE(t testing.TestingT, host Host, command string, retries int, sleepBetweenRetries time.Duration, f ...func(testing.TestingT, Host, string) (string, error)) (string, error) {
 	handler := CheckSshCommandE
 	if f != nil {
 		handler = f[0]
 	}
 	return retry.DoWithRetryE(t, fmt.Sprintf("Checking SSH connection to %s", host.Hostname), retries, sleepBetweenRetries, func() (string, error) {
-		return handler(t, host, command)
+		out, err := handler(t, host, command)
+		return out, failOnSshCommandError(err)
 	})
 }
 
+// failOnSshCommandError wraps the given error in a retry.FatalError if it means that the connection succeeded but the
+// command exited with a non-zero status, as retrying won't help in that case. Other errors are returned as is.
+func failOnSshCommandError(err error) error {
+	var exitErr *ssh.ExitError
+	if errors.As(err, &exitErr) {
+		return retry.FatalError{Underlying: err}
+	}
+	return err
+}
+
 // CheckPrivateSshConnection attempts to connect to privateHost (which is not addressable from the Internet) via a
 // separate publicHost (which is addressable from the Internet) and then executes "command" on privateHost and returns
 // its output. It is useful for checking that it's possible to SSH from a Bastion Host to a private instance.
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/gruntwork-io/terratest/modules/retry"
 	grunttest "github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
 )
 
 func TestHostWithDefaultPort(t *testing.T) {
@@ -98,6 +101,21 @@ func TestCheckSshCommandWithRetry(t *testing.T) {
 	CheckSshCommandWithRetry(t, host, command, retries, 3, mockSshCommandE)
 }
 
+func TestCheckSshCommandWithRetryEDoesNotRetryCommandFailure(t *testing.T) {
+	t.Parallel()
+
+	host := Host{Hostname: "Host"}
+	command := "exit 1"
+	calls := 0
+
+	_, err := CheckSshCommandWithRetryE(t, host, command, 3, 0, func(t grunttest.TestingT, host Host, command string) (string, error) {
+		calls++
+		return "", &ssh.ExitError{}
+	})
+	require.IsType(t, retry.FatalError{}, err)
+	assert.Equal(t, 1, calls)
+}
+
 func mockSshConnectionE(t grunttest.TestingT, host Host) error {
 	timesCalled += 1
 	if timesCalled >= 5 {
@@ -0,0 +1,141 @@
+package ssh
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// SshTunnel forwards a local port, through an SSH connection to a host, to a port on a remote host that is reachable
+// from that host, like `ssh -L` does. This allows tests to reach hosts and databases in private subnets through a
+// bastion host.
+type SshTunnel struct {
+	host       Host
+	remoteHost string
+	localPort  int
+	remotePort int
+	t          testing.TestingT
+	client     *ssh.Client
+	listener   net.Listener
+	done       chan struct{}
+}
+
+// NewSshTunnel creates a new tunnel from the given local port to the given port on the given remote host, through the
+// given SSH host. If the local port is 0, an open port on the host system is selected when the tunnel is opened with
+// ForwardPort.
+func NewSshTunnel(host Host, remoteHost string, localPort int, remotePort int) *SshTunnel {
+	return &SshTunnel{
+		host:       host,
+		remoteHost: remoteHost,
+		localPort:  localPort,
+		remotePort: remotePort,
+	}
+}
+
+// Endpoint returns the local endpoint of the tunnel.
+func (tunnel *SshTunnel) Endpoint() string {
+	return fmt.Sprintf("localhost:%d", tunnel.localPort)
+}
+
+// LocalPort returns the local port of the tunnel.
+func (tunnel *SshTunnel) LocalPort() int {
+	return tunnel.localPort
+}
+
+// ForwardPort opens the tunnel. This will fail the test if the tunnel could not be opened.
+func (tunnel *SshTunnel) ForwardPort(t testing.TestingT) {
+	require.NoError(t, tunnel.ForwardPortE(t))
+}
+
+// ForwardPortE opens the tunnel by connecting to the SSH host and listening on the local port, after which every
+// connection to the local port is forwarded through the SSH connection to the remote host. Call Close to close the
+// tunnel.
+func (tunnel *SshTunnel) ForwardPortE(t testing.TestingT) error {
+	authMethods, err := createAuthMethodsForHost(tunnel.host)
+	if err != nil {
+		return err
+	}
+
+	client, err := createSSHClient(&SshConnectionOptions{
+		Username:    tunnel.host.SshUserName,
+		Address:     tunnel.host.Hostname,
+		Port:        tunnel.host.getPort(),
+		AuthMethods: authMethods,
+	})
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", tunnel.Endpoint())
+	if err != nil {
+		client.Close()
+		return err
+	}
+	tunnel.localPort = listener.Addr().(*net.TCPAddr).Port
+
+	logger.Default.Logf(t, "Created an SSH tunnel through %s routing local port %d to %s", tunnel.host.Hostname, tunnel.localPort, tunnel.remoteAddress())
+
+	tunnel.t = t
+	tunnel.client = client
+	tunnel.listener = listener
+	tunnel.done = make(chan struct{})
+	go tunnel.serve(client.Dial)
+	return nil
+}
+
+// serve accepts connections on the local port until the listener is closed, and forwards each of them to the remote
+// host with the given dial function.
+func (tunnel *SshTunnel) serve(dial func(network string, address string) (net.Conn, error)) {
+	defer close(tunnel.done)
+	for {
+		localConn, err := tunnel.listener.Accept()
+		if err != nil {
+			return
+		}
+		go tunnel.forward(localConn, dial)
+	}
+}
+
+// forward copies data in both directions between the given local connection and a new connection to the remote host,
+// until either side closes its connection.
+func (tunnel *SshTunnel) forward(localConn net.Conn, dial func(network string, address string) (net.Conn, error)) {
+	defer localConn.Close()
+
+	remoteConn, err := dial("tcp", tunnel.remoteAddress())
+	if err != nil {
+		logger.Default.Logf(tunnel.t, "Failed to connect to %s through the SSH tunnel: %v", tunnel.remoteAddress(), err)
+		return
+	}
+	defer remoteConn.Close()
+
+	copied := make(chan struct{}, 2)
+	go func() {
+		io.Copy(remoteConn, localConn)
+		copied <- struct{}{}
+	}()
+	go func() {
+		io.Copy(localConn, remoteConn)
+		copied <- struct{}{}
+	}()
+	<-copied
+}
+
+func (tunnel *SshTunnel) remoteAddress() string {
+	return net.JoinHostPort(tunnel.remoteHost, strconv.Itoa(tunnel.remotePort))
+}
+
+// Close closes the tunnel by no longer accepting connections on the local port and closing the SSH connection, which
+// also closes the connections forwarded through it.
+func (tunnel *SshTunnel) Close() {
+	if tunnel.listener != nil {
+		Close(tunnel.t, tunnel.listener)
+		<-tunnel.done
+	}
+	Close(tunnel.t, tunnel.client)
+}
@@ -0,0 +1,51 @@
+package ssh
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSshTunnelForwardsConnectionsToTheRemoteHost(t *testing.T) {
+	t.Parallel()
+
+	remote, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer remote.Close()
+	go func() {
+		for {
+			conn, err := remote.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	tunnel := NewSshTunnel(Host{}, "localhost", 0, remote.Addr().(*net.TCPAddr).Port)
+	tunnel.t = t
+	tunnel.localPort = listener.Addr().(*net.TCPAddr).Port
+	tunnel.listener = listener
+	tunnel.done = make(chan struct{})
+	go tunnel.serve(net.Dial)
+	defer tunnel.Close()
+
+	conn, err := net.Dial("tcp", tunnel.Endpoint())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("hello\n"))
+	require.NoError(t, err)
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "hello\n", line)
+}
@@ -16,12 +16,24 @@ func InitAndApply(t testing.TestingT, options *Options) string {
 	return out
 }
 
-// InitAndApplyE runs terraform init and apply with the given options and return stdout/stderr from the apply command. Note that this
-// method does NOT call destroy and assumes the caller is responsible for cleaning up any resources created by running
-// apply.
+// InitAndApplyE runs terraform init and apply with the given options and return stdout/stderr from the apply command, or
+// from the init command if init fails. If options.SaveFailureArtifacts is set and init or apply fails, this saves
+// the output, plan and state to options.FailureArtifactsDir (or a temp dir named after the test) and logs its path. Note
+// that this method does NOT call destroy and assumes the caller is responsible for cleaning up any resources created by
+// running apply.
 func InitAndApplyE(t testing.TestingT, options *Options) (string, error) {
-	if _, err := InitE(t, options); err != nil {
-		return "", err
+	out, err := initAndApplyE(t, options)
+	if err != nil && options.SaveFailureArtifacts {
+		saveAndLogFailureArtifacts(t, options, out)
+	}
+	return out, err
+}
+
+// initAndApplyE runs terraform init and apply with the given options and return stdout/stderr from the apply command,
+// or from the init command if init fails.
+func initAndApplyE(t testing.TestingT, options *Options) (string, error) {
+	if out, err := InitE(t, options); err != nil {
+		return out, err
 	}
 
 	return ApplyE(t, options)
@@ -49,6 +61,80 @@ func ApplyE(t testing.TestingT, options *Options) (string, error) {
 	return RunTerraformCommandE(t, options, FormatArgs(options, "apply", "-input=false", "-auto-approve")...)
 }
 
+// ApplyTarget runs terraform apply with the given options, targeting only the resources with the given addresses (and
+// their dependencies) instead of options.Targets, and returns stdout/stderr. This is useful to bring up expensive
+// resources incrementally across the stages of a test. Note that this method does NOT call destroy and assumes the
+// caller is responsible for cleaning up any resources created by running apply.
+func ApplyTarget(t testing.TestingT, options *Options, targets ...string) string {
+	out, err := ApplyTargetE(t, options, targets...)
+	require.NoError(t, err)
+	return out
+}
+
+// ApplyTargetE runs terraform apply with the given options, targeting only the resources with the given addresses (and
+// their dependencies) instead of options.Targets, and returns stdout/stderr. Note that this method does NOT call destroy
+// and assumes the caller is responsible for cleaning up any resources created by running apply.
+func ApplyTargetE(t testing.TestingT, options *Options, targets ...string) (string, error) {
+	targetOptions, err := withTargets(options, targets)
+	if err != nil {
+		return "", err
+	}
+	return ApplyE(t, targetOptions)
+}
+
+// withTargets returns a copy of the given options with Targets set to the given targets.
+func withTargets(options *Options, targets []string) (*Options, error) {
+	targetOptions, err := options.Clone()
+	if err != nil {
+		return nil, err
+	}
+	targetOptions.Targets = targets
+	return targetOptions, nil
+}
+
+// ApplyReplace runs terraform apply with the given options, forcing the replacement of the resources with the given
+// addresses (like terraform taint) instead of options.Replace, and returns stdout/stderr. This is useful to verify that
+// resources can be recreated cleanly. Note that this method does NOT call destroy and assumes the caller is responsible
+// for cleaning up any resources created by running apply.
+func ApplyReplace(t testing.TestingT, options *Options, addresses ...string) string {
+	out, err := ApplyReplaceE(t, options, addresses...)
+	require.NoError(t, err)
+	return out
+}
+
+// ApplyReplaceE runs terraform apply with the given options, forcing the replacement of the resources with the given
+// addresses (like terraform taint) instead of options.Replace, and returns stdout/stderr. Note that this method does NOT
+// call destroy and assumes the caller is responsible for cleaning up any resources created by running apply.
+func ApplyReplaceE(t testing.TestingT, options *Options, addresses ...string) (string, error) {
+	replaceOptions, err := options.Clone()
+	if err != nil {
+		return "", err
+	}
+	replaceOptions.Replace = addresses
+	return ApplyE(t, replaceOptions)
+}
+
+// ApplyRefreshOnly runs terraform apply -refresh-only with the given options, which updates the state to match the
+// real infrastructure without changing it, and returns stdout/stderr. This will fail the test if there is an error in
+// the command.
+func ApplyRefreshOnly(t testing.TestingT, options *Options) string {
+	out, err := ApplyRefreshOnlyE(t, options)
+	require.NoError(t, err)
+	return out
+}
+
+// ApplyRefreshOnlyE runs terraform apply -refresh-only with the given options, which updates the state to match the
+// real infrastructure without changing it, and returns stdout/stderr. options.Replace is ignored, as Terraform doesn't
+// allow replacing resources in refresh-only mode.
+func ApplyRefreshOnlyE(t testing.TestingT, options *Options) (string, error) {
+	refreshOptions, err := options.Clone()
+	if err != nil {
+		return "", err
+	}
+	refreshOptions.Replace = nil
+	return RunTerraformCommandE(t, refreshOptions, FormatArgs(refreshOptions, "apply", "-input=false", "-auto-approve", "-refresh-only")...)
+}
+
 // TgApplyAllE runs terragrunt apply-all with the given options and return stdout/stderr. Note that this method does NOT call destroy and
 // assumes the caller is responsible for cleaning up any resources created by running apply.
 func TgApplyAllE(t testing.TestingT, options *Options) (string, error) {
@@ -102,12 +188,12 @@ func InitAndApplyAndIdempotent(t testing.TestingT, options *Options) string {
 	return out
 }
 
-// InitAndApplyAndIdempotentE runs terraform init and apply with the given options and return stdout/stderr from the apply command. It then runs
-// plan again and will fail the test if plan requires additional changes. Note that this method does NOT call destroy and assumes
+// InitAndApplyAndIdempotentE runs terraform init and apply with the given options and return stdout/stderr from the apply command, or
+// from the init command if init fails. It then runs plan again and will fail the test if plan requires additional changes. Note that this method does NOT call destroy and assumes
 // the caller is responsible for cleaning up any resources created by running apply.
 func InitAndApplyAndIdempotentE(t testing.TestingT, options *Options) (string, error) {
-	if _, err := InitE(t, options); err != nil {
-		return "", err
+	if out, err := InitE(t, options); err != nil {
+		return out, err
 	}
 
 	return ApplyAndIdempotentE(t, options)
@@ -1,6 +1,8 @@
 package terraform
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -296,3 +298,90 @@ func TestApplyWithPlanFile(t *testing.T) {
 	require.Contains(t, out, "1 added, 0 changed, 0 destroyed.")
 	require.NotRegexp(t, `\[\d*m`, out, "Output should not contain color escape codes")
 }
+
+// writeTerraformStub writes a script that can be used as TerraformBinary, which prints the given message to stderr and
+// exits with the given code when running the given command, and succeeds silently otherwise.
+func writeTerraformStub(t *testing.T, command string, message string, exitCode int) string {
+	stubPath := filepath.Join(t.TempDir(), "terraform-stub")
+	script := fmt.Sprintf("#!/bin/sh\nif [ \"$1\" = %q ]; then\n  echo %q >&2\n  exit %d\nfi\n", command, message, exitCode)
+	require.NoError(t, os.WriteFile(stubPath, []byte(script), 0755))
+	return stubPath
+}
+
+func TestInitAndApplyEReturnsOutputOnInitFailure(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: writeTerraformStub(t, "init", "Error: Failed to query available provider packages", 1),
+	}
+
+	out, err := InitAndApplyE(t, options)
+	require.Error(t, err)
+	assert.Contains(t, out, "Failed to query available provider packages")
+}
+
+func TestApplyEReturnsOutputOnFailure(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: writeTerraformStub(t, "apply", "Error: creating S3 Bucket: BucketAlreadyExists", 1),
+	}
+
+	out, err := InitAndApplyE(t, options)
+	require.Error(t, err)
+	assert.Contains(t, out, "BucketAlreadyExists")
+}
+
+func TestGetExitCodeForTerraformCommandEReturnsStubbedExitCode(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: writeTerraformStub(t, "plan", "changes detected", 2),
+	}
+
+	exitCode, err := GetExitCodeForTerraformCommandE(t, options, "plan", "-detailed-exitcode")
+	require.NoError(t, err)
+	assert.Equal(t, 2, exitCode)
+}
+
+func TestApplyTargetEDoesNotModifyOptions(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: writeTerraformStub(t, "apply", "Error: Invalid target address", 1),
+		Targets:         []string{"null_resource.foo"},
+	}
+
+	out, err := ApplyTargetE(t, options, "null_resource.bar")
+	require.Error(t, err)
+	assert.Contains(t, out, "Invalid target address")
+	assert.Equal(t, []string{"null_resource.foo"}, options.Targets)
+}
+
+func TestApplyReplaceAndRefreshOnly(t *testing.T) {
+	t.Parallel()
+
+	stubPath, logPath := writeRollbackStub(t)
+	options := &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: stubPath,
+		Replace:         []string{"null_resource.foo"},
+	}
+
+	_, err := ApplyReplaceE(t, options, "null_resource.bar")
+	require.NoError(t, err)
+	_, err = ApplyRefreshOnlyE(t, options)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"null_resource.foo"}, options.Replace)
+
+	content, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"apply -input=false -auto-approve -replace null_resource.bar -lock=false",
+		"apply -input=false -auto-approve -refresh-only -lock=false",
+	}, strings.Split(strings.TrimSpace(string(content)), "\n"))
+}
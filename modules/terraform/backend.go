@@ -0,0 +1,79 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/gruntwork-io/terratest/modules/random"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// The backend types that IsolateBackendState supports.
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+	BackendGCS   = "gcs"
+)
+
+// backendStateSettings are the backend config settings that set the location of the state, by backend type.
+var backendStateSettings = map[string]string{
+	BackendLocal: "path",
+	BackendS3:    "key",
+	BackendGCS:   "prefix",
+}
+
+// unsafeStateNameCharsRegexp matches the characters of test names that are not safe in state keys and paths.
+var unsafeStateNameCharsRegexp = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+
+// IsolateBackendState sets the location of the state in the BackendConfig of the given options (the key of the s3
+// backend, the prefix of the gcs backend or the path of the local backend) to a location that is unique to this test
+// run, so that parallel tests of the same module don't share state, and returns that location. The other settings of
+// the backend (e.g. bucket or region) must be set in the BackendConfig or in the module. This must be called before
+// terraform init. Use test_structure.DeleteTerraformBackendState to delete the state at the end of the test. This will
+// fail the test if the backend type is not supported.
+func IsolateBackendState(t testing.TestingT, options *Options, backendType string) string {
+	location, err := IsolateBackendStateE(t, options, backendType)
+	require.NoError(t, err)
+	return location
+}
+
+// IsolateBackendStateE sets the location of the state in the BackendConfig of the given options (the key of the s3
+// backend, the prefix of the gcs backend or the path of the local backend) to a location that is unique to this test
+// run, so that parallel tests of the same module don't share state, and returns that location. The other settings of
+// the backend (e.g. bucket or region) must be set in the BackendConfig or in the module. This must be called before
+// terraform init. Use test_structure.DeleteTerraformBackendState to delete the state at the end of the test.
+func IsolateBackendStateE(t testing.TestingT, options *Options, backendType string) (string, error) {
+	setting, err := GetBackendStateSettingE(backendType)
+	if err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("%s-%s", unsafeStateNameCharsRegexp.ReplaceAllString(t.Name(), "_"), random.UniqueId())
+	location := fmt.Sprintf("terratest/%s/terraform.tfstate", name)
+	switch backendType {
+	case BackendGCS:
+		location = "terratest/" + name
+	case BackendLocal:
+		location = filepath.Join(os.TempDir(), "terratest-state", name, "terraform.tfstate")
+	}
+
+	if options.BackendConfig == nil {
+		options.BackendConfig = map[string]interface{}{}
+	}
+	options.BackendConfig[setting] = location
+	options.Logger.Logf(t, "Storing the state of %s in %s (%s backend)", options.TerraformDir, location, backendType)
+	return location, nil
+}
+
+// GetBackendStateSettingE returns the backend config setting that sets the location of the state for the given
+// backend type (e.g. key for the s3 backend).
+func GetBackendStateSettingE(backendType string) (string, error) {
+	setting, supported := backendStateSettings[backendType]
+	if !supported {
+		return "", UnsupportedBackendType(backendType)
+	}
+	return setting, nil
+}
@@ -0,0 +1,28 @@
+package terraform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsolateBackendStateE(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{BackendConfig: map[string]interface{}{"bucket": "my-state"}}
+	key, err := IsolateBackendStateE(t, options, BackendS3)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key, "terratest/TestIsolateBackendStateE-"))
+	assert.True(t, strings.HasSuffix(key, "/terraform.tfstate"))
+	assert.Equal(t, map[string]interface{}{"bucket": "my-state", "key": key}, options.BackendConfig)
+
+	prefix, err := IsolateBackendStateE(t, options, BackendGCS)
+	require.NoError(t, err)
+	assert.Equal(t, prefix, options.BackendConfig["prefix"])
+	assert.NotContains(t, prefix, ".tfstate")
+
+	_, err = IsolateBackendStateE(t, options, "azurerm")
+	assert.Equal(t, UnsupportedBackendType("azurerm"), err)
+}
@@ -0,0 +1,432 @@
+package terraform
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/retry"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// DefaultCloudHostname is the hostname of Terraform Cloud, used when CloudOptions.Hostname is not set.
+const DefaultCloudHostname = "app.terraform.io"
+
+// CloudOptions are the options for calling the API of Terraform Cloud or Terraform Enterprise.
+type CloudOptions struct {
+	Hostname     string         // The hostname of Terraform Cloud or Terraform Enterprise (e.g. tfe.example.com), or its base URL. Defaults to DefaultCloudHostname.
+	Organization string         // The name of the organization that owns the workspaces
+	Token        string         // A user or team API token with access to the organization
+	Logger       *logger.Logger // Set a non-default logger that should be used. See the logger package for more info.
+}
+
+// The statuses of a run that won't change anymore, mapped to whether the run succeeded. See
+// https://developer.hashicorp.com/terraform/cloud-docs/api-docs/run#run-states for details.
+var cloudRunFinalStatuses = map[string]bool{
+	"applied":              true,
+	"planned_and_finished": true,
+	"planned_and_saved":    true,
+	"errored":              false,
+	"discarded":            false,
+	"canceled":             false,
+	"force_canceled":       false,
+}
+
+// The statuses in which a run waits for its plan to be confirmed before applying it. Runs of workspaces without
+// auto-apply stop in one of these (depending on the cost estimation, policy checks and run tasks of the workspace), so
+// they are final for such runs.
+var cloudRunConfirmableStatuses = map[string]bool{
+	"planned":             true,
+	"cost_estimated":      true,
+	"policy_checked":      true,
+	"policy_override":     true,
+	"post_plan_completed": true,
+}
+
+// The JSON:API document format used by all the requests and responses of the API.
+type cloudDocument struct {
+	Data cloudResource `json:"data"`
+}
+
+type cloudListDocument struct {
+	Data []cloudResource `json:"data"`
+}
+
+type cloudResource struct {
+	ID            string                       `json:"id,omitempty"`
+	Type          string                       `json:"type"`
+	Attributes    map[string]interface{}       `json:"attributes,omitempty"`
+	Relationships map[string]cloudRelationship `json:"relationships,omitempty"`
+}
+
+type cloudRelationship struct {
+	Data interface{} `json:"data"`
+}
+
+// WithCloudWorkspace makes a copy of the Options object and returns an updated object that runs Terraform against the
+// given workspace of Terraform Cloud or Terraform Enterprise, by setting the TF_CLOUD_HOSTNAME, TF_CLOUD_ORGANIZATION,
+// TF_WORKSPACE and TF_TOKEN_<hostname> environment variables. The Terraform code must have an empty cloud block (e.g.
+// `terraform { cloud {} }`) for init to pick these up.
+// This will fail the test if there are any errors in the cloning process.
+func WithCloudWorkspace(t testing.TestingT, originalOptions *Options, cloudOptions *CloudOptions, workspaceName string) *Options {
+	newOptions, err := originalOptions.Clone()
+	require.NoError(t, err)
+
+	hostname := getCloudHostname(cloudOptions)
+	newOptions.EnvVars["TF_CLOUD_HOSTNAME"] = hostname
+	newOptions.EnvVars["TF_CLOUD_ORGANIZATION"] = cloudOptions.Organization
+	newOptions.EnvVars["TF_WORKSPACE"] = workspaceName
+	newOptions.EnvVars[getCloudTokenEnvVarName(hostname)] = cloudOptions.Token
+
+	return newOptions
+}
+
+// CreateCloudWorkspace creates a workspace with the given name and returns its ID. This will fail the test if there is
+// an error.
+func CreateCloudWorkspace(t testing.TestingT, cloudOptions *CloudOptions, workspaceName string) string {
+	workspaceID, err := CreateCloudWorkspaceE(t, cloudOptions, workspaceName)
+	require.NoError(t, err)
+	return workspaceID
+}
+
+// CreateCloudWorkspaceE creates a workspace with the given name and returns its ID.
+func CreateCloudWorkspaceE(t testing.TestingT, cloudOptions *CloudOptions, workspaceName string) (string, error) {
+	cloudOptions.Logger.Logf(t, "Creating workspace %s in organization %s", workspaceName, cloudOptions.Organization)
+
+	request := cloudDocument{Data: cloudResource{
+		Type:       "workspaces",
+		Attributes: map[string]interface{}{"name": workspaceName},
+	}}
+	var response cloudDocument
+	path := fmt.Sprintf("/organizations/%s/workspaces", url.PathEscape(cloudOptions.Organization))
+	if err := doCloudRequestE(cloudOptions, http.MethodPost, path, request, &response); err != nil {
+		return "", err
+	}
+	return response.Data.ID, nil
+}
+
+// GetCloudWorkspaceID returns the ID of the workspace with the given name. This will fail the test if there is an error.
+func GetCloudWorkspaceID(t testing.TestingT, cloudOptions *CloudOptions, workspaceName string) string {
+	workspaceID, err := GetCloudWorkspaceIDE(t, cloudOptions, workspaceName)
+	require.NoError(t, err)
+	return workspaceID
+}
+
+// GetCloudWorkspaceIDE returns the ID of the workspace with the given name.
+func GetCloudWorkspaceIDE(t testing.TestingT, cloudOptions *CloudOptions, workspaceName string) (string, error) {
+	var response cloudDocument
+	path := fmt.Sprintf("/organizations/%s/workspaces/%s", url.PathEscape(cloudOptions.Organization), url.PathEscape(workspaceName))
+	if err := doCloudRequestE(cloudOptions, http.MethodGet, path, nil, &response); err != nil {
+		return "", err
+	}
+	return response.Data.ID, nil
+}
+
+// DeleteCloudWorkspace deletes the workspace with the given name, along with its state. This will fail the test if
+// there is an error.
+func DeleteCloudWorkspace(t testing.TestingT, cloudOptions *CloudOptions, workspaceName string) {
+	require.NoError(t, DeleteCloudWorkspaceE(t, cloudOptions, workspaceName))
+}
+
+// DeleteCloudWorkspaceE deletes the workspace with the given name, along with its state.
+func DeleteCloudWorkspaceE(t testing.TestingT, cloudOptions *CloudOptions, workspaceName string) error {
+	cloudOptions.Logger.Logf(t, "Deleting workspace %s in organization %s", workspaceName, cloudOptions.Organization)
+
+	path := fmt.Sprintf("/organizations/%s/workspaces/%s", url.PathEscape(cloudOptions.Organization), url.PathEscape(workspaceName))
+	return doCloudRequestE(cloudOptions, http.MethodDelete, path, nil, nil)
+}
+
+// CreateCloudVariableSet creates a variable set with the given name that sets the given Terraform variables in the
+// workspaces with the given IDs, and returns its ID. Values that aren't strings are set as HCL (e.g. lists and maps).
+// This will fail the test if there is an error.
+func CreateCloudVariableSet(t testing.TestingT, cloudOptions *CloudOptions, name string, workspaceIDs []string, vars map[string]interface{}) string {
+	variableSetID, err := CreateCloudVariableSetE(t, cloudOptions, name, workspaceIDs, vars)
+	require.NoError(t, err)
+	return variableSetID
+}
+
+// CreateCloudVariableSetE creates a variable set with the given name that sets the given Terraform variables in the
+// workspaces with the given IDs, and returns its ID. Values that aren't strings are set as HCL (e.g. lists and maps).
+func CreateCloudVariableSetE(t testing.TestingT, cloudOptions *CloudOptions, name string, workspaceIDs []string, vars map[string]interface{}) (string, error) {
+	cloudOptions.Logger.Logf(t, "Creating variable set %s in organization %s", name, cloudOptions.Organization)
+
+	request := newCloudVariableSetDocument(name, workspaceIDs, vars)
+	var response cloudDocument
+	path := fmt.Sprintf("/organizations/%s/varsets", url.PathEscape(cloudOptions.Organization))
+	if err := doCloudRequestE(cloudOptions, http.MethodPost, path, request, &response); err != nil {
+		return "", err
+	}
+	return response.Data.ID, nil
+}
+
+// DeleteCloudVariableSet deletes the variable set with the given ID. This will fail the test if there is an error.
+func DeleteCloudVariableSet(t testing.TestingT, cloudOptions *CloudOptions, variableSetID string) {
+	require.NoError(t, DeleteCloudVariableSetE(t, cloudOptions, variableSetID))
+}
+
+// DeleteCloudVariableSetE deletes the variable set with the given ID.
+func DeleteCloudVariableSetE(t testing.TestingT, cloudOptions *CloudOptions, variableSetID string) error {
+	cloudOptions.Logger.Logf(t, "Deleting variable set %s", variableSetID)
+	return doCloudRequestE(cloudOptions, http.MethodDelete, "/varsets/"+url.PathEscape(variableSetID), nil, nil)
+}
+
+func newCloudVariableSetDocument(name string, workspaceIDs []string, vars map[string]interface{}) cloudDocument {
+	workspaces := []cloudResource{}
+	for _, workspaceID := range workspaceIDs {
+		workspaces = append(workspaces, cloudResource{ID: workspaceID, Type: "workspaces"})
+	}
+
+	variables := []cloudResource{}
+	for key, value := range vars {
+		_, isString := value.(string)
+		variables = append(variables, cloudResource{
+			Type: "vars",
+			Attributes: map[string]interface{}{
+				"key":      key,
+				"value":    toHclString(value, false),
+				"category": "terraform",
+				"hcl":      !isString,
+			},
+		})
+	}
+
+	return cloudDocument{Data: cloudResource{
+		Type:       "varsets",
+		Attributes: map[string]interface{}{"name": name, "global": false},
+		Relationships: map[string]cloudRelationship{
+			"workspaces": {Data: workspaces},
+			"vars":       {Data: variables},
+		},
+	}}
+}
+
+// GetLatestCloudRunID returns the ID of the most recent run of the workspace with the given ID. This will fail the test
+// if there is an error.
+func GetLatestCloudRunID(t testing.TestingT, cloudOptions *CloudOptions, workspaceID string) string {
+	runID, err := GetLatestCloudRunIDE(t, cloudOptions, workspaceID)
+	require.NoError(t, err)
+	return runID
+}
+
+// GetLatestCloudRunIDE returns the ID of the most recent run of the workspace with the given ID, or a CloudRunNotFound
+// error if the workspace has no runs.
+func GetLatestCloudRunIDE(t testing.TestingT, cloudOptions *CloudOptions, workspaceID string) (string, error) {
+	var response cloudListDocument
+	path := fmt.Sprintf("/workspaces/%s/runs?page%%5Bsize%%5D=1", url.PathEscape(workspaceID))
+	if err := doCloudRequestE(cloudOptions, http.MethodGet, path, nil, &response); err != nil {
+		return "", err
+	}
+	if len(response.Data) == 0 {
+		return "", CloudRunNotFound(workspaceID)
+	}
+	return response.Data[0].ID, nil
+}
+
+// WaitForCloudRun waits until the run with the given ID finishes, retrying the given number of times with the given
+// sleep in between, and returns its final status. A run without auto-apply finishes once its plan waits for
+// confirmation (e.g. with the status planned or policy_checked). This will fail the test if the run doesn't finish in time or fails
+// (i.e. is errored, discarded or canceled).
+func WaitForCloudRun(t testing.TestingT, cloudOptions *CloudOptions, runID string, maxRetries int, sleepBetweenRetries time.Duration) string {
+	status, err := WaitForCloudRunE(t, cloudOptions, runID, maxRetries, sleepBetweenRetries)
+	require.NoError(t, err)
+	return status
+}
+
+// WaitForCloudRunE waits until the run with the given ID finishes, retrying the given number of times with the given
+// sleep in between, and returns its final status. A run without auto-apply finishes once its plan waits for
+// confirmation (e.g. with the status planned or policy_checked). Returns a CloudRunFailed error if the run is errored, discarded or
+// canceled.
+func WaitForCloudRunE(t testing.TestingT, cloudOptions *CloudOptions, runID string, maxRetries int, sleepBetweenRetries time.Duration) (string, error) {
+	description := fmt.Sprintf("Waiting for run %s to finish", runID)
+	status, err := retry.DoWithRetryE(t, description, maxRetries, sleepBetweenRetries, func() (string, error) {
+		var response cloudDocument
+		if err := doCloudRequestE(cloudOptions, http.MethodGet, "/runs/"+url.PathEscape(runID), nil, &response); err != nil {
+			return "", err
+		}
+
+		status, succeeded, isFinal := getCloudRunState(response.Data)
+		if !isFinal {
+			return "", fmt.Errorf("run %s is still %s", runID, status)
+		}
+		if !succeeded {
+			return "", retry.FatalError{Underlying: CloudRunFailed{RunID: runID, Status: status}}
+		}
+		return status, nil
+	})
+
+	var fatalErr retry.FatalError
+	if errors.As(err, &fatalErr) {
+		return "", fatalErr.Underlying
+	}
+	return status, err
+}
+
+// getCloudRunState returns the status of the given run, whether the status is final and whether the run succeeded. A
+// run without auto-apply that waits for its plan to be confirmed is final and successful, as it won't go on by itself.
+func getCloudRunState(run cloudResource) (string, bool, bool) {
+	status, _ := run.Attributes["status"].(string)
+	if succeeded, isFinal := cloudRunFinalStatuses[status]; isFinal {
+		return status, succeeded, true
+	}
+	autoApply, _ := run.Attributes["auto-apply"].(bool)
+	if cloudRunConfirmableStatuses[status] && !autoApply {
+		return status, true, true
+	}
+	return status, false, false
+}
+
+// LogCloudRunOutput fetches the logs of the plan and apply of the run with the given ID and writes them to the logger
+// of the given options, so the output of a remote run shows up in the test output. This will fail the test if there is
+// an error.
+func LogCloudRunOutput(t testing.TestingT, cloudOptions *CloudOptions, runID string) {
+	require.NoError(t, LogCloudRunOutputE(t, cloudOptions, runID))
+}
+
+// LogCloudRunOutputE fetches the logs of the plan and apply of the run with the given ID and writes them to the logger
+// of the given options line by line as they are downloaded, so the output of a remote run shows up in the test output.
+func LogCloudRunOutputE(t testing.TestingT, cloudOptions *CloudOptions, runID string) error {
+	var run cloudDocument
+	if err := doCloudRequestE(cloudOptions, http.MethodGet, "/runs/"+url.PathEscape(runID), nil, &run); err != nil {
+		return err
+	}
+
+	for _, phase := range []struct{ relationship, path string }{{"plan", "/plans/"}, {"apply", "/applies/"}} {
+		phaseID := getCloudRelationshipID(run.Data, phase.relationship)
+		if phaseID == "" {
+			continue
+		}
+
+		var response cloudDocument
+		if err := doCloudRequestE(cloudOptions, http.MethodGet, phase.path+url.PathEscape(phaseID), nil, &response); err != nil {
+			return err
+		}
+		logURL, _ := response.Data.Attributes["log-read-url"].(string)
+		if logURL == "" {
+			continue
+		}
+
+		cloudOptions.Logger.Logf(t, "Output of the %s of run %s:", phase.relationship, runID)
+		if err := streamCloudLogsE(t, cloudOptions.Logger, logURL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// streamCloudLogsE downloads the logs at the given (pre-signed) URL and writes them to the given logger line by line as
+// they are read, like the output of the commands run with the shell module, stripping the control characters that mark
+// the start and end of the logs.
+func streamCloudLogsE(t testing.TestingT, log *logger.Logger, logURL string) error {
+	response, err := http.Get(logURL)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(response.Body)
+		return CloudApiError{Method: http.MethodGet, Url: logURL, StatusCode: response.StatusCode, Body: string(body)}
+	}
+
+	reader := bufio.NewReader(response.Body)
+	for {
+		rawLine, readErr := reader.ReadString('\n')
+		rawLine = strings.TrimSuffix(rawLine, "\n")
+		line := strings.Trim(rawLine, "\x02\x03")
+		if len(line) > 0 || (len(rawLine) == 0 && readErr == nil) {
+			// Use the format string indirection to avoid interpreting any formatting characters in the line
+			log.Logf(t, "%s", line)
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
+
+func getCloudRelationshipID(resource cloudResource, name string) string {
+	related, ok := resource.Relationships[name].Data.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	id, _ := related["id"].(string)
+	return id
+}
+
+// doCloudRequestE sends a request with the given JSON:API body (if not nil) to the given path of the API, and decodes
+// the response into out (if not nil). Returns a CloudApiError if the API doesn't respond with a 2xx status code.
+func doCloudRequestE(cloudOptions *CloudOptions, method string, path string, body interface{}, out interface{}) error {
+	var requestBody io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		requestBody = bytes.NewReader(bodyBytes)
+	}
+
+	requestURL := getCloudBaseURL(cloudOptions) + "/api/v2" + path
+	request, err := http.NewRequest(method, requestURL, requestBody)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+cloudOptions.Token)
+	request.Header.Set("Content-Type", "application/vnd.api+json")
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return CloudApiError{Method: method, Url: requestURL, StatusCode: response.StatusCode, Body: string(responseBody)}
+	}
+
+	if out == nil || len(responseBody) == 0 {
+		return nil
+	}
+	return json.Unmarshal(responseBody, out)
+}
+
+// getCloudBaseURL returns the base URL of the API, which is the Hostname itself if it is a URL.
+func getCloudBaseURL(cloudOptions *CloudOptions) string {
+	if strings.HasPrefix(cloudOptions.Hostname, "https://") || strings.HasPrefix(cloudOptions.Hostname, "http://") {
+		return strings.TrimSuffix(cloudOptions.Hostname, "/")
+	}
+	return "https://" + getCloudHostname(cloudOptions)
+}
+
+// getCloudHostname returns the hostname (and port, if any) of Terraform Cloud or Terraform Enterprise.
+func getCloudHostname(cloudOptions *CloudOptions) string {
+	if cloudOptions.Hostname == "" {
+		return DefaultCloudHostname
+	}
+	if parsed, err := url.Parse(cloudOptions.Hostname); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+	return cloudOptions.Hostname
+}
+
+// getCloudTokenEnvVarName returns the name of the environment variable that Terraform reads the API token for the given
+// hostname from, e.g. TF_TOKEN_app_terraform_io. Dots are replaced with underscores and dashes with double underscores.
+func getCloudTokenEnvVarName(hostname string) string {
+	name := strings.ReplaceAll(hostname, "-", "__")
+	name = strings.ReplaceAll(name, ".", "_")
+	return "TF_TOKEN_" + name
+}
@@ -0,0 +1,216 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCloudServer starts a fake Terraform Cloud API that responds to the given paths with the given JSON bodies
+// (or status codes for int values), and returns options pointing at it.
+func newTestCloudServer(t *testing.T, responses map[string]interface{}) (*CloudOptions, *[]string) {
+	var mutex sync.Mutex
+	requests := []string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		mutex.Unlock()
+
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch response := responses[r.Method+" "+r.URL.RequestURI()].(type) {
+		case nil:
+			w.WriteHeader(http.StatusNotFound)
+		case int:
+			w.WriteHeader(response)
+		case func() string:
+			fmt.Fprint(w, response())
+		default:
+			fmt.Fprint(w, response)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return &CloudOptions{Hostname: server.URL, Organization: "test-org", Token: "test-token"}, &requests
+}
+
+func TestCloudWorkspaceLifecycle(t *testing.T) {
+	t.Parallel()
+
+	cloudOptions, requests := newTestCloudServer(t, map[string]interface{}{
+		"POST /api/v2/organizations/test-org/workspaces":        `{"data": {"id": "ws-123", "type": "workspaces"}}`,
+		"GET /api/v2/organizations/test-org/workspaces/test":    `{"data": {"id": "ws-123", "type": "workspaces"}}`,
+		"DELETE /api/v2/organizations/test-org/workspaces/test": http.StatusNoContent,
+	})
+
+	assert.Equal(t, "ws-123", CreateCloudWorkspace(t, cloudOptions, "test"))
+	assert.Equal(t, "ws-123", GetCloudWorkspaceID(t, cloudOptions, "test"))
+	DeleteCloudWorkspace(t, cloudOptions, "test")
+
+	_, err := GetCloudWorkspaceIDE(t, cloudOptions, "missing")
+	var apiErr CloudApiError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Len(t, *requests, 4)
+}
+
+func TestNewCloudVariableSetDocument(t *testing.T) {
+	t.Parallel()
+
+	document := newCloudVariableSetDocument("test", []string{"ws-123"}, map[string]interface{}{
+		"region": "us-east-1",
+		"zones":  []string{"a", "b"},
+	})
+
+	documentJSON, err := json.Marshal(document)
+	require.NoError(t, err)
+
+	var parsed cloudDocument
+	require.NoError(t, json.Unmarshal(documentJSON, &parsed))
+	assert.Equal(t, "varsets", parsed.Data.Type)
+	assert.Equal(t, "test", parsed.Data.Attributes["name"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": "ws-123", "type": "workspaces"}}, parsed.Data.Relationships["workspaces"].Data)
+
+	variables := map[string]map[string]interface{}{}
+	for _, variable := range parsed.Data.Relationships["vars"].Data.([]interface{}) {
+		attributes := variable.(map[string]interface{})["attributes"].(map[string]interface{})
+		variables[attributes["key"].(string)] = attributes
+	}
+	assert.Equal(t, "us-east-1", variables["region"]["value"])
+	assert.Equal(t, false, variables["region"]["hcl"])
+	assert.Equal(t, `["a", "b"]`, variables["zones"]["value"])
+	assert.Equal(t, true, variables["zones"]["hcl"])
+}
+
+func TestWaitForCloudRun(t *testing.T) {
+	t.Parallel()
+
+	var mutex sync.Mutex
+	statuses := []string{"pending", "planning", "applying", "applied"}
+	cloudOptions, _ := newTestCloudServer(t, map[string]interface{}{
+		"GET /api/v2/runs/run-ok": func() string {
+			mutex.Lock()
+			defer mutex.Unlock()
+			status := statuses[0]
+			if len(statuses) > 1 {
+				statuses = statuses[1:]
+			}
+			return fmt.Sprintf(`{"data": {"id": "run-ok", "type": "runs", "attributes": {"status": %q}}}`, status)
+		},
+		"GET /api/v2/runs/run-errored": `{"data": {"id": "run-errored", "type": "runs", "attributes": {"status": "errored"}}}`,
+	})
+
+	assert.Equal(t, "applied", WaitForCloudRun(t, cloudOptions, "run-ok", 10, time.Millisecond))
+
+	_, err := WaitForCloudRunE(t, cloudOptions, "run-errored", 10, time.Millisecond)
+	assert.Equal(t, CloudRunFailed{RunID: "run-errored", Status: "errored"}, err)
+}
+
+func TestWaitForCloudRunAwaitingConfirmation(t *testing.T) {
+	t.Parallel()
+
+	cloudOptions, _ := newTestCloudServer(t, map[string]interface{}{
+		"GET /api/v2/runs/run-manual": `{"data": {"id": "run-manual", "type": "runs", "attributes": {"status": "policy_checked", "auto-apply": false}}}`,
+		"GET /api/v2/runs/run-auto":   `{"data": {"id": "run-auto", "type": "runs", "attributes": {"status": "policy_checked", "auto-apply": true}}}`,
+	})
+
+	assert.Equal(t, "policy_checked", WaitForCloudRun(t, cloudOptions, "run-manual", 1, time.Millisecond))
+
+	_, err := WaitForCloudRunE(t, cloudOptions, "run-auto", 2, time.Millisecond)
+	assert.Error(t, err)
+}
+
+func TestGetCloudRunState(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		status            string
+		autoApply         bool
+		expectedSucceeded bool
+		expectedIsFinal   bool
+	}{
+		{"applied", true, true, true},
+		{"errored", false, false, true},
+		{"planning", false, false, false},
+		{"planned", false, true, true},
+		{"planned", true, false, false},
+		{"cost_estimated", false, true, true},
+		{"post_plan_completed", false, true, true},
+		{"post_plan_completed", true, false, false},
+	}
+
+	for _, testCase := range testCases {
+		run := cloudResource{Type: "runs", Attributes: map[string]interface{}{"status": testCase.status, "auto-apply": testCase.autoApply}}
+		status, succeeded, isFinal := getCloudRunState(run)
+		assert.Equal(t, testCase.status, status)
+		assert.Equal(t, testCase.expectedSucceeded, succeeded, "status %s, auto-apply %t", testCase.status, testCase.autoApply)
+		assert.Equal(t, testCase.expectedIsFinal, isFinal, "status %s, auto-apply %t", testCase.status, testCase.autoApply)
+	}
+}
+
+func TestGetLatestCloudRunID(t *testing.T) {
+	t.Parallel()
+
+	cloudOptions, _ := newTestCloudServer(t, map[string]interface{}{
+		"GET /api/v2/workspaces/ws-123/runs?page%5Bsize%5D=1": `{"data": [{"id": "run-456", "type": "runs"}]}`,
+		"GET /api/v2/workspaces/ws-new/runs?page%5Bsize%5D=1": `{"data": []}`,
+	})
+
+	assert.Equal(t, "run-456", GetLatestCloudRunID(t, cloudOptions, "ws-123"))
+
+	_, err := GetLatestCloudRunIDE(t, cloudOptions, "ws-new")
+	assert.Equal(t, CloudRunNotFound("ws-new"), err)
+}
+
+func TestLogCloudRunOutput(t *testing.T) {
+	t.Parallel()
+
+	logServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "\x02Terraform v1.5.7\n\nPlan: 1 to add, 0 to change, 0 to destroy.\n\x03")
+	}))
+	t.Cleanup(logServer.Close)
+
+	cloudOptions, requests := newTestCloudServer(t, map[string]interface{}{
+		"GET /api/v2/runs/run-456": `{"data": {"id": "run-456", "type": "runs", "relationships": {"plan": {"data": {"id": "plan-1", "type": "plans"}}, "apply": {"data": null}}}}`,
+		"GET /api/v2/plans/plan-1": fmt.Sprintf(`{"data": {"id": "plan-1", "type": "plans", "attributes": {"log-read-url": %q}}}`, logServer.URL),
+	})
+
+	recorder := &recordingLogger{}
+	cloudOptions.Logger = logger.New(recorder)
+
+	LogCloudRunOutput(t, cloudOptions, "run-456")
+	assert.Equal(t, []string{"GET /api/v2/runs/run-456", "GET /api/v2/plans/plan-1"}, *requests)
+	assert.Equal(t, []string{
+		"Output of the plan of run run-456:",
+		"Terraform v1.5.7",
+		"",
+		"Plan: 1 to add, 0 to change, 0 to destroy.",
+	}, recorder.logs)
+}
+
+func TestWithCloudWorkspace(t *testing.T) {
+	t.Parallel()
+
+	options := WithCloudWorkspace(t, &Options{}, &CloudOptions{Organization: "test-org", Token: "test-token"}, "test")
+	assert.Equal(t, map[string]string{
+		"TF_CLOUD_HOSTNAME":         "app.terraform.io",
+		"TF_CLOUD_ORGANIZATION":     "test-org",
+		"TF_WORKSPACE":              "test",
+		"TF_TOKEN_app_terraform_io": "test-token",
+	}, options.EnvVars)
+
+	assert.Equal(t, "TF_TOKEN_my__tfe_example_com", getCloudTokenEnvVarName("my-tfe.example.com"))
+	assert.Equal(t, "tfe.example.com", getCloudHostname(&CloudOptions{Hostname: "https://tfe.example.com"}))
+}
@@ -15,15 +15,45 @@ import (
 
 func generateCommand(options *Options, args ...string) shell.Command {
 	cmd := shell.Command{
-		Command:    options.TerraformBinary,
-		Args:       args,
-		WorkingDir: options.TerraformDir,
-		Env:        options.EnvVars,
-		Logger:     options.Logger,
+		Command:        options.TerraformBinary,
+		Args:           args,
+		WorkingDir:     options.TerraformDir,
+		Env:            getCommandEnvVars(options, args...),
+		Logger:         options.Logger,
+		OutputCallback: options.OutputCallback,
+		Context:        options.Context,
 	}
 	return cmd
 }
 
+// Commands that must not run with TF_WORKSPACE set: init runs in the default workspace before Options.Workspace is
+// created, and the workspace commands refuse to change the workspace while TF_WORKSPACE overrides it.
+var commandsWithoutWorkspaceOverride = []string{
+	"init",
+	"workspace",
+}
+
+// getCommandEnvVars returns the environment variables to run the given command with, which are the EnvVars of the
+// options plus TF_WORKSPACE if Options.Workspace is set and TF_PLUGIN_CACHE_DIR if Options.ProviderCacheDir is set.
+// The EnvVars take precedence.
+func getCommandEnvVars(options *Options, args ...string) map[string]string {
+	envVars := map[string]string{}
+	if options.Workspace != "" && !(len(args) > 0 && collections.ListContains(commandsWithoutWorkspaceOverride, args[0])) {
+		envVars["TF_WORKSPACE"] = options.Workspace
+	}
+	if options.ProviderCacheDir != "" {
+		envVars["TF_PLUGIN_CACHE_DIR"] = getAbsPath(options.ProviderCacheDir)
+	}
+	if len(envVars) == 0 {
+		return options.EnvVars
+	}
+
+	for key, value := range options.EnvVars {
+		envVars[key] = value
+	}
+	return envVars
+}
+
 var commandsWithParallelism = []string{
 	"plan",
 	"apply",
@@ -88,10 +118,16 @@ func RunTerraformCommand(t testing.TestingT, additionalOptions *Options, args ..
 func RunTerraformCommandE(t testing.TestingT, additionalOptions *Options, additionalArgs ...string) (string, error) {
 	options, args := GetCommonOptions(additionalOptions, additionalArgs...)
 
+	args, cleanup, err := renderVarFileTemplates(options, args)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
 	cmd := generateCommand(options, args...)
 	description := fmt.Sprintf("%s %v", options.TerraformBinary, args)
 
-	return retry.DoWithRetryableErrorsE(t, description, options.RetryableTerraformErrors, options.MaxRetries, options.TimeBetweenRetries, func() (string, error) {
+	return doWithRetryableTerraformErrorsE(t, options, getTerraformCommand(args), description, func() (string, error) {
 		s, err := shell.RunCommandAndGetOutputE(t, cmd)
 		if err != nil {
 			return s, err
@@ -109,9 +145,15 @@ func RunTerraformCommandE(t testing.TestingT, additionalOptions *Options, additi
 func RunTerraformCommandAndGetStdoutE(t testing.TestingT, additionalOptions *Options, additionalArgs ...string) (string, error) {
 	options, args := GetCommonOptions(additionalOptions, additionalArgs...)
 
+	args, cleanup, err := renderVarFileTemplates(options, args)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
 	cmd := generateCommand(options, args...)
 	description := fmt.Sprintf("%s %v", options.TerraformBinary, args)
-	return retry.DoWithRetryableErrorsE(t, description, options.RetryableTerraformErrors, options.MaxRetries, options.TimeBetweenRetries, func() (string, error) {
+	return doWithRetryableTerraformErrorsE(t, options, getTerraformCommand(args), description, func() (string, error) {
 		s, err := shell.RunCommandAndGetStdOutE(t, cmd)
 		if err != nil {
 			return s, err
@@ -136,9 +178,15 @@ func GetExitCodeForTerraformCommand(t testing.TestingT, additionalOptions *Optio
 func GetExitCodeForTerraformCommandE(t testing.TestingT, additionalOptions *Options, additionalArgs ...string) (int, error) {
 	options, args := GetCommonOptions(additionalOptions, additionalArgs...)
 
+	args, cleanup, err := renderVarFileTemplates(options, args)
+	if err != nil {
+		return DefaultErrorExitCode, err
+	}
+	defer cleanup()
+
 	additionalOptions.Logger.Logf(t, "Running %s with args %v", options.TerraformBinary, args)
 	cmd := generateCommand(options, args...)
-	_, err := shell.RunCommandAndGetOutputE(t, cmd)
+	_, err = shell.RunCommandAndGetOutputE(t, cmd)
 	if err == nil {
 		return DefaultSuccessExitCode, nil
 	}
@@ -163,6 +211,25 @@ func defaultTerraformExecutable() string {
 	return TofuDefaultPath
 }
 
+// IsOpenTofu returns true if the TerraformBinary of the given options runs OpenTofu rather than Terraform.
+func IsOpenTofu(t testing.TestingT, options *Options) bool {
+	isOpenTofu, err := IsOpenTofuE(t, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return isOpenTofu
+}
+
+// IsOpenTofuE returns true if the TerraformBinary of the given options runs OpenTofu rather than Terraform, based on
+// the output of the version command.
+func IsOpenTofuE(t testing.TestingT, options *Options) (bool, error) {
+	out, err := RunTerraformCommandE(t, options, "version")
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(out, "OpenTofu"), nil
+}
+
 func hasWarning(opts *Options, out string) error {
 	for k, v := range opts.WarningsAsErrors {
 		str := fmt.Sprintf("\nWarning: %s[^\n]*\n", k)
@@ -179,6 +246,130 @@ func hasWarning(opts *Options, out string) error {
 	return nil
 }
 
+// doWithRetryableTerraformErrorsE works like retry.DoWithRetryableErrorsE with the ErrorClassifier (or the
+// RetryableTerraformErrors, see getErrorClassifier), MaxRetries and TimeBetweenRetries of the given options, except
+// that the errors are not matched against the warnings in the output of the command, unless RetryOnWarnings is set.
+// This prevents the text of a warning (e.g. a deprecation notice mentioning a timeout) from turning an unrelated error
+// into a retry.
+func doWithRetryableTerraformErrorsE(t testing.TestingT, options *Options, command string, description string, action func() (string, error)) (string, error) {
+	classifier, err := getErrorClassifier(options)
+	if err != nil {
+		return "", retry.FatalError{Underlying: err}
+	}
+
+	return retry.DoWithRetryE(t, description, options.MaxRetries, options.TimeBetweenRetries, func() (string, error) {
+		output, err := action()
+		if err == nil {
+			return output, nil
+		}
+
+		classification := classifyTerraformError(classifier, command, options.RetryOnWarnings, output, err)
+		options.RetryMetrics.record(classification)
+		if classification.Class == ErrorRetryable {
+			options.Logger.Logf(t, "'%s' failed with the error '%s' but this error was expected and warrants a retry. Further details: %s\n", description, err.Error(), classification.Message)
+			return output, err
+		}
+		if classification.Class == ErrorFatal {
+			options.Logger.Logf(t, "'%s' failed with an error that is known not to resolve on retry (%s): %s\n", description, classification.Rule, classification.Message)
+		}
+
+		return output, retry.FatalError{Underlying: err}
+	})
+}
+
+// isRetryableTerraformError returns the message of the first retryable error regex that matches the given output or
+// error, and whether there was a match. Unless matchWarnings is set, warnings are removed from the output and error
+// before matching.
+func isRetryableTerraformError(retryableErrors map[*regexp.Regexp]string, matchWarnings bool, output string, err error) (string, bool) {
+	classification := classifyTerraformError(newRetryableErrorsClassifier(retryableErrors), "", matchWarnings, output, err)
+	return classification.Message, classification.Class == ErrorRetryable
+}
+
+// classifyTerraformError classifies the given error of the given Terraform command with the given output using the
+// given classifier. Unless matchWarnings is set, warnings are removed from the output and error before classifying.
+func classifyTerraformError(classifier ErrorClassifier, command string, matchWarnings bool, output string, err error) ErrorClassification {
+	errText := err.Error()
+	if !matchWarnings {
+		output = stripTerraformWarnings(output)
+		errText = stripTerraformWarnings(errText)
+	}
+	return classifier.Classify(command, output, errText)
+}
+
+// getTerraformCommand returns the Terraform command run with the given arguments, which is the first argument that
+// isn't a flag. For the Terragrunt commands that run a command in all the modules (e.g. run-all destroy or destroy-all),
+// this is the command run in the modules (e.g. destroy).
+func getTerraformCommand(args []string) string {
+	runAll := false
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		if arg == "run-all" && !runAll {
+			runAll = true
+			continue
+		}
+		return strings.TrimSuffix(arg, "-all")
+	}
+	return ""
+}
+
+var (
+	// ansiEscapeRegex matches the ANSI escape codes Terraform uses to color its output.
+	ansiEscapeRegex = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+	// diagnosticStartRegex matches the first line of an error or warning printed by Terraform, either in the plain
+	// format used with -no-color or inside the box drawn around it otherwise.
+	diagnosticStartRegex = regexp.MustCompile(`^(?:│ )?(Error|Warning): `)
+)
+
+// stripTerraformWarnings removes the warnings from the given Terraform output. Boxed warnings end with the box; plain
+// warnings, which have no end marker, are assumed to last until the next error or the next box.
+func stripTerraformWarnings(out string) string {
+	var kept []string
+	inWarning, inBox := false, false
+	var box []string
+
+	for _, line := range strings.Split(out, "\n") {
+		plain := ansiEscapeRegex.ReplaceAllString(line, "")
+
+		if inBox {
+			box = append(box, line)
+			if strings.HasPrefix(plain, "╵") {
+				if !isWarningBox(box) {
+					kept = append(kept, box...)
+				}
+				inBox, box = false, nil
+			}
+			continue
+		}
+		if strings.HasPrefix(plain, "╷") {
+			inBox, inWarning, box = true, false, []string{line}
+			continue
+		}
+		if matches := diagnosticStartRegex.FindStringSubmatch(plain); matches != nil {
+			inWarning = matches[1] == "Warning"
+		}
+		if !inWarning {
+			kept = append(kept, line)
+		}
+	}
+
+	// An unterminated box is kept as is
+	kept = append(kept, box...)
+	return strings.Join(kept, "\n")
+}
+
+// isWarningBox returns true if the given lines of a box drawn by Terraform contain a warning.
+func isWarningBox(box []string) bool {
+	for _, line := range box {
+		matches := diagnosticStartRegex.FindStringSubmatch(ansiEscapeRegex.ReplaceAllString(line, ""))
+		if matches != nil {
+			return matches[1] == "Warning"
+		}
+	}
+	return false
+}
+
 // setTerragruntLogFormatting sets a default log formatting for terragrunt
 // if it is not already set in options.EnvVars or OS environment vars
 func setTerragruntLogFormatting(options *Options) {
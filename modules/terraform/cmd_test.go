@@ -0,0 +1,152 @@
+package terraform
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const plainWarningOutput = `
+Warning: Argument is deprecated
+
+  with aws_s3_bucket.example,
+  on main.tf line 12, in resource "aws_s3_bucket" "example":
+  12:   timeout = "connection reset by peer"
+
+Use the aws_s3_bucket_timeouts resource instead.
+`
+
+const boxedWarningOutput = "\x1b[33m╷\x1b[0m\x1b[0m\n" +
+	"\x1b[33m│\x1b[0m \x1b[0m\x1b[1m\x1b[33mWarning: \x1b[0m\x1b[0m\x1b[1mArgument is deprecated\x1b[0m\n" +
+	"\x1b[33m│\x1b[0m \x1b[0m\n" +
+	"\x1b[33m│\x1b[0m \x1b[0mconnection reset by peer\n" +
+	"\x1b[33m╵\x1b[0m\x1b[0m\n"
+
+const plainErrorOutput = `
+Error: creating S3 Bucket: RequestError: send request failed
+caused by: read tcp: connection reset by peer
+`
+
+func TestIsRetryableTerraformErrorIgnoresWarnings(t *testing.T) {
+	t.Parallel()
+
+	retryableErrors := map[*regexp.Regexp]string{
+		regexp.MustCompile(".*connection reset by peer.*"): "Failed to reach helm charts repository.",
+	}
+	err := errors.New("exit status 1")
+
+	testCases := []struct {
+		name          string
+		output        string
+		matchWarnings bool
+		expected      bool
+	}{
+		{"PlainWarningOnly", plainWarningOutput, false, false},
+		{"BoxedWarningOnly", boxedWarningOutput, false, false},
+		{"PlainWarningOnlyMatchWarnings", plainWarningOutput, true, true},
+		{"BoxedWarningOnlyMatchWarnings", boxedWarningOutput, true, true},
+		{"ErrorOnly", plainErrorOutput, false, true},
+		{"WarningThenError", plainWarningOutput + plainErrorOutput, false, true},
+		{"BoxedWarningThenError", boxedWarningOutput + plainErrorOutput, false, true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, retryable := isRetryableTerraformError(retryableErrors, testCase.matchWarnings, testCase.output, err)
+			assert.Equal(t, testCase.expected, retryable)
+		})
+	}
+}
+
+func TestIsRetryableTerraformErrorIgnoresWarningsInError(t *testing.T) {
+	t.Parallel()
+
+	retryableErrors := map[*regexp.Regexp]string{
+		regexp.MustCompile(".*connection reset by peer.*"): "Failed to reach helm charts repository.",
+	}
+	err := errors.New("error while running command: exit status 1; " + plainWarningOutput)
+
+	_, retryable := isRetryableTerraformError(retryableErrors, false, "", err)
+	assert.False(t, retryable)
+}
+
+func TestHasWarningFailsOnAnyWarning(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{WarningsAsErrors: map[string]string{".*": "no warnings allowed"}}
+	assert.Error(t, hasWarning(options, plainWarningOutput))
+	assert.NoError(t, hasWarning(options, "\nApply complete! Resources: 1 added, 0 changed, 0 destroyed.\n"))
+}
+
+func TestIsOpenTofuE(t *testing.T) {
+	t.Parallel()
+
+	tofuOptions := &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: writeTerraformStub(t, "version", "OpenTofu v1.8.3", 0),
+	}
+	isOpenTofu, err := IsOpenTofuE(t, tofuOptions)
+	assert.NoError(t, err)
+	assert.True(t, isOpenTofu)
+
+	terraformOptions := &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: writeTerraformStub(t, "version", "Terraform v1.9.8", 0),
+	}
+	isOpenTofu, err = IsOpenTofuE(t, terraformOptions)
+	assert.NoError(t, err)
+	assert.False(t, isOpenTofu)
+}
+
+func TestGetCommandEnvVarsSetsWorkspace(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		EnvVars:   map[string]string{"FOO": "bar"},
+		Workspace: "staging",
+	}
+
+	assert.Equal(t, map[string]string{"FOO": "bar", "TF_WORKSPACE": "staging"}, getCommandEnvVars(options, "plan", "-input=false"))
+	assert.Equal(t, map[string]string{"FOO": "bar"}, getCommandEnvVars(options, "init"))
+	assert.Equal(t, map[string]string{"FOO": "bar"}, getCommandEnvVars(options, "workspace", "list"))
+	assert.Equal(t, map[string]string{"FOO": "bar"}, options.EnvVars)
+
+	assert.Equal(t, map[string]string{"FOO": "bar"}, getCommandEnvVars(&Options{EnvVars: map[string]string{"FOO": "bar"}}, "plan"))
+}
+
+func TestGetCommandEnvVarsSetsProviderCacheDir(t *testing.T) {
+	t.Parallel()
+
+	cacheDir := t.TempDir()
+	options := &Options{ProviderCacheDir: cacheDir}
+	assert.Equal(t, map[string]string{"TF_PLUGIN_CACHE_DIR": cacheDir}, getCommandEnvVars(options, "init"))
+
+	options.EnvVars = map[string]string{"TF_PLUGIN_CACHE_DIR": "/custom"}
+	assert.Equal(t, map[string]string{"TF_PLUGIN_CACHE_DIR": "/custom"}, getCommandEnvVars(options, "init"))
+}
+
+func TestRunTerraformCommandEStreamsOutputToCallback(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{}
+	options := &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: writeTerraformStub(t, "apply", "null_resource.test: Still creating... [10s elapsed]", 0),
+		OutputCallback: func(line string) {
+			lines = append(lines, line)
+		},
+	}
+
+	clonedOptions, err := options.Clone()
+	require.NoError(t, err)
+
+	_, err = ApplyE(t, clonedOptions)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"null_resource.test: Still creating... [10s elapsed]"}, lines)
+}
@@ -0,0 +1,134 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gruntwork-io/terratest/modules/shell"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// ConsoleEval evaluates the given expression (e.g. local.name, a function call or any other complex expression) with
+// terraform console in the TerraformDir of the given options, against its current state, and returns the result decoded
+// from JSON: strings, float64 for numbers, bools, []interface{} for lists, tuples and sets, map[string]interface{} for
+// maps and objects, and nil for null. This will fail the test if the expression can't be evaluated.
+func ConsoleEval(t testing.TestingT, options *Options, expression string) interface{} {
+	out, err := ConsoleEvalE(t, options, expression)
+	require.NoError(t, err)
+	return out
+}
+
+// ConsoleEvalE evaluates the given expression (e.g. local.name, a function call or any other complex expression) with
+// terraform console in the TerraformDir of the given options, against its current state, and returns the result decoded
+// from JSON: strings, float64 for numbers, bools, []interface{} for lists, tuples and sets, map[string]interface{} for
+// maps and objects, and nil for null.
+func ConsoleEvalE(t testing.TestingT, options *Options, expression string) (interface{}, error) {
+	out, err := consoleEvalJsonE(t, options, expression)
+	if err != nil {
+		return nil, err
+	}
+
+	var result interface{}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ConsoleEvalStruct evaluates the given expression with terraform console, like ConsoleEval, and stores the result in
+// the value pointed to by v, using the same rules as json.Unmarshal. This will fail the test if the expression can't be
+// evaluated or if the result is not appropriate for v.
+func ConsoleEvalStruct(t testing.TestingT, options *Options, expression string, v interface{}) {
+	err := ConsoleEvalStructE(t, options, expression, v)
+	require.NoError(t, err)
+}
+
+// ConsoleEvalStructE evaluates the given expression with terraform console, like ConsoleEvalE, and stores the result in
+// the value pointed to by v, using the same rules as json.Unmarshal.
+func ConsoleEvalStructE(t testing.TestingT, options *Options, expression string, v interface{}) error {
+	out, err := consoleEvalJsonE(t, options, expression)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(out), v)
+}
+
+// consoleExpressionReplacer puts multi-line expressions on a single line.
+var consoleExpressionReplacer = strings.NewReplacer("\r\n", " ", "\n", " ")
+
+// consoleEvalJsonE evaluates the given expression with terraform console and returns the result as JSON. The
+// expression is wrapped in jsonencode, so that the result has the same format for every type and Terraform version.
+func consoleEvalJsonE(t testing.TestingT, options *Options, expression string) (string, error) {
+	options, args := GetCommonOptions(options, FormatArgs(options, "console")...)
+
+	args, cleanup, err := renderVarFileTemplates(options, args)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	options.Logger.Logf(t, "Running %s with args %v and expression %s", options.TerraformBinary, args, expression)
+	cmd := generateCommand(options, args...)
+	// terraform console reads one expression per line, so the expression must be on a single line
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("jsonencode(%s)\n", consoleExpressionReplacer.Replace(expression)))
+
+	out, err := shell.RunCommandAndGetStdOutE(t, cmd)
+	if err != nil {
+		return "", err
+	}
+	return parseConsoleString(out)
+}
+
+// parseConsoleString returns the string that terraform console printed, which is quoted and escaped like an HCL
+// string.
+func parseConsoleString(out string) (string, error) {
+	quoted := strings.TrimSpace(out)
+	if len(quoted) < 2 || !strings.HasPrefix(quoted, `"`) || !strings.HasSuffix(quoted, `"`) {
+		return "", ConsoleOutputNotParsable(out)
+	}
+
+	var builder strings.Builder
+	runes := []rune(quoted[1 : len(quoted)-1])
+	for i := 0; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\\' && i+1 < len(runes):
+			i++
+			switch runes[i] {
+			case 'n':
+				builder.WriteRune('\n')
+			case 'r':
+				builder.WriteRune('\r')
+			case 't':
+				builder.WriteRune('\t')
+			case '"', '\\':
+				builder.WriteRune(runes[i])
+			case 'u', 'U':
+				length := 4
+				if runes[i] == 'U' {
+					length = 8
+				}
+				if i+length >= len(runes) {
+					return "", ConsoleOutputNotParsable(out)
+				}
+				code, err := strconv.ParseUint(string(runes[i+1:i+1+length]), 16, 32)
+				if err != nil {
+					return "", ConsoleOutputNotParsable(out)
+				}
+				builder.WriteRune(rune(code))
+				i += length
+			default:
+				return "", ConsoleOutputNotParsable(out)
+			}
+		case (runes[i] == '$' || runes[i] == '%') && i+2 < len(runes) && runes[i+1] == runes[i] && runes[i+2] == '{':
+			// HCL escapes template sequences by doubling the $ or %
+			builder.WriteRune(runes[i])
+			i++
+		default:
+			builder.WriteRune(runes[i])
+		}
+	}
+	return builder.String(), nil
+}
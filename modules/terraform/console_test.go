@@ -0,0 +1,80 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConsoleString(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		out      string
+		expected string
+	}{
+		{"Object", `"{\"name\":\"test\",\"count\":3}"` + "\n", `{"name":"test","count":3}`},
+		{"Escapes", `"\"line1\\nline2\\t\\u00e9\" <"`, `"line1\nline2\t\u00e9" <`},
+		{"HclEscapes", `"\u00e9\n\\"`, "é\n\\"},
+		{"Templates", `"\"$${var.name} %%{if true}\""`, `"${var.name} %{if true}"`},
+		{"Unicode", `"\"söme chäräcter\""`, `"söme chäräcter"`},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := parseConsoleString(testCase.out)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, actual)
+		})
+	}
+
+	_, err := parseConsoleString("Error: Reference to undeclared local value")
+	assert.Equal(t, ConsoleOutputNotParsable("Error: Reference to undeclared local value"), err)
+}
+
+func TestConsoleEvalEPipesExpressionToConsole(t *testing.T) {
+	t.Parallel()
+
+	// the stub prints the expression it reads from stdin as a JSON string
+	stubPath := filepath.Join(t.TempDir(), "terraform-stub")
+	script := "#!/bin/sh\nif [ \"$1\" = \"console\" ]; then\n  read expression\n  echo \"\\\"[\\\\\\\"$expression\\\\\\\"]\\\"\"\nfi\n"
+	require.NoError(t, os.WriteFile(stubPath, []byte(script), 0755))
+
+	options := &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: stubPath,
+	}
+
+	result, err := ConsoleEvalE(t, options, "upper(\n  local.name\n)")
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"jsonencode(upper(   local.name ))"}, result)
+}
+
+func TestConsoleEval(t *testing.T) {
+	t.Parallel()
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-output", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir: testFolder,
+	}
+
+	InitAndApply(t, options)
+
+	assert.Equal(t, "THIS IS A STRING.", ConsoleEval(t, options, `upper("This is a string.")`))
+	assert.Equal(t, 6.28, ConsoleEval(t, options, "3.14 * 2"))
+	assert.Equal(t, map[string]interface{}{"a": []interface{}{true, nil}}, ConsoleEval(t, options, "{ a = [true, null] }"))
+
+	var values []string
+	ConsoleEvalStruct(t, options, `split(",", "a,b")`, &values)
+	assert.Equal(t, []string{"a", "b"}, values)
+}
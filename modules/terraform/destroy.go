@@ -19,11 +19,43 @@ func TgDestroyAll(t testing.TestingT, options *Options) string {
 	return out
 }
 
-// DestroyE runs terraform destroy with the given options and return stdout/stderr.
+// DestroyE runs terraform destroy with the given options and return stdout/stderr. If AllowNoState is set on the
+// options, this does nothing and returns an empty output when the state is empty or the working directory was never
+// initialized (see StateIsEmptyE).
 func DestroyE(t testing.TestingT, options *Options) (string, error) {
+	if options.AllowNoState {
+		isEmpty, err := StateIsEmptyE(t, options)
+		if err != nil {
+			return "", err
+		}
+		if isEmpty {
+			options.Logger.Logf(t, "The state of %s is empty, so there is nothing to destroy", options.TerraformDir)
+			return "", nil
+		}
+	}
+
 	return RunTerraformCommandE(t, options, FormatArgs(options, "destroy", "-auto-approve", "-input=false")...)
 }
 
+// DestroyTarget runs terraform destroy with the given options, targeting only the resources with the given addresses
+// (and the resources that depend on them) instead of options.Targets, and returns stdout/stderr. This is useful to tear
+// down only parts of a test setup while iterating on it.
+func DestroyTarget(t testing.TestingT, options *Options, targets ...string) string {
+	out, err := DestroyTargetE(t, options, targets...)
+	require.NoError(t, err)
+	return out
+}
+
+// DestroyTargetE runs terraform destroy with the given options, targeting only the resources with the given addresses
+// (and the resources that depend on them) instead of options.Targets, and returns stdout/stderr.
+func DestroyTargetE(t testing.TestingT, options *Options, targets ...string) (string, error) {
+	targetOptions, err := withTargets(options, targets)
+	if err != nil {
+		return "", err
+	}
+	return DestroyE(t, targetOptions)
+}
+
 // TgDestroyAllE runs terragrunt destroy with the given options and return stdout.
 func TgDestroyAllE(t testing.TestingT, options *Options) (string, error) {
 	if options.TerraformBinary != "terragrunt" {
@@ -0,0 +1,74 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeDestroyErrorStub writes a script that can be used as TerraformBinary, which fails the first time it runs the
+// given command with a DependencyViolation error, and succeeds the next times.
+func writeDestroyErrorStub(t *testing.T, command string) string {
+	dir := t.TempDir()
+	stubPath := filepath.Join(dir, "terraform-stub")
+	markerPath := filepath.Join(dir, "attempted")
+	script := fmt.Sprintf(`#!/bin/sh
+if [ "$1" = %q ]; then
+  if [ -f %q ]; then
+    echo "This is not the first attempt, so exiting successfully"
+    exit 0
+  fi
+  touch %q
+  echo "Error: DependencyViolation: resource has a dependent object" >&2
+  exit 1
+fi
+`, command, markerPath, markerPath)
+	require.NoError(t, os.WriteFile(stubPath, []byte(script), 0755))
+	return stubPath
+}
+
+func TestDestroyWithErrorNoRetry(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: writeDestroyErrorStub(t, "destroy"),
+	}
+
+	out, err := DestroyE(t, options)
+	require.Error(t, err)
+	assert.Contains(t, out, "DependencyViolation")
+}
+
+func TestDestroyWithErrorWithDefaultRetry(t *testing.T) {
+	t.Parallel()
+
+	options := WithDefaultRetryableErrors(t, &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: writeDestroyErrorStub(t, "destroy"),
+	})
+	options.TimeBetweenRetries = time.Millisecond
+
+	out := Destroy(t, options)
+	assert.Contains(t, out, "This is not the first attempt, so exiting successfully")
+	assert.NotContains(t, options.RetryableTerraformErrors, ".*DependencyViolation.*")
+}
+
+func TestApplyWithDestroyErrorWithDefaultRetry(t *testing.T) {
+	t.Parallel()
+
+	options := WithDefaultRetryableErrors(t, &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: writeDestroyErrorStub(t, "apply"),
+	})
+	options.TimeBetweenRetries = time.Millisecond
+
+	out, err := ApplyE(t, options)
+	require.Error(t, err)
+	assert.Contains(t, out, "DependencyViolation")
+}
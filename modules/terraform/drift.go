@@ -0,0 +1,151 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ResourceDrift describes how a resource changed outside of Terraform since the last apply.
+type ResourceDrift struct {
+	Address string                    // The full address of the resource (e.g. module.foo.aws_instance.bar)
+	Actions tfjson.Actions            // The change detected by the refresh (e.g. update for a modified resource, delete for a removed one)
+	Changes map[string]AttributeDrift // The top-level attributes whose values changed, keyed by attribute name
+}
+
+// AttributeDrift is the value of an attribute in the state and the actual value found by the refresh.
+type AttributeDrift struct {
+	Before interface{} // The value in the Terraform state
+	After  interface{} // The actual value of the resource
+}
+
+// AssertNoDrift runs terraform plan -refresh-only with the given options and fails the test with a description of the
+// drifted resources and attributes if any resource changed outside of Terraform since the last apply. This is useful
+// to check that a module is idempotent or that an external change was caught.
+func AssertNoDrift(t testing.TestingT, options *Options) {
+	drift, err := GetDriftE(t, options)
+	require.NoError(t, err)
+	assert.Emptyf(t, drift, "Detected drift in %d resource(s):\n%s", len(drift), formatResourceDrift(drift))
+}
+
+// GetDrift runs terraform plan -refresh-only with the given options and returns the resources that changed outside of
+// Terraform since the last apply, sorted by address. This will fail the test if there is an error in the command.
+func GetDrift(t testing.TestingT, options *Options) []ResourceDrift {
+	drift, err := GetDriftE(t, options)
+	require.NoError(t, err)
+	return drift
+}
+
+// GetDriftE runs terraform plan -refresh-only with the given options and returns the resources that changed outside of
+// Terraform since the last apply, sorted by address.
+func GetDriftE(t testing.TestingT, options *Options) ([]ResourceDrift, error) {
+	planFile, err := os.CreateTemp("", "terratest-drift-plan-")
+	if err != nil {
+		return nil, err
+	}
+	planFile.Close()
+	defer os.Remove(planFile.Name())
+
+	planOptions, err := options.Clone()
+	if err != nil {
+		return nil, err
+	}
+	planOptions.PlanFilePath = planFile.Name()
+
+	exitCode, err := GetExitCodeForTerraformCommandE(t, planOptions, FormatArgs(planOptions, "plan", "-input=false", "-refresh-only", "-detailed-exitcode")...)
+	if err != nil {
+		return nil, err
+	}
+	if exitCode == DefaultSuccessExitCode {
+		return []ResourceDrift{}, nil
+	}
+	if exitCode != TerraformPlanChangesPresentExitCode {
+		return nil, fmt.Errorf("terraform plan -refresh-only failed with exit code %d", exitCode)
+	}
+
+	plan, err := ShowWithStructE(t, planOptions)
+	if err != nil {
+		return nil, err
+	}
+	return parseResourceDrift(plan.RawPlan.ResourceDrift), nil
+}
+
+// parseResourceDrift converts the resource drift of a plan to a list of ResourceDrift sorted by address.
+func parseResourceDrift(resourceChanges []*tfjson.ResourceChange) []ResourceDrift {
+	drift := []ResourceDrift{}
+	for _, resourceChange := range resourceChanges {
+		if resourceChange == nil || resourceChange.Change == nil || resourceChange.Change.Actions.NoOp() {
+			continue
+		}
+		drift = append(drift, ResourceDrift{
+			Address: resourceChange.Address,
+			Actions: resourceChange.Change.Actions,
+			Changes: getAttributeDrift(resourceChange.Change.Before, resourceChange.Change.After),
+		})
+	}
+	sort.Slice(drift, func(i, j int) bool { return drift[i].Address < drift[j].Address })
+	return drift
+}
+
+// getAttributeDrift returns the top-level attributes whose values differ between the given before and after values
+// of a resource.
+func getAttributeDrift(before interface{}, after interface{}) map[string]AttributeDrift {
+	beforeMap, _ := before.(map[string]interface{})
+	afterMap, _ := after.(map[string]interface{})
+
+	changes := map[string]AttributeDrift{}
+	for name, beforeValue := range beforeMap {
+		if afterValue := afterMap[name]; !reflect.DeepEqual(beforeValue, afterValue) {
+			changes[name] = AttributeDrift{Before: beforeValue, After: afterValue}
+		}
+	}
+	for name, afterValue := range afterMap {
+		if _, inBefore := beforeMap[name]; !inBefore && afterValue != nil {
+			changes[name] = AttributeDrift{After: afterValue}
+		}
+	}
+	return changes
+}
+
+// formatResourceDrift renders the given drift as a human readable diff, e.g.:
+//
+//	aws_instance.foo (update)
+//	  instance_type: "t3.micro" => "t3.small"
+func formatResourceDrift(drift []ResourceDrift) string {
+	var builder strings.Builder
+	for _, resourceDrift := range drift {
+		actions := []string{}
+		for _, action := range resourceDrift.Actions {
+			actions = append(actions, string(action))
+		}
+		fmt.Fprintf(&builder, "  %s (%s)\n", resourceDrift.Address, strings.Join(actions, ", "))
+
+		names := []string{}
+		for name := range resourceDrift.Changes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			change := resourceDrift.Changes[name]
+			fmt.Fprintf(&builder, "    %s: %s => %s\n", name, formatDriftValue(change.Before), formatDriftValue(change.After))
+		}
+	}
+	return builder.String()
+}
+
+// formatDriftValue renders the given attribute value as JSON, which is close to how Terraform shows values.
+func formatDriftValue(value interface{}) string {
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(valueJSON)
+}
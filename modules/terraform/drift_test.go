@@ -0,0 +1,86 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/files"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDrift(t *testing.T) {
+	t.Parallel()
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-drift", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir: testFolder,
+	}
+	defer Destroy(t, options)
+
+	InitAndApply(t, options)
+	AssertNoDrift(t, options)
+
+	// Remove the file outside of Terraform
+	require.NoError(t, os.Remove(filepath.Join(testFolder, "drift.txt")))
+
+	drift := GetDrift(t, options)
+	require.Len(t, drift, 1)
+	assert.Equal(t, "local_file.test", drift[0].Address)
+	assert.Equal(t, tfjson.Actions{tfjson.ActionDelete}, drift[0].Actions)
+}
+
+func TestParseResourceDrift(t *testing.T) {
+	t.Parallel()
+
+	drift := parseResourceDrift([]*tfjson.ResourceChange{
+		{
+			Address: "null_resource.unchanged",
+			Change:  &tfjson.Change{Actions: tfjson.Actions{tfjson.ActionNoop}},
+		},
+		{
+			Address: "aws_instance.foo",
+			Change: &tfjson.Change{
+				Actions: tfjson.Actions{tfjson.ActionUpdate},
+				Before:  map[string]interface{}{"instance_type": "t3.micro", "tags": map[string]interface{}{"Name": "foo"}},
+				After:   map[string]interface{}{"instance_type": "t3.small", "tags": map[string]interface{}{"Name": "foo"}, "ipv6": true},
+			},
+		},
+		{
+			Address: "aws_s3_bucket.bar",
+			Change: &tfjson.Change{
+				Actions: tfjson.Actions{tfjson.ActionDelete},
+				Before:  map[string]interface{}{"bucket": "bar"},
+			},
+		},
+	})
+
+	assert.Equal(t, []ResourceDrift{
+		{
+			Address: "aws_instance.foo",
+			Actions: tfjson.Actions{tfjson.ActionUpdate},
+			Changes: map[string]AttributeDrift{
+				"instance_type": {Before: "t3.micro", After: "t3.small"},
+				"ipv6":          {After: true},
+			},
+		},
+		{
+			Address: "aws_s3_bucket.bar",
+			Actions: tfjson.Actions{tfjson.ActionDelete},
+			Changes: map[string]AttributeDrift{
+				"bucket": {Before: "bar"},
+			},
+		},
+	}, drift)
+
+	assert.Equal(t, `  aws_instance.foo (update)
+    instance_type: "t3.micro" => "t3.small"
+    ipv6: null => true
+  aws_s3_bucket.bar (delete)
+    bucket: "bar" => null
+`, formatResourceDrift(drift))
+}
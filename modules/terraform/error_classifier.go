@@ -0,0 +1,179 @@
+package terraform
+
+import (
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/gruntwork-io/terratest/modules/collections"
+)
+
+// ErrorClass is the class of an error of a Terraform command, which decides whether the command is retried.
+type ErrorClass int
+
+const (
+	ErrorUnclassified ErrorClass = iota // No rule matched the error, so the command is not retried
+	ErrorRetryable                      // The error is transient (e.g. throttling), so the command is retried
+	ErrorFatal                          // The error is known to be permanent (e.g. invalid credentials), so the command is never retried
+)
+
+func (class ErrorClass) String() string {
+	switch class {
+	case ErrorRetryable:
+		return "retryable"
+	case ErrorFatal:
+		return "fatal"
+	default:
+		return "unclassified"
+	}
+}
+
+// ErrorClassification is the result of the classification of an error of a Terraform command.
+type ErrorClassification struct {
+	Class   ErrorClass // The class of the error
+	Rule    string     // The name of the rule that classified the error, empty if the error is unclassified
+	Message string     // The explanation of the rule, logged when the command is retried
+}
+
+// ErrorClassifier decides whether a failed Terraform command should be retried. Set it as the ErrorClassifier of the
+// options to use it instead of the RetryableTerraformErrors.
+type ErrorClassifier interface {
+	// Classify classifies the error of a Terraform command (e.g. apply or destroy, see getTerraformCommand) from the
+	// output of the command and the message of the error. Unless RetryOnWarnings is set in the options, the warnings
+	// are removed from both before classifying.
+	Classify(command string, output string, errMessage string) ErrorClassification
+}
+
+// RetryRule is a rule of a RuleClassifier, which classifies the errors that match its pattern.
+type RetryRule struct {
+	Name     string         // The unique name of the rule (e.g. aws-throttling), used in logs and RetryMetrics
+	Pattern  *regexp.Regexp // The pattern to match against the output and the error message of the command
+	Class    ErrorClass     // The class of the errors that match the pattern
+	Message  string         // The explanation of the rule (e.g. Rate limited by the AWS API.)
+	Commands []string       // The Terraform commands (e.g. destroy) the rule applies to. If empty, it applies to all commands.
+}
+
+// RuleClassifier is an ErrorClassifier that classifies errors with a list of rules. If both fatal and retryable rules
+// match an error, the error is fatal. Otherwise, the first matching rule classifies the error.
+type RuleClassifier struct {
+	Rules []RetryRule
+}
+
+// Classify classifies the error of a Terraform command with the rules of the classifier that apply to the command.
+func (classifier *RuleClassifier) Classify(command string, output string, errMessage string) ErrorClassification {
+	classification := ErrorClassification{Class: ErrorUnclassified}
+	for _, rule := range classifier.Rules {
+		if rule.Class == ErrorUnclassified || (len(rule.Commands) > 0 && !collections.ListContains(rule.Commands, command)) {
+			continue
+		}
+		if !(rule.Pattern.MatchString(output) || rule.Pattern.MatchString(errMessage)) {
+			continue
+		}
+		if rule.Class == ErrorFatal {
+			return ErrorClassification{Class: ErrorFatal, Rule: rule.Name, Message: rule.Message}
+		}
+		if classification.Class == ErrorUnclassified {
+			classification = ErrorClassification{Class: rule.Class, Rule: rule.Name, Message: rule.Message}
+		}
+	}
+	return classification
+}
+
+// newRetryableErrorsClassifier returns a RuleClassifier with a retryable rule for each of the given retryable errors,
+// in the format of Options.RetryableTerraformErrors, that applies to the given commands (or to all commands if none
+// are given). The rules are named after their regular expression.
+func newRetryableErrorsClassifier(retryableErrors map[*regexp.Regexp]string, commands ...string) *RuleClassifier {
+	classifier := &RuleClassifier{}
+	for errorRegexp, errorMessage := range retryableErrors {
+		classifier.Rules = append(classifier.Rules, RetryRule{
+			Name:     errorRegexp.String(),
+			Pattern:  errorRegexp,
+			Class:    ErrorRetryable,
+			Message:  errorMessage,
+			Commands: commands,
+		})
+	}
+	// the order of maps is random, so sort the rules to always report the same rule when several match
+	sort.Slice(classifier.Rules, func(i, j int) bool { return classifier.Rules[i].Name < classifier.Rules[j].Name })
+	return classifier
+}
+
+// getErrorClassifier returns the ErrorClassifier of the given options or, if it isn't set, a RuleClassifier with a
+// retryable rule for each of the RetryableTerraformErrors, followed by a retryable rule that only applies to destroy
+// for each of the DefaultRetryableDestroyErrors.
+func getErrorClassifier(options *Options) (ErrorClassifier, error) {
+	if options.ErrorClassifier != nil {
+		return options.ErrorClassifier, nil
+	}
+
+	retryableErrors, err := compileRetryableErrors(options.RetryableTerraformErrors)
+	if err != nil {
+		return nil, err
+	}
+	destroyErrors, err := compileRetryableErrors(DefaultRetryableDestroyErrors)
+	if err != nil {
+		return nil, err
+	}
+
+	classifier := newRetryableErrorsClassifier(retryableErrors)
+	for _, rule := range newRetryableErrorsClassifier(destroyErrors, "destroy").Rules {
+		if _, exists := options.RetryableTerraformErrors[rule.Name]; !exists {
+			classifier.Rules = append(classifier.Rules, rule)
+		}
+	}
+	return classifier, nil
+}
+
+// compileRetryableErrors compiles the regular expressions of the given retryable errors, in the format of
+// Options.RetryableTerraformErrors.
+func compileRetryableErrors(retryableErrors map[string]string) (map[*regexp.Regexp]string, error) {
+	retryableErrorsRegexp := map[*regexp.Regexp]string{}
+	for errorStr, errorMessage := range retryableErrors {
+		errorRegex, err := regexp.Compile(errorStr)
+		if err != nil {
+			return nil, err
+		}
+		retryableErrorsRegexp[errorRegex] = errorMessage
+	}
+	return retryableErrorsRegexp, nil
+}
+
+// RetryMetrics counts how many times each rule classified an error of a Terraform command, e.g. to find out which
+// transient errors a test suite runs into. Set the same RetryMetrics in the options of all the tests to count them
+// across the whole run. It is safe for concurrent use.
+type RetryMetrics struct {
+	mutex  sync.Mutex
+	counts map[string]int
+}
+
+// NewRetryMetrics returns an empty RetryMetrics.
+func NewRetryMetrics() *RetryMetrics {
+	return &RetryMetrics{counts: map[string]int{}}
+}
+
+// Counts returns how many times each rule classified an error, by rule name. Rules that never matched are omitted.
+func (metrics *RetryMetrics) Counts() map[string]int {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	counts := make(map[string]int, len(metrics.counts))
+	for rule, count := range metrics.counts {
+		counts[rule] = count
+	}
+	return counts
+}
+
+// record counts the given classification, if a rule classified the error.
+func (metrics *RetryMetrics) record(classification ErrorClassification) {
+	if metrics == nil || classification.Rule == "" {
+		return
+	}
+
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	if metrics.counts == nil {
+		metrics.counts = map[string]int{}
+	}
+	metrics.counts[classification.Rule]++
+}
@@ -0,0 +1,117 @@
+package terraform
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleClassifierPrefersFatalRules(t *testing.T) {
+	t.Parallel()
+
+	classifier := &RuleClassifier{Rules: []RetryRule{
+		{Name: "timeout", Pattern: regexp.MustCompile("timeout"), Class: ErrorRetryable, Message: "Timed out."},
+		{Name: "access-denied", Pattern: regexp.MustCompile("AccessDenied"), Class: ErrorFatal, Message: "Access denied."},
+	}}
+
+	assert.Equal(t, ErrorClassification{Class: ErrorRetryable, Rule: "timeout", Message: "Timed out."}, classifier.Classify("apply", "Error: timeout", ""))
+	assert.Equal(t, ErrorClassification{Class: ErrorFatal, Rule: "access-denied", Message: "Access denied."}, classifier.Classify("apply", "Error: timeout", "AccessDenied: not allowed"))
+	assert.Equal(t, ErrorClassification{Class: ErrorUnclassified}, classifier.Classify("apply", "Error: invalid", "exit status 1"))
+}
+
+func TestRuleClassifierOnlyAppliesRulesToTheirCommands(t *testing.T) {
+	t.Parallel()
+
+	classifier := &RuleClassifier{Rules: []RetryRule{
+		{Name: "in-use", Pattern: regexp.MustCompile("is currently in use"), Class: ErrorRetryable, Message: "In use.", Commands: []string{"destroy"}},
+	}}
+
+	assert.Equal(t, ErrorClassification{Class: ErrorRetryable, Rule: "in-use", Message: "In use."}, classifier.Classify("destroy", "Error: subnet is currently in use", ""))
+	assert.Equal(t, ErrorClassification{Class: ErrorUnclassified}, classifier.Classify("apply", "Error: subnet is currently in use", ""))
+}
+
+func TestGetTerraformCommand(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "apply", getTerraformCommand([]string{"apply", "-auto-approve"}))
+	assert.Equal(t, "destroy", getTerraformCommand([]string{"-chdir=dir", "destroy"}))
+	assert.Equal(t, "destroy", getTerraformCommand([]string{"run-all", "destroy", "--terragrunt-non-interactive"}))
+	assert.Equal(t, "destroy", getTerraformCommand([]string{"destroy-all"}))
+	assert.Equal(t, "", getTerraformCommand(nil))
+}
+
+func TestBuiltinRetryRules(t *testing.T) {
+	t.Parallel()
+
+	classifier := &RuleClassifier{Rules: GetBuiltinRetryRules(t, LatestBuiltinRetryRulesVersion)}
+
+	testCases := []struct {
+		command  string
+		output   string
+		expected ErrorClass
+		rule     string
+	}{
+		{"apply", "Error: creating EC2 Instance: operation error EC2: RunInstances, api error RequestLimitExceeded: Request limit exceeded.", ErrorRetryable, "aws-throttling"},
+		{"apply", "Error: googleapi: Error 429: Quota exceeded for quota metric 'Queries', rateLimitExceeded", ErrorRetryable, "gcp-throttling"},
+		{"apply", "Error: Provider produced inconsistent result after apply", ErrorRetryable, "inconsistent-result"},
+		{"apply", "Error: The plugin encountered an error, and failed to respond to the plugin.(*GRPCProvider).ApplyResourceChange call.", ErrorRetryable, "plugin-crash"},
+		{"apply", "Error: creating Lambda Function: InvalidParameterValueException: The role defined for the function cannot be assumed by Lambda.", ErrorRetryable, "aws-iam-propagation"},
+		{"apply", "Error: No valid credential sources found", ErrorFatal, "invalid-credentials"},
+		{"apply", "Error: creating IAM Role Policy Attachment: AccessDenied: User is not authorized to perform: iam:AttachRolePolicy", ErrorRetryable, "access-denied"},
+		{"apply", "Error: creating S3 Bucket: ExpiredToken: The security token included in the request is expired (connection reset by peer)", ErrorFatal, "invalid-credentials"},
+		{"apply", "Error: Unsupported argument", ErrorFatal, "invalid-configuration"},
+		{"apply", "Error: creating S3 Bucket: BucketAlreadyExists", ErrorUnclassified, ""},
+		{"destroy", "Error: deleting EC2 Subnet: DependencyViolation: The subnet has dependencies and cannot be deleted.", ErrorRetryable, "dependency-violation"},
+		{"apply", "Error: deleting EC2 Subnet: DependencyViolation: The subnet has dependencies and cannot be deleted.", ErrorUnclassified, ""},
+	}
+
+	for _, testCase := range testCases {
+		classification := classifier.Classify(testCase.command, testCase.output, "exit status 1")
+		assert.Equal(t, testCase.expected, classification.Class, testCase.output)
+		assert.Equal(t, testCase.rule, classification.Rule, testCase.output)
+	}
+
+	_, err := GetBuiltinRetryRulesE("0")
+	assert.Equal(t, UnknownRetryRulesVersion("0"), err)
+}
+
+func TestRunTerraformCommandEClassifiesErrorsAndRecordsMetrics(t *testing.T) {
+	t.Parallel()
+
+	metrics := NewRetryMetrics()
+	options := WithBuiltinRetryRules(t, &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: writeTerraformStub(t, "apply", "Error: api error Throttling: Rate exceeded", 1),
+		RetryMetrics:    metrics,
+	})
+	options.TimeBetweenRetries = 0
+	require.Same(t, metrics, options.RetryMetrics)
+
+	_, err := ApplyE(t, options)
+	require.Error(t, err)
+	assert.Equal(t, map[string]int{"aws-throttling": 4}, metrics.Counts())
+
+	options.TerraformBinary = writeTerraformStub(t, "apply", "Error: No valid credential sources found", 1)
+	_, err = ApplyE(t, options)
+	require.Error(t, err)
+	assert.Equal(t, map[string]int{"aws-throttling": 4, "invalid-credentials": 1}, metrics.Counts())
+}
+
+func TestRunTerraformCommandERecordsRetryableTerraformErrors(t *testing.T) {
+	t.Parallel()
+
+	metrics := NewRetryMetrics()
+	options := &Options{
+		TerraformDir:             t.TempDir(),
+		TerraformBinary:          writeTerraformStub(t, "apply", "Error: read: connection reset by peer", 1),
+		RetryableTerraformErrors: map[string]string{".*connection reset by peer.*": "Network error."},
+		MaxRetries:               1,
+		RetryMetrics:             metrics,
+	}
+
+	_, err := ApplyE(t, options)
+	require.Error(t, err)
+	assert.Equal(t, map[string]int{".*connection reset by peer.*": 2}, metrics.Counts())
+}
@@ -3,6 +3,7 @@ package terraform
 import (
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 // TgInvalidBinary occurs when a terragrunt function is called and the TerraformBinary is
@@ -101,3 +102,217 @@ type WorkspaceDoesNotExist string
 func (err WorkspaceDoesNotExist) Error() string {
 	return fmt.Sprintf("The workspace %q does not exist.", string(err))
 }
+
+// ModuleManifestNotFound occurs when the module manifest written by terraform init can't be found, usually because
+// init hasn't run yet
+type ModuleManifestNotFound string
+
+func (path ModuleManifestNotFound) Error() string {
+	return fmt.Sprintf("module manifest %s not found: run terraform init first", string(path))
+}
+
+// MissingVariables occurs when required variables are not provided through Vars, VarFiles, an automatically loaded var
+// file or a TF_VAR_ environment variable
+type MissingVariables struct {
+	Names []string
+}
+
+func (err MissingVariables) Error() string {
+	return fmt.Sprintf("required variables not provided through Vars, VarFiles or TF_VAR_ environment variables: %s", strings.Join(err.Names, ", "))
+}
+
+// CloudApiError occurs when the API of Terraform Cloud or Terraform Enterprise responds with an error status code
+type CloudApiError struct {
+	Method     string
+	Url        string
+	StatusCode int
+	Body       string
+}
+
+func (err CloudApiError) Error() string {
+	return fmt.Sprintf("%s %s returned status %d: %s", err.Method, err.Url, err.StatusCode, err.Body)
+}
+
+// CloudRunNotFound occurs when a Terraform Cloud or Terraform Enterprise workspace has no runs
+type CloudRunNotFound string
+
+func (workspaceID CloudRunNotFound) Error() string {
+	return fmt.Sprintf("workspace %s has no runs", string(workspaceID))
+}
+
+// CloudRunFailed occurs when a Terraform Cloud or Terraform Enterprise run finishes without succeeding
+type CloudRunFailed struct {
+	RunID  string
+	Status string
+}
+
+func (err CloudRunFailed) Error() string {
+	return fmt.Sprintf("run %s finished with status %s", err.RunID, err.Status)
+}
+
+// StateResourceNotFound occurs when the state has no resource with the given address
+type StateResourceNotFound string
+
+func (address StateResourceNotFound) Error() string {
+	return fmt.Sprintf("resource %s not found in state", string(address))
+}
+
+// StateMvFailed occurs when terraform state mv fails to move the resources at the source address to the destination
+// address. If the source address matches several resources, some of them may have been moved before the failure.
+type StateMvFailed struct {
+	Source      string
+	Destination string
+	Underlying  error
+}
+
+func (err StateMvFailed) Error() string {
+	return fmt.Sprintf("failed to move %s to %s in state, resources that were already moved stay moved: %v", err.Source, err.Destination, err.Underlying)
+}
+
+func (err StateMvFailed) Unwrap() error {
+	return err.Underlying
+}
+
+// ResourceAlreadyManaged occurs when importing a resource whose address is already in the state
+type ResourceAlreadyManaged string
+
+func (address ResourceAlreadyManaged) Error() string {
+	return fmt.Sprintf("resource %s is already managed by Terraform: remove it from the state before importing it", string(address))
+}
+
+// RemoteObjectNotFound occurs when importing a resource with the ID of a remote object that does not exist
+type RemoteObjectNotFound struct {
+	Address string
+	ID      string
+}
+
+func (err RemoteObjectNotFound) Error() string {
+	return fmt.Sprintf("cannot import %s: remote object with ID %q does not exist", err.Address, err.ID)
+}
+
+// ApplyValidationFailed occurs when the apply or the validation of ApplyAndValidate fails
+type ApplyValidationFailed struct {
+	Underlying   error  // The error of the apply or the validation
+	ArtifactsDir string // The directory with the plan and state at the time of the failure, empty if they couldn't be saved
+	RolledBack   bool   // Whether the known-good RollbackVars were re-applied successfully
+	RollbackErr  error  // The error of the rollback, if any
+}
+
+func (err ApplyValidationFailed) Error() string {
+	message := fmt.Sprintf("apply failed validation: %v (plan and state saved to %q)", err.Underlying, err.ArtifactsDir)
+	if err.RolledBack {
+		message += "; rolled back to the known-good vars"
+	} else if err.RollbackErr != nil {
+		message += fmt.Sprintf("; rollback failed: %v", err.RollbackErr)
+	}
+	return message
+}
+
+func (err ApplyValidationFailed) Unwrap() error {
+	return err.Underlying
+}
+
+// ConsoleOutputNotParsable occurs when the output of terraform console is not the string returned by jsonencode
+type ConsoleOutputNotParsable string
+
+func (err ConsoleOutputNotParsable) Error() string {
+	return fmt.Sprintf("could not parse the output of terraform console: %q", string(err))
+}
+
+// StackModuleNotFound occurs when a module of a stack depends on a module that is not in the stack
+type StackModuleNotFound struct {
+	Module       string
+	DependencyOf string
+}
+
+func (err StackModuleNotFound) Error() string {
+	return fmt.Sprintf("module %s, a dependency of module %s, is not in the stack", err.Module, err.DependencyOf)
+}
+
+// StackDependencyCycle occurs when the modules of a stack depend on each other. It contains the modules of the cycle.
+type StackDependencyCycle []string
+
+func (err StackDependencyCycle) Error() string {
+	return fmt.Sprintf("the modules of the stack have a dependency cycle: %s", strings.Join(err, " -> "))
+}
+
+// StackModuleSkipped occurs when a module of a stack is not applied (or destroyed) because a module it depends on
+// (or that depends on it) failed
+type StackModuleSkipped struct {
+	Module       string
+	FailedModule string
+}
+
+func (err StackModuleSkipped) Error() string {
+	return fmt.Sprintf("module %s was skipped because module %s failed", err.Module, err.FailedModule)
+}
+
+// UnsupportedBackendType occurs when a backend helper is called with a backend type it doesn't support
+type UnsupportedBackendType string
+
+func (err UnsupportedBackendType) Error() string {
+	return fmt.Sprintf("unsupported backend type %q: expected one of %s, %s or %s", string(err), BackendLocal, BackendS3, BackendGCS)
+}
+
+// UnknownRetryRulesVersion occurs when there are no built-in retry rules with the given version
+type UnknownRetryRulesVersion string
+
+func (err UnknownRetryRulesVersion) Error() string {
+	return fmt.Sprintf("unknown version %q of the built-in retry rules, the latest version is %s", string(err), LatestBuiltinRetryRulesVersion)
+}
+
+// LockFileNotFound occurs when the TerraformDir doesn't contain a .terraform.lock.hcl file to check
+type LockFileNotFound string
+
+func (err LockFileNotFound) Error() string {
+	return fmt.Sprintf("lock file %s not found: run terraform init and commit the lock file", string(err))
+}
+
+// LockFileInconsistent occurs when terraform providers lock changes the lock file, e.g. because it is missing the
+// hashes of the providers for some of the platforms
+type LockFileInconsistent struct {
+	Path         string
+	Platforms    []string
+	AddedLines   []string
+	RemovedLines []string
+}
+
+func (err LockFileInconsistent) Error() string {
+	message := fmt.Sprintf("lock file %s is not consistent for platforms %s: terraform providers lock would change it", err.Path, strings.Join(err.Platforms, ", "))
+	if len(err.AddedLines) > 0 {
+		message += fmt.Sprintf("; added lines:\n  %s", strings.Join(err.AddedLines, "\n  "))
+	}
+	if len(err.RemovedLines) > 0 {
+		message += fmt.Sprintf("; removed lines:\n  %s", strings.Join(err.RemovedLines, "\n  "))
+	}
+	return message
+}
+
+// TerraformVersionNotFound occurs when InstallTerraform can't find the version to install, because it isn't set in the
+// options, in a version file or in the required_version of the given dir
+type TerraformVersionNotFound string
+
+func (err TerraformVersionNotFound) Error() string {
+	return fmt.Sprintf("no terraform version to install: set TerraformVersion in the options, add a %s file or set required_version in %s", TerraformVersionFile, string(err))
+}
+
+// NoMatchingTerraformVersion occurs when no published version of terraform (or tofu) matches the version constraint
+type NoMatchingTerraformVersion struct {
+	Product    string
+	Constraint string
+}
+
+func (err NoMatchingTerraformVersion) Error() string {
+	return fmt.Sprintf("no version of %s matches the version constraint %q", err.Product, err.Constraint)
+}
+
+// TerraformChecksumMismatch occurs when the checksum of a downloaded release doesn't match its published checksum
+type TerraformChecksumMismatch struct {
+	File     string
+	Expected string
+	Actual   string
+}
+
+func (err TerraformChecksumMismatch) Error() string {
+	return fmt.Sprintf("checksum of %s is %s, expected %s", err.File, err.Actual, err.Expected)
+}
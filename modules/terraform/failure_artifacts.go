@@ -0,0 +1,72 @@
+package terraform
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// saveAndLogFailureArtifacts saves the failure artifacts of the given options with saveFailureArtifacts, logs where
+// they were saved (or why they couldn't be), and returns the path of the directory they were saved to, if any.
+func saveAndLogFailureArtifacts(t testing.TestingT, options *Options, output string) string {
+	artifactsDir, err := saveFailureArtifacts(t, options, output)
+	if err != nil {
+		options.Logger.Logf(t, "Failed to save the failure artifacts of %s: %v", options.TerraformDir, err)
+	}
+	if artifactsDir != "" {
+		options.Logger.Logf(t, "Saved the output, plan and state of %s to %s", options.TerraformDir, artifactsDir)
+	}
+	return artifactsDir
+}
+
+// saveFailureArtifacts saves the given output of the failed command (output.txt), the output of terraform plan
+// (plan.txt), the plan file (plan.out) and its JSON representation (plan.json), and the state (state.json) of the given
+// options to options.FailureArtifactsDir, or a new temp dir named after the test if it's not set, and returns the path
+// of that directory.
+func saveFailureArtifacts(t testing.TestingT, options *Options, output string) (string, error) {
+	artifactsDir := options.FailureArtifactsDir
+	if artifactsDir == "" {
+		tempDir, err := os.MkdirTemp("", "terratest-failure-artifacts-"+unsafeStateNameCharsRegexp.ReplaceAllString(t.Name(), "_")+"-")
+		if err != nil {
+			return "", err
+		}
+		artifactsDir = tempDir
+	} else if err := os.MkdirAll(artifactsDir, 0755); err != nil {
+		return "", err
+	}
+
+	var outputErr error
+	if output != "" {
+		outputErr = os.WriteFile(filepath.Join(artifactsDir, "output.txt"), []byte(output), 0644)
+	}
+
+	artifactOptions, err := options.Clone()
+	if err != nil {
+		return "", err
+	}
+
+	artifactOptions.PlanFilePath = ""
+	state, stateErr := ShowE(t, artifactOptions)
+	if stateErr == nil {
+		stateErr = os.WriteFile(filepath.Join(artifactsDir, "state.json"), []byte(state), 0644)
+	}
+
+	artifactOptions.PlanFilePath = filepath.Join(artifactsDir, "plan.out")
+	plan, planErr := PlanE(t, artifactOptions)
+	if plan != "" {
+		if err := os.WriteFile(filepath.Join(artifactsDir, "plan.txt"), []byte(plan), 0644); err != nil && planErr == nil {
+			planErr = err
+		}
+	}
+	if planErr == nil {
+		var planJSON string
+		planJSON, planErr = ShowE(t, artifactOptions)
+		if planErr == nil {
+			planErr = os.WriteFile(filepath.Join(artifactsDir, "plan.json"), []byte(planJSON), 0644)
+		}
+	}
+
+	return artifactsDir, errors.Join(outputErr, stateErr, planErr)
+}
@@ -0,0 +1,67 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitAndApplyESavesFailureArtifacts(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	stubPath := filepath.Join(dir, "terraform-stub")
+	script := `#!/bin/sh
+if [ "$1" = "apply" ]; then
+  echo "Error: creating EC2 Instance: UnauthorizedOperation" >&2
+  exit 1
+fi
+if [ "$1" = "show" ]; then
+  echo "{\"format_version\": \"1.0\", \"args\": \"$*\"}"
+fi
+if [ "$1" = "plan" ]; then
+  echo "Plan: 1 to add, 0 to change, 0 to destroy."
+fi
+`
+	require.NoError(t, os.WriteFile(stubPath, []byte(script), 0755))
+
+	artifactsDir := filepath.Join(dir, "artifacts")
+	options := &Options{
+		TerraformDir:         t.TempDir(),
+		TerraformBinary:      stubPath,
+		FailureArtifactsDir:  artifactsDir,
+		SaveFailureArtifacts: true,
+	}
+	_, err := InitAndApplyE(t, options)
+	require.Error(t, err)
+
+	output, err := os.ReadFile(filepath.Join(artifactsDir, "output.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(output), "UnauthorizedOperation")
+
+	plan, err := os.ReadFile(filepath.Join(artifactsDir, "plan.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(plan), "1 to add")
+
+	planJSON, err := os.ReadFile(filepath.Join(artifactsDir, "plan.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(planJSON), filepath.Join(artifactsDir, "plan.out"))
+
+	state, err := os.ReadFile(filepath.Join(artifactsDir, "state.json"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(state), "plan.out")
+	assert.Empty(t, options.PlanFilePath)
+}
+
+func TestInitAndApplyEDoesNotSaveFailureArtifactsByDefault(t *testing.T) {
+	t.Parallel()
+
+	stubPath := writeTerraformStub(t, "apply", "Error: failed", 1)
+	artifactsDir := filepath.Join(t.TempDir(), "artifacts")
+	_, err := InitAndApplyE(t, &Options{TerraformDir: t.TempDir(), TerraformBinary: stubPath, FailureArtifactsDir: artifactsDir})
+	require.Error(t, err)
+	assert.NoDirExists(t, artifactsDir)
+}
@@ -0,0 +1,57 @@
+package terraform
+
+import (
+	"github.com/gruntwork-io/terratest/modules/shell"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// formatCheckDiffExitCode is the exit code of `terraform fmt -check` when some files are not formatted.
+const formatCheckDiffExitCode = 3
+
+// FormatCheck runs terraform fmt -check -recursive -diff in the TerraformDir of the given options, without modifying
+// any files. Returns the diff of the changes that terraform fmt would make and whether any file needs to be
+// reformatted. This will fail the test if the command fails for any other reason.
+func FormatCheck(t testing.TestingT, options *Options) (string, bool) {
+	diff, needsFormatting, err := FormatCheckE(t, options)
+	require.NoError(t, err)
+	return diff, needsFormatting
+}
+
+// FormatCheckE runs terraform fmt -check -recursive -diff in the TerraformDir of the given options, without modifying
+// any files. Returns the diff of the changes that terraform fmt would make and whether any file needs to be
+// reformatted. Unformatted files are not reported as an error.
+func FormatCheckE(t testing.TestingT, options *Options) (string, bool, error) {
+	return formatCheckE(t, options, true)
+}
+
+// formatCheckE runs terraform fmt -check -diff in the TerraformDir of the given options, and in its subdirectories if
+// recursive is true. See FormatCheckE.
+func formatCheckE(t testing.TestingT, options *Options, recursive bool) (string, bool, error) {
+	args := []string{"fmt", "-check", "-diff"}
+	if recursive {
+		args = append(args, "-recursive")
+	}
+	if options.NoColor {
+		args = append(args, "-no-color")
+	}
+
+	options, args = GetCommonOptions(options, args...)
+	options.Logger.Logf(t, "Running %s with args %v", options.TerraformBinary, args)
+	out, err := shell.RunCommandAndGetStdOutE(t, generateCommand(options, args...))
+	return parseFormatCheckResult(out, err)
+}
+
+// parseFormatCheckResult converts the result of terraform fmt -check to the diff and whether any file needs to be
+// reformatted. The exit code signaling unformatted files is not an error.
+func parseFormatCheckResult(out string, err error) (string, bool, error) {
+	if err == nil {
+		return out, false, nil
+	}
+
+	exitCode, exitCodeErr := shell.GetExitCodeForRunCommandError(err)
+	if exitCodeErr == nil && exitCode == formatCheckDiffExitCode {
+		return out, true, nil
+	}
+	return out, false, err
+}
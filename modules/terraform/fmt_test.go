@@ -0,0 +1,63 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatCheckDetectsUnformattedFiles(t *testing.T) {
+	t.Parallel()
+
+	testFolder := t.TempDir()
+	unformatted := "variable   \"name\" {\ntype=string\n}\n"
+	mainFile := filepath.Join(testFolder, "main.tf")
+	require.NoError(t, os.WriteFile(mainFile, []byte(unformatted), 0644))
+
+	options := &Options{
+		TerraformDir: testFolder,
+		NoColor:      true,
+	}
+
+	diff, needsFormatting := FormatCheck(t, options)
+	assert.True(t, needsFormatting)
+	assert.Contains(t, diff, "main.tf")
+
+	// The check must not reformat the files
+	contents, err := os.ReadFile(mainFile)
+	require.NoError(t, err)
+	assert.Equal(t, unformatted, string(contents))
+}
+
+func TestFormatCheckAcceptsFormattedFiles(t *testing.T) {
+	t.Parallel()
+
+	testFolder := t.TempDir()
+	formatted := "variable \"name\" {\n  type = string\n}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(testFolder, "main.tf"), []byte(formatted), 0644))
+
+	options := &Options{
+		TerraformDir: testFolder,
+		NoColor:      true,
+	}
+
+	diff, needsFormatting := FormatCheck(t, options)
+	assert.False(t, needsFormatting)
+	assert.Empty(t, diff)
+}
+
+func TestFormatCheckEReturnsErrorOnFailure(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: writeTerraformStub(t, "fmt", "Error: Invalid file", 2),
+	}
+
+	_, needsFormatting, err := FormatCheckE(t, options)
+	require.Error(t, err)
+	assert.False(t, needsFormatting)
+}
@@ -11,6 +11,15 @@ import (
 
 const runAllCmd = "run-all"
 
+// varsVarFileArg is the -var-file argument that FormatArgs passes instead of -var flags when VarsInVarFile is set. It's
+// replaced with the path of a var file containing the Vars right before running the command.
+const varsVarFileArg = "<terratest-vars.tfvars>"
+
+// sensitiveVarsVarFileArg is the -var-file argument that FormatArgs passes for the Vars marked with Sensitive, instead of
+// -var flags, so that their values don't show up in the logged command. It's replaced with the path of a var file
+// containing those Vars right before running the command.
+const sensitiveVarsVarFileArg = "<terratest-sensitive-vars.tfvars>"
+
 // TerraformCommandsWithLockSupport is a list of all the Terraform commands that
 // can obtain locks on Terraform state
 var TerraformCommandsWithLockSupport = []string{
@@ -36,8 +45,54 @@ var TerraformCommandsWithPlanFileSupport = []string{
 	"graph",
 }
 
+// TerraformCommandsWithCompactWarningsSupport is a list of all the Terraform commands that support the
+// -compact-warnings flag
+var TerraformCommandsWithCompactWarningsSupport = []string{
+	"plan",
+	"plan-all",
+	"apply",
+	"apply-all",
+	"destroy",
+	"destroy-all",
+	"refresh",
+}
+
+// TerraformCommandsWithTargetSupport is a list of all the Terraform commands that support the -target flag
+var TerraformCommandsWithTargetSupport = []string{
+	"plan",
+	"plan-all",
+	"apply",
+	"apply-all",
+	"destroy",
+	"destroy-all",
+	"refresh",
+}
+
+// TerraformCommandsWithReplaceSupport is a list of all the Terraform commands that support the -replace flag
+var TerraformCommandsWithReplaceSupport = []string{
+	"plan",
+	"plan-all",
+	"apply",
+	"apply-all",
+}
+
+// TerraformCommandsWithInputSupport is a list of all the Terraform commands that can prompt for input, and so support
+// the -input flag
+var TerraformCommandsWithInputSupport = []string{
+	"plan",
+	"plan-all",
+	"apply",
+	"apply-all",
+	"destroy",
+	"destroy-all",
+	"init",
+	"refresh",
+	"import",
+}
+
 // FormatArgs converts the inputs to a format palatable to terraform. This includes converting the given vars to the
-// format the Terraform CLI expects (-var key=value).
+// format the Terraform CLI expects (-var key=value), and disabling interactive input (-input=false) for the commands
+// that would otherwise prompt for missing variables, unless the -input flag is already set in args.
 func FormatArgs(options *Options, args ...string) []string {
 	var terraformArgs []string
 	commandType := args[0]
@@ -49,28 +104,65 @@ func FormatArgs(options *Options, args ...string) []string {
 	}
 	lockSupported := collections.ListContains(TerraformCommandsWithLockSupport, commandType)
 	planFileSupported := collections.ListContains(TerraformCommandsWithPlanFileSupport, commandType)
+	compactWarningsSupported := collections.ListContains(TerraformCommandsWithCompactWarningsSupport, commandType)
+	inputSupported := collections.ListContains(TerraformCommandsWithInputSupport, commandType)
+	targetSupported := collections.ListContains(TerraformCommandsWithTargetSupport, commandType)
+	replaceSupported := collections.ListContains(TerraformCommandsWithReplaceSupport, commandType)
 
 	// Include -var and -var-file flags unless we're running 'apply' with a plan file
 	includeVars := !(commandType == "apply" && len(options.PlanFilePath) > 0)
 
-	terraformArgs = append(terraformArgs, args...)
+	if inputSupported && !hasInputArg(args) {
+		// Insert the flag right after the command, as some commands (e.g. import) stop parsing flags at the first
+		// positional argument
+		commandLength := 1
+		if args[0] == runAllCmd {
+			commandLength = 2
+		}
+		terraformArgs = append(terraformArgs, args[:commandLength]...)
+		terraformArgs = append(terraformArgs, "-input=false")
+		terraformArgs = append(terraformArgs, args[commandLength:]...)
+	} else {
+		terraformArgs = append(terraformArgs, args...)
+	}
 
 	if includeVars {
+		var varArgs []string
+		if options.VarsInVarFile && len(options.Vars) > 0 {
+			varArgs = []string{"-var-file", varsVarFileArg}
+		} else {
+			vars, sensitiveVars := splitSensitiveVars(options.Vars)
+			varArgs = FormatTerraformVarsAsArgs(vars)
+			if len(sensitiveVars) > 0 {
+				varArgs = append(varArgs, "-var-file", sensitiveVarsVarFileArg)
+			}
+		}
+
 		if options.SetVarsAfterVarFiles {
 			terraformArgs = append(terraformArgs, FormatTerraformArgs("-var-file", options.VarFiles)...)
-			terraformArgs = append(terraformArgs, FormatTerraformVarsAsArgs(options.Vars)...)
+			terraformArgs = append(terraformArgs, varArgs...)
 		} else {
-			terraformArgs = append(terraformArgs, FormatTerraformVarsAsArgs(options.Vars)...)
+			terraformArgs = append(terraformArgs, varArgs...)
 			terraformArgs = append(terraformArgs, FormatTerraformArgs("-var-file", options.VarFiles)...)
 		}
 	}
 
-	terraformArgs = append(terraformArgs, FormatTerraformArgs("-target", options.Targets)...)
+	// Like vars, targets and replacements can't be set when running 'apply' with a plan file
+	if targetSupported && includeVars {
+		terraformArgs = append(terraformArgs, FormatTerraformArgs("-target", options.Targets)...)
+	}
+	if replaceSupported && includeVars {
+		terraformArgs = append(terraformArgs, FormatTerraformArgs("-replace", options.Replace)...)
+	}
 
 	if options.NoColor {
 		terraformArgs = append(terraformArgs, "-no-color")
 	}
 
+	if options.CompactWarnings && compactWarningsSupported {
+		terraformArgs = append(terraformArgs, "-compact-warnings")
+	}
+
 	if lockSupported {
 		// If command supports locking, handle lock arguments
 		terraformArgs = append(terraformArgs, FormatTerraformLockAsArgs(options.Lock, options.LockTimeout)...)
@@ -84,6 +176,34 @@ func FormatArgs(options *Options, args ...string) []string {
 	return terraformArgs
 }
 
+// splitSensitiveVars returns the given variables that are not marked with Sensitive and the ones that are.
+func splitSensitiveVars(allVars map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	vars := map[string]interface{}{}
+	sensitiveVars := map[string]interface{}{}
+	for name, value := range allVars {
+		if _, isSensitive := value.(SensitiveValue); isSensitive {
+			sensitiveVars[name] = value
+		} else {
+			vars[name] = value
+		}
+	}
+	return vars, sensitiveVars
+}
+
+// hasInputArg returns true if the given args already set the -input flag (e.g. -input=false or --input=true).
+func hasInputArg(args []string) bool {
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		flag := strings.TrimLeft(arg, "-")
+		if flag == "input" || strings.HasPrefix(flag, "input=") {
+			return true
+		}
+	}
+	return false
+}
+
 // FormatTerraformPlanFileAsArg formats the out variable as a command-line arg for Terraform (e.g. of the format
 // -out=/some/path/to/plan.out or /some/path/to/plan.out). Only plan supports passing in the plan file as -out; the
 // other commands expect it as the first positional argument. This returns an empty string if outPath is empty string.
@@ -165,6 +285,10 @@ func formatTerraformArgs(vars map[string]interface{}, prefix string, useSpaceAsS
 // ints, booleans, lists, and maps. Everything else is forced into a string using Sprintf. Hopefully, this approach is
 // good enough for the type of variables we deal with in Terratest.
 func toHclString(value interface{}, isNested bool) string {
+	if sensitive, isSensitive := value.(SensitiveValue); isSensitive {
+		return toHclString(sensitive.Value, isNested)
+	}
+
 	// Ideally, we'd use a type switch here to identify slices and maps, but we can't do that, because Go doesn't
 	// support generics, and the type switch only matches concrete types. So we could match []interface{}, but if
 	// a user passes in []string{}, that would NOT match (the same logic applies to maps). Therefore, we have to
@@ -267,11 +267,11 @@ func TestFormatArgsAppliesLockCorrectly(t *testing.T) {
 		command  []string
 		expected []string
 	}{
-		{[]string{"plan"}, []string{"plan", "-lock=false"}},
+		{[]string{"plan"}, []string{"plan", "-input=false", "-lock=false"}},
 		{[]string{"validate"}, []string{"validate"}},
-		{[]string{"plan-all"}, []string{"plan-all", "-lock=false"}},
+		{[]string{"plan-all"}, []string{"plan-all", "-input=false", "-lock=false"}},
 		{[]string{"run-all", "validate"}, []string{"run-all", "validate"}},
-		{[]string{"run-all", "plan"}, []string{"run-all", "plan", "-lock=false"}},
+		{[]string{"run-all", "plan"}, []string{"run-all", "plan", "-input=false", "-lock=false"}},
 	}
 
 	for _, testCase := range testCases {
@@ -289,10 +289,10 @@ func TestFormatSetVarsAfterVarFilesFormatsCorrectly(t *testing.T) {
 		setVarsAfterVarFiles bool
 		expected             []string
 	}{
-		{[]string{"plan"}, map[string]interface{}{"foo": "bar"}, []string{"test.tfvars"}, true, []string{"plan", "-var-file", "test.tfvars", "-var", "foo=bar", "-lock=false"}},
-		{[]string{"plan"}, map[string]interface{}{"foo": "bar", "hello": "world"}, []string{"test.tfvars"}, true, []string{"plan", "-var-file", "test.tfvars", "-var", "foo=bar", "-var", "hello=world", "-lock=false"}},
-		{[]string{"plan"}, map[string]interface{}{"foo": "bar", "hello": "world"}, []string{"test.tfvars"}, false, []string{"plan", "-var", "foo=bar", "-var", "hello=world", "-var-file", "test.tfvars", "-lock=false"}},
-		{[]string{"plan"}, map[string]interface{}{"foo": "bar"}, []string{"test.tfvars"}, false, []string{"plan", "-var", "foo=bar", "-var-file", "test.tfvars", "-lock=false"}},
+		{[]string{"plan"}, map[string]interface{}{"foo": "bar"}, []string{"test.tfvars"}, true, []string{"plan", "-input=false", "-var-file", "test.tfvars", "-var", "foo=bar", "-lock=false"}},
+		{[]string{"plan"}, map[string]interface{}{"foo": "bar", "hello": "world"}, []string{"test.tfvars"}, true, []string{"plan", "-input=false", "-var-file", "test.tfvars", "-var", "foo=bar", "-var", "hello=world", "-lock=false"}},
+		{[]string{"plan"}, map[string]interface{}{"foo": "bar", "hello": "world"}, []string{"test.tfvars"}, false, []string{"plan", "-input=false", "-var", "foo=bar", "-var", "hello=world", "-var-file", "test.tfvars", "-lock=false"}},
+		{[]string{"plan"}, map[string]interface{}{"foo": "bar"}, []string{"test.tfvars"}, false, []string{"plan", "-input=false", "-var", "foo=bar", "-var-file", "test.tfvars", "-lock=false"}},
 	}
 
 	for _, testCase := range testCases {
@@ -312,3 +312,116 @@ func TestFormatSetVarsAfterVarFilesFormatsCorrectly(t *testing.T) {
 		assert.Equal(t, testCase.expected[len(testCase.expected)-1], result[len(result)-1])
 	}
 }
+
+func TestFormatArgsHandlesPlanFileCorrectly(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		Vars:         map[string]interface{}{"foo": "bar"},
+		VarFiles:     []string{"test.tfvars"},
+		PlanFilePath: "/tmp/plan.out",
+	}
+
+	testCases := []struct {
+		command  []string
+		expected []string
+	}{
+		{[]string{"plan"}, []string{"plan", "-input=false", "-var", "foo=bar", "-var-file", "test.tfvars", "-lock=false", "-out=/tmp/plan.out"}},
+		{[]string{"apply"}, []string{"apply", "-input=false", "-lock=false", "/tmp/plan.out"}},
+		{[]string{"run-all", "apply"}, []string{"run-all", "apply", "-input=false", "-lock=false", "/tmp/plan.out"}},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, FormatArgs(options, testCase.command...))
+	}
+}
+
+func TestFormatArgsAppliesColorAndCompactWarningsCorrectly(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		NoColor:         true,
+		CompactWarnings: true,
+	}
+
+	testCases := []struct {
+		command  []string
+		expected []string
+	}{
+		{[]string{"plan"}, []string{"plan", "-input=false", "-no-color", "-compact-warnings", "-lock=false"}},
+		{[]string{"apply"}, []string{"apply", "-input=false", "-no-color", "-compact-warnings", "-lock=false"}},
+		{[]string{"destroy"}, []string{"destroy", "-input=false", "-no-color", "-compact-warnings", "-lock=false"}},
+		{[]string{"run-all", "plan"}, []string{"run-all", "plan", "-input=false", "-no-color", "-compact-warnings", "-lock=false"}},
+		{[]string{"validate"}, []string{"validate", "-no-color"}},
+		{[]string{"run-all", "validate"}, []string{"run-all", "validate", "-no-color"}},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, FormatArgs(options, testCase.command...))
+	}
+
+	assert.Equal(t, []string{"plan", "-input=false", "-lock=false"}, FormatArgs(&Options{}, "plan"))
+}
+
+func TestFormatArgsDisablesInputCorrectly(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		command  []string
+		expected []string
+	}{
+		{[]string{"refresh"}, []string{"refresh", "-input=false", "-lock=false"}},
+		{[]string{"import", "aws_instance.foo", "i-123"}, []string{"import", "-input=false", "aws_instance.foo", "i-123", "-lock=false"}},
+		{[]string{"apply", "-input=false", "-auto-approve"}, []string{"apply", "-input=false", "-auto-approve", "-lock=false"}},
+		{[]string{"run-all", "plan", "--input=false"}, []string{"run-all", "plan", "--input=false", "-lock=false"}},
+		{[]string{"plan", "-input=true"}, []string{"plan", "-input=true", "-lock=false"}},
+		{[]string{"output"}, []string{"output"}},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, FormatArgs(&Options{}, testCase.command...))
+	}
+}
+
+func TestFormatArgsSetsTargetsCorrectly(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		command      []string
+		planFilePath string
+		expected     []string
+	}{
+		{[]string{"plan"}, "", []string{"plan", "-input=false", "-target", "null_resource.foo", "-lock=false"}},
+		{[]string{"apply", "-auto-approve"}, "", []string{"apply", "-input=false", "-auto-approve", "-target", "null_resource.foo", "-lock=false"}},
+		{[]string{"destroy", "-auto-approve"}, "", []string{"destroy", "-input=false", "-auto-approve", "-target", "null_resource.foo", "-lock=false"}},
+		{[]string{"apply", "-auto-approve"}, "/tmp/plan.out", []string{"apply", "-input=false", "-auto-approve", "-lock=false", "/tmp/plan.out"}},
+		{[]string{"validate"}, "", []string{"validate"}},
+		{[]string{"test", "-json"}, "", []string{"test", "-json"}},
+	}
+
+	for _, testCase := range testCases {
+		options := &Options{Targets: []string{"null_resource.foo"}, PlanFilePath: testCase.planFilePath}
+		assert.Equal(t, testCase.expected, FormatArgs(options, testCase.command...))
+	}
+}
+
+func TestFormatArgsSetsReplaceCorrectly(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		command      []string
+		planFilePath string
+		expected     []string
+	}{
+		{[]string{"plan"}, "", []string{"plan", "-input=false", "-replace", "null_resource.foo", "-lock=false"}},
+		{[]string{"apply", "-auto-approve"}, "", []string{"apply", "-input=false", "-auto-approve", "-replace", "null_resource.foo", "-lock=false"}},
+		{[]string{"apply", "-auto-approve"}, "/tmp/plan.out", []string{"apply", "-input=false", "-auto-approve", "-lock=false", "/tmp/plan.out"}},
+		{[]string{"destroy", "-auto-approve"}, "", []string{"destroy", "-input=false", "-auto-approve", "-lock=false"}},
+		{[]string{"refresh"}, "", []string{"refresh", "-input=false", "-lock=false"}},
+	}
+
+	for _, testCase := range testCases {
+		options := &Options{Replace: []string{"null_resource.foo"}, PlanFilePath: testCase.planFilePath}
+		assert.Equal(t, testCase.expected, FormatArgs(options, testCase.command...))
+	}
+}
@@ -0,0 +1,139 @@
+package terraform
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Graph is the dependency graph of a Terraform configuration, as returned by terraform graph.
+type Graph struct {
+	Nodes []string            // The sorted names of the nodes (e.g. aws_instance.web, module.db.aws_db_instance.this or var.name)
+	Edges map[string][]string // The sorted names of the nodes that each node directly depends on
+}
+
+var (
+	// graphEdgeRegexp matches an edge of the DOT output of terraform graph, e.g. "a" -> "b".
+	graphEdgeRegexp = regexp.MustCompile(`^\s*"((?:[^"\\]|\\.)*)"\s*->\s*"((?:[^"\\]|\\.)*)"`)
+
+	// graphNodeRegexp matches a node of the DOT output of terraform graph, e.g. "a" [label = "a"].
+	graphNodeRegexp = regexp.MustCompile(`^\s*"((?:[^"\\]|\\.)*)"\s*(?:\[|;|$)`)
+
+	// graphExpandSuffix is the suffix that older Terraform versions add to the names of the nodes of the configuration.
+	graphExpandSuffix = " (expand)"
+)
+
+// GetGraph runs terraform graph with the given options and returns the parsed dependency graph. This will fail the
+// test if there is an error in the command.
+func GetGraph(t testing.TestingT, options *Options) *Graph {
+	graph, err := GetGraphE(t, options)
+	require.NoError(t, err)
+	return graph
+}
+
+// GetGraphE runs terraform graph with the given options and returns the parsed dependency graph.
+func GetGraphE(t testing.TestingT, options *Options) (*Graph, error) {
+	out, err := RunTerraformCommandAndGetStdoutE(t, options, "graph")
+	if err != nil {
+		return nil, err
+	}
+	return ParseGraph(out), nil
+}
+
+// ParseGraph parses the DOT output of terraform graph. The names of the nodes are normalized to the addresses used in
+// the configuration, without the [root] prefix and the (expand) suffix of older Terraform versions. Other internal nodes
+// of older versions keep their suffix (e.g. the provider["registry.terraform.io/hashicorp/aws"] (close) node).
+func ParseGraph(out string) *Graph {
+	nodes := map[string]bool{}
+	edges := map[string]map[string]bool{}
+
+	for _, line := range strings.Split(out, "\n") {
+		if match := graphEdgeRegexp.FindStringSubmatch(line); match != nil {
+			from := normalizeGraphNode(match[1])
+			to := normalizeGraphNode(match[2])
+			nodes[from] = true
+			nodes[to] = true
+			if from == to {
+				continue
+			}
+			if edges[from] == nil {
+				edges[from] = map[string]bool{}
+			}
+			edges[from][to] = true
+		} else if match := graphNodeRegexp.FindStringSubmatch(line); match != nil {
+			nodes[normalizeGraphNode(match[1])] = true
+		}
+	}
+
+	graph := &Graph{Nodes: sortedSetKeys(nodes), Edges: map[string][]string{}}
+	for from, dependencies := range edges {
+		graph.Edges[from] = sortedSetKeys(dependencies)
+	}
+	return graph
+}
+
+// DependsOn returns true if the node with the given name depends on the other node, directly or through other nodes
+// (e.g. a resource that depends on another one through a variable of a module).
+func (graph *Graph) DependsOn(node string, dependency string) bool {
+	visited := map[string]bool{node: true}
+	queue := []string{node}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range graph.Edges[current] {
+			if next == dependency {
+				return true
+			}
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return false
+}
+
+// AssertDependsOn checks that the node with the given name (e.g. aws_instance.web) depends on the other node (e.g.
+// aws_security_group.web), directly or through other nodes, failing the test if it does not.
+func AssertDependsOn(t testing.TestingT, graph *Graph, node string, dependency string) {
+	assert.Truef(t, graph.DependsOn(node, dependency), "%s does not depend on %s. Direct dependencies of %s: %v", node, dependency, node, graph.Edges[node])
+}
+
+// RequireDependsOn checks that the node with the given name (e.g. aws_instance.web) depends on the other node (e.g.
+// aws_security_group.web), directly or through other nodes, failing and halting the test if it does not.
+func RequireDependsOn(t testing.TestingT, graph *Graph, node string, dependency string) {
+	require.Truef(t, graph.DependsOn(node, dependency), "%s does not depend on %s. Direct dependencies of %s: %v", node, dependency, node, graph.Edges[node])
+}
+
+// AssertNotDependsOn checks that the node with the given name does not depend on the other node, directly or through
+// other nodes, failing the test if it does.
+func AssertNotDependsOn(t testing.TestingT, graph *Graph, node string, dependency string) {
+	assert.Falsef(t, graph.DependsOn(node, dependency), "%s unexpectedly depends on %s", node, dependency)
+}
+
+// RequireNotDependsOn checks that the node with the given name does not depend on the other node, directly or through
+// other nodes, failing and halting the test if it does.
+func RequireNotDependsOn(t testing.TestingT, graph *Graph, node string, dependency string) {
+	require.Falsef(t, graph.DependsOn(node, dependency), "%s unexpectedly depends on %s", node, dependency)
+}
+
+// normalizeGraphNode returns the address of the given node of the DOT output of terraform graph.
+func normalizeGraphNode(name string) string {
+	name = strings.ReplaceAll(name, `\"`, `"`)
+	name = strings.TrimPrefix(name, "[root] ")
+	return strings.TrimSuffix(name, graphExpandSuffix)
+}
+
+// sortedSetKeys returns the sorted keys of the given set.
+func sortedSetKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
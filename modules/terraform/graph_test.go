@@ -0,0 +1,66 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const legacyGraphOutput = `digraph {
+	compound = "true"
+	newrank = "true"
+	subgraph "root" {
+		"[root] aws_instance.web (expand)" [label = "aws_instance.web", shape = "box"]
+		"[root] aws_security_group.web (expand)" [label = "aws_security_group.web", shape = "box"]
+		"[root] module.db.aws_db_instance.this (expand)" [label = "module.db.aws_db_instance.this", shape = "box"]
+		"[root] provider[\"registry.terraform.io/hashicorp/aws\"]" [label = "provider[\"registry.terraform.io/hashicorp/aws\"]", shape = "diamond"]
+		"[root] aws_instance.web (expand)" -> "[root] aws_security_group.web (expand)"
+		"[root] aws_instance.web (expand)" -> "[root] module.db.output.endpoint (expand)"
+		"[root] module.db.output.endpoint (expand)" -> "[root] module.db.aws_db_instance.this (expand)"
+		"[root] aws_security_group.web (expand)" -> "[root] provider[\"registry.terraform.io/hashicorp/aws\"]"
+		"[root] provider[\"registry.terraform.io/hashicorp/aws\"] (close)" -> "[root] aws_instance.web (expand)"
+	}
+}
+`
+
+const graphOutput = `digraph G {
+  rankdir = "RL";
+  node [shape = rect, fontname = "sans-serif"];
+  "aws_instance.web" [label="aws_instance.web"];
+  "aws_security_group.web" [label="aws_security_group.web"];
+  subgraph "cluster_module.db" {
+    label = "module.db"
+    fontname = "sans-serif"
+    "module.db.aws_db_instance.this" [label="aws_db_instance.this"];
+  }
+  "aws_instance.web" -> "aws_security_group.web";
+  "aws_instance.web" -> "module.db.aws_db_instance.this";
+}
+`
+
+func TestParseGraph(t *testing.T) {
+	t.Parallel()
+
+	for name, out := range map[string]string{"Legacy": legacyGraphOutput, "Simplified": graphOutput} {
+		out := out
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			graph := ParseGraph(out)
+			assert.Contains(t, graph.Nodes, "aws_instance.web")
+			assert.Contains(t, graph.Nodes, "module.db.aws_db_instance.this")
+			assert.NotContains(t, graph.Nodes, "cluster_module.db")
+
+			AssertDependsOn(t, graph, "aws_instance.web", "aws_security_group.web")
+			AssertDependsOn(t, graph, "aws_instance.web", "module.db.aws_db_instance.this")
+			AssertNotDependsOn(t, graph, "aws_security_group.web", "aws_instance.web")
+			AssertNotDependsOn(t, graph, "module.db.aws_db_instance.this", "aws_security_group.web")
+		})
+	}
+
+	graph := ParseGraph(legacyGraphOutput)
+	assert.Equal(t, []string{"aws_security_group.web", "module.db.output.endpoint"}, graph.Edges["aws_instance.web"])
+	assert.Equal(t, []string{`provider["registry.terraform.io/hashicorp/aws"]`}, graph.Edges["aws_security_group.web"])
+	// the node that closes the provider is not merged with the provider, as that would create a cycle
+	assert.True(t, graph.DependsOn(`provider["registry.terraform.io/hashicorp/aws"] (close)`, "aws_instance.web"))
+}
@@ -0,0 +1,43 @@
+package terraform
+
+import (
+	"regexp"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// resourceAlreadyManagedRegexp matches the error of terraform import when the address is already in the state.
+	resourceAlreadyManagedRegexp = regexp.MustCompile(`(?i)resource already managed by (terraform|opentofu)`)
+
+	// remoteObjectNotFoundRegexp matches the error of terraform import when there is no remote object with the ID.
+	remoteObjectNotFoundRegexp = regexp.MustCompile(`(?i)cannot import non-existent remote object`)
+)
+
+// Import runs terraform import with the given options to import the existing remote object with the given ID into the
+// resource with the given address (e.g. aws_s3_bucket.example), and returns stdout/stderr. This will fail the test if
+// the import fails, e.g. to check that a resource can be imported.
+func Import(t testing.TestingT, options *Options, address string, id string) string {
+	out, err := ImportE(t, options, address, id)
+	require.NoError(t, err)
+	return out
+}
+
+// ImportE runs terraform import with the given options to import the existing remote object with the given ID into the
+// resource with the given address (e.g. aws_s3_bucket.example), and returns stdout/stderr. This returns a
+// ResourceAlreadyManaged error if the address is already in the state, and a RemoteObjectNotFound error if there is no
+// remote object with the given ID.
+func ImportE(t testing.TestingT, options *Options, address string, id string) (string, error) {
+	args := append(FormatArgs(options, "import"), address, id)
+	out, err := RunTerraformCommandE(t, options, args...)
+	if err != nil {
+		switch {
+		case resourceAlreadyManagedRegexp.MatchString(out):
+			return out, ResourceAlreadyManaged(address)
+		case remoteObjectNotFoundRegexp.MatchString(out):
+			return out, RemoteObjectNotFound{Address: address, ID: id}
+		}
+	}
+	return out, err
+}
@@ -0,0 +1,36 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportEReturnsStructuredErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		message  string
+		expected error
+	}{
+		{"already managed", "Error: Resource already managed by Terraform", ResourceAlreadyManaged("null_resource.test")},
+		{"remote object not found", "Error: Cannot import non-existent remote object", RemoteObjectNotFound{Address: "null_resource.test", ID: "123"}},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			options := &Options{
+				TerraformDir:    t.TempDir(),
+				TerraformBinary: writeTerraformStub(t, "import", testCase.message, 1),
+			}
+
+			out, err := ImportE(t, options, "null_resource.test", "123")
+			assert.Equal(t, testCase.expected, err)
+			assert.Contains(t, out, testCase.message)
+		})
+	}
+}
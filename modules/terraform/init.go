@@ -2,6 +2,7 @@ package terraform
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/gruntwork-io/terratest/modules/testing"
 )
@@ -15,9 +16,30 @@ func Init(t testing.TestingT, options *Options) string {
 	return out
 }
 
-// InitE calls terraform init and return stdout/stderr.
+// InitE calls terraform init and return stdout/stderr. If options.Workspace is set, this also selects that workspace,
+// creating it if it doesn't exist.
 func InitE(t testing.TestingT, options *Options) (string, error) {
-	args := []string{"init", fmt.Sprintf("-upgrade=%t", options.Upgrade)}
+	initOptions, cleanup, err := getProviderInstallationOptions(options)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	out, err := RunTerraformCommandE(t, initOptions, formatInitArgs(options)...)
+	if err != nil || options.Workspace == "" {
+		return out, err
+	}
+
+	if _, err := WorkspaceSelectOrNewE(t, options, options.Workspace); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// formatInitArgs returns the args for the terraform init command, including the init-only flags (e.g. -upgrade,
+// -reconfigure) that FormatArgs never adds to other commands.
+func formatInitArgs(options *Options) []string {
+	args := []string{"init", "-input=false", fmt.Sprintf("-upgrade=%t", options.Upgrade)}
 
 	// Append reconfigure option if specified
 	if options.Reconfigure {
@@ -34,5 +56,33 @@ func InitE(t testing.TestingT, options *Options) (string, error) {
 
 	args = append(args, FormatTerraformBackendConfigAsArgs(options.BackendConfig)...)
 	args = append(args, FormatTerraformPluginDirAsArgs(options.PluginDir)...)
-	return RunTerraformCommandE(t, options, args...)
+	return args
+}
+
+// getProviderInstallationOptions returns the options to run terraform init with so that it installs the providers from
+// options.ProviderMirrorDir and caches them in options.ProviderCacheDir, creating the cache dir if needed. The returned
+// cleanup function removes the generated CLI configuration and must be called once init has finished.
+func getProviderInstallationOptions(options *Options) (*Options, func(), error) {
+	if options.ProviderCacheDir != "" {
+		if err := os.MkdirAll(options.ProviderCacheDir, 0755); err != nil {
+			return nil, func() {}, err
+		}
+	}
+	if options.ProviderMirrorDir == "" {
+		return options, func() {}, nil
+	}
+
+	configFile, err := writeProviderMirrorConfig(options.ProviderMirrorDir)
+	if err != nil {
+		return nil, func() {}, err
+	}
+	cleanup := func() { os.Remove(configFile) }
+
+	initOptions, err := options.Clone()
+	if err != nil {
+		cleanup()
+		return nil, func() {}, err
+	}
+	initOptions.EnvVars["TF_CLI_CONFIG_FILE"] = configFile
+	return initOptions, cleanup, nil
 }
@@ -151,3 +151,43 @@ func TestInitNoColorOption(t *testing.T) {
 	// Check that NoColor correctly doesn't output the colour escape codes which look like [0m,[1m or [32m
 	require.NotRegexp(t, `\[\d*m`, out, "Output should not contain color escape codes")
 }
+
+func TestInitOnlyFlagsAreOnlyFormattedForInit(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		Upgrade:     true,
+		Reconfigure: true,
+	}
+
+	initArgs := formatInitArgs(options)
+	assert.Contains(t, initArgs, "-upgrade=true")
+	assert.Contains(t, initArgs, "-reconfigure")
+
+	for _, command := range []string{"plan", "apply", "destroy", "validate", "output"} {
+		args := FormatArgs(options, command)
+		for _, arg := range args {
+			assert.NotContains(t, arg, "-upgrade", "init-only flag rendered for %s", command)
+			assert.NotContains(t, arg, "-reconfigure", "init-only flag rendered for %s", command)
+		}
+	}
+}
+
+func TestInitWithProviderMirrorAndCache(t *testing.T) {
+	t.Parallel()
+
+	mirrorDir := SetupProviderMirror(t, "hashicorp/null")
+	assert.DirExists(t, filepath.Join(mirrorDir, "registry.terraform.io", "hashicorp", "null"))
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-basic-configuration", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir:      testFolder,
+		ProviderMirrorDir: mirrorDir,
+		ProviderCacheDir:  filepath.Join(t.TempDir(), "cache"),
+	}
+
+	Init(t, options)
+	assert.DirExists(t, filepath.Join(options.ProviderCacheDir, "registry.terraform.io", "hashicorp", "null"))
+}
@@ -0,0 +1,481 @@
+package terraform
+
+import (
+	"archive/zip"
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	// TerraformVersionFile is the name of the file (as used by tfenv) that pins the version of terraform of a module.
+	TerraformVersionFile = ".terraform-version"
+
+	// TofuVersionFile is the name of the file (as used by tofuenv) that pins the version of tofu of a module.
+	TofuVersionFile = ".opentofu-version"
+
+	// LatestTerraformVersion can be used as the TerraformVersion (or in a version file) to install the latest stable
+	// version.
+	LatestTerraformVersion = "latest"
+
+	// installedTerraformDirPattern is the pattern of the name of the directory in the temp dir of the OS that
+	// InstallTerraform creates to install the binaries to, so that each version is only downloaded once per test run.
+	installedTerraformDirPattern = "terratest-terraform-versions-"
+)
+
+// terraformReleaseSource describes where the releases of terraform or tofu are published.
+type terraformReleaseSource struct {
+	// The name of the binary, which is also the prefix of the release files (e.g. terraform_1.5.7_linux_amd64.zip)
+	product string
+	// The URL of the JSON index of the published versions
+	versionsURL string
+	// The format of the URL of the directory containing the zip files and SHA256SUMS of a version
+	downloadURLFormat string
+}
+
+var (
+	terraformReleases = terraformReleaseSource{
+		product:           TerraformDefaultPath,
+		versionsURL:       "https://releases.hashicorp.com/terraform/index.json",
+		downloadURLFormat: "https://releases.hashicorp.com/terraform/%s",
+	}
+	tofuReleases = terraformReleaseSource{
+		product:           TofuDefaultPath,
+		versionsURL:       "https://get.opentofu.org/tofu/api.json",
+		downloadURLFormat: "https://github.com/opentofu/opentofu/releases/download/v%s",
+	}
+
+	// installTerraformMutex makes sure that each binary is only installed by one test at a time.
+	installTerraformMutex sync.Mutex
+	// installedTerraformDir is the directory that InstallTerraform installs the binaries to, which is created by the
+	// first install of the test run. A new directory is used for every test run, so that binaries that other users of
+	// the temp dir put there are never run.
+	installedTerraformDir string
+	// installedTerraformChecksums are the hex encoded SHA256 checksums of the binaries installed by InstallTerraform,
+	// by path, to check that a binary was not modified before reusing it.
+	installedTerraformChecksums = map[string]string{}
+)
+
+// InstallTerraform downloads the version of terraform (or tofu, if the TerraformBinary of the options is tofu) set by
+// the TerraformVersion of the options, or else pinned by the .terraform-version file (.opentofu-version for tofu) or
+// the required_version constraints of the TerraformDir, and sets the TerraformBinary of the options to it. For
+// terragrunt, the binary is set as TERRAGRUNT_TFPATH instead. The binaries are cached in a new dir in the temp dir of
+// the OS, so each version is only downloaded once per test run, and their checksums are checked before they are reused.
+// Returns the path of the binary. This will fail the test if there is an error.
+func InstallTerraform(t testing.TestingT, options *Options) string {
+	binaryPath, err := InstallTerraformE(t, options)
+	require.NoError(t, err)
+	return binaryPath
+}
+
+// InstallTerraformE downloads the version of terraform (or tofu, if the TerraformBinary of the options is tofu) set by
+// the TerraformVersion of the options, or else pinned by the .terraform-version file (.opentofu-version for tofu) or
+// the required_version constraints of the TerraformDir, and sets the TerraformBinary of the options to it. For
+// terragrunt, the binary is set as TERRAGRUNT_TFPATH instead. The binaries are cached in a new dir in the temp dir of
+// the OS, so each version is only downloaded once per test run, and their checksums are checked before they are reused.
+// Returns the path of the binary.
+func InstallTerraformE(t testing.TestingT, options *Options) (string, error) {
+	source := terraformReleases
+	if filepath.Base(options.TerraformBinary) == TofuDefaultPath {
+		source = tofuReleases
+	}
+
+	constraint := options.TerraformVersion
+	if constraint == "" {
+		var err error
+		constraint, err = GetTerraformVersionConstraintE(options.TerraformDir, source.product)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	installDir, err := getInstalledTerraformDirE()
+	if err != nil {
+		return "", err
+	}
+	binaryPath, err := installTerraformVersionE(t, options, source, constraint, installDir)
+	if err != nil {
+		return "", err
+	}
+
+	if filepath.Base(options.TerraformBinary) == TerragruntDefaultPath {
+		if options.EnvVars == nil {
+			options.EnvVars = map[string]string{}
+		}
+		options.EnvVars["TERRAGRUNT_TFPATH"] = binaryPath
+	} else {
+		options.TerraformBinary = binaryPath
+	}
+	return binaryPath, nil
+}
+
+// getInstalledTerraformDirE returns the directory that InstallTerraform installs the binaries to, creating it on the
+// first call.
+func getInstalledTerraformDirE() (string, error) {
+	installTerraformMutex.Lock()
+	defer installTerraformMutex.Unlock()
+
+	if installedTerraformDir == "" {
+		dir, err := os.MkdirTemp("", installedTerraformDirPattern)
+		if err != nil {
+			return "", err
+		}
+		installedTerraformDir = dir
+	}
+	return installedTerraformDir, nil
+}
+
+// GetTerraformVersionConstraintE returns the version of the given product (terraform or tofu) pinned by the version
+// file of the given dir or of its closest parent dir that has one (like tfenv does), or else the required_version
+// constraints of the .tf files in the given dir.
+func GetTerraformVersionConstraintE(terraformDir string, product string) (string, error) {
+	versionFiles := []string{TerraformVersionFile}
+	if product == TofuDefaultPath {
+		versionFiles = []string{TofuVersionFile, TerraformVersionFile}
+	}
+
+	dir, err := filepath.Abs(terraformDir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		for _, versionFile := range versionFiles {
+			contents, err := os.ReadFile(filepath.Join(dir, versionFile))
+			if err == nil {
+				return strings.TrimSpace(string(contents)), nil
+			}
+			if !os.IsNotExist(err) {
+				return "", err
+			}
+		}
+
+		parentDir := filepath.Dir(dir)
+		if parentDir == dir {
+			break
+		}
+		dir = parentDir
+	}
+
+	constraints, err := getRequiredVersionsE(terraformDir)
+	if err != nil {
+		return "", err
+	}
+	if len(constraints) == 0 {
+		return "", TerraformVersionNotFound(terraformDir)
+	}
+	return strings.Join(constraints, ", "), nil
+}
+
+// getRequiredVersionsE returns the required_version constraints of the terraform blocks of the .tf files in the given
+// dir.
+func getRequiredVersionsE(terraformDir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(terraformDir, "*.tf"))
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &hcl.BodySchema{Blocks: []hcl.BlockHeaderSchema{{Type: "terraform"}}}
+	blockSchema := &hcl.BodySchema{Attributes: []hcl.AttributeSchema{{Name: "required_version"}}}
+
+	parser := hclparse.NewParser()
+	constraints := []string{}
+	for _, path := range paths {
+		file, diags := parser.ParseHCLFile(path)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		content, _, diags := file.Body.PartialContent(schema)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		for _, block := range content.Blocks {
+			blockContent, _, diags := block.Body.PartialContent(blockSchema)
+			if diags.HasErrors() {
+				return nil, diags
+			}
+			attr, hasRequiredVersion := blockContent.Attributes["required_version"]
+			if !hasRequiredVersion {
+				continue
+			}
+			value, diags := attr.Expr.Value(nil)
+			if diags.HasErrors() {
+				return nil, diags
+			}
+			if value.IsNull() || !value.Type().Equals(cty.String) {
+				return nil, hcl.Diagnostics{{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid required_version",
+					Detail:   fmt.Sprintf("The required_version must be a string, not %s.", value.Type().FriendlyName()),
+					Subject:  attr.Expr.Range().Ptr(),
+				}}
+			}
+			constraints = append(constraints, value.AsString())
+		}
+	}
+	return constraints, nil
+}
+
+// installTerraformVersionE installs the newest version of the given release source that matches the given constraint
+// to the given dir, unless it was already installed there and has not been modified since, and returns the path of the
+// binary.
+func installTerraformVersionE(t testing.TestingT, options *Options, source terraformReleaseSource, constraint string, installDir string) (string, error) {
+	installVersion, err := resolveTerraformVersionE(source, constraint)
+	if err != nil {
+		return "", err
+	}
+
+	binaryName := source.product
+	if runtime.GOOS == "windows" {
+		binaryName += ".exe"
+	}
+	versionDir := filepath.Join(installDir, fmt.Sprintf("%s_%s_%s_%s", source.product, installVersion, runtime.GOOS, runtime.GOARCH))
+	binaryPath := filepath.Join(versionDir, binaryName)
+
+	installTerraformMutex.Lock()
+	defer installTerraformMutex.Unlock()
+
+	if expectedChecksum, installed := installedTerraformChecksums[binaryPath]; installed {
+		checksum, err := getFileChecksumE(binaryPath)
+		if err == nil && checksum == expectedChecksum {
+			return binaryPath, nil
+		}
+		options.Logger.Logf(t, "Installing %s again, as it was modified or removed since it was installed", binaryPath)
+	}
+
+	downloadURL := fmt.Sprintf(source.downloadURLFormat, installVersion)
+	zipName := fmt.Sprintf("%s_%s_%s_%s.zip", source.product, installVersion, runtime.GOOS, runtime.GOARCH)
+	options.Logger.Logf(t, "Installing %s %s to %s", source.product, installVersion, versionDir)
+
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		return "", err
+	}
+	zipFile, err := os.CreateTemp(versionDir, "*.zip")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(zipFile.Name())
+	defer zipFile.Close()
+
+	checksum, err := downloadFileE(downloadURL+"/"+zipName, zipFile)
+	if err != nil {
+		return "", err
+	}
+	expectedChecksum, err := getReleaseChecksumE(fmt.Sprintf("%s/%s_%s_SHA256SUMS", downloadURL, source.product, installVersion), zipName)
+	if err != nil {
+		return "", err
+	}
+	if checksum != expectedChecksum {
+		return "", TerraformChecksumMismatch{File: zipName, Expected: expectedChecksum, Actual: checksum}
+	}
+
+	if err := extractZipFileE(zipFile.Name(), binaryName, binaryPath); err != nil {
+		return "", err
+	}
+	binaryChecksum, err := getFileChecksumE(binaryPath)
+	if err != nil {
+		return "", err
+	}
+	installedTerraformChecksums[binaryPath] = binaryChecksum
+	return binaryPath, nil
+}
+
+// resolveTerraformVersionE returns the given constraint if it is an exact version, or else the newest version of the
+// given release source that matches it. Prereleases only match constraints that mention a prerelease.
+func resolveTerraformVersionE(source terraformReleaseSource, constraint string) (string, error) {
+	constraint = strings.TrimSpace(constraint)
+	if exactVersion, err := version.NewVersion(constraint); err == nil && !strings.ContainsAny(constraint, "=<>~!,") {
+		return exactVersion.String(), nil
+	}
+
+	var constraints version.Constraints
+	if constraint != LatestTerraformVersion {
+		var err error
+		constraints, err = version.NewConstraint(constraint)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	versions, err := listTerraformVersionsE(source)
+	if err != nil {
+		return "", err
+	}
+
+	var newest *version.Version
+	for _, candidate := range versions {
+		if constraints == nil && candidate.Prerelease() != "" {
+			continue
+		}
+		if constraints != nil && !constraints.Check(candidate) {
+			continue
+		}
+		if newest == nil || candidate.GreaterThan(newest) {
+			newest = candidate
+		}
+	}
+	if newest == nil {
+		return "", NoMatchingTerraformVersion{Product: source.product, Constraint: constraint}
+	}
+	return newest.String(), nil
+}
+
+// listTerraformVersionsE returns the versions in the JSON index of the given release source, which is either a map of
+// versions (as published by HashiCorp) or a list of objects with an id (as published by OpenTofu).
+func listTerraformVersionsE(source terraformReleaseSource) ([]*version.Version, error) {
+	response, err := http.Get(source.versionsURL)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list the versions of %s from %s: %s", source.product, source.versionsURL, response.Status)
+	}
+
+	var index struct {
+		Versions json.RawMessage `json:"versions"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&index); err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	var versionsMap map[string]json.RawMessage
+	var versionsList []struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(index.Versions, &versionsMap); err == nil {
+		for id := range versionsMap {
+			ids = append(ids, id)
+		}
+	} else if err := json.Unmarshal(index.Versions, &versionsList); err == nil {
+		for _, entry := range versionsList {
+			ids = append(ids, entry.ID)
+		}
+	} else {
+		return nil, err
+	}
+
+	versions := []*version.Version{}
+	for _, id := range ids {
+		parsed, err := version.NewVersion(id)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, parsed)
+	}
+	return versions, nil
+}
+
+// downloadFileE downloads the given URL to the given writer and returns the hex encoded SHA256 checksum of the
+// contents.
+func downloadFileE(url string, writer io.Writer) (string, error) {
+	response, err := http.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download %s: %s", url, response.Status)
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(writer, hash), response.Body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// getFileChecksumE returns the hex encoded SHA256 checksum of the contents of the file at the given path.
+func getFileChecksumE(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// getReleaseChecksumE returns the checksum of the given file in the SHA256SUMS file at the given URL.
+func getReleaseChecksumE(url string, fileName string) (string, error) {
+	response, err := http.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download %s: %s", url, response.Status)
+	}
+
+	scanner := bufio.NewScanner(response.Body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == fileName {
+			return fields[0], nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("no checksum for %s in %s", fileName, url)
+}
+
+// extractZipFileE extracts the file with the given name from the given zip archive to the given path, as an
+// executable.
+func extractZipFileE(zipPath string, fileName string, destination string) error {
+	archive, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	for _, file := range archive.File {
+		if file.Name != fileName {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+
+		// Write to a temp file first, so that a partially extracted binary is never used
+		tmpFile, err := os.CreateTemp(filepath.Dir(destination), fileName+"-*")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmpFile.Name())
+		if _, err := io.Copy(tmpFile, reader); err != nil {
+			tmpFile.Close()
+			return err
+		}
+		if err := tmpFile.Close(); err != nil {
+			return err
+		}
+		if err := os.Chmod(tmpFile.Name(), 0755); err != nil {
+			return err
+		}
+		return os.Rename(tmpFile.Name(), destination)
+	}
+	return fmt.Errorf("%s not found in %s", fileName, zipPath)
+}
@@ -0,0 +1,215 @@
+package terraform
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startReleasesServer starts a server that publishes fake terraform releases in the format of releases.hashicorp.com,
+// with the given checksum (or the actual checksum if empty), and returns the release source for it and the number of
+// zip files it served.
+func startReleasesServer(t *testing.T, checksum string) (terraformReleaseSource, *int32) {
+	var zipBuffer bytes.Buffer
+	archive := zip.NewWriter(&zipBuffer)
+	binaryName := "terraform"
+	if runtime.GOOS == "windows" {
+		binaryName += ".exe"
+	}
+	writer, err := archive.Create(binaryName)
+	require.NoError(t, err)
+	_, err = writer.Write([]byte("#!/bin/sh\necho \"Terraform v1.5.7\"\n"))
+	require.NoError(t, err)
+	require.NoError(t, archive.Close())
+
+	if checksum == "" {
+		sum := sha256.Sum256(zipBuffer.Bytes())
+		checksum = hex.EncodeToString(sum[:])
+	}
+	zipName := fmt.Sprintf("terraform_1.5.7_%s_%s.zip", runtime.GOOS, runtime.GOARCH)
+
+	downloads := new(int32)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/index.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "terraform", "versions": {"1.4.6": {}, "1.5.7": {}, "1.6.0-beta1": {}}}`)
+	})
+	mux.HandleFunc("/1.5.7/"+zipName, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(downloads, 1)
+		w.Write(zipBuffer.Bytes())
+	})
+	mux.HandleFunc("/1.5.7/terraform_1.5.7_SHA256SUMS", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "0000  terraform_1.5.7_other_arch.zip\n%s  %s\n", checksum, zipName)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return terraformReleaseSource{
+		product:           TerraformDefaultPath,
+		versionsURL:       server.URL + "/index.json",
+		downloadURLFormat: server.URL + "/%s",
+	}, downloads
+}
+
+func TestInstallTerraformVersionE(t *testing.T) {
+	t.Parallel()
+
+	source, downloads := startReleasesServer(t, "")
+	installDir := t.TempDir()
+
+	binaryPath, err := installTerraformVersionE(t, &Options{}, source, "~> 1.5.0", installDir)
+	require.NoError(t, err)
+	assert.Contains(t, binaryPath, "terraform_1.5.7_")
+	info, err := os.Stat(binaryPath)
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode()&0100)
+
+	cachedPath, err := installTerraformVersionE(t, &Options{}, source, "1.5.7", installDir)
+	require.NoError(t, err)
+	assert.Equal(t, binaryPath, cachedPath)
+	assert.Equal(t, int32(1), atomic.LoadInt32(downloads))
+
+	_, err = installTerraformVersionE(t, &Options{}, source, ">= 2.0", installDir)
+	assert.ErrorAs(t, err, new(NoMatchingTerraformVersion))
+
+	// A binary that was modified since it was installed is installed again
+	require.NoError(t, os.WriteFile(binaryPath, []byte("#!/bin/sh\necho modified\n"), 0755))
+	reinstalledPath, err := installTerraformVersionE(t, &Options{}, source, "1.5.7", installDir)
+	require.NoError(t, err)
+	assert.Equal(t, binaryPath, reinstalledPath)
+	assert.Equal(t, int32(2), atomic.LoadInt32(downloads))
+	contents, err := os.ReadFile(binaryPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "Terraform v1.5.7")
+}
+
+func TestInstallTerraformVersionEDoesNotReuseBinariesItDidNotInstall(t *testing.T) {
+	t.Parallel()
+
+	source, downloads := startReleasesServer(t, "")
+	installDir := t.TempDir()
+	binaryName := "terraform"
+	if runtime.GOOS == "windows" {
+		binaryName += ".exe"
+	}
+	binaryPath := filepath.Join(installDir, fmt.Sprintf("terraform_1.5.7_%s_%s", runtime.GOOS, runtime.GOARCH), binaryName)
+	require.NoError(t, os.MkdirAll(filepath.Dir(binaryPath), 0755))
+	require.NoError(t, os.WriteFile(binaryPath, []byte("#!/bin/sh\necho planted\n"), 0755))
+
+	installedPath, err := installTerraformVersionE(t, &Options{}, source, "1.5.7", installDir)
+	require.NoError(t, err)
+	assert.Equal(t, binaryPath, installedPath)
+	assert.Equal(t, int32(1), atomic.LoadInt32(downloads))
+	contents, err := os.ReadFile(binaryPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "Terraform v1.5.7")
+}
+
+func TestInstallTerraformVersionEChecksumMismatch(t *testing.T) {
+	t.Parallel()
+
+	source, _ := startReleasesServer(t, "0123")
+	installDir := t.TempDir()
+
+	_, err := installTerraformVersionE(t, &Options{}, source, "1.5.7", installDir)
+	assert.ErrorAs(t, err, new(TerraformChecksumMismatch))
+
+	matches, err := filepath.Glob(filepath.Join(installDir, "*", "terraform*"))
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+func TestResolveTerraformVersionE(t *testing.T) {
+	t.Parallel()
+
+	source, _ := startReleasesServer(t, "")
+	for constraint, expected := range map[string]string{
+		"latest":             "1.5.7",
+		"< 1.5":              "1.4.6",
+		">= 1.4, < 2.0":      "1.5.7",
+		">= 1.6.0-beta1":     "1.6.0-beta1",
+		"1.3.0":              "1.3.0",
+		"v1.5.7":             "1.5.7",
+		"= 1.4.6":            "1.4.6",
+		" ~> 1.4.0 ":         "1.4.6",
+		"~> 1.5.0, != 1.5.7": "",
+	} {
+		resolved, err := resolveTerraformVersionE(source, constraint)
+		if expected == "" {
+			assert.Error(t, err, constraint)
+			continue
+		}
+		require.NoError(t, err, constraint)
+		assert.Equal(t, expected, resolved, constraint)
+	}
+
+	tofuServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"versions": [{"id": "1.8.1"}, {"id": "1.7.3"}]}`)
+	}))
+	defer tofuServer.Close()
+	resolved, err := resolveTerraformVersionE(terraformReleaseSource{product: TofuDefaultPath, versionsURL: tofuServer.URL}, "latest")
+	require.NoError(t, err)
+	assert.Equal(t, "1.8.1", resolved)
+}
+
+func TestGetTerraformVersionConstraintE(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	terraformDir := filepath.Join(rootDir, "modules", "vpc")
+	require.NoError(t, os.MkdirAll(terraformDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(terraformDir, "versions.tf"), []byte(`
+terraform {
+  required_version = ">= 1.3"
+
+  required_providers {
+    null = {
+      source = "hashicorp/null"
+    }
+  }
+}
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(terraformDir, "main.tf"), []byte(`
+terraform {
+  required_version = "< 2.0"
+}
+`), 0644))
+
+	constraint, err := GetTerraformVersionConstraintE(terraformDir, TerraformDefaultPath)
+	require.NoError(t, err)
+	assert.Contains(t, []string{">= 1.3, < 2.0", "< 2.0, >= 1.3"}, constraint)
+
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, TerraformVersionFile), []byte("1.5.7\n"), 0644))
+	constraint, err = GetTerraformVersionConstraintE(terraformDir, TerraformDefaultPath)
+	require.NoError(t, err)
+	assert.Equal(t, "1.5.7", constraint)
+
+	require.NoError(t, os.WriteFile(filepath.Join(terraformDir, TofuVersionFile), []byte("1.8.1\n"), 0644))
+	constraint, err = GetTerraformVersionConstraintE(terraformDir, TofuDefaultPath)
+	require.NoError(t, err)
+	assert.Equal(t, "1.8.1", constraint)
+
+	_, err = GetTerraformVersionConstraintE(t.TempDir(), TerraformDefaultPath)
+	assert.ErrorAs(t, err, new(TerraformVersionNotFound))
+
+	invalidDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(invalidDir, "versions.tf"), []byte(`
+terraform {
+  required_version = [">= 1.3"]
+}
+`), 0644))
+	_, err = GetTerraformVersionConstraintE(invalidDir, TerraformDefaultPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "required_version must be a string")
+}
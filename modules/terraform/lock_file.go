@@ -0,0 +1,95 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// LockFileName is the name of the dependency lock file of Terraform, in the root of the TerraformDir.
+const LockFileName = ".terraform.lock.hcl"
+
+// DefaultLockFilePlatforms are the platforms that AssertLockFileConsistent checks the hashes of when the
+// LockFilePlatforms of the options are not set.
+var DefaultLockFilePlatforms = []string{"linux_amd64", "linux_arm64", "darwin_amd64", "darwin_arm64", "windows_amd64"}
+
+// AssertLockFileConsistent runs terraform providers lock for the LockFilePlatforms of the given options (or the
+// DefaultLockFilePlatforms) and fails the test if that changes the .terraform.lock.hcl file in the TerraformDir, e.g.
+// because it is missing the hashes of the providers for some of the platforms. The lock file is left unchanged.
+func AssertLockFileConsistent(t testing.TestingT, options *Options) {
+	require.NoError(t, AssertLockFileConsistentE(t, options))
+}
+
+// AssertLockFileConsistentE runs terraform providers lock for the LockFilePlatforms of the given options (or the
+// DefaultLockFilePlatforms) and returns a LockFileInconsistent error if that changes the .terraform.lock.hcl file in
+// the TerraformDir, e.g. because it is missing the hashes of the providers for some of the platforms. The lock file
+// is left unchanged.
+func AssertLockFileConsistentE(t testing.TestingT, options *Options) error {
+	lockFilePath := filepath.Join(options.TerraformDir, LockFileName)
+	original, err := os.ReadFile(lockFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return LockFileNotFound(lockFilePath)
+		}
+		return err
+	}
+
+	platforms := options.LockFilePlatforms
+	if len(platforms) == 0 {
+		platforms = DefaultLockFilePlatforms
+	}
+
+	args := []string{"providers", "lock"}
+	for _, platform := range platforms {
+		args = append(args, "-platform="+platform)
+	}
+
+	_, lockErr := RunTerraformCommandE(t, options, args...)
+
+	updated, err := os.ReadFile(lockFilePath)
+	// Always restore the lock file, so that checking it doesn't change the module under test.
+	if restoreErr := os.WriteFile(lockFilePath, original, 0644); restoreErr != nil && lockErr == nil {
+		lockErr = restoreErr
+	}
+	if lockErr != nil {
+		return lockErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if string(updated) == string(original) {
+		return nil
+	}
+	return LockFileInconsistent{
+		Path:         lockFilePath,
+		Platforms:    platforms,
+		AddedLines:   getAddedLines(string(original), string(updated)),
+		RemovedLines: getAddedLines(string(updated), string(original)),
+	}
+}
+
+// getAddedLines returns the trimmed, non-empty lines of updated that are not in original, in the order of updated.
+func getAddedLines(original string, updated string) []string {
+	originalLines := map[string]int{}
+	for _, line := range strings.Split(original, "\n") {
+		originalLines[strings.TrimSpace(line)]++
+	}
+
+	added := []string{}
+	for _, line := range strings.Split(updated, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if originalLines[line] > 0 {
+			originalLines[line]--
+			continue
+		}
+		added = append(added, line)
+	}
+	return added
+}
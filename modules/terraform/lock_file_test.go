@@ -0,0 +1,69 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLockFile = `provider "registry.terraform.io/hashicorp/null" {
+  version = "3.2.2"
+  hashes = [
+    "h1:linux_amd64",
+  ]
+}
+`
+
+// writeLockStub writes a script that can be used as TerraformBinary, which records its args in the returned log file
+// and, for providers lock, adds the hash of every platform other than linux_amd64 to the lock file.
+func writeLockStub(t *testing.T) (string, string) {
+	dir := t.TempDir()
+	stubPath := filepath.Join(dir, "terraform-stub")
+	logPath := filepath.Join(dir, "commands.log")
+	script := fmt.Sprintf(`#!/bin/sh
+echo "$@" >> %q
+shift 2
+for platform in "$@"; do
+  platform="${platform#-platform=}"
+  if [ "$platform" != "linux_amd64" ]; then
+    sed -i "s/\"h1:linux_amd64\",/&\n    \"h1:$platform\",/" %s
+  fi
+done
+`, logPath, LockFileName)
+	require.NoError(t, os.WriteFile(stubPath, []byte(script), 0755))
+	return stubPath, logPath
+}
+
+func TestAssertLockFileConsistentE(t *testing.T) {
+	t.Parallel()
+
+	stubPath, logPath := writeLockStub(t)
+	terraformDir := t.TempDir()
+	lockFilePath := filepath.Join(terraformDir, LockFileName)
+	require.NoError(t, os.WriteFile(lockFilePath, []byte(testLockFile), 0644))
+
+	options := &Options{TerraformDir: terraformDir, TerraformBinary: stubPath, LockFilePlatforms: []string{"linux_amd64"}}
+	require.NoError(t, AssertLockFileConsistentE(t, options))
+
+	options.LockFilePlatforms = []string{"linux_amd64", "darwin_arm64"}
+	err := AssertLockFileConsistentE(t, options)
+	var inconsistent LockFileInconsistent
+	require.ErrorAs(t, err, &inconsistent)
+	assert.Equal(t, []string{`"h1:darwin_arm64",`}, inconsistent.AddedLines)
+	assert.Empty(t, inconsistent.RemovedLines)
+
+	lockFile, err := os.ReadFile(lockFilePath)
+	require.NoError(t, err)
+	assert.Equal(t, testLockFile, string(lockFile))
+
+	commands, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(commands), "providers lock -platform=linux_amd64 -platform=darwin_arm64")
+
+	err = AssertLockFileConsistentE(t, &Options{TerraformDir: t.TempDir(), TerraformBinary: stubPath})
+	assert.ErrorAs(t, err, new(LockFileNotFound))
+}
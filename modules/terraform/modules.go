@@ -0,0 +1,83 @@
+package terraform
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// ModuleCall is a module block that terraform init installed, either in the root module or in one of its sub-modules.
+type ModuleCall struct {
+	Key     string // The dot-separated path of the call from the root module (e.g. "vpc" or "vpc.subnets")
+	Source  string // The source of the module, as written in the module block
+	Version string // The version of the module that was installed. Empty for sources without versions (e.g. local paths).
+	Dir     string // The directory, relative to TerraformDir, where the module was installed
+}
+
+// moduleManifest is the structure of the .terraform/modules/modules.json file terraform init writes.
+type moduleManifest struct {
+	Modules []ModuleCall `json:"Modules"`
+}
+
+// GetModuleCalls returns all the module calls of the configuration in options.TerraformDir, including the calls of
+// sub-modules, sorted by key. They are read from the module manifest written by terraform init, so init must have run
+// before calling this function. This will fail the test if there is an error.
+func GetModuleCalls(t testing.TestingT, options *Options) []ModuleCall {
+	moduleCalls, err := GetModuleCallsE(t, options)
+	require.NoError(t, err)
+	return moduleCalls
+}
+
+// GetModuleCallsE returns all the module calls of the configuration in options.TerraformDir, including the calls of
+// sub-modules, sorted by key. They are read from the module manifest written by terraform init, so init must have run
+// before calling this function.
+func GetModuleCallsE(t testing.TestingT, options *Options) ([]ModuleCall, error) {
+	manifestPath := moduleManifestPath(options)
+	content, err := os.ReadFile(manifestPath)
+	if os.IsNotExist(err) {
+		return nil, ModuleManifestNotFound(manifestPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseModuleManifest(content)
+}
+
+// moduleManifestPath returns the path of the module manifest, taking into account a TF_DATA_DIR override in the
+// environment variables of the options.
+func moduleManifestPath(options *Options) string {
+	dataDir := ".terraform"
+	if envDataDir, ok := options.EnvVars["TF_DATA_DIR"]; ok && envDataDir != "" {
+		dataDir = envDataDir
+	}
+	if !filepath.IsAbs(dataDir) {
+		dataDir = filepath.Join(options.TerraformDir, dataDir)
+	}
+	return filepath.Join(dataDir, "modules", "modules.json")
+}
+
+// parseModuleManifest returns the module calls in the given module manifest, sorted by key. The entry for the root
+// module, which has an empty key, is left out.
+func parseModuleManifest(content []byte) ([]ModuleCall, error) {
+	manifest := moduleManifest{}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, err
+	}
+
+	moduleCalls := []ModuleCall{}
+	for _, module := range manifest.Modules {
+		if module.Key == "" {
+			continue
+		}
+		moduleCalls = append(moduleCalls, module)
+	}
+	sort.Slice(moduleCalls, func(i, j int) bool {
+		return moduleCalls[i].Key < moduleCalls[j].Key
+	})
+
+	return moduleCalls, nil
+}
@@ -0,0 +1,60 @@
+package terraform
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetModuleCalls(t *testing.T) {
+	t.Parallel()
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-module-calls", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir: testFolder,
+	}
+
+	_, err = GetModuleCallsE(t, options)
+	require.IsType(t, ModuleManifestNotFound(""), err)
+
+	Init(t, options)
+
+	moduleCalls := GetModuleCalls(t, options)
+	require.Len(t, moduleCalls, 2)
+	assert.Equal(t, "child", moduleCalls[0].Key)
+	assert.Equal(t, "./modules/child", moduleCalls[0].Source)
+	assert.Equal(t, "child.grandchild", moduleCalls[1].Key)
+	assert.Equal(t, "./modules/grandchild", moduleCalls[1].Source)
+}
+
+func TestParseModuleManifest(t *testing.T) {
+	t.Parallel()
+
+	manifest := `{"Modules":[
+		{"Key":"","Source":"","Dir":"."},
+		{"Key":"vpc","Source":"registry.terraform.io/terraform-aws-modules/vpc/aws","Version":"5.1.2","Dir":".terraform/modules/vpc"},
+		{"Key":"app","Source":"git::https://example.com/app.git?ref=v1.0.0","Dir":".terraform/modules/app"}
+	]}`
+
+	moduleCalls, err := parseModuleManifest([]byte(manifest))
+	require.NoError(t, err)
+	assert.Equal(t, []ModuleCall{
+		{Key: "app", Source: "git::https://example.com/app.git?ref=v1.0.0", Dir: ".terraform/modules/app"},
+		{Key: "vpc", Source: "registry.terraform.io/terraform-aws-modules/vpc/aws", Version: "5.1.2", Dir: ".terraform/modules/vpc"},
+	}, moduleCalls)
+}
+
+func TestModuleManifestPath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, filepath.Join("root", ".terraform", "modules", "modules.json"), moduleManifestPath(&Options{TerraformDir: "root"}))
+	assert.Equal(t, filepath.Join("root", "data", "modules", "modules.json"), moduleManifestPath(&Options{
+		TerraformDir: "root",
+		EnvVars:      map[string]string{"TF_DATA_DIR": "data"},
+	}))
+}
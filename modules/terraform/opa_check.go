@@ -74,6 +74,40 @@ func OPAEvalE(
 	return opa.EvalE(t, opaEvalOptions, jsonFiles, resultQuery)
 }
 
+// OPAEvalPlan evaluates all the Rego policies of the RulePath of the OPA options (a file or a directory) against the
+// given plan, in the JSON format returned by InitAndPlanAndShow, and returns the violations reported by their deny and
+// violation rules, with the addresses of the resources that violate them. See opa.EvalPolicies for the format of the
+// rules. This function fails the test if the policies can't be evaluated, but not if there are violations.
+func OPAEvalPlan(t testing.TestingT, tfOptions *Options, opaEvalOptions *opa.EvalOptions, planJson string) []opa.Violation {
+	violations, err := OPAEvalPlanE(t, tfOptions, opaEvalOptions, planJson)
+	require.NoError(t, err)
+	return violations
+}
+
+// OPAEvalPlanE evaluates all the Rego policies of the RulePath of the OPA options (a file or a directory) against the
+// given plan, in the JSON format returned by InitAndPlanAndShow, and returns the violations reported by their deny and
+// violation rules, with the addresses of the resources that violate them. See opa.EvalPolicies for the format of the
+// rules.
+func OPAEvalPlanE(t testing.TestingT, tfOptions *Options, opaEvalOptions *opa.EvalOptions, planJson string) ([]opa.Violation, error) {
+	tfOptions.Logger.Logf(t, "Running the plan of %s through `opa eval` on policies %s", tfOptions.TerraformDir, opaEvalOptions.RulePath)
+
+	planFile, err := os.CreateTemp("", "terratest-opa-plan-*.json")
+	if err != nil {
+		return nil, err
+	}
+	if !opaEvalOptions.DebugKeepTempFiles {
+		defer os.Remove(planFile.Name())
+	}
+
+	_, err = planFile.WriteString(planJson)
+	planFile.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return opa.EvalPoliciesE(t, opaEvalOptions, planFile.Name())
+}
+
 // HCLFileToJSONFile is a function that takes a path containing HCL code, and converts it to JSON representation and
 // writes out the contents to the given path.
 func HCLFileToJSONFile(hclPath, jsonOutPath string) error {
@@ -1,6 +1,7 @@
 package terraform
 
 import (
+	"context"
 	"time"
 
 	"github.com/gruntwork-io/terratest/modules/logger"
@@ -36,10 +37,32 @@ var (
 	}
 )
 
+// DefaultRetryableDestroyErrors are the transient errors of `terraform destroy` that are retried in addition to the
+// RetryableTerraformErrors, if MaxRetries is set (e.g. with WithDefaultRetryableErrors) and no ErrorClassifier is set. Destroy frequently fails
+// when a resource is deleted while the resources that depend on it (e.g., ENIs created by Lambda or EKS, objects
+// written by log delivery) are still being cleaned up, and these usually succeed once the dependents are gone. They are
+// not retried on apply, where they point to a permanent problem with the configuration.
+var DefaultRetryableDestroyErrors = map[string]string{
+	".*DependencyViolation.*":    "Resource still has dependents that are being deleted.",
+	".*is currently in use.*":    "Resource still in use by dependents that are being deleted.",
+	".*ResourceInUseException.*": "Resource still in use by dependents that are being deleted.",
+	".*BucketNotEmpty.*":         "Bucket still has objects that are being deleted.",
+}
+
+// DefaultRetryableOpenTofuErrors are the transient errors specific to OpenTofu that are retried in addition to the
+// RetryableTerraformErrors when using WithOpenTofu.
+var DefaultRetryableOpenTofuErrors = map[string]string{
+	// `tofu init` fails with these when the OpenTofu registry or the GitHub releases it redirects to can't be reached.
+	".*Could not retrieve the list of available versions for provider.*": "Failed to retrieve plugin due to transient network error.",
+	".*Failed to install provider.*":                                     "Failed to retrieve plugin due to transient network error.",
+	".*registry.opentofu.org.*(timeout|connection reset|EOF).*":          "Failed to reach the OpenTofu registry.",
+}
+
 // Options for running Terraform commands
 type Options struct {
-	TerraformBinary string // Name of the binary that will be used
-	TerraformDir    string // The path to the folder where the Terraform code is defined.
+	TerraformBinary  string // Name of the binary that will be used
+	TerraformDir     string // The path to the folder where the Terraform code is defined.
+	TerraformVersion string // The version (e.g. 1.5.7), version constraint (e.g. ~> 1.5) or "latest" of terraform (or tofu) that InstallTerraform installs. If not set, the version pinned by the .terraform-version file or required_version of TerraformDir is installed.
 
 	// The vars to pass to Terraform commands using the -var option. Note that terraform does not support passing `null`
 	// as a variable value through the command line. That is, if you use `map[string]interface{}{"foo": nil}` as `Vars`,
@@ -50,8 +73,10 @@ type Options struct {
 	// }
 	Vars map[string]interface{}
 
-	VarFiles                 []string               // The var file paths to pass to Terraform commands using -var-file option.
-	Targets                  []string               // The target resources to pass to the terraform command with -target
+	VarFiles                 []string               // The var file paths to pass to Terraform commands using -var-file option. Paths ending in .tmpl are rendered with TemplateData first.
+	TemplateData             interface{}            // The data used to render any VarFiles ending in .tmpl as Go text templates
+	Targets                  []string               // The target resources to pass to the plan, apply, destroy and refresh commands with -target
+	Replace                  []string               // The resources to force the plan and apply commands to replace (destroy and recreate) with -replace
 	Lock                     bool                   // The lock option to pass to the terraform command with -lock
 	LockTimeout              string                 // The lock timeout option to pass to the terraform command with -lock-timeout
 	EnvVars                  map[string]string      // Environment variables to set when running Terraform
@@ -63,26 +88,49 @@ type Options struct {
 	Reconfigure              bool                   // Set the -reconfigure flag to the terraform init command
 	MigrateState             bool                   // Set the -migrate-state and -force-copy (suppress 'yes' answer prompt) flag to the terraform init command
 	NoColor                  bool                   // Whether the -no-color flag will be set for any Terraform command or not
+	CompactWarnings          bool                   // Whether the -compact-warnings flag will be set for the Terraform commands that support it (plan, apply, destroy and refresh)
+	Workspace                string                 // The workspace to run all the Terraform commands in. Init creates it if it doesn't exist yet.
+	AllowNoState             bool                   // Make Destroy succeed without running terraform destroy if the state is empty or the working directory was never initialized
+	RollbackVars             map[string]interface{} // The known-good vars that ApplyAndValidate re-applies (instead of Vars) when the validation fails
+	FailureArtifactsDir      string                 // The directory to which ApplyAndValidate (and InitAndApply, if SaveFailureArtifacts is set) saves the output, plan and state when they fail. A temp dir named after the test is used if not set.
+	SaveFailureArtifacts     bool                   // Make InitAndApply save the output, plan and state to FailureArtifactsDir when init or apply fails, for debugging failures in CI
 	SshAgent                 *ssh.SshAgent          // Overrides local SSH agent with the given in-process agent
 	NoStderr                 bool                   // Disable stderr redirection
 	OutputMaxLineSize        int                    // The max size of one line in stdout and stderr (in bytes)
 	Logger                   *logger.Logger         // Set a non-default logger that should be used. See the logger package for more info.
+	OutputCallback           func(line string)      `json:"-"` // If set, called with each line of output of the Terraform commands as soon as it is written, e.g. to report the progress of long applies or to detect stalls. It isn't saved with the options.
 	Parallelism              int                    // Set the parallelism setting for Terraform
 	PlanFilePath             string                 // The path to output a plan file to (for the plan command) or read one from (for the apply command)
 	PluginDir                string                 // The path of downloaded plugins to pass to the terraform init command (-plugin-dir)
+	ProviderCacheDir         string                 // The directory in which Terraform caches the providers it downloads (TF_PLUGIN_CACHE_DIR), so that tests sharing it download each provider only once. Init creates it if it doesn't exist.
+	LockFilePlatforms        []string               // The platforms (e.g. darwin_arm64) for which AssertLockFileConsistent checks the provider hashes in the lock file. DefaultLockFilePlatforms is used if not set.
+	ProviderMirrorDir        string                 // A filesystem mirror (e.g. from SetupProviderMirror) from which the terraform init command installs the providers it contains instead of downloading them. This overrides TF_CLI_CONFIG_FILE for init.
+	SensitiveOutputs         bool                   // Redact the values of the outputs marked as sensitive from the logs of the Output functions (see OutputSensitive)
 	SetVarsAfterVarFiles     bool                   // Pass -var options after -var-file options to Terraform commands
-	WarningsAsErrors         map[string]string      // Terraform warning messages that should be treated as errors. The keys are a regexp to match against the warning and the value is what to display to a user if that warning is matched.
+	VarsInVarFile            bool                   // Pass Vars to Terraform commands in a generated var file (see WriteVarFile) instead of -var options, which handles complex and multi-line values better
+	WarningsAsErrors         map[string]string      // Terraform warning messages that should be treated as errors. The keys are a regexp to match against the warning and the value is what to display to a user if that warning is matched. Use ".*" as a key to fail on any warning.
+	RetryOnWarnings          bool                   // Also match RetryableTerraformErrors against the warnings in the output. By default, warnings are ignored when deciding whether to retry.
+	ErrorClassifier          ErrorClassifier        `json:"-"` // If set, decides which errors are retried instead of RetryableTerraformErrors (see WithBuiltinRetryRules). It isn't saved with the options.
+	RetryMetrics             *RetryMetrics          `json:"-"` // If set, counts the rules of the ErrorClassifier (or the RetryableTerraformErrors) that matched the errors of the commands. It isn't saved with the options.
+	// If set, the Terraform commands are interrupted when the context is done (e.g. the context passed to the stage by
+	// test_structure.RunTestStageWithContext), so that Terraform stops gracefully instead of running on after the test.
+	// The context isn't saved with the options (see test_structure.SaveTerraformOptions).
+	Context context.Context `json:"-"`
 }
 
 // Clone makes a deep copy of most fields on the Options object and returns it.
 //
 // NOTE: options.SshAgent and options.Logger CANNOT be deep copied (e.g., the SshAgent struct contains channels and
-// listeners that can't be meaningfully copied), so the original values are retained.
+// listeners that can't be meaningfully copied), so the original values are retained. options.ErrorClassifier and
+// options.RetryMetrics are retained too, so that the metrics of the copies are counted together, and so is options.Context.
 func (options *Options) Clone() (*Options, error) {
 	newOptions := &Options{}
 	if err := copier.Copy(newOptions, options); err != nil {
 		return nil, err
 	}
+	newOptions.ErrorClassifier = options.ErrorClassifier
+	newOptions.RetryMetrics = options.RetryMetrics
+	newOptions.Context = options.Context
 	// copier does not deep copy maps, so we have to do it manually.
 	newOptions.EnvVars = make(map[string]string)
 	for key, val := range options.EnvVars {
@@ -100,6 +148,12 @@ func (options *Options) Clone() (*Options, error) {
 	for key, val := range options.RetryableTerraformErrors {
 		newOptions.RetryableTerraformErrors[key] = val
 	}
+	if options.RollbackVars != nil {
+		newOptions.RollbackVars = make(map[string]interface{})
+		for key, val := range options.RollbackVars {
+			newOptions.RollbackVars[key] = val
+		}
+	}
 	newOptions.WarningsAsErrors = make(map[string]string)
 	for key, val := range options.WarningsAsErrors {
 		newOptions.WarningsAsErrors[key] = val
@@ -130,3 +184,25 @@ func WithDefaultRetryableErrors(t testing.TestingT, originalOptions *Options) *O
 
 	return newOptions
 }
+
+// WithOpenTofu makes a copy of the Options object and returns an updated object that runs OpenTofu instead of
+// Terraform, so the same test can run against either engine (e.g. from a CI matrix). If TerraformBinary is terragrunt,
+// terragrunt is told to run tofu instead. The DefaultRetryableOpenTofuErrors are added to the
+// RetryableTerraformErrors, which are only retried if MaxRetries is set (e.g. with WithDefaultRetryableErrors).
+// This will fail the test if there are any errors in the cloning process.
+func WithOpenTofu(t testing.TestingT, originalOptions *Options) *Options {
+	newOptions, err := originalOptions.Clone()
+	require.NoError(t, err)
+
+	if newOptions.TerraformBinary == TerragruntDefaultPath {
+		newOptions.EnvVars["TERRAGRUNT_TFPATH"] = TofuDefaultPath
+	} else {
+		newOptions.TerraformBinary = TofuDefaultPath
+	}
+
+	for k, v := range DefaultRetryableOpenTofuErrors {
+		newOptions.RetryableTerraformErrors[k] = v
+	}
+
+	return newOptions
+}
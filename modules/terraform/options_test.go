@@ -1,6 +1,8 @@
 package terraform
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/gruntwork-io/terratest/modules/random"
@@ -41,3 +43,45 @@ func TestOptionsCloneDeepClonesVars(t *testing.T) {
 	assert.Equal(t, unique, original.Vars["unique"])
 	assert.Equal(t, unique, copied.Vars["original"])
 }
+
+func TestOptionsCloneRetainsContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	original := Options{TerraformDir: "vpc", Context: ctx}
+	copied, err := original.Clone()
+	require.NoError(t, err)
+	assert.Equal(t, ctx, copied.Context)
+	assert.Equal(t, ctx, generateCommand(copied, "apply").Context)
+
+	// The context isn't saved along with the options
+	data, err := json.Marshal(original)
+	require.NoError(t, err)
+	var loaded Options
+	require.NoError(t, json.Unmarshal(data, &loaded))
+	assert.Equal(t, "vpc", loaded.TerraformDir)
+	assert.Nil(t, loaded.Context)
+}
+
+func TestWithOpenTofu(t *testing.T) {
+	t.Parallel()
+
+	original := &Options{
+		TerraformBinary:          TerraformDefaultPath,
+		RetryableTerraformErrors: map[string]string{".*foo.*": "foo"},
+	}
+	options := WithOpenTofu(t, original)
+	assert.Equal(t, TofuDefaultPath, options.TerraformBinary)
+	assert.Equal(t, "foo", options.RetryableTerraformErrors[".*foo.*"])
+	for k := range DefaultRetryableOpenTofuErrors {
+		assert.Contains(t, options.RetryableTerraformErrors, k)
+	}
+	assert.Equal(t, TerraformDefaultPath, original.TerraformBinary)
+	assert.Len(t, original.RetryableTerraformErrors, 1)
+
+	terragruntOptions := WithOpenTofu(t, &Options{TerraformBinary: TerragruntDefaultPath})
+	assert.Equal(t, TerragruntDefaultPath, terragruntOptions.TerraformBinary)
+	assert.Equal(t, TofuDefaultPath, terragruntOptions.EnvVars["TERRAGRUNT_TFPATH"])
+}
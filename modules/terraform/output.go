@@ -284,7 +284,14 @@ func OutputJson(t testing.TestingT, options *Options, key string) string {
 // OutputJsonE calls terraform output for the given variable and returns the
 // result as the json string.
 // If key is an empty string, it will return all the output variables.
+// If options.SensitiveOutputs is set, the sensitive outputs are redacted from the logs.
 func OutputJsonE(t testing.TestingT, options *Options, key string) (string, error) {
+	if options.SensitiveOutputs {
+		if _, err := registerSensitiveOutputsE(t, options); err != nil {
+			return "", err
+		}
+	}
+
 	args := []string{"output", "-no-color", "-json"}
 	if key != "" {
 		args = append(args, key)
@@ -319,6 +326,24 @@ func OutputStructE(t testing.TestingT, options *Options, key string, v interface
 	return json.Unmarshal([]byte(out), &v)
 }
 
+// OutputAs calls terraform output for the given variable and returns its value decoded into a value of type T (e.g. a
+// struct, a map of lists or a list of structs), using the same rules as json.Unmarshal. If the value returned by
+// Terraform is not appropriate for T, it fails the test.
+func OutputAs[T any](t testing.TestingT, options *Options, key string) T {
+	out, err := OutputAsE[T](t, options, key)
+	require.NoError(t, err)
+	return out
+}
+
+// OutputAsE calls terraform output for the given variable and returns its value decoded into a value of type T (e.g. a
+// struct, a map of lists or a list of structs), using the same rules as json.Unmarshal. If the value returned by
+// Terraform is not appropriate for T, it returns an error.
+func OutputAsE[T any](t testing.TestingT, options *Options, key string) (T, error) {
+	var out T
+	err := OutputStructE(t, options, key, &out)
+	return out, err
+}
+
 // OutputForKeysE calls terraform output for the given key list and returns values as a map.
 // The returned values are of type interface{} and need to be type casted as necessary. Refer to output_test.go
 func OutputForKeysE(t testing.TestingT, options *Options, keys []string) (map[string]interface{}, error) {
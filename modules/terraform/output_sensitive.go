@@ -0,0 +1,96 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// outputMeta is an output as returned by terraform output -json.
+type outputMeta struct {
+	Sensitive bool        `json:"sensitive"`
+	Value     interface{} `json:"value"`
+}
+
+// OutputSensitive calls terraform output for the given variable and returns its string value representation, like
+// Output, but without logging the output of Terraform. The value of the output, and the values of all the outputs
+// marked as sensitive in Terraform, are registered with logger.RegisterSecret so that they are redacted from the logs
+// of the test from now on. This will fail the test if the output can't be read.
+func OutputSensitive(t testing.TestingT, options *Options, key string) string {
+	out, err := OutputSensitiveE(t, options, key)
+	require.NoError(t, err)
+	return out
+}
+
+// OutputSensitiveE calls terraform output for the given variable and returns its string value representation, like
+// OutputE, but without logging the output of Terraform. The value of the output, and the values of all the outputs
+// marked as sensitive in Terraform, are registered with logger.RegisterSecret so that they are redacted from the logs
+// of the test from now on.
+func OutputSensitiveE(t testing.TestingT, options *Options, key string) (string, error) {
+	outputs, err := registerSensitiveOutputsE(t, options)
+	if err != nil {
+		return "", err
+	}
+
+	output, exists := outputs[key]
+	if !exists {
+		return "", OutputKeyNotFound(key)
+	}
+	registerSecretValues(output.Value)
+
+	options.Logger.Logf(t, "Read sensitive output %s", key)
+	return fmt.Sprintf("%v", output.Value), nil
+}
+
+// registerSensitiveOutputsE reads all the outputs with terraform output -json without logging them, registers the
+// values of the sensitive ones with logger.RegisterSecret, and returns all the outputs.
+func registerSensitiveOutputsE(t testing.TestingT, options *Options) (map[string]outputMeta, error) {
+	quietOptions, err := options.Clone()
+	if err != nil {
+		return nil, err
+	}
+	quietOptions.Logger = logger.Discard
+	quietOptions.OutputCallback = nil
+
+	rawJson, err := RunTerraformCommandAndGetStdoutE(t, quietOptions, "output", "-no-color", "-json")
+	if err != nil {
+		// the output may contain the sensitive values
+		return nil, fmt.Errorf("failed to read the outputs: %s", logger.Redact(err.Error()))
+	}
+	out, err := cleanJson(rawJson)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := map[string]outputMeta{}
+	if err := json.Unmarshal([]byte(out), &outputs); err != nil {
+		return nil, err
+	}
+
+	for _, output := range outputs {
+		if output.Sensitive {
+			registerSecretValues(output.Value)
+		}
+	}
+	return outputs, nil
+}
+
+// registerSecretValues registers the strings in the given output value (recursively for lists and maps) with
+// logger.RegisterSecret. Numbers and bools are not registered, as redacting them would redact unrelated logs.
+func registerSecretValues(value interface{}) {
+	switch value := value.(type) {
+	case string:
+		logger.RegisterSecret(value)
+	case []interface{}:
+		for _, item := range value {
+			registerSecretValues(item)
+		}
+	case map[string]interface{}:
+		for _, item := range value {
+			registerSecretValues(item)
+		}
+	}
+}
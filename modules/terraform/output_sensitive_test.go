@@ -0,0 +1,78 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	terratesting "github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sensitiveOutputsJson = `{
+  "db_password": {"sensitive": true, "type": "string", "value": "output-sensitive-test-password"},
+  "db_credentials": {"sensitive": true, "type": ["object", {"user": "string", "tokens": ["list", "string"]}], "value": {"user": "output-sensitive-test-user", "tokens": ["output-sensitive-test-token"]}},
+  "db_port": {"sensitive": true, "type": "number", "value": 5432},
+  "db_host": {"sensitive": false, "type": "string", "value": "db.example.com"}
+}`
+
+type recordingLogger struct {
+	logs []string
+}
+
+func (l *recordingLogger) Logf(t terratesting.TestingT, format string, args ...interface{}) {
+	l.logs = append(l.logs, fmt.Sprintf(format, args...))
+}
+
+// writeOutputStub writes a terraform binary stub that prints the given JSON to stdout for terraform output.
+func writeOutputStub(t *testing.T, outputJson string) string {
+	stubPath := filepath.Join(t.TempDir(), "terraform-stub")
+	script := fmt.Sprintf("#!/bin/sh\nif [ \"$1\" = \"output\" ]; then\n  cat <<'EOF'\n%s\nEOF\nfi\n", outputJson)
+	require.NoError(t, os.WriteFile(stubPath, []byte(script), 0755))
+	return stubPath
+}
+
+func TestOutputSensitiveERedactsSensitiveOutputs(t *testing.T) {
+	t.Parallel()
+
+	recorder := &recordingLogger{}
+	options := &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: writeOutputStub(t, sensitiveOutputsJson),
+		Logger:          logger.New(recorder),
+	}
+
+	password, err := OutputSensitiveE(t, options, "db_password")
+	require.NoError(t, err)
+	assert.Equal(t, "output-sensitive-test-password", password)
+
+	_, err = OutputSensitiveE(t, options, "missing")
+	assert.Equal(t, OutputKeyNotFound("missing"), err)
+
+	options.Logger.Logf(t, "password=%s user=%s token=%s port=%d host=%s", password, "output-sensitive-test-user", "output-sensitive-test-token", 5432, "db.example.com")
+	logs := strings.Join(recorder.logs, "\n")
+	assert.NotContains(t, logs, "output-sensitive-test")
+	assert.Contains(t, logs, "password=[REDACTED] user=[REDACTED] token=[REDACTED] port=5432 host=db.example.com")
+}
+
+func TestOutputERedactsSensitiveOutputsWithSensitiveOutputs(t *testing.T) {
+	t.Parallel()
+
+	recorder := &recordingLogger{}
+	options := &Options{
+		TerraformDir:     t.TempDir(),
+		TerraformBinary:  writeOutputStub(t, `{"api_key": {"sensitive": true, "type": "string", "value": "output-e-sensitive-test-key"}}`),
+		Logger:           logger.New(recorder),
+		SensitiveOutputs: true,
+	}
+
+	out, err := OutputJsonE(t, options, "")
+	require.NoError(t, err)
+	assert.Contains(t, out, "output-e-sensitive-test-key")
+	assert.NotEmpty(t, recorder.logs)
+	assert.NotContains(t, strings.Join(recorder.logs, "\n"), "output-e-sensitive-test-key")
+}
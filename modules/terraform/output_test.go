@@ -416,6 +416,52 @@ func TestOutputStruct(t *testing.T) {
 	require.Equal(t, expectedList, actualList, "List should be %q, got %q", expectedList, actualList)
 }
 
+func TestOutputAs(t *testing.T) {
+	t.Parallel()
+
+	type ListMap struct {
+		Five float64 `json:"five"`
+		Six  string  `json:"six"`
+	}
+	type TestStruct struct {
+		Somebool    bool                   `json:"somebool"`
+		Somefloat   float64                `json:"somefloat"`
+		Someint     int                    `json:"someint"`
+		Somestring  string                 `json:"somestring"`
+		Somemap     map[string]interface{} `json:"somemap"`
+		Listmaps    []ListMap              `json:"listmaps"`
+		Liststrings []string               `json:"liststrings"`
+	}
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-output-struct", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir: testFolder,
+	}
+
+	InitAndApply(t, options)
+
+	actualObject := OutputAs[TestStruct](t, options, "object")
+	require.Equal(t, TestStruct{
+		Somebool:    true,
+		Somefloat:   0.1,
+		Someint:     1,
+		Somestring:  "two",
+		Somemap:     map[string]interface{}{"three": 3.0, "four": "four"},
+		Listmaps:    []ListMap{{Five: 5, Six: "six"}},
+		Liststrings: []string{"seven", "eight", "nine"},
+	}, actualObject)
+
+	actualList := OutputAs[[]TestStruct](t, options, "list_of_objects")
+	require.Len(t, actualList, 2)
+	require.Equal(t, "five", actualList[1].Somestring)
+	require.Equal(t, 4, actualList[1].Someint)
+
+	_, err = OutputAsE[[]TestStruct](t, options, "object")
+	require.Error(t, err)
+}
+
 func TestOutputsAll(t *testing.T) {
 	t.Parallel()
 
@@ -471,9 +517,10 @@ func TestOutputsForKeysError(t *testing.T) {
 
 	InitAndApply(t, options)
 
-	_, err = OutputForKeysE(t, options, []string{"random_key"})
+	_, err = OutputForKeysE(t, options, []string{"mogwai", "random_key"})
 
 	require.Error(t, err)
+	assert.Equal(t, OutputKeyNotFound("random_key"), err)
 }
 
 func TestTgOutputJsonParsing(t *testing.T) {
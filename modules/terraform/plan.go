@@ -18,10 +18,11 @@ func InitAndPlan(t testing.TestingT, options *Options) string {
 	return out
 }
 
-// InitAndPlanE runs terraform init and plan with the given options and returns stdout/stderr from the plan command.
+// InitAndPlanE runs terraform init and plan with the given options and returns stdout/stderr from the plan command, or
+// from the init command if init fails.
 func InitAndPlanE(t testing.TestingT, options *Options) (string, error) {
-	if _, err := InitE(t, options); err != nil {
-		return "", err
+	if out, err := InitE(t, options); err != nil {
+		return out, err
 	}
 
 	return PlanE(t, options)
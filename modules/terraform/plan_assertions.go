@@ -0,0 +1,109 @@
+package terraform
+
+import (
+	"sort"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// AssertResourceChange checks that the plan makes exactly the given changes to the resource with the given full
+// address (e.g. tfjson.Actions{tfjson.ActionCreate}, or tfjson.Actions{tfjson.ActionDelete, tfjson.ActionCreate} for
+// a replacement), failing the test if it does not.
+func AssertResourceChange(t testing.TestingT, plan *PlanStruct, address string, expected tfjson.Actions) {
+	actual, hasChange := getResourceChangeActions(plan, address)
+	if assert.Truef(t, hasChange, "Given resource changes map does not have key %s", address) {
+		assert.Equalf(t, expected, actual, "Unexpected planned changes for resource %s", address)
+	}
+}
+
+// RequireResourceChange checks that the plan makes exactly the given changes to the resource with the given full
+// address (e.g. tfjson.Actions{tfjson.ActionCreate}, or tfjson.Actions{tfjson.ActionDelete, tfjson.ActionCreate} for
+// a replacement), failing and halting the test if it does not.
+func RequireResourceChange(t testing.TestingT, plan *PlanStruct, address string, expected tfjson.Actions) {
+	actual, hasChange := getResourceChangeActions(plan, address)
+	require.Truef(t, hasChange, "Given resource changes map does not have key %s", address)
+	require.Equalf(t, expected, actual, "Unexpected planned changes for resource %s", address)
+}
+
+// AssertPlanCreatesOnly checks that the plan only creates resources, failing the test if it updates, replaces or
+// destroys any resource. Resources without changes and data sources that are read are ignored.
+func AssertPlanCreatesOnly(t testing.TestingT, plan *PlanStruct) {
+	addresses := getResourceAddressesForActions(plan, isNotCreateOnly)
+	assert.Emptyf(t, addresses, "Plan changes resources other than by creating them: %v", addresses)
+}
+
+// RequirePlanCreatesOnly checks that the plan only creates resources, failing and halting the test if it updates,
+// replaces or destroys any resource. Resources without changes and data sources that are read are ignored.
+func RequirePlanCreatesOnly(t testing.TestingT, plan *PlanStruct) {
+	addresses := getResourceAddressesForActions(plan, isNotCreateOnly)
+	require.Emptyf(t, addresses, "Plan changes resources other than by creating them: %v", addresses)
+}
+
+// AssertNoDestroys checks that the plan does not destroy any resource, including as part of a replacement, failing the
+// test if it does.
+func AssertNoDestroys(t testing.TestingT, plan *PlanStruct) {
+	addresses := getResourceAddressesForActions(plan, isDestroy)
+	assert.Emptyf(t, addresses, "Plan destroys resources: %v", addresses)
+}
+
+// RequireNoDestroys checks that the plan does not destroy any resource, including as part of a replacement, failing
+// and halting the test if it does.
+func RequireNoDestroys(t testing.TestingT, plan *PlanStruct) {
+	addresses := getResourceAddressesForActions(plan, isDestroy)
+	require.Emptyf(t, addresses, "Plan destroys resources: %v", addresses)
+}
+
+// GetResourceAddressesForAction returns the sorted full addresses of the resources that the plan changes with the
+// given action (e.g. tfjson.ActionDelete also returns the resources that are replaced).
+func GetResourceAddressesForAction(plan *PlanStruct, action tfjson.Action) []string {
+	return getResourceAddressesForActions(plan, func(actions tfjson.Actions) bool {
+		for _, actual := range actions {
+			if actual == action {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// getResourceChangeActions returns the planned actions for the resource with the given full address, and whether the
+// plan has a change for that resource at all.
+func getResourceChangeActions(plan *PlanStruct, address string) (tfjson.Actions, bool) {
+	resourceChange, hasKey := plan.ResourceChangesMap[address]
+	if !hasKey || resourceChange == nil || resourceChange.Change == nil {
+		return nil, false
+	}
+	return resourceChange.Change.Actions, true
+}
+
+// getResourceAddressesForActions returns the sorted full addresses of the resources whose planned actions match the
+// given function.
+func getResourceAddressesForActions(plan *PlanStruct, matches func(tfjson.Actions) bool) []string {
+	addresses := []string{}
+	for address, resourceChange := range plan.ResourceChangesMap {
+		if resourceChange == nil || resourceChange.Change == nil {
+			continue
+		}
+		if matches(resourceChange.Change.Actions) {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Strings(addresses)
+	return addresses
+}
+
+func isNotCreateOnly(actions tfjson.Actions) bool {
+	return !actions.Create() && !actions.NoOp() && !actions.Read()
+}
+
+func isDestroy(actions tfjson.Actions) bool {
+	for _, action := range actions {
+		if action == tfjson.ActionDelete {
+			return true
+		}
+	}
+	return false
+}
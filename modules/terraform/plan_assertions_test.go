@@ -0,0 +1,57 @@
+package terraform
+
+import (
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPlanStruct(changes map[string]tfjson.Actions) *PlanStruct {
+	plan := &PlanStruct{ResourceChangesMap: map[string]*tfjson.ResourceChange{}}
+	for address, actions := range changes {
+		plan.ResourceChangesMap[address] = &tfjson.ResourceChange{
+			Address: address,
+			Change:  &tfjson.Change{Actions: actions},
+		}
+	}
+	return plan
+}
+
+func TestPlanAssertionsOnCreateOnlyPlan(t *testing.T) {
+	t.Parallel()
+
+	plan := newTestPlanStruct(map[string]tfjson.Actions{
+		"null_resource.foo":            {tfjson.ActionCreate},
+		"module.bar.null_resource.baz": {tfjson.ActionCreate},
+		"null_resource.unchanged":      {tfjson.ActionNoop},
+		"data.null_data_source.foo":    {tfjson.ActionRead},
+	})
+
+	AssertPlanCreatesOnly(t, plan)
+	RequireNoDestroys(t, plan)
+	AssertResourceChange(t, plan, "module.bar.null_resource.baz", tfjson.Actions{tfjson.ActionCreate})
+	RequireResourceChange(t, plan, "null_resource.unchanged", tfjson.Actions{tfjson.ActionNoop})
+	assert.Equal(t, []string{"module.bar.null_resource.baz", "null_resource.foo"}, GetResourceAddressesForAction(plan, tfjson.ActionCreate))
+}
+
+func TestPlanAssertionsOnDestructivePlan(t *testing.T) {
+	t.Parallel()
+
+	plan := newTestPlanStruct(map[string]tfjson.Actions{
+		"null_resource.created":  {tfjson.ActionCreate},
+		"null_resource.updated":  {tfjson.ActionUpdate},
+		"null_resource.replaced": {tfjson.ActionDelete, tfjson.ActionCreate},
+		"null_resource.deleted":  {tfjson.ActionDelete},
+	})
+
+	assert.Equal(t, []string{"null_resource.deleted", "null_resource.replaced", "null_resource.updated"}, getResourceAddressesForActions(plan, isNotCreateOnly))
+	assert.Equal(t, []string{"null_resource.deleted", "null_resource.replaced"}, getResourceAddressesForActions(plan, isDestroy))
+	assert.Equal(t, []string{"null_resource.created", "null_resource.replaced"}, GetResourceAddressesForAction(plan, tfjson.ActionCreate))
+
+	AssertResourceChange(t, plan, "null_resource.replaced", tfjson.Actions{tfjson.ActionDelete, tfjson.ActionCreate})
+
+	actions, hasChange := getResourceChangeActions(plan, "null_resource.missing")
+	assert.False(t, hasChange)
+	assert.Nil(t, actions)
+}
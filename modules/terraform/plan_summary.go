@@ -0,0 +1,109 @@
+package terraform
+
+import (
+	"os"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/stretchr/testify/require"
+)
+
+// PlanActionCounts are the numbers of resources that a plan adds, changes, destroys, imports and forgets, counted the
+// same way as the "Plan: ..." line of terraform plan (i.e. a replaced resource is counted as both added and destroyed).
+type PlanActionCounts struct {
+	Add     int
+	Change  int
+	Destroy int
+	Import  int
+	Forget  int
+	Total   int // The number of resources that the plan adds, changes, destroys, imports or forgets
+}
+
+// PlanSummary summarizes the resource changes of a plan, in total and per resource type (e.g. aws_instance). Resources
+// that are only read (data sources) or that have no changes are not counted.
+type PlanSummary struct {
+	PlanActionCounts
+	ResourceTypes map[string]PlanActionCounts
+}
+
+// GetPlanSummary runs terraform plan with the given options and returns the numbers of resources that it adds, changes,
+// destroys, imports and forgets, in total and per resource type. This will fail the test if there is an error in the
+// command.
+func GetPlanSummary(t testing.TestingT, options *Options) *PlanSummary {
+	summary, err := GetPlanSummaryE(t, options)
+	require.NoError(t, err)
+	return summary
+}
+
+// GetPlanSummaryE runs terraform plan with the given options and returns the numbers of resources that it adds,
+// changes, destroys, imports and forgets, in total and per resource type.
+func GetPlanSummaryE(t testing.TestingT, options *Options) (*PlanSummary, error) {
+	planFile, err := os.CreateTemp("", "terratest-summary-plan-")
+	if err != nil {
+		return nil, err
+	}
+	planFile.Close()
+	defer os.Remove(planFile.Name())
+
+	planOptions, err := options.Clone()
+	if err != nil {
+		return nil, err
+	}
+	planOptions.PlanFilePath = planFile.Name()
+
+	if _, err := PlanE(t, planOptions); err != nil {
+		return nil, err
+	}
+	plan, err := ShowWithStructE(t, planOptions)
+	if err != nil {
+		return nil, err
+	}
+	return GetPlanSummaryFromStruct(plan), nil
+}
+
+// GetPlanSummaryFromStruct returns the numbers of resources that the given plan adds, changes, destroys, imports and
+// forgets, in total and per resource type.
+func GetPlanSummaryFromStruct(plan *PlanStruct) *PlanSummary {
+	summary := &PlanSummary{ResourceTypes: map[string]PlanActionCounts{}}
+	for _, resourceChange := range plan.ResourceChangesMap {
+		if resourceChange == nil || resourceChange.Change == nil || resourceChange.Mode == tfjson.DataResourceMode {
+			continue
+		}
+
+		counts := summary.ResourceTypes[resourceChange.Type]
+		if !counts.add(resourceChange.Change) {
+			continue
+		}
+		summary.ResourceTypes[resourceChange.Type] = counts
+		summary.add(resourceChange.Change)
+	}
+	return summary
+}
+
+// add counts the given change and returns whether it changes the resource at all.
+func (counts *PlanActionCounts) add(change *tfjson.Change) bool {
+	actions := change.Actions
+	imported := change.Importing != nil
+
+	switch {
+	case actions.Create():
+		counts.Add++
+	case actions.Update():
+		counts.Change++
+	case actions.Delete():
+		counts.Destroy++
+	case actions.Replace():
+		counts.Add++
+		counts.Destroy++
+	case actions.Forget():
+		counts.Forget++
+	case !imported:
+		return false
+	}
+
+	if imported {
+		counts.Import++
+	}
+	counts.Total++
+	return true
+}
@@ -0,0 +1,62 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPlanSummaryJSON = `{
+  "format_version": "1.2",
+  "resource_changes": [
+    {"address": "aws_instance.new", "mode": "managed", "type": "aws_instance", "change": {"actions": ["create"]}},
+    {"address": "aws_instance.old", "mode": "managed", "type": "aws_instance", "change": {"actions": ["delete"]}},
+    {"address": "aws_instance.replaced", "mode": "managed", "type": "aws_instance", "change": {"actions": ["create", "delete"]}},
+    {"address": "aws_s3_bucket.updated", "mode": "managed", "type": "aws_s3_bucket", "change": {"actions": ["update"]}},
+    {"address": "aws_s3_bucket.imported", "mode": "managed", "type": "aws_s3_bucket", "change": {"actions": ["no-op"], "importing": {"id": "bucket"}}},
+    {"address": "aws_s3_bucket.unchanged", "mode": "managed", "type": "aws_s3_bucket", "change": {"actions": ["no-op"]}},
+    {"address": "null_resource.forgotten", "mode": "managed", "type": "null_resource", "change": {"actions": ["forget"]}},
+    {"address": "data.aws_ami.ubuntu", "mode": "data", "type": "aws_ami", "change": {"actions": ["read"]}}
+  ]
+}`
+
+func TestGetPlanSummaryFromStruct(t *testing.T) {
+	t.Parallel()
+
+	plan, err := ParsePlanJSON(testPlanSummaryJSON)
+	require.NoError(t, err)
+
+	summary := GetPlanSummaryFromStruct(plan)
+	assert.Equal(t, PlanActionCounts{Add: 2, Change: 1, Destroy: 2, Import: 1, Forget: 1, Total: 6}, summary.PlanActionCounts)
+	assert.Equal(t, map[string]PlanActionCounts{
+		"aws_instance":  {Add: 2, Destroy: 2, Total: 3},
+		"aws_s3_bucket": {Change: 1, Import: 1, Total: 2},
+		"null_resource": {Forget: 1, Total: 1},
+	}, summary.ResourceTypes)
+}
+
+func TestGetPlanSummaryE(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "plan.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(testPlanSummaryJSON), 0644))
+	stubPath := filepath.Join(dir, "terraform-stub")
+	script := fmt.Sprintf(`#!/bin/sh
+if [ "$1" = "show" ]; then
+  cat %q
+fi
+`, jsonPath)
+	require.NoError(t, os.WriteFile(stubPath, []byte(script), 0755))
+
+	options := &Options{TerraformDir: t.TempDir(), TerraformBinary: stubPath}
+	summary, err := GetPlanSummaryE(t, options)
+	require.NoError(t, err)
+	assert.Equal(t, 6, summary.Total)
+	assert.Equal(t, 1, summary.ResourceTypes["aws_s3_bucket"].Import)
+	assert.Empty(t, options.PlanFilePath)
+}
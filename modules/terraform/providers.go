@@ -0,0 +1,206 @@
+package terraform
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// providerMirrorDirName is the name of the directory in the temp dir of the OS that SetupProviderMirror mirrors the
+// providers to, so that the mirror is shared by all the tests (and test packages) of a test run.
+const providerMirrorDirName = "terratest-provider-mirror"
+
+var (
+	// mirroredProviders are the providers that SetupProviderMirror already mirrored in this process.
+	mirroredProviders      = map[string]bool{}
+	mirroredProvidersMutex sync.Mutex
+)
+
+// SetupProviderMirror downloads the given providers to a filesystem mirror shared by all the tests of the test run,
+// and returns the path of the mirror, to use as ProviderMirrorDir in the options. Each provider is the source address
+// of the provider, optionally followed by @ and a version constraint (e.g. hashicorp/aws or hashicorp/aws@~> 5.0).
+// Providers are only mirrored once per test run, so this can be called from every test. This will fail the test if
+// the providers can't be mirrored.
+func SetupProviderMirror(t testing.TestingT, providers ...string) string {
+	mirrorDir, err := SetupProviderMirrorE(t, providers...)
+	require.NoError(t, err)
+	return mirrorDir
+}
+
+// SetupProviderMirrorE downloads the given providers to a filesystem mirror shared by all the tests of the test run,
+// and returns the path of the mirror, to use as ProviderMirrorDir in the options. Each provider is the source address
+// of the provider, optionally followed by @ and a version constraint (e.g. hashicorp/aws or hashicorp/aws@~> 5.0).
+// Providers are only mirrored once per test run, so this can be called from every test.
+func SetupProviderMirrorE(t testing.TestingT, providers ...string) (string, error) {
+	mirrorDir := filepath.Join(os.TempDir(), providerMirrorDirName)
+
+	mirroredProvidersMutex.Lock()
+	defer mirroredProvidersMutex.Unlock()
+
+	missingProviders := []string{}
+	for _, provider := range providers {
+		if !mirroredProviders[provider] {
+			missingProviders = append(missingProviders, provider)
+		}
+	}
+	if len(missingProviders) == 0 {
+		return mirrorDir, nil
+	}
+
+	configDir, err := os.MkdirTemp("", "terratest-provider-mirror-config-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(configDir)
+
+	config, err := formatRequiredProviders(missingProviders)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "main.tf"), []byte(config), 0644); err != nil {
+		return "", err
+	}
+
+	// Other test packages of the test run, which run in separate processes, may read or mirror to the mirror at the
+	// same time, so mirror to a new dir first and then move the provider packages over, as renames are atomic
+	downloadDir := filepath.Join(configDir, "mirror")
+	if _, err := RunTerraformCommandE(t, &Options{TerraformDir: configDir}, "providers", "mirror", downloadDir); err != nil {
+		return "", err
+	}
+	if err := moveProviderPackages(downloadDir, mirrorDir); err != nil {
+		return "", err
+	}
+
+	for _, provider := range missingProviders {
+		mirroredProviders[provider] = true
+	}
+	return mirrorDir, nil
+}
+
+// moveProviderPackages moves the provider packages and other files in the given download dir to the same paths in the
+// given filesystem mirror. Each file is renamed into place, so that other processes never see a partially written
+// package. The download dir must be on the same filesystem as the mirror, such as in the temp dir of the OS.
+func moveProviderPackages(downloadDir string, mirrorDir string) error {
+	return filepath.WalkDir(downloadDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		relPath, err := filepath.Rel(downloadDir, path)
+		if err != nil {
+			return err
+		}
+		mirrorPath := filepath.Join(mirrorDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(mirrorPath), 0755); err != nil {
+			return err
+		}
+		return os.Rename(path, mirrorPath)
+	})
+}
+
+// formatRequiredProviders returns a Terraform configuration that requires the given providers, in the format of
+// SetupProviderMirror.
+func formatRequiredProviders(providers []string) (string, error) {
+	var builder strings.Builder
+	builder.WriteString("terraform {\n  required_providers {\n")
+	for i, provider := range providers {
+		source, version, hasVersion := strings.Cut(provider, "@")
+		if strings.TrimSpace(source) == "" {
+			return "", fmt.Errorf("invalid provider %q: expected a source address such as hashicorp/aws", provider)
+		}
+
+		fmt.Fprintf(&builder, "    provider%d = {\n      source = ", i)
+		writeHclString(&builder, strings.TrimSpace(source))
+		if hasVersion {
+			builder.WriteString("\n      version = ")
+			writeHclString(&builder, strings.TrimSpace(version))
+		}
+		builder.WriteString("\n    }\n")
+	}
+	builder.WriteString("  }\n}\n")
+	return builder.String(), nil
+}
+
+// writeProviderMirrorConfig writes a Terraform CLI configuration file that installs the providers in the given
+// filesystem mirror from the mirror, and all the other providers from their registries, and returns its path.
+func writeProviderMirrorConfig(mirrorDir string) (string, error) {
+	config, err := formatProviderMirrorConfig(mirrorDir)
+	if err != nil {
+		return "", err
+	}
+
+	configFile, err := os.CreateTemp("", "*-terratest.tfrc")
+	if err != nil {
+		return "", err
+	}
+	defer configFile.Close()
+
+	if _, err := configFile.WriteString(config); err != nil {
+		os.Remove(configFile.Name())
+		return "", err
+	}
+	return configFile.Name(), nil
+}
+
+// formatProviderMirrorConfig returns the provider_installation block of a Terraform CLI configuration that installs
+// the providers in the given filesystem mirror from the mirror, and all the other providers from their registries.
+func formatProviderMirrorConfig(mirrorDir string) (string, error) {
+	providers, err := getMirroredProviders(mirrorDir)
+	if err != nil {
+		return "", err
+	}
+	if len(providers) == 0 {
+		return "provider_installation {\n  direct {}\n}\n", nil
+	}
+
+	var builder strings.Builder
+	builder.WriteString("provider_installation {\n  filesystem_mirror {\n    path    = ")
+	writeHclString(&builder, getAbsPath(mirrorDir))
+	builder.WriteString("\n    include = ")
+	if err := writeHclValue(&builder, providers, "    ", false); err != nil {
+		return "", err
+	}
+	builder.WriteString("\n  }\n  direct {\n    exclude = ")
+	if err := writeHclValue(&builder, providers, "    ", false); err != nil {
+		return "", err
+	}
+	builder.WriteString("\n  }\n}\n")
+	return builder.String(), nil
+}
+
+// getMirroredProviders returns the sorted source addresses (e.g. registry.terraform.io/hashicorp/aws) of the providers
+// in the given filesystem mirror, which stores them as HOSTNAME/NAMESPACE/TYPE/...
+func getMirroredProviders(mirrorDir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(mirrorDir, "*", "*", "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	providers := []string{}
+	for _, path := range paths {
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			continue
+		}
+		relPath, err := filepath.Rel(mirrorDir, path)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, filepath.ToSlash(relPath))
+	}
+	sort.Strings(providers)
+	return providers, nil
+}
+
+// getAbsPath returns the absolute version of the given path, or the path itself if it can't be made absolute.
+func getAbsPath(path string) string {
+	if absPath, err := filepath.Abs(path); err == nil {
+		return absPath
+	}
+	return path
+}
@@ -0,0 +1,102 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatRequiredProviders(t *testing.T) {
+	t.Parallel()
+
+	config, err := formatRequiredProviders([]string{"hashicorp/null", "hashicorp/aws@~> 5.0"})
+	require.NoError(t, err)
+	assert.Equal(t, `terraform {
+  required_providers {
+    provider0 = {
+      source = "hashicorp/null"
+    }
+    provider1 = {
+      source = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+`, config)
+
+	_, err = formatRequiredProviders([]string{"@1.0.0"})
+	assert.Error(t, err)
+}
+
+func TestMoveProviderPackages(t *testing.T) {
+	t.Parallel()
+
+	downloadDir := t.TempDir()
+	mirrorDir := filepath.Join(t.TempDir(), "mirror")
+	providerDir := filepath.Join("registry.terraform.io", "hashicorp", "null")
+	require.NoError(t, os.MkdirAll(filepath.Join(downloadDir, providerDir), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(downloadDir, providerDir, "terraform-provider-null_3.2.2_linux_amd64.zip"), []byte("new"), 0644))
+
+	// Packages already in the mirror, such as the ones mirrored by other test packages, are kept
+	require.NoError(t, os.MkdirAll(filepath.Join(mirrorDir, providerDir), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(mirrorDir, providerDir, "terraform-provider-null_3.2.1_linux_amd64.zip"), []byte("old"), 0644))
+
+	require.NoError(t, moveProviderPackages(downloadDir, mirrorDir))
+
+	contents, err := os.ReadFile(filepath.Join(mirrorDir, providerDir, "terraform-provider-null_3.2.2_linux_amd64.zip"))
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(contents))
+	assert.FileExists(t, filepath.Join(mirrorDir, providerDir, "terraform-provider-null_3.2.1_linux_amd64.zip"))
+	assert.NoFileExists(t, filepath.Join(downloadDir, providerDir, "terraform-provider-null_3.2.2_linux_amd64.zip"))
+}
+
+func TestFormatProviderMirrorConfig(t *testing.T) {
+	t.Parallel()
+
+	mirrorDir := t.TempDir()
+	for _, provider := range []string{"registry.terraform.io/hashicorp/null", "registry.terraform.io/hashicorp/aws"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(mirrorDir, filepath.FromSlash(provider)), 0755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(mirrorDir, "registry.terraform.io", "hashicorp", "index.json"), []byte("{}"), 0644))
+
+	providers, err := getMirroredProviders(mirrorDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"registry.terraform.io/hashicorp/aws", "registry.terraform.io/hashicorp/null"}, providers)
+
+	config, err := formatProviderMirrorConfig(mirrorDir)
+	require.NoError(t, err)
+	assert.Contains(t, config, `"registry.terraform.io/hashicorp/aws",`)
+
+	file, diagnostics := hclparse.NewParser().ParseHCL([]byte(config), "terratest.tfrc")
+	require.False(t, diagnostics.HasErrors(), diagnostics.Error())
+	require.NotNil(t, file)
+
+	emptyConfig, err := formatProviderMirrorConfig(t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, "provider_installation {\n  direct {}\n}\n", emptyConfig)
+}
+
+func TestGetProviderInstallationOptions(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		ProviderCacheDir:  filepath.Join(t.TempDir(), "cache"),
+		ProviderMirrorDir: t.TempDir(),
+		EnvVars:           map[string]string{"FOO": "bar"},
+	}
+
+	initOptions, cleanup, err := getProviderInstallationOptions(options)
+	require.NoError(t, err)
+	assert.DirExists(t, options.ProviderCacheDir)
+
+	configFile := initOptions.EnvVars["TF_CLI_CONFIG_FILE"]
+	assert.FileExists(t, configFile)
+	assert.Equal(t, map[string]string{"FOO": "bar"}, options.EnvVars)
+
+	cleanup()
+	assert.NoFileExists(t, configFile)
+}
@@ -0,0 +1,80 @@
+package terraform
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// LatestBuiltinRetryRulesVersion is the latest version of the built-in retry rules. A new version is added whenever
+// rules are added, changed or removed, and the previous versions are kept, so that tests can pin the rules they use.
+const LatestBuiltinRetryRulesVersion = "1"
+
+// builtinRetryRules are the built-in retry rules, by version.
+var builtinRetryRules = map[string][]RetryRule{
+	"1": {
+		// Errors that never self resolve, such as invalid credentials or configuration errors. These take precedence
+		// over the retryable rules, so that e.g. an expired token is not retried because of a mention of a timeout in
+		// the same output.
+		{Name: "invalid-credentials", Pattern: regexp.MustCompile(`(?i)(no valid credential sources found|InvalidClientTokenId|ExpiredToken|SignatureDoesNotMatch|invalid_grant)`), Class: ErrorFatal, Message: "The credentials are invalid or expired."},
+		{Name: "invalid-configuration", Pattern: regexp.MustCompile(`Error: (Unsupported argument|Missing required argument|Invalid reference|Reference to undeclared|Unsupported attribute|Invalid value for variable|Invalid function argument|Incorrect attribute value type)`), Class: ErrorFatal, Message: "The Terraform configuration is invalid."},
+
+		// Rate limits of the cloud APIs, which self resolve once the requests slow down.
+		{Name: "aws-throttling", Pattern: regexp.MustCompile(`(Throttling|ThrottlingException|RequestLimitExceeded|TooManyRequestsException|Rate exceeded|SlowDown)`), Class: ErrorRetryable, Message: "Rate limited by the AWS API."},
+		{Name: "gcp-throttling", Pattern: regexp.MustCompile(`(rateLimitExceeded|userRateLimitExceeded|RESOURCE_EXHAUSTED|Quota exceeded for quota metric)`), Class: ErrorRetryable, Message: "Rate limited by the Google Cloud API."},
+		{Name: "azure-throttling", Pattern: regexp.MustCompile(`(TooManyRequests|Status=429)`), Class: ErrorRetryable, Message: "Rate limited by the Azure API."},
+		{Name: "http-throttling", Pattern: regexp.MustCompile(`(429 Too Many Requests|status code:? 429)`), Class: ErrorRetryable, Message: "Rate limited by an API."},
+
+		// Eventual consistency of the cloud APIs, where a resource (or a permission granted by IAM) is not visible (or
+		// not usable) right after it is created, or not propagated after apply. A resource that still has dependents is
+		// only retried on destroy, as on apply it points to a permanent problem with the configuration.
+		{Name: "inconsistent-result", Pattern: regexp.MustCompile(`Provider produced inconsistent result after apply`), Class: ErrorRetryable, Message: "Provider eventual consistency error."},
+		{Name: "aws-iam-propagation", Pattern: regexp.MustCompile(`(The role defined for the function cannot be assumed by Lambda|InvalidInstanceProfile|Invalid IAM Instance Profile|is not authorized to perform: iam:PassRole)`), Class: ErrorRetryable, Message: "IAM changes are still propagating."},
+		{Name: "access-denied", Pattern: regexp.MustCompile(`(AccessDenied|UnauthorizedOperation|AuthorizationFailed|Error 403: .* does not have .* permission)`), Class: ErrorRetryable, Message: "The credentials lack the required permissions, possibly because IAM changes are still propagating."},
+		{Name: "aws-not-found-after-create", Pattern: regexp.MustCompile(`(InvalidGroup\.NotFound|InvalidSubnetID\.NotFound|InvalidVpcID\.NotFound|InvalidRouteTableID\.NotFound|NoSuchBucket)`), Class: ErrorRetryable, Message: "A resource that was just created is not visible yet."},
+		{Name: "dependency-violation", Pattern: regexp.MustCompile(`(DependencyViolation|is currently in use|ResourceInUseException|BucketNotEmpty)`), Class: ErrorRetryable, Message: "Resource still has dependents that are being deleted.", Commands: []string{"destroy"}},
+
+		// Crashes and timeouts of the provider plugins, and network errors while downloading them.
+		{Name: "plugin-crash", Pattern: regexp.MustCompile(`(Plugin did not respond|The plugin encountered an error, and failed to respond|plugin exited before we could connect|rpc error: code = Unavailable|transport is closing)`), Class: ErrorRetryable, Message: "The provider plugin crashed."},
+		{Name: "plugin-start-timeout", Pattern: regexp.MustCompile(`(timeout while waiting for plugin to start|timed out waiting for server handshake)`), Class: ErrorRetryable, Message: "The provider plugin failed to start in time."},
+		{Name: "plugin-download", Pattern: regexp.MustCompile(`(unable to verify signature|unable to verify checksum|no provider exists with the given name|registry service is unreachable|Error installing provider|Failed to query available provider packages|could not query provider registry for|Failed to install provider|Could not retrieve the list of available versions for provider)`), Class: ErrorRetryable, Message: "Failed to retrieve plugin due to transient network error."},
+		{Name: "network", Pattern: regexp.MustCompile(`(connection reset by peer|i/o timeout|TLS handshake timeout|no such host|net/http: request canceled)`), Class: ErrorRetryable, Message: "Transient network error."},
+	},
+}
+
+// GetBuiltinRetryRules returns the built-in retry rules of the given version (e.g. LatestBuiltinRetryRulesVersion),
+// which classify the common transient errors (provider throttling, eventual consistency and plugin crashes) as
+// retryable, and the common permanent errors (invalid credentials and configuration errors) as fatal. This will fail
+// the test if there is no such version.
+func GetBuiltinRetryRules(t testing.TestingT, version string) []RetryRule {
+	rules, err := GetBuiltinRetryRulesE(version)
+	require.NoError(t, err)
+	return rules
+}
+
+// GetBuiltinRetryRulesE returns the built-in retry rules of the given version (e.g. LatestBuiltinRetryRulesVersion),
+// which classify the common transient errors (provider throttling, eventual consistency and plugin crashes) as
+// retryable, and the common permanent errors (invalid credentials and configuration errors) as fatal.
+func GetBuiltinRetryRulesE(version string) ([]RetryRule, error) {
+	rules, exists := builtinRetryRules[version]
+	if !exists {
+		return nil, UnknownRetryRulesVersion(version)
+	}
+	return append([]RetryRule{}, rules...), nil
+}
+
+// WithBuiltinRetryRules makes a copy of the Options object and returns an updated object that classifies the errors
+// of Terraform commands with the latest built-in retry rules (see GetBuiltinRetryRules), instead of the
+// RetryableTerraformErrors, and retries the retryable ones with the same defaults as WithDefaultRetryableErrors.
+// This will fail the test if there are any errors in the cloning process.
+func WithBuiltinRetryRules(t testing.TestingT, originalOptions *Options) *Options {
+	newOptions, err := originalOptions.Clone()
+	require.NoError(t, err)
+
+	newOptions.ErrorClassifier = &RuleClassifier{Rules: GetBuiltinRetryRules(t, LatestBuiltinRetryRulesVersion)}
+	newOptions.MaxRetries = 3
+	newOptions.TimeBetweenRetries = 5 * time.Second
+	return newOptions
+}
@@ -0,0 +1,61 @@
+package terraform
+
+import (
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// ValidateFunc validates the infrastructure deployed with the given options, e.g. by calling its endpoints, and returns
+// an error if it's not working as expected.
+type ValidateFunc func(t testing.TestingT, options *Options) error
+
+// ApplyAndValidate runs terraform init and apply with the given options, and then the given validation function. If
+// the apply or the validation fails, this saves the output, plan and state to options.FailureArtifactsDir (or a temp
+// dir) for debugging and, if options.RollbackVars is set, re-applies with those known-good vars before failing the
+// test. This is useful for blue/green tests, which must leave the previous version running when a new one is broken.
+// Note that this method does NOT call destroy and assumes the caller is responsible for cleaning up any resources
+// created by running apply.
+func ApplyAndValidate(t testing.TestingT, options *Options, validate ValidateFunc) {
+	require.NoError(t, ApplyAndValidateE(t, options, validate))
+}
+
+// ApplyAndValidateE runs terraform init and apply with the given options, and then the given validation function. If
+// the apply or the validation fails, this saves the output, plan and state to options.FailureArtifactsDir (or a temp
+// dir) for debugging and, if options.RollbackVars is set, re-applies with those known-good vars, and returns an
+// ApplyValidationFailed error. Note that this method does NOT call destroy and assumes the caller is responsible for
+// cleaning up any resources created by running apply.
+func ApplyAndValidateE(t testing.TestingT, options *Options, validate ValidateFunc) error {
+	var validationErr error
+	out, err := initAndApplyE(t, options)
+	if err != nil {
+		validationErr = err
+	} else {
+		validationErr = validate(t, options)
+	}
+	if validationErr == nil {
+		return nil
+	}
+
+	options.Logger.Logf(t, "Apply of %s failed validation: %v", options.TerraformDir, validationErr)
+	failure := ApplyValidationFailed{Underlying: validationErr}
+	failure.ArtifactsDir = saveAndLogFailureArtifacts(t, options, out)
+
+	if options.RollbackVars != nil {
+		failure.RollbackErr = rollbackE(t, options)
+		failure.RolledBack = failure.RollbackErr == nil
+	}
+	return failure
+}
+
+// rollbackE re-applies the given options with their RollbackVars instead of their Vars.
+func rollbackE(t testing.TestingT, options *Options) error {
+	rollbackOptions, err := options.Clone()
+	if err != nil {
+		return err
+	}
+	rollbackOptions.Vars = options.RollbackVars
+
+	options.Logger.Logf(t, "Rolling back %s to the known-good vars", options.TerraformDir)
+	_, err = ApplyE(t, rollbackOptions)
+	return err
+}
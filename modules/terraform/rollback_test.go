@@ -0,0 +1,92 @@
+package terraform
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	terratesting "github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeRollbackStub writes a script that can be used as TerraformBinary, which records the args of every command to
+// the returned log file and prints fake state and plan output for show and plan.
+func writeRollbackStub(t *testing.T) (string, string) {
+	dir := t.TempDir()
+	stubPath := filepath.Join(dir, "terraform-stub")
+	logPath := filepath.Join(dir, "commands.log")
+	script := fmt.Sprintf(`#!/bin/sh
+echo "$@" >> %q
+if [ "$1" = "show" ]; then
+  echo '{"format_version": "1.0"}'
+fi
+if [ "$1" = "plan" ]; then
+  echo "Plan: 0 to add, 1 to change, 0 to destroy."
+fi
+`, logPath)
+	require.NoError(t, os.WriteFile(stubPath, []byte(script), 0755))
+	return stubPath, logPath
+}
+
+func TestApplyAndValidateSucceeds(t *testing.T) {
+	t.Parallel()
+
+	stubPath, logPath := writeRollbackStub(t)
+	options := &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: stubPath,
+		RollbackVars:    map[string]interface{}{"version": "blue"},
+	}
+
+	validated := false
+	ApplyAndValidate(t, options, func(t terratesting.TestingT, options *Options) error {
+		validated = true
+		return nil
+	})
+	assert.True(t, validated)
+
+	commands, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(commands), "\n"))
+}
+
+func TestApplyAndValidateERollsBackOnValidationFailure(t *testing.T) {
+	t.Parallel()
+
+	stubPath, logPath := writeRollbackStub(t)
+	artifactsDir := filepath.Join(t.TempDir(), "artifacts")
+	options := &Options{
+		TerraformDir:        t.TempDir(),
+		TerraformBinary:     stubPath,
+		Vars:                map[string]interface{}{"version": "green"},
+		RollbackVars:        map[string]interface{}{"version": "blue"},
+		FailureArtifactsDir: artifactsDir,
+	}
+
+	validationErr := errors.New("green is not healthy")
+	err := ApplyAndValidateE(t, options, func(t terratesting.TestingT, options *Options) error {
+		return validationErr
+	})
+
+	var failure ApplyValidationFailed
+	require.ErrorAs(t, err, &failure)
+	assert.ErrorIs(t, err, validationErr)
+	assert.Equal(t, artifactsDir, failure.ArtifactsDir)
+	assert.True(t, failure.RolledBack)
+
+	assert.FileExists(t, filepath.Join(artifactsDir, "state.json"))
+	plan, err := os.ReadFile(filepath.Join(artifactsDir, "plan.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(plan), "1 to change")
+
+	commands, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(commands)), "\n")
+	assert.Contains(t, lines[len(lines)-1], "apply")
+	assert.Contains(t, lines[len(lines)-1], "version=blue")
+	assert.Equal(t, map[string]interface{}{"version": "green"}, options.Vars)
+}
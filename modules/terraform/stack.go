@@ -0,0 +1,252 @@
+package terraform
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// Stack is a set of Terraform modules that depend on each other, such as a network module and the app modules that
+// are deployed in that network. ApplyStack applies the modules concurrently, each module as soon as the modules it
+// depends on are applied, and passes the outputs of the modules as variables to the modules that depend on them.
+// DestroyStack destroys them in the reverse order.
+type Stack struct {
+	Parallelism int // The max number of modules to apply or destroy at the same time. 0 means no limit.
+
+	modules map[string]*StackModule
+	names   []string // The names of the modules, in the order they were added
+}
+
+// StackModule is a module of a Stack.
+type StackModule struct {
+	Name      string                 // The name of the module in the stack
+	Options   *Options               // The options to apply and destroy the module with. The vars wired to the outputs of other modules are set in its Vars.
+	DependsOn []string               // The names of the modules that must be applied before this module, and destroyed after it
+	Outputs   map[string]interface{} // The outputs of the module, set once it is applied
+
+	inputs map[string]stackInput
+}
+
+// stackInput is an output of a module of a stack that is passed to a variable of another module.
+type stackInput struct {
+	module string
+	output string
+}
+
+// NewStack returns an empty Stack.
+func NewStack() *Stack {
+	return &Stack{modules: map[string]*StackModule{}}
+}
+
+// AddModule adds a module with the given name (which must be unique in the stack) and options to the stack, which must
+// be applied after the given modules, and returns it, e.g. to wire its variables to the outputs of other modules.
+func (stack *Stack) AddModule(name string, options *Options, dependsOn ...string) *StackModule {
+	if _, exists := stack.modules[name]; !exists {
+		stack.names = append(stack.names, name)
+	}
+
+	module := &StackModule{
+		Name:      name,
+		Options:   options,
+		DependsOn: dependsOn,
+		inputs:    map[string]stackInput{},
+	}
+	stack.modules[name] = module
+	return module
+}
+
+// GetModule returns the module of the stack with the given name, or nil if there is none.
+func (stack *Stack) GetModule(name string) *StackModule {
+	return stack.modules[name]
+}
+
+// SetVarFromOutput sets the given variable of the module to the value of the given output of another module of the
+// stack once that module is applied, which makes the module depend on the other module. Returns the module to allow
+// chaining calls.
+func (module *StackModule) SetVarFromOutput(variable string, fromModule string, output string) *StackModule {
+	module.inputs[variable] = stackInput{module: fromModule, output: output}
+	for _, dependency := range module.DependsOn {
+		if dependency == fromModule {
+			return module
+		}
+	}
+	module.DependsOn = append(module.DependsOn, fromModule)
+	return module
+}
+
+// ApplyStack runs terraform init and apply in all the modules of the stack, concurrently, applying each module once
+// the modules it depends on are applied. Fail the test if any module fails to apply.
+func ApplyStack(t testing.TestingT, stack *Stack) {
+	require.NoError(t, ApplyStackE(t, stack))
+}
+
+// ApplyStackE runs terraform init and apply in all the modules of the stack, concurrently, applying each module once
+// the modules it depends on are applied. The outputs of each module are read after it is applied, and passed to the
+// vars wired to them with SetVarFromOutput. If a module fails to apply, the modules that depend on it are not applied,
+// but the others are, and the returned error contains the error of every module that was not applied.
+func ApplyStackE(t testing.TestingT, stack *Stack) error {
+	return stack.runE(t, false, func(module *StackModule) error {
+		if err := stack.setInputVars(module); err != nil {
+			return err
+		}
+		if _, err := InitAndApplyE(t, module.Options); err != nil {
+			return err
+		}
+
+		outputs, err := OutputAllE(t, module.Options)
+		if err != nil {
+			return err
+		}
+		module.Outputs = outputs
+		return nil
+	})
+}
+
+// DestroyStack runs terraform destroy in all the modules of the stack, concurrently, destroying each module once the
+// modules that depend on it are destroyed. Fail the test if any module fails to be destroyed.
+func DestroyStack(t testing.TestingT, stack *Stack) {
+	require.NoError(t, DestroyStackE(t, stack))
+}
+
+// DestroyStackE runs terraform destroy in all the modules of the stack, concurrently, destroying each module once the
+// modules that depend on it are destroyed. If a module fails to be destroyed, the modules it depends on are not
+// destroyed, but the others are, and the returned error contains the error of every module that was not destroyed.
+func DestroyStackE(t testing.TestingT, stack *Stack) error {
+	return stack.runE(t, true, func(module *StackModule) error {
+		_, err := DestroyE(t, module.Options)
+		return err
+	})
+}
+
+// setInputVars sets the vars of the module that are wired to the outputs of other modules.
+func (stack *Stack) setInputVars(module *StackModule) error {
+	if len(module.inputs) > 0 && module.Options.Vars == nil {
+		module.Options.Vars = map[string]interface{}{}
+	}
+	for variable, input := range module.inputs {
+		value, exists := stack.modules[input.module].Outputs[input.output]
+		if !exists {
+			return OutputKeyNotFound(input.output)
+		}
+		module.Options.Vars[variable] = value
+	}
+	return nil
+}
+
+// runE runs the given action on every module of the stack, concurrently, but only once the action succeeded on the
+// dependencies of the module, or on the modules that depend on it if reverse is true.
+func (stack *Stack) runE(t testing.TestingT, reverse bool, action func(module *StackModule) error) error {
+	order, err := stack.getTopologicalOrder()
+	if err != nil {
+		return err
+	}
+
+	prerequisites := map[string][]string{}
+	for _, name := range order {
+		for _, dependency := range stack.modules[name].DependsOn {
+			if reverse {
+				prerequisites[dependency] = append(prerequisites[dependency], name)
+			} else {
+				prerequisites[name] = append(prerequisites[name], dependency)
+			}
+		}
+	}
+
+	done := map[string]chan struct{}{}
+	for _, name := range order {
+		done[name] = make(chan struct{})
+	}
+	errs := map[string]error{}
+	errsMutex := sync.Mutex{}
+
+	var semaphore chan struct{}
+	if stack.Parallelism > 0 {
+		semaphore = make(chan struct{}, stack.Parallelism)
+	}
+
+	wg := &sync.WaitGroup{}
+	for _, name := range order {
+		wg.Add(1)
+		go func(module *StackModule) {
+			defer wg.Done()
+			defer close(done[module.Name])
+
+			var err error
+			for _, prerequisite := range prerequisites[module.Name] {
+				<-done[prerequisite]
+				errsMutex.Lock()
+				if errs[prerequisite] != nil && err == nil {
+					err = StackModuleSkipped{Module: module.Name, FailedModule: prerequisite}
+				}
+				errsMutex.Unlock()
+			}
+
+			if err == nil {
+				if semaphore != nil {
+					semaphore <- struct{}{}
+					defer func() { <-semaphore }()
+				}
+				module.Options.Logger.Logf(t, "Running module %s of the stack in %s", module.Name, module.Options.TerraformDir)
+				err = action(module)
+			}
+
+			errsMutex.Lock()
+			errs[module.Name] = err
+			errsMutex.Unlock()
+		}(stack.modules[name])
+	}
+	wg.Wait()
+
+	errorsOccurred := new(multierror.Error)
+	for _, name := range order {
+		if err := errs[name]; err != nil {
+			errorsOccurred = multierror.Append(errorsOccurred, fmt.Errorf("module %s: %w", name, err))
+		}
+	}
+	return errorsOccurred.ErrorOrNil()
+}
+
+// getTopologicalOrder returns the names of the modules of the stack, with every module after the modules it depends
+// on, and otherwise in the order they were added.
+func (stack *Stack) getTopologicalOrder() ([]string, error) {
+	order := []string{}
+	visited := map[string]bool{}
+	visiting := map[string]bool{}
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		if visited[name] {
+			return nil
+		}
+		path = append(path, name)
+		if visiting[name] {
+			return StackDependencyCycle(path)
+		}
+		visiting[name] = true
+
+		for _, dependency := range stack.modules[name].DependsOn {
+			if _, exists := stack.modules[dependency]; !exists {
+				return StackModuleNotFound{Module: dependency, DependencyOf: name}
+			}
+			if err := visit(dependency, path); err != nil {
+				return err
+			}
+		}
+
+		visiting[name] = false
+		visited[name] = true
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range stack.names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
@@ -0,0 +1,115 @@
+package terraform
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeStackStub writes a terraform binary stub that records the commands it runs in each module to the given log,
+// fails to apply the modules named fail, and outputs the name of the module as id.
+func writeStackStub(t *testing.T, logPath string) string {
+	stubPath := filepath.Join(t.TempDir(), "terraform-stub")
+	script := fmt.Sprintf(`#!/bin/sh
+module=$(basename "$PWD")
+echo "$1 $module $*" >> %q
+if [ "$1" = "apply" ] && [ "$module" = "fail" ]; then
+  exit 1
+fi
+if [ "$1" = "output" ]; then
+  echo "{\"id\": {\"sensitive\": false, \"type\": \"string\", \"value\": \"$module-id\"}}"
+fi
+`, logPath)
+	require.NoError(t, os.WriteFile(stubPath, []byte(script), 0755))
+	return stubPath
+}
+
+// readStackLog returns the modules in which the stub ran the given command, in order.
+func readStackLog(t *testing.T, logPath string, command string) []string {
+	content, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+
+	modules := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == command {
+			modules = append(modules, fields[1])
+		}
+	}
+	return modules
+}
+
+func newStackModuleOptions(t *testing.T, rootDir string, stubPath string, name string) *Options {
+	dir := filepath.Join(rootDir, name)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	return &Options{TerraformDir: dir, TerraformBinary: stubPath}
+}
+
+func TestApplyAndDestroyStackE(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	logPath := filepath.Join(rootDir, "commands.log")
+	stubPath := writeStackStub(t, logPath)
+
+	stack := NewStack()
+	stack.AddModule("app", newStackModuleOptions(t, rootDir, stubPath, "app")).
+		SetVarFromOutput("network_id", "network", "id").
+		SetVarFromOutput("db_id", "db", "id")
+	stack.AddModule("db", newStackModuleOptions(t, rootDir, stubPath, "db")).
+		SetVarFromOutput("network_id", "network", "id")
+	stack.AddModule("network", newStackModuleOptions(t, rootDir, stubPath, "network"))
+
+	require.NoError(t, ApplyStackE(t, stack))
+	assert.Equal(t, []string{"network", "db", "app"}, readStackLog(t, logPath, "apply"))
+	assert.Equal(t, map[string]interface{}{"network_id": "network-id", "db_id": "db-id"}, stack.GetModule("app").Options.Vars)
+	assert.Equal(t, map[string]interface{}{"id": "db-id"}, stack.GetModule("db").Outputs)
+
+	require.NoError(t, DestroyStackE(t, stack))
+	assert.Equal(t, []string{"app", "db", "network"}, readStackLog(t, logPath, "destroy"))
+	content, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "-var network_id=network-id")
+}
+
+func TestApplyStackESkipsModulesThatDependOnFailedModules(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	logPath := filepath.Join(rootDir, "commands.log")
+	stubPath := writeStackStub(t, logPath)
+
+	stack := NewStack()
+	stack.Parallelism = 1
+	stack.AddModule("fail", newStackModuleOptions(t, rootDir, stubPath, "fail"))
+	stack.AddModule("app", newStackModuleOptions(t, rootDir, stubPath, "app"), "fail")
+	stack.AddModule("other", newStackModuleOptions(t, rootDir, stubPath, "other"))
+
+	err := ApplyStackE(t, stack)
+	require.Error(t, err)
+	assert.ElementsMatch(t, []string{"fail", "other"}, readStackLog(t, logPath, "apply"))
+
+	var skipped StackModuleSkipped
+	require.True(t, errors.As(err, &skipped))
+	assert.Equal(t, StackModuleSkipped{Module: "app", FailedModule: "fail"}, skipped)
+}
+
+func TestApplyStackEValidatesDependencies(t *testing.T) {
+	t.Parallel()
+
+	stack := NewStack()
+	stack.AddModule("a", &Options{}, "b")
+	stack.AddModule("b", &Options{}, "c")
+	stack.AddModule("c", &Options{}, "a")
+	assert.Equal(t, StackDependencyCycle{"a", "b", "c", "a"}, ApplyStackE(t, stack))
+
+	stack = NewStack()
+	stack.AddModule("a", &Options{}, "missing")
+	assert.Equal(t, StackModuleNotFound{Module: "missing", DependencyOf: "a"}, DestroyStackE(t, stack))
+}
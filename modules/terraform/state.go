@@ -0,0 +1,197 @@
+package terraform
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// noStateErrorRegexps match the errors that terraform state list returns when there is no state to read, because the
+// working directory was never initialized or no state was ever written. The wording of these errors changed across
+// Terraform and OpenTofu versions, so they are matched loosely.
+var noStateErrorRegexps = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)no state file was found`),
+	regexp.MustCompile(`(?i)backend (re)?initialization required`),
+}
+
+// noMatchingStateAddressRegexp matches the errors that terraform state mv and terraform state rm return when the given
+// address doesn't match any resource in the state.
+var noMatchingStateAddressRegexp = regexp.MustCompile(`(?i)invalid (source|target) address`)
+
+// StateIsEmpty returns true if the state of the given options has no resources, or if there is no state at all because
+// the working directory was never initialized. This will fail the test if the state can't be read for any other reason.
+func StateIsEmpty(t testing.TestingT, options *Options) bool {
+	isEmpty, err := StateIsEmptyE(t, options)
+	require.NoError(t, err)
+	return isEmpty
+}
+
+// StateIsEmptyE returns true if the state of the given options has no resources, or if there is no state at all because
+// the working directory was never initialized.
+func StateIsEmptyE(t testing.TestingT, options *Options) (bool, error) {
+	out, err := RunTerraformCommandAndGetStdoutE(t, options, "state", "list")
+	if err != nil {
+		if isNoStateError(err) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	return strings.TrimSpace(out) == "", nil
+}
+
+// isNoStateError returns true if the given error of terraform state list means that there is no state to read.
+func isNoStateError(err error) bool {
+	for _, noStateErrorRegexp := range noStateErrorRegexps {
+		if noStateErrorRegexp.MatchString(err.Error()) {
+			return true
+		}
+	}
+	return false
+}
+
+// StateList calls terraform state list with the given options and returns the addresses of the resources in the
+// state. This will fail the test if there is an error in the command.
+func StateList(t testing.TestingT, options *Options) []string {
+	addresses, err := StateListE(t, options)
+	require.NoError(t, err)
+	return addresses
+}
+
+// StateListE calls terraform state list with the given options and returns the addresses of the resources in the
+// state.
+func StateListE(t testing.TestingT, options *Options) ([]string, error) {
+	out, err := RunTerraformCommandAndGetStdoutE(t, options, "state", "list")
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := []string{}
+	for _, line := range strings.Split(out, "\n") {
+		if address := strings.TrimSpace(line); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses, nil
+}
+
+// StateShow returns the resource with the given full address (e.g. module.foo.null_resource.test[0]) in the state of
+// the given options. This will fail the test if the resource is not in the state or there is an error in the command.
+func StateShow(t testing.TestingT, options *Options, address string) *StateResource {
+	resource, err := StateShowE(t, options, address)
+	require.NoError(t, err)
+	return resource
+}
+
+// StateShowE returns the resource with the given full address (e.g. module.foo.null_resource.test[0]) in the state of
+// the given options, or a StateResourceNotFound error if the resource is not in the state.
+func StateShowE(t testing.TestingT, options *Options, address string) (*StateResource, error) {
+	state, err := getStateE(t, options)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, exists := state.ResourcesMap[address]
+	if !exists {
+		return nil, StateResourceNotFound(address)
+	}
+	return resource, nil
+}
+
+// GetState calls terraform show in json mode with the given options and returns the parsed state, including the
+// versions of the providers selected by terraform init. This will fail the test if there is an error in the command.
+func GetState(t testing.TestingT, options *Options) *StateStruct {
+	state, err := GetStateE(t, options)
+	require.NoError(t, err)
+	return state
+}
+
+// GetStateE calls terraform show in json mode with the given options and returns the parsed state, including the
+// versions of the providers selected by terraform init.
+func GetStateE(t testing.TestingT, options *Options) (*StateStruct, error) {
+	state, err := getStateE(t, options)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := RunTerraformCommandAndGetStdoutE(t, options, "version", "-json")
+	if err != nil {
+		return nil, err
+	}
+	var version struct {
+		ProviderSelections map[string]string `json:"provider_selections"`
+	}
+	if err := json.Unmarshal([]byte(out), &version); err != nil {
+		return nil, err
+	}
+	for provider, providerVersion := range version.ProviderSelections {
+		state.ProviderVersions[provider] = providerVersion
+	}
+	return state, nil
+}
+
+// getStateE calls terraform show in json mode with the given options, ignoring PlanFilePath, and returns the parsed
+// state without the provider versions.
+func getStateE(t testing.TestingT, options *Options) (*StateStruct, error) {
+	stateOptions, err := options.Clone()
+	if err != nil {
+		return nil, err
+	}
+	stateOptions.PlanFilePath = ""
+
+	out, err := ShowE(t, stateOptions)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStateJSON(out)
+}
+
+// StateMv calls terraform state mv with the given options to move the resource at the source address to the
+// destination address in the state (e.g. to check a refactoring before writing a moved block), and returns
+// stdout/stderr. This will fail the test if there is an error in the command.
+func StateMv(t testing.TestingT, options *Options, source string, destination string) string {
+	out, err := StateMvE(t, options, source, destination)
+	require.NoError(t, err)
+	return out
+}
+
+// StateMvE calls terraform state mv with the given options to move the resource at the source address to the
+// destination address in the state, and returns stdout/stderr. This returns a StateResourceNotFound error if the source
+// address doesn't match any resource in the state, and otherwise a StateMvFailed error with both addresses if the move
+// fails. Note that when the source address matches several resources (e.g. a module), some of them may have been moved
+// before the failure, so check the state (e.g. with StateList) before retrying.
+func StateMvE(t testing.TestingT, options *Options, source string, destination string) (string, error) {
+	args := append([]string{"state", "mv"}, FormatTerraformLockAsArgs(options.Lock, options.LockTimeout)...)
+	out, err := RunTerraformCommandE(t, options, append(args, source, destination)...)
+	if err != nil && noMatchingStateAddressRegexp.MatchString(out) {
+		return out, StateResourceNotFound(source)
+	}
+	if err != nil {
+		return out, StateMvFailed{Source: source, Destination: destination, Underlying: err}
+	}
+	return out, nil
+}
+
+// StateRm calls terraform state rm with the given options to remove the resources at the given addresses from the
+// state without destroying them, and returns stdout/stderr. This will fail the test if there is an error in the
+// command.
+func StateRm(t testing.TestingT, options *Options, addresses ...string) string {
+	out, err := StateRmE(t, options, addresses...)
+	require.NoError(t, err)
+	return out
+}
+
+// StateRmE calls terraform state rm with the given options to remove the resources at the given addresses from the
+// state without destroying them, and returns stdout/stderr. This returns a StateResourceNotFound error if an address
+// doesn't match any resource in the state.
+func StateRmE(t testing.TestingT, options *Options, addresses ...string) (string, error) {
+	args := append([]string{"state", "rm"}, FormatTerraformLockAsArgs(options.Lock, options.LockTimeout)...)
+	out, err := RunTerraformCommandE(t, options, append(args, addresses...)...)
+	if err != nil && noMatchingStateAddressRegexp.MatchString(out) {
+		return out, StateResourceNotFound(strings.Join(addresses, ", "))
+	}
+	return out, err
+}
@@ -0,0 +1,102 @@
+package terraform
+
+import (
+	"sort"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// AssertResourceInState checks that the state has a resource with the given full address (e.g.
+// module.foo.null_resource.test[0]), failing the test if it does not.
+func AssertResourceInState(t testing.TestingT, state *StateStruct, address string) {
+	_, exists := state.ResourcesMap[address]
+	assert.Truef(t, exists, "Resource %s not found in state. Resources in state: %v", address, getStateAddresses(state))
+}
+
+// RequireResourceInState checks that the state has a resource with the given full address (e.g.
+// module.foo.null_resource.test[0]), failing and halting the test if it does not.
+func RequireResourceInState(t testing.TestingT, state *StateStruct, address string) {
+	_, exists := state.ResourcesMap[address]
+	require.Truef(t, exists, "Resource %s not found in state. Resources in state: %v", address, getStateAddresses(state))
+}
+
+// AssertResourceNotInState checks that the state has no resource with the given full address, failing the test if it
+// does.
+func AssertResourceNotInState(t testing.TestingT, state *StateStruct, address string) {
+	_, exists := state.ResourcesMap[address]
+	assert.Falsef(t, exists, "Resource %s unexpectedly found in state", address)
+}
+
+// RequireResourceNotInState checks that the state has no resource with the given full address, failing and halting
+// the test if it does.
+func RequireResourceNotInState(t testing.TestingT, state *StateStruct, address string) {
+	_, exists := state.ResourcesMap[address]
+	require.Falsef(t, exists, "Resource %s unexpectedly found in state", address)
+}
+
+// AssertResourceCountInState checks that the state has the expected number of managed resources of the given type
+// (e.g. null_resource) across all modules, failing the test if it does not.
+func AssertResourceCountInState(t testing.TestingT, state *StateStruct, resourceType string, expected int) {
+	assert.Equalf(t, expected, countStateResources(state, resourceType), "Unexpected number of %s resources in state", resourceType)
+}
+
+// RequireResourceCountInState checks that the state has the expected number of managed resources of the given type
+// (e.g. null_resource) across all modules, failing and halting the test if it does not.
+func RequireResourceCountInState(t testing.TestingT, state *StateStruct, resourceType string, expected int) {
+	require.Equalf(t, expected, countStateResources(state, resourceType), "Unexpected number of %s resources in state", resourceType)
+}
+
+// AssertResourceAttributeInState checks that the resource with the given full address has the expected value for the
+// given top-level attribute, failing the test if it does not. Note that numbers in the state are float64.
+func AssertResourceAttributeInState(t testing.TestingT, state *StateStruct, address string, attribute string, expected interface{}) {
+	resource, exists := state.ResourcesMap[address]
+	if assert.Truef(t, exists, "Resource %s not found in state", address) {
+		assert.Equalf(t, expected, resource.Values[attribute], "Unexpected value for attribute %s of resource %s", attribute, address)
+	}
+}
+
+// RequireResourceAttributeInState checks that the resource with the given full address has the expected value for
+// the given top-level attribute, failing and halting the test if it does not. Note that numbers in the state are
+// float64.
+func RequireResourceAttributeInState(t testing.TestingT, state *StateStruct, address string, attribute string, expected interface{}) {
+	resource, exists := state.ResourcesMap[address]
+	require.Truef(t, exists, "Resource %s not found in state", address)
+	require.Equalf(t, expected, resource.Values[attribute], "Unexpected value for attribute %s of resource %s", attribute, address)
+}
+
+// AssertProviderVersionInState checks that the provider with the given source address (e.g.
+// registry.terraform.io/hashicorp/null) is at the expected version, failing the test if it is not. The state must come
+// from GetState, as only GetState looks up the provider versions.
+func AssertProviderVersionInState(t testing.TestingT, state *StateStruct, provider string, expected string) {
+	assert.Equalf(t, expected, state.ProviderVersions[provider], "Unexpected version for provider %s", provider)
+}
+
+// RequireProviderVersionInState checks that the provider with the given source address (e.g.
+// registry.terraform.io/hashicorp/null) is at the expected version, failing and halting the test if it is not. The
+// state must come from GetState, as only GetState looks up the provider versions.
+func RequireProviderVersionInState(t testing.TestingT, state *StateStruct, provider string, expected string) {
+	require.Equalf(t, expected, state.ProviderVersions[provider], "Unexpected version for provider %s", provider)
+}
+
+// countStateResources returns the number of managed resources of the given type in the state.
+func countStateResources(state *StateStruct, resourceType string) int {
+	count := 0
+	for _, resource := range state.ResourcesMap {
+		if resource.Mode == "managed" && resource.Type == resourceType {
+			count++
+		}
+	}
+	return count
+}
+
+// getStateAddresses returns the sorted full addresses of the resources in the state.
+func getStateAddresses(state *StateStruct) []string {
+	addresses := []string{}
+	for address := range state.ResourcesMap {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+	return addresses
+}
@@ -0,0 +1,77 @@
+package terraform
+
+import (
+	"encoding/json"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// StateStruct is a Go Struct representation of the state returned from Terraform (after running `terraform show`).
+// Unlike the raw state representation returned by terraform-json, this struct provides a map that maps the resource
+// addresses to the resources in the state, including the resources of child modules.
+type StateStruct struct {
+	// The raw representation of the state. See
+	// https://www.terraform.io/docs/internals/json-format.html#state-representation for details on the structure of
+	// the state output.
+	RawState tfjson.State
+
+	// A map that maps full resource addresses (e.g., module.foo.null_resource.test[0]) to the resources in the state.
+	ResourcesMap map[string]*StateResource
+
+	// A map that maps provider source addresses (e.g., registry.terraform.io/hashicorp/null) to the versions of the
+	// providers selected by terraform init. This is only set by GetState.
+	ProviderVersions map[string]string
+}
+
+// StateResource is a resource (or data source) in the Terraform state.
+type StateResource struct {
+	Address   string                 // The full address of the resource (e.g. module.foo.null_resource.test[0])
+	Module    string                 // The address of the module of the resource, empty for the root module
+	Mode      string                 // Either managed for resources or data for data sources
+	Type      string                 // The type of the resource (e.g. null_resource)
+	Name      string                 // The name of the resource (e.g. test)
+	Index     interface{}            // The count (int) or for_each (string) key of the resource, nil for neither
+	Provider  string                 // The source address of the provider (e.g. registry.terraform.io/hashicorp/null)
+	Values    map[string]interface{} // The attribute values of the resource
+	DependsOn []string               // The addresses of the resources that this resource depends on
+	Tainted   bool                   // Whether the resource is tainted and will be recreated on the next apply
+}
+
+// ParseStateJSON takes in the json string representation of the terraform state and returns a go struct
+// representation for easy introspection.
+func ParseStateJSON(jsonStr string) (*StateStruct, error) {
+	state := &StateStruct{ProviderVersions: map[string]string{}}
+
+	if err := json.Unmarshal([]byte(jsonStr), &state.RawState); err != nil {
+		return nil, err
+	}
+
+	state.ResourcesMap = map[string]*StateResource{}
+	if state.RawState.Values != nil && state.RawState.Values.RootModule != nil {
+		parseModuleStateResources(state.RawState.Values.RootModule, state.ResourcesMap)
+	}
+	return state, nil
+}
+
+// parseModuleStateResources recursively walks through the given module and its child modules and adds their resources
+// to the given map.
+func parseModuleStateResources(module *tfjson.StateModule, out map[string]*StateResource) {
+	for _, resource := range module.Resources {
+		out[resource.Address] = &StateResource{
+			Address:   resource.Address,
+			Module:    module.Address,
+			Mode:      string(resource.Mode),
+			Type:      resource.Type,
+			Name:      resource.Name,
+			Index:     resource.Index,
+			Provider:  resource.ProviderName,
+			Values:    resource.AttributeValues,
+			DependsOn: resource.DependsOn,
+			Tainted:   resource.Tainted,
+		}
+	}
+
+	for _, child := range module.ChildModules {
+		parseModuleStateResources(child, out)
+	}
+}
@@ -0,0 +1,99 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testStateJSON = `{
+  "format_version": "1.0",
+  "terraform_version": "1.9.8",
+  "values": {
+    "root_module": {
+      "resources": [
+        {
+          "address": "null_resource.test[0]",
+          "mode": "managed",
+          "type": "null_resource",
+          "name": "test",
+          "index": 0,
+          "provider_name": "registry.terraform.io/hashicorp/null",
+          "values": {"id": "123", "triggers": null}
+        },
+        {
+          "address": "data.null_data_source.test",
+          "mode": "data",
+          "type": "null_data_source",
+          "name": "test",
+          "provider_name": "registry.terraform.io/hashicorp/null",
+          "values": {"outputs": {}}
+        }
+      ],
+      "child_modules": [
+        {
+          "address": "module.child",
+          "resources": [
+            {
+              "address": "module.child.null_resource.test[\"a\"]",
+              "mode": "managed",
+              "type": "null_resource",
+              "name": "test",
+              "index": "a",
+              "provider_name": "registry.terraform.io/hashicorp/null",
+              "values": {"id": "456"},
+              "depends_on": ["null_resource.test"]
+            }
+          ]
+        }
+      ]
+    }
+  }
+}`
+
+func TestParseStateJSON(t *testing.T) {
+	t.Parallel()
+
+	state, err := ParseStateJSON(testStateJSON)
+	require.NoError(t, err)
+
+	assert.Equal(t, "1.9.8", state.RawState.TerraformVersion)
+	assert.Len(t, state.ResourcesMap, 3)
+	assert.Equal(t, &StateResource{
+		Address:   `module.child.null_resource.test["a"]`,
+		Module:    "module.child",
+		Mode:      "managed",
+		Type:      "null_resource",
+		Name:      "test",
+		Index:     "a",
+		Provider:  "registry.terraform.io/hashicorp/null",
+		Values:    map[string]interface{}{"id": "456"},
+		DependsOn: []string{"null_resource.test"},
+	}, state.ResourcesMap[`module.child.null_resource.test["a"]`])
+	assert.Equal(t, "", state.ResourcesMap["null_resource.test[0]"].Module)
+}
+
+func TestParseStateJSONEmptyState(t *testing.T) {
+	t.Parallel()
+
+	state, err := ParseStateJSON(`{"format_version": "1.0"}`)
+	require.NoError(t, err)
+	assert.Empty(t, state.ResourcesMap)
+}
+
+func TestStateAssertions(t *testing.T) {
+	t.Parallel()
+
+	state, err := ParseStateJSON(testStateJSON)
+	require.NoError(t, err)
+	state.ProviderVersions["registry.terraform.io/hashicorp/null"] = "3.2.3"
+
+	AssertResourceInState(t, state, "null_resource.test[0]")
+	AssertResourceNotInState(t, state, "null_resource.test[1]")
+	AssertResourceCountInState(t, state, "null_resource", 2)
+	AssertResourceCountInState(t, state, "null_data_source", 0)
+	AssertResourceAttributeInState(t, state, `module.child.null_resource.test["a"]`, "id", "456")
+	AssertProviderVersionInState(t, state, "registry.terraform.io/hashicorp/null", "3.2.3")
+	assert.Equal(t, []string{"data.null_data_source.test", `module.child.null_resource.test["a"]`, "null_resource.test[0]"}, getStateAddresses(state))
+}
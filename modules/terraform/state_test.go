@@ -0,0 +1,208 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateIsEmpty(t *testing.T) {
+	t.Parallel()
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-basic-configuration", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir: testFolder,
+		Vars:         map[string]interface{}{"cnt": 1},
+	}
+
+	assert.True(t, StateIsEmpty(t, options))
+
+	InitAndApply(t, options)
+	assert.False(t, StateIsEmpty(t, options))
+
+	Destroy(t, options)
+	assert.True(t, StateIsEmpty(t, options))
+}
+
+func TestDestroyWithAllowNoStateOnUninitializedDir(t *testing.T) {
+	t.Parallel()
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-no-error", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir: testFolder,
+		AllowNoState: true,
+	}
+
+	out, err := DestroyE(t, options)
+	require.NoError(t, err)
+	assert.Empty(t, out)
+}
+
+func TestDestroyWithAllowNoStateSkipsDestroyOfEmptyState(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		stateOutput string
+		exitCode    int
+	}{
+		{"no state file", "No state file was found!", 1},
+		{"backend not initialized", "Error: Backend initialization required, please run \"terraform init\"", 1},
+		{"empty state", "", 0},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			options := &Options{
+				TerraformDir:    t.TempDir(),
+				TerraformBinary: writeStateStub(t, testCase.stateOutput, testCase.exitCode),
+				AllowNoState:    true,
+			}
+
+			out, err := DestroyE(t, options)
+			require.NoError(t, err)
+			assert.Empty(t, out)
+		})
+	}
+}
+
+func TestDestroyWithAllowNoStateDestroysNonEmptyState(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: writeStateStub(t, "null_resource.test", 0),
+		AllowNoState:    true,
+	}
+
+	_, err := DestroyE(t, options)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "destroy was run")
+}
+
+func TestStateIsEmptyEReturnsOtherErrors(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: writeStateStub(t, "Error: Failed to load state: AccessDenied", 1),
+	}
+
+	_, err := StateIsEmptyE(t, options)
+	require.Error(t, err)
+}
+
+// writeStateStub writes a script that can be used as TerraformBinary, which prints the given output for
+// `state list` and fails when running `destroy`.
+func writeStateStub(t *testing.T, stateOutput string, exitCode int) string {
+	stubPath := filepath.Join(t.TempDir(), "terraform-stub")
+	script := fmt.Sprintf(`#!/bin/sh
+if [ "$1" = "state" ]; then
+  echo %q
+  echo %q >&2
+  exit %d
+fi
+if [ "$1" = "destroy" ]; then
+  echo "destroy was run" >&2
+  exit 1
+fi
+`, stateOutput, stateOutput, exitCode)
+	require.NoError(t, os.WriteFile(stubPath, []byte(script), 0755))
+	return stubPath
+}
+
+func TestStateListE(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: writeStateStub(t, "null_resource.test[0]\nmodule.child.null_resource.test[\"a\"]", 0),
+	}
+
+	addresses, err := StateListE(t, options)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"null_resource.test[0]", `module.child.null_resource.test["a"]`}, addresses)
+}
+
+func TestGetState(t *testing.T) {
+	t.Parallel()
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-basic-configuration", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir: testFolder,
+		Vars:         map[string]interface{}{"cnt": 2},
+	}
+	defer Destroy(t, options)
+	InitAndApply(t, options)
+
+	assert.Equal(t, []string{"null_resource.test[0]", "null_resource.test[1]"}, StateList(t, options))
+
+	state := GetState(t, options)
+	AssertResourceInState(t, state, "null_resource.test[1]")
+	AssertResourceCountInState(t, state, "null_resource", 2)
+	assert.NotEmpty(t, state.ProviderVersions["registry.terraform.io/hashicorp/null"])
+
+	resource := StateShow(t, options, "null_resource.test[0]")
+	assert.Equal(t, "null_resource", resource.Type)
+	assert.Equal(t, float64(0), resource.Index)
+
+	_, err = StateShowE(t, options, "null_resource.test[2]")
+	assert.Equal(t, StateResourceNotFound("null_resource.test[2]"), err)
+}
+
+func TestStateMvAndStateRm(t *testing.T) {
+	t.Parallel()
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-basic-configuration", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir: testFolder,
+		Vars:         map[string]interface{}{"cnt": 2},
+	}
+	defer Destroy(t, options)
+	InitAndApply(t, options)
+
+	StateMv(t, options, "null_resource.test[1]", "null_resource.test[5]")
+	assert.Equal(t, []string{"null_resource.test[0]", "null_resource.test[5]"}, StateList(t, options))
+
+	StateRm(t, options, "null_resource.test[5]")
+	assert.Equal(t, []string{"null_resource.test[0]"}, StateList(t, options))
+
+	_, err = StateMvE(t, options, "null_resource.test[1]", "null_resource.test[2]")
+	assert.Equal(t, StateResourceNotFound("null_resource.test[1]"), err)
+
+	_, err = StateRmE(t, options, "null_resource.test[1]")
+	assert.Equal(t, StateResourceNotFound("null_resource.test[1]"), err)
+}
+
+func TestStateMvEReturnsAddressesOnFailure(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: writeTerraformStub(t, "state", "Error: Failed to persist state to backend", 1),
+	}
+
+	out, err := StateMvE(t, options, "module.old", "module.new")
+	var mvErr StateMvFailed
+	require.ErrorAs(t, err, &mvErr)
+	assert.Equal(t, "module.old", mvErr.Source)
+	assert.Equal(t, "module.new", mvErr.Destination)
+	assert.Contains(t, err.Error(), "failed to move module.old to module.new")
+	assert.Contains(t, out, "Failed to persist state to backend")
+}
@@ -0,0 +1,174 @@
+package terraform
+
+import (
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// The statuses of the modules of a terragrunt run-all command.
+const (
+	TgModuleSucceeded = "succeeded"  // The command succeeded in the module
+	TgModuleFailed    = "failed"     // The command failed in the module
+	TgModuleEarlyExit = "early exit" // The command didn't run in the module because one of its dependencies failed
+	TgModuleUnknown   = "unknown"    // The run-all command failed before the module finished (e.g. terragrunt itself failed)
+)
+
+// TgRunAllResult is the result of a terragrunt run-all command.
+type TgRunAllResult struct {
+	Command string           // The terraform command that was run in each module (e.g. apply)
+	Output  string           // The stdout/stderr of the run-all command
+	Modules []TgModuleResult // The modules of the stack, in the order terragrunt processed them
+}
+
+// TgModuleResult is the result of a terragrunt run-all command in one module of the stack.
+type TgModuleResult struct {
+	Path   string // The path of the module, relative to the TerraformDir of the options (e.g. vpc)
+	Group  int    // The group in which terragrunt ran the module (modules run after their dependencies), 0 if unknown
+	Status string // One of TgModuleSucceeded, TgModuleFailed, TgModuleEarlyExit or TgModuleUnknown
+	Error  string // The error of the module if it failed or exited early
+}
+
+// GetModule returns the result of the module with the given path (relative to the TerraformDir of the options), and
+// whether the module is part of the result.
+func (result *TgRunAllResult) GetModule(path string) (TgModuleResult, bool) {
+	path = filepath.Clean(path)
+	for _, module := range result.Modules {
+		if module.Path == path {
+			return module, true
+		}
+	}
+	return TgModuleResult{}, false
+}
+
+// GetModulesWithStatus returns the paths of the modules that have the given status, in the order terragrunt processed
+// them.
+func (result *TgRunAllResult) GetModulesWithStatus(status string) []string {
+	paths := []string{}
+	for _, module := range result.Modules {
+		if module.Status == status {
+			paths = append(paths, module.Path)
+		}
+	}
+	return paths
+}
+
+var (
+	// tgGroupRegexp matches the header of a group in the list of modules that terragrunt prints before running them.
+	tgGroupRegexp = regexp.MustCompile(`^\s*Group (\d+)\s*$`)
+
+	// tgGroupModuleRegexp matches a module of a group. Newer terragrunt versions call modules units.
+	tgGroupModuleRegexp = regexp.MustCompile(`^\s*- (?:Module|Unit) (.+?)\s*$`)
+
+	// tgModuleSucceededRegexp matches the (debug) message that terragrunt logs when a module succeeds.
+	tgModuleSucceededRegexp = regexp.MustCompile(`(?:Module|Unit) (\S+) has finished successfully`)
+
+	// tgModuleFailedRegexp matches the message that terragrunt logs when a module fails.
+	tgModuleFailedRegexp = regexp.MustCompile(`(?:Module|Unit) (\S+) has finished with an error:?\s*(.*?)\s*$`)
+
+	// tgModuleEarlyExitRegexp matches the message that terragrunt logs when a module doesn't run because of a failed
+	// dependency.
+	tgModuleEarlyExitRegexp = regexp.MustCompile(`Dependency (\S+) of (?:module|unit) (\S+) just finished with an error`)
+)
+
+// TgRunAll runs terragrunt run-all with the given terraform command (apply, destroy or plan) and options, and returns
+// the result of each module of the stack. This will fail the test if the command fails in any module.
+func TgRunAll(t testing.TestingT, options *Options, command string) *TgRunAllResult {
+	result, err := TgRunAllE(t, options, command)
+	require.NoError(t, err)
+	return result
+}
+
+// TgRunAllE runs terragrunt run-all with the given terraform command (apply, destroy or plan) and options, and returns
+// the result of each module of the stack. If the command fails in some modules, this returns the result along with the
+// error, so that tests can assert on the individual modules. Modules that neither failed nor were stopped by a failed
+// dependency are reported as succeeded, as terragrunt only logs their success at debug level.
+func TgRunAllE(t testing.TestingT, options *Options, command string) (*TgRunAllResult, error) {
+	if options.TerraformBinary != "terragrunt" {
+		return nil, TgInvalidBinary(options.TerraformBinary)
+	}
+
+	args := []string{"run-all", command, "-input=false"}
+	if command == "apply" || command == "destroy" {
+		args = append(args, "-auto-approve")
+	}
+
+	out, err := RunTerraformCommandE(t, options, FormatArgs(options, args...)...)
+	return parseTgRunAllOutput(options.TerraformDir, command, out, err == nil), err
+}
+
+// parseTgRunAllOutput parses the modules and their statuses from the output of a terragrunt run-all command that ran
+// in the given directory. If succeeded is false, the modules without a failure are reported as succeeded only if the
+// failure of the command can be attributed to other modules.
+func parseTgRunAllOutput(terragruntDir string, command string, out string, succeeded bool) *TgRunAllResult {
+	result := &TgRunAllResult{Command: command, Output: out, Modules: []TgModuleResult{}}
+	moduleIndexes := map[string]int{}
+
+	getModule := func(path string) *TgModuleResult {
+		path = getTgModulePath(terragruntDir, path)
+		index, exists := moduleIndexes[path]
+		if !exists {
+			index = len(result.Modules)
+			moduleIndexes[path] = index
+			result.Modules = append(result.Modules, TgModuleResult{Path: path})
+		}
+		return &result.Modules[index]
+	}
+
+	group := 0
+	hasFailedModule := false
+	for _, line := range strings.Split(out, "\n") {
+		if match := tgGroupRegexp.FindStringSubmatch(line); match != nil {
+			group, _ = strconv.Atoi(match[1])
+		} else if match := tgGroupModuleRegexp.FindStringSubmatch(line); match != nil && group > 0 {
+			getModule(match[1]).Group = group
+		} else if match := tgModuleFailedRegexp.FindStringSubmatch(line); match != nil {
+			module := getModule(match[1])
+			// Modules that exit early are also reported as finished with an error about their dependency
+			if module.Status == TgModuleEarlyExit || strings.Contains(match[2], "because one of its dependencies") {
+				module.Status = TgModuleEarlyExit
+			} else {
+				module.Status = TgModuleFailed
+				hasFailedModule = true
+			}
+			module.Error = match[2]
+		} else if match := tgModuleEarlyExitRegexp.FindStringSubmatch(line); match != nil {
+			module := getModule(match[2])
+			if module.Status == "" {
+				module.Status = TgModuleEarlyExit
+				module.Error = "dependency " + getTgModulePath(terragruntDir, match[1]) + " failed"
+			}
+		} else if match := tgModuleSucceededRegexp.FindStringSubmatch(line); match != nil {
+			getModule(match[1]).Status = TgModuleSucceeded
+		}
+	}
+
+	for i := range result.Modules {
+		if result.Modules[i].Status != "" {
+			continue
+		}
+		if succeeded || hasFailedModule {
+			result.Modules[i].Status = TgModuleSucceeded
+		} else {
+			result.Modules[i].Status = TgModuleUnknown
+		}
+	}
+	return result
+}
+
+// getTgModulePath returns the given module path as logged by terragrunt (absolute in older versions, relative in newer
+// ones) relative to the given terragrunt directory.
+func getTgModulePath(terragruntDir string, path string) string {
+	if filepath.IsAbs(path) && terragruntDir != "" {
+		if absDir, err := filepath.Abs(terragruntDir); err == nil {
+			if relPath, err := filepath.Rel(absDir, path); err == nil {
+				return relPath
+			}
+		}
+	}
+	return filepath.Clean(path)
+}
@@ -0,0 +1,67 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const tgRunAllOutput = `The stack at /stack will be processed in the following order for command apply:
+Group 1
+- Module /stack/vpc
+- Module /stack/kms
+
+Group 2
+- Module /stack/db
+
+Group 3
+- Module /stack/app
+
+Module /stack/vpc has finished successfully!
+Module /stack/db has finished with an error: 1 error occurred:
+	* exit status 1
+Dependency /stack/db of module /stack/app just finished with an error. Module /stack/app will have to return an error too.
+Module /stack/app has finished with an error: Cannot process module Module /stack/app because one of its dependencies, Module /stack/db, finished with an error: exit status 1
+`
+
+func TestParseTgRunAllOutput(t *testing.T) {
+	t.Parallel()
+
+	result := parseTgRunAllOutput("/stack", "apply", tgRunAllOutput, false)
+	assert.Equal(t, []TgModuleResult{
+		{Path: "vpc", Group: 1, Status: TgModuleSucceeded},
+		{Path: "kms", Group: 1, Status: TgModuleSucceeded},
+		{Path: "db", Group: 2, Status: TgModuleFailed, Error: "1 error occurred:"},
+		{Path: "app", Group: 3, Status: TgModuleEarlyExit, Error: "Cannot process module Module /stack/app because one of its dependencies, Module /stack/db, finished with an error: exit status 1"},
+	}, result.Modules)
+
+	assert.Equal(t, []string{"db"}, result.GetModulesWithStatus(TgModuleFailed))
+	module, exists := result.GetModule("./db")
+	require.True(t, exists)
+	assert.Equal(t, 2, module.Group)
+}
+
+func TestParseTgRunAllOutputWithUnits(t *testing.T) {
+	t.Parallel()
+
+	out := `Group 1
+- Unit ./vpc
+
+Group 2
+- Unit ./app
+`
+
+	result := parseTgRunAllOutput("/stack", "plan", out, true)
+	assert.Equal(t, []string{"vpc", "app"}, result.GetModulesWithStatus(TgModuleSucceeded))
+
+	result = parseTgRunAllOutput("/stack", "plan", out, false)
+	assert.Equal(t, []string{"vpc", "app"}, result.GetModulesWithStatus(TgModuleUnknown))
+}
+
+func TestTgRunAllERequiresTerragrunt(t *testing.T) {
+	t.Parallel()
+
+	_, err := TgRunAllE(t, &Options{TerraformBinary: "terraform"}, "apply")
+	assert.Equal(t, TgInvalidBinary("terraform"), err)
+}
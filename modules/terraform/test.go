@@ -0,0 +1,190 @@
+package terraform
+
+import (
+	"bufio"
+	"encoding/json"
+	"strings"
+	go_test "testing"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// The statuses of a run (and of a whole test suite) in the output of terraform test.
+const (
+	TestStatusPass  = "pass"
+	TestStatusFail  = "fail"
+	TestStatusError = "error"
+	TestStatusSkip  = "skip"
+)
+
+// TestResults are the results of running terraform test.
+type TestResults struct {
+	Runs        []TestRunResult // The results of the run blocks of all the test files, in the order they were run
+	Diagnostics []string        // The errors and warnings that aren't specific to a run block (e.g. a test file failing to load)
+	Summary     TestSummary     // The totals of the whole test suite
+}
+
+// TestRunResult is the result of a run block of a test file (e.g. tests/main.tftest.hcl).
+type TestRunResult struct {
+	File        string   // The path of the test file
+	Run         string   // The name of the run block
+	Status      string   // One of pass, fail, error or skip
+	Diagnostics []string // The failed assertions, errors and warnings of the run block
+}
+
+// TestSummary are the totals of a terraform test suite.
+type TestSummary struct {
+	Status  string `json:"status"`  // One of pass, fail, error or skip
+	Passed  int    `json:"passed"`  // The number of run blocks that passed
+	Failed  int    `json:"failed"`  // The number of run blocks with failed assertions
+	Errored int    `json:"errored"` // The number of run blocks that could not be run
+	Skipped int    `json:"skipped"` // The number of run blocks that were skipped
+}
+
+// A line of the machine-readable output of terraform test -json.
+type testJSONMessage struct {
+	Message  string `json:"@message"`
+	Type     string `json:"type"`
+	TestFile string `json:"@testfile"`
+	TestRun  string `json:"@testrun"`
+
+	Run *struct {
+		Path     string `json:"path"`
+		Run      string `json:"run"`
+		Progress string `json:"progress"`
+		Status   string `json:"status"`
+	} `json:"test_run"`
+
+	Diagnostic *struct {
+		Severity string `json:"severity"`
+		Summary  string `json:"summary"`
+		Detail   string `json:"detail"`
+	} `json:"diagnostic"`
+
+	Summary *TestSummary `json:"test_summary"`
+}
+
+// Test runs the built-in terraform test command (Terraform 1.6+) with the given options, and reports each run block of
+// each test file as a Go subtest (named <test file>/<run block>), so that HCL-native tests show up in go test output
+// next to the other tests. This fails the subtests whose run block fails, and the test itself if terraform test can't
+// run at all or reports a failure that isn't specific to a run block (e.g. a test file that fails to load).
+func Test(t *go_test.T, options *Options) {
+	results, err := TestE(t, options)
+	require.NoError(t, err)
+
+	runsByFile := map[string][]TestRunResult{}
+	files := []string{}
+	for _, run := range results.Runs {
+		if _, seen := runsByFile[run.File]; !seen {
+			files = append(files, run.File)
+		}
+		runsByFile[run.File] = append(runsByFile[run.File], run)
+	}
+
+	for _, file := range files {
+		runs := runsByFile[file]
+		t.Run(file, func(t *go_test.T) {
+			for _, run := range runs {
+				run := run
+				t.Run(run.Run, func(t *go_test.T) {
+					switch run.Status {
+					case TestStatusFail, TestStatusError:
+						t.Errorf("run %q in %s finished with status %s:\n%s", run.Run, run.File, run.Status, strings.Join(run.Diagnostics, "\n"))
+					case TestStatusSkip:
+						t.Skipf("run %q in %s was skipped", run.Run, run.File)
+					}
+				})
+			}
+		})
+	}
+
+	if isTestSuiteFailed(results) {
+		t.Errorf("terraform test finished with status %s:\n%s", results.Summary.Status, strings.Join(results.Diagnostics, "\n"))
+	}
+}
+
+// isTestSuiteFailed returns whether the given results fail the test suite as a whole, whatever the results of the run
+// blocks: if the summary status is fail or error, or if there are errors that aren't specific to a run block (e.g. a
+// test file failing to load while the other test files pass).
+func isTestSuiteFailed(results *TestResults) bool {
+	if results.Summary.Status != TestStatusPass && results.Summary.Status != TestStatusSkip {
+		return true
+	}
+	for _, diagnostic := range results.Diagnostics {
+		if strings.HasPrefix(diagnostic, "error:") {
+			return true
+		}
+	}
+	return false
+}
+
+// TestE runs the built-in terraform test command (Terraform 1.6+) with the given options and returns its parsed
+// results. Failing run blocks are not an error: check the Status of the results instead. An error is only returned if
+// terraform test doesn't produce any results.
+func TestE(t testing.TestingT, options *Options) (*TestResults, error) {
+	out, runErr := RunTerraformCommandAndGetStdoutE(t, options, FormatArgs(options, "test", "-json")...)
+
+	results, hasSummary, err := parseTestOutput(out)
+	if err != nil {
+		return nil, err
+	}
+	if !hasSummary && runErr != nil {
+		return nil, runErr
+	}
+	return results, nil
+}
+
+// parseTestOutput parses the output of terraform test -json, and returns whether the output contains the summary that
+// terraform test writes once all tests are done.
+func parseTestOutput(out string) (*TestResults, bool, error) {
+	results := &TestResults{Runs: []TestRunResult{}, Diagnostics: []string{}}
+	hasSummary := false
+	runIndexes := map[string]int{}
+
+	getRun := func(file string, name string) *TestRunResult {
+		key := file + "\x00" + name
+		index, exists := runIndexes[key]
+		if !exists {
+			index = len(results.Runs)
+			runIndexes[key] = index
+			results.Runs = append(results.Runs, TestRunResult{File: file, Run: name, Diagnostics: []string{}})
+		}
+		return &results.Runs[index]
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+
+		var message testJSONMessage
+		if err := json.Unmarshal([]byte(line), &message); err != nil {
+			return nil, false, err
+		}
+
+		switch {
+		case message.Run != nil:
+			run := getRun(message.Run.Path, message.Run.Run)
+			if message.Run.Progress == "complete" {
+				run.Status = message.Run.Status
+			}
+		case message.Diagnostic != nil:
+			diagnostic := strings.TrimSpace(message.Diagnostic.Severity + ": " + message.Diagnostic.Summary + "\n" + message.Diagnostic.Detail)
+			if message.TestRun != "" {
+				run := getRun(message.TestFile, message.TestRun)
+				run.Diagnostics = append(run.Diagnostics, diagnostic)
+			} else {
+				results.Diagnostics = append(results.Diagnostics, diagnostic)
+			}
+		case message.Summary != nil:
+			results.Summary = *message.Summary
+			hasSummary = true
+		}
+	}
+
+	return results, hasSummary, scanner.Err()
+}
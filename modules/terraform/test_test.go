@@ -0,0 +1,89 @@
+package terraform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTestOutput(t *testing.T) {
+	t.Parallel()
+
+	out := strings.Join([]string{
+		`{"@level":"info","@message":"Terraform 1.9.8","type":"version","terraform":"1.9.8","ui":"1.2"}`,
+		`{"@level":"info","@message":"Found 2 files and 3 run blocks","type":"test_abstract","test_abstract":{"tests/main.tftest.hcl":["defaults","custom_name"],"tests/broken.tftest.hcl":[]}}`,
+		`{"@level":"info","@message":"tests/main.tftest.hcl... in progress","@testfile":"tests/main.tftest.hcl","type":"test_file","test_file":{"path":"tests/main.tftest.hcl","progress":"starting"}}`,
+		`{"@level":"info","@message":"  \"defaults\"... in progress","@testfile":"tests/main.tftest.hcl","@testrun":"defaults","type":"test_run","test_run":{"path":"tests/main.tftest.hcl","run":"defaults","progress":"starting","elapsed":0}}`,
+		`{"@level":"info","@message":"  \"defaults\"... pass","@testfile":"tests/main.tftest.hcl","@testrun":"defaults","type":"test_run","test_run":{"path":"tests/main.tftest.hcl","run":"defaults","progress":"complete","status":"pass"}}`,
+		`{"@level":"error","@message":"Error: Test assertion failed","@testfile":"tests/main.tftest.hcl","@testrun":"custom_name","type":"diagnostic","diagnostic":{"severity":"error","summary":"Test assertion failed","detail":"name did not match"}}`,
+		`{"@level":"info","@message":"  \"custom_name\"... fail","@testfile":"tests/main.tftest.hcl","@testrun":"custom_name","type":"test_run","test_run":{"path":"tests/main.tftest.hcl","run":"custom_name","progress":"complete","status":"fail"}}`,
+		`{"@level":"error","@message":"Error: Unsupported block type","@testfile":"tests/broken.tftest.hcl","type":"diagnostic","diagnostic":{"severity":"error","summary":"Unsupported block type","detail":""}}`,
+		`{"@level":"info","@message":"Failure! 1 passed, 1 failed.","type":"test_summary","test_summary":{"status":"fail","passed":1,"failed":1,"errored":0,"skipped":0}}`,
+	}, "\n")
+
+	results, hasSummary, err := parseTestOutput(out)
+	require.NoError(t, err)
+	assert.True(t, hasSummary)
+
+	assert.Equal(t, []TestRunResult{
+		{File: "tests/main.tftest.hcl", Run: "defaults", Status: TestStatusPass, Diagnostics: []string{}},
+		{File: "tests/main.tftest.hcl", Run: "custom_name", Status: TestStatusFail, Diagnostics: []string{"error: Test assertion failed\nname did not match"}},
+	}, results.Runs)
+	assert.Equal(t, []string{"error: Unsupported block type"}, results.Diagnostics)
+	assert.Equal(t, TestSummary{Status: TestStatusFail, Passed: 1, Failed: 1}, results.Summary)
+}
+
+func TestParseTestOutputWithoutSummary(t *testing.T) {
+	t.Parallel()
+
+	results, hasSummary, err := parseTestOutput("Error: Failed to load plugin schemas\n")
+	require.NoError(t, err)
+	assert.False(t, hasSummary)
+	assert.Empty(t, results.Runs)
+}
+
+func TestTestEReturnsErrorWithoutResults(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		TerraformDir:    t.TempDir(),
+		TerraformBinary: writeTerraformStub(t, "test", "Error: Unsupported command", 1),
+	}
+
+	_, err := TestE(t, options)
+	require.Error(t, err)
+}
+
+func TestIsTestSuiteFailed(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		results  *TestResults
+		expected bool
+	}{
+		{"passed", &TestResults{Summary: TestSummary{Status: TestStatusPass}}, false},
+		{"skipped", &TestResults{Summary: TestSummary{Status: TestStatusSkip}}, false},
+		{"failed", &TestResults{Summary: TestSummary{Status: TestStatusFail}}, true},
+		{"errored", &TestResults{Summary: TestSummary{Status: TestStatusError}}, true},
+		{"file error with passing runs", &TestResults{
+			Runs:        []TestRunResult{{File: "tests/main.tftest.hcl", Run: "defaults", Status: TestStatusPass}},
+			Diagnostics: []string{"error: Unsupported block type"},
+			Summary:     TestSummary{Status: TestStatusPass, Passed: 1},
+		}, true},
+		{"file warning", &TestResults{
+			Diagnostics: []string{"warning: Deprecated attribute"},
+			Summary:     TestSummary{Status: TestStatusPass},
+		}, false},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, testCase.expected, isTestSuiteFailed(testCase.results))
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package terraform
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	go_test "testing"
+
+	"github.com/gruntwork-io/terratest/modules/collections"
+	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// ValidateAllModulesOptions configures ValidateAllModules.
+type ValidateAllModulesOptions struct {
+	ExcludeDirs     []string // The directories to skip with their subdirectories, relative to the root dir (e.g. test/fixtures)
+	Parallelism     int      // The number of modules to validate at the same time. Defaults to the number of CPUs.
+	SkipFormatCheck bool     // Don't run terraform fmt -check
+	Options         *Options // The options to run every command with (e.g. TerraformBinary, EnvVars or ProviderCacheDir). TerraformDir is ignored.
+}
+
+// ModuleValidationResult is the result of the validation of one module by ValidateAllModules.
+type ModuleValidationResult struct {
+	Dir        string // The directory of the module, relative to the root dir
+	Err        error  // The error of terraform init or terraform validate, if any
+	Output     string // The output of terraform init and terraform validate
+	FormatDiff string // The changes terraform fmt would make, empty if all the files of the module are formatted
+}
+
+// Failed returns true if the module is not valid or not formatted.
+func (result ModuleValidationResult) Failed() bool {
+	return result.Err != nil || result.FormatDiff != ""
+}
+
+// ValidateAllModules finds every Terraform module (directory with .tf files) under the given root dir, and runs
+// terraform init -backend=false, terraform validate and terraform fmt -check in all of them, in parallel. Each module
+// is then reported as a subtest (named after its directory) that fails if the module is not valid or not formatted.
+// The modules are validated in a copy of the root dir, so that no .terraform dirs are written to the repo, which means
+// that the root dir must contain all the local modules that the modules use (e.g. the root of the repo).
+func ValidateAllModules(t *go_test.T, rootDir string, opts *ValidateAllModulesOptions) {
+	results, err := ValidateAllModulesE(t, rootDir, opts)
+	require.NoError(t, err)
+
+	for _, result := range results {
+		result := result
+		t.Run(result.Dir, func(t *go_test.T) {
+			if result.Err != nil {
+				t.Errorf("terraform validate failed in %s: %v", result.Dir, result.Err)
+			}
+			if result.FormatDiff != "" {
+				t.Errorf("terraform fmt found unformatted files in %s:\n%s", result.Dir, result.FormatDiff)
+			}
+		})
+	}
+}
+
+// ValidateAllModulesE finds every Terraform module (directory with .tf files) under the given root dir, runs terraform
+// init -backend=false, terraform validate and terraform fmt -check in all of them, in parallel, and returns the result
+// of each module, sorted by directory. Invalid or unformatted modules are not an error: check the results instead.
+func ValidateAllModulesE(t testing.TestingT, rootDir string, opts *ValidateAllModulesOptions) ([]ModuleValidationResult, error) {
+	if opts == nil {
+		opts = &ValidateAllModulesOptions{}
+	}
+
+	testFolder, err := files.CopyTerraformFolderToTemp(rootDir, "validate-all-modules")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(testFolder)
+
+	dirs, err := findTerraformModuleDirs(testFolder, opts.ExcludeDirs)
+	if err != nil {
+		return nil, err
+	}
+
+	parallelism := opts.Parallelism
+	if parallelism <= 0 {
+		parallelism = runtime.NumCPU()
+	}
+
+	results := make([]ModuleValidationResult, len(dirs))
+	dirIndexes := make(chan int)
+	wg := &sync.WaitGroup{}
+	for worker := 0; worker < parallelism; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range dirIndexes {
+				results[index] = validateModule(t, testFolder, dirs[index], opts)
+			}
+		}()
+	}
+	for index := range dirs {
+		dirIndexes <- index
+	}
+	close(dirIndexes)
+	wg.Wait()
+
+	return results, nil
+}
+
+// validateModule runs terraform init -backend=false, terraform validate and terraform fmt -check in the given module
+// dir (relative to the given root dir).
+func validateModule(t testing.TestingT, rootDir string, dir string, opts *ValidateAllModulesOptions) ModuleValidationResult {
+	result := ModuleValidationResult{Dir: dir}
+
+	options := &Options{}
+	if opts.Options != nil {
+		clonedOptions, err := opts.Options.Clone()
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		options = clonedOptions
+	}
+	options.TerraformDir = filepath.Join(rootDir, dir)
+
+	initOptions, cleanup, err := getProviderInstallationOptions(options)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	defer cleanup()
+
+	out, err := RunTerraformCommandE(t, initOptions, "init", "-input=false", "-backend=false")
+	result.Output = out
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	out, err = ValidateE(t, options)
+	result.Output += out
+	result.Err = err
+
+	if !opts.SkipFormatCheck {
+		diff, needsFormatting, err := formatCheckE(t, options, false)
+		if err != nil && result.Err == nil {
+			result.Err = err
+		}
+		if needsFormatting {
+			result.FormatDiff = diff
+		}
+	}
+	return result
+}
+
+// findTerraformModuleDirs returns the sorted directories (relative to the given root dir) that contain .tf files,
+// skipping hidden directories (e.g. .terraform) and the given directories (relative to the root dir) with their
+// subdirectories.
+func findTerraformModuleDirs(rootDir string, excludeDirs []string) ([]string, error) {
+	excludedDirs := []string{}
+	for _, excludeDir := range excludeDirs {
+		excludedDirs = append(excludedDirs, filepath.Clean(excludeDir))
+	}
+
+	dirs := []string{}
+	err := filepath.WalkDir(rootDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(rootDir, path)
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if relPath != "." && (strings.HasPrefix(entry.Name(), ".") || collections.ListContains(excludedDirs, relPath)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if filepath.Ext(path) == ".tf" && !collections.ListContains(dirs, filepath.Dir(relPath)) {
+			dirs = append(dirs, filepath.Dir(relPath))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(dirs)
+	return dirs, nil
+}
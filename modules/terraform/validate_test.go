@@ -1,6 +1,8 @@
 package terraform
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/gruntwork-io/terratest/modules/files"
@@ -35,3 +37,45 @@ func TestInitAndValidateWithError(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, out, "Reference to undeclared input variable")
 }
+
+func TestFindTerraformModuleDirs(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	for _, file := range []string{"main.tf", "modules/vpc/main.tf", "modules/vpc/outputs.tf", "modules/vpc/nested/main.tf", "test/fixtures/main.tf", ".terraform/modules/vpc/main.tf", "docs/README.md"} {
+		path := filepath.Join(rootDir, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte{}, 0644))
+	}
+
+	dirs, err := findTerraformModuleDirs(rootDir, []string{"test/fixtures"})
+	require.NoError(t, err)
+	require.Equal(t, []string{".", "modules/vpc", "modules/vpc/nested"}, dirs)
+}
+
+func TestValidateAllModulesEReportsUnformattedModules(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	for _, dir := range []string{"a", "b"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(rootDir, dir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(rootDir, dir, "main.tf"), []byte{}, 0644))
+	}
+
+	// terraform fmt prints the diff to stdout
+	stubPath := filepath.Join(t.TempDir(), "terraform-stub")
+	require.NoError(t, os.WriteFile(stubPath, []byte("#!/bin/sh\nif [ \"$1\" = \"fmt\" ]; then\n  echo main.tf\n  exit 3\nfi\n"), 0755))
+
+	results, err := ValidateAllModulesE(t, rootDir, &ValidateAllModulesOptions{
+		Parallelism: 2,
+		Options:     &Options{TerraformBinary: stubPath},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for i, dir := range []string{"a", "b"} {
+		require.Equal(t, dir, results[i].Dir)
+		require.NoError(t, results[i].Err)
+		require.Equal(t, "main.tf", results[i].FormatDiff)
+		require.True(t, results[i].Failed())
+	}
+}
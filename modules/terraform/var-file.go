@@ -4,13 +4,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"text/template"
 
+	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/testing"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/stretchr/testify/require"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/gocty"
@@ -247,3 +253,318 @@ func generateTypeFromValuesMap(valMap map[string]cty.Value) cty.Type {
 	}
 	return cty.Object(outType)
 }
+
+// varFileTemplateExtension is the suffix that marks an entry in VarFiles as a Go text template that should be rendered
+// with Options.TemplateData before being passed to Terraform.
+const varFileTemplateExtension = ".tmpl"
+
+// renderVarFileTemplates renders every -var-file argument in args that ends in .tmpl using options.TemplateData, and
+// writes options.Vars (or only the ones marked with Sensitive) to a var file for the -var-file argument that FormatArgs
+// adds with VarsInVarFile (or for the sensitive Vars), and returns a copy of args that points at the rendered files instead. Relative template paths are resolved against
+// options.TerraformDir, the same way Terraform resolves them. The returned cleanup function removes the rendered files
+// and must be called once the command has finished.
+func renderVarFileTemplates(options *Options, args []string) ([]string, func(), error) {
+	renderedFiles := []string{}
+	cleanup := func() {
+		for _, renderedFile := range renderedFiles {
+			os.Remove(renderedFile)
+		}
+	}
+
+	renderedArgs := make([]string, len(args))
+	copy(renderedArgs, args)
+
+	for i := 0; i < len(renderedArgs)-1; i++ {
+		if renderedArgs[i] != "-var-file" {
+			continue
+		}
+
+		var renderedFile string
+		var err error
+		switch {
+		case renderedArgs[i+1] == varsVarFileArg:
+			renderedFile, err = writeVarsVarFile(options.Vars)
+		case renderedArgs[i+1] == sensitiveVarsVarFileArg:
+			_, sensitiveVars := splitSensitiveVars(options.Vars)
+			renderedFile, err = writeVarsVarFile(sensitiveVars)
+		case strings.HasSuffix(renderedArgs[i+1], varFileTemplateExtension):
+			renderedFile, err = renderVarFileTemplate(options, renderedArgs[i+1])
+		default:
+			continue
+		}
+		if err != nil {
+			cleanup()
+			return nil, func() {}, err
+		}
+		renderedFiles = append(renderedFiles, renderedFile)
+		renderedArgs[i+1] = renderedFile
+		i++
+	}
+
+	return renderedArgs, cleanup, nil
+}
+
+// renderVarFileTemplate renders the given var file template with options.TemplateData into a temp file and returns the
+// path of that file. The rendered file keeps the name of the template minus the .tmpl suffix (e.g. foo.tfvars.json.tmpl
+// renders to <random>-foo.tfvars.json) so that Terraform can still detect JSON var files by their extension.
+func renderVarFileTemplate(options *Options, templatePath string) (string, error) {
+	fullPath := templatePath
+	if !filepath.IsAbs(fullPath) && options.TerraformDir != "" {
+		fullPath = filepath.Join(options.TerraformDir, templatePath)
+	}
+
+	tmpl, err := template.New(filepath.Base(fullPath)).Option("missingkey=error").ParseFiles(fullPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse var file template %s: %w", templatePath, err)
+	}
+
+	renderedName := strings.TrimSuffix(filepath.Base(fullPath), varFileTemplateExtension)
+	renderedFile, err := os.CreateTemp("", "*-"+renderedName)
+	if err != nil {
+		return "", err
+	}
+	defer renderedFile.Close()
+
+	if err := tmpl.Execute(renderedFile, options.TemplateData); err != nil {
+		os.Remove(renderedFile.Name())
+		return "", fmt.Errorf("failed to render var file template %s: %w", templatePath, err)
+	}
+
+	return renderedFile.Name(), nil
+}
+
+// SensitiveValue marks a variable value as sensitive: WriteVarFile writes the value to the var file as is, but redacts
+// it from the logs, and FormatArgs passes it in a temp var file instead of a -var flag. Use Sensitive to create one.
+type SensitiveValue struct {
+	Value interface{}
+}
+
+// Sensitive marks the given variable value as sensitive, so that it's not logged by WriteVarFile. Sensitive values in
+// the Vars of the options are passed to Terraform in a temp var file instead of -var flags, so that they don't show up
+// in the logged commands either.
+func Sensitive(value interface{}) SensitiveValue {
+	return SensitiveValue{Value: value}
+}
+
+// redactedValue replaces the sensitive values in the logs of WriteVarFile.
+const redactedValue = `"(sensitive value)"`
+
+// WriteVarFile writes the given variables to the var file at the given path as HCL (e.g. foo.tfvars), so that complex
+// values can be passed to Terraform with VarFiles instead of -var flags. Nested maps and lists are written as objects
+// and tuples, and strings ending in a newline (e.g. scripts or certificates) as heredocs. Wrap a value with Sensitive
+// to redact it from the logs. This will fail the test if a value can't be converted to HCL or the file can't be written.
+func WriteVarFile(t testing.TestingT, path string, vars map[string]interface{}) {
+	require.NoError(t, WriteVarFileE(t, path, vars))
+}
+
+// WriteVarFileE writes the given variables to the var file at the given path as HCL (e.g. foo.tfvars), so that complex
+// values can be passed to Terraform with VarFiles instead of -var flags. Nested maps and lists are written as objects
+// and tuples, and strings ending in a newline (e.g. scripts or certificates) as heredocs. Wrap a value with Sensitive
+// to redact it from the logs.
+func WriteVarFileE(t testing.TestingT, path string, vars map[string]interface{}) error {
+	contents, err := formatVarFile(vars, false)
+	if err != nil {
+		return err
+	}
+	redactedContents, err := formatVarFile(vars, true)
+	if err != nil {
+		return err
+	}
+
+	logger.Default.Logf(t, "Writing var file %s:\n%s", path, redactedContents)
+	return os.WriteFile(path, []byte(contents), 0644)
+}
+
+// writeVarsVarFile writes the given Vars of the options to a temp var file, which only the current user can read, and
+// returns its path.
+func writeVarsVarFile(vars map[string]interface{}) (string, error) {
+	contents, err := formatVarFile(vars, false)
+	if err != nil {
+		return "", err
+	}
+
+	varFile, err := os.CreateTemp("", "*-terratest-vars.tfvars")
+	if err != nil {
+		return "", err
+	}
+	defer varFile.Close()
+
+	if _, err := varFile.WriteString(contents); err != nil {
+		os.Remove(varFile.Name())
+		return "", err
+	}
+	return varFile.Name(), nil
+}
+
+// formatVarFile returns the given variables as the HCL contents of a var file, sorted by name. If redact is true, the
+// values marked with Sensitive are replaced with a placeholder.
+func formatVarFile(vars map[string]interface{}, redact bool) (string, error) {
+	var builder strings.Builder
+	for _, name := range sortedKeys(vars) {
+		if !hclsyntax.ValidIdentifier(name) {
+			return "", fmt.Errorf("invalid variable name %q: variable names must be valid HCL identifiers", name)
+		}
+		builder.WriteString(name + " = ")
+		if err := writeHclValue(&builder, vars[name], "", redact); err != nil {
+			return "", fmt.Errorf("failed to convert variable %s to HCL: %w", name, err)
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String(), nil
+}
+
+// writeHclValue writes the given value as an HCL expression, indenting nested lines with the given indent. Unlike
+// toHclString, which formats values for -var flags, this escapes strings and fails on values it can't convert.
+func writeHclValue(builder *strings.Builder, value interface{}, indent string, redact bool) error {
+	if sensitive, isSensitive := value.(SensitiveValue); isSensitive {
+		if redact {
+			builder.WriteString(redactedValue)
+			return nil
+		}
+		return writeHclValue(builder, sensitive.Value, indent, redact)
+	}
+
+	if value == nil {
+		builder.WriteString("null")
+		return nil
+	}
+
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if reflectValue.IsNil() {
+			builder.WriteString("null")
+			return nil
+		}
+		return writeHclValue(builder, reflectValue.Elem().Interface(), indent, redact)
+
+	case reflect.Bool:
+		builder.WriteString(strconv.FormatBool(reflectValue.Bool()))
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		builder.WriteString(strconv.FormatInt(reflectValue.Int(), 10))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		builder.WriteString(strconv.FormatUint(reflectValue.Uint(), 10))
+
+	case reflect.Float32, reflect.Float64:
+		builder.WriteString(strconv.FormatFloat(reflectValue.Float(), 'f', -1, 64))
+
+	case reflect.String:
+		if number, isNumber := value.(json.Number); isNumber {
+			builder.WriteString(number.String())
+		} else {
+			writeHclString(builder, reflectValue.String())
+		}
+
+	case reflect.Slice, reflect.Array:
+		if reflectValue.Len() == 0 {
+			builder.WriteString("[]")
+			return nil
+		}
+		builder.WriteString("[\n")
+		for i := 0; i < reflectValue.Len(); i++ {
+			builder.WriteString(indent + "  ")
+			if err := writeHclValue(builder, reflectValue.Index(i).Interface(), indent+"  ", redact); err != nil {
+				return err
+			}
+			builder.WriteString(",\n")
+		}
+		builder.WriteString(indent + "]")
+
+	case reflect.Map:
+		genericMap, isMap := tryToConvertToGenericMap(value)
+		if !isMap {
+			return fmt.Errorf("unsupported map type %T: map keys must be strings", value)
+		}
+		if len(genericMap) == 0 {
+			builder.WriteString("{}")
+			return nil
+		}
+		builder.WriteString("{\n")
+		for _, key := range sortedKeys(genericMap) {
+			builder.WriteString(indent + "  ")
+			if hclsyntax.ValidIdentifier(key) {
+				builder.WriteString(key)
+			} else {
+				writeHclString(builder, key)
+			}
+			builder.WriteString(" = ")
+			if err := writeHclValue(builder, genericMap[key], indent+"  ", redact); err != nil {
+				return err
+			}
+			builder.WriteString("\n")
+		}
+		builder.WriteString(indent + "}")
+
+	default:
+		return fmt.Errorf("unsupported type %T", value)
+	}
+	return nil
+}
+
+// writeHclString writes the given string as a quoted HCL string, or as a heredoc if it spans multiple lines and ends in
+// a newline (as the value of a heredoc always ends in a newline). Template sequences (${ and %{) are escaped so that
+// the string is taken literally.
+func writeHclString(builder *strings.Builder, value string) {
+	escapedTemplates := strings.NewReplacer("${", "$${", "%{", "%%{").Replace(value)
+
+	lines := strings.Split(strings.TrimSuffix(escapedTemplates, "\n"), "\n")
+	if strings.HasSuffix(value, "\n") && len(lines) > 1 {
+		delimiter := getHeredocDelimiter(lines)
+		builder.WriteString("<<" + delimiter + "\n" + escapedTemplates + delimiter)
+		return
+	}
+
+	builder.WriteString(`"`)
+	for _, char := range escapedTemplates {
+		switch char {
+		case '"':
+			builder.WriteString(`\"`)
+		case '\\':
+			builder.WriteString(`\\`)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\r':
+			builder.WriteString(`\r`)
+		case '\t':
+			builder.WriteString(`\t`)
+		default:
+			if char < 0x20 {
+				fmt.Fprintf(builder, `\u%04x`, char)
+			} else {
+				builder.WriteRune(char)
+			}
+		}
+	}
+	builder.WriteString(`"`)
+}
+
+// getHeredocDelimiter returns a heredoc delimiter that doesn't appear as a line of the given heredoc.
+func getHeredocDelimiter(lines []string) string {
+	delimiter := "EOT"
+	for i := 0; containsTrimmedLine(lines, delimiter); i++ {
+		delimiter = fmt.Sprintf("EOT%d", i)
+	}
+	return delimiter
+}
+
+// containsTrimmedLine returns true if one of the given lines is the given value surrounded by whitespace.
+func containsTrimmedLine(lines []string, value string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the keys of the given map in alphabetical order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
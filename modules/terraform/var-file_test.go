@@ -3,6 +3,8 @@ package terraform
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/gruntwork-io/terratest/modules/random"
@@ -466,6 +468,157 @@ func TestGetAllVariablesFromVarFileStructOutJSON(t *testing.T) {
 	require.Equal(t, "us-east-2", region.AwsRegion)
 }
 
+func TestRenderVarFileTemplates(t *testing.T) {
+	t.Parallel()
+
+	templateFileName := fmt.Sprintf("./%s.tfvars.tmpl", random.UniqueId())
+	WriteFile(t, templateFileName, []byte(`ami_id = "{{ .AmiId }}"`))
+	defer os.Remove(templateFileName)
+
+	options := &Options{
+		TemplateData: map[string]string{"AmiId": "ami-123456"},
+	}
+
+	args, cleanup, err := renderVarFileTemplates(options, []string{"apply", "-var-file", templateFileName, "-var-file", "plain.tfvars"})
+	require.NoError(t, err)
+
+	renderedFile := args[2]
+	require.NotEqual(t, templateFileName, renderedFile)
+	require.True(t, strings.HasSuffix(renderedFile, ".tfvars"))
+	require.Equal(t, "plain.tfvars", args[4])
+	require.Equal(t, "ami-123456", GetVariableAsStringFromVarFile(t, renderedFile, "ami_id"))
+
+	cleanup()
+	require.NoFileExists(t, renderedFile)
+}
+
+func TestRenderVarFileTemplatesMissingKey(t *testing.T) {
+	t.Parallel()
+
+	templateFileName := fmt.Sprintf("./%s.tfvars.tmpl", random.UniqueId())
+	WriteFile(t, templateFileName, []byte(`ami_id = "{{ .AmiId }}"`))
+	defer os.Remove(templateFileName)
+
+	options := &Options{
+		TemplateData: map[string]string{},
+	}
+
+	_, _, err := renderVarFileTemplates(options, []string{"apply", "-var-file", templateFileName})
+	require.Error(t, err)
+}
+
+func TestWriteVarFile(t *testing.T) {
+	t.Parallel()
+
+	varFile := filepath.Join(t.TempDir(), "test.tfvars")
+	vars := map[string]interface{}{
+		"name":     `my "app" ${var.not_interpolated}`,
+		"count":    3,
+		"ratio":    0.5,
+		"enabled":  true,
+		"nothing":  nil,
+		"zones":    []string{"us-east-1a", "us-east-1b"},
+		"empty":    []int{},
+		"script":   "#!/bin/sh\necho \"EOT\"\nEOT\n",
+		"password": Sensitive("hunter2"),
+		"tags": map[string]interface{}{
+			"Name":          "test",
+			"kubernetes.io": map[string]int{"weight": 1},
+		},
+	}
+
+	WriteVarFile(t, varFile, vars)
+
+	var parsed map[string]interface{}
+	require.NoError(t, GetAllVariablesFromVarFileE(t, varFile, &parsed))
+	require.Equal(t, map[string]interface{}{
+		"name":     `my "app" ${var.not_interpolated}`,
+		"count":    float64(3),
+		"ratio":    0.5,
+		"enabled":  true,
+		"nothing":  nil,
+		"zones":    []interface{}{"us-east-1a", "us-east-1b"},
+		"empty":    []interface{}{},
+		"script":   "#!/bin/sh\necho \"EOT\"\nEOT\n",
+		"password": "hunter2",
+		"tags": map[string]interface{}{
+			"Name":          "test",
+			"kubernetes.io": map[string]interface{}{"weight": float64(1)},
+		},
+	}, parsed)
+
+	contents, err := os.ReadFile(varFile)
+	require.NoError(t, err)
+	require.Contains(t, string(contents), "script = <<EOT0\n")
+}
+
+func TestFormatVarFileRedactsSensitiveValues(t *testing.T) {
+	t.Parallel()
+
+	contents, err := formatVarFile(map[string]interface{}{"password": Sensitive("hunter2"), "user": "admin"}, true)
+	require.NoError(t, err)
+	require.Equal(t, "password = \"(sensitive value)\"\nuser = \"admin\"\n", contents)
+}
+
+func TestFormatVarFileInvalidValues(t *testing.T) {
+	t.Parallel()
+
+	_, err := formatVarFile(map[string]interface{}{"not valid": "foo"}, false)
+	require.Error(t, err)
+
+	_, err = formatVarFile(map[string]interface{}{"func": func() {}}, false)
+	require.Error(t, err)
+
+	_, err = formatVarFile(map[string]interface{}{"map": map[int]string{1: "foo"}}, false)
+	require.Error(t, err)
+}
+
+func TestRenderVarFileTemplatesWritesVarsInVarFile(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		Vars:          map[string]interface{}{"cnt": 2, "description": "multi\nline"},
+		VarsInVarFile: true,
+	}
+
+	formattedArgs := FormatArgs(options, "plan")
+	require.Equal(t, []string{"plan", "-input=false", "-var-file", varsVarFileArg, "-lock=false"}, formattedArgs)
+
+	args, cleanup, err := renderVarFileTemplates(options, formattedArgs)
+	require.NoError(t, err)
+
+	varFile := args[3]
+	require.NotEqual(t, varsVarFileArg, varFile)
+	require.Equal(t, "multi\nline", GetVariableAsStringFromVarFile(t, varFile, "description"))
+	require.Equal(t, "2", GetVariableAsStringFromVarFile(t, varFile, "cnt"))
+
+	cleanup()
+	require.NoFileExists(t, varFile)
+}
+
+func TestRenderVarFileTemplatesWritesSensitiveVarsInVarFile(t *testing.T) {
+	t.Parallel()
+
+	options := &Options{
+		Vars: map[string]interface{}{"cnt": 2, "password": Sensitive("hunter2")},
+	}
+
+	formattedArgs := FormatArgs(options, "plan")
+	require.Equal(t, []string{"plan", "-input=false", "-var", "cnt=2", "-var-file", sensitiveVarsVarFileArg, "-lock=false"}, formattedArgs)
+
+	args, cleanup, err := renderVarFileTemplates(options, formattedArgs)
+	require.NoError(t, err)
+
+	varFile := args[5]
+	require.NotEqual(t, sensitiveVarsVarFileArg, varFile)
+	require.Equal(t, "hunter2", GetVariableAsStringFromVarFile(t, varFile, "password"))
+	_, err = GetVariableAsStringFromVarFileE(t, varFile, "cnt")
+	require.Error(t, err)
+
+	cleanup()
+	require.NoFileExists(t, varFile)
+}
+
 // Helper function to write a file to the filesystem
 // Will immediately fail the test if it could not write the file
 func WriteFile(t *testing.T, fileName string, bytes []byte) {
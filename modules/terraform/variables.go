@@ -0,0 +1,148 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/stretchr/testify/require"
+)
+
+// AssertVariablesProvided checks that each of the given required variables is set through options.Vars,
+// options.VarFiles, a var file that Terraform loads automatically (terraform.tfvars and *.auto.tfvars in
+// options.TerraformDir) or a TF_VAR_ environment variable, and fails the test with the list of missing variables
+// otherwise. Call this before running Terraform to fail fast on a missing variable instead of getting a confusing
+// error (or a prompt) from the middle of a Terraform run.
+func AssertVariablesProvided(t testing.TestingT, options *Options, requiredVars []string) {
+	require.NoError(t, AssertVariablesProvidedE(t, options, requiredVars))
+}
+
+// AssertVariablesProvidedE checks that each of the given required variables is set through options.Vars,
+// options.VarFiles, a var file that Terraform loads automatically (terraform.tfvars and *.auto.tfvars in
+// options.TerraformDir) or a TF_VAR_ environment variable, and returns a MissingVariables error listing the missing
+// variables otherwise.
+func AssertVariablesProvidedE(t testing.TestingT, options *Options, requiredVars []string) error {
+	providedVars, err := getProvidedVariableNamesE(options)
+	if err != nil {
+		return err
+	}
+
+	missingVars := []string{}
+	for _, name := range requiredVars {
+		if !providedVars[name] {
+			missingVars = append(missingVars, name)
+		}
+	}
+	if len(missingVars) > 0 {
+		return MissingVariables{Names: missingVars}
+	}
+	return nil
+}
+
+// getProvidedVariableNamesE returns the names of all the variables that will be passed to Terraform with the given
+// options.
+func getProvidedVariableNamesE(options *Options) (map[string]bool, error) {
+	providedVars := map[string]bool{}
+
+	for name := range options.Vars {
+		providedVars[name] = true
+	}
+
+	for _, env := range os.Environ() {
+		if name, _, found := strings.Cut(env, "="); found && strings.HasPrefix(name, "TF_VAR_") {
+			providedVars[strings.TrimPrefix(name, "TF_VAR_")] = true
+		}
+	}
+	for name := range options.EnvVars {
+		if strings.HasPrefix(name, "TF_VAR_") {
+			providedVars[strings.TrimPrefix(name, "TF_VAR_")] = true
+		}
+	}
+
+	varFiles, err := getAutoLoadedVarFiles(options.TerraformDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, varFile := range options.VarFiles {
+		// Like Terraform, resolve relative var file paths against the working directory
+		if !filepath.IsAbs(varFile) && options.TerraformDir != "" {
+			varFile = filepath.Join(options.TerraformDir, varFile)
+		}
+		varFiles = append(varFiles, varFile)
+	}
+
+	for _, varFile := range varFiles {
+		names, err := getVarFileVariableNamesE(options, varFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			providedVars[name] = true
+		}
+	}
+
+	return providedVars, nil
+}
+
+// getAutoLoadedVarFiles returns the paths of the var files in the given directory that Terraform loads automatically.
+func getAutoLoadedVarFiles(terraformDir string) ([]string, error) {
+	varFiles := []string{}
+	for _, name := range []string{"terraform.tfvars", "terraform.tfvars.json"} {
+		path := filepath.Join(terraformDir, name)
+		if _, err := os.Stat(path); err == nil {
+			varFiles = append(varFiles, path)
+		}
+	}
+	for _, pattern := range []string{"*.auto.tfvars", "*.auto.tfvars.json"} {
+		matches, err := filepath.Glob(filepath.Join(terraformDir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		varFiles = append(varFiles, matches...)
+	}
+	return varFiles, nil
+}
+
+// getVarFileVariableNamesE returns the names of the variables set in the given var file, rendering it first if it is
+// a template.
+func getVarFileVariableNamesE(options *Options, varFile string) ([]string, error) {
+	if strings.HasSuffix(varFile, varFileTemplateExtension) {
+		renderedFile, err := renderVarFileTemplate(options, varFile)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(renderedFile)
+		varFile = renderedFile
+	}
+
+	fileContents, err := os.ReadFile(varFile)
+	if err != nil {
+		return nil, VarFileNotFound{Path: varFile}
+	}
+
+	parser := hclparse.NewParser()
+	var file *hcl.File
+	var diags hcl.Diagnostics
+	if strings.HasSuffix(varFile, ".json") {
+		file, diags = parser.ParseJSON(fileContents, varFile)
+	} else {
+		file, diags = parser.ParseHCL(fileContents, varFile)
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	attrs, diags := file.Body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	names := []string{}
+	for name := range attrs {
+		names = append(names, name)
+	}
+	return names, nil
+}
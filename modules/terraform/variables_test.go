@@ -0,0 +1,69 @@
+package terraform
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssertVariablesProvidedFailsFastOnMissingVariable(t *testing.T) {
+	t.Parallel()
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-basic-configuration", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir: testFolder,
+	}
+
+	err = AssertVariablesProvidedE(t, options, []string{"cnt"})
+	require.Error(t, err)
+	assert.Equal(t, MissingVariables{Names: []string{"cnt"}}, err)
+
+	// Without the pre-flight check, Terraform itself fails without waiting for input
+	_, err = InitAndPlanE(t, options)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "No value for required variable")
+
+	options.Vars = map[string]interface{}{"cnt": 1}
+	AssertVariablesProvided(t, options, []string{"cnt"})
+}
+
+func TestAssertVariablesProvidedChecksAllSources(t *testing.T) {
+	t.Parallel()
+
+	testFolder := t.TempDir()
+	WriteFile(t, filepath.Join(testFolder, "terraform.tfvars"), []byte(`from_tfvars = "a"`))
+	WriteFile(t, filepath.Join(testFolder, "extra.auto.tfvars.json"), []byte(`{"from_auto_tfvars": "b"}`))
+	WriteFile(t, filepath.Join(testFolder, "explicit.tfvars"), []byte(`from_var_file = "c"`))
+	WriteFile(t, filepath.Join(testFolder, "template.tfvars.tmpl"), []byte(`from_template = "{{ .Value }}"`))
+
+	options := &Options{
+		TerraformDir: testFolder,
+		Vars:         map[string]interface{}{"from_vars": "d"},
+		VarFiles:     []string{"explicit.tfvars", "template.tfvars.tmpl"},
+		TemplateData: map[string]string{"Value": "e"},
+		EnvVars:      map[string]string{"TF_VAR_from_env": "f"},
+	}
+
+	AssertVariablesProvided(t, options, []string{"from_tfvars", "from_auto_tfvars", "from_var_file", "from_template", "from_vars", "from_env"})
+
+	err := AssertVariablesProvidedE(t, options, []string{"from_vars", "missing_one", "missing_two"})
+	assert.Equal(t, MissingVariables{Names: []string{"missing_one", "missing_two"}}, err)
+}
+
+func TestAssertVariablesProvidedMissingVarFile(t *testing.T) {
+	t.Parallel()
+
+	testFolder := t.TempDir()
+	options := &Options{
+		TerraformDir: testFolder,
+		VarFiles:     []string{"missing.tfvars"},
+	}
+
+	err := AssertVariablesProvidedE(t, options, []string{"foo"})
+	assert.Equal(t, VarFileNotFound{Path: filepath.Join(testFolder, "missing.tfvars")}, err)
+}
@@ -39,10 +39,54 @@ func WorkspaceSelectOrNewE(t testing.TestingT, options *Options, name string) (s
 	return RunTerraformCommandE(t, options, "workspace", "show")
 }
 
+// WorkspaceNew runs terraform workspace new with the given options and the workspace name, and returns the name of
+// the current workspace, which is the new workspace. This will fail the test if the workspace already exists.
+func WorkspaceNew(t testing.TestingT, options *Options, name string) string {
+	out, err := WorkspaceNewE(t, options, name)
+	require.NoError(t, err)
+	return out
+}
+
+// WorkspaceNewE runs terraform workspace new with the given options and the workspace name, and returns the name of
+// the current workspace, which is the new workspace. Returns an error if the workspace already exists.
+func WorkspaceNewE(t testing.TestingT, options *Options, name string) (string, error) {
+	if _, err := RunTerraformCommandE(t, options, "workspace", "new", name); err != nil {
+		return "", err
+	}
+	return RunTerraformCommandE(t, options, "workspace", "show")
+}
+
+// WorkspaceList runs terraform workspace list with the given options and returns the names of all the workspaces.
+func WorkspaceList(t testing.TestingT, options *Options) []string {
+	workspaces, err := WorkspaceListE(t, options)
+	require.NoError(t, err)
+	return workspaces
+}
+
+// WorkspaceListE runs terraform workspace list with the given options and returns the names of all the workspaces.
+func WorkspaceListE(t testing.TestingT, options *Options) ([]string, error) {
+	out, err := RunTerraformCommandE(t, options, "workspace", "list")
+	if err != nil {
+		return nil, err
+	}
+	return parseWorkspaceList(out), nil
+}
+
+// parseWorkspaceList parses the output of terraform workspace list, where the current workspace is marked with a *.
+func parseWorkspaceList(out string) []string {
+	workspaces := []string{}
+	for _, line := range strings.Split(out, "\n") {
+		workspace := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*"))
+		if workspace != "" {
+			workspaces = append(workspaces, workspace)
+		}
+	}
+	return workspaces
+}
+
 func isExistingWorkspace(out string, name string) bool {
-	workspaces := strings.Split(out, "\n")
-	for _, ws := range workspaces {
-		if strings.HasSuffix(ws, name) {
+	for _, workspace := range parseWorkspaceList(out) {
+		if workspace == name {
 			return true
 		}
 	}
@@ -54,6 +98,26 @@ func isExistingWorkspace(out string, name string) bool {
 // If the workspace to delete is the current one, then it tries to switch to the "default" workspace.
 // Deleting the workspace "default" is not supported.
 func WorkspaceDeleteE(t testing.TestingT, options *Options, name string) (string, error) {
+	return workspaceDeleteE(t, options, name, false)
+}
+
+// WorkspaceForceDeleteE works like WorkspaceDeleteE, but also deletes the workspace if its state still tracks
+// resources (terraform workspace delete -force). The resources themselves are not destroyed, so only use this after
+// running destroy, or for resources that are cleaned up in some other way.
+func WorkspaceForceDeleteE(t testing.TestingT, options *Options, name string) (string, error) {
+	return workspaceDeleteE(t, options, name, true)
+}
+
+// WorkspaceForceDelete works like WorkspaceDelete, but also deletes the workspace if its state still tracks resources
+// (terraform workspace delete -force). The resources themselves are not destroyed, so only use this after running
+// destroy, or for resources that are cleaned up in some other way.
+func WorkspaceForceDelete(t testing.TestingT, options *Options, name string) string {
+	out, err := WorkspaceForceDeleteE(t, options, name)
+	require.NoError(t, err)
+	return out
+}
+
+func workspaceDeleteE(t testing.TestingT, options *Options, name string, force bool) (string, error) {
 	currentWorkspace, err := RunTerraformCommandE(t, options, "workspace", "show")
 	if err != nil {
 		return currentWorkspace, err
@@ -80,7 +144,11 @@ func WorkspaceDeleteE(t testing.TestingT, options *Options, name string) (string
 	}
 
 	// delete workspace
-	_, err = RunTerraformCommandE(t, options, "workspace", "delete", name)
+	args := []string{"workspace", "delete"}
+	if force {
+		args = append(args, "-force")
+	}
+	_, err = RunTerraformCommandE(t, options, append(args, name)...)
 
 	return currentWorkspace, err
 }
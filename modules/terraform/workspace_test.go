@@ -81,6 +81,75 @@ func TestWorkspaceApply(t *testing.T) {
 	assert.Contains(t, out, "Hello, Terratest")
 }
 
+func TestWorkspaceNewAndList(t *testing.T) {
+	t.Parallel()
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-workspace", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir: testFolder,
+	}
+
+	assert.Equal(t, "staging", WorkspaceNew(t, options, "staging"))
+	assert.Equal(t, "production", WorkspaceNew(t, options, "production"))
+	assert.ElementsMatch(t, []string{"default", "staging", "production"}, WorkspaceList(t, options))
+
+	_, err = WorkspaceNewE(t, options, "staging")
+	assert.Error(t, err)
+}
+
+func TestWorkspaceOption(t *testing.T) {
+	t.Parallel()
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-workspace", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir: testFolder,
+		Workspace:    "Terratest",
+	}
+
+	InitAndApply(t, options)
+	assert.Equal(t, "Hello, Terratest", Output(t, options, "test"))
+
+	// The workspace applies to all commands, even if another workspace is selected in the meantime
+	WorkspaceSelectOrNew(t, options, "other")
+	assert.Equal(t, "Hello, Terratest", Output(t, options, "test"))
+}
+
+func TestWorkspaceForceDelete(t *testing.T) {
+	t.Parallel()
+
+	testFolder, err := files.CopyTerraformFolderToTemp("../../test/fixtures/terraform-basic-configuration", t.Name())
+	require.NoError(t, err)
+
+	options := &Options{
+		TerraformDir: testFolder,
+		Vars:         map[string]interface{}{"cnt": 1},
+	}
+
+	InitAndApply(t, options)
+	WorkspaceNew(t, options, "staging")
+	_, err = RunTerraformCommandE(t, options, FormatArgs(options, "apply", "-auto-approve")...)
+	require.NoError(t, err)
+
+	// The workspace still tracks resources, so it can only be deleted with -force
+	WorkspaceSelectOrNew(t, options, "default")
+	_, err = WorkspaceDeleteE(t, options, "staging")
+	require.Error(t, err)
+
+	assert.Equal(t, "default", WorkspaceForceDelete(t, options, "staging"))
+	assert.Equal(t, []string{"default"}, WorkspaceList(t, options))
+}
+
+func TestParseWorkspaceList(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"default", "foo", "foobar"}, parseWorkspaceList("  default\n* foo\n  foobar\n"))
+	assert.Equal(t, []string{}, parseWorkspaceList(""))
+}
+
 func TestIsExistingWorkspace(t *testing.T) {
 	t.Parallel()
 
@@ -100,6 +169,7 @@ func TestIsExistingWorkspace(t *testing.T) {
 		{"  default\n* foo\n", "foobar", false},
 		{"* default\n  foo\n", "foobar", false},
 		{"* default\n  foo\n", "foo", true},
+		{"* default\n  foobar\n", "bar", false},
 	}
 
 	for _, testCase := range testCases {
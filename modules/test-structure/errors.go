@@ -0,0 +1,74 @@
+package test_structure
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TestDataSchemaMismatch occurs when the test data loaded by Load was saved with a different type or schema version,
+// e.g. by an older test binary
+type TestDataSchemaMismatch struct {
+	Path            string
+	ExpectedType    string
+	ExpectedVersion int
+	ActualType      string
+	ActualVersion   int
+}
+
+func (err TestDataSchemaMismatch) Error() string {
+	return fmt.Sprintf(
+		"test data in %s was saved as %s (schema version %d), but is loaded as %s (schema version %d): delete it and rerun the earlier test stages",
+		err.Path, err.ActualType, err.ActualVersion, err.ExpectedType, err.ExpectedVersion,
+	)
+}
+
+// PipelineStageNotFound occurs when a stage of a pipeline depends on a stage that is not in the pipeline
+type PipelineStageNotFound struct {
+	Stage        string
+	DependencyOf string
+}
+
+func (err PipelineStageNotFound) Error() string {
+	return fmt.Sprintf("stage %s, a dependency of stage %s, is not in the pipeline", err.Stage, err.DependencyOf)
+}
+
+// PipelineDependencyCycle occurs when the stages of a pipeline depend on each other. It contains the stages of the
+// cycle.
+type PipelineDependencyCycle []string
+
+func (err PipelineDependencyCycle) Error() string {
+	return fmt.Sprintf("the stages of the pipeline have a dependency cycle: %s", strings.Join(err, " -> "))
+}
+
+// PipelineStageOutputsMissing occurs when a stage of a pipeline is skipped, but the test data it saves for the stages
+// that depend on it doesn't exist
+type PipelineStageOutputsMissing struct {
+	Stage string
+	Paths []string
+}
+
+func (err PipelineStageOutputsMissing) Error() string {
+	return fmt.Sprintf("stage %s was skipped, but its outputs are missing: %s. Run the stage first.", err.Stage, strings.Join(err.Paths, ", "))
+}
+
+// UnsupportedStageReportFormat occurs when WriteStageReport is called with a format it doesn't support
+type UnsupportedStageReportFormat string
+
+func (err UnsupportedStageReportFormat) Error() string {
+	return fmt.Sprintf("unsupported stage report format %q: expected %s or %s", string(err), StageReportJSON, StageReportJUnit)
+}
+
+// StaleTestData occurs when the test data being loaded was saved longer ago than the MaxAge of the DataStore
+type StaleTestData struct {
+	Path   string
+	Age    time.Duration
+	MaxAge time.Duration
+}
+
+func (err StaleTestData) Error() string {
+	return fmt.Sprintf(
+		"stale test data: %s was saved %s ago, which is more than the max age of %s. Re-run the stage that saves it.",
+		err.Path, err.Age.Round(time.Second), err.MaxAge,
+	)
+}
@@ -0,0 +1,376 @@
+package test_structure
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// Pipeline is a set of test stages (e.g. build_ami, deploy, validate and teardown) that depend on each other.
+// RunPipeline runs the stages in the order of their dependencies, skips the stages for which `SKIP_<stageName>` is
+// set or that the stage config of the test skips (as RunTestStage does) after checking that the outputs they would
+// have saved exist, and logs how long each stage took. This makes the order of the stages and the test data they
+// share explicit, instead of relying on the order of RunTestStage calls (and defers) in the test.
+type Pipeline struct {
+	stages map[string]*PipelineStage
+	names  []string // The names of the stages, in the order they were added
+	// The results of the stages that RunPipeline ran or skipped, in the order they were run
+	results []StageResult
+	// The names of the stages that succeeded in the current run of RunPipeline or in an earlier one
+	succeeded []string
+	// The path of the file in which RunPipeline records the stages that succeeded when the pipeline fails
+	resumeFile string
+}
+
+// PipelineStage is a stage of a Pipeline.
+type PipelineStage struct {
+	Name               string        // The name of the stage, which is also used for its `SKIP_<stageName>` environment variable
+	Run                func()        // The function that runs the stage
+	RunE               func() error  // The function that runs the stage and returns an error if it fails, used instead of Run if set
+	DependsOn          []string      // The names of the stages that must run before this stage
+	Outputs            []string      // The paths of the test data (e.g. from FormatTestDataPath) that the stage saves for the stages that depend on it
+	MaxRetries         int           // How many times to retry the stage if it fails
+	TimeBetweenRetries time.Duration // How long to wait before the first retry, which doubles after each retry
+}
+
+// pipelineResumePoint is the format of the resume file of a pipeline.
+type pipelineResumePoint struct {
+	Succeeded []string `json:"succeeded"` // The names of the stages that succeeded
+}
+
+// NewPipeline returns an empty Pipeline.
+func NewPipeline() *Pipeline {
+	return &Pipeline{stages: map[string]*PipelineStage{}}
+}
+
+// Stage adds a stage with the given name (which must be unique in the pipeline) to the pipeline, which runs the given
+// function after the given stages, and returns it, e.g. to declare its outputs.
+func (pipeline *Pipeline) Stage(name string, run func(), dependsOn ...string) *PipelineStage {
+	if _, exists := pipeline.stages[name]; !exists {
+		pipeline.names = append(pipeline.names, name)
+	}
+
+	stage := &PipelineStage{
+		Name:      name,
+		Run:       run,
+		DependsOn: dependsOn,
+	}
+	pipeline.stages[name] = stage
+	return stage
+}
+
+// StageE adds a stage with the given name (which must be unique in the pipeline) to the pipeline, which runs the given
+// function after the given stages, and returns it. The stage fails if the function returns an error, which (unlike
+// failing the test through t, e.g. with require) allows a retry of the stage (see WithRetries) to succeed.
+func (pipeline *Pipeline) StageE(name string, run func() error, dependsOn ...string) *PipelineStage {
+	stage := pipeline.Stage(name, nil, dependsOn...)
+	stage.RunE = run
+	return stage
+}
+
+// WithOutputs declares the paths of the test data that the stage saves for the stages that depend on it. When the stage
+// is skipped, RunPipeline checks that this test data exists (e.g. from an earlier run) before running the next stages.
+// Returns the stage to allow chaining calls.
+func (stage *PipelineStage) WithOutputs(paths ...string) *PipelineStage {
+	stage.Outputs = append(stage.Outputs, paths...)
+	return stage
+}
+
+// WithRetries retries the stage up to maxRetries times if it fails, waiting timeBetweenRetries before the first retry
+// and twice as long before each retry after that. Note that a stage that fails the test through t (e.g. with require,
+// or a non-E Terratest function) stops the test and is not retried, so stages with retries should be added with StageE.
+// Returns the stage to allow chaining calls.
+func (stage *PipelineStage) WithRetries(maxRetries int, timeBetweenRetries time.Duration) *PipelineStage {
+	stage.MaxRetries = maxRetries
+	stage.TimeBetweenRetries = timeBetweenRetries
+	return stage
+}
+
+// WithResumeFile sets the path of the resume file of the pipeline (e.g. FormatTestDataPath(testFolder,
+// "pipeline.json")). When the pipeline fails, RunPipeline records the stages that succeeded in this file, and the next
+// run of the pipeline skips them (after checking that their outputs exist), so that re-running a failed test locally
+// continues from the stage that failed without setting `SKIP_<stageName>` environment variables. The file is deleted
+// when the pipeline succeeds. Returns the pipeline to allow chaining calls.
+func (pipeline *Pipeline) WithResumeFile(path string) *Pipeline {
+	pipeline.resumeFile = path
+	return pipeline
+}
+
+// GetResults returns the results of the stages that RunPipeline ran or skipped, in the order they were run.
+func (pipeline *Pipeline) GetResults() []StageResult {
+	return pipeline.results
+}
+
+// RunPipeline runs the stages of the given pipeline, every stage after the stages it depends on, and otherwise in the
+// order they were added. A stage is skipped if the `SKIP_<stageName>` environment variable is set or the stage config
+// of the test (see StageConfig) skips it, in which case the outputs it declares must already exist. Stages that
+// succeeded in an earlier run that failed are skipped the same way if the pipeline has a resume file (see
+// WithResumeFile), and failed stages are retried as configured with WithRetries. This will fail the test if the
+// dependencies of the stages are invalid, if a stage fails, or if the outputs of a skipped stage are missing, without
+// running the stages that follow. The time each stage took is logged at the end.
+func RunPipeline(t testing.TestingT, pipeline *Pipeline) {
+	require.NoError(t, RunPipelineE(t, pipeline))
+}
+
+// RunPipelineE runs the stages of the given pipeline, every stage after the stages it depends on, and otherwise in the
+// order they were added. A stage is skipped if the `SKIP_<stageName>` environment variable is set or the stage config
+// of the test (see StageConfig) skips it, in which case the outputs it declares must already exist. Stages that
+// succeeded in an earlier run that failed are skipped the same way if the pipeline has a resume file (see
+// WithResumeFile), and failed stages are retried as configured with WithRetries. Returns an error, without running the
+// stages that follow, if a stage fails or if the outputs of a skipped stage are missing. The time each stage took is
+// logged at the end.
+func RunPipelineE(t testing.TestingT, pipeline *Pipeline) error {
+	order, err := pipeline.getTopologicalOrder()
+	if err != nil {
+		return err
+	}
+	resumePoint, err := pipeline.readResumeFile(t)
+	if err != nil {
+		return err
+	}
+
+	pipeline.results = []StageResult{}
+	pipeline.succeeded = []string{}
+	completed := false
+	// The stages run on the goroutine of the test, so the timings are logged and the resume file is written in a defer,
+	// which also runs when a stage fails the test with t.FailNow
+	defer func() {
+		logger.Default.Logf(t, "Pipeline stage timings:\n%s", formatStageResults(pipeline.results))
+	}()
+	defer func() {
+		if completed || pipeline.resumeFile == "" {
+			return
+		}
+		if writeErr := pipeline.writeResumeFile(t, pipelineResumePoint{Succeeded: pipeline.succeeded}); writeErr != nil {
+			logger.Default.Logf(t, "Failed to write resume file %s: %v", pipeline.resumeFile, writeErr)
+		}
+	}()
+
+	if err := pipeline.runStages(t, order, resumePoint.Succeeded); err != nil {
+		return err
+	}
+	completed = true
+	if pipeline.resumeFile == "" {
+		return nil
+	}
+	if err := os.Remove(pipeline.resumeFile); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// runStages runs the stages with the given names, in order, skipping the ones that succeeded in an earlier run, and
+// records the names of the stages that succeeded in this run or an earlier one in the pipeline.
+func (pipeline *Pipeline) runStages(t testing.TestingT, order []string, succeededEarlier []string) error {
+	for _, name := range order {
+		stage := pipeline.stages[name]
+
+		skipReason, err := getStageSkipReason(t, name)
+		if err != nil {
+			return err
+		}
+		if skipReason == "" && containsStageName(succeededEarlier, name) {
+			skipReason = fmt.Sprintf("The resume file %s shows that the stage succeeded in an earlier run", pipeline.resumeFile)
+			pipeline.succeeded = append(pipeline.succeeded, name)
+		}
+		if skipReason != "" {
+			logger.Default.Logf(t, "%s, so skipping stage '%s'.", skipReason, name)
+			pipeline.recordResult(t, StageResult{Name: name, Start: time.Now(), Skipped: true})
+			if missing := getMissingTestData(t, stage.Outputs); len(missing) > 0 {
+				return PipelineStageOutputsMissing{Stage: name, Paths: missing}
+			}
+			continue
+		}
+
+		envVarName := fmt.Sprintf("%s%s", SKIP_STAGE_ENV_VAR_PREFIX, name)
+		logger.Default.Logf(t, "The '%s' environment variable is not set, so executing stage '%s'.", envVarName, name)
+		if err := pipeline.runAndRecordStage(t, stage); err != nil {
+			return err
+		}
+		pipeline.succeeded = append(pipeline.succeeded, name)
+	}
+	return nil
+}
+
+// runAndRecordStage runs the given stage, retrying it as configured with WithRetries, and records its result. The
+// result is recorded in a defer, so that a stage that fails the test with t.FailNow (which stops the goroutine of the
+// test through runtime.Goexit) is recorded as failed too.
+func (pipeline *Pipeline) runAndRecordStage(t testing.TestingT, stage *PipelineStage) (err error) {
+	result := StageResult{Name: stage.Name, Start: time.Now()}
+	completed := false
+	defer func() {
+		result.Duration = time.Since(result.Start)
+		if !completed {
+			result.Failed = true
+			result.Error = fmt.Sprintf("stage '%s' failed the test", stage.Name)
+		} else if err != nil {
+			result.Failed = true
+			result.Error = err.Error()
+		}
+		pipeline.recordResult(t, result)
+	}()
+
+	err = runPipelineStageWithRetries(t, stage)
+	completed = true
+	return err
+}
+
+// readResumeFile reads the resume file of the pipeline, if it has one and it exists.
+func (pipeline *Pipeline) readResumeFile(t testing.TestingT) (pipelineResumePoint, error) {
+	resumePoint := pipelineResumePoint{}
+	if pipeline.resumeFile == "" {
+		return resumePoint, nil
+	}
+
+	contents, err := os.ReadFile(pipeline.resumeFile)
+	if os.IsNotExist(err) {
+		return resumePoint, nil
+	}
+	if err != nil {
+		return resumePoint, err
+	}
+	if err := json.Unmarshal(contents, &resumePoint); err != nil {
+		return resumePoint, fmt.Errorf("failed to parse resume file %s: %w", pipeline.resumeFile, err)
+	}
+
+	logger.Default.Logf(t, "Resuming the pipeline from %s, in which these stages succeeded: %s", pipeline.resumeFile, strings.Join(resumePoint.Succeeded, ", "))
+	return resumePoint, nil
+}
+
+// writeResumeFile writes the given resume point to the resume file of the pipeline.
+func (pipeline *Pipeline) writeResumeFile(t testing.TestingT, resumePoint pipelineResumePoint) error {
+	logger.Default.Logf(t, "Writing the stages that succeeded to %s, so that the next run resumes from the stage that failed", pipeline.resumeFile)
+
+	contents, err := json.Marshal(resumePoint)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(pipeline.resumeFile), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(pipeline.resumeFile, contents, 0644)
+}
+
+// recordResult records the given stage result in the results of the pipeline and in the stage report of the test.
+func (pipeline *Pipeline) recordResult(t testing.TestingT, result StageResult) {
+	pipeline.results = append(pipeline.results, result)
+	recordStageResult(t, result)
+}
+
+// runPipelineStageWithRetries runs the given stage, and retries it as configured with WithRetries if it fails.
+func runPipelineStageWithRetries(t testing.TestingT, stage *PipelineStage) error {
+	sleepBetweenRetries := stage.TimeBetweenRetries
+	for retry := 1; ; retry++ {
+		err := runPipelineStage(stage)
+		if err == nil || retry > stage.MaxRetries {
+			return err
+		}
+
+		logger.Default.Logf(t, "%v. Sleeping for %s and will try again (retry %d of %d).", err, sleepBetweenRetries, retry, stage.MaxRetries)
+		time.Sleep(sleepBetweenRetries)
+		sleepBetweenRetries *= 2
+	}
+}
+
+// runPipelineStage runs the given stage on the goroutine of the test, as t.FailNow must be called from it, and returns
+// an error if the stage returns one or panics.
+func runPipelineStage(stage *PipelineStage) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("stage '%s' panicked: %v", stage.Name, recovered)
+		}
+	}()
+
+	if stage.RunE == nil {
+		stage.Run()
+		return nil
+	}
+	if err := stage.RunE(); err != nil {
+		return fmt.Errorf("stage '%s' failed: %w", stage.Name, err)
+	}
+	return nil
+}
+
+// getMissingTestData returns the given paths at which there is no test data.
+func getMissingTestData(t testing.TestingT, paths []string) []string {
+	missing := []string{}
+	for _, path := range paths {
+		if !IsTestDataPresent(t, path) {
+			missing = append(missing, path)
+		}
+	}
+	return missing
+}
+
+// formatStageResults formats the given stage results as a table for the logs.
+func formatStageResults(results []StageResult) string {
+	nameWidth := 0
+	for _, result := range results {
+		if len(result.Name) > nameWidth {
+			nameWidth = len(result.Name)
+		}
+	}
+
+	var builder strings.Builder
+	var total time.Duration
+	for _, result := range results {
+		status := "ran"
+		if result.Skipped {
+			status = "skipped"
+		} else if result.Failed {
+			status = "failed"
+		}
+		fmt.Fprintf(&builder, "  %-*s  %-7s  %s\n", nameWidth, result.Name, status, result.Duration.Round(time.Millisecond))
+		total += result.Duration
+	}
+	fmt.Fprintf(&builder, "  %-*s  %-7s  %s", nameWidth, "total", "", total.Round(time.Millisecond))
+	return builder.String()
+}
+
+// getTopologicalOrder returns the names of the stages of the pipeline, with every stage after the stages it depends
+// on, and otherwise in the order they were added.
+func (pipeline *Pipeline) getTopologicalOrder() ([]string, error) {
+	order := []string{}
+	visited := map[string]bool{}
+	visiting := map[string]bool{}
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		if visited[name] {
+			return nil
+		}
+		path = append(path, name)
+		if visiting[name] {
+			return PipelineDependencyCycle(path)
+		}
+		visiting[name] = true
+
+		for _, dependency := range pipeline.stages[name].DependsOn {
+			if _, exists := pipeline.stages[dependency]; !exists {
+				return PipelineStageNotFound{Stage: dependency, DependencyOf: name}
+			}
+			if err := visit(dependency, path); err != nil {
+				return err
+			}
+		}
+
+		visiting[name] = false
+		visited[name] = true
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range pipeline.names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
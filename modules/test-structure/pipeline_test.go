@@ -0,0 +1,180 @@
+package test_structure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunPipelineRunsStagesInDependencyOrder(t *testing.T) {
+	t.Parallel()
+
+	ran := []string{}
+	pipeline := NewPipeline()
+	pipeline.Stage("validate", func() { ran = append(ran, "validate") }, "deploy")
+	pipeline.Stage("deploy", func() { ran = append(ran, "deploy") }, "build_ami", "network")
+	pipeline.Stage("build_ami", func() { ran = append(ran, "build_ami") })
+	pipeline.Stage("network", func() { ran = append(ran, "network") })
+
+	RunPipeline(t, pipeline)
+	assert.Equal(t, []string{"build_ami", "network", "deploy", "validate"}, ran)
+
+	results := pipeline.GetResults()
+	require.Len(t, results, 4)
+	assert.Equal(t, "build_ami", results[0].Name)
+	assert.False(t, results[0].Skipped)
+	assert.False(t, results[0].Failed)
+}
+
+func TestRunPipelineEStopsAtFailedStage(t *testing.T) {
+	t.Parallel()
+
+	ran := []string{}
+	pipeline := NewPipeline()
+	pipeline.Stage("build_ami", func() { panic("packer build failed") })
+	pipeline.Stage("deploy", func() { ran = append(ran, "deploy") }, "build_ami")
+
+	err := RunPipelineE(t, pipeline)
+	assert.ErrorContains(t, err, "packer build failed")
+	assert.Empty(t, ran)
+	results := pipeline.GetResults()
+	require.Len(t, results, 1)
+	assert.True(t, results[0].Failed)
+	assert.Contains(t, results[0].Error, "packer build failed")
+	assert.Equal(t, results, GetStageResults(t))
+}
+
+func TestRunPipelineEValidatesDependencies(t *testing.T) {
+	t.Parallel()
+
+	pipeline := NewPipeline()
+	pipeline.Stage("deploy", func() {}, "build_ami")
+	assert.ErrorAs(t, RunPipelineE(t, pipeline), new(PipelineStageNotFound))
+
+	pipeline.Stage("build_ami", func() {}, "validate")
+	pipeline.Stage("validate", func() {}, "deploy")
+	var cycle PipelineDependencyCycle
+	require.ErrorAs(t, RunPipelineE(t, pipeline), &cycle)
+	assert.Equal(t, PipelineDependencyCycle{"deploy", "build_ami", "validate", "deploy"}, cycle)
+}
+
+func TestRunPipelineEChecksOutputsOfSkippedStages(t *testing.T) {
+	t.Setenv("SKIP_pipeline_build_ami", "true")
+
+	testFolder := t.TempDir()
+	ran := []string{}
+	pipeline := NewPipeline()
+	pipeline.Stage("pipeline_build_ami", func() {
+		ran = append(ran, "pipeline_build_ami")
+		SaveAmiId(t, testFolder, "ami-1234")
+	}).WithOutputs(formatNamedTestDataPath(testFolder, "AMI"))
+	pipeline.Stage("pipeline_deploy", func() { ran = append(ran, "pipeline_deploy") }, "pipeline_build_ami")
+
+	var missing PipelineStageOutputsMissing
+	require.ErrorAs(t, RunPipelineE(t, pipeline), &missing)
+	assert.Equal(t, []string{formatNamedTestDataPath(testFolder, "AMI")}, missing.Paths)
+	assert.Empty(t, ran)
+
+	SaveAmiId(t, testFolder, "ami-1234")
+	require.NoError(t, RunPipelineE(t, pipeline))
+	assert.Equal(t, []string{"pipeline_deploy"}, ran)
+	assert.True(t, pipeline.GetResults()[0].Skipped)
+}
+
+func TestRunPipelineERetriesFailedStages(t *testing.T) {
+	t.Parallel()
+
+	attempts := 0
+	pipeline := NewPipeline()
+	pipeline.StageE("deploy", func() error {
+		attempts++
+		if attempts < 3 {
+			return fmt.Errorf("attempt %d failed", attempts)
+		}
+		return nil
+	}).WithRetries(2, time.Millisecond)
+
+	require.NoError(t, RunPipelineE(t, pipeline))
+	assert.Equal(t, 3, attempts)
+	assert.False(t, pipeline.GetResults()[0].Failed)
+
+	attempts = -10
+	err := RunPipelineE(t, pipeline)
+	assert.ErrorContains(t, err, "stage 'deploy' failed: attempt -7 failed")
+	assert.Equal(t, -7, attempts)
+}
+
+func TestRunPipelineEResumesFromFailedStage(t *testing.T) {
+	t.Parallel()
+
+	resumeFile := filepath.Join(t.TempDir(), "pipeline.json")
+	ran := []string{}
+	validateErr := errors.New("endpoint is not healthy")
+	pipeline := NewPipeline().WithResumeFile(resumeFile)
+	pipeline.Stage("build_ami", func() { ran = append(ran, "build_ami") })
+	pipeline.Stage("deploy", func() { ran = append(ran, "deploy") }, "build_ami")
+	pipeline.StageE("validate", func() error {
+		ran = append(ran, "validate")
+		return validateErr
+	}, "deploy")
+
+	require.ErrorIs(t, RunPipelineE(t, pipeline), validateErr)
+	assert.Equal(t, []string{"build_ami", "deploy", "validate"}, ran)
+	assert.FileExists(t, resumeFile)
+
+	ran = []string{}
+	validateErr = nil
+	require.NoError(t, RunPipelineE(t, pipeline))
+	assert.Equal(t, []string{"validate"}, ran)
+	assert.True(t, pipeline.GetResults()[0].Skipped)
+	assert.NoFileExists(t, resumeFile)
+}
+
+// failNowT is a TestingT whose FailNow stops the goroutine it is called from, like the one of testing.T does, without
+// failing the test that runs it.
+type failNowT struct {
+	*testing.T
+	failed bool
+}
+
+func (t *failNowT) FailNow() {
+	t.failed = true
+	runtime.Goexit()
+}
+
+func TestRunPipelineERecordsStageThatFailsTheTest(t *testing.T) {
+	t.Parallel()
+
+	resumeFile := filepath.Join(t.TempDir(), "pipeline.json")
+	fakeT := &failNowT{T: t}
+	ran := []string{}
+	pipeline := NewPipeline().WithResumeFile(resumeFile)
+	pipeline.Stage("build_ami", func() { ran = append(ran, "build_ami") })
+	pipeline.Stage("deploy", func() { fakeT.FailNow() }, "build_ami")
+	pipeline.Stage("validate", func() { ran = append(ran, "validate") }, "deploy")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		RunPipelineE(fakeT, pipeline)
+	}()
+	<-done
+
+	assert.True(t, fakeT.failed)
+	assert.Equal(t, []string{"build_ami"}, ran)
+	results := pipeline.GetResults()
+	require.Len(t, results, 2)
+	assert.True(t, results[1].Failed)
+	assert.Equal(t, "stage 'deploy' failed the test", results[1].Error)
+
+	contents, err := os.ReadFile(resumeFile)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"succeeded": ["build_ami"]}`, string(contents))
+}
@@ -1,13 +1,16 @@
 package test_structure
 
 import (
-	"encoding/json"
-	"fmt"
+	"errors"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/gruntwork-io/terratest/modules/aws"
-	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/gruntwork-io/terratest/modules/docker"
+	"github.com/gruntwork-io/terratest/modules/helm"
 	"github.com/gruntwork-io/terratest/modules/k8s"
 	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/packer"
@@ -40,7 +43,7 @@ func LoadTerraformOptions(t testing.TestingT, testFolder string) *terraform.Opti
 
 // formatTerraformOptionsPath formats a path to save TerraformOptions in the given folder.
 func formatTerraformOptionsPath(testFolder string) string {
-	return FormatTestDataPath(testFolder, "TerraformOptions.json")
+	return formatNamedTestDataPath(testFolder, "TerraformOptions")
 }
 
 // SavePackerOptions serializes and saves PackerOptions into the given folder. This allows you to create PackerOptions during setup
@@ -59,7 +62,7 @@ func LoadPackerOptions(t testing.TestingT, testFolder string) *packer.Options {
 
 // formatPackerOptionsPath formats a path to save PackerOptions in the given folder.
 func formatPackerOptionsPath(testFolder string) string {
-	return FormatTestDataPath(testFolder, "PackerOptions.json")
+	return formatNamedTestDataPath(testFolder, "PackerOptions")
 }
 
 // SaveEc2KeyPair serializes and saves an Ec2KeyPair into the given folder. This allows you to create an Ec2KeyPair during setup
@@ -78,7 +81,7 @@ func LoadEc2KeyPair(t testing.TestingT, testFolder string) *aws.Ec2Keypair {
 
 // formatEc2KeyPairPath formats a path to save an Ec2KeyPair in the given folder.
 func formatEc2KeyPairPath(testFolder string) string {
-	return FormatTestDataPath(testFolder, "Ec2KeyPair.json")
+	return formatNamedTestDataPath(testFolder, "Ec2KeyPair")
 }
 
 // SaveSshKeyPair serializes and saves an SshKeyPair into the given folder. This allows you to create an SshKeyPair during setup
@@ -97,7 +100,7 @@ func LoadSshKeyPair(t testing.TestingT, testFolder string) *ssh.KeyPair {
 
 // formatSshKeyPairPath formats a path to save an SshKeyPair in the given folder.
 func formatSshKeyPairPath(testFolder string) string {
-	return FormatTestDataPath(testFolder, "SshKeyPair.json")
+	return formatNamedTestDataPath(testFolder, "SshKeyPair")
 }
 
 // SaveKubectlOptions serializes and saves KubectlOptions into the given folder. This allows you to create a KubectlOptions during setup
@@ -116,7 +119,104 @@ func LoadKubectlOptions(t testing.TestingT, testFolder string) *k8s.KubectlOptio
 
 // formatKubectlOptionsPath formats a path to save a KubectlOptions in the given folder.
 func formatKubectlOptionsPath(testFolder string) string {
-	return FormatTestDataPath(testFolder, "KubectlOptions.json")
+	return formatNamedTestDataPath(testFolder, "KubectlOptions")
+}
+
+// SaveHelmOptions serializes and saves helm Options into the given folder. This allows you to create helm Options during setup
+// and reuse those Options later during validation and teardown.
+func SaveHelmOptions(t testing.TestingT, testFolder string, helmOptions *helm.Options) {
+	SaveTestData(t, formatHelmOptionsPath(testFolder), true, helmOptions)
+}
+
+// LoadHelmOptions loads and unserializes helm Options from the given folder. This allows you to reuse helm Options that were
+// created during an earlier setup step in later validation and teardown steps.
+func LoadHelmOptions(t testing.TestingT, testFolder string) *helm.Options {
+	var helmOptions helm.Options
+	LoadTestData(t, formatHelmOptionsPath(testFolder), &helmOptions)
+	return &helmOptions
+}
+
+// formatHelmOptionsPath formats a path to save helm Options in the given folder.
+func formatHelmOptionsPath(testFolder string) string {
+	return formatNamedTestDataPath(testFolder, "HelmOptions")
+}
+
+// SaveDockerOptions serializes and saves docker Options (used for docker compose) into the given folder. This allows you to
+// create docker Options during setup and reuse those Options later during validation and teardown.
+func SaveDockerOptions(t testing.TestingT, testFolder string, dockerOptions *docker.Options) {
+	SaveTestData(t, formatDockerOptionsPath(testFolder), true, dockerOptions)
+}
+
+// LoadDockerOptions loads and unserializes docker Options from the given folder. This allows you to reuse docker Options that
+// were created during an earlier setup step in later validation and teardown steps.
+func LoadDockerOptions(t testing.TestingT, testFolder string) *docker.Options {
+	var dockerOptions docker.Options
+	LoadTestData(t, formatDockerOptionsPath(testFolder), &dockerOptions)
+	return &dockerOptions
+}
+
+// formatDockerOptionsPath formats a path to save docker Options in the given folder.
+func formatDockerOptionsPath(testFolder string) string {
+	return formatNamedTestDataPath(testFolder, "DockerOptions")
+}
+
+// SaveDockerBuildOptions serializes and saves docker BuildOptions into the given folder. This allows you to create BuildOptions
+// during setup and reuse those BuildOptions later during validation and teardown.
+func SaveDockerBuildOptions(t testing.TestingT, testFolder string, buildOptions *docker.BuildOptions) {
+	SaveTestData(t, formatDockerBuildOptionsPath(testFolder), true, buildOptions)
+}
+
+// LoadDockerBuildOptions loads and unserializes docker BuildOptions from the given folder. This allows you to reuse BuildOptions
+// that were created during an earlier setup step in later validation and teardown steps.
+func LoadDockerBuildOptions(t testing.TestingT, testFolder string) *docker.BuildOptions {
+	var buildOptions docker.BuildOptions
+	LoadTestData(t, formatDockerBuildOptionsPath(testFolder), &buildOptions)
+	return &buildOptions
+}
+
+// formatDockerBuildOptionsPath formats a path to save docker BuildOptions in the given folder.
+func formatDockerBuildOptionsPath(testFolder string) string {
+	return formatNamedTestDataPath(testFolder, "DockerBuildOptions")
+}
+
+// SaveDockerRunOptions serializes and saves docker RunOptions into the given folder. This allows you to create RunOptions
+// during setup and reuse those RunOptions later during validation and teardown.
+func SaveDockerRunOptions(t testing.TestingT, testFolder string, runOptions *docker.RunOptions) {
+	SaveTestData(t, formatDockerRunOptionsPath(testFolder), true, runOptions)
+}
+
+// LoadDockerRunOptions loads and unserializes docker RunOptions from the given folder. This allows you to reuse RunOptions
+// that were created during an earlier setup step in later validation and teardown steps.
+func LoadDockerRunOptions(t testing.TestingT, testFolder string) *docker.RunOptions {
+	var runOptions docker.RunOptions
+	LoadTestData(t, formatDockerRunOptionsPath(testFolder), &runOptions)
+	return &runOptions
+}
+
+// formatDockerRunOptionsPath formats a path to save docker RunOptions in the given folder.
+func formatDockerRunOptionsPath(testFolder string) string {
+	return formatNamedTestDataPath(testFolder, "DockerRunOptions")
+}
+
+// SaveSshHost serializes and saves an ssh Host into the given folder, without logging it, since it may contain a private key or
+// password. This allows you to create a Host during setup and reuse that Host later during validation and teardown. Note that
+// the OverrideSshAgent of the Host is not saved, as an in-process SSH agent can't outlive the test stage that started it.
+func SaveSshHost(t testing.TestingT, testFolder string, host ssh.Host) {
+	host.OverrideSshAgent = nil
+	saveTestData(t, formatSshHostPath(testFolder), true, host, false)
+}
+
+// LoadSshHost loads and unserializes an ssh Host from the given folder. This allows you to reuse a Host that was created during
+// an earlier setup step in later validation and teardown steps.
+func LoadSshHost(t testing.TestingT, testFolder string) ssh.Host {
+	var host ssh.Host
+	LoadTestData(t, formatSshHostPath(testFolder), &host)
+	return host
+}
+
+// formatSshHostPath formats a path to save an ssh Host in the given folder.
+func formatSshHostPath(testFolder string) string {
+	return formatNamedTestDataPath(testFolder, "SshHost")
 }
 
 // SaveString serializes and saves a uniquely named string value into the given folder. This allows you to create one or more string
@@ -179,8 +279,7 @@ func LoadAmiId(t testing.TestingT, testFolder string) string {
 
 // formatNamedTestDataPath formats a path to save an arbitrary named value in the given folder.
 func formatNamedTestDataPath(testFolder string, name string) string {
-	filename := fmt.Sprintf("%s.json", name)
-	return FormatTestDataPath(testFolder, filename)
+	return FormatTestDataPath(testFolder, name+getTestDataCodec().FileExtension())
 }
 
 // FormatTestDataPath formats a path to save test data.
@@ -188,6 +287,18 @@ func FormatTestDataPath(testFolder string, filename string) string {
 	return filepath.Join(testFolder, ".test-data", filename)
 }
 
+// WithNamespace returns a folder, inside the .test-data folder of the given folder, in which to save and load the test data
+// of the given test, so that tests (or subtests) that run in parallel with the same folder don't overwrite each other's test
+// data. For example:
+//
+//	testFolder := test_structure.WithNamespace(t, "../examples/vpc")
+//	test_structure.SaveString(t, testFolder, "vpc-id", vpcID)
+//
+// The namespaced test data is deleted along with the rest of the test data by CleanupTestDataFolder of the given folder.
+func WithNamespace(t testing.TestingT, testFolder string) string {
+	return FormatTestDataPath(testFolder, filepath.Join("namespaces", filepath.FromSlash(t.Name())))
+}
+
 // SaveTestData serializes and saves a value used at test time to the given path. This allows you to create some sort of test data
 // (e.g., TerraformOptions) during setup and to reuse this data later during validation and teardown. If `overwrite` is `true`,
 // any contents that exist in the file found at `path` will be overwritten. This has the potential for causing duplicated resources
@@ -200,7 +311,7 @@ func SaveTestData(t testing.TestingT, path string, overwrite bool, value interfa
 // (e.g., TerraformOptions) during setup and to reuse this data later during validation and teardown. If `overwrite` is `true`,
 // any contents that exist in the file found at `path` will be overwritten. This has the potential for causing duplicated resources
 // and should be used with caution. If `overwrite` is `false`, the save will be skipped and a warning will be logged.
-// If `loggedVal` is `true`, the value will be logged as JSON.
+// If `loggedVal` is `true`, the serialized value will be logged.
 func saveTestData(t testing.TestingT, path string, overwrite bool, value interface{}, loggedVal bool) {
 	logger.Default.Logf(t, "Storing test data in %s so it can be reused later", path)
 
@@ -213,21 +324,20 @@ func saveTestData(t testing.TestingT, path string, overwrite bool, value interfa
 		}
 	}
 
-	bytes, err := json.Marshal(value)
+	bytes, err := getTestDataCodec().Marshal(value)
 	if err != nil {
-		t.Fatalf("Failed to convert value %s to JSON: %v", path, err)
+		t.Fatalf("Failed to serialize value %s: %v", path, err)
 	}
 
 	if loggedVal {
-		logger.Default.Logf(t, "Marshalled JSON: %s", string(bytes))
-	}
-
-	parentDir := filepath.Dir(path)
-	if err := os.MkdirAll(parentDir, 0777); err != nil {
-		t.Fatalf("Failed to create folder %s: %v", parentDir, err)
+		if utf8.Valid(bytes) {
+			logger.Default.Logf(t, "Marshalled test data: %s", string(bytes))
+		} else {
+			logger.Default.Logf(t, "Marshalled test data: %d bytes of binary data", len(bytes))
+		}
 	}
 
-	if err := os.WriteFile(path, bytes, 0644); err != nil {
+	if err := getTestDataStore().Write(t, path, bytes); err != nil {
 		t.Fatalf("Failed to save value %s: %v", path, err)
 	}
 }
@@ -238,50 +348,56 @@ func saveTestData(t testing.TestingT, path string, overwrite bool, value interfa
 func LoadTestData(t testing.TestingT, path string, value interface{}) {
 	logger.Default.Logf(t, "Loading test data from %s", path)
 
-	bytes, err := os.ReadFile(path)
+	bytes, err := getTestDataStore().Read(t, path)
 	if err != nil {
 		t.Fatalf("Failed to load value from %s: %v", path, err)
 	}
+	if err := checkTestDataAge(t, path); err != nil {
+		t.Fatalf("Failed to load value from %s: %v", path, err)
+	}
 
-	if err := json.Unmarshal(bytes, value); err != nil {
-		t.Fatalf("Failed to parse JSON for value %s: %v", path, err)
+	if err := getTestDataCodec().Unmarshal(bytes, value); err != nil {
+		t.Fatalf("Failed to parse value %s: %v", path, err)
 	}
 }
 
-// IsTestDataPresent returns true if a file exists at $path and the test data there is non-empty.
+// IsTestDataPresent returns true if test data exists at $path and the test data there is non-empty.
 func IsTestDataPresent(t testing.TestingT, path string) bool {
-	exists, err := files.FileExistsE(path)
-	if err != nil {
-		t.Fatalf("Failed to load test data from %s due to unexpected error: %v", path, err)
-	}
-	if !exists {
+	bytes, err := getTestDataStore().Read(t, path)
+	if errors.Is(err, os.ErrNotExist) {
 		return false
 	}
-
-	bytes, err := os.ReadFile(path)
-
 	if err != nil {
 		t.Fatalf("Failed to load test data from %s due to unexpected error: %v", path, err)
 	}
 
-	if isEmptyJSON(t, bytes) {
+	if isEmptyTestData(t, bytes) {
 		return false
 	}
 
+	var stale StaleTestData
+	if err := checkTestDataAge(t, path); errors.As(err, &stale) {
+		logger.Default.Logf(t, "Ignoring %v", err)
+		return false
+	} else if err != nil {
+		t.Fatalf("Failed to load test data from %s due to unexpected error: %v", path, err)
+	}
+
 	return true
 }
 
-// isEmptyJSON returns true if the given bytes are empty, or in a valid JSON format that can reasonably be considered empty.
-// The types used are based on the type possibilities listed at https://golang.org/src/encoding/json/decode.go?s=4062:4110#L51
-func isEmptyJSON(t testing.TestingT, bytes []byte) bool {
+// isEmptyTestData returns true if the given bytes are empty, or a valid serialized value (in the format of the codec of
+// the store set with SetTestDataStore) that can reasonably be considered empty. The types used are based on the type
+// possibilities listed at https://golang.org/src/encoding/json/decode.go?s=4062:4110#L51
+func isEmptyTestData(t testing.TestingT, bytes []byte) bool {
 	var value interface{}
 
 	if len(bytes) == 0 {
 		return true
 	}
 
-	if err := json.Unmarshal(bytes, &value); err != nil {
-		t.Fatalf("Failed to parse JSON while testing whether it is empty: %v", err)
+	if err := getTestDataCodec().Unmarshal(bytes, &value); err != nil {
+		t.Fatalf("Failed to parse test data while testing whether it is empty: %v", err)
 	}
 
 	if value == nil {
@@ -298,6 +414,12 @@ func isEmptyJSON(t testing.TestingT, bytes []byte) bool {
 		return true
 	}
 
+	// Binary formats such as CBOR load integers as integers rather than as float64
+	valueUint64, ok := value.(uint64)
+	if ok && valueUint64 == 0 {
+		return true
+	}
+
 	valueString, ok := value.(string)
 	if ok && valueString == "" {
 		return true
@@ -316,11 +438,65 @@ func isEmptyJSON(t testing.TestingT, bytes []byte) bool {
 	return false
 }
 
+// ListTestData returns the names of all the test data saved in the .test-data folder inside the given folder (e.g.
+// "TerraformOptions", or the names given to SaveString and SaveNamed), sorted. Test data saved in subfolders of the
+// .test-data folder is named by its path relative to it. If there are any errors, fail the test.
+func ListTestData(t testing.TestingT, testFolder string) []string {
+	names, err := ListTestDataE(t, testFolder)
+	require.NoError(t, err)
+	return names
+}
+
+// ListTestDataE returns the names of all the test data saved in the .test-data folder inside the given folder (e.g.
+// "TerraformOptions", or the names given to SaveString and SaveNamed), sorted. Test data saved in subfolders of the
+// .test-data folder is named by its path relative to it.
+func ListTestDataE(t testing.TestingT, testFolder string) ([]string, error) {
+	testDataFolder := FormatTestDataPath(testFolder, "")
+	paths, err := getTestDataStore().List(t, testDataFolder)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, path := range paths {
+		relPath, err := filepath.Rel(testDataFolder, path)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, strings.TrimSuffix(filepath.ToSlash(relPath), getTestDataCodec().FileExtension()))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// CleanupAllTestData logs the names of all the test data saved in the .test-data folder inside the given folder and
+// cleans up the folder. If there are any errors, fail the test.
+func CleanupAllTestData(t testing.TestingT, testFolder string) {
+	require.NoError(t, CleanupAllTestDataE(t, testFolder))
+}
+
+// CleanupAllTestDataE logs the names of all the test data saved in the .test-data folder inside the given folder and
+// cleans up the folder.
+func CleanupAllTestDataE(t testing.TestingT, testFolder string) error {
+	names, err := ListTestDataE(t, testFolder)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		logger.Default.Logf(t, "There is no test data in %s. Nothing to cleanup.", testFolder)
+		return nil
+	}
+
+	logger.Default.Logf(t, "Cleaning up test data %s from %s", strings.Join(names, ", "), testFolder)
+	return CleanupTestDataFolderE(t, testFolder)
+}
+
 // CleanupTestData cleans up the test data at the given path.
 func CleanupTestData(t testing.TestingT, path string) {
-	if files.FileExists(path) {
+	store := getTestDataStore()
+	if _, err := store.Read(t, path); err == nil {
 		logger.Default.Logf(t, "Cleaning up test data from %s", path)
-		if err := os.Remove(path); err != nil {
+		if err := store.Delete(t, path); err != nil {
 			t.Fatalf("Failed to clean up file at %s: %v", path, err)
 		}
 	} else {
@@ -338,17 +514,8 @@ func CleanupTestDataFolder(t testing.TestingT, path string) {
 // CleanupTestDataFolderE cleans up the .test-data folder inside the given folder.
 func CleanupTestDataFolderE(t testing.TestingT, path string) error {
 	path = filepath.Join(path, ".test-data")
-	exists, err := files.FileExistsE(path)
-	if err != nil {
-		logger.Default.Logf(t, "Failed to clean up test data folder at %s: %v", path, err)
-		return err
-	}
-
-	if !exists {
-		logger.Default.Logf(t, "%s does not exist. Nothing to cleanup.", path)
-		return nil
-	}
-	if err := os.RemoveAll(path); err != nil {
+	logger.Default.Logf(t, "Cleaning up test data folder %s", path)
+	if err := getTestDataStore().DeleteFolder(t, path); err != nil {
 		logger.Default.Logf(t, "Failed to clean up test data folder at %s: %v", path, err)
 		return err
 	}
@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/docker"
 	"github.com/gruntwork-io/terratest/modules/files"
+	"github.com/gruntwork-io/terratest/modules/helm"
 	"github.com/gruntwork-io/terratest/modules/k8s"
 	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/ssh"
@@ -74,39 +77,39 @@ func TestIsEmptyJson(t *testing.T) {
 	var isEmpty bool
 
 	jsonValue = []byte("null")
-	isEmpty = isEmptyJSON(t, jsonValue)
+	isEmpty = isEmptyTestData(t, jsonValue)
 	assert.True(t, isEmpty, `The JSON literal "null" should be treated as an empty value.`)
 
 	jsonValue = []byte("false")
-	isEmpty = isEmptyJSON(t, jsonValue)
+	isEmpty = isEmptyTestData(t, jsonValue)
 	assert.True(t, isEmpty, `The JSON literal "false" should be treated as an empty value.`)
 
 	jsonValue = []byte("true")
-	isEmpty = isEmptyJSON(t, jsonValue)
+	isEmpty = isEmptyTestData(t, jsonValue)
 	assert.False(t, isEmpty, `The JSON literal "true" should be treated as a non-empty value.`)
 
 	jsonValue = []byte("0")
-	isEmpty = isEmptyJSON(t, jsonValue)
+	isEmpty = isEmptyTestData(t, jsonValue)
 	assert.True(t, isEmpty, `The JSON literal "0" should be treated as an empty value.`)
 
 	jsonValue = []byte("1")
-	isEmpty = isEmptyJSON(t, jsonValue)
+	isEmpty = isEmptyTestData(t, jsonValue)
 	assert.False(t, isEmpty, `The JSON literal "1" should be treated as a non-empty value.`)
 
 	jsonValue = []byte("{}")
-	isEmpty = isEmptyJSON(t, jsonValue)
+	isEmpty = isEmptyTestData(t, jsonValue)
 	assert.True(t, isEmpty, `The JSON value "{}" should be treated as an empty value.`)
 
 	jsonValue = []byte(`{ "key": "val" }`)
-	isEmpty = isEmptyJSON(t, jsonValue)
+	isEmpty = isEmptyTestData(t, jsonValue)
 	assert.False(t, isEmpty, `The JSON value { "key": "val" } should be treated as a non-empty value.`)
 
 	jsonValue = []byte(`[]`)
-	isEmpty = isEmptyJSON(t, jsonValue)
+	isEmpty = isEmptyTestData(t, jsonValue)
 	assert.True(t, isEmpty, `The JSON value "[]" should be treated as an empty value.`)
 
 	jsonValue = []byte(`[{ "key": "val" }]`)
-	isEmpty = isEmptyJSON(t, jsonValue)
+	isEmpty = isEmptyTestData(t, jsonValue)
 	assert.False(t, isEmpty, `The JSON value [{ "key": "val" }] should be treated as a non-empty value.`)
 }
 
@@ -125,6 +128,29 @@ func TestSaveAndLoadTerraformOptions(t *testing.T) {
 	assert.Equal(t, expectedData, actualData)
 }
 
+func TestSaveAndLoadTerraformOptionsWithFuncFields(t *testing.T) {
+	t.Parallel()
+
+	tmpFolder := t.TempDir()
+
+	savedData := terraform.WithBuiltinRetryRules(t, &terraform.Options{
+		TerraformDir:   "/abc/def/ghi",
+		Vars:           map[string]interface{}{"foo": "bar"},
+		OutputCallback: func(line string) {},
+		RetryMetrics:   terraform.NewRetryMetrics(),
+	})
+	SaveTerraformOptions(t, tmpFolder, savedData)
+
+	// The fields that can't be serialized are left out, rather than failing the save.
+	expectedData, err := savedData.Clone()
+	require.NoError(t, err)
+	expectedData.OutputCallback = nil
+	expectedData.ErrorClassifier = nil
+	expectedData.RetryMetrics = nil
+	actualData := LoadTerraformOptions(t, tmpFolder)
+	assert.Equal(t, expectedData, actualData)
+}
+
 func TestSaveTerraformOptionsIfNotPresent(t *testing.T) {
 	t.Parallel()
 
@@ -241,6 +267,66 @@ func TestSaveDuplicateTestData(t *testing.T) {
 	assert.Equal(t, val2, actualVal, "Actual test data should use overwritten values")
 }
 
+func TestListAndCleanupAllTestData(t *testing.T) {
+	t.Parallel()
+
+	tmpFolder := t.TempDir()
+	assert.Empty(t, ListTestData(t, tmpFolder))
+
+	SaveString(t, tmpFolder, "vpc-id", "vpc-1234")
+	SaveAmiId(t, tmpFolder, "ami-1234")
+	SaveTestData(t, FormatTestDataPath(tmpFolder, "stages/deploy.json"), true, "done")
+	assert.Equal(t, []string{"AMI", "stages/deploy", "vpc-id"}, ListTestData(t, tmpFolder))
+
+	CleanupAllTestData(t, tmpFolder)
+	assert.Empty(t, ListTestData(t, tmpFolder))
+	assert.NoDirExists(t, FormatTestDataPath(tmpFolder, ""))
+}
+
+func TestSaveStringConcurrently(t *testing.T) {
+	t.Parallel()
+
+	tmpFolder := t.TempDir()
+	values := []string{}
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		value := strings.Repeat(fmt.Sprintf("value-%d-", i), 1000)
+		values = append(values, value)
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			SaveString(t, tmpFolder, "shared", value)
+		}()
+	}
+	waitGroup.Wait()
+
+	assert.Contains(t, values, LoadString(t, tmpFolder, "shared"))
+	assert.Equal(t, []string{"shared"}, ListTestData(t, tmpFolder))
+}
+
+func TestWithNamespace(t *testing.T) {
+	t.Parallel()
+
+	tmpFolder := t.TempDir()
+	for _, name := range []string{"us-east-1", "eu-west-1"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			testFolder := WithNamespace(t, tmpFolder)
+			SaveString(t, testFolder, "region", name)
+			assert.Equal(t, name, LoadString(t, testFolder, "region"))
+		})
+	}
+
+	t.Cleanup(func() {
+		assert.Equal(t, []string{
+			"namespaces/TestWithNamespace/eu-west-1/.test-data/region",
+			"namespaces/TestWithNamespace/us-east-1/.test-data/region",
+		}, ListTestData(t, tmpFolder))
+	})
+}
+
 func TestSaveAndLoadNamedInts(t *testing.T) {
 	t.Parallel()
 
@@ -281,6 +367,63 @@ func TestSaveAndLoadKubectlOptions(t *testing.T) {
 	assert.Equal(t, expectedData, actualData)
 }
 
+func TestSaveAndLoadHelmOptions(t *testing.T) {
+	t.Parallel()
+
+	tmpFolder := t.TempDir()
+
+	expectedData := &helm.Options{
+		ValuesFiles:    []string{"values.yaml"},
+		SetValues:      map[string]string{"image.tag": "1.0.0"},
+		KubectlOptions: &k8s.KubectlOptions{Namespace: "terratest"},
+		Version:        "0.1.0",
+	}
+	SaveHelmOptions(t, tmpFolder, expectedData)
+
+	actualData := LoadHelmOptions(t, tmpFolder)
+	assert.Equal(t, expectedData, actualData)
+}
+
+func TestSaveAndLoadDockerOptions(t *testing.T) {
+	t.Parallel()
+
+	tmpFolder := t.TempDir()
+
+	expectedOptions := &docker.Options{WorkingDir: "../examples/docker-compose-stdout-example", ProjectName: "terratest"}
+	SaveDockerOptions(t, tmpFolder, expectedOptions)
+	assert.Equal(t, expectedOptions, LoadDockerOptions(t, tmpFolder))
+
+	expectedBuildOptions := &docker.BuildOptions{Tags: []string{"terratest:latest"}, BuildArgs: []string{"VERSION=1"}}
+	SaveDockerBuildOptions(t, tmpFolder, expectedBuildOptions)
+	assert.Equal(t, expectedBuildOptions, LoadDockerBuildOptions(t, tmpFolder))
+
+	expectedRunOptions := &docker.RunOptions{Command: []string{"echo", "hello"}, Remove: true}
+	SaveDockerRunOptions(t, tmpFolder, expectedRunOptions)
+	assert.Equal(t, expectedRunOptions, LoadDockerRunOptions(t, tmpFolder))
+}
+
+func TestSaveAndLoadSshHost(t *testing.T) {
+	t.Parallel()
+
+	tmpFolder := t.TempDir()
+
+	keyPair, err := ssh.GenerateRSAKeyPairE(t, 2048)
+	require.NoError(t, err)
+	expectedHost := ssh.Host{
+		Hostname:         "10.0.0.1",
+		SshUserName:      "ubuntu",
+		SshKeyPair:       keyPair,
+		OverrideSshAgent: &ssh.SshAgent{},
+		CustomPort:       2222,
+	}
+	SaveSshHost(t, tmpFolder, expectedHost)
+
+	actualHost := LoadSshHost(t, tmpFolder)
+	assert.Nil(t, actualHost.OverrideSshAgent)
+	expectedHost.OverrideSshAgent = nil
+	assert.Equal(t, expectedHost, actualHost)
+}
+
 type tStringLogger struct {
 	sb strings.Builder
 }
@@ -0,0 +1,178 @@
+package test_structure
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// STAGE_CONFIG_ENV_VAR is the environment variable that can be set to the path of a YAML or JSON StageConfig file,
+// which applies to all the tests that don't have a stage config of their own.
+const STAGE_CONFIG_ENV_VAR = "TERRATEST_STAGE_CONFIG"
+
+// StageConfig controls which of the stages run by RunTestStage and RunPipeline are skipped, in addition to the
+// `SKIP_<stageName>` environment variables. For example, the following YAML file skips the build_ami stage and runs
+// none of the stages other than build_ami, deploy and validate:
+//
+//	skip:
+//	  - build_ami
+//	only:
+//	  - build_ami
+//	  - deploy
+//	  - validate
+type StageConfig struct {
+	Skip []string `json:"skip" yaml:"skip"` // The names of the stages to skip
+	Only []string `json:"only" yaml:"only"` // If set, the names of the only stages to run; all the other stages are skipped
+}
+
+var (
+	// stageConfigs are the stage configs set for tests, keyed by the name of the test.
+	stageConfigs      = map[string]StageConfig{}
+	stageConfigsMutex sync.Mutex
+)
+
+// SetStageConfig sets the stage config of the given test (and its subtests), replacing any stage config set for it
+// before. The stage config is removed when the test finishes, if the test supports cleanup functions (as *testing.T
+// does), so that it doesn't apply to the next run of the test (e.g. with -count).
+func SetStageConfig(t testing.TestingT, config StageConfig) {
+	stageConfigsMutex.Lock()
+	defer stageConfigsMutex.Unlock()
+
+	testName := t.Name()
+	if _, exists := stageConfigs[testName]; !exists {
+		registerCleanup(t, func() {
+			stageConfigsMutex.Lock()
+			defer stageConfigsMutex.Unlock()
+			delete(stageConfigs, testName)
+		})
+	}
+	stageConfigs[testName] = config
+}
+
+// SkipStages skips the stages with the given names in the given test (and its subtests), e.g. to skip deploying while
+// iterating on the validation stage locally:
+//
+//	test_structure.SkipStages(t, "build_ami", "deploy")
+func SkipStages(t testing.TestingT, stageNames ...string) {
+	config := GetStageConfig(t)
+	config.Skip = append(append([]string{}, config.Skip...), stageNames...)
+	SetStageConfig(t, config)
+}
+
+// RunOnlyStages skips all the stages other than the ones with the given names in the given test (and its subtests).
+func RunOnlyStages(t testing.TestingT, stageNames ...string) {
+	config := GetStageConfig(t)
+	config.Only = append([]string{}, stageNames...)
+	SetStageConfig(t, config)
+}
+
+// GetStageConfig returns the stage config of the given test: the one set for the test, or else for its closest parent
+// test, or else the one loaded from the file in the TERRATEST_STAGE_CONFIG environment variable. This will fail the
+// test if that file can't be loaded.
+func GetStageConfig(t testing.TestingT) StageConfig {
+	config, err := GetStageConfigE(t)
+	require.NoError(t, err)
+	return config
+}
+
+// GetStageConfigE returns the stage config of the given test: the one set for the test, or else for its closest parent
+// test, or else the one loaded from the file in the TERRATEST_STAGE_CONFIG environment variable.
+func GetStageConfigE(t testing.TestingT) (StageConfig, error) {
+	if config, found := getTestStageConfig(t.Name()); found {
+		return config, nil
+	}
+
+	path := os.Getenv(STAGE_CONFIG_ENV_VAR)
+	if path == "" {
+		return StageConfig{}, nil
+	}
+	return readStageConfigFile(path)
+}
+
+// getTestStageConfig returns the stage config set for the test with the given name, or else for its closest parent
+// test, and whether there is one.
+func getTestStageConfig(testName string) (StageConfig, bool) {
+	stageConfigsMutex.Lock()
+	defer stageConfigsMutex.Unlock()
+
+	for name := testName; ; {
+		if config, found := stageConfigs[name]; found {
+			return config, true
+		}
+		index := strings.LastIndex(name, "/")
+		if index < 0 {
+			return StageConfig{}, false
+		}
+		name = name[:index]
+	}
+}
+
+// LoadStageConfig loads the stage config of the given test (and its subtests) from the given YAML or JSON file. This
+// will fail the test if the file can't be loaded.
+func LoadStageConfig(t testing.TestingT, path string) {
+	require.NoError(t, LoadStageConfigE(t, path))
+}
+
+// LoadStageConfigE loads the stage config of the given test (and its subtests) from the given YAML or JSON file.
+func LoadStageConfigE(t testing.TestingT, path string) error {
+	logger.Default.Logf(t, "Loading stage config from %s", path)
+
+	config, err := readStageConfigFile(path)
+	if err != nil {
+		return err
+	}
+	SetStageConfig(t, config)
+	return nil
+}
+
+// readStageConfigFile reads the stage config in the given YAML or JSON file (JSON being a subset of YAML).
+func readStageConfigFile(path string) (StageConfig, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return StageConfig{}, err
+	}
+
+	var config StageConfig
+	if err := yaml.Unmarshal(contents, &config); err != nil {
+		return StageConfig{}, fmt.Errorf("failed to parse stage config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// getStageSkipReason returns why the stage with the given name should be skipped in the given test, or an empty string
+// if it should run. A stage is skipped if its `SKIP_<stageName>` environment variable is set, or if the stage config of
+// the test skips it or doesn't list it in its only stages.
+func getStageSkipReason(t testing.TestingT, stageName string) (string, error) {
+	envVarName := fmt.Sprintf("%s%s", SKIP_STAGE_ENV_VAR_PREFIX, stageName)
+	if os.Getenv(envVarName) != "" {
+		return fmt.Sprintf("The '%s' environment variable is set", envVarName), nil
+	}
+
+	config, err := GetStageConfigE(t)
+	if err != nil {
+		return "", err
+	}
+	if containsStageName(config.Skip, stageName) {
+		return "The stage config skips it", nil
+	}
+	if len(config.Only) > 0 && !containsStageName(config.Only, stageName) {
+		return fmt.Sprintf("The stage config only runs stages %s", strings.Join(config.Only, ", ")), nil
+	}
+	return "", nil
+}
+
+// containsStageName returns true if the given stage names contain the given stage name.
+func containsStageName(stageNames []string, stageName string) bool {
+	for _, name := range stageNames {
+		if name == stageName {
+			return true
+		}
+	}
+	return false
+}
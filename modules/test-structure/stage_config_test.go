@@ -0,0 +1,90 @@
+package test_structure
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkipStagesSkipsStages(t *testing.T) {
+	SkipStages(t, "config_build_ami", "config_deploy")
+
+	ran := []string{}
+	for _, stageName := range []string{"config_build_ami", "config_deploy", "config_validate"} {
+		stageName := stageName
+		RunTestStage(t, stageName, func() { ran = append(ran, stageName) })
+	}
+	assert.Equal(t, []string{"config_validate"}, ran)
+}
+
+func TestRunOnlyStagesAppliesToSubtests(t *testing.T) {
+	RunOnlyStages(t, "config_validate")
+
+	t.Run("subtest", func(t *testing.T) {
+		ran := []string{}
+		pipeline := NewPipeline()
+		pipeline.Stage("config_deploy", func() { ran = append(ran, "config_deploy") })
+		pipeline.Stage("config_validate", func() { ran = append(ran, "config_validate") }, "config_deploy")
+		RunPipeline(t, pipeline)
+
+		assert.Equal(t, []string{"config_validate"}, ran)
+		assert.True(t, pipeline.GetResults()[0].Skipped)
+	})
+}
+
+func TestStageConfigIsRemovedWhenTheTestFinishes(t *testing.T) {
+	var testName string
+	t.Run("subtest", func(t *testing.T) {
+		testName = t.Name()
+		SkipStages(t, "config_deploy")
+	})
+
+	_, found := getTestStageConfig(testName)
+	assert.False(t, found)
+}
+
+func TestLoadStageConfigFromYamlAndJson(t *testing.T) {
+	testCases := map[string]string{
+		"stages.yaml": "skip:\n  - build_ami\nonly:\n  - build_ami\n  - validate\n",
+		"stages.json": `{"skip": ["build_ami"], "only": ["build_ami", "validate"]}`,
+	}
+
+	for fileName, contents := range testCases {
+		t.Run(fileName, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), fileName)
+			require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+
+			LoadStageConfig(t, path)
+			assert.Equal(t, StageConfig{Skip: []string{"build_ami"}, Only: []string{"build_ami", "validate"}}, GetStageConfig(t))
+
+			for stageName, expectedSkip := range map[string]bool{"build_ami": true, "deploy": true, "validate": false} {
+				reason, err := getStageSkipReason(t, stageName)
+				require.NoError(t, err)
+				assert.Equal(t, expectedSkip, reason != "", stageName)
+			}
+		})
+	}
+}
+
+func TestGetStageConfigFromEnvVar(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stages.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("skip: [deploy]\n"), 0644))
+	t.Setenv(STAGE_CONFIG_ENV_VAR, path)
+
+	assert.Equal(t, StageConfig{Skip: []string{"deploy"}}, GetStageConfig(t))
+
+	SetStageConfig(t, StageConfig{})
+	assert.Equal(t, StageConfig{}, GetStageConfig(t))
+}
+
+func TestGetStageConfigEInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stages.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("skip: {"), 0644))
+	t.Setenv(STAGE_CONFIG_ENV_VAR, path)
+
+	_, err := GetStageConfigE(t)
+	require.Error(t, err)
+}
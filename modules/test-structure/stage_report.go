@@ -0,0 +1,249 @@
+package test_structure
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// StageReportFormat is the format in which WriteStageReport writes the stage report of a test.
+type StageReportFormat string
+
+const (
+	// StageReportJSON writes the stage report as JSON.
+	StageReportJSON StageReportFormat = "json"
+
+	// StageReportJUnit writes the stage report as JUnit XML, with a test case for each stage, which most CI systems can
+	// display.
+	StageReportJUnit StageReportFormat = "junit"
+)
+
+// StageResult is the result of a test stage run by RunTestStage or RunPipeline.
+type StageResult struct {
+	Name     string
+	Start    time.Time     // When the stage started (or was skipped)
+	Duration time.Duration // How long the stage took to run
+	Skipped  bool          // Whether the stage was skipped because `SKIP_<stageName>` was set
+	Failed   bool          // Whether the stage panicked or failed the test
+	Error    string        // Why the stage failed
+}
+
+var (
+	// stageResults are the results of the stages run by RunTestStage and RunPipeline, keyed by the name of the test.
+	stageResults      = map[string][]StageResult{}
+	stageResultsMutex sync.Mutex
+)
+
+// recordStageResult records the given result of a stage of the given test for its stage report. The results are
+// removed when the test finishes, if the test supports cleanup functions (as *testing.T does), so that the next run of
+// the test (e.g. with -count) starts with an empty report.
+func recordStageResult(t testing.TestingT, result StageResult) {
+	stageResultsMutex.Lock()
+	defer stageResultsMutex.Unlock()
+
+	testName := t.Name()
+	if _, exists := stageResults[testName]; !exists {
+		registerCleanup(t, func() {
+			stageResultsMutex.Lock()
+			defer stageResultsMutex.Unlock()
+			delete(stageResults, testName)
+		})
+	}
+	stageResults[testName] = append(stageResults[testName], result)
+}
+
+// GetStageResults returns the results of the stages that RunTestStage (and RunPipeline) ran or skipped in the given
+// test, in the order they finished.
+func GetStageResults(t testing.TestingT) []StageResult {
+	stageResultsMutex.Lock()
+	defer stageResultsMutex.Unlock()
+	return append([]StageResult{}, stageResults[t.Name()]...)
+}
+
+// cleanupRegisterer is implemented by the TestingT implementations that can run functions when the test finishes, such
+// as *testing.T.
+type cleanupRegisterer interface {
+	Cleanup(func())
+}
+
+// registerCleanup registers the given function to run when the given test finishes, if the test supports it.
+func registerCleanup(t testing.TestingT, cleanup func()) {
+	if registerer, canCleanup := t.(cleanupRegisterer); canCleanup {
+		registerer.Cleanup(cleanup)
+	}
+}
+
+// failureReporter is implemented by the TestingT implementations that can tell whether the test has failed, such as
+// *testing.T.
+type failureReporter interface {
+	Failed() bool
+}
+
+// runAndRecordTestStage runs the given stage and records its result. A stage that panics, fails the test with
+// t.FailNow, or marks the test as failed is recorded as failed. Panics are recorded and then propagated.
+func runAndRecordTestStage(t testing.TestingT, stageName string, stage func()) {
+	result := StageResult{Name: stageName, Start: time.Now()}
+	reporter, canReportFailure := t.(failureReporter)
+	failedBefore := canReportFailure && reporter.Failed()
+
+	completed := false
+	defer func() {
+		result.Duration = time.Since(result.Start)
+		recovered := recover()
+		switch {
+		case recovered != nil:
+			result.Failed = true
+			result.Error = fmt.Sprintf("stage '%s' panicked: %v", stageName, recovered)
+		case !completed:
+			result.Failed = true
+			result.Error = fmt.Sprintf("stage '%s' failed", stageName)
+		case canReportFailure && !failedBefore && reporter.Failed():
+			result.Failed = true
+			result.Error = fmt.Sprintf("stage '%s' marked the test as failed", stageName)
+		}
+		recordStageResult(t, result)
+		if recovered != nil {
+			panic(recovered)
+		}
+	}()
+
+	stage()
+	completed = true
+}
+
+// WriteStageReport writes the report of the stages that RunTestStage (and RunPipeline) ran or skipped in the given
+// test to the given path, in the given format, e.g. in a deferred call at the start of the test, so that CI dashboards
+// can show which stage of the test failed and how long each stage took. This will fail the test if the report can't
+// be written.
+func WriteStageReport(t testing.TestingT, path string, format StageReportFormat) {
+	require.NoError(t, WriteStageReportE(t, path, format))
+}
+
+// WriteStageReportE writes the report of the stages that RunTestStage (and RunPipeline) ran or skipped in the given
+// test to the given path, in the given format, so that CI dashboards can show which stage of the test failed and how
+// long each stage took.
+func WriteStageReportE(t testing.TestingT, path string, format StageReportFormat) error {
+	var contents []byte
+	var err error
+	switch format {
+	case StageReportJSON:
+		contents, err = formatStageReportJSON(t.Name(), GetStageResults(t))
+	case StageReportJUnit:
+		contents, err = formatStageReportJUnit(t.Name(), GetStageResults(t))
+	default:
+		return UnsupportedStageReportFormat(format)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, contents, 0644)
+}
+
+// jsonStageReport is the JSON format of a stage report.
+type jsonStageReport struct {
+	Test   string            `json:"test"`
+	Stages []jsonStageResult `json:"stages"`
+}
+
+// jsonStageResult is the JSON format of a StageResult.
+type jsonStageResult struct {
+	Name            string    `json:"name"`
+	Start           time.Time `json:"start"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	Skipped         bool      `json:"skipped"`
+	Failed          bool      `json:"failed"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// formatStageReportJSON formats the given stage results of the given test as JSON.
+func formatStageReportJSON(testName string, results []StageResult) ([]byte, error) {
+	report := jsonStageReport{Test: testName, Stages: []jsonStageResult{}}
+	for _, result := range results {
+		report.Stages = append(report.Stages, jsonStageResult{
+			Name:            result.Name,
+			Start:           result.Start,
+			DurationSeconds: result.Duration.Seconds(),
+			Skipped:         result.Skipped,
+			Failed:          result.Failed,
+			Error:           result.Error,
+		})
+	}
+	return json.MarshalIndent(report, "", "  ")
+}
+
+// junitTestSuites is the root element of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite is a JUnit test suite, for the stages of a test.
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase is a JUnit test case, for a stage of a test.
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+}
+
+// junitFailure is the failure of a JUnit test case.
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+// formatStageReportJUnit formats the given stage results of the given test as JUnit XML, with a test suite for the test
+// and a test case for each stage.
+func formatStageReportJUnit(testName string, results []StageResult) ([]byte, error) {
+	suite := junitTestSuite{Name: testName, Tests: len(results), Cases: []junitTestCase{}}
+	var total time.Duration
+	for i, result := range results {
+		if i == 0 {
+			suite.Timestamp = result.Start.UTC().Format(time.RFC3339)
+		}
+		total += result.Duration
+
+		testCase := junitTestCase{Name: result.Name, ClassName: testName, Time: formatJUnitSeconds(result.Duration)}
+		if result.Skipped {
+			suite.Skipped++
+			testCase.Skipped = &struct{}{}
+		} else if result.Failed {
+			suite.Failures++
+			testCase.Failure = &junitFailure{Message: result.Error}
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	suite.Time = formatJUnitSeconds(total)
+
+	contents, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), contents...), nil
+}
+
+// formatJUnitSeconds formats the given duration in seconds, as JUnit reports do.
+func formatJUnitSeconds(duration time.Duration) string {
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}
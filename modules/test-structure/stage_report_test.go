@@ -0,0 +1,73 @@
+package test_structure
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunTestStageRecordsStageResults(t *testing.T) {
+	t.Setenv("SKIP_report_teardown", "true")
+
+	RunTestStage(t, "report_setup", func() {})
+	assert.Panics(t, func() {
+		RunTestStage(t, "report_validate", func() { panic("endpoint is not healthy") })
+	})
+	RunTestStage(t, "report_teardown", func() {})
+
+	results := GetStageResults(t)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, "report_setup", results[0].Name)
+	assert.False(t, results[0].Failed)
+	assert.False(t, results[0].Start.IsZero())
+
+	assert.Equal(t, "report_validate", results[1].Name)
+	assert.True(t, results[1].Failed)
+	assert.Contains(t, results[1].Error, "endpoint is not healthy")
+
+	assert.Equal(t, "report_teardown", results[2].Name)
+	assert.True(t, results[2].Skipped)
+
+	reportDir := t.TempDir()
+
+	jsonPath := filepath.Join(reportDir, "stages.json")
+	WriteStageReport(t, jsonPath, StageReportJSON)
+	jsonReport, err := os.ReadFile(jsonPath)
+	require.NoError(t, err)
+	var report jsonStageReport
+	require.NoError(t, json.Unmarshal(jsonReport, &report))
+	assert.Equal(t, t.Name(), report.Test)
+	require.Len(t, report.Stages, 3)
+	assert.True(t, report.Stages[1].Failed)
+
+	junitPath := filepath.Join(reportDir, "junit", "stages.xml")
+	WriteStageReport(t, junitPath, StageReportJUnit)
+	junitReport, err := os.ReadFile(junitPath)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(junitReport), "<?xml"))
+	assert.Contains(t, string(junitReport), `<testsuite name="TestRunTestStageRecordsStageResults" tests="3" failures="1" skipped="1"`)
+	assert.Contains(t, string(junitReport), `<testcase name="report_validate" classname="TestRunTestStageRecordsStageResults"`)
+	assert.Contains(t, string(junitReport), `<failure message="stage &#39;report_validate&#39; panicked: endpoint is not healthy"></failure>`)
+	assert.Contains(t, string(junitReport), `<skipped></skipped>`)
+
+	assert.ErrorAs(t, WriteStageReportE(t, filepath.Join(reportDir, "stages.txt"), "text"), new(UnsupportedStageReportFormat))
+}
+
+func TestStageResultsAreRemovedWhenTheTestFinishes(t *testing.T) {
+	var testName string
+	t.Run("subtest", func(t *testing.T) {
+		testName = t.Name()
+		RunTestStage(t, "report_cleanup", func() {})
+		require.Len(t, GetStageResults(t), 1)
+	})
+
+	stageResultsMutex.Lock()
+	defer stageResultsMutex.Unlock()
+	assert.NotContains(t, stageResults, testName)
+}
@@ -0,0 +1,109 @@
+package test_structure
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/gcp"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// DeleteTerraformBackendState deletes the state that Terraform stored in the given backend with the BackendConfig of
+// the given options, such as the unique state set by terraform.IsolateBackendState, e.g. at the end of a test after
+// destroying the resources. The bucket (and the region for the s3 backend) must be set in the BackendConfig. This will
+// fail the test if the state can't be deleted.
+func DeleteTerraformBackendState(t testing.TestingT, options *terraform.Options, backendType string) {
+	require.NoError(t, DeleteTerraformBackendStateE(t, options, backendType))
+}
+
+// DeleteTerraformBackendStateE deletes the state that Terraform stored in the given backend with the BackendConfig of
+// the given options, such as the unique state set by terraform.IsolateBackendState, e.g. at the end of a test after
+// destroying the resources. The bucket (and the region for the s3 backend) must be set in the BackendConfig. The state
+// of options.Workspace is deleted along with the state of the default workspace.
+func DeleteTerraformBackendStateE(t testing.TestingT, options *terraform.Options, backendType string) error {
+	setting, err := terraform.GetBackendStateSettingE(backendType)
+	if err != nil {
+		return err
+	}
+	location, err := getBackendConfigString(options, setting)
+	if err != nil {
+		return err
+	}
+
+	switch backendType {
+	case terraform.BackendS3:
+		return deleteS3BackendStateE(t, options, location)
+	case terraform.BackendGCS:
+		return deleteGcsBackendStateE(t, options, location)
+	default:
+		for _, statePath := range []string{location, location + ".backup"} {
+			if err := os.Remove(statePath); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// deleteS3BackendStateE deletes the state with the given key, and the state of the workspace of the options, from the
+// bucket of the s3 backend.
+func deleteS3BackendStateE(t testing.TestingT, options *terraform.Options, key string) error {
+	bucket, err := getBackendConfigString(options, "bucket")
+	if err != nil {
+		return err
+	}
+	region, err := getBackendConfigString(options, "region")
+	if err != nil {
+		return err
+	}
+
+	keys := []string{key}
+	if options.Workspace != "" && options.Workspace != "default" {
+		workspaceKeyPrefix := "env:"
+		if prefix, hasPrefix := options.BackendConfig["workspace_key_prefix"]; hasPrefix {
+			workspaceKeyPrefix = fmt.Sprintf("%v", prefix)
+		}
+		keys = append(keys, path.Join(workspaceKeyPrefix, options.Workspace, key))
+	}
+
+	for _, key := range keys {
+		if err := aws.DeleteS3ObjectE(t, region, bucket, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteGcsBackendStateE deletes the state of the default workspace, and of the workspace of the options, under the
+// given prefix of the bucket of the gcs backend.
+func deleteGcsBackendStateE(t testing.TestingT, options *terraform.Options, prefix string) error {
+	bucket, err := getBackendConfigString(options, "bucket")
+	if err != nil {
+		return err
+	}
+
+	workspaces := []string{"default"}
+	if options.Workspace != "" && options.Workspace != "default" {
+		workspaces = append(workspaces, options.Workspace)
+	}
+
+	for _, workspace := range workspaces {
+		if err := gcp.DeleteBucketObjectE(t, bucket, path.Join(prefix, workspace+".tfstate")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getBackendConfigString returns the given setting of the BackendConfig of the options, or an error if it's not set.
+func getBackendConfigString(options *terraform.Options, setting string) (string, error) {
+	value, exists := options.BackendConfig[setting]
+	if !exists || value == nil || fmt.Sprintf("%v", value) == "" {
+		return "", fmt.Errorf("the %s setting must be set in the BackendConfig to delete the state", setting)
+	}
+	return fmt.Sprintf("%v", value), nil
+}
@@ -0,0 +1,39 @@
+package test_structure
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsolateAndDeleteLocalTerraformBackendState(t *testing.T) {
+	t.Parallel()
+
+	options := &terraform.Options{}
+	statePath := terraform.IsolateBackendState(t, options, terraform.BackendLocal)
+	assert.Equal(t, map[string]interface{}{"path": statePath}, options.BackendConfig)
+	assert.True(t, strings.Contains(statePath, "TestIsolateAndDeleteLocalTerraformBackendState-"))
+	assert.NotEqual(t, statePath, terraform.IsolateBackendState(t, &terraform.Options{}, terraform.BackendLocal))
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(statePath), 0755))
+	require.NoError(t, os.WriteFile(statePath, []byte("{}"), 0644))
+	DeleteTerraformBackendState(t, options, terraform.BackendLocal)
+	assert.NoFileExists(t, statePath)
+
+	// deleting a state that doesn't exist is not an error
+	DeleteTerraformBackendState(t, options, terraform.BackendLocal)
+}
+
+func TestDeleteTerraformBackendStateERequiresBucket(t *testing.T) {
+	t.Parallel()
+
+	options := &terraform.Options{}
+	terraform.IsolateBackendState(t, options, terraform.BackendS3)
+	assert.Error(t, DeleteTerraformBackendStateE(t, options, terraform.BackendS3))
+	assert.Equal(t, terraform.UnsupportedBackendType("azurerm"), DeleteTerraformBackendStateE(t, options, "azurerm"))
+}
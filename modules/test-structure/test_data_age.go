@@ -0,0 +1,52 @@
+package test_structure
+
+import (
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// getMaxTestDataAge returns the max age of the store set with SetTestDataStore, which is 0 (test data never goes stale)
+// unless the store is a DataStore with a MaxAge.
+func getMaxTestDataAge() time.Duration {
+	if store, ok := getTestDataStore().(interface{ getMaxAge() time.Duration }); ok {
+		return store.getMaxAge()
+	}
+	return 0
+}
+
+// GetTestDataAge returns how long ago the test data at the given path was saved. This will fail the test if there is
+// no test data at the path.
+func GetTestDataAge(t testing.TestingT, path string) time.Duration {
+	age, err := GetTestDataAgeE(t, path)
+	require.NoError(t, err)
+	return age
+}
+
+// GetTestDataAgeE returns how long ago the test data at the given path was saved.
+func GetTestDataAgeE(t testing.TestingT, path string) (time.Duration, error) {
+	modTime, err := getTestDataStore().ModTime(t, path)
+	if err != nil {
+		return 0, err
+	}
+	return time.Since(modTime), nil
+}
+
+// checkTestDataAge returns a StaleTestData error if the test data at the given path is older than the MaxAge of the
+// store set with SetTestDataStore.
+func checkTestDataAge(t testing.TestingT, path string) error {
+	maxAge := getMaxTestDataAge()
+	if maxAge <= 0 {
+		return nil
+	}
+
+	age, err := GetTestDataAgeE(t, path)
+	if err != nil {
+		return err
+	}
+	if age > maxAge {
+		return StaleTestData{Path: path, Age: age, MaxAge: maxAge}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package test_structure
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Not parallel, because it sets the store of the test data of all the tests
+func TestDataStoreMaxAge(t *testing.T) {
+	tmpFolder := t.TempDir()
+	Save(t, tmpFolder, "ami", 1, "ami-1234")
+	path := formatNamedTestDataPath(tmpFolder, "ami")
+
+	threeWeeksAgo := time.Now().Add(-21 * 24 * time.Hour)
+	require.NoError(t, os.Chtimes(path, threeWeeksAgo, threeWeeksAgo))
+	assert.InDelta(t, 21*24*time.Hour, GetTestDataAge(t, path), float64(time.Minute))
+
+	// Test data never goes stale by default
+	assert.True(t, IsTestDataPresent(t, path))
+
+	previous := SetTestDataStore(DataStore{MaxAge: 7 * 24 * time.Hour})
+	defer SetTestDataStore(previous)
+
+	assert.False(t, IsTestDataPresent(t, path))
+	_, err := LoadE[string](t, tmpFolder, "ami", 1)
+	var stale StaleTestData
+	require.ErrorAs(t, err, &stale)
+	assert.Equal(t, path, stale.Path)
+	assert.Contains(t, err.Error(), "Re-run the stage that saves it")
+
+	Save(t, tmpFolder, "ami", 1, "ami-5678")
+	assert.Equal(t, "ami-5678", Load[string](t, tmpFolder, "ami", 1))
+}
@@ -0,0 +1,113 @@
+package test_structure
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// TestDataCodec serializes and unserializes the test data saved with SaveTestData and loaded with LoadTestData (and all the
+// functions built on them, such as SaveTerraformOptions and LoadAmiId). Set it as the Codec of a DataStore to save the test
+// data in another format than JSON, or implement this interface for a format that isn't supported out of the box.
+type TestDataCodec interface {
+	// Marshal serializes the given value.
+	Marshal(value interface{}) ([]byte, error)
+	// Unmarshal unserializes the given data into the given value, which should be a pointer.
+	Unmarshal(data []byte, value interface{}) error
+	// FileExtension returns the extension, including the leading dot, of the files in which the test data is saved.
+	FileExtension() string
+}
+
+// JSONTestDataCodec is a TestDataCodec that saves the test data as JSON. This is the default.
+type JSONTestDataCodec struct{}
+
+// Marshal serializes the given value as JSON.
+func (codec JSONTestDataCodec) Marshal(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Unmarshal unserializes the given JSON into the given value.
+func (codec JSONTestDataCodec) Unmarshal(data []byte, value interface{}) error {
+	return json.Unmarshal(data, value)
+}
+
+// FileExtension returns .json.
+func (codec JSONTestDataCodec) FileExtension() string {
+	return ".json"
+}
+
+// YAMLTestDataCodec is a TestDataCodec that saves the test data as YAML, so that it can easily be edited by hand while
+// debugging a test. The values are converted to and from YAML through JSON, so the json struct tags of the values (e.g.
+// of terraform.Options) are honored, just like with JSONTestDataCodec, and JSON test data can be loaded too.
+type YAMLTestDataCodec struct{}
+
+// Marshal serializes the given value as YAML.
+func (codec YAMLTestDataCodec) Marshal(value interface{}) ([]byte, error) {
+	return yaml.Marshal(value)
+}
+
+// Unmarshal unserializes the given YAML into the given value.
+func (codec YAMLTestDataCodec) Unmarshal(data []byte, value interface{}) error {
+	return yaml.Unmarshal(data, value)
+}
+
+// FileExtension returns .yaml.
+func (codec YAMLTestDataCodec) FileExtension() string {
+	return ".yaml"
+}
+
+// CBORTestDataCodec is a TestDataCodec that saves the test data as CBOR (RFC 8949), a binary format that is more compact
+// than JSON and that stores byte slices as is instead of base64 encoded, for large binary test data (e.g. archives or
+// images built by a test stage). Like with JSONTestDataCodec, the json struct tags of the values are honored, and maps are
+// loaded as map[string]interface{}.
+type CBORTestDataCodec struct{}
+
+// Marshal serializes the given value as CBOR.
+func (codec CBORTestDataCodec) Marshal(value interface{}) ([]byte, error) {
+	encMode, err := cbor.EncOptions{Sort: cbor.SortCoreDeterministic, Time: cbor.TimeRFC3339Nano}.EncMode()
+	if err != nil {
+		return nil, err
+	}
+	return encMode.Marshal(value)
+}
+
+// Unmarshal unserializes the given CBOR into the given value.
+func (codec CBORTestDataCodec) Unmarshal(data []byte, value interface{}) error {
+	decMode, err := cbor.DecOptions{DefaultMapType: reflect.TypeOf(map[string]interface{}(nil))}.DecMode()
+	if err != nil {
+		return err
+	}
+	return decMode.Unmarshal(data, value)
+}
+
+// FileExtension returns .cbor.
+func (codec CBORTestDataCodec) FileExtension() string {
+	return ".cbor"
+}
+
+// getTestDataCodec returns the codec of the store set with SetTestDataStore, which is JSONTestDataCodec unless the store
+// is a DataStore with another codec.
+func getTestDataCodec() TestDataCodec {
+	if store, ok := getTestDataStore().(interface{ getCodec() TestDataCodec }); ok {
+		return store.getCodec()
+	}
+	return JSONTestDataCodec{}
+}
+
+// getTestDataContentType returns the media type of the test data at the given path, from the extension of the codec that
+// saved it, for the stores that record it (e.g. GCSTestDataStore).
+func getTestDataContentType(path string) string {
+	switch {
+	case strings.HasSuffix(path, JSONTestDataCodec{}.FileExtension()):
+		return "application/json"
+	case strings.HasSuffix(path, YAMLTestDataCodec{}.FileExtension()):
+		return "application/yaml"
+	case strings.HasSuffix(path, CBORTestDataCodec{}.FileExtension()):
+		return "application/cbor"
+	default:
+		return "application/octet-stream"
+	}
+}
@@ -0,0 +1,112 @@
+package test_structure
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Not parallel, because it replaces the store of all the tests
+func TestDataStoreWithYAMLCodec(t *testing.T) {
+	previous := SetTestDataStore(DataStore{Codec: YAMLTestDataCodec{}})
+	defer SetTestDataStore(previous)
+
+	tmpFolder := t.TempDir()
+
+	expectedData := testData{Foo: "foo", Bar: true, Baz: map[string]interface{}{"abc": "def"}}
+	SaveNamed(t, tmpFolder, "test-data", expectedData)
+	assert.FileExists(t, FormatTestDataPath(tmpFolder, "test-data.yaml"))
+	assert.Equal(t, expectedData, LoadNamed[testData](t, tmpFolder, "test-data"))
+
+	SaveString(t, tmpFolder, "vpc-id", "vpc-1234")
+	assert.Equal(t, "vpc-1234", LoadString(t, tmpFolder, "vpc-id"))
+
+	Save(t, tmpFolder, "ids", 1, []string{"a", "b"})
+	assert.Equal(t, []string{"a", "b"}, Load[[]string](t, tmpFolder, "ids", 1))
+
+	assert.Equal(t, []string{"ids", "test-data", "vpc-id"}, ListTestData(t, tmpFolder))
+
+	SaveString(t, tmpFolder, "empty", "")
+	assert.False(t, IsTestDataPresent(t, formatNamedTestDataPath(tmpFolder, "empty")))
+}
+
+func TestYAMLTestDataCodecHonorsJSONTags(t *testing.T) {
+	t.Parallel()
+
+	type tagged struct {
+		VpcID string `json:"vpc_id"`
+	}
+
+	data, err := YAMLTestDataCodec{}.Marshal(tagged{VpcID: "vpc-1234"})
+	require.NoError(t, err)
+	assert.Equal(t, "vpc_id: vpc-1234\n", string(data))
+
+	// JSON is a subset of YAML, so test data saved as JSON can be loaded too
+	var value tagged
+	require.NoError(t, YAMLTestDataCodec{}.Unmarshal([]byte(`{"vpc_id": "vpc-5678"}`), &value))
+	assert.Equal(t, "vpc-5678", value.VpcID)
+}
+
+func TestJSONTestDataCodecIsTheDefault(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, filepath.Join("vpc", ".test-data", "vpc-id.json"), formatNamedTestDataPath("vpc", "vpc-id"))
+}
+
+// Not parallel, because it replaces the store of all the tests
+func TestDataStoreWithCBORCodec(t *testing.T) {
+	previous := SetTestDataStore(DataStore{Store: LocalTestDataStore{}, Codec: CBORTestDataCodec{}})
+	defer SetTestDataStore(previous)
+
+	tmpFolder := t.TempDir()
+
+	expectedOptions := &terraform.Options{
+		TerraformDir: "/abc/def/ghi",
+		Vars:         map[string]interface{}{"name": "test", "tags": map[string]interface{}{"env": "test"}},
+		EnvVars:      map[string]string{"AWS_REGION": "us-east-1"},
+	}
+	SaveTerraformOptions(t, tmpFolder, expectedOptions)
+	assert.FileExists(t, FormatTestDataPath(tmpFolder, "TerraformOptions.cbor"))
+	assert.Equal(t, expectedOptions, LoadTerraformOptions(t, tmpFolder))
+
+	artifact := []byte{0x00, 0xff, 0x10, 0x80}
+	Save(t, tmpFolder, "artifact", 1, artifact)
+	assert.Equal(t, artifact, Load[[]byte](t, tmpFolder, "artifact", 1))
+
+	assert.Equal(t, []string{"TerraformOptions", "artifact"}, ListTestData(t, tmpFolder))
+
+	SaveTestData(t, formatNamedTestDataPath(tmpFolder, "zero"), true, 0)
+	assert.False(t, IsTestDataPresent(t, formatNamedTestDataPath(tmpFolder, "zero")))
+}
+
+func TestCBORTestDataCodecStoresBytesAsIs(t *testing.T) {
+	t.Parallel()
+
+	artifact := make([]byte, 1024)
+	cborData, err := CBORTestDataCodec{}.Marshal(artifact)
+	require.NoError(t, err)
+	jsonData, err := JSONTestDataCodec{}.Marshal(artifact)
+	require.NoError(t, err)
+	assert.Less(t, len(cborData), len(jsonData))
+
+	type tagged struct {
+		VpcID string `json:"vpc_id"`
+	}
+	data, err := CBORTestDataCodec{}.Marshal(tagged{VpcID: "vpc-1234"})
+	require.NoError(t, err)
+	var value map[string]interface{}
+	require.NoError(t, CBORTestDataCodec{}.Unmarshal(data, &value))
+	assert.Equal(t, map[string]interface{}{"vpc_id": "vpc-1234"}, value)
+}
+
+func TestGetTestDataContentType(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "application/json", getTestDataContentType("vpc/.test-data/TerraformOptions.json"))
+	assert.Equal(t, "application/yaml", getTestDataContentType("vpc/.test-data/TerraformOptions.yaml"))
+	assert.Equal(t, "application/cbor", getTestDataContentType("vpc/.test-data/TerraformOptions.cbor"))
+	assert.Equal(t, "application/octet-stream", getTestDataContentType("vpc/.test-data/TerraformOptions"))
+}
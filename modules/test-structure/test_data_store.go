@@ -0,0 +1,248 @@
+package test_structure
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// TestDataStore stores the test data saved by SaveTestData (and all the functions built on it, such as
+// SaveTerraformOptions and SaveAmiId), so that it can be loaded by a later test stage. By default, test data is stored
+// in local files (LocalTestDataStore) as JSON. Use SetTestDataStore with a remote store (e.g. S3TestDataStore) to share
+// the test data between test stages that run on different machines, and with a DataStore to save it in another format.
+type TestDataStore interface {
+	// Read returns the test data stored at the given path. If there is no test data at the path, this returns an error
+	// for which errors.Is(err, os.ErrNotExist) is true.
+	Read(t testing.TestingT, path string) ([]byte, error)
+
+	// Write stores the given test data at the given path, overwriting any test data stored there before.
+	Write(t testing.TestingT, path string, data []byte) error
+
+	// Delete deletes the test data stored at the given path. Deleting test data that doesn't exist is not an error.
+	Delete(t testing.TestingT, path string) error
+
+	// DeleteFolder deletes all the test data stored under the given folder.
+	DeleteFolder(t testing.TestingT, path string) error
+
+	// List returns the paths of all the test data stored under the given folder, including in its subfolders. If there
+	// is no test data under the folder, this returns an empty list.
+	List(t testing.TestingT, path string) ([]string, error)
+
+	// ModTime returns when the test data at the given path was last written. If there is no test data at the path, this
+	// returns an error for which errors.Is(err, os.ErrNotExist) is true.
+	ModTime(t testing.TestingT, path string) (time.Time, error)
+}
+
+var (
+	testDataStore      TestDataStore = LocalTestDataStore{}
+	testDataStoreMutex sync.RWMutex
+)
+
+// SetTestDataStore sets the store in which the test data of all the tests is saved and loaded, and returns the store
+// that was used before, e.g. to restore it at the end of the test. This is typically called once, in TestMain.
+func SetTestDataStore(store TestDataStore) TestDataStore {
+	testDataStoreMutex.Lock()
+	defer testDataStoreMutex.Unlock()
+
+	previous := testDataStore
+	testDataStore = store
+	return previous
+}
+
+// getTestDataStore returns the store set with SetTestDataStore.
+func getTestDataStore() TestDataStore {
+	testDataStoreMutex.RLock()
+	defer testDataStoreMutex.RUnlock()
+	return testDataStore
+}
+
+// DataStore is a TestDataStore that saves the test data in the given store, serialized with the given codec. For example,
+// to save the test data of all the tests as YAML, so that it can be edited by hand while debugging a test:
+//
+//	test_structure.SetTestDataStore(test_structure.DataStore{Codec: test_structure.YAMLTestDataCodec{}})
+//
+// Note that the codec also determines the extension of the files in which the named test data (e.g. TerraformOptions or
+// the values saved with SaveString) is saved, so test data saved with one codec isn't found by the named Load functions
+// after switching to a codec with another extension.
+//
+// Set MaxAge so that loading test data (with LoadTestData, Load and all the functions built on them, such as LoadAmiId
+// and LoadTerraformOptions) that was saved longer ago fails with a StaleTestData error, and IsTestDataPresent returns
+// false for it. That way a stage that uses e.g. an AMI ID saved three weeks ago fails fast, asking to re-run the stage
+// that saved it, instead of failing later because the AMI has been deregistered.
+type DataStore struct {
+	Store  TestDataStore // The store in which the test data is saved. LocalTestDataStore if not set.
+	Codec  TestDataCodec // The codec with which the test data is serialized. JSONTestDataCodec if not set.
+	MaxAge time.Duration // How long ago loaded test data can have been saved. Test data never goes stale if not set.
+}
+
+// getStore returns the store of the DataStore, or LocalTestDataStore if it isn't set.
+func (store DataStore) getStore() TestDataStore {
+	if store.Store == nil {
+		return LocalTestDataStore{}
+	}
+	return store.Store
+}
+
+// getCodec returns the codec of the DataStore, or JSONTestDataCodec if it isn't set.
+func (store DataStore) getCodec() TestDataCodec {
+	if store.Codec == nil {
+		return JSONTestDataCodec{}
+	}
+	return store.Codec
+}
+
+// getMaxAge returns the max age of the test data of the DataStore, which is 0 (no max age) if it isn't set.
+func (store DataStore) getMaxAge() time.Duration {
+	return store.MaxAge
+}
+
+// Read returns the test data stored at the given path in the store of the DataStore.
+func (store DataStore) Read(t testing.TestingT, path string) ([]byte, error) {
+	return store.getStore().Read(t, path)
+}
+
+// Write stores the given test data at the given path in the store of the DataStore.
+func (store DataStore) Write(t testing.TestingT, path string, data []byte) error {
+	return store.getStore().Write(t, path, data)
+}
+
+// Delete deletes the test data stored at the given path in the store of the DataStore.
+func (store DataStore) Delete(t testing.TestingT, path string) error {
+	return store.getStore().Delete(t, path)
+}
+
+// DeleteFolder deletes all the test data stored under the given folder in the store of the DataStore.
+func (store DataStore) DeleteFolder(t testing.TestingT, path string) error {
+	return store.getStore().DeleteFolder(t, path)
+}
+
+// List returns the paths of all the test data stored under the given folder in the store of the DataStore.
+func (store DataStore) List(t testing.TestingT, path string) ([]string, error) {
+	return store.getStore().List(t, path)
+}
+
+// ModTime returns when the test data at the given path was last written in the store of the DataStore.
+func (store DataStore) ModTime(t testing.TestingT, path string) (time.Time, error) {
+	return store.getStore().ModTime(t, path)
+}
+
+// LocalTestDataStore stores test data in local files. This is the default TestDataStore.
+type LocalTestDataStore struct{}
+
+// Read returns the contents of the file at the given path.
+func (store LocalTestDataStore) Read(t testing.TestingT, path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+// Write writes the given test data to the file at the given path, creating its parent folders if necessary. The data is
+// written to a temp file that is then renamed to the path, so that tests running in parallel never read a partially
+// written file, and the file contains the data of one of the writes if they write to the same path at the same time.
+func (store LocalTestDataStore) Write(t testing.TestingT, path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return err
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+	_, writeErr := tmpFile.Write(data)
+	closeErr := tmpFile.Close()
+	if err := errors.Join(writeErr, closeErr, os.Chmod(tmpPath, 0644)); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// Delete deletes the file at the given path.
+func (store LocalTestDataStore) Delete(t testing.TestingT, path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// DeleteFolder deletes the folder at the given path and all its contents.
+func (store LocalTestDataStore) DeleteFolder(t testing.TestingT, path string) error {
+	return os.RemoveAll(path)
+}
+
+// ModTime returns the modification time of the file at the given path.
+func (store LocalTestDataStore) ModTime(t testing.TestingT, path string) (time.Time, error) {
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return fileInfo.ModTime(), nil
+}
+
+// List returns the paths of all the files under the given folder.
+func (store LocalTestDataStore) List(t testing.TestingT, path string) ([]string, error) {
+	paths := []string{}
+	err := filepath.WalkDir(path, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && !isLocalTestDataTempFile(entry.Name()) {
+			paths = append(paths, filePath)
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return []string{}, nil
+	}
+	return paths, err
+}
+
+// isLocalTestDataTempFile returns true if the file with the given name is a temp file that LocalTestDataStore is writing.
+func isLocalTestDataTempFile(name string) bool {
+	return strings.HasPrefix(name, ".") && strings.Contains(name, ".tmp-")
+}
+
+// getTestDataKey returns the key under the given prefix at which a remote TestDataStore stores the test data of the
+// given local path. Paths in the working directory are made relative to it, and any leading / or ../ is removed, so
+// that test stages running from the same repo on different machines (e.g. checked out in different folders) use the
+// same keys.
+func getTestDataKey(prefix string, localPath string) string {
+	if filepath.IsAbs(localPath) {
+		if workingDir, err := os.Getwd(); err == nil {
+			if relPath, err := filepath.Rel(workingDir, localPath); err == nil && !strings.HasPrefix(relPath, "..") {
+				localPath = relPath
+			}
+		}
+	}
+
+	key := filepath.ToSlash(filepath.Clean(strings.TrimPrefix(localPath, filepath.VolumeName(localPath))))
+	key = strings.TrimLeft(key, "/")
+	for strings.HasPrefix(key, "../") {
+		key = strings.TrimPrefix(key, "../")
+	}
+	if key == "." || key == ".." {
+		key = ""
+	}
+	return path.Join(prefix, key)
+}
+
+// getTestDataPaths returns the local paths under the given folder of the given keys, which a remote TestDataStore
+// listed under the given key of the folder.
+func getTestDataPaths(folder string, folderKey string, keys []string) []string {
+	paths := []string{}
+	for _, key := range keys {
+		relKey := strings.TrimPrefix(strings.TrimPrefix(key, folderKey), "/")
+		paths = append(paths, filepath.Join(folder, filepath.FromSlash(relKey)))
+	}
+	return paths
+}
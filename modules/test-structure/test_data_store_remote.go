@@ -0,0 +1,222 @@
+package test_structure
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/azure"
+	"github.com/gruntwork-io/terratest/modules/gcp"
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/testing"
+)
+
+// S3TestDataStore is a TestDataStore that stores test data as objects in an S3 bucket, under the given prefix. See
+// getTestDataKey for how the local paths of the test data are converted to keys.
+type S3TestDataStore struct {
+	Region string
+	Bucket string
+	Prefix string
+}
+
+// Read returns the contents of the object of the given path.
+func (store S3TestDataStore) Read(t testing.TestingT, path string) ([]byte, error) {
+	key := getTestDataKey(store.Prefix, path)
+	contents, err := aws.GetS3ObjectContentsE(t, store.Region, store.Bucket, key)
+	var noSuchKey *types.NoSuchKey
+	if errors.As(err, &noSuchKey) {
+		return nil, fmt.Errorf("test data s3://%s/%s: %w", store.Bucket, key, os.ErrNotExist)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(contents), nil
+}
+
+// Write writes the given test data to the object of the given path.
+func (store S3TestDataStore) Write(t testing.TestingT, path string, data []byte) error {
+	return aws.PutS3ObjectContentsE(t, store.Region, store.Bucket, getTestDataKey(store.Prefix, path), string(data))
+}
+
+// Delete deletes the object of the given path.
+func (store S3TestDataStore) Delete(t testing.TestingT, path string) error {
+	return aws.DeleteS3ObjectE(t, store.Region, store.Bucket, getTestDataKey(store.Prefix, path))
+}
+
+// DeleteFolder deletes all the objects under the given folder.
+func (store S3TestDataStore) DeleteFolder(t testing.TestingT, path string) error {
+	return aws.DeleteS3ObjectsWithPrefixE(t, store.Region, store.Bucket, getTestDataKey(store.Prefix, path)+"/")
+}
+
+// List returns the paths of all the objects under the given folder.
+func (store S3TestDataStore) List(t testing.TestingT, path string) ([]string, error) {
+	folderKey := getTestDataKey(store.Prefix, path)
+	keys, err := aws.ListS3ObjectKeysWithPrefixE(t, store.Region, store.Bucket, folderKey+"/")
+	if err != nil {
+		return nil, err
+	}
+	return getTestDataPaths(path, folderKey, keys), nil
+}
+
+// ModTime returns when the object of the given path was last modified.
+func (store S3TestDataStore) ModTime(t testing.TestingT, path string) (time.Time, error) {
+	key := getTestDataKey(store.Prefix, path)
+	lastModified, err := aws.GetS3ObjectLastModifiedE(t, store.Region, store.Bucket, key)
+	var notFound *types.NotFound
+	if errors.As(err, &notFound) {
+		return time.Time{}, fmt.Errorf("test data s3://%s/%s: %w", store.Bucket, key, os.ErrNotExist)
+	}
+	return lastModified, err
+}
+
+// GCSTestDataStore is a TestDataStore that stores test data as objects in a Google Cloud Storage bucket, under the
+// given prefix. See getTestDataKey for how the local paths of the test data are converted to object paths.
+type GCSTestDataStore struct {
+	Bucket string
+	Prefix string
+}
+
+// Read returns the contents of the object of the given path.
+func (store GCSTestDataStore) Read(t testing.TestingT, path string) ([]byte, error) {
+	objectPath := getTestDataKey(store.Prefix, path)
+	reader, err := gcp.ReadBucketObjectE(t, store.Bucket, objectPath)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, fmt.Errorf("test data gs://%s/%s: %w", store.Bucket, objectPath, os.ErrNotExist)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	return io.ReadAll(reader)
+}
+
+// Write writes the given test data to the object of the given path.
+func (store GCSTestDataStore) Write(t testing.TestingT, path string, data []byte) error {
+	_, err := gcp.WriteBucketObjectE(t, store.Bucket, getTestDataKey(store.Prefix, path), bytes.NewReader(data), getTestDataContentType(path))
+	return err
+}
+
+// Delete deletes the object of the given path.
+func (store GCSTestDataStore) Delete(t testing.TestingT, path string) error {
+	return gcp.DeleteBucketObjectE(t, store.Bucket, getTestDataKey(store.Prefix, path))
+}
+
+// DeleteFolder deletes all the objects under the given folder.
+func (store GCSTestDataStore) DeleteFolder(t testing.TestingT, path string) error {
+	return gcp.DeleteBucketObjectsWithPrefixE(t, store.Bucket, getTestDataKey(store.Prefix, path)+"/")
+}
+
+// List returns the paths of all the objects under the given folder.
+func (store GCSTestDataStore) List(t testing.TestingT, path string) ([]string, error) {
+	folderKey := getTestDataKey(store.Prefix, path)
+	objectPaths, err := gcp.ListBucketObjectsWithPrefixE(t, store.Bucket, folderKey+"/")
+	if err != nil {
+		return nil, err
+	}
+	return getTestDataPaths(path, folderKey, objectPaths), nil
+}
+
+// ModTime returns when the object of the given path was last updated.
+func (store GCSTestDataStore) ModTime(t testing.TestingT, path string) (time.Time, error) {
+	objectPath := getTestDataKey(store.Prefix, path)
+	updated, err := gcp.GetBucketObjectUpdatedTimeE(t, store.Bucket, objectPath)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return time.Time{}, fmt.Errorf("test data gs://%s/%s: %w", store.Bucket, objectPath, os.ErrNotExist)
+	}
+	return updated, err
+}
+
+// AzureBlobTestDataStore is a TestDataStore that stores test data as block blobs in an Azure Storage container, under
+// the given prefix. ContainerURL is the URL of the container (e.g. https://myaccount.blob.core.windows.net/test-data).
+// If SASToken is set, the requests are authorized with it, so it needs read, write, delete and list permissions on the
+// container; otherwise they are authorized with the default Azure credential. See getTestDataKey for how the local paths
+// of the test data are converted to blob names.
+type AzureBlobTestDataStore struct {
+	ContainerURL string
+	SASToken     string
+	Prefix       string
+}
+
+// Read returns the contents of the blob of the given path.
+func (store AzureBlobTestDataStore) Read(t testing.TestingT, path string) ([]byte, error) {
+	blobName := getTestDataKey(store.Prefix, path)
+	contents, err := azure.ReadStorageBlobE(store.getContainerURL(), blobName)
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return nil, fmt.Errorf("test data %s/%s: %w", store.ContainerURL, blobName, os.ErrNotExist)
+	}
+	return contents, err
+}
+
+// Write writes the given test data to the blob of the given path.
+func (store AzureBlobTestDataStore) Write(t testing.TestingT, path string, data []byte) error {
+	blobName := getTestDataKey(store.Prefix, path)
+	logger.Default.Logf(t, "Writing test data to blob %s in %s", blobName, store.ContainerURL)
+	return azure.WriteStorageBlobE(store.getContainerURL(), blobName, data, getTestDataContentType(path))
+}
+
+// Delete deletes the blob of the given path.
+func (store AzureBlobTestDataStore) Delete(t testing.TestingT, path string) error {
+	return store.deleteBlob(t, getTestDataKey(store.Prefix, path))
+}
+
+// ModTime returns when the blob of the given path was last modified.
+func (store AzureBlobTestDataStore) ModTime(t testing.TestingT, path string) (time.Time, error) {
+	blobName := getTestDataKey(store.Prefix, path)
+	lastModified, err := azure.GetStorageBlobLastModifiedE(store.getContainerURL(), blobName)
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return time.Time{}, fmt.Errorf("test data %s/%s: %w", store.ContainerURL, blobName, os.ErrNotExist)
+	}
+	return lastModified, err
+}
+
+// DeleteFolder deletes all the blobs under the given folder.
+func (store AzureBlobTestDataStore) DeleteFolder(t testing.TestingT, path string) error {
+	blobNames, err := azure.ListStorageBlobNamesWithPrefixE(store.getContainerURL(), getTestDataKey(store.Prefix, path)+"/")
+	if err != nil {
+		return err
+	}
+	for _, blobName := range blobNames {
+		if err := store.deleteBlob(t, blobName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List returns the paths of all the blobs under the given folder.
+func (store AzureBlobTestDataStore) List(t testing.TestingT, path string) ([]string, error) {
+	folderKey := getTestDataKey(store.Prefix, path)
+	blobNames, err := azure.ListStorageBlobNamesWithPrefixE(store.getContainerURL(), folderKey+"/")
+	if err != nil {
+		return nil, err
+	}
+	return getTestDataPaths(path, folderKey, blobNames), nil
+}
+
+// deleteBlob deletes the blob with the given name, which is not an error if it doesn't exist.
+func (store AzureBlobTestDataStore) deleteBlob(t testing.TestingT, blobName string) error {
+	logger.Default.Logf(t, "Deleting test data blob %s in %s", blobName, store.ContainerURL)
+	return azure.DeleteStorageBlobE(store.getContainerURL(), blobName)
+}
+
+// getContainerURL returns the URL of the container, including the SAS token if it's set.
+func (store AzureBlobTestDataStore) getContainerURL() string {
+	sasToken := strings.TrimPrefix(store.SASToken, "?")
+	if sasToken == "" {
+		return store.ContainerURL
+	}
+	if strings.Contains(store.ContainerURL, "?") {
+		return store.ContainerURL + "&" + sasToken
+	}
+	return store.ContainerURL + "?" + sasToken
+}
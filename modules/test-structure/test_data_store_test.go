@@ -0,0 +1,220 @@
+package test_structure
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gotesting "github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryTestDataStore is a TestDataStore that keeps the test data in memory, to test the functions built on the store.
+type memoryTestDataStore struct {
+	mutex sync.Mutex
+	data  map[string][]byte
+}
+
+func (store *memoryTestDataStore) Read(t gotesting.TestingT, path string) ([]byte, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	data, exists := store.data[path]
+	if !exists {
+		return nil, fmt.Errorf("test data %s: %w", path, os.ErrNotExist)
+	}
+	return data, nil
+}
+
+func (store *memoryTestDataStore) Write(t gotesting.TestingT, path string, data []byte) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.data[path] = data
+	return nil
+}
+
+func (store *memoryTestDataStore) Delete(t gotesting.TestingT, path string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	delete(store.data, path)
+	return nil
+}
+
+func (store *memoryTestDataStore) DeleteFolder(t gotesting.TestingT, path string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	for key := range store.data {
+		if strings.HasPrefix(key, path+string(filepath.Separator)) {
+			delete(store.data, key)
+		}
+	}
+	return nil
+}
+
+func (store *memoryTestDataStore) List(t gotesting.TestingT, path string) ([]string, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	paths := []string{}
+	for key := range store.data {
+		if strings.HasPrefix(key, path+string(filepath.Separator)) {
+			paths = append(paths, key)
+		}
+	}
+	return paths, nil
+}
+
+func (store *memoryTestDataStore) ModTime(t gotesting.TestingT, path string) (time.Time, error) {
+	if _, err := store.Read(t, path); err != nil {
+		return time.Time{}, err
+	}
+	return time.Now(), nil
+}
+
+// Not parallel, because it replaces the store of all the tests
+func TestSetTestDataStore(t *testing.T) {
+	store := &memoryTestDataStore{data: map[string][]byte{}}
+	previous := SetTestDataStore(store)
+	defer SetTestDataStore(previous)
+
+	testFolder := filepath.Join("stages", "vpc")
+	SaveString(t, testFolder, "vpc-id", "vpc-1234")
+	SaveAmiId(t, testFolder, "ami-1234")
+	assert.NoDirExists(t, "stages")
+
+	assert.Equal(t, "vpc-1234", LoadString(t, testFolder, "vpc-id"))
+	assert.Equal(t, "ami-1234", LoadAmiId(t, testFolder))
+	assert.True(t, IsTestDataPresent(t, formatNamedTestDataPath(testFolder, "vpc-id")))
+
+	CleanupTestData(t, formatNamedTestDataPath(testFolder, "vpc-id"))
+	assert.False(t, IsTestDataPresent(t, formatNamedTestDataPath(testFolder, "vpc-id")))
+
+	CleanupTestDataFolder(t, testFolder)
+	assert.Empty(t, store.data)
+}
+
+func TestGetTestDataKey(t *testing.T) {
+	t.Parallel()
+
+	workingDir, err := os.Getwd()
+	require.NoError(t, err)
+
+	assert.Equal(t, "ci/examples/vpc/.test-data/AmiId.json", getTestDataKey("ci", "../examples/vpc/.test-data/AmiId.json"))
+	assert.Equal(t, "ci/stages/.test-data/AmiId.json", getTestDataKey("ci", filepath.Join(workingDir, "stages", ".test-data", "AmiId.json")))
+	assert.Equal(t, "tmp/vpc/.test-data", getTestDataKey("", "/tmp/vpc/.test-data/"))
+}
+
+// startAzureBlobServer starts a server that implements the parts of the Azure Blob Storage REST API that
+// AzureBlobTestDataStore uses, for a container named test-data that requires the SAS token sig=test. It returns the
+// contents and the content types of the blobs.
+func startAzureBlobServer(t *testing.T) (*httptest.Server, map[string][]byte, map[string]string) {
+	var mutex sync.Mutex
+	blobs := map[string][]byte{}
+	contentTypes := map[string]string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if r.URL.Query().Get("sig") != "test" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path == "/test-data" && r.URL.Query().Get("comp") == "list" {
+			names := []string{}
+			for name := range blobs {
+				if strings.HasPrefix(name, r.URL.Query().Get("prefix")) {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+			fmt.Fprint(w, "<EnumerationResults><Blobs>")
+			for _, name := range names {
+				fmt.Fprint(w, "<Blob><Name>")
+				xml.EscapeText(w, []byte(name))
+				fmt.Fprint(w, "</Name></Blob>")
+			}
+			fmt.Fprint(w, "</Blobs><NextMarker/></EnumerationResults>")
+			return
+		}
+
+		name := strings.TrimPrefix(r.URL.Path, "/test-data/")
+		if r.Method == http.MethodPut {
+			if r.Header.Get("x-ms-blob-type") != "BlockBlob" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			blobs[name] = body
+			contentTypes[name] = r.Header.Get("x-ms-blob-content-type")
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+
+		body, exists := blobs[name]
+		if !exists {
+			w.Header().Set("x-ms-error-code", "BlobNotFound")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			w.Write(body)
+		case http.MethodHead:
+			w.Header().Set("Last-Modified", "Mon, 05 Oct 2026 10:00:00 GMT")
+		case http.MethodDelete:
+			delete(blobs, name)
+			delete(contentTypes, name)
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, blobs, contentTypes
+}
+
+func TestAzureBlobTestDataStore(t *testing.T) {
+	t.Parallel()
+
+	server, blobs, contentTypes := startAzureBlobServer(t)
+	store := AzureBlobTestDataStore{ContainerURL: server.URL + "/test-data", SASToken: "?sig=test", Prefix: "run-1"}
+
+	path := filepath.Join("/tmp", "vpc", ".test-data", "VpcId.json")
+	_, err := store.Read(t, path)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, store.Write(t, path, []byte(`"vpc-1234"`)))
+	require.NoError(t, store.Write(t, filepath.Join("/tmp", "vpc", ".test-data", "AmiId.json"), []byte(`"ami-1234"`)))
+	assert.Contains(t, blobs, "run-1/tmp/vpc/.test-data/VpcId.json")
+	assert.Equal(t, "application/json", contentTypes["run-1/tmp/vpc/.test-data/VpcId.json"])
+
+	data, err := store.Read(t, path)
+	require.NoError(t, err)
+	assert.Equal(t, `"vpc-1234"`, string(data))
+
+	modTime, err := store.ModTime(t, path)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 10, 5, 10, 0, 0, 0, time.UTC), modTime.UTC())
+
+	require.NoError(t, store.Delete(t, path))
+	require.NoError(t, store.Delete(t, path))
+	assert.Len(t, blobs, 1)
+
+	paths, err := store.List(t, filepath.Join("/tmp", "vpc", ".test-data"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("/tmp", "vpc", ".test-data", "AmiId.json")}, paths)
+
+	require.NoError(t, store.DeleteFolder(t, filepath.Join("/tmp", "vpc", ".test-data")))
+	assert.Empty(t, blobs)
+
+	_, err = AzureBlobTestDataStore{ContainerURL: server.URL + "/test-data", SASToken: "sig=wrong"}.Read(t, path)
+	assert.ErrorContains(t, err, "403")
+	assert.NotErrorIs(t, err, os.ErrNotExist)
+}
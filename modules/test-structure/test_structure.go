@@ -1,10 +1,15 @@
 package test_structure
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gruntwork-io/terratest/modules/git"
 
@@ -15,6 +20,7 @@ import (
 	"github.com/gruntwork-io/terratest/modules/opa"
 	"github.com/gruntwork-io/terratest/modules/terraform"
 	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/hashicorp/go-multierror"
 	"github.com/stretchr/testify/require"
 )
 
@@ -22,17 +28,139 @@ import (
 const SKIP_STAGE_ENV_VAR_PREFIX = "SKIP_"
 
 // RunTestStage executes the given test stage (e.g., setup, teardown, validation) if an environment variable of the name
-// `SKIP_<stageName>` (e.g., SKIP_teardown) is not set.
+// `SKIP_<stageName>` (e.g., SKIP_teardown) is not set and the stage config of the test (see StageConfig) doesn't skip
+// it. The start time, duration, and whether the stage was skipped or failed are recorded for the stage report of the
+// test (see WriteStageReport).
 func RunTestStage(t testing.TestingT, stageName string, stage func()) {
-	envVarName := fmt.Sprintf("%s%s", SKIP_STAGE_ENV_VAR_PREFIX, stageName)
-	if os.Getenv(envVarName) == "" {
+	skipReason, err := getStageSkipReason(t, stageName)
+	require.NoError(t, err)
+
+	if skipReason == "" {
+		envVarName := fmt.Sprintf("%s%s", SKIP_STAGE_ENV_VAR_PREFIX, stageName)
 		logger.Default.Logf(t, "The '%s' environment variable is not set, so executing stage '%s'.", envVarName, stageName)
-		stage()
+		runAndRecordTestStage(t, stageName, stage)
 	} else {
-		logger.Default.Logf(t, "The '%s' environment variable is set, so skipping stage '%s'.", envVarName, stageName)
+		logger.Default.Logf(t, "%s, so skipping stage '%s'.", skipReason, stageName)
+		recordStageResult(t, StageResult{Name: stageName, Start: time.Now(), Skipped: true})
+	}
+}
+
+// STAGE_CONTEXT_GRACE_PERIOD is how long before the test times out RunTestStageWithContext cancels the context of the
+// stage, so that the commands of the stage have the time to stop gracefully (see shell.CancelWaitDelay) before the test
+// binary panics. It is clamped to half of the time left before the deadline of the test when the stage starts.
+const STAGE_CONTEXT_GRACE_PERIOD = time.Minute
+
+// RunTestStageWithContext executes the given test stage like RunTestStage, passing it a context, derived from the given
+// one, that is cancelled when the test is about to time out (STAGE_CONTEXT_GRACE_PERIOD before its deadline) or when
+// the test binary receives SIGINT (e.g. Ctrl-C) or SIGTERM. Pass the context to the helpers that accept one (e.g.
+// terraform.Options.Context) so that they stop the commands they run instead of orphaning them:
+//
+//	test_structure.RunTestStageWithContext(ctx, t, "deploy", func(ctx context.Context) {
+//		terraformOptions.Context = ctx
+//		terraform.InitAndApply(t, terraformOptions)
+//	})
+func RunTestStageWithContext(ctx context.Context, t testing.TestingT, stageName string, stage func(ctx context.Context)) {
+	RunTestStage(t, stageName, func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(signals)
+
+		stageCtx, cancel := newStageContext(ctx, t, signals)
+		defer cancel()
+		stage(stageCtx)
+	})
+}
+
+// newStageContext returns a context derived from the given one that is cancelled before the deadline of the given test,
+// if it has one (see getStageContextGracePeriod), or when a signal is received on the given channel.
+func newStageContext(ctx context.Context, t testing.TestingT, signals <-chan os.Signal) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	cancelDeadline := context.CancelFunc(func() {})
+	if testWithDeadline, ok := t.(interface{ Deadline() (time.Time, bool) }); ok {
+		if deadline, hasDeadline := testWithDeadline.Deadline(); hasDeadline {
+			gracePeriod := getStageContextGracePeriod(time.Until(deadline))
+			ctx, cancelDeadline = context.WithDeadline(ctx, deadline.Add(-gracePeriod))
+		}
+	}
+
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		cancelDeadline()
+		cancel()
 	}
 }
 
+// getStageContextGracePeriod returns how long before the deadline of the test the context of a stage is cancelled,
+// given the time left before the deadline: STAGE_CONTEXT_GRACE_PERIOD, but at most half of the time left, so that a
+// test with a short timeout (e.g. -timeout 1m) doesn't get a context that is already cancelled.
+func getStageContextGracePeriod(timeLeft time.Duration) time.Duration {
+	if timeLeft/2 < STAGE_CONTEXT_GRACE_PERIOD {
+		return timeLeft / 2
+	}
+	return STAGE_CONTEXT_GRACE_PERIOD
+}
+
+// RunTestStagesConcurrently executes the given test stages, keyed by stage name, each in its own goroutine and waits for
+// all of them to finish. As with RunTestStage, a stage is skipped if the `SKIP_<stageName>` environment variable is set.
+// This will fail the test if any of the stages panics or fails the test, after all the other stages have finished.
+//
+// Since the stages run at the same time, they must be safe to run concurrently: they must not share state (e.g. the same
+// terraform.Options or working directory) without synchronization. Note that a stage that calls t.FailNow (e.g. through
+// require or a non-E Terratest function) only stops its own goroutine.
+func RunTestStagesConcurrently(t testing.TestingT, stages map[string]func()) {
+	require.NoError(t, RunTestStagesConcurrentlyE(t, stages))
+}
+
+// RunTestStagesConcurrentlyE executes the given test stages, keyed by stage name, each in its own goroutine and waits
+// for all of them to finish. As with RunTestStage, a stage is skipped if the `SKIP_<stageName>` environment variable is
+// set. Returns an error listing every stage that panicked or failed the test.
+//
+// Since the stages run at the same time, they must be safe to run concurrently: they must not share state (e.g. the same
+// terraform.Options or working directory) without synchronization. Note that a stage that calls t.FailNow (e.g. through
+// require or a non-E Terratest function) only stops its own goroutine.
+func RunTestStagesConcurrentlyE(t testing.TestingT, stages map[string]func()) error {
+	var waitGroup sync.WaitGroup
+	var mutex sync.Mutex
+	var errorsOccurred = new(multierror.Error)
+
+	for stageName, stage := range stages {
+		waitGroup.Add(1)
+		go func(stageName string, stage func()) {
+			defer waitGroup.Done()
+
+			completed := false
+			// A stage that fails the test with t.FailNow exits its goroutine through runtime.Goexit, which still runs the
+			// deferred calls, so both panics and failures are recorded here.
+			defer func() {
+				var err error
+				if recovered := recover(); recovered != nil {
+					err = fmt.Errorf("stage '%s' panicked: %v", stageName, recovered)
+				} else if !completed {
+					err = fmt.Errorf("stage '%s' failed", stageName)
+				}
+				if err != nil {
+					mutex.Lock()
+					errorsOccurred = multierror.Append(errorsOccurred, err)
+					mutex.Unlock()
+				}
+			}()
+
+			RunTestStage(t, stageName, stage)
+			completed = true
+		}(stageName, stage)
+	}
+
+	waitGroup.Wait()
+	return errorsOccurred.ErrorOrNil()
+}
+
 // SkipStageEnvVarSet returns true if an environment variable is set instructing Terratest to skip a test stage. This can be an easy way
 // to tell if the tests are running in a local dev environment vs a CI server.
 func SkipStageEnvVarSet() bool {
@@ -108,9 +236,35 @@ func CopyTerraformFolderToTemp(t testing.TestingT, rootFolder string, terraformM
 // there are no other concurrent tests running and we want to be able to cache test data between test stages, so in that
 // case, we do NOT copy anything to a temp folder, and return the path to the original terraform module folder instead.
 func CopyTerraformFolderToDest(t testing.TestingT, rootFolder string, terraformModuleFolder string, destRootFolder string) string {
+	tmpTestFolder, _ := CopyTerraformFolderToDestWithOptions(t, rootFolder, terraformModuleFolder, destRootFolder, files.CopyFolderOptions{})
+	return tmpTestFolder
+}
+
+// CopyTerraformFolderToTempWithOptions works like CopyTerraformFolderToTemp, but only copies the files and folders of
+// the given root folder selected by the given options, and also returns a function that deletes the temp folder. For
+// example, in a large monorepo:
+//
+//	tempTestFolder, cleanup := test_structure.CopyTerraformFolderToTempWithOptions(t, "..", "examples/vpc", files.CopyFolderOptions{
+//		Include:  []string{"examples/vpc/**", "modules/**"},
+//		SkipDirs: files.DefaultSkipDirs,
+//	})
+//	defer cleanup()
+//
+// As with CopyTerraformFolderToTemp, if any of the SKIP_<stage> environment variables is set, the path to the original
+// terraform module folder is returned, and the cleanup function does nothing.
+func CopyTerraformFolderToTempWithOptions(t testing.TestingT, rootFolder string, terraformModuleFolder string, options files.CopyFolderOptions) (string, func()) {
+	return CopyTerraformFolderToDestWithOptions(t, rootFolder, terraformModuleFolder, os.TempDir(), options)
+}
+
+// CopyTerraformFolderToDestWithOptions works like CopyTerraformFolderToDest, but only copies the files and folders of
+// the given root folder selected by the given options, and also returns a function that deletes the copy. As with
+// CopyTerraformFolderToDest, if any of the SKIP_<stage> environment variables is set, the path to the original terraform
+// module folder is returned, and the cleanup function does nothing.
+func CopyTerraformFolderToDestWithOptions(t testing.TestingT, rootFolder string, terraformModuleFolder string, destRootFolder string, options files.CopyFolderOptions) (string, func()) {
+	noCleanup := func() {}
 	if SkipStageEnvVarSet() {
 		logger.Default.Logf(t, "A SKIP_XXX environment variable is set. Using original examples folder rather than a temp folder so we can cache data between stages for faster local testing.")
-		return filepath.Join(rootFolder, terraformModuleFolder)
+		return filepath.Join(rootFolder, terraformModuleFolder), noCleanup
 	}
 
 	fullTerraformModuleFolder := filepath.Join(rootFolder, terraformModuleFolder)
@@ -121,7 +275,7 @@ func CopyTerraformFolderToDest(t testing.TestingT, rootFolder string, terraformM
 		t.Fatal(files.DirNotFoundError{Directory: fullTerraformModuleFolder})
 	}
 
-	tmpRootFolder, err := files.CopyTerraformFolderToDest(rootFolder, destRootFolder, cleanName(t.Name()))
+	tmpRootFolder, err := files.CopyTerraformFolderToDestWithOptions(rootFolder, destRootFolder, cleanName(t.Name()), options)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -131,7 +285,14 @@ func CopyTerraformFolderToDest(t testing.TestingT, rootFolder string, terraformM
 	// Log temp folder so we can see it
 	logger.Default.Logf(t, "Copied terraform folder %s to %s", fullTerraformModuleFolder, tmpTestFolder)
 
-	return tmpTestFolder
+	// The copy of the root folder is in a randomly-named folder of its own, which is the one to delete
+	cleanup := func() {
+		logger.Default.Logf(t, "Deleting the copy of terraform folder %s in %s", fullTerraformModuleFolder, filepath.Dir(tmpRootFolder))
+		if err := os.RemoveAll(filepath.Dir(tmpRootFolder)); err != nil {
+			logger.Default.Logf(t, "Failed to delete %s: %v", filepath.Dir(tmpRootFolder), err)
+		}
+	}
+	return tmpTestFolder, cleanup
 }
 
 func cleanName(originalName string) string {
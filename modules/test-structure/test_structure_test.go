@@ -1,11 +1,15 @@
 package test_structure
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gruntwork-io/terratest/modules/collections"
+	"github.com/gruntwork-io/terratest/modules/files"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -22,6 +26,18 @@ func TestCopySubtestToTempFolder(t *testing.T) {
 	})
 }
 
+func TestCopyToTempFolderWithOptions(t *testing.T) {
+	tempFolder, cleanup := CopyTerraformFolderToTempWithOptions(t, "../../", "examples/terraform-hello-world-example", files.CopyFolderOptions{
+		Include: []string{"examples/terraform-hello-world-example/*.tf"},
+	})
+	assert.FileExists(t, filepath.Join(tempFolder, "main.tf"))
+	assert.NoFileExists(t, filepath.Join(tempFolder, "README.md"))
+	assert.NoDirExists(t, filepath.Join(tempFolder, "..", "..", "modules"))
+
+	cleanup()
+	assert.NoDirExists(t, tempFolder)
+}
+
 // TestValidateAllTerraformModulesSucceedsOnValidTerraform points at a simple text fixture Terraform module that is
 // known to be valid
 func TestValidateAllTerraformModulesSucceedsOnValidTerraform(t *testing.T) {
@@ -144,3 +160,108 @@ func TestValidateTerragruntModulesOnTerratestByInclusion(t *testing.T) {
 
 	ValidateAllTerraformModules(t, opts)
 }
+
+func TestRunTestStagesConcurrentlyRunsStagesInParallel(t *testing.T) {
+	t.Parallel()
+
+	// Each stage waits for the other one to start, so this only finishes if the stages run at the same time.
+	firstStarted := make(chan struct{})
+	secondStarted := make(chan struct{})
+
+	RunTestStagesConcurrently(t, map[string]func(){
+		"first": func() {
+			close(firstStarted)
+			<-secondStarted
+		},
+		"second": func() {
+			close(secondStarted)
+			<-firstStarted
+		},
+	})
+}
+
+func TestRunTestStagesConcurrentlyESkipsStages(t *testing.T) {
+	t.Setenv("SKIP_concurrently_skipped", "true")
+
+	ran := false
+	err := RunTestStagesConcurrentlyE(t, map[string]func(){
+		"concurrently_skipped": func() { ran = true },
+	})
+	require.NoError(t, err)
+	assert.False(t, ran)
+}
+
+func TestRunTestStagesConcurrentlyEReportsAllPanickedStages(t *testing.T) {
+	t.Parallel()
+
+	var mutex sync.Mutex
+	completedStages := []string{}
+
+	err := RunTestStagesConcurrentlyE(t, map[string]func(){
+		"first_panic":  func() { panic("first failure") },
+		"second_panic": func() { panic("second failure") },
+		"success": func() {
+			mutex.Lock()
+			defer mutex.Unlock()
+			completedStages = append(completedStages, "success")
+		},
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stage 'first_panic' panicked: first failure")
+	assert.Contains(t, err.Error(), "stage 'second_panic' panicked: second failure")
+	assert.NotContains(t, err.Error(), "success")
+	assert.Equal(t, []string{"success"}, completedStages)
+}
+
+func TestNewStageContextIsCancelledOnSignal(t *testing.T) {
+	t.Parallel()
+
+	signals := make(chan os.Signal, 1)
+	ctx, cancel := newStageContext(context.Background(), t, signals)
+	defer cancel()
+
+	signals <- os.Interrupt
+	select {
+	case <-ctx.Done():
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	case <-time.After(10 * time.Second):
+		t.Fatal("The context of the stage wasn't cancelled on SIGINT")
+	}
+}
+
+// testWithDeadline is a test whose deadline is set by the test, rather than by the -timeout flag.
+type testWithDeadline struct {
+	*testing.T
+	deadline time.Time
+}
+
+func (t testWithDeadline) Deadline() (time.Time, bool) {
+	return t.deadline, true
+}
+
+func TestRunTestStageWithContextCancelsContextBeforeTestDeadline(t *testing.T) {
+	t.Parallel()
+
+	test := testWithDeadline{T: t, deadline: time.Now().Add(200 * time.Millisecond)}
+	RunTestStageWithContext(context.Background(), test, "context_deadline", func(ctx context.Context) {
+		deadline, hasDeadline := ctx.Deadline()
+		assert.True(t, hasDeadline)
+		assert.True(t, deadline.Before(test.deadline))
+		assert.True(t, deadline.After(time.Now()))
+
+		select {
+		case <-ctx.Done():
+			assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+		case <-time.After(10 * time.Second):
+			t.Fatal("The context of the stage wasn't cancelled before the deadline of the test")
+		}
+	})
+}
+
+func TestGetStageContextGracePeriod(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, STAGE_CONTEXT_GRACE_PERIOD, getStageContextGracePeriod(time.Hour))
+	assert.Equal(t, 20*time.Second, getStageContextGracePeriod(40*time.Second))
+}
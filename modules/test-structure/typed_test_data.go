@@ -0,0 +1,110 @@
+package test_structure
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/gruntwork-io/terratest/modules/logger"
+	"github.com/gruntwork-io/terratest/modules/testing"
+	"github.com/stretchr/testify/require"
+)
+
+// versionedTestData is the format in which Save stores a value, along with the schema version and the name of its type,
+// so that Load can detect test data saved for a different struct shape.
+type versionedTestData[T any] struct {
+	SchemaVersion int    `json:"schema_version"`
+	Type          string `json:"type"`
+	Data          T      `json:"data"`
+}
+
+// versionedTestDataHeader is the part of versionedTestData that Load checks before unmarshaling the data.
+type versionedTestDataHeader struct {
+	SchemaVersion *int   `json:"schema_version"`
+	Type          string `json:"type"`
+}
+
+// Save serializes and saves the given value under the given name in the given folder, along with the given schema
+// version and the name of its type, overwriting any value saved before. Bump the schema version whenever the shape of
+// T changes, so that Load fails on test data saved by an older test binary instead of silently unmarshaling it into the
+// wrong struct shape.
+func Save[T any](t testing.TestingT, testFolder string, name string, schemaVersion int, value T) {
+	data := versionedTestData[T]{
+		SchemaVersion: schemaVersion,
+		Type:          getTypeName[T](),
+		Data:          value,
+	}
+	SaveTestData(t, formatNamedTestDataPath(testFolder, name), true, data)
+}
+
+// Load loads and unserializes the value saved by Save under the given name in the given folder. This will fail the
+// test if the value was saved with a different type or schema version.
+func Load[T any](t testing.TestingT, testFolder string, name string, schemaVersion int) T {
+	value, err := LoadE[T](t, testFolder, name, schemaVersion)
+	require.NoError(t, err)
+	return value
+}
+
+// LoadE loads and unserializes the value saved by Save under the given name in the given folder. Returns a
+// TestDataSchemaMismatch error if the value was saved with a different type or schema version, and a StaleTestData
+// error if it was saved longer ago than the MaxAge of the DataStore set with SetTestDataStore.
+func LoadE[T any](t testing.TestingT, testFolder string, name string, schemaVersion int) (T, error) {
+	var value T
+	path := formatNamedTestDataPath(testFolder, name)
+	logger.Default.Logf(t, "Loading test data from %s", path)
+
+	bytes, err := getTestDataStore().Read(t, path)
+	if err != nil {
+		return value, err
+	}
+	if err := checkTestDataAge(t, path); err != nil {
+		return value, err
+	}
+
+	var header versionedTestDataHeader
+	if err := getTestDataCodec().Unmarshal(bytes, &header); err != nil {
+		return value, fmt.Errorf("failed to parse value %s: %w", path, err)
+	}
+	expectedType := getTypeName[T]()
+	if header.SchemaVersion == nil || *header.SchemaVersion != schemaVersion || header.Type != expectedType {
+		mismatch := TestDataSchemaMismatch{
+			Path:            path,
+			ExpectedType:    expectedType,
+			ExpectedVersion: schemaVersion,
+			ActualType:      header.Type,
+		}
+		if header.SchemaVersion != nil {
+			mismatch.ActualVersion = *header.SchemaVersion
+		}
+		return value, mismatch
+	}
+
+	var data versionedTestData[T]
+	if err := getTestDataCodec().Unmarshal(bytes, &data); err != nil {
+		return value, fmt.Errorf("failed to parse value %s: %w", path, err)
+	}
+	return data.Data, nil
+}
+
+// SaveNamed serializes and saves the given value (e.g. a struct with the outputs of a test stage) under the given name in
+// the given folder, overwriting any value saved before. Unlike Save, the value is saved as is, without a schema
+// version, so it can also be loaded with LoadTestData.
+func SaveNamed[T any](t testing.TestingT, testFolder string, name string, value T) {
+	SaveTestData(t, formatNamedTestDataPath(testFolder, name), true, value)
+}
+
+// LoadNamed loads and unserializes the value saved by SaveNamed under the given name in the given folder.
+func LoadNamed[T any](t testing.TestingT, testFolder string, name string) T {
+	var value T
+	LoadTestData(t, formatNamedTestDataPath(testFolder, name), &value)
+	return value
+}
+
+// getTypeName returns the name of T, including the path of its package if it's a named type (e.g.
+// github.com/org/repo/test.VpcInfo), so that types with the same name in different packages are told apart.
+func getTypeName[T any]() string {
+	valueType := reflect.TypeOf((*T)(nil)).Elem()
+	if valueType.Name() != "" && valueType.PkgPath() != "" {
+		return valueType.PkgPath() + "." + valueType.Name()
+	}
+	return valueType.String()
+}
@@ -0,0 +1,72 @@
+package test_structure
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDataV2 struct {
+	Foo  string
+	Bars []string
+}
+
+func TestSaveAndLoadVersionedTestData(t *testing.T) {
+	t.Parallel()
+
+	tmpFolder := t.TempDir()
+
+	expectedData := testData{
+		Foo: "foo",
+		Bar: true,
+		Baz: map[string]interface{}{"abc": "def"},
+	}
+	Save(t, tmpFolder, "test-data", 1, expectedData)
+	assert.Equal(t, expectedData, Load[testData](t, tmpFolder, "test-data", 1))
+
+	Save(t, tmpFolder, "ids", 1, []string{"a", "b"})
+	assert.Equal(t, []string{"a", "b"}, Load[[]string](t, tmpFolder, "ids", 1))
+}
+
+func TestLoadVersionedTestDataFailsOnMismatch(t *testing.T) {
+	t.Parallel()
+
+	tmpFolder := t.TempDir()
+	Save(t, tmpFolder, "test-data", 1, testData{Foo: "foo"})
+
+	var mismatch TestDataSchemaMismatch
+
+	_, err := LoadE[testData](t, tmpFolder, "test-data", 2)
+	require.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, 1, mismatch.ActualVersion)
+	assert.Equal(t, 2, mismatch.ExpectedVersion)
+
+	_, err = LoadE[testDataV2](t, tmpFolder, "test-data", 1)
+	require.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, "github.com/gruntwork-io/terratest/modules/test-structure.testData", mismatch.ActualType)
+	assert.Equal(t, "github.com/gruntwork-io/terratest/modules/test-structure.testDataV2", mismatch.ExpectedType)
+
+	// Data saved with SaveTestData has no schema version
+	SaveTestData(t, FormatTestDataPath(tmpFolder, "unversioned.json"), true, testData{Foo: "foo"})
+	_, err = LoadE[testData](t, tmpFolder, "unversioned", 1)
+	assert.ErrorAs(t, err, &mismatch)
+
+	_, err = LoadE[testData](t, tmpFolder, "missing", 1)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestSaveAndLoadNamedTestData(t *testing.T) {
+	t.Parallel()
+
+	tmpFolder := t.TempDir()
+
+	expectedData := testData{Foo: "foo", Bar: true}
+	SaveNamed(t, tmpFolder, "test-data", expectedData)
+	assert.Equal(t, expectedData, LoadNamed[testData](t, tmpFolder, "test-data"))
+
+	var actualData testData
+	LoadTestData(t, formatNamedTestDataPath(tmpFolder, "test-data"), &actualData)
+	assert.Equal(t, expectedData, actualData)
+}
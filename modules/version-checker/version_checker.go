@@ -20,6 +20,7 @@ const (
 	Docker VersionCheckerBinary = iota
 	Terraform
 	Packer
+	OpenTofu
 )
 
 const (
@@ -128,6 +129,8 @@ func getBinary(params CheckVersionParams) (string, error) {
 		return "packer", nil
 	case Terraform:
 		return terraform.DefaultExecutable, nil
+	case OpenTofu:
+		return terraform.TofuDefaultPath, nil
 	default:
 		return "", fmt.Errorf("unsupported Binary for checking versions {%d}", params.Binary)
 	}
@@ -94,6 +94,13 @@ func TestExtractVersionFromShellCommandOutput(t *testing.T) {
 			containError:         false,
 			expectedErrorMessage: "",
 		},
+		{
+			name:                 "OpenTofu version output",
+			outputStr:            "OpenTofu v1.8.3\non linux_amd64",
+			expectedVersionStr:   "1.8.3",
+			containError:         false,
+			expectedErrorMessage: "",
+		},
 		{
 			name:                 "invalid output string",
 			outputStr:            "version is vabc",
@@ -121,6 +128,21 @@ func TestExtractVersionFromShellCommandOutput(t *testing.T) {
 	}
 }
 
+func TestGetBinary(t *testing.T) {
+	t.Parallel()
+
+	binary, err := getBinary(CheckVersionParams{Binary: OpenTofu})
+	require.NoError(t, err)
+	require.Equal(t, "tofu", binary)
+
+	binary, err = getBinary(CheckVersionParams{Binary: OpenTofu, BinaryPath: "/usr/local/bin/tofu"})
+	require.NoError(t, err)
+	require.Equal(t, "/usr/local/bin/tofu", binary)
+
+	_, err = getBinary(CheckVersionParams{Binary: VersionCheckerBinary(-1)})
+	require.Error(t, err)
+}
+
 func TestCheckVersionConstraint(t *testing.T) {
 	t.Parallel()
 